@@ -0,0 +1,173 @@
+import os
+import json
+import gzip
+import hashlib
+from typing import Dict, List, Any, Optional, Iterator
+
+try:
+    import zstandard
+except ImportError:
+    zstandard = None
+
+
+class CaptureStore:
+    """Content-addressed, compressed storage for captured exchanges.
+
+    Layout:
+        store.json           store metadata (format version, blob codec)
+        index.jsonl          one record per distinct exchange, keyed by endpoint
+        blobs/ab/abcdef...   compressed bodies named by their sha256 digest
+
+    Identical bodies are stored once, and identical exchanges collapse into
+    one index record with a count, so multi-day captures stay small. Bodies
+    are zstd-compressed when the zstandard package is installed and gzip
+    otherwise; the codec is recorded in store.json for readers.
+    """
+
+    VERSION = 1
+
+    def __init__(self, root: str, codec: Optional[str] = None):
+        self.root = root
+        self.records: List[Dict[str, Any]] = []
+        self._records_by_id: Dict[str, Dict[str, Any]] = {}
+        self.blob_writes = 0
+        self.blob_dedup_hits = 0
+
+        meta_path = os.path.join(root, 'store.json')
+        if os.path.exists(meta_path):
+            with open(meta_path, 'r') as f:
+                self.codec = json.load(f)['codec']
+            self._load_index()
+        else:
+            self.codec = codec or ('zstd' if zstandard else 'gzip')
+            os.makedirs(os.path.join(root, 'blobs'), exist_ok=True)
+            with open(meta_path, 'w') as f:
+                json.dump({'version': self.VERSION, 'codec': self.codec}, f, indent=2)
+
+    def add(self, capture: Dict[str, Any], endpoint: str) -> Dict[str, Any]:
+        request = capture.get('request', {})
+        response = capture.get('response', {})
+
+        record = {
+            'endpoint': endpoint,
+            'method': request.get('method', 'GET'),
+            'url': request.get('url', ''),
+            'status': response.get('status', 200),
+            'started_at': capture.get('started_at'),
+            'duration_ms': capture.get('duration_ms'),
+            'request': self._store_message(request),
+            'response': self._store_message(response)
+        }
+        if capture.get('websocket_messages'):
+            record['websocket_messages'] = self._put_blob(json.dumps(capture['websocket_messages']).encode('utf-8'))
+
+        record_id = self._digest(json.dumps(
+            {k: v for k, v in record.items() if k not in ('started_at', 'duration_ms')}, sort_keys=True).encode('utf-8'))[:24]
+        existing = self._records_by_id.get(record_id)
+        if existing:
+            existing['count'] += 1
+            return existing
+
+        record['id'] = record_id
+        record['count'] = 1
+        self.records.append(record)
+        self._records_by_id[record_id] = record
+        return record
+
+    def samples(self, endpoint: Optional[str] = None, status: Optional[int] = None) -> Iterator[Dict[str, Any]]:
+        for record in self.records:
+            if endpoint and record['endpoint'] != endpoint:
+                continue
+            if status is not None and record['status'] != status:
+                continue
+            yield self.load_capture(record)
+
+    def endpoints(self) -> List[str]:
+        return sorted({record['endpoint'] for record in self.records})
+
+    def load_capture(self, record: Dict[str, Any]) -> Dict[str, Any]:
+        capture = {
+            'request': self._load_message(record['request'], url=record['url'], method=record['method']),
+            'response': self._load_message(record['response'], status=record['status'])
+        }
+        if record.get('started_at'):
+            capture['started_at'] = record['started_at']
+        if record.get('duration_ms'):
+            capture['duration_ms'] = record['duration_ms']
+        if record.get('websocket_messages'):
+            capture['websocket_messages'] = json.loads(self.get_blob(record['websocket_messages']))
+        return capture
+
+    def flush(self):
+        index_path = os.path.join(self.root, 'index.jsonl')
+        tmp_path = index_path + '.tmp'
+        with open(tmp_path, 'w') as f:
+            for record in self.records:
+                f.write(json.dumps(record, sort_keys=True) + '\n')
+        os.replace(tmp_path, index_path)
+
+    def get_blob(self, digest: str) -> bytes:
+        with open(self._blob_path(digest), 'rb') as f:
+            data = f.read()
+        if self.codec == 'zstd':
+            if zstandard is None:
+                raise RuntimeError('capture store uses zstd; install the zstandard package to read it')
+            return zstandard.ZstdDecompressor().decompress(data)
+        return gzip.decompress(data)
+
+    def _store_message(self, message: Dict[str, Any]) -> Dict[str, Any]:
+        stored = {}
+        if message.get('headers'):
+            stored['headers'] = message['headers']
+        if message.get('encoding'):
+            stored['encoding'] = message['encoding']
+        body = message.get('body')
+        if body is not None:
+            if isinstance(body, (dict, list)):
+                body = json.dumps(body, separators=(',', ':'))
+            stored['body'] = self._put_blob(body.encode('utf-8') if isinstance(body, str) else body)
+        return stored
+
+    def _load_message(self, stored: Dict[str, Any], **fields) -> Dict[str, Any]:
+        message = dict(fields)
+        if stored.get('headers'):
+            message['headers'] = stored['headers']
+        if stored.get('encoding'):
+            message['encoding'] = stored['encoding']
+        if stored.get('body'):
+            message['body'] = self.get_blob(stored['body']).decode('utf-8')
+        return message
+
+    def _put_blob(self, data: bytes) -> str:
+        digest = self._digest(data)
+        path = self._blob_path(digest)
+        if os.path.exists(path):
+            self.blob_dedup_hits += 1
+            return digest
+
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        if self.codec == 'zstd':
+            compressed = zstandard.ZstdCompressor(level=10).compress(data)
+        else:
+            compressed = gzip.compress(data, mtime=0)
+        with open(path, 'wb') as f:
+            f.write(compressed)
+        self.blob_writes += 1
+        return digest
+
+    def _blob_path(self, digest: str) -> str:
+        return os.path.join(self.root, 'blobs', digest[:2], digest)
+
+    def _digest(self, data: bytes) -> str:
+        return hashlib.sha256(data).hexdigest()
+
+    def _load_index(self):
+        index_path = os.path.join(self.root, 'index.jsonl')
+        if not os.path.exists(index_path):
+            return
+        with open(index_path, 'r') as f:
+            for line in f:
+                if line.strip():
+                    record = json.loads(line)
+                    self.records.append(record)
+                    self._records_by_id[record['id']] = record
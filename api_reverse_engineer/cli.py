@@ -5,14 +5,68 @@ Automatically generates typed SDK clients from network traffic
 """
 
 import argparse
+import filecmp
 import json
+import shutil
 import sys
 import os
+import tempfile
 from typing import Dict, Any
 from traffic_parser import TrafficParser
 from python_generator import PythonSDKGenerator
 from typescript_generator import TypeScriptSDKGenerator
 from go_generator import GoSDKGenerator
+from capture_store import CaptureStore
+from schema_registry import SchemaSnapshot, open_registry
+from inference_config import InferenceConfig
+
+
+def generate_sdks(name, base_url, endpoints, languages, output_dirs, grpc_parser=None,
+                  fixture_synthesizer=None, go_package=None, go_module_dir=None, go_module_name=None,
+                  go_results=False, go_struct_depth=3, webhook_parser=None, environments=None,
+                  websocket_parser=None):
+    generated_files = []
+    
+    if 'python' in languages:
+        print(f"🐍 Generating Python SDK...", end=' ')
+        generator = PythonSDKGenerator(name, base_url, endpoints)
+        output_file = generator.generate(output_dirs['python'])
+        generated_files.append(output_file)
+        print(f"✅")
+    
+    if 'typescript' in languages:
+        print(f"📘 Generating TypeScript SDK...", end=' ')
+        generator = TypeScriptSDKGenerator(name, base_url, endpoints)
+        output_file = generator.generate(output_dirs['typescript'])
+        generated_files.append(output_file)
+        print(f"✅")
+    
+    if 'go' in languages:
+        print(f"🐹 Generating Go SDK...", end=' ')
+        generator = GoSDKGenerator(name, base_url, endpoints, grpc_parser=grpc_parser,
+                                   fixture_synthesizer=fixture_synthesizer, result_types=go_results,
+                                   struct_depth=go_struct_depth, webhook_parser=webhook_parser,
+                                   environments=environments, websocket_parser=websocket_parser,
+                                   locked_names=GoSDKGenerator.read_names_lock(output_dirs['go']))
+        output_file = generator.generate(output_dirs['go'], package_name=go_package,
+                                         module_dir=go_module_dir, module_name=go_module_name)
+        generated_files.append(output_file)
+        print(f"✅")
+    
+    return generated_files
+
+
+def changed_files(original_dir, regenerated_dir):
+    """The files under regenerated_dir that are missing from original_dir or
+    differ from their copy there, relative to the directories."""
+    changed = []
+    for root, _, files in os.walk(regenerated_dir):
+        for name in files:
+            path = os.path.relpath(os.path.join(root, name), regenerated_dir)
+            original = os.path.join(original_dir, path)
+            if not os.path.isfile(original) or not filecmp.cmp(original, os.path.join(root, name), shallow=False):
+                changed.append(path)
+    return sorted(changed)
 
 
 def main():
@@ -30,6 +84,9 @@ Examples:
   # Generate from JSON traffic data
   %(prog)s --json traffic.json --name "MyAPI" --base-url https://api.example.com
 
+  # Fail if regenerating would change the committed SDKs, e.g. in CI
+  %(prog)s --har api_traffic.har --name "MyAPI" --output ./sdks --check
+
   # Capture live traffic (requires mitmproxy)
   %(prog)s --capture --port 8080 --name "MyAPI" --duration 60
         """
@@ -47,6 +104,19 @@ Examples:
         help='Path to JSON file containing traffic data'
     )
     
+    parser.add_argument(
+        '--from-store',
+        type=str,
+        help='Path to a capture store directory written by --capture-store'
+    )
+    
+    parser.add_argument(
+        '--openapi',
+        type=str,
+        help='Regenerate from an OpenAPI spec, such as an edited copy of the openapi.yaml '
+             'written beside the Go SDK, instead of captured traffic'
+    )
+    
     parser.add_argument(
         '--capture',
         action='store_true',
@@ -95,6 +165,88 @@ Examples:
         help='Output directory for generated SDKs (default: generated_sdks)'
     )
     
+    parser.add_argument(
+        '--check',
+        action='store_true',
+        help='Regenerate into a scratch copy of the output directory and exit with status 1, '
+             'listing the files that would change, if it differs from the output directory'
+    )
+    
+    parser.add_argument(
+        '--capture-store',
+        type=str,
+        help='Add the normalized capture to a deduplicating, compressed capture store directory'
+    )
+    
+    parser.add_argument(
+        '--save-capture',
+        type=str,
+        help='Write the normalized capture (PII masked unless --retain-raw-pii) to a JSON file'
+    )
+    
+    parser.add_argument(
+        '--retain-raw-pii',
+        action='store_true',
+        help='Keep raw emails, phone numbers, tokens, and card numbers in the capture '
+             '(masked by default so capture artifacts are safe to share)'
+    )
+    
+    parser.add_argument(
+        '--keep-secrets',
+        action='store_true',
+        help='Do not replace Authorization headers, cookies, and API keys with secret placeholders'
+    )
+    
+    parser.add_argument(
+        '--secrets-file',
+        type=str,
+        help='Store the redacted credentials in an owner-only JSON file for local replay'
+    )
+    
+    parser.add_argument(
+        '--api-layout',
+        choices=['combined', 'per-api'],
+        default='combined',
+        help='How to lay out SDKs when traffic spans several hosts: one module with a '
+             'package per API, or a separate SDK tree per API (default: combined)'
+    )
+    
+    parser.add_argument(
+        '--config',
+        type=str,
+        help='JSON config file; its "inference" section tunes path-parameter detection, '
+             'enum and date detection, and envelope detection'
+    )
+    
+    parser.add_argument(
+        '--min-samples',
+        type=int,
+        help='Samples required before a field gets a concrete type; fields seen less '
+             'often are generated untyped (default: 1, overrides the config file)'
+    )
+    
+    parser.add_argument(
+        '--registry',
+        type=str,
+        help='Publish the inferred schema as a new version to a schema registry '
+             '(directory path or http(s) URL) and report changes since the previous version'
+    )
+    
+    parser.add_argument(
+        '--go-results',
+        action='store_true',
+        help='Generate Go methods returning Result[T], which bundles the value, the '
+             'response metadata, and the error, instead of (T, error)'
+    )
+    
+    parser.add_argument(
+        '--go-struct-depth',
+        type=int,
+        default=3,
+        help='Levels of nested objects in Go bodies that get named structs; deeper '
+             'objects stay map[string]interface{} (default: 3)'
+    )
+    
     parser.add_argument(
         '--verbose',
         action='store_true',
@@ -103,10 +255,30 @@ Examples:
     
     args = parser.parse_args()
     
-    if not args.har and not args.json and not args.capture:
-        parser.error('Please provide either --har, --json, or --capture option')
+    if not args.har and not args.json and not args.from_store and not args.openapi and not args.capture:
+        parser.error('Please provide either --har, --json, --from-store, --openapi, or --capture option')
+    if args.openapi and os.path.abspath(args.openapi) == os.path.abspath(f"{args.output}/go/openapi.yaml"):
+        parser.error(f"{args.openapi} is rewritten when the Go SDK is generated; "
+                     f"copy it out of {args.output} before editing it")
+    
+    output_dir = args.output
+    if args.check:
+        # Regenerate over a copy, as a real run would over the original, so
+        # the names lock is honored and files no longer generated stay put.
+        check_dir = tempfile.mkdtemp(prefix='sdk-check-')
+        args.output = os.path.join(check_dir, 'output')
+        if os.path.isdir(output_dir):
+            shutil.copytree(output_dir, args.output)
+    
+    try:
+        inference_config = InferenceConfig.load(args.config) if args.config else InferenceConfig()
+    except (OSError, ValueError, TypeError) as e:
+        parser.error(f"invalid config {args.config}: {e}")
+    if args.min_samples is not None:
+        inference_config.min_samples = args.min_samples
     
-    traffic_parser = TrafficParser()
+    traffic_parser = TrafficParser(mask_pii=not args.retain_raw_pii, redact_secrets=not args.keep_secrets,
+                                   config=inference_config)
     endpoints = {}
     
     try:
@@ -122,6 +294,16 @@ Examples:
                 traffic_data = json.load(f)
             endpoints = traffic_parser.parse_raw_traffic(traffic_data)
             
+        elif args.from_store:
+            if args.verbose:
+                print(f"📝 Reading capture store: {args.from_store}")
+            endpoints = traffic_parser.parse_capture_store(CaptureStore(args.from_store))
+            
+        elif args.openapi:
+            if args.verbose:
+                print(f"📝 Reading OpenAPI spec: {args.openapi}")
+            endpoints = traffic_parser.parse_openapi(args.openapi)
+            
         elif args.capture:
             print(f"🔴 Live capture mode (experimental)")
             print(f"⚠️  This would require mitmproxy integration")
@@ -132,7 +314,29 @@ Examples:
             print(f"   4. Run: {sys.argv[0]} --har <file.har> --name {args.name}")
             sys.exit(1)
         
-        if not endpoints:
+        if args.save_capture:
+            traffic_parser.save_capture(args.save_capture)
+            if args.verbose:
+                print(f"💾 Saved {len(traffic_parser.captures)} captured exchanges to {args.save_capture}")
+        
+        if args.capture_store:
+            store = CaptureStore(args.capture_store)
+            traffic_parser.write_capture_store(store)
+            if args.verbose:
+                print(f"🗄️  Capture store {args.capture_store}: {len(store.records)} distinct exchanges, "
+                      f"{store.blob_writes} new bodies, {store.blob_dedup_hits} deduplicated ({store.codec})")
+        
+        if args.secrets_file:
+            traffic_parser.secret_redactor.write_secrets(args.secrets_file)
+            if args.verbose:
+                print(f"🔐 Stored {len(traffic_parser.secret_redactor.secrets)} redacted credentials in {args.secrets_file}")
+        
+        graphql_operations = traffic_parser.graphql_operations
+        websocket_channels = traffic_parser.websocket_channels
+        grpc_methods = traffic_parser.grpc_methods
+        webhook_events = traffic_parser.webhook_events
+        
+        if not endpoints and not graphql_operations and not websocket_channels and not grpc_methods and not webhook_events:
             print("❌ No API endpoints detected in the traffic")
             sys.exit(1)
         
@@ -145,6 +349,22 @@ Examples:
         print(f"{'='*50}")
         print(f"📍 Base URL: {base_url}")
         print(f"🔍 Endpoints found: {len(endpoints)}")
+        if graphql_operations:
+            print(f"🕸️  GraphQL operations found: {len(graphql_operations)}")
+        if websocket_channels:
+            print(f"🔌 WebSocket channels found: {len(websocket_channels)}")
+        if grpc_methods:
+            print(f"📡 gRPC methods found: {len(grpc_methods)} (inferred without reflection)")
+        if webhook_events:
+            print(f"🪝 Webhook event types found: {len(webhook_events)}")
+        environments = traffic_parser.environments.get(traffic_parser.base_url, {})
+        if len(environments) > 1:
+            print(f"🌍 Environments: {', '.join(f'{name} ({url})' for name, url in environments.items())}")
+        
+        # A spec's types were settled by whoever wrote it, whatever the samples.
+        low_confidence = [] if args.openapi else traffic_parser.low_confidence_fields()
+        if low_confidence:
+            print(f"⚠️  Low-confidence fields: {len(low_confidence)} (few samples, widened, or conflicting types)")
         
         if args.verbose:
             print(f"\n📋 Detected Endpoints:")
@@ -154,6 +374,55 @@ Examples:
                     print(f"         Path params: {', '.join(endpoint.path_params)}")
                 if endpoint.query_params:
                     print(f"         Query params: {', '.join(endpoint.query_params.keys())}")
+            
+            if graphql_operations:
+                print(f"\n🕸️  GraphQL Operations:")
+                for operation in graphql_operations.values():
+                    print(f"  • {operation.operation_type:12} {operation.name} ({operation.path}, {len(operation.examples)} samples)")
+                    if operation.root_fields:
+                        print(f"         Root fields: {', '.join(operation.root_fields)}")
+            
+            if websocket_channels:
+                print(f"\n🔌 WebSocket Channels:")
+                for channel in websocket_channels.values():
+                    discriminator = channel.discriminator or 'opcode'
+                    print(f"  • {channel.path} ({channel.frame_count} frames, keyed by {discriminator})")
+                    for message_type in channel.message_types.values():
+                        print(f"         {message_type.direction:7} {message_type.name} x{message_type.count}")
+            
+            if low_confidence:
+                print(f"\n⚠️  Low-Confidence Fields:")
+                for label, field_path, reason in low_confidence:
+                    print(f"  • {label} {field_path}: {reason}")
+            
+            if grpc_methods:
+                print(f"\n📡 gRPC Methods:")
+                for grpc_method in grpc_methods.values():
+                    print(f"  • {grpc_method.path} ({grpc_method.calls} calls)")
+                    print(f"         Request fields: {len(grpc_method.request.fields)}, response fields: {len(grpc_method.response.fields)}")
+            
+            if webhook_events:
+                signature = traffic_parser.webhook_parser.signature
+                print(f"\n🪝 Webhook Events ({signature.header + ' signatures' if signature else 'unsigned'}):")
+                for event in webhook_events.values():
+                    print(f"  • {event.event_type or '(untyped)'} → {event.name}Event x{event.count}")
+        
+        if args.registry:
+            registry = open_registry(args.registry)
+            previous = registry.get(args.name)
+            source = args.har or args.json or args.from_store or args.openapi
+            snapshot = registry.publish(SchemaSnapshot.from_endpoints(
+                args.name, base_url, endpoints, [source, traffic_parser.capture_digest()]))
+            if previous and previous.version == snapshot.version:
+                print(f"\n📚 Schema unchanged since registry version {snapshot.version}")
+            else:
+                print(f"\n📚 Published schema version {snapshot.version} to {args.registry}")
+                if previous:
+                    changes = registry.compare(args.name, previous.version, snapshot.version)
+                    print(f"   {len(changes)} changes since version {previous.version}")
+                    if args.verbose:
+                        for change in changes:
+                            print(f"     {change}")
         
         languages = args.languages
         if 'all' in languages:
@@ -162,28 +431,59 @@ Examples:
         print(f"\n🚀 Generating SDKs for: {', '.join(languages)}")
         print(f"{'='*50}")
         
-        generated_files = []
+        partitions = traffic_parser.partition_by_api()
         
-        if 'python' in languages:
-            print(f"🐍 Generating Python SDK...", end=' ')
-            generator = PythonSDKGenerator(args.name, base_url, endpoints)
-            output_file = generator.generate(f"{args.output}/python")
-            generated_files.append(output_file)
-            print(f"✅")
+        if len(partitions) > 1 and not args.base_url:
+            print(f"🌐 Traffic spans {len(partitions)} APIs, using {args.api_layout} layout")
+            generated_files = []
+            module_name = args.name.lower().replace(' ', '_').replace('-', '_')
+            for partition in partitions.values():
+                print(f"\n  {partition.name}: {partition.base_url}{partition.base_path} ({len(partition.endpoints)} endpoints)")
+                grpc_parser = traffic_parser.grpc_parser if partition.base_url == traffic_parser.base_url else None
+                webhook_parser = traffic_parser.webhook_parser if partition.base_url == traffic_parser.base_url else None
+                websocket_parser = traffic_parser.websocket_parser if partition.base_url == traffic_parser.base_url else None
+                if args.api_layout == 'per-api':
+                    output_dirs = {lang: f"{args.output}/{partition.name}/{lang}" for lang in languages}
+                    generated_files += generate_sdks(f"{args.name}_{partition.name}", partition.base_url,
+                                                     partition.endpoints, languages, output_dirs, grpc_parser,
+                                                     traffic_parser.fixture_synthesizer, go_results=args.go_results,
+                                                     go_struct_depth=args.go_struct_depth,
+                                                     webhook_parser=webhook_parser,
+                                                     websocket_parser=websocket_parser,
+                                                     environments=traffic_parser.environments.get(partition.base_url))
+                else:
+                    output_dirs = {lang: f"{args.output}/{lang}/{partition.name}" for lang in languages}
+                    generated_files += generate_sdks(f"{args.name}_{partition.name}", partition.base_url,
+                                                     partition.endpoints, languages, output_dirs, grpc_parser,
+                                                     traffic_parser.fixture_synthesizer,
+                                                     go_package=partition.name,
+                                                     go_module_dir=f"{args.output}/go",
+                                                     go_module_name=module_name,
+                                                     go_results=args.go_results,
+                                                     go_struct_depth=args.go_struct_depth,
+                                                     webhook_parser=webhook_parser,
+                                                     websocket_parser=websocket_parser,
+                                                     environments=traffic_parser.environments.get(partition.base_url))
+        else:
+            output_dirs = {lang: f"{args.output}/{lang}" for lang in languages}
+            generated_files = generate_sdks(args.name, base_url, endpoints, languages, output_dirs,
+                                            traffic_parser.grpc_parser, traffic_parser.fixture_synthesizer,
+                                            go_results=args.go_results,
+                                            go_struct_depth=args.go_struct_depth,
+                                            webhook_parser=traffic_parser.webhook_parser,
+                                            websocket_parser=traffic_parser.websocket_parser,
+                                            environments=traffic_parser.environments.get(traffic_parser.base_url))
         
-        if 'typescript' in languages:
-            print(f"📘 Generating TypeScript SDK...", end=' ')
-            generator = TypeScriptSDKGenerator(args.name, base_url, endpoints)
-            output_file = generator.generate(f"{args.output}/typescript")
-            generated_files.append(output_file)
-            print(f"✅")
-        
-        if 'go' in languages:
-            print(f"🐹 Generating Go SDK...", end=' ')
-            generator = GoSDKGenerator(args.name, base_url, endpoints)
-            output_file = generator.generate(f"{args.output}/go")
-            generated_files.append(output_file)
-            print(f"✅")
+        if args.check:
+            changed = changed_files(output_dir, args.output)
+            shutil.rmtree(check_dir)
+            if changed:
+                print(f"\n❌ Regenerating would change these files in {output_dir}:")
+                for path in changed:
+                    print(f"  • {path}")
+                sys.exit(1)
+            print(f"\n✅ {output_dir} is up to date")
+            return
         
         print(f"\n🎉 SDK Generation Complete!")
         print(f"{'='*50}")
@@ -0,0 +1,198 @@
+import re
+import json
+from datetime import datetime
+from collections import Counter
+from typing import Dict, List, Any, Optional
+
+
+class _Observations:
+    def __init__(self):
+        self.count = 0
+        self.nulls = 0
+        self.types: Counter = Counter()
+        self.strings: List[str] = []
+        self.numbers: List[float] = []
+        self.trues = 0
+        self.lengths: List[int] = []
+        self.properties: Dict[str, '_Observations'] = {}
+        self.presence: Counter = Counter()
+        self.objects = 0
+        self.items: Optional['_Observations'] = None
+
+
+class FixtureSynthesizer:
+    """Learns value distributions from captured bodies for fake fixtures.
+
+    Each field is reduced to a profile (string format, enum frequencies,
+    numeric range, null and presence rates, array lengths) from which the
+    generated `fixtures` package produces realistic but synthetic values.
+    Only category-like values (enums) are copied from the capture; names,
+    emails, ids and free text are regenerated from their format, so
+    fixtures never carry real customer data.
+    """
+
+    EMAIL_PATTERN = re.compile(r'^[\w.+-]+@[\w-]+(?:\.[\w-]+)+$')
+    UUID_PATTERN = re.compile(r'^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$')
+    DATETIME_PATTERN = re.compile(r'^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?$')
+    DATE_PATTERN = re.compile(r'^\d{4}-\d{2}-\d{2}$')
+    URL_PATTERN = re.compile(r'^https?://\S+$')
+    NAME_PATTERN = re.compile(r"^[A-Z][a-z'-]+(?: [A-Z][a-z'-]+)+$")
+    ENUM_KEYS = {'status', 'state', 'type', 'kind', 'role', 'category', 'currency', 'level', 'tier', 'plan', 'visibility'}
+    MAX_ENUM_VALUES = 10
+
+    def __init__(self, max_enum_values: int = MAX_ENUM_VALUES):
+        self.max_enum_values = max_enum_values
+        self._requests: Dict[str, _Observations] = {}
+        self._responses: Dict[str, Dict[str, _Observations]] = {}
+
+    def observe(self, endpoint: str, capture: Dict[str, Any]):
+        request = capture.get('request', {})
+        response = capture.get('response', {})
+
+        request_body = self._load_json(request.get('body'), request.get('encoding'))
+        if request_body is not None:
+            self._observe(self._requests.setdefault(endpoint, _Observations()), request_body)
+
+        response_body = self._load_json(response.get('body'), response.get('encoding'))
+        if response_body is not None:
+            statuses = self._responses.setdefault(endpoint, {})
+            self._observe(statuses.setdefault(str(response.get('status', 200)), _Observations()), response_body)
+
+    def profiles(self, endpoints: Optional[List[str]] = None) -> Dict[str, Dict[str, Any]]:
+        profiles = {}
+        for endpoint in sorted(set(self._requests) | set(self._responses)):
+            if endpoints is not None and endpoint not in endpoints:
+                continue
+            profile: Dict[str, Any] = {}
+            if endpoint in self._requests:
+                profile['request'] = self._build(self._requests[endpoint], '')
+            responses = self._responses.get(endpoint, {})
+            if responses:
+                profile['responses'] = {status: self._build(obs, '') for status, obs in sorted(responses.items())}
+            profiles[endpoint] = profile
+        return profiles
+
+    def render_profiles(self, endpoints: Optional[List[str]] = None) -> str:
+        return json.dumps(self.profiles(endpoints), indent=2, sort_keys=True) + '\n'
+
+    def _observe(self, obs: _Observations, value: Any):
+        obs.count += 1
+        if value is None:
+            obs.nulls += 1
+            return
+        if isinstance(value, bool):
+            obs.types['boolean'] += 1
+            obs.trues += value
+        elif isinstance(value, int):
+            obs.types['integer'] += 1
+            obs.numbers.append(value)
+        elif isinstance(value, float):
+            obs.types['number'] += 1
+            obs.numbers.append(value)
+        elif isinstance(value, str):
+            obs.types['string'] += 1
+            obs.strings.append(value)
+        elif isinstance(value, list):
+            obs.types['array'] += 1
+            obs.lengths.append(len(value))
+            if obs.items is None:
+                obs.items = _Observations()
+            for item in value:
+                self._observe(obs.items, item)
+        elif isinstance(value, dict):
+            obs.types['object'] += 1
+            obs.objects += 1
+            for key, child in value.items():
+                obs.presence[key] += 1
+                self._observe(obs.properties.setdefault(key, _Observations()), child)
+
+    def _build(self, obs: _Observations, key: str) -> Dict[str, Any]:
+        non_null = obs.count - obs.nulls
+        if not obs.types:
+            return {'kind': 'null'}
+
+        kind = obs.types.most_common(1)[0][0]
+        if kind == 'integer' and obs.types['number']:
+            kind = 'number'
+        node: Dict[str, Any] = {'kind': kind}
+        if obs.nulls:
+            node['null_rate'] = round(obs.nulls / obs.count, 3)
+
+        if kind == 'object':
+            node['properties'] = {name: self._build(child, name) for name, child in sorted(obs.properties.items())}
+            presence = {name: round(count / obs.objects, 3) for name, count in sorted(obs.presence.items())
+                        if count < obs.objects}
+            if presence:
+                node['presence'] = presence
+        elif kind == 'array':
+            node['min_items'] = min(obs.lengths)
+            node['max_items'] = max(obs.lengths)
+            node['items'] = self._build(obs.items, key) if obs.items and obs.items.count else {'kind': 'null'}
+        elif kind == 'boolean':
+            node['true_rate'] = round(obs.trues / max(non_null, 1), 3)
+        elif kind in ('integer', 'number'):
+            node['min'] = min(obs.numbers)
+            node['max'] = max(obs.numbers)
+            if kind == 'integer' and (key == 'id' or key.endswith('_id')):
+                node['sequence'] = True
+        elif kind == 'string':
+            node.update(self._string_profile(obs.strings, key))
+        return node
+
+    def _string_profile(self, values: List[str], key: str) -> Dict[str, Any]:
+        profile: Dict[str, Any] = {
+            'min_length': min(len(v) for v in values),
+            'max_length': max(len(v) for v in values)
+        }
+
+        for fmt, pattern in (('email', self.EMAIL_PATTERN), ('uuid', self.UUID_PATTERN),
+                             ('date-time', self.DATETIME_PATTERN), ('date', self.DATE_PATTERN),
+                             ('url', self.URL_PATTERN), ('name', self.NAME_PATTERN)):
+            if fmt == 'name' and 'name' not in key.lower():
+                continue
+            if all(pattern.match(v) for v in values):
+                profile['format'] = fmt
+                if fmt in ('date-time', 'date'):
+                    profile.update(self._time_range(values))
+                return profile
+
+        counts = Counter(values)
+        repeated = len(values) >= 3 and len(counts) <= len(values) // 2
+        if len(counts) <= self.max_enum_values and (key.lower() in self.ENUM_KEYS or repeated):
+            profile['format'] = 'enum'
+            profile['enum'] = [{'value': value, 'weight': round(count / len(values), 3)}
+                               for value, count in sorted(counts.items())]
+            return profile
+
+        templates = {self._template(v) for v in values}
+        if len(templates) == 1 and not any(c == ' ' for c in next(iter(templates))):
+            profile['format'] = 'pattern'
+            profile['pattern'] = templates.pop()
+            return profile
+
+        profile['format'] = 'text'
+        return profile
+
+    def _template(self, value: str) -> str:
+        return ''.join('9' if c.isdigit() else 'A' if c.isupper() else 'a' if c.islower() else c for c in value)
+
+    def _time_range(self, values: List[str]) -> Dict[str, Any]:
+        stamps = []
+        for value in values:
+            try:
+                stamps.append(datetime.fromisoformat(value.replace('Z', '+00:00').replace(' ', 'T')).timestamp())
+            except ValueError:
+                continue
+        if not stamps:
+            return {}
+        return {'min': int(min(stamps)), 'max': int(max(stamps))}
+
+    def _load_json(self, body: Any, encoding: Optional[str]) -> Any:
+        if isinstance(body, (dict, list)):
+            return body
+        if not body or encoding == 'base64':
+            return None
+        try:
+            return json.loads(body)
+        except (json.JSONDecodeError, TypeError):
+            return None
@@ -0,0 +1,96 @@
+// Package agenttool is the interface through which agents discover and call
+// the capabilities of generated SDKs. Each SDK's Tools method returns one
+// Tool per captured operation, so an agent can list what an API offers and
+// invoke it with JSON arguments without knowing its Go types:
+//
+//	tools := client.Tools()
+//	catalog, _ := agenttool.Catalog(tools) // for the model's tool list
+//	out, err := agenttool.Find(tools, "list_users").Invoke(ctx, []byte(`{}`))
+package agenttool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Tool is a callable capability with a JSON Schema for its input.
+type Tool interface {
+	// Name identifies the tool, such as "get_user".
+	Name() string
+	// Description tells an agent what the tool does.
+	Description() string
+	// InputSchema is the JSON Schema of the object Invoke accepts.
+	InputSchema() json.RawMessage
+	// Invoke calls the tool with input matching InputSchema and returns
+	// its JSON result.
+	Invoke(ctx context.Context, input json.RawMessage) (json.RawMessage, error)
+}
+
+// Func is a Tool made of its parts.
+type Func struct {
+	ToolName        string
+	ToolDescription string
+	Schema          json.RawMessage
+	Call            func(ctx context.Context, input json.RawMessage) (json.RawMessage, error)
+}
+
+func (f Func) Name() string                 { return f.ToolName }
+func (f Func) Description() string          { return f.ToolDescription }
+func (f Func) InputSchema() json.RawMessage { return f.Schema }
+
+func (f Func) Invoke(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	return f.Call(ctx, input)
+}
+
+// Find returns the tool named name, or nil.
+func Find(tools []Tool, name string) Tool {
+	for _, tool := range tools {
+		if tool.Name() == name {
+			return tool
+		}
+	}
+	return nil
+}
+
+// Invoke calls the tool named name, for agents that pick tools by name.
+func Invoke(ctx context.Context, tools []Tool, name string, input json.RawMessage) (json.RawMessage, error) {
+	tool := Find(tools, name)
+	if tool == nil {
+		return nil, fmt.Errorf("agenttool: no tool %q", name)
+	}
+	return tool.Invoke(ctx, input)
+}
+
+// Descriptor is a tool's name, description, and input schema, as listed
+// to models that choose tools.
+type Descriptor struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// Catalog describes tools as a JSON array of Descriptors.
+func Catalog(tools []Tool) ([]byte, error) {
+	descriptors := make([]Descriptor, len(tools))
+	for i, tool := range tools {
+		descriptors[i] = Descriptor{tool.Name(), tool.Description(), tool.InputSchema()}
+	}
+	return json.MarshalIndent(descriptors, "", "  ")
+}
+
+// DecodeInput unmarshals a tool's input into v, treating empty input as an
+// empty object and rejecting unknown fields so that misspelt arguments are
+// reported rather than ignored.
+func DecodeInput(input json.RawMessage, v interface{}) error {
+	if len(input) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("agenttool: invalid input: %w", err)
+	}
+	return nil
+}
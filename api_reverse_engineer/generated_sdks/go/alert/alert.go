@@ -0,0 +1,229 @@
+// Package alert delivers schema-violation alerts raised by the drift
+// monitor (and any other validator) to pluggable sinks: generic webhooks,
+// Slack incoming webhooks, and a Prometheus metric that alert rules can
+// fire on.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Alert describes one schema violation.
+type Alert struct {
+	Source   string    `json:"source"`
+	API      string    `json:"api,omitempty"`
+	Kind     string    `json:"kind"`
+	Endpoint string    `json:"endpoint"`
+	Field    string    `json:"field,omitempty"`
+	Expected string    `json:"expected,omitempty"`
+	Observed string    `json:"observed,omitempty"`
+	Sample   string    `json:"sample,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Summary renders the alert as a single line.
+func (a Alert) Summary() string {
+	location := strings.TrimSpace(a.Endpoint + " " + a.Field)
+	summary := fmt.Sprintf("[%s] %s: %s", a.Source, a.Kind, location)
+	if a.API != "" {
+		summary = fmt.Sprintf("[%s] %s %s: %s", a.Source, a.API, a.Kind, location)
+	}
+	if a.Expected != "" || a.Observed != "" {
+		summary += fmt.Sprintf(" (expected %s, observed %s)", orNone(a.Expected), orNone(a.Observed))
+	}
+	return summary
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// Sink receives alerts.
+type Sink interface {
+	Send(ctx context.Context, a Alert) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, a Alert) error
+
+// Send calls f.
+func (f SinkFunc) Send(ctx context.Context, a Alert) error { return f(ctx, a) }
+
+// Multi fans an alert out to every sink and joins their errors.
+func Multi(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, a Alert) error {
+		var errs []error
+		for _, sink := range sinks {
+			if err := sink.Send(ctx, a); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Webhook POSTs each alert as JSON to URL.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// Send implements Sink.
+func (w *Webhook) Send(ctx context.Context, a Alert) error {
+	return postJSON(ctx, w.Client, w.URL, w.Headers, a)
+}
+
+// Slack posts alerts to a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string
+	// Channel overrides the webhook's default channel when set.
+	Channel string
+	Client  *http.Client
+}
+
+// Send implements Sink.
+func (s *Slack) Send(ctx context.Context, a Alert) error {
+	text := fmt.Sprintf(":warning: *Schema violation* %s", a.Summary())
+	if a.Sample != "" {
+		text += fmt.Sprintf("\n```%s```", a.Sample)
+	}
+	payload := map[string]string{"text": text}
+	if s.Channel != "" {
+		payload["channel"] = s.Channel
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, nil, payload)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert: %s responded with status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+type seriesKey struct {
+	source, api, kind, endpoint, field string
+}
+
+type series struct {
+	count    float64
+	lastSeen time.Time
+}
+
+// Prometheus counts alerts and serves them in the Prometheus text format,
+// so alert rules such as
+//
+//	increase(example_schema_violations_total[15m]) > 0
+//
+// can page on upstream API changes. Mount it on a metrics endpoint.
+type Prometheus struct {
+	namespace string
+	mu        sync.Mutex
+	series    map[seriesKey]*series
+}
+
+// NewPrometheus creates a Prometheus sink whose metric names start with
+// namespace.
+func NewPrometheus(namespace string) *Prometheus {
+	return &Prometheus{namespace: namespace, series: make(map[seriesKey]*series)}
+}
+
+// Send implements Sink.
+func (p *Prometheus) Send(_ context.Context, a Alert) error {
+	key := seriesKey{a.Source, a.API, a.Kind, a.Endpoint, a.Field}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.series[key]
+	if !ok {
+		s = &series{}
+		p.series[key] = s
+	}
+	s.count++
+	s.lastSeen = a.Time
+	return nil
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	keys := make([]seriesKey, 0, len(p.series))
+	values := make(map[seriesKey]series, len(p.series))
+	for key, s := range p.series {
+		keys = append(keys, key)
+		values[key] = *s
+	}
+	p.mu.Unlock()
+	sort.Slice(keys, func(i, j int) bool { return labels(keys[i]) < labels(keys[j]) })
+
+	var buf bytes.Buffer
+	name := p.metricName("schema_violations_total")
+	fmt.Fprintf(&buf, "# HELP %s Distinct schema violations alerted on.\n# TYPE %s counter\n", name, name)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s{%s} %g\n", name, labels(key), values[key].count)
+	}
+	name = p.metricName("schema_violation_last_seen_timestamp_seconds")
+	fmt.Fprintf(&buf, "# HELP %s Unix time a schema violation was last observed.\n# TYPE %s gauge\n", name, name)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s{%s} %d\n", name, labels(key), values[key].lastSeen.Unix())
+	}
+	return buf.WriteTo(w)
+}
+
+func (p *Prometheus) metricName(name string) string {
+	if p.namespace == "" {
+		return name
+	}
+	return p.namespace + "_" + name
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labels(key seriesKey) string {
+	pairs := [][2]string{{"source", key.source}, {"api", key.api}, {"kind", key.kind}, {"endpoint", key.endpoint}, {"field", key.field}}
+	parts := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pair[0], labelEscaper.Replace(pair[1])))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,107 @@
+package example_api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AuditRecord describes one API call, for access logs of the data pulled
+// through the SDK.
+type AuditRecord struct {
+	Time        time.Time         // when the call started
+	Caller      string            // from ContextWithCaller; empty if unset
+	Operation   string            // "GET /v1/users/{id}"
+	Path        string            // "/v1/users/42"
+	ResourceIDs map[string]string // path parameters: {"id": "42"}
+	StatusCode  int               // 0 when no response arrived
+	Err         error             // nil on success
+	Duration    time.Duration
+}
+
+// Outcome is "success" or "error".
+func (r AuditRecord) Outcome() string {
+	if r.Err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// AuditSink receives a record of every API call. Audit runs synchronously
+// after the call, so slow sinks should buffer.
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord)
+}
+
+// AuditSinkFunc adapts a function to AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord)
+
+// Audit implements AuditSink.
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) {
+	f(ctx, record)
+}
+
+// WithAuditSink sends a record of every call to sink.
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.auditSink = sink
+	}
+}
+
+type callerKey struct{}
+
+// ContextWithCaller returns a context identifying the caller on whose
+// behalf calls made with it run, for the audit log.
+func ContextWithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller set with ContextWithCaller.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// audit sends the record of a finished call to the audit sink. It is
+// deferred by doRequest, and a panicking sink fails the call.
+func (c *ExampleapiClient) audit(method, endpoint, path string, options *requestOptions, start time.Time, err *error) {
+	if c.auditSink == nil {
+		return
+	}
+	operation := method + " " + endpoint
+	defer recoverPanic(operation, 1, err)
+	c.auditSink.Audit(options.ctx, AuditRecord{
+		Time:        start,
+		Caller:      CallerFromContext(options.ctx),
+		Operation:   operation,
+		Path:        path,
+		ResourceIDs: pathParams(endpoint, path),
+		StatusCode:  options.status,
+		Err:         *err,
+		Duration:    time.Since(start),
+	})
+}
+
+// pathParams matches path against the endpoint pattern's {name} segments.
+func pathParams(pattern, path string) map[string]string {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return nil
+	}
+	var params map[string]string
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			value, err := url.PathUnescape(pathSegments[i])
+			if err != nil {
+				value = pathSegments[i]
+			}
+			params[segment[1:len(segment)-1]] = value
+		}
+	}
+	return params
+}
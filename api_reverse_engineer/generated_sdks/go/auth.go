@@ -0,0 +1,109 @@
+package example_api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// SetAPIKey sets the API key, sent the way the captured requests sent
+// theirs: as a bearer token. It is safe to call while other goroutines make
+// calls.
+func (c *ExampleapiClient) SetAPIKey(key string) {
+	c.SetAuthToken(key)
+}
+
+// WithAPIKeyHeader sends key in header, e.g. X-Api-Key.
+func WithAPIKeyHeader(header, key string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.setHeaderCredential(header, key)
+	}
+}
+
+// WithAPIKeyQuery sends key as the query parameter param, e.g. api_key, of
+// every request. Debug output and logs redact it.
+func WithAPIKeyQuery(param, key string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.setQueryCredential(param, key)
+	}
+}
+
+// WithBasicAuth authorizes with HTTP basic auth.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.SetHeader("Authorization", basicAuth(username, password))
+	}
+}
+
+// WithSessionCookie sends the cookie name=value with every request, for
+// APIs authenticated by a browser session. Cookies from several calls are
+// combined.
+func WithSessionCookie(name, value string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.setCookie(name, value)
+	}
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// setCookie adds name=value to the Cookie header, replacing any earlier
+// value of name.
+func (c *ExampleapiClient) setCookie(name, value string) {
+	c.headerMu.Lock()
+	defer c.headerMu.Unlock()
+	cookies := []string{name + "=" + value}
+	for _, cookie := range strings.Split(c.Headers["Cookie"], ";") {
+		cookie = strings.TrimSpace(cookie)
+		if cookie != "" && !strings.HasPrefix(cookie, name+"=") {
+			cookies = append(cookies, cookie)
+		}
+	}
+	c.Headers["Cookie"] = strings.Join(cookies, "; ")
+}
+
+// credentialHeaders holds the canonical names of the headers clients send
+// API keys in, so that they are redacted like the usual credential headers.
+var credentialHeaders sync.Map
+
+// setHeaderCredential sends key in header.
+func (c *ExampleapiClient) setHeaderCredential(header, key string) {
+	credentialHeaders.Store(http.CanonicalHeaderKey(header), true)
+	c.SetHeader(header, key)
+}
+
+// credentialParams holds the names of the query parameters clients send
+// credentials in, lowercased, so that they are redacted like the usual ones.
+var credentialParams sync.Map
+
+// setQueryCredential sends key as the query parameter param. The map is
+// replaced rather than changed, so derived clients can share it.
+func (c *ExampleapiClient) setQueryCredential(param, key string) {
+	c.headerMu.Lock()
+	defer c.headerMu.Unlock()
+	credentials := map[string]string{param: key}
+	for name, value := range c.queryCredentials {
+		if name != param {
+			credentials[name] = value
+		}
+	}
+	c.queryCredentials = credentials
+	credentialParams.Store(strings.ToLower(param), true)
+}
+
+// applyQueryCredentials adds the client's query credentials to req.
+func (c *ExampleapiClient) applyQueryCredentials(req *http.Request) {
+	c.headerMu.RLock()
+	credentials := c.queryCredentials
+	c.headerMu.RUnlock()
+	if len(credentials) == 0 {
+		return
+	}
+	query := req.URL.Query()
+	for param, key := range credentials {
+		query.Set(param, key)
+	}
+	req.URL.RawQuery = query.Encode()
+}
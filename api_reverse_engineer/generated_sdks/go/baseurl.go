@@ -0,0 +1,32 @@
+package example_api
+
+import "strings"
+
+// defaultBaseURL is the base URL of DefaultEnvironment.
+const defaultBaseURL = "https://api.example.com"
+
+// endpointBaseURLs maps endpoints captured on another host than
+// defaultBaseURL, such as uploads or exports, to that host.
+var endpointBaseURLs = map[string]string{}
+
+// WithBaseURL sends one call to baseURL instead of the client's BaseURL, for
+// endpoints served from another host (uploads., exports., auth.).
+func WithBaseURL(baseURL string) RequestOption {
+	return func(o *requestOptions) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// baseURL returns where a call goes: the per-call WithBaseURL, else the host
+// the endpoint was captured on while the client uses the default base URL,
+// else the client's BaseURL. Pointing BaseURL elsewhere (a staging API or a
+// test server) therefore sends every endpoint there.
+func (c *ExampleapiClient) baseURL(method, endpoint string, options *requestOptions) string {
+	if options.baseURL != "" {
+		return options.baseURL
+	}
+	if host, ok := endpointBaseURLs[method+" "+endpoint]; ok && c.BaseURL == defaultBaseURL {
+		return host
+	}
+	return c.BaseURL
+}
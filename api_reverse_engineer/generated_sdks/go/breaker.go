@@ -0,0 +1,200 @@
+package example_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned (wrapped), without a request being sent, by
+// calls whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of one circuit of a breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests fast until its cooldown has passed.
+	CircuitOpen
+	// CircuitHalfOpen lets probe requests through; the first to succeed
+	// closes the circuit and the first to fail opens it again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// BreakerPolicy configures a circuit breaker. A request fails when no
+// response arrives, other than because its caller's context ended, or the
+// response is a 5xx other than 501; any other response succeeds.
+type BreakerPolicy struct {
+	// FailureThreshold is the consecutive failures that open a circuit;
+	// zero means 5.
+	FailureThreshold int
+	// Cooldown is how long an open circuit fails requests before letting a
+	// probe through; zero means 30 seconds.
+	Cooldown time.Duration
+	// HalfOpenProbes is how many probes may be in flight at once; zero
+	// means 1.
+	HalfOpenProbes int
+	// PerOperation keeps a circuit per operation ("GET /v1/users/{id}")
+	// rather than per host, for APIs whose endpoints fail independently.
+	PerOperation bool
+	// OnStateChange, if set, is called when a circuit changes state.
+	OnStateChange func(circuit string, from, to CircuitState)
+}
+
+// WithCircuitBreaker stops sending requests to a host, or an operation,
+// after policy.FailureThreshold consecutive failures, failing calls with
+// ErrCircuitOpen instead, so batch jobs fail fast rather than hammering an
+// API that is down. After the cooldown, probe requests test whether it has
+// recovered. Every attempt of a retried call counts, and an open circuit
+// ends the retries.
+func WithCircuitBreaker(policy BreakerPolicy) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.breaker = &circuitBreaker{policy: policy, circuits: make(map[string]*breakerCircuit)}
+	}
+}
+
+// CircuitStates returns the state of each circuit of the client's breaker
+// that has seen a request, keyed by host or operation.
+func (c *ExampleapiClient) CircuitStates() map[string]CircuitState {
+	states := make(map[string]CircuitState)
+	if c.breaker == nil {
+		return states
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	for key, circuit := range c.breaker.circuits {
+		states[key] = circuit.current(c.breaker.cooldown(), time.Now())
+	}
+	return states
+}
+
+type circuitBreaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	circuits map[string]*breakerCircuit
+}
+
+type breakerCircuit struct {
+	state    CircuitState
+	failures int
+	opened   time.Time
+	probes   int
+}
+
+// current is the circuit's state, an open circuit becoming half-open once
+// the cooldown has passed.
+func (c *breakerCircuit) current(cooldown time.Duration, now time.Time) CircuitState {
+	if c.state == CircuitOpen && now.Sub(c.opened) >= cooldown {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+func (b *circuitBreaker) cooldown() time.Duration {
+	if b.policy.Cooldown <= 0 {
+		return 30 * time.Second
+	}
+	return b.policy.Cooldown
+}
+
+// allow admits a request on the circuit key, returning the function that
+// reports its outcome, or an error wrapping ErrCircuitOpen. It admits
+// every request on a nil breaker.
+func (b *circuitBreaker) allow(key string) (func(ctx context.Context, resp *http.Response, err error), error) {
+	if b == nil {
+		return func(context.Context, *http.Response, error) {}, nil
+	}
+	b.mu.Lock()
+	circuit := b.circuits[key]
+	if circuit == nil {
+		circuit = &breakerCircuit{}
+		b.circuits[key] = circuit
+	}
+	now := time.Now()
+	from := circuit.state
+	probe := false
+	switch circuit.current(b.cooldown(), now) {
+	case CircuitOpen:
+		wait := b.cooldown() - now.Sub(circuit.opened)
+		b.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s (next probe in %s)", ErrCircuitOpen, key, wait.Round(time.Millisecond))
+	case CircuitHalfOpen:
+		probes := b.policy.HalfOpenProbes
+		if probes <= 0 {
+			probes = 1
+		}
+		if circuit.probes >= probes {
+			b.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s (probe in flight)", ErrCircuitOpen, key)
+		}
+		circuit.state = CircuitHalfOpen
+		circuit.probes++
+		probe = true
+	}
+	b.mu.Unlock()
+	if probe {
+		b.changed(key, from, CircuitHalfOpen)
+	}
+
+	return func(ctx context.Context, resp *http.Response, err error) {
+		b.mu.Lock()
+		from := circuit.state
+		if probe {
+			circuit.probes--
+		}
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// The caller gave up; that says nothing about the server.
+		case err != nil || resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+			circuit.failures++
+			if circuit.state == CircuitHalfOpen || circuit.failures >= b.threshold() {
+				circuit.state, circuit.opened = CircuitOpen, time.Now()
+			}
+		default:
+			circuit.state, circuit.failures = CircuitClosed, 0
+		}
+		to := circuit.state
+		b.mu.Unlock()
+		b.changed(key, from, to)
+	}, nil
+}
+
+func (b *circuitBreaker) threshold() int {
+	if b.policy.FailureThreshold <= 0 {
+		return 5
+	}
+	return b.policy.FailureThreshold
+}
+
+// changed calls the policy's OnStateChange if the state changed.
+func (b *circuitBreaker) changed(key string, from, to CircuitState) {
+	if from != to && b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(key, from, to)
+	}
+}
+
+// circuitKey is the breaker circuit a request to operation belongs to.
+func (c *ExampleapiClient) circuitKey(operation string, req *http.Request) string {
+	if c.breaker != nil && c.breaker.policy.PerOperation {
+		return operation
+	}
+	return req.URL.Host
+}
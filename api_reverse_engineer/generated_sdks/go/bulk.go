@@ -0,0 +1,185 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultConcurrency is the number of calls a bulk helper makes at once
+// unless Concurrency says otherwise.
+const DefaultConcurrency = 4
+
+// ErrBulkSkipped is the error of the items a bulk helper run with
+// StopOnError did not call because an earlier call failed.
+var ErrBulkSkipped = errors.New("skipped after an earlier call failed")
+
+// BulkOption adjusts a call to a bulk helper such as CreatePostsBatch.
+type BulkOption func(*bulkOptions)
+
+type bulkOptions struct {
+	concurrency    int
+	stopOnError    bool
+	requestOptions []RequestOption
+}
+
+// Concurrency sets how many calls a bulk helper makes at once. Every call
+// still waits for the client's rate limiter and circuit breaker, so a high
+// value cannot push the client past its configured rate.
+func Concurrency(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n < 1 {
+			n = 1
+		}
+		o.concurrency = n
+	}
+}
+
+// StopOnError stops a bulk helper from starting calls once one has failed.
+// Calls already under way finish; the rest fail with ErrBulkSkipped.
+func StopOnError() BulkOption {
+	return func(o *bulkOptions) {
+		o.stopOnError = true
+	}
+}
+
+// WithBulkRequestOptions applies opts to every call a bulk helper makes.
+func WithBulkRequestOptions(opts ...RequestOption) BulkOption {
+	return func(o *bulkOptions) {
+		o.requestOptions = append(o.requestOptions, opts...)
+	}
+}
+
+// BulkResult is the outcome of one item of a bulk call.
+type BulkResult[T any] struct {
+	Index int // the item's position in the slice passed in
+	Value T
+	Err   error
+}
+
+// BulkError is the error of a bulk call some of whose items failed. The
+// results still hold the value of every item that succeeded:
+//
+//	results, err := client.CreatePostsBatch(ctx, requests, Concurrency(5))
+//	var bulkErr *BulkError
+//	if errors.As(err, &bulkErr) {
+//		for index, err := range bulkErr.Errors {
+//			log.Printf("item %d: %v", index, err)
+//		}
+//	}
+type BulkError struct {
+	Total  int           // the number of items
+	Errors map[int]error // by the index of the failed item
+}
+
+func (e *BulkError) Error() string {
+	first := e.indexes()[0]
+	return fmt.Sprintf("%d of %d calls failed; item %d: %v", len(e.Errors), e.Total, first, e.Errors[first])
+}
+
+// Unwrap returns the items' errors in item order, so errors.Is and
+// errors.As find, say, an *APIError among them.
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, index := range e.indexes() {
+		errs = append(errs, e.Errors[index])
+	}
+	return errs
+}
+
+func (e *BulkError) indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for index := range e.Errors {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// runBulk calls call for every item from a pool of workers and collects the
+// results in item order. The error is a *BulkError if any item failed.
+// Items not yet started when ctx is done fail with its error.
+func runBulk[T, R any](ctx context.Context, items []T, opts []BulkOption, call func(context.Context, T, []RequestOption) (R, error)) ([]BulkResult[R], error) {
+	o := bulkOptions{concurrency: DefaultConcurrency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	results := make([]BulkResult[R], len(items))
+	indexes := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for worker := 0; worker < o.concurrency && worker < len(items); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := &results[i]
+				result.Index = i
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+					continue
+				}
+				if o.stopOnError && failed.Load() {
+					result.Err = ErrBulkSkipped
+					continue
+				}
+				result.Value, result.Err = call(ctx, items[i], o.requestOptions)
+				if result.Err != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	bulkErr := &BulkError{Total: len(items), Errors: make(map[int]error)}
+	for _, result := range results {
+		if result.Err != nil {
+			bulkErr.Errors[result.Index] = result.Err
+		}
+	}
+	if len(bulkErr.Errors) > 0 {
+		return results, bulkErr
+	}
+	return results, nil
+}
+
+// CreatePostsBatch calls CreatePost (POST /v1/posts) for each of requests, a
+// few at a time, returning their results in the same order.
+func (c *ExampleapiClient) CreatePostsBatch(ctx context.Context, requests []CreatePostRequest, opts ...BulkOption) ([]BulkResult[*CreatePostResponse], error) {
+	return runBulk(ctx, requests, opts, func(ctx context.Context, data CreatePostRequest, opts []RequestOption) (*CreatePostResponse, error) {
+		return c.CreatePost(ctx, &data, opts...)
+	})
+}
+
+// CreateUsersBatch calls CreateUser (POST /v1/users) for each of requests, a
+// few at a time, returning their results in the same order.
+func (c *ExampleapiClient) CreateUsersBatch(ctx context.Context, requests []CreateUserRequest, opts ...BulkOption) ([]BulkResult[*CreateUserResponse], error) {
+	return runBulk(ctx, requests, opts, func(ctx context.Context, data CreateUserRequest, opts []RequestOption) (*CreateUserResponse, error) {
+		return c.CreateUser(ctx, &data, opts...)
+	})
+}
+
+// GetUsersBatch calls GetUser (GET /v1/users/{id}) for each of ids, a few at
+// a time, returning their results in the same order.
+func (c *ExampleapiClient) GetUsersBatch(ctx context.Context, ids []int64, opts ...BulkOption) ([]BulkResult[*GetUserResponse], error) {
+	return runBulk(ctx, ids, opts, func(ctx context.Context, id int64, opts []RequestOption) (*GetUserResponse, error) {
+		return c.GetUser(ctx, id, opts...)
+	})
+}
+
+// DeleteUsersBatch calls DeleteUser (DELETE /v1/users/{id}) for each of ids,
+// a few at a time, returning their results in the same order.
+func (c *ExampleapiClient) DeleteUsersBatch(ctx context.Context, ids []int64, opts ...BulkOption) ([]BulkResult[json.RawMessage], error) {
+	return runBulk(ctx, ids, opts, func(ctx context.Context, id int64, opts []RequestOption) (json.RawMessage, error) {
+		return c.DeleteUser(ctx, id, opts...)
+	})
+}
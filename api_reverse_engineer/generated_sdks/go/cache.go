@@ -0,0 +1,176 @@
+package example_api
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ResponseCache stores the responses to GET calls that carried an ETag or
+// Last-Modified header. Implementations must be safe for concurrent use.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Put(key string, response *CachedResponse)
+}
+
+// CachedResponse is a stored 200 response. Body is the body as received,
+// before any Content-Encoding is removed.
+type CachedResponse struct {
+	Header http.Header
+	Body   []byte
+}
+
+// WithResponseCache makes GET calls conditional: a stored response's ETag
+// is sent as If-None-Match and its Last-Modified as If-Modified-Since, and a
+// 304 Not Modified is answered from cache. Every call still reaches the
+// server, so cached data is never stale, but unchanged resources cost no
+// body, and APIs that do not count 304s against rate limits cost no quota.
+//
+// Entries are keyed by URL and by the credentials sent, so clients with
+// different tokens can share a cache. Streams and downloads are not cached.
+func WithResponseCache(cache ResponseCache) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.cache = cache
+	}
+}
+
+// MemoryCache is a ResponseCache holding the most recently used responses
+// in memory.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries responses;
+// zero or less means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the response stored under key.
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).response, true
+}
+
+// Put stores response under key, evicting the least recently used response
+// when the cache is full.
+func (m *MemoryCache) Put(key string, response *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.entries[key]; ok {
+		element.Value.(*memoryEntry).response = response
+		m.order.MoveToFront(element)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, response: response})
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of stored responses.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// cacheKey identifies req's response: its URL and a digest of its secret
+// headers, so that one client's responses are never served to another.
+func (c *ExampleapiClient) cacheKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if c.isSecretHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	digest := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(digest, "%s: %q\n", name, req.Header[name])
+	}
+	return req.URL.String() + " " + hex.EncodeToString(digest.Sum(nil))
+}
+
+// revalidate makes req conditional on the stored response to it, which it
+// returns; it returns nil when req is not cacheable or nothing is stored.
+// Validators the caller set are left alone.
+func (c *ExampleapiClient) revalidate(req *http.Request) *CachedResponse {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return nil
+	}
+	cached, ok := c.cache.Get(c.cacheKey(req))
+	if !ok {
+		return nil
+	}
+	if etag := cached.Header.Get("ETag"); etag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if modified := cached.Header.Get("Last-Modified"); modified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", modified)
+	}
+	return cached
+}
+
+// cachedResponse answers a 304 to req with the stored response, its
+// headers updated from the 304's as HTTP caches do, and stores the update.
+func (c *ExampleapiClient) cachedResponse(req *http.Request, notModified *http.Response, cached *CachedResponse) *http.Response {
+	header := cached.Header.Clone()
+	for name, values := range notModified.Header {
+		switch name {
+		case "Content-Length", "Content-Encoding", "Content-Type", "Transfer-Encoding":
+		default:
+			header[name] = values
+		}
+	}
+	c.cache.Put(c.cacheKey(req), &CachedResponse{Header: header, Body: cached.Body})
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       notModified.Request,
+	}
+}
+
+// storeResponse stores a 200 response to a GET request if it carries a
+// validator; wire is its body as received.
+func (c *ExampleapiClient) storeResponse(req *http.Request, resp *http.Response, wire []byte) {
+	if c.cache == nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return
+	}
+	if resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return
+	}
+	c.cache.Put(c.cacheKey(req), &CachedResponse{Header: resp.Header.Clone(), Body: wire})
+}
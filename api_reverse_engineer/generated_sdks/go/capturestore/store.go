@@ -0,0 +1,200 @@
+// Package capturestore reads capture stores written by the API reverse
+// engineering tool (cli.py --capture-store) so Go code can query recorded
+// samples per endpoint without loading every body up front.
+package capturestore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Decoder wraps a compressed blob reader with a decompressing reader.
+type Decoder func(r io.Reader) (io.ReadCloser, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"gzip": func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	}
+)
+
+// RegisterDecoder installs a decoder for a blob codec. gzip is built in;
+// stores written with zstd need one registered, for example backed by
+// github.com/klauspost/compress/zstd.
+func RegisterDecoder(codec string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[codec] = decoder
+}
+
+// Message is the stored half of an exchange. Body holds the sha256 digest
+// of the body blob, not the body itself; use Store.Body to read it.
+type Message struct {
+	Headers  map[string]string `json:"headers,omitempty"`
+	Encoding string            `json:"encoding,omitempty"`
+	Body     string            `json:"body,omitempty"`
+}
+
+// Record is one distinct captured exchange. Count is how many identical
+// exchanges were collapsed into it.
+type Record struct {
+	ID        string  `json:"id"`
+	Endpoint  string  `json:"endpoint"`
+	Method    string  `json:"method"`
+	URL       string  `json:"url"`
+	Status    int     `json:"status"`
+	StartedAt string  `json:"started_at,omitempty"`
+	Count     int     `json:"count"`
+	Request   Message `json:"request"`
+	Response  Message `json:"response"`
+}
+
+// Query filters records. Zero-valued fields match everything.
+type Query struct {
+	Endpoint string
+	Method   string
+	Status   int
+	Limit    int
+}
+
+// Store is a read-only view of a capture store directory.
+type Store struct {
+	root       string
+	codec      string
+	records    []Record
+	byEndpoint map[string][]int
+}
+
+// Open loads the index of the capture store at root.
+func Open(root string) (*Store, error) {
+	metaFile, err := os.ReadFile(filepath.Join(root, "store.json"))
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		Version int    `json:"version"`
+		Codec   string `json:"codec"`
+	}
+	if err := json.Unmarshal(metaFile, &meta); err != nil {
+		return nil, fmt.Errorf("capturestore: invalid store.json: %w", err)
+	}
+
+	s := &Store{root: root, codec: meta.Codec, byEndpoint: make(map[string][]int)}
+
+	index, err := os.Open(filepath.Join(root, "index.jsonl"))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+
+	scanner := bufio.NewScanner(index)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("capturestore: invalid index record: %w", err)
+		}
+		s.byEndpoint[record.Endpoint] = append(s.byEndpoint[record.Endpoint], len(s.records))
+		s.records = append(s.records, record)
+	}
+	return s, scanner.Err()
+}
+
+// Codec reports the compression codec used for blobs.
+func (s *Store) Codec() string {
+	return s.codec
+}
+
+// Endpoints lists the endpoint labels ("GET /v1/users/{id}") in the store.
+func (s *Store) Endpoints() []string {
+	endpoints := make([]string, 0, len(s.byEndpoint))
+	for endpoint := range s.byEndpoint {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// Samples returns every record captured for endpoint.
+func (s *Store) Samples(endpoint string) []Record {
+	return s.Query(Query{Endpoint: endpoint})
+}
+
+// Query returns the records matching q in capture order.
+func (s *Store) Query(q Query) []Record {
+	candidates := s.records
+	if q.Endpoint != "" {
+		indexes := s.byEndpoint[q.Endpoint]
+		candidates = make([]Record, len(indexes))
+		for i, idx := range indexes {
+			candidates[i] = s.records[idx]
+		}
+	}
+
+	var matched []Record
+	for _, record := range candidates {
+		if q.Method != "" && record.Method != q.Method {
+			continue
+		}
+		if q.Status != 0 && record.Status != q.Status {
+			continue
+		}
+		matched = append(matched, record)
+		if q.Limit > 0 && len(matched) == q.Limit {
+			break
+		}
+	}
+	return matched
+}
+
+// Body reads and decompresses the blob with the given digest. An empty
+// digest yields a nil body.
+func (s *Store) Body(digest string) ([]byte, error) {
+	if digest == "" {
+		return nil, nil
+	}
+	decodersMu.RLock()
+	decoder, ok := decoders[s.codec]
+	decodersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("capturestore: no decoder registered for codec %q", s.codec)
+	}
+	if len(digest) < 2 {
+		return nil, fmt.Errorf("capturestore: invalid digest %q", digest)
+	}
+
+	f, err := os.Open(filepath.Join(s.root, "blobs", digest[:2], digest))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := decoder(f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// DecodeResponse unmarshals the response body of record into v.
+func (s *Store) DecodeResponse(record Record, v interface{}) error {
+	body, err := s.Body(record.Response.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,66 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint is the saved position of a change feed subscription or a
+// sync.
+type Checkpoint struct {
+	Name   string   `json:"name"`
+	Cursor string   `json:"cursor"`
+	Seen   []string `json:"seen,omitempty"`
+}
+
+// CheckpointStore persists checkpoints so a restarted process resumes where
+// it stopped. Load returns nil for an unknown name.
+type CheckpointStore interface {
+	Load(ctx context.Context, name string) (*Checkpoint, error)
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// FileCheckpointStore keeps each checkpoint in Dir as <name>.json.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// Load implements CheckpointStore.
+func (f FileCheckpointStore) Load(_ context.Context, name string) (*Checkpoint, error) {
+	data, err := os.ReadFile(f.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", name, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore, replacing the file atomically.
+func (f FileCheckpointStore) Save(_ context.Context, checkpoint *Checkpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp := f.path(checkpoint.Name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(checkpoint.Name))
+}
+
+func (f FileCheckpointStore) path(name string) string {
+	return filepath.Join(f.Dir, filepath.Base(name)+".json")
+}
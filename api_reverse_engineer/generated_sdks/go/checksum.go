@@ -0,0 +1,190 @@
+package example_api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ChecksumError reports a response body that does not match the checksum
+// the server sent with it.
+type ChecksumError struct {
+	Operation string // "GET /v1/files/{id}"
+	Header    string // header carrying the checksum, e.g. "Content-MD5"
+	Algorithm string // "md5", "sha-256", "crc32c", ...
+	Expected  string // base64, as sent
+	Actual    string // base64, computed
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s checksum mismatch in %s: expected %s, got %s", e.Operation, e.Algorithm, e.Header, e.Expected, e.Actual)
+}
+
+// WithoutChecksumValidation stops verifying response checksums for the given
+// operations ("GET /v1/files/{id}"), or for every operation when none are
+// given. Checksums are verified by default whenever a response carries
+// Content-MD5, x-amz-checksum-*, Digest, or Content-Digest headers.
+func WithoutChecksumValidation(operations ...string) ClientOption {
+	return func(c *ExampleapiClient) {
+		if len(operations) == 0 {
+			c.checksums.disabled = true
+			return
+		}
+		if c.checksums.skip == nil {
+			c.checksums.skip = make(map[string]bool)
+		}
+		for _, operation := range operations {
+			c.checksums.skip[operation] = true
+		}
+	}
+}
+
+// checksumPolicy holds which operations skip checksum validation.
+type checksumPolicy struct {
+	disabled bool
+	skip     map[string]bool
+}
+
+// checksumAlgorithms maps algorithm names, as used in Digest headers and
+// x-amz-checksum-* suffixes, to hash constructors.
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5":     md5.New,
+	"sha":     sha1.New,
+	"sha1":    sha1.New,
+	"sha-256": sha256.New,
+	"sha256":  sha256.New,
+	"sha-512": sha512.New,
+	"sha512":  sha512.New,
+	"crc32":   func() hash.Hash { return crc32.NewIEEE() },
+	"crc32c":  func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) },
+}
+
+// verify checks body against every checksum header in resp it understands.
+func (p checksumPolicy) verify(operation string, resp *http.Response, body []byte) error {
+	for _, digest := range p.digests(operation, resp) {
+		digest.hash.Write(body)
+		if err := digest.check(operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseDigest is a checksum a response carries, with the hash of the
+// body to compare it with.
+type responseDigest struct {
+	header    string
+	algorithm string
+	expected  string // base64
+	hash      hash.Hash
+}
+
+// digests returns the checksums in resp's headers whose algorithm is
+// known, with empty hashes. Bodies the transport decompressed have none,
+// as their checksums cover the compressed bytes.
+func (p checksumPolicy) digests(operation string, resp *http.Response) []*responseDigest {
+	if p.disabled || p.skip[operation] || resp.Uncompressed {
+		return nil
+	}
+	var digests []*responseDigest
+	add := func(header, algorithm, expected string) {
+		if newHash, ok := checksumAlgorithms[algorithm]; ok {
+			digests = append(digests, &responseDigest{header: header, algorithm: algorithm, expected: expected, hash: newHash()})
+		}
+	}
+	for header, values := range resp.Header {
+		name := strings.ToLower(header)
+		for _, value := range values {
+			switch {
+			case name == "content-md5":
+				add(header, "md5", value)
+			case strings.HasPrefix(name, "x-amz-checksum-"):
+				add(header, strings.TrimPrefix(name, "x-amz-checksum-"), value)
+			case name == "digest" || name == "content-digest":
+				// "sha-256=abc=, md5=def=" (RFC 3230) or "sha-256=:abc=:" (RFC 9530).
+				for _, part := range strings.Split(value, ",") {
+					if algorithm, digest, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+						add(header, strings.ToLower(algorithm), strings.Trim(digest, ":"))
+					}
+				}
+			}
+		}
+	}
+	return digests
+}
+
+// check compares the hash of the body written so far with the expected
+// digest.
+func (d *responseDigest) check(operation string) error {
+	sum := d.hash.Sum(nil)
+	if want, err := base64.StdEncoding.DecodeString(d.expected); err == nil && bytes.Equal(want, sum) {
+		return nil
+	}
+	return &ChecksumError{Operation: operation, Header: d.header, Algorithm: d.algorithm, Expected: d.expected, Actual: base64.StdEncoding.EncodeToString(sum)}
+}
+
+// verifiedBody applies the checks send makes on a buffered body to the
+// body of a download or stream, which is read as it arrives: checksums are
+// computed while it is read, and a signed body is kept until its end to
+// verify the signature. A failed check is returned by the Read reaching
+// the end of the body, in place of io.EOF, so what was read before is not
+// to be trusted until then.
+func (c *ExampleapiClient) verifiedBody(operation string, resp *http.Response) io.ReadCloser {
+	digests := c.checksums.digests(operation, resp)
+	if len(digests) == 0 && c.signatures == nil {
+		return resp.Body
+	}
+	return &verifyingReader{ReadCloser: resp.Body, operation: operation, resp: resp, digests: digests, signatures: c.signatures}
+}
+
+// verifyingReader checks a response body once it has been read to the end.
+type verifyingReader struct {
+	io.ReadCloser
+	operation  string
+	resp       *http.Response
+	digests    []*responseDigest
+	signatures *SignatureVerifier
+	signed     bytes.Buffer // the body, for the signature
+	err        error        // the result at the end of the body
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.ReadCloser.Read(p)
+	for _, digest := range r.digests {
+		digest.hash.Write(p[:n])
+	}
+	if r.signatures != nil {
+		r.signed.Write(p[:n])
+	}
+	if err == io.EOF {
+		r.err = r.verify()
+		if r.err == nil {
+			r.err = io.EOF
+		}
+		return n, r.err
+	}
+	return n, err
+}
+
+func (r *verifyingReader) verify() error {
+	for _, digest := range r.digests {
+		if err := digest.check(r.operation); err != nil {
+			return err
+		}
+	}
+	err := r.signatures.verify(r.operation, r.resp, r.signed.Bytes())
+	r.signed = bytes.Buffer{}
+	return err
+}
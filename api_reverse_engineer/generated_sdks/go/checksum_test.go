@@ -0,0 +1,90 @@
+package example_api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestVerifiedBody(t *testing.T) {
+	const body = `{"id":1}`
+	sum := sha256.Sum256([]byte(body))
+	good := base64.StdEncoding.EncodeToString(sum[:])
+	bad := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+	tests := []struct {
+		name    string
+		header  string
+		opts    []ClientOption
+		wantErr bool
+	}{
+		{"matching digest", "sha-256=:" + good + ":", nil, false},
+		{"mismatched digest", "sha-256=:" + bad + ":", nil, true},
+		{"unknown algorithm", "unixsum=30637", nil, false},
+		{"validation off", "sha-256=:" + bad + ":", []ClientOption{WithoutChecksumValidation("GET /v1/users/{id}")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewExampleapiClient("", tt.opts...)
+			resp := &http.Response{Header: http.Header{"Content-Digest": {tt.header}}, Body: io.NopCloser(strings.NewReader(body))}
+			got, err := io.ReadAll(c.verifiedBody("GET /v1/users/{id}", resp))
+			if string(got) != body {
+				t.Errorf("read %q, want %q", got, body)
+			}
+			var checksumErr *ChecksumError
+			if tt.wantErr != errors.As(err, &checksumErr) {
+				t.Errorf("err = %v, want ChecksumError: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifiedBodySignature(t *testing.T) {
+	const body = `{"id":1}`
+	key := []byte("secret")
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("1." + body))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	c := NewExampleapiClient("", WithResponseSignatures(SignatureVerifier{
+		Keys:            StaticSignatureKey(key),
+		Header:          "X-Signature",
+		TimestampHeader: "X-Timestamp",
+		MaxSkew:         -1,
+	}))
+	for _, tt := range []struct {
+		name, body string
+		wantErr    bool
+	}{
+		{"signed", body, false},
+		{"tampered", `{"id":2}`, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{"X-Signature": {signature}, "X-Timestamp": {"1"}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}
+			_, err := io.ReadAll(c.verifiedBody("GET /v1/users/{id}", resp))
+			var signatureErr *SignatureError
+			if tt.wantErr != errors.As(err, &signatureErr) {
+				t.Errorf("err = %v, want SignatureError: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifiedBodyWithoutKeys(t *testing.T) {
+	c := NewExampleapiClient("", WithResponseSignatures(SignatureVerifier{Header: "X-Signature", TimestampHeader: "X-Timestamp", MaxSkew: -1}))
+	resp := &http.Response{
+		Header: http.Header{"X-Signature": {"c2lnbmF0dXJl"}, "X-Timestamp": {"1"}},
+		Body:   io.NopCloser(strings.NewReader(`{}`)),
+	}
+	_, err := io.ReadAll(c.verifiedBody("GET /v1/users/{id}", resp))
+	var signatureErr *SignatureError
+	if !errors.As(err, &signatureErr) {
+		t.Errorf("err = %v, want SignatureError", err)
+	}
+}
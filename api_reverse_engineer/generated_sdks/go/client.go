@@ -2,251 +2,723 @@ package example_api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"runtime/pprof"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 // Type Definitions
+type ListPostsResponse struct {
+    Posts []PostItem `json:"posts"`
+    Total int `json:"total"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
+type CreatePostRequest struct {
+    Title string `json:"title"`
+    Content string `json:"content"`
+    Tags []string `json:"tags"`
+    Status PostStatus `json:"status"`
+}
+
+type CreatePostResponse struct {
+    Id string `json:"id"`
+    Title string `json:"title"`
+    Content string `json:"content"`
+    AuthorId int `json:"author_id"`
+    Tags []string `json:"tags"`
+    Status PostStatus `json:"status"`
+    CreatedAt Time `json:"created_at"`
+    Views int `json:"views"`
+    Likes int `json:"likes"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
 type ListUsersResponse struct {
-    Users []map[string]interface{} `json:"users"`
+    Users []UserItem `json:"users"`
     Total int `json:"total"`
     Page int `json:"page"`
     Limit int `json:"limit"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
 }
 
-type ListUsersResponse struct {
-    Id int `json:"id"`
-    Name string `json:"name"`
-    Email string `json:"email"`
-    CreatedAt string `json:"created_at"`
-    IsActive bool `json:"is_active"`
-    Profile map[string]interface{} `json:"profile"`
+// ListUsersParams holds the query parameters of ListUsers; zero
+// values are left out.
+type ListUsersParams struct {
+	Page  int `url:"page"`
+	Limit int `url:"limit"`
+
+	// Extra holds parameters sent as given, such as zero values or filters
+	// not seen in the captured traffic.
+	Extra url.Values
+}
+
+func (p *ListUsersParams) values() url.Values {
+	if p == nil {
+		return nil
+	}
+	query := url.Values{}
+	for name, values := range p.Extra {
+		query[name] = append([]string(nil), values...)
+	}
+	if p.Page != 0 {
+		query.Set("page", fmt.Sprint(p.Page))
+	}
+	if p.Limit != 0 {
+		query.Set("limit", fmt.Sprint(p.Limit))
+	}
+	return query
 }
 
 type CreateUserRequest struct {
     Name string `json:"name"`
     Email string `json:"email"`
     Password string `json:"password"`
-    Profile map[string]interface{} `json:"profile"`
+    Profile UserProfile `json:"profile"`
 }
 
-type UpdateUserRequest struct {
+type CreateUserResponse struct {
+    Id int `json:"id"`
     Name string `json:"name"`
     Email string `json:"email"`
+    CreatedAt Time `json:"created_at"`
     IsActive bool `json:"is_active"`
+    Profile UserProfile `json:"profile"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
 }
 
-type UpdateUserResponse struct {
+type GetUserResponse struct {
     Id int `json:"id"`
     Name string `json:"name"`
     Email string `json:"email"`
-    CreatedAt string `json:"created_at"`
-    UpdatedAt string `json:"updated_at"`
+    CreatedAt Time `json:"created_at"`
     IsActive bool `json:"is_active"`
+    Profile UserProfile2 `json:"profile"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
 }
 
-type ListPostsResponse struct {
-    Posts []map[string]interface{} `json:"posts"`
-    Total int `json:"total"`
+type UpdateUserRequest struct {
+    Name *string `json:"name,omitempty"`
+    Email *string `json:"email,omitempty"`
+    IsActive *bool `json:"is_active,omitempty"`
 }
 
-type CreatePostRequest struct {
+type UpdateUserResponse struct {
+    Id int `json:"id"`
+    Name string `json:"name"`
+    Email string `json:"email"`
+    CreatedAt Time `json:"created_at"`
+    UpdatedAt Time `json:"updated_at"`
+    IsActive bool `json:"is_active"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
+type PostItem struct {
+    Id string `json:"id"`
     Title string `json:"title"`
     Content string `json:"content"`
+    AuthorId int `json:"author_id"`
     Tags []string `json:"tags"`
-    Status string `json:"status"`
+    PublishedAt Time `json:"published_at"`
+    Views int `json:"views"`
+    Likes int `json:"likes"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
+type UserItem struct {
+    Id int `json:"id"`
+    Name string `json:"name"`
+    Email string `json:"email"`
+    CreatedAt Time `json:"created_at"`
+    IsActive bool `json:"is_active"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
+type UserProfile struct {
+    Bio string `json:"bio"`
+    Location string `json:"location"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
+}
+
+type UserProfile2 struct {
+    Bio string `json:"bio"`
+    Location string `json:"location"`
+    AvatarUrl string `json:"avatar_url"`
+
+    // Extra holds the fields the response had but this type lacks, kept
+    // when the client decodes with DecodeTolerant.
+    Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Client Definition
 type ExampleapiClient struct {
 	BaseURL    string
 	HTTPClient *http.Client
-	Headers    map[string]string
+	// Headers are sent with every request. Change them with SetHeader and
+	// SetAuthToken, which are safe while calls are in flight, or derive a
+	// client with WithHeader; writing to the map directly is not.
+	Headers map[string]string
+
+	// V1 groups the API methods by version and resource, as V1.Posts.List,
+	// sharing the client's configuration.
+	V1 *V1Service
+
+	headerMu    *sync.RWMutex
+	debug       *debugLogger
+	logger      callLogger
+	schema      *schemaTelemetry
+	stats       *clientStats
+	timeLayouts *TimeLayoutRegistry
+	limiter     *rateLimiter
+	credentials []*secretCredential
+	limits      sizeLimits
+	lifecycle   *lifecycle
+	checksums   checksumPolicy
+	codecs      map[string]PayloadCodec
+	serializers map[string]Serializer
+	codec       Codec
+	decodeMode  DecodeMode
+	signatures  *SignatureVerifier
+	signer      Signer
+	auditSink   AuditSink
+	quota       *quotaTracker
+	quotaWait   bool
+	endpoints   *EndpointRegistry
+	versionHook VersionHook
+	retry       *RetryPolicy
+	cache       ResponseCache
+	breaker     *circuitBreaker
+	middleware  []Middleware
+	defaults    []RequestOption
+
+	acceptEncoding    string
+	compressMinSize   int
+	errorMessages     *ErrorMessages
+	instrumentation   Instrumentation
+	idempotencyHeader string
+	queryCredentials  map[string]string
+	skipValidation    bool
 }
 
+// ClientOption configures a client created with NewExampleapiClient.
+type ClientOption func(*ExampleapiClient)
+
 // NewExampleapiClient creates a new API client
-func NewExampleapiClient(baseURL string) *ExampleapiClient {
+func NewExampleapiClient(baseURL string, opts ...ClientOption) *ExampleapiClient {
 	if baseURL == "" {
-		baseURL = "https://api.example.com"
+		baseURL = defaultBaseURL
 	}
-	return &ExampleapiClient{
+	c := &ExampleapiClient{
 		BaseURL:    strings.TrimSuffix(baseURL, "/"),
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		HTTPClient: &http.Client{Transport: newTransport(), CheckRedirect: RedirectPolicy{}.check},
 		Headers:    make(map[string]string),
+		headerMu:   &sync.RWMutex{},
+		schema:     &schemaTelemetry{},
+		stats:      &clientStats{},
+		lifecycle:  &lifecycle{},
+		quota:      &quotaTracker{},
+		endpoints:  &EndpointRegistry{},
+
+		acceptEncoding:    DefaultAcceptEncoding,
+		compressMinSize:   DefaultCompressMinSize,
+		idempotencyHeader: capturedIdempotencyHeader,
+	}
+	c.bindVersions()
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// SetAuthToken sets the authorization token
+// SetAuthToken sets the authorization token. It is safe to call while
+// other goroutines make calls, which send the old token or the new one.
 func (c *ExampleapiClient) SetAuthToken(token string) {
-	c.Headers["Authorization"] = fmt.Sprintf("Bearer %s", token)
+	c.SetHeader("Authorization", fmt.Sprintf("Bearer %s", token))
 }
 
-// SetHeader sets a custom header
+// SetHeader sets a custom header. It is safe to call while other
+// goroutines make calls.
 func (c *ExampleapiClient) SetHeader(key, value string) {
+	c.headerMu.Lock()
+	defer c.headerMu.Unlock()
 	c.Headers[key] = value
 }
 
-// doRequest performs the HTTP request
-func (c *ExampleapiClient) doRequest(method, path string, params url.Values, body interface{}) ([]byte, error) {
-	fullURL := c.BaseURL + path
+// WithHeader returns a client that also sends the header, leaving c as it
+// is; it shares everything else with c, as derived clients do. Use it to
+// give part of a program its own headers without affecting calls in flight.
+func (c *ExampleapiClient) WithHeader(key, value string) *ExampleapiClient {
+	derived := c.derive()
+	derived.Headers[key] = value
+	return derived
+}
+
+// WithAuthToken returns a client that authorizes with token, leaving c as
+// it is, e.g. to act for one user of a multi-user service.
+func (c *ExampleapiClient) WithAuthToken(token string) *ExampleapiClient {
+	return c.WithHeader("Authorization", fmt.Sprintf("Bearer %s", token))
+}
+
+// derive returns a copy of c that shares its HTTP client, telemetry, stats,
+// lifecycle, quota, and endpoint registry, and applies defaults to every
+// call before any per-call options. Its headers are its own.
+func (c *ExampleapiClient) derive(defaults ...RequestOption) *ExampleapiClient {
+	c.headerMu.RLock()
+	derived := *c // queryCredentials is replaced under the lock
+	c.headerMu.RUnlock()
+	derived.Headers = c.headers()
+	derived.headerMu = &sync.RWMutex{}
+	derived.defaults = append(append([]RequestOption(nil), c.defaults...), defaults...)
+	derived.bindVersions()
+	return &derived
+}
+
+// headers returns a copy of the client's headers.
+func (c *ExampleapiClient) headers() map[string]string {
+	c.headerMu.RLock()
+	defer c.headerMu.RUnlock()
+	headers := make(map[string]string, len(c.Headers))
+	for key, value := range c.Headers {
+		headers[key] = value
+	}
+	return headers
+}
+
+// doRequest performs the HTTP request with ctx, labelled with the endpoint
+// and method for CPU and goroutine profiles.
+func (c *ExampleapiClient) doRequest(ctx context.Context, method, endpoint, path string, params url.Values, body interface{}, opts ...RequestOption) (responseBody []byte, err error) {
+	if err := c.lifecycle.enter(); err != nil {
+		return nil, err
+	}
+	defer c.lifecycle.exit()
+	options := newRequestOptions(ctx, c.defaults, opts)
+	cancel := options.startTimeout()
+	defer cancel()
+	c.assignIdempotencyKey(method, options)
+	defer c.instrument(method, endpoint, options)(&err)
+	defer c.audit(method, endpoint, path, options, time.Now(), &err)
+	defer options.meta.finish(time.Now())
+	if err := c.checkEndpoint(method + " " + endpoint); err != nil {
+		return nil, err
+	}
+	defer recoverPanic(method+" "+endpoint, 1, &err)
+	baseURL := c.baseURL(method, endpoint, options)
+	pprof.Do(options.ctx, profileLabels(method, endpoint), func(context.Context) {
+		responseBody, err = c.sendRetrying(method, endpoint, baseURL, path, params, body, options)
+	})
+	if err == nil && options.envelope != nil {
+		err = c.decodeEnvelopeMeta(method+" "+endpoint, responseBody, options.envelope)
+	}
+	return responseBody, err
+}
+
+// profileLabels are the pprof labels attached to every API call.
+func profileLabels(method, endpoint string) pprof.LabelSet {
+	return pprof.Labels("endpoint", endpoint, "method", method)
+}
+
+// send performs the HTTP request
+func (c *ExampleapiClient) send(method, endpoint, baseURL, path string, params url.Values, body interface{}, options *requestOptions) ([]byte, error) {
+	operation := method + " " + endpoint
+	codec := c.codecs[operation]
+	req, jsonBody, err := c.newRequest(method, endpoint, baseURL, path, params, body, options)
+	if err != nil {
+		return nil, err
+	}
+	cached := c.revalidate(req)
+	if options.dryRun {
+		return nil, c.dryRun(operation, req, jsonBody)
+	}
+	resp, err := c.roundTrip(operation, req, options)
+	if err != nil {
+		c.debug.log(req, jsonBody, nil, nil, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp = c.cachedResponse(req, resp, cached)
+	}
+	
+	wire, err := c.limits.readResponse(resp)
+	if err != nil {
+		c.debug.log(req, jsonBody, resp, nil, err)
+		return nil, err
+	}
+	c.storeResponse(req, resp, wire)
+	responseBody, err := inflate(resp, wire, c.limits.response)
+	c.debug.log(req, jsonBody, resp, responseBody, err)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checksums.verify(operation, resp, wire); err != nil {
+		return nil, err
+	}
+	if err := c.signatures.verify(operation, resp, responseBody); err != nil {
+		return nil, err
+	}
+	if codec != nil {
+		if responseBody, err = codec.DecodeResponse(operation, resp.Header.Get("Content-Type"), responseBody); err != nil {
+			return nil, err
+		}
+	}
+	
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(operation, resp.StatusCode, resp.Header, responseBody, c.errorMessages, req.Header.Get("Accept-Language"))
+	}
+	if err := redirectError(resp); err != nil {
+		return nil, err
+	}
+	
+	return responseBody, nil
+}
+
+// openResponse makes a call without reading the response body, for streams
+// and downloads. The caller closes the body and then calls release. Error
+// statuses are read and returned as an *APIError.
+func (c *ExampleapiClient) openResponse(ctx context.Context, method, endpoint, path string, params url.Values, body interface{}, opts []RequestOption) (resp *http.Response, release func(), err error) {
+	if err := c.lifecycle.enter(); err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if resp == nil {
+			c.lifecycle.exit()
+		}
+	}()
+	operation := method + " " + endpoint
+	options := newRequestOptions(ctx, c.defaults, opts)
+	cancel := options.startTimeout()
+	defer func() {
+		if resp == nil {
+			cancel()
+		}
+	}()
+	c.assignIdempotencyKey(method, options)
+	defer c.instrument(method, endpoint, options)(&err)
+	defer c.audit(method, endpoint, path, options, time.Now(), &err)
+	if err := c.checkEndpoint(operation); err != nil {
+		return nil, nil, err
+	}
+	defer recoverPanic(operation, 1, &err)
+	req, jsonBody, err := c.newRequest(method, endpoint, c.baseURL(method, endpoint, options), path, params, body, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if options.dryRun {
+		return nil, nil, c.dryRun(operation, req, jsonBody)
+	}
+	resp, err = c.roundTrip(operation, req, options)
+	if err != nil {
+		c.debug.log(req, jsonBody, nil, nil, err)
+		return nil, nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		responseBody, err := c.limits.readResponse(resp)
+		c.debug.log(req, jsonBody, resp, responseBody, err)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, newAPIError(operation, resp.StatusCode, resp.Header, responseBody, c.errorMessages, req.Header.Get("Accept-Language"))
+	}
+	if err := redirectError(resp); err != nil {
+		resp.Body.Close()
+		c.debug.log(req, jsonBody, resp, nil, err)
+		return nil, nil, err
+	}
+	c.debug.log(req, jsonBody, resp, nil, nil)
+	resp.Body = c.verifiedBody(operation, resp)
+	return resp, func() {
+		cancel()
+		c.lifecycle.exit()
+	}, nil
+}
+
+// newRequest builds the HTTP request of a call, with its body encoded and
+// the client's headers and credentials set, and returns the encoded body.
+func (c *ExampleapiClient) newRequest(method, endpoint, baseURL, path string, params url.Values, body interface{}, options *requestOptions) (*http.Request, []byte, error) {
+	operation := method + " " + endpoint
+	codec := c.codecs[operation]
+	upload, _ := body.(*uploadBody)
+	if upload != nil {
+		body = nil
+	}
+	fullURL := baseURL + path
+	params = options.queryParams(params)
 	if params != nil && len(params) > 0 {
 		fullURL = fullURL + "?" + params.Encode()
 	}
 	
 	var bodyReader io.Reader
+	var jsonBody, wireBody []byte
+	var contentEncoding string
+	contentType := "application/json"
+	if mediaType, ok := requestContentTypes[operation]; ok {
+		contentType = mediaType
+	}
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		body = c.withTimeLayouts(body)
+		serializer, err := c.serializer(contentType)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
+		jsonBody, err = serializer.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if codec != nil {
+			if jsonBody, contentType, err = codec.EncodeRequest(operation, jsonBody); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := c.limits.checkRequest(int64(len(jsonBody))); err != nil {
+			return nil, nil, err
+		}
+		if wireBody, contentEncoding, err = c.compressRequest(operation, jsonBody); err != nil {
+			return nil, nil, err
+		}
+		bodyReader = bytes.NewBuffer(wireBody)
 	}
 	
-	req, err := http.NewRequest(method, fullURL, bodyReader)
+	req, err := http.NewRequestWithContext(options.ctx, method, fullURL, bodyReader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
+	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if c.acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", c.acceptEncoding)
 	}
 	
+	c.headerMu.RLock()
 	for key, value := range c.Headers {
 		req.Header.Set(key, value)
 	}
-	
-	resp, err := c.HTTPClient.Do(req)
+	c.headerMu.RUnlock()
+	c.applyQueryCredentials(req)
+	if err := c.applyCredentials(req); err != nil {
+		return nil, nil, err
+	}
+	c.setIdempotencyKey(req, options)
+	options.apply(req)
+	if err := checkPath(endpoint, req.URL.EscapedPath()); err != nil {
+		return nil, nil, err
+	}
+	if upload != nil {
+		if err := upload.attach(req); err != nil {
+			return nil, nil, err
+		}
+		if err := c.limits.checkRequest(req.ContentLength); err != nil {
+			req.Body.Close()
+			return nil, nil, err
+		}
+	}
+	if err := c.sign(req, wireBody); err != nil {
+		if upload != nil {
+			req.Body.Close()
+		}
+		return nil, nil, err
+	}
+	return req, jsonBody, nil
+}
+
+// roundTrip sends req once the client's rate limits and circuit breaker
+// allow, and records the response's status, stats, and quota.
+func (c *ExampleapiClient) roundTrip(operation string, req *http.Request, options *requestOptions) (*http.Response, error) {
+	if err := c.limiter.wait(options.ctx); err != nil {
+		return nil, err
+	}
+	if c.quotaWait {
+		if err := c.WaitForQuota(options.ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+	report, err := c.breaker.allow(c.circuitKey(operation, req))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	
-	responseBody, err := io.ReadAll(resp.Body)
+	options.url = req.URL
+	request := c.stats.start(operation, req)
+	resp, err := c.doer(req).Do(req)
+	report(options.ctx, resp, err)
+	if c.logger != nil {
+		c.logger.logAttempt(options.ctx, operation, options.attempt, req, resp, time.Since(request.started), err)
+	}
 	if err != nil {
+		request.finish(nil)
 		return nil, err
 	}
-	
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+	request.finish(resp)
+	options.status = resp.StatusCode
+	if options.statusOut != nil {
+		*options.statusOut = resp.StatusCode
 	}
-	
-	return responseBody, nil
+	options.meta.record(operation, resp)
+	c.quota.update(resp)
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.expireCredentials(req)
+	}
+	return resp, nil
 }
 
-// ListUsers performs GET /v1/users
-func (c *ExampleapiClient) ListUsers() (*ListUsersResponse, error) {
-	path := "/v1/users"
+// ListPosts performs GET /v1/posts
+func (c *ExampleapiClient) ListPosts(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error) {
+	path := "/v1/posts"
 	
-	responseBody, err := c.doRequest("GET", path, nil, nil)
+	responseBody, err := c.doRequest(ctx, "GET", "/v1/posts", path, nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result ListUsersResponse
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result ListPostsResponse
+	if err := c.decode("GET /v1/posts", responseBody, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// ListUsers performs GET /v1/users/{id}
-func (c *ExampleapiClient) ListUsers(id string) (*ListUsersResponse, error) {
-	path := `/v1/users/{id}`
-	path = strings.Replace(path, "{id}", id, 1)
+// CreatePost performs POST /v1/posts
+func (c *ExampleapiClient) CreatePost(ctx context.Context, data *CreatePostRequest, opts ...RequestOption) (*CreatePostResponse, error) {
+	path := "/v1/posts"
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
 	
-	responseBody, err := c.doRequest("GET", path, nil, nil)
+	responseBody, err := c.doRequest(ctx, "POST", "/v1/posts", path, nil, data, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result ListUsersResponse
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result CreatePostResponse
+	if err := c.decode("POST /v1/posts", responseBody, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// CreateUser performs POST /v1/users
-func (c *ExampleapiClient) CreateUser(data *CreateUserRequest) (map[string]interface{}, error) {
+// ListUsers performs GET /v1/users
+func (c *ExampleapiClient) ListUsers(ctx context.Context, params *ListUsersParams, opts ...RequestOption) (*ListUsersResponse, error) {
 	path := "/v1/users"
+	query := params.values()
 	
-	responseBody, err := c.doRequest("POST", path, nil, data)
+	responseBody, err := c.doRequest(ctx, "GET", "/v1/users", path, query, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result map[string]interface{}
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result ListUsersResponse
+	if err := c.decode("GET /v1/users", responseBody, &result); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &result, nil
 }
 
-// UpdateUser performs PUT /v1/users/{id}
-func (c *ExampleapiClient) UpdateUser(id string, data *UpdateUserRequest) (*UpdateUserResponse, error) {
-	path := `/v1/users/{id}`
-	path = strings.Replace(path, "{id}", id, 1)
+// CreateUser performs POST /v1/users
+func (c *ExampleapiClient) CreateUser(ctx context.Context, data *CreateUserRequest, opts ...RequestOption) (*CreateUserResponse, error) {
+	path := "/v1/users"
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
 	
-	responseBody, err := c.doRequest("PUT", path, nil, data)
+	responseBody, err := c.doRequest(ctx, "POST", "/v1/users", path, nil, data, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result UpdateUserResponse
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result CreateUserResponse
+	if err := c.decode("POST /v1/users", responseBody, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// DeleteUser performs DELETE /v1/users/{id}
-func (c *ExampleapiClient) DeleteUser(id string) (map[string]interface{}, error) {
+// GetUser performs GET /v1/users/{id}
+func (c *ExampleapiClient) GetUser(ctx context.Context, id int64, opts ...RequestOption) (*GetUserResponse, error) {
 	path := `/v1/users/{id}`
-	path = strings.Replace(path, "{id}", id, 1)
+	path = strings.Replace(path, "{id}", strconv.FormatInt(id, 10), 1)
 	
-	responseBody, err := c.doRequest("DELETE", path, nil, nil)
+	responseBody, err := c.doRequest(ctx, "GET", "/v1/users/{id}", path, nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result map[string]interface{}
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result GetUserResponse
+	if err := c.decode("GET /v1/users/{id}", responseBody, &result); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &result, nil
 }
 
-// ListPosts performs GET /v1/posts
-func (c *ExampleapiClient) ListPosts() (*ListPostsResponse, error) {
-	path := "/v1/posts"
+// UpdateUser performs PUT /v1/users/{id}
+func (c *ExampleapiClient) UpdateUser(ctx context.Context, id int64, data *UpdateUserRequest, opts ...RequestOption) (*UpdateUserResponse, error) {
+	path := `/v1/users/{id}`
+	path = strings.Replace(path, "{id}", strconv.FormatInt(id, 10), 1)
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
 	
-	responseBody, err := c.doRequest("GET", path, nil, nil)
+	responseBody, err := c.doRequest(ctx, "PUT", "/v1/users/{id}", path, nil, data, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result ListPostsResponse
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result UpdateUserResponse
+	if err := c.decode("PUT /v1/users/{id}", responseBody, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// CreatePost performs POST /v1/posts
-func (c *ExampleapiClient) CreatePost(data *CreatePostRequest) (map[string]interface{}, error) {
-	path := "/v1/posts"
+// DeleteUser performs DELETE /v1/users/{id}
+func (c *ExampleapiClient) DeleteUser(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error) {
+	path := `/v1/users/{id}`
+	path = strings.Replace(path, "{id}", strconv.FormatInt(id, 10), 1)
 	
-	responseBody, err := c.doRequest("POST", path, nil, data)
+	responseBody, err := c.doRequest(ctx, "DELETE", "/v1/users/{id}", path, nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 	
-	var result map[string]interface{}
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	var result json.RawMessage
+	if err := c.decode("DELETE /v1/users/{id}", responseBody, &result); err != nil {
 		return nil, err
 	}
 	return result, nil
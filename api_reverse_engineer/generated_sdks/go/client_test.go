@@ -0,0 +1,63 @@
+package example_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentHeaders changes the client's headers and credentials while
+// other goroutines make calls and derive clients; run it with -race.
+func TestConcurrentHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	c := NewExampleapiClient(server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			c.SetAuthToken(fmt.Sprint("token-", i))
+			c.SetHeader("X-Request-Tag", fmt.Sprint(i))
+			c.setCookie("session", fmt.Sprint(i))
+			c.setQueryCredential("api_key", fmt.Sprint(i))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			derived := c.WithHeader("X-Worker", fmt.Sprint(i))
+			for _, client := range []*ExampleapiClient{c, derived} {
+				if _, err := client.doRequest(context.Background(), "GET", "/ping", "/ping", nil, nil); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := c.headers()["X-Worker"]; got != "" {
+		t.Errorf("WithHeader changed the parent client: X-Worker = %q", got)
+	}
+}
+
+func TestWithHeaderLeavesParent(t *testing.T) {
+	c := NewExampleapiClient("")
+	c.SetHeader("X-Tag", "parent")
+	derived := c.WithHeader("X-Tag", "derived")
+	c.SetHeader("X-Other", "parent")
+
+	if got := c.headers()["X-Tag"]; got != "parent" {
+		t.Errorf("parent X-Tag = %q, want parent", got)
+	}
+	if got := derived.headers()["X-Tag"]; got != "derived" {
+		t.Errorf("derived X-Tag = %q, want derived", got)
+	}
+	if got, ok := derived.headers()["X-Other"]; ok {
+		t.Errorf("derived client saw a later parent header: X-Other = %q", got)
+	}
+}
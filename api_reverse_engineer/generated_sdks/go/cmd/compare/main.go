@@ -0,0 +1,278 @@
+// Command compare makes the same read-only calls against two deployments of
+// the Exampleapi API, such as production and staging or the original and a
+// reimplementation, and reports where their responses differ. Each side is a
+// base URL, using the credentials from the environment, or the name of a
+// profile:
+//
+//	go run ./cmd/compare -a prod -b https://staging.example.com -arg id=42
+//
+// Identifiers and timestamps differ between deployments by design, so
+// their values are masked before comparing unless -strict is given; their
+// presence and type still count. It exits with status 1 if any response
+// differs.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/example/example_api"
+	"github.com/example/example_api/jsondiff"
+)
+
+// operation is a read-only call compare can make.
+type operation struct {
+	params []string
+	call   func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error
+}
+
+// operations are the captured GET operations.
+var operations = map[string]operation{
+	"GET /v1/posts": {nil, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		_, err := c.ListPosts(ctx)
+		return err
+	}},
+	"GET /v1/users": {nil, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		_, err := c.ListUsers(ctx, nil)
+		return err
+	}},
+	"GET /v1/users/{id}": {[]string{"id"}, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		id, err := strconv.ParseInt(args["id"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("-arg id: %w", err)
+		}
+		_, err = c.GetUser(ctx, id)
+		return err
+	}},
+}
+
+type listFlags []string
+
+func (l *listFlags) String() string { return strings.Join(*l, ", ") }
+
+func (l *listFlags) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type argFlags map[string]string
+
+func (a argFlags) String() string { return fmt.Sprint(map[string]string(a)) }
+
+func (a argFlags) Set(value string) error {
+	name, arg, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("want name=value, got %q", value)
+	}
+	a[name] = arg
+	return nil
+}
+
+func main() {
+	args := argFlags{}
+	var endpoints, ignore, unordered listFlags
+	a := flag.String("a", "", "first deployment: a base URL, or a profile name")
+	b := flag.String("b", "", "second deployment: a base URL, or a profile name")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout for each call")
+	strict := flag.Bool("strict", false, "compare identifier and timestamp values too")
+	list := flag.Bool("list", false, "list the operations -endpoint accepts and exit")
+	flag.Var(&endpoints, "endpoint", "operation to compare, such as \"GET /v1/users\"; repeatable (default: every operation whose path parameters are given)")
+	flag.Var(args, "arg", "path parameter as name=value; repeatable")
+	flag.Var(&ignore, "ignore", "field name or path (such as $.meta.total) to leave out; repeatable")
+	flag.Var(&unordered, "unordered", "path of an array (such as $.users[*].roles) to compare regardless of order, or * for all; repeatable")
+	flag.Parse()
+
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if *list {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+	if *a == "" || *b == "" {
+		fmt.Fprintln(os.Stderr, "compare: -a and -b are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if len(endpoints) == 0 {
+		for _, name := range names {
+			if hasArgs(operations[name].params, args) {
+				endpoints = append(endpoints, name)
+			}
+		}
+	}
+
+	left, err := newSide(*a)
+	if err != nil {
+		fatal(err)
+	}
+	right, err := newSide(*b)
+	if err != nil {
+		fatal(err)
+	}
+	opts := jsondiff.Options{
+		Ignore:           ignore,
+		IgnoreTimestamps: !*strict,
+		IgnoreIDs:        !*strict,
+		Unordered:        unordered,
+	}
+
+	fmt.Printf("comparing %s (a) with %s (b)\n", left.client.BaseURL, right.client.BaseURL)
+	differing := 0
+	for _, name := range endpoints {
+		op, ok := operations[name]
+		if !ok {
+			fatal(fmt.Errorf("unknown operation %q; run with -list", name))
+		}
+		if !hasArgs(op.params, args) {
+			fatal(fmt.Errorf("%s needs -arg for %s", name, strings.Join(op.params, ", ")))
+		}
+		resultA := left.call(op, args, *timeout)
+		resultB := right.call(op, args, *timeout)
+		diffs := compare(resultA, resultB, opts)
+		if len(diffs) == 0 {
+			fmt.Printf("\nsame  %s\n", name)
+			continue
+		}
+		differing++
+		fmt.Printf("\ndiff  %s\n", name)
+		for _, diff := range diffs {
+			fmt.Printf("      %s\n", diff)
+		}
+	}
+	fmt.Printf("\n%d of %d operations differ\n", differing, len(endpoints))
+	if differing > 0 {
+		os.Exit(1)
+	}
+}
+
+func hasArgs(params []string, args map[string]string) bool {
+	for _, name := range params {
+		if _, ok := args[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "compare:", err)
+	os.Exit(2)
+}
+
+// side is one deployment and the raw response of its last call.
+type side struct {
+	client   *api.ExampleapiClient
+	recorder *recorder
+}
+
+// newSide creates a client for target, a base URL or a profile name. Its
+// transport records raw response bodies, so fields the SDK types do not
+// know are compared too.
+func newSide(target string) (*side, error) {
+	// Leave decompression to the transport, so the recorder sees plain bodies.
+	opts := []api.ClientOption{api.WithAcceptEncoding("")}
+	var client *api.ExampleapiClient
+	var err error
+	if strings.Contains(target, "://") {
+		baseURL := strings.TrimSuffix(target, "/")
+		client, err = api.NewClientFromEnv(append(opts, func(c *api.ExampleapiClient) { c.BaseURL = baseURL })...)
+	} else {
+		client, err = api.NewClientFromProfile(target, opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	httpClient := *client.HTTPClient
+	rec := &recorder{next: httpClient.Transport}
+	if rec.next == nil {
+		rec.next = http.DefaultTransport
+	}
+	httpClient.Transport = rec
+	client.HTTPClient = &httpClient
+	return &side{client: client, recorder: rec}, nil
+}
+
+// result is what one call returned.
+type result struct {
+	status int
+	body   []byte
+	err    error
+}
+
+func (s *side) call(op operation, args map[string]string, timeout time.Duration) result {
+	s.recorder.status, s.recorder.body = 0, nil
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := op.call(ctx, s.client, args)
+	if s.recorder.status != 0 {
+		// An error status is a response to compare, not a failure to call.
+		err = nil
+	}
+	return result{s.recorder.status, s.recorder.body, err}
+}
+
+// recorder is an http.RoundTripper keeping the last response's status and
+// body.
+type recorder struct {
+	next   http.RoundTripper
+	status int
+	body   []byte
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.status, r.body = resp.StatusCode, body
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// compare describes the differences between two results.
+func compare(a, b result, opts jsondiff.Options) []string {
+	switch {
+	case a.err != nil || b.err != nil:
+		return []string{fmt.Sprintf("! call failed: a: %v, b: %v", a.err, b.err)}
+	case a.status != b.status:
+		return []string{fmt.Sprintf("! status %d vs %d", a.status, b.status)}
+	}
+	diffs, err := jsondiff.Compare(a.body, b.body, opts)
+	if err != nil {
+		if !bytes.Equal(a.body, b.body) {
+			return []string{fmt.Sprintf("~ body (not JSON): %d bytes vs %d bytes", len(a.body), len(b.body))}
+		}
+		return nil
+	}
+	lines := make([]string, len(diffs))
+	for i, diff := range diffs {
+		lines[i] = markers[diff.Kind] + " " + diff.String()
+	}
+	return lines
+}
+
+var markers = map[jsondiff.Kind]string{
+	jsondiff.Changed:     "~",
+	jsondiff.TypeChanged: "!",
+	jsondiff.Removed:     "-",
+	jsondiff.Added:       "+",
+}
@@ -0,0 +1,403 @@
+// Command exampleapi-cli calls the Exampleapi API from the command line, with a
+// subcommand per operation and a flag per parameter and request body field:
+//
+//	exampleapi-cli users list --limit 50 --json
+//	exampleapi-cli users create --name Ada --email ada@example.com
+//	exampleapi-cli users update --id 42 --body @user.json
+//
+// It reads the base URL and credentials from the same environment variables
+// and profiles as NewClientFromEnv. Run it without arguments to list the
+// commands, or with -h after a command to list its flags. Fields without a
+// flag of their own, such as nested objects, are passed in --body as JSON,
+// which the field flags then override.
+//
+// Results are printed as text, lists as tables, unless --json asks for the
+// JSON response. With --dry-run the request is printed as a curl command,
+// or as JSON with --json, instead of being sent. Failed calls exit with
+// status 1.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	api "github.com/example/example_api"
+	"github.com/example/example_api/agenttool"
+)
+
+// commands maps each subcommand to the client tool it calls.
+var commands = map[string]string{
+	"posts create": "create_post",
+	"posts list":   "list_posts",
+	"users create": "create_user",
+	"users delete": "delete_user",
+	"users get":    "get_user",
+	"users list":   "list_users",
+	"users update": "update_user",
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdout)
+	stop()
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "exampleapi-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	client, err := api.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+	name, args := splitCommand(args)
+	toolName, ok := commands[name]
+	if !ok {
+		usage(os.Stderr)
+		if name == "" {
+			return flag.ErrHelp
+		}
+		return fmt.Errorf("unknown command %q", name)
+	}
+	tool := agenttool.Find(client.Tools(), toolName)
+	var schema schema
+	if err := json.Unmarshal(tool.InputSchema(), &schema); err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "%s\n\nusage: exampleapi-cli %s [flags]\n\n", tool.Description(), name)
+		fs.PrintDefaults()
+	}
+	asJSON := fs.Bool("json", false, "print the JSON response")
+	dryRun := fs.Bool("dry-run", false, "print the request as a curl command instead of sending it")
+	input := map[string]interface{}{}
+	required := map[string]bool{}
+	for _, param := range schema.Required {
+		required[param] = true
+	}
+	for param, property := range schema.Properties {
+		if param != "body" {
+			defineFlag(fs, flagName(param), property, input, param, "parameter", required[param])
+		}
+	}
+	bodySchema := schema.Properties["body"]
+	var bodyJSON *string
+	bodyFields := map[string]interface{}{}
+	if bodySchema != nil {
+		bodyJSON = fs.String("body", "", "the request body as JSON, @file to read it from a file, or @- from stdin")
+		for field, property := range bodySchema.Properties {
+			defineFlag(fs, flagName(field), property, bodyFields, field, "request body field", false)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	for param := range required {
+		if _, ok := input[param]; !ok && param != "body" {
+			return fmt.Errorf("missing --%s", flagName(param))
+		}
+	}
+	if bodySchema != nil {
+		body := map[string]interface{}{}
+		if *bodyJSON != "" {
+			if err := readBody(*bodyJSON, body); err != nil {
+				return fmt.Errorf("--body: %w", err)
+			}
+		}
+		for field, value := range bodyFields {
+			body[field] = value
+		}
+		if len(body) > 0 || required["body"] {
+			input["body"] = body
+		}
+	}
+
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		tool = agenttool.Find(client.DryRun().Tools(), toolName)
+	}
+	output, err := tool.Invoke(ctx, encoded)
+	var preview *api.DryRunError
+	if errors.As(err, &preview) {
+		if *asJSON {
+			return json.NewEncoder(stdout).Encode(preview.Preview)
+		}
+		_, err := fmt.Fprintln(stdout, preview.Preview.Curl())
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		_, err := fmt.Fprintf(stdout, "%s\n", output)
+		return err
+	}
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return err
+	}
+	return printText(stdout, result)
+}
+
+// splitCommand returns the command named by the leading words of args, and
+// the arguments after it.
+func splitCommand(args []string) (string, []string) {
+	for i := len(args); i > 0; i-- {
+		if name := strings.Join(args[:i], " "); commands[name] != "" {
+			return name, args[i:]
+		}
+	}
+	words := 0
+	for words < len(args) && !strings.HasPrefix(args[words], "-") {
+		words++
+	}
+	return strings.Join(args[:words], " "), args[words:]
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "usage: exampleapi-cli <command> [flags]\n\ncommands:")
+	for _, name := range names {
+		fmt.Fprintln(w, "  "+name)
+	}
+}
+
+// schema is the part of a tool's JSON Schema the flags are derived from.
+type schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	Required   []string           `json:"required"`
+}
+
+// flagName turns a parameter or field name into a flag name: per_page and
+// perPage become per-page.
+func flagName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '.':
+			r = '-'
+		case r >= 'A' && r <= 'Z':
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// defineFlag adds a flag setting target[key] for a property of a scalar
+// type or an array of them, which the flag may be repeated for. Other
+// properties, and those whose flag name is taken, have no flag.
+func defineFlag(fs *flag.FlagSet, name string, property *schema, target map[string]interface{}, key, role string, required bool) {
+	if fs.Lookup(name) != nil {
+		return
+	}
+	typ, repeated := property.Type, false
+	if typ == "array" && property.Items != nil {
+		typ, repeated = property.Items.Type, true
+	}
+	if typ != "string" && typ != "integer" && typ != "number" && typ != "boolean" {
+		return
+	}
+	description := "`" + typ + "` " + role
+	if repeated {
+		description += ", repeatable"
+	}
+	if required {
+		description += ", required"
+	}
+	fs.Var(&fieldFlag{typ: typ, repeated: repeated, target: target, key: key}, name, description)
+}
+
+// fieldFlag parses a flag of a JSON Schema type into a JSON value.
+type fieldFlag struct {
+	typ      string
+	repeated bool
+	target   map[string]interface{}
+	key      string
+}
+
+func (f *fieldFlag) String() string {
+	if f == nil || f.target[f.key] == nil {
+		return ""
+	}
+	return fmt.Sprint(f.target[f.key])
+}
+
+func (f *fieldFlag) IsBoolFlag() bool { return f.typ == "boolean" && !f.repeated }
+
+func (f *fieldFlag) Set(value string) error {
+	var parsed interface{}
+	var err error
+	switch f.typ {
+	case "integer":
+		parsed, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		parsed, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		parsed, err = strconv.ParseBool(value)
+	default:
+		parsed = value
+	}
+	if err != nil {
+		return fmt.Errorf("not a valid %s", f.typ)
+	}
+	if f.repeated {
+		values, _ := f.target[f.key].([]interface{})
+		parsed = append(values, parsed)
+	}
+	f.target[f.key] = parsed
+	return nil
+}
+
+// readBody decodes the value of the --body flag into body.
+func readBody(value string, body map[string]interface{}) error {
+	data := []byte(value)
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, &body)
+}
+
+// printText prints a result for reading: a list of objects, or an object
+// holding one, as a table, and other objects as one field per line.
+func printText(w io.Writer, result interface{}) error {
+	if object, ok := result.(map[string]interface{}); ok {
+		if items := listField(object); items != nil {
+			result = items
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	switch result := result.(type) {
+	case []interface{}:
+		columns := tableColumns(result)
+		if len(columns) == 0 {
+			for _, item := range result {
+				fmt.Fprintln(tw, text(item))
+			}
+			break
+		}
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, item := range result {
+			object, _ := item.(map[string]interface{})
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = text(object[column])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(result))
+		for key := range result {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(tw, "%s:\t%s\n", key, text(result[key]))
+		}
+	case nil:
+	default:
+		fmt.Fprintln(tw, text(result))
+	}
+	return tw.Flush()
+}
+
+// listField returns the only array of objects in an envelope such as
+// {"users": [...], "total": 2}, or nil.
+func listField(object map[string]interface{}) []interface{} {
+	var list []interface{}
+	for _, value := range object {
+		if items, ok := value.([]interface{}); ok && len(tableColumns(items)) > 0 {
+			if list != nil {
+				return nil
+			}
+			list = items
+		}
+	}
+	return list
+}
+
+// tableColumns returns the scalar fields of a list of objects, in sorted
+// order with id first, or nil when the list does not hold objects.
+func tableColumns(items []interface{}) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for key, value := range object {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				continue
+			}
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if (columns[i] == "id") != (columns[j] == "id") {
+			return columns[i] == "id"
+		}
+		return columns[i] < columns[j]
+	})
+	return columns
+}
+
+// text formats a value for a table cell or field line: scalars as they
+// are, anything else as JSON.
+func text(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
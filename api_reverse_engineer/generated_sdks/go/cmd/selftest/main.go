@@ -0,0 +1,274 @@
+// Command selftest checks that this machine can use the Exampleapi API:
+// DNS resolution, TCP and TLS reachability, credentials, and whether a
+// read-only call's response still matches the SDK's schema. It reads the
+// same environment variables and profiles as NewClientFromEnv and prints a
+// report to attach to support requests:
+//
+//	go run ./cmd/selftest -endpoint "GET /v1/users/{id}" -arg id=42
+//
+// It exits with status 1 if any check fails.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/example/example_api"
+)
+
+// operation is a read-only call the self-test can make.
+type operation struct {
+	params []string
+	call   func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error
+}
+
+// operations are the captured GET operations; pingOperation is the default.
+var operations = map[string]operation{
+	"GET /v1/posts": {nil, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		_, err := c.ListPosts(ctx)
+		return err
+	}},
+	"GET /v1/users": {nil, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		_, err := c.ListUsers(ctx, nil)
+		return err
+	}},
+	"GET /v1/users/{id}": {[]string{"id"}, func(ctx context.Context, c *api.ExampleapiClient, args map[string]string) error {
+		id, err := strconv.ParseInt(args["id"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("-arg id: %w", err)
+		}
+		_, err = c.GetUser(ctx, id)
+		return err
+	}},
+}
+
+const pingOperation = "GET /v1/posts"
+
+type argFlags map[string]string
+
+func (a argFlags) String() string { return fmt.Sprint(map[string]string(a)) }
+
+func (a argFlags) Set(value string) error {
+	name, arg, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("want name=value, got %q", value)
+	}
+	a[name] = arg
+	return nil
+}
+
+// report prints check results and remembers whether any failed.
+type report struct {
+	failed bool
+}
+
+func (r *report) check(name string, err error, detail string) {
+	switch {
+	case errors.Is(err, errSkipped):
+		r.print("SKIP", name, detail)
+	case err != nil:
+		r.failed = true
+		r.print("FAIL", name, err.Error())
+	default:
+		r.print("PASS", name, detail)
+	}
+}
+
+func (r *report) print(status, name, detail string) {
+	fmt.Printf("%-4s  %-12s %s\n", status, name, strings.ReplaceAll(detail, "\n", "\n"+strings.Repeat(" ", 19)))
+}
+
+// errSkipped marks a check that could not be run or decided.
+var errSkipped = errors.New("skipped")
+
+func main() {
+	args := argFlags{}
+	endpoint := flag.String("endpoint", pingOperation, "read-only operation to call, such as \"GET /v1/users\"")
+	baseURL := flag.String("base-url", "", "API base URL, overriding the environment and profile")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout for each check")
+	list := flag.Bool("list", false, "list the operations -endpoint accepts and exit")
+	flag.Var(args, "arg", "path parameter for -endpoint as name=value; repeatable")
+	flag.Parse()
+
+	if *list {
+		names := make([]string, 0, len(operations))
+		for name := range operations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	var status int
+	var mismatches []api.SchemaMismatch
+	opts := []api.ClientOption{
+		api.WithAuditSink(api.AuditSinkFunc(func(_ context.Context, record api.AuditRecord) {
+			status = record.StatusCode
+		})),
+		api.WithSchemaMismatchHandler(func(m api.SchemaMismatch) {
+			mismatches = append(mismatches, m)
+		}),
+	}
+	if *baseURL != "" {
+		opts = append(opts, func(c *api.ExampleapiClient) { c.BaseURL = strings.TrimSuffix(*baseURL, "/") })
+	}
+	client, err := api.NewClientFromEnv(opts...)
+	r := &report{}
+	fmt.Printf("Exampleapi self-test, %s %s/%s, %s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH, time.Now().Format(time.RFC3339))
+	r.check("config", err, "base URL "+clientURL(client))
+	if err != nil {
+		os.Exit(1)
+	}
+
+	target, err := url.Parse(client.BaseURL)
+	if err == nil && target.Host == "" {
+		err = fmt.Errorf("base URL %q has no host", client.BaseURL)
+	}
+	if err != nil {
+		r.check("dns", err, "")
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	addrs, err := net.DefaultResolver.LookupHost(ctx, target.Hostname())
+	cancel()
+	r.check("dns", err, target.Hostname()+" -> "+strings.Join(addrs, ", "))
+
+	transport, _ := client.HTTPClient.Transport.(*http.Transport)
+	detail, err := checkConnect(target, transport, *timeout)
+	r.check("connect", err, detail)
+
+	ctx, cancel = context.WithTimeout(context.Background(), *timeout)
+	err = client.Ping(ctx)
+	cancel()
+	switch {
+	case status == 401 || status == 403:
+		r.check("credentials", fmt.Errorf("rejected with status %d; check %s or the profile", status, api.EnvToken), "")
+	case err == nil:
+		r.check("credentials", nil, fmt.Sprintf("accepted by %s (status %d)", pingOperation, status))
+	default:
+		r.check("credentials", errSkipped, "not verified: "+err.Error())
+	}
+
+	op, ok := operations[*endpoint]
+	if !ok {
+		r.check("call", fmt.Errorf("unknown operation %q; run with -list", *endpoint), "")
+		os.Exit(1)
+	}
+	for _, name := range op.params {
+		if _, ok := args[name]; !ok {
+			r.check("call", fmt.Errorf("%s needs -arg %s=...", *endpoint, name), "")
+			os.Exit(1)
+		}
+	}
+	status, mismatches = 0, nil
+	ctx, cancel = context.WithTimeout(context.Background(), *timeout)
+	start := time.Now()
+	err = op.call(ctx, client, args)
+	cancel()
+	r.check("call", err, fmt.Sprintf("%s -> %d in %s", *endpoint, status, time.Since(start).Round(time.Millisecond)))
+
+	r.schema(err, mismatches)
+
+	if r.failed {
+		os.Exit(1)
+	}
+}
+
+// schema reports the mismatches between the call's response and the SDK
+// types. Values of the wrong type fail the check; fields added to or
+// missing from the response only warn, as the capture may not have seen
+// every optional field.
+func (r *report) schema(callErr error, mismatches []api.SchemaMismatch) {
+	if callErr != nil {
+		r.check("schema", errSkipped, "no response to validate")
+		return
+	}
+	if len(mismatches) == 0 {
+		r.check("schema", nil, "response matches the SDK types")
+		return
+	}
+	status := "WARN"
+	lines := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		lines[i] = fmt.Sprintf("%s %s", m.Kind, m.Field)
+		if m.Kind == api.TypeFallback {
+			lines[i] += fmt.Sprintf(" (expected %s, got %s)", m.Expected, m.Observed)
+			status, r.failed = "FAIL", true
+		}
+	}
+	r.print(status, "schema", fmt.Sprintf("%d mismatches:\n%s", len(mismatches), strings.Join(lines, "\n")))
+}
+
+// checkConnect dials the API, completing a TLS handshake for https with the
+// client's TLS settings, and describes the connection. Through a proxy, only
+// the proxy is dialed.
+func checkConnect(target *url.URL, transport *http.Transport, timeout time.Duration) (string, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	host, port := target.Hostname(), target.Port()
+	if port == "" {
+		port = map[bool]string{true: "443", false: "80"}[target.Scheme == "https"]
+	}
+	if transport != nil && transport.Proxy != nil {
+		proxyURL, err := transport.Proxy(&http.Request{URL: target})
+		if err != nil {
+			return "", fmt.Errorf("proxy: %w", err)
+		}
+		if proxyURL != nil {
+			conn, err := dialer.Dial("tcp", proxyURL.Host)
+			if err != nil {
+				return "", fmt.Errorf("proxy %s: %w", proxyURL.Redacted(), err)
+			}
+			defer conn.Close()
+			return "via proxy " + proxyURL.Redacted() + " (TLS not checked)", nil
+		}
+	}
+	address := net.JoinHostPort(host, port)
+	if target.Scheme != "https" {
+		conn, err := dialer.Dial("tcp", address)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		return "tcp " + conn.RemoteAddr().String(), nil
+	}
+	config := &tls.Config{}
+	if transport != nil && transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	config.ServerName = host
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, config)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	cert := state.PeerCertificates[0]
+	days := int(time.Until(cert.NotAfter).Hours() / 24)
+	detail := fmt.Sprintf("%s %s, certificate %q expires in %d days", conn.RemoteAddr(), tls.VersionName(state.Version), cert.Subject.CommonName, days)
+	if days < 14 {
+		detail += " (renew soon)"
+	}
+	return detail, nil
+}
+
+func clientURL(client *api.ExampleapiClient) string {
+	if client == nil {
+		return ""
+	}
+	return client.BaseURL
+}
@@ -0,0 +1,169 @@
+package example_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Codec encodes and decodes JSON bodies. The default uses encoding/json;
+// sonic.ConfigStd, jsoniter.ConfigCompatibleWithStandardLibrary, and other
+// drop-in replacements implement it as they are. A codec that does not
+// return *json.UnmarshalTypeError for values of the wrong type fails those
+// calls instead of leaving the field at its zero value.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// WithCodec encodes JSON request bodies and decodes JSON responses with
+// codec, including those of +json media types without a Serializer of
+// their own.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.codec = codec
+	}
+}
+
+// jsonCodec returns the codec for JSON bodies.
+func (c *ExampleapiClient) jsonCodec() Codec {
+	if c.codec != nil {
+		return c.codec
+	}
+	return JSONSerializer{}
+}
+
+// DecodeMode chooses what decoding does with response fields the generated
+// types have no field for. The schema was inferred from captured traffic,
+// so responses gain fields over time.
+type DecodeMode int
+
+const (
+	// DecodeLenient drops unknown fields, reporting them only as schema
+	// mismatches. It is the default.
+	DecodeLenient DecodeMode = iota
+	// DecodeTolerant keeps unknown fields, undecoded, in the Extra map of
+	// the struct they appear in.
+	DecodeTolerant
+	// DecodeStrict fails calls whose response has unknown fields with an
+	// *UnknownFieldsError. The result is still decoded.
+	DecodeStrict
+)
+
+// WithDecodeMode sets what decoding does with unknown response fields.
+func WithDecodeMode(mode DecodeMode) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.decodeMode = mode
+	}
+}
+
+// UnknownFieldsError reports the response fields the generated types have
+// no field for, returned in DecodeStrict mode.
+type UnknownFieldsError struct {
+	Operation string   // "GET /v1/users/{id}"
+	Fields    []string // paths such as "users[].nickname"
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s: unknown response fields: %s", e.Operation, strings.Join(e.Fields, ", "))
+}
+
+// checkUnknownFields keeps or rejects the fields of a JSON body v has no
+// field for, as the client's DecodeMode says.
+func (c *ExampleapiClient) checkUnknownFields(operation string, body []byte, v interface{}) error {
+	if c.decodeMode == DecodeLenient {
+		return nil
+	}
+	unknown := make(map[string]bool)
+	collectUnknownFields("", body, reflect.ValueOf(v), c.decodeMode == DecodeTolerant, unknown)
+	if c.decodeMode != DecodeStrict || len(unknown) == 0 {
+		return nil
+	}
+	fields := make([]string, 0, len(unknown))
+	for field := range unknown {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return &UnknownFieldsError{Operation: operation, Fields: fields}
+}
+
+var rawFieldsType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// collectUnknownFields adds the paths of the fields of raw that v has no
+// field for to unknown. With keep, it also stores them in the Extra map of
+// the struct they appear in. Values with custom decoding are not entered.
+func collectUnknownFields(path string, raw json.RawMessage, v reflect.Value, keep bool, unknown map[string]bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Type().Implements(unmarshalerType) || (v.CanAddr() && v.Addr().Type().Implements(unmarshalerType)) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return
+		}
+		known := make(map[string]bool, len(object))
+		var extra reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type == rawFieldsType && field.Tag.Get("json") == "-" {
+				extra = v.Field(i)
+				continue
+			}
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+			if key, found := lookupKey(object, name); found {
+				known[key] = true
+				collectUnknownFields(joinPath(path, name), object[key], v.Field(i), keep, unknown)
+			}
+		}
+		for key, value := range object {
+			if known[key] {
+				continue
+			}
+			unknown[joinPath(path, key)] = true
+			if keep && extra.IsValid() && extra.CanSet() {
+				if extra.IsNil() {
+					extra.Set(reflect.MakeMap(rawFieldsType))
+				}
+				extra.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			collectUnknownFields(path+"[]", items[i], v.Index(i), keep, unknown)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if v.Type().Key().Kind() != reflect.String || json.Unmarshal(raw, &object) != nil {
+			return
+		}
+		for key, value := range object {
+			mapKey := reflect.ValueOf(key).Convert(v.Type().Key())
+			current := v.MapIndex(mapKey)
+			if !current.IsValid() {
+				continue
+			}
+			elem := reflect.New(current.Type()).Elem()
+			elem.Set(current)
+			collectUnknownFields(joinPath(path, key), value, elem, keep, unknown)
+			if keep {
+				v.SetMapIndex(mapKey, elem)
+			}
+		}
+	}
+}
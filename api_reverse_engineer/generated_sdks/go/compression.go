@@ -0,0 +1,140 @@
+package example_api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultAcceptEncoding is the Accept-Encoding header clients send unless
+// WithAcceptEncoding says otherwise.
+const DefaultAcceptEncoding = "gzip, deflate"
+
+// DefaultCompressMinSize is the size from which request bodies are
+// compressed, for operations the server accepts compressed bodies for;
+// smaller bodies gain too little to be worth the server inflating them.
+const DefaultCompressMinSize = 1024
+
+// requestEncodings maps the operations the server was seen accepting
+// compressed request bodies for to the Content-Encoding it accepted.
+var requestEncodings = map[string]string{}
+
+// maxInflateRatio bounds how large a gzip trailer may claim the inflated
+// body to be, relative to the compressed one, before it is not trusted for
+// sizing the buffer.
+const maxInflateRatio = 1032
+
+// WithAcceptEncoding sets the Accept-Encoding header sent with every
+// request; gzip and deflate responses are inflated by the client. An empty
+// string leaves negotiation to the HTTP transport.
+//
+// The client inflates responses itself, into a buffer sized from the gzip
+// trailer, so a large response is held once compressed and once inflated
+// rather than regrown while reading. Checksum headers are verified against
+// the compressed bytes, as the server sent them.
+func WithAcceptEncoding(encodings string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.acceptEncoding = encodings
+	}
+}
+
+// WithRequestCompression compresses request bodies of minSize bytes or more,
+// instead of DefaultCompressMinSize, for the operations the server was seen
+// accepting compressed bodies for. A negative minSize sends every body
+// uncompressed. Request signatures and digests cover the compressed bytes,
+// as the server receives them.
+func WithRequestCompression(minSize int) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.compressMinSize = minSize
+	}
+}
+
+// compressRequest encodes body as the server accepts for operation, when it
+// is large enough, returning the body to send and its Content-Encoding.
+func (c *ExampleapiClient) compressRequest(operation string, body []byte) ([]byte, string, error) {
+	encoding, ok := requestEncodings[operation]
+	if !ok || c.compressMinSize < 0 || len(body) < c.compressMinSize {
+		return body, "", nil
+	}
+	var buf bytes.Buffer
+	var w io.WriteCloser = gzip.NewWriter(&buf)
+	if encoding == "deflate" {
+		w = zlib.NewWriter(&buf)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, "", fmt.Errorf("compressing request: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("compressing request: %w", err)
+	}
+	return buf.Bytes(), encoding, nil
+}
+
+// inflate decodes the Content-Encoding of a response body read as wire,
+// stopping after limit inflated bytes when limit is positive. The wire bytes
+// are kept alongside, for the response cache and checksums.
+func inflate(resp *http.Response, wire []byte, limit int64) ([]byte, error) {
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	body := wire
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var reader io.Reader
+		var err error
+		switch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// Some servers send raw DEFLATE rather than the zlib format the
+			// specification asks for.
+			if reader, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(body)), nil
+			}
+		default:
+			return nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inflating %s response: %w", encodings[i], err)
+		}
+		if body, err = readInflated(reader, inflatedSize(body), limit); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// inflatedSize estimates the inflated size of body: the size a gzip trailer
+// records, when plausible, or four times the compressed size. Either can be
+// far off, so readInflated caps it like any other presize.
+func inflatedSize(body []byte) int64 {
+	if len(body) >= 18 && body[0] == 0x1f && body[1] == 0x8b {
+		size := int64(binary.LittleEndian.Uint32(body[len(body)-4:]))
+		if size <= int64(len(body))*maxInflateRatio {
+			return size
+		}
+	}
+	return int64(len(body)) * 4
+}
+
+func readInflated(reader io.Reader, size, limit int64) ([]byte, error) {
+	if limit > 0 {
+		if size > limit {
+			size = limit + 1
+		}
+		reader = io.LimitReader(reader, limit+1)
+	}
+	body, err := readAll(reader, size)
+	if err != nil {
+		return nil, fmt.Errorf("inflating response: %w", err)
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, &SizeLimitError{Direction: "response", Limit: limit, Size: int64(len(body))}
+	}
+	return body, nil
+}
@@ -0,0 +1,16 @@
+package example_api
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadInflatedPresize(t *testing.T) {
+	body, err := readInflated(bytes.NewReader([]byte(`{}`)), 1<<40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cap(body) > maxPresize+bytes.MinRead {
+		t.Errorf("allocated %d bytes up front, want at most %d", cap(body), maxPresize+bytes.MinRead)
+	}
+}
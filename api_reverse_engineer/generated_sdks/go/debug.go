@@ -0,0 +1,176 @@
+package example_api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithDebug writes every request to w as a copy-pasteable curl command,
+// followed by the raw response. Credentials are replaced by shell
+// variables ("Authorization: $AUTHORIZATION"), so the command can be re-run
+// after exporting them, and credential query parameters are redacted.
+func WithDebug(w io.Writer) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.debug = &debugLogger{w: w}
+	}
+}
+
+// maxDebugBody caps how much of each body is written by WithDebug.
+const maxDebugBody = 64 << 10
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"Api-Key":             true,
+	"X-Auth-Token":        true,
+	"X-Access-Token":      true,
+	"X-Csrf-Token":        true,
+}
+
+var sensitiveParams = map[string]bool{
+	"api_key":       true,
+	"apikey":        true,
+	"access_token":  true,
+	"token":         true,
+	"key":           true,
+	"signature":     true,
+	"sig":           true,
+	"client_secret": true,
+}
+
+// isSensitiveHeader reports whether a header usually carries credentials,
+// or carries an API key set with WithAPIKeyHeader.
+func isSensitiveHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	_, credential := credentialHeaders.Load(name)
+	return sensitiveHeaders[name] || credential
+}
+
+// isSecretHeader reports whether a header is sensitive or carries one of
+// the client's credentials.
+func (c *ExampleapiClient) isSecretHeader(name string) bool {
+	if isSensitiveHeader(name) {
+		return true
+	}
+	for _, credential := range c.credentials {
+		if http.CanonicalHeaderKey(credential.header) == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// callLogger logs the attempts and retries of calls; WithLogger sets one
+// on Go 1.21 and later.
+type callLogger interface {
+	logAttempt(ctx context.Context, operation string, attempt int, req *http.Request, resp *http.Response, took time.Duration, err error)
+	logRetry(ctx context.Context, operation string, attempt int, wait time.Duration, err error)
+}
+
+// redactQuery returns a copy of u with credential query parameters
+// replaced by REDACTED.
+func redactQuery(u *url.URL) *url.URL {
+	redacted := *u
+	if u.RawQuery == "" {
+		return &redacted
+	}
+	query := u.Query()
+	for name := range query {
+		_, credential := credentialParams.Load(strings.ToLower(name))
+		if sensitiveParams[strings.ToLower(name)] || credential {
+			query.Set(name, "REDACTED")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return &redacted
+}
+
+type debugLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// log writes one exchange; it is a no-op on a nil logger so call sites
+// need no checks.
+func (d *debugLogger) log(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error) {
+	if d == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(curlCommand(req, reqBody))
+	b.WriteString("\n")
+	if resp != nil {
+		fmt.Fprintf(&b, "< %s %s\n", resp.Proto, resp.Status)
+		for _, name := range sortedKeys(resp.Header) {
+			for _, value := range resp.Header[name] {
+				if isSensitiveHeader(name) {
+					value = "REDACTED"
+				}
+				fmt.Fprintf(&b, "< %s: %s\n", name, value)
+			}
+		}
+		b.WriteString("<\n")
+		b.WriteString(truncateBody(respBody))
+		b.WriteString("\n")
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "# error: %v\n", err)
+	}
+	b.WriteString("\n")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	io.WriteString(d.w, b.String())
+}
+
+func curlCommand(req *http.Request, body []byte) string {
+	parts := []string{"curl"}
+	if req.Method != http.MethodGet {
+		parts = append(parts, "-X "+req.Method)
+	}
+	parts = append(parts, shellQuote(redactQuery(req.URL).String()))
+	for _, name := range sortedKeys(req.Header) {
+		for _, value := range req.Header[name] {
+			if isSensitiveHeader(name) {
+				variable := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+				parts = append(parts, fmt.Sprintf(`-H "%s: $%s"`, name, variable))
+				continue
+			}
+			parts = append(parts, "-H "+shellQuote(name+": "+value))
+		}
+	}
+	if len(body) > 0 {
+		parts = append(parts, "--data-raw "+shellQuote(truncateBody(body)))
+	}
+	return strings.Join(parts, " \\\n  ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func truncateBody(body []byte) string {
+	if len(body) <= maxDebugBody {
+		return string(body)
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxDebugBody], len(body)-maxDebugBody)
+}
+
+func sortedKeys(header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
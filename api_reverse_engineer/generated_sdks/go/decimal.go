@@ -0,0 +1,400 @@
+package example_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact base-10 number for monetary and other decimal fields,
+// so arithmetic never drifts the way float64 does (0.1 + 0.2 is exactly
+// 0.3). It decodes from JSON numbers and numeric strings, keeps the scale it
+// was written with ("10.50" stays "10.50"), and encodes in the same form it
+// was decoded from. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int // nil means zero
+	scale    int32    // digits after the decimal point
+	quoted   bool     // encoded as a JSON string
+}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1050, 2) is 10.50.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}.normalize()
+}
+
+// ParseDecimal parses a decimal such as "-12.345" or "1e3".
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	mantissa, exponent := text, int64(0)
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		var err error
+		mantissa = text[:i]
+		if exponent, err = strconv.ParseInt(text[i+1:], 10, 32); err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	negative := strings.HasPrefix(mantissa, "-")
+	if negative || strings.HasPrefix(mantissa, "+") {
+		mantissa = mantissa[1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := whole + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+	scale := int64(len(frac)) - exponent
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("decimal %q: exponent out of range", s)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}.normalize(), nil
+}
+
+// maxDecimalScale bounds the scale ParseDecimal accepts either way, so that
+// an input such as "1e2000000000" is rejected rather than expanded into a
+// two-billion-digit integer.
+const maxDecimalScale = 10000
+
+// MustParseDecimal is ParseDecimal for literals; it panics on invalid input.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat converts f using its shortest exact representation, so
+// DecimalFromFloat(0.1) is 0.1 rather than 0.1000000000000000055511151231257827.
+// It panics on NaN and infinities.
+func DecimalFromFloat(f float64) Decimal {
+	return MustParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// normalize removes negative scales, which ParseDecimal produces for
+// exponents.
+func (d Decimal) normalize() Decimal {
+	if d.scale < 0 {
+		d.unscaled = new(big.Int).Mul(d.int(), pow10(-d.scale))
+		d.scale = 0
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rescale returns d's unscaled value at a scale of at least d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale <= d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add returns d + other. Results keep d's JSON form.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale, quoted: d.quoted}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale, quoted: d.quoted}
+}
+
+// Div returns d / other rounded half away from zero to places digits. It
+// panics if other is zero.
+func (d Decimal) Div(other Decimal, places int32) Decimal {
+	if other.IsZero() {
+		panic("decimal division by zero")
+	}
+	// d / other = (d.unscaled / other.unscaled) * 10^(other.scale - d.scale)
+	num, den := d.int(), other.int()
+	if shift := places + other.scale - d.scale; shift >= 0 {
+		num = new(big.Int).Mul(num, pow10(shift))
+	} else {
+		den = new(big.Int).Mul(den, pow10(-shift))
+	}
+	return Decimal{unscaled: divRound(num, den), scale: places, quoted: d.quoted}
+}
+
+// Round returns d rounded half away from zero to places digits after the
+// decimal point; values with fewer digits are returned unchanged.
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 || d.scale <= places {
+		return d
+	}
+	return Decimal{unscaled: divRound(d.int(), pow10(d.scale-places)), scale: places, quoted: d.quoted}
+}
+
+func divRound(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	// Round away from zero when |rem| * 2 >= |den|.
+	if new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	d.unscaled = new(big.Int).Neg(d.int())
+	return d
+}
+
+// Cmp returns -1, 0, or +1 as d is less than, equal to, or greater than
+// other; 10.5 and 10.50 are equal.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := maxScale(d, other)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal reports whether d and other are numerically equal.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0, or +1.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Float64 returns the nearest float64, for display or statistics only.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with its full scale, e.g. "-0.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// AsString returns d encoded as a JSON string ("10.50") rather than a
+// number, for APIs that expect decimal strings in requests.
+func (d Decimal) AsString() Decimal {
+	d.quoted = true
+	return d
+}
+
+var decimalType = reflect.TypeOf(Decimal{})
+
+// UnmarshalJSON implements json.Unmarshaler. Values that are not decimals
+// fail as a *json.UnmarshalTypeError, which the client tolerates.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	quoted := len(data) > 0 && data[0] == '"'
+	text := string(data)
+	if quoted {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		var raw interface{}
+		json.Unmarshal(data, &raw)
+		return &json.UnmarshalTypeError{Value: jsonType(raw), Type: decimalType}
+	}
+	parsed.quoted = quoted
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.quoted {
+		return strconv.AppendQuote(nil, d.String()), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// acceptsJSON reports whether raw decodes as a Decimal, for schema
+// mismatch telemetry.
+func (Decimal) acceptsJSON(raw interface{}) bool {
+	switch v := raw.(type) {
+	case float64:
+		return true
+	case string:
+		_, err := ParseDecimal(v)
+		return err == nil
+	}
+	return false
+}
+
+// ErrCurrencyMismatch is returned when combining Money in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// moneyAmountKeys and moneyCurrencyKeys are the JSON keys recognized in
+// money objects, in order of preference when encoding.
+var (
+	moneyAmountKeys   = []string{"amount", "value"}
+	moneyCurrencyKeys = []string{"currency", "currency_code"}
+)
+
+// Money is an exact amount in a currency, decoded from objects such as
+// {"amount": "10.50", "currency": "USD"}. It encodes with the keys it was
+// decoded with.
+type Money struct {
+	Amount   Decimal
+	Currency string
+
+	amountKey, currencyKey string
+}
+
+// NewMoney returns amount in currency.
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add returns m + other, or ErrCurrencyMismatch.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: cannot add %s to %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+	}
+	m.Amount = m.Amount.Add(other.Amount)
+	return m, nil
+}
+
+// Sub returns m - other, or ErrCurrencyMismatch.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: cannot subtract %s from %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+	}
+	m.Amount = m.Amount.Sub(other.Amount)
+	return m, nil
+}
+
+// String formats m as "10.50 USD".
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*m = Money{}
+	for _, key := range moneyCurrencyKeys {
+		if raw, ok := object[key]; ok {
+			m.currencyKey = key
+			if err := json.Unmarshal(raw, &m.Currency); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	for _, key := range moneyAmountKeys {
+		if raw, ok := object[key]; ok {
+			m.amountKey = key
+			return m.Amount.UnmarshalJSON(raw)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {
+	amountKey, currencyKey := m.amountKey, m.currencyKey
+	if amountKey == "" {
+		amountKey = moneyAmountKeys[0]
+	}
+	if currencyKey == "" {
+		currencyKey = moneyCurrencyKeys[0]
+	}
+	amount, _ := m.Amount.MarshalJSON()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONKey(&buf, amountKey)
+	buf.Write(amount)
+	buf.WriteByte(',')
+	writeJSONKey(&buf, currencyKey)
+	currency, err := json.Marshal(m.Currency)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(currency)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJSONKey(buf *bytes.Buffer, key string) {
+	encoded, _ := json.Marshal(key)
+	buf.Write(encoded)
+	buf.WriteByte(':')
+}
+
+// acceptsJSON reports whether raw decodes as Money.
+func (Money) acceptsJSON(raw interface{}) bool {
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, key := range moneyAmountKeys {
+		if amount, ok := object[key]; ok {
+			return Decimal{}.acceptsJSON(amount)
+		}
+	}
+	return false
+}
@@ -0,0 +1,496 @@
+// Package drift watches live API responses for divergence from a schema
+// published to the registry by the API reverse engineering tool
+// (cli.py --registry). Wrap the client's transport with a Monitor to
+// validate a sample of responses and accumulate findings over time.
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/example_api/alert"
+)
+
+// Schema is a JSON-schema-like node as inferred by the tool.
+type Schema struct {
+	Type       string             `json:"type"`
+	Format     string             `json:"format,omitempty"`
+	Nullable   bool               `json:"nullable,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+// Endpoint is the registered shape of one operation.
+type Endpoint struct {
+	Method          string             `json:"method"`
+	Path            string             `json:"path"`
+	PathParams      []string           `json:"path_params"`
+	RequestSchema   *Schema            `json:"request_body_schema"`
+	ResponseSchemas map[string]*Schema `json:"response_schemas"`
+}
+
+// Snapshot is one version of an API's schema in the registry.
+type Snapshot struct {
+	APIName        string               `json:"api_name"`
+	BaseURL        string               `json:"base_url"`
+	Version        int                  `json:"version"`
+	CreatedAt      string               `json:"created_at"`
+	Fingerprint    string               `json:"fingerprint"`
+	SourceCaptures []string             `json:"source_captures"`
+	Endpoints      map[string]*Endpoint `json:"endpoints"`
+}
+
+// LoadSnapshot reads a registry snapshot file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("drift: invalid snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^0-9A-Za-z_.-]+`)
+
+// LoadLatest reads the newest version of apiName from a filesystem
+// registry rooted at dir.
+func LoadLatest(dir, apiName string) (*Snapshot, error) {
+	apiDir := filepath.Join(dir, unsafeNameChars.ReplaceAllString(apiName, "_"))
+	matches, err := filepath.Glob(filepath.Join(apiDir, "v*.json"))
+	if err != nil {
+		return nil, err
+	}
+	latest, latestPath := 0, ""
+	for _, match := range matches {
+		version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), "v"), ".json"))
+		if err == nil && version > latest {
+			latest, latestPath = version, match
+		}
+	}
+	if latestPath == "" {
+		return nil, fmt.Errorf("drift: no schema versions for %q in %s", apiName, dir)
+	}
+	return LoadSnapshot(latestPath)
+}
+
+// Kind classifies a finding.
+type Kind string
+
+const (
+	// FieldAdded is a response field absent from the registered schema.
+	FieldAdded Kind = "field_added"
+	// FieldMissing is a required field that was absent from a response.
+	FieldMissing Kind = "field_missing"
+	// TypeChanged is a value whose JSON type differs from the schema.
+	TypeChanged Kind = "type_changed"
+	// EndpointVanished is a registered endpoint answering 404 or 410.
+	EndpointVanished Kind = "endpoint_vanished"
+	// UnknownEndpoint is a response for a route not in the schema.
+	UnknownEndpoint Kind = "unknown_endpoint"
+)
+
+// Finding is one distinct divergence, aggregated across occurrences.
+type Finding struct {
+	Kind      Kind
+	Endpoint  string
+	Field     string
+	Expected  string
+	Observed  string
+	Sample    string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+func (f Finding) String() string {
+	location := strings.TrimSpace(f.Endpoint + " " + f.Field)
+	if f.Kind == TypeChanged {
+		return fmt.Sprintf("%s: %s: expected %s, observed %s (x%d)", f.Kind, location, f.Expected, f.Observed, f.Count)
+	}
+	return fmt.Sprintf("%s: %s (x%d)", f.Kind, location, f.Count)
+}
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithSampleRate validates only the given fraction (0-1] of responses.
+func WithSampleRate(rate float64) Option {
+	return func(m *Monitor) { m.sampleRate = rate }
+}
+
+// WithMaxBodyBytes skips validation of responses larger than n bytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(m *Monitor) { m.maxBodyBytes = n }
+}
+
+// WithHandler calls fn the first time each distinct finding is seen.
+func WithHandler(fn func(Finding)) Option {
+	return func(m *Monitor) { m.handlers = append(m.handlers, fn) }
+}
+
+// WithSink delivers an alert.Alert to sink the first time each distinct
+// finding is seen. Delivery happens in the background so the response is
+// not held up; call Flush to wait for pending deliveries.
+func WithSink(sink alert.Sink) Option {
+	return func(m *Monitor) { m.sinks = append(m.sinks, sink) }
+}
+
+// WithAlertErrorHandler is called when a sink fails to deliver an alert.
+func WithAlertErrorHandler(fn func(alert.Alert, error)) Option {
+	return func(m *Monitor) { m.onAlertError = fn }
+}
+
+type route struct {
+	label    string
+	method   string
+	host     string
+	segments []string
+	endpoint *Endpoint
+}
+
+// Monitor validates responses against a snapshot. It is safe for
+// concurrent use.
+type Monitor struct {
+	snapshot     *Snapshot
+	basePath     string
+	routes       []route
+	sampleRate   float64
+	maxBodyBytes int64
+	handlers     []func(Finding)
+	sinks        []alert.Sink
+	onAlertError func(alert.Alert, error)
+	pending      sync.WaitGroup
+
+	mu       sync.Mutex
+	findings map[string]*Finding
+	checked  int
+	rand     *rand.Rand
+}
+
+// NewMonitor creates a Monitor for snapshot.
+func NewMonitor(snapshot *Snapshot, opts ...Option) *Monitor {
+	m := &Monitor{
+		snapshot:     snapshot,
+		sampleRate:   1,
+		maxBodyBytes: 1 << 20,
+		findings:     make(map[string]*Finding),
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if base, err := url.Parse(snapshot.BaseURL); err == nil {
+		m.basePath = strings.TrimSuffix(base.Path, "/")
+	}
+	for label, endpoint := range snapshot.Endpoints {
+		r := route{label: label, method: endpoint.Method, endpoint: endpoint}
+		if _, target, ok := strings.Cut(label, " "); ok && strings.Contains(target, "://") {
+			if u, err := url.Parse(target); err == nil {
+				r.host = u.Host
+			}
+		}
+		r.segments = strings.Split(strings.Trim(endpoint.Path, "/"), "/")
+		m.routes = append(m.routes, r)
+	}
+	// Prefer literal segments over parameters when several routes match.
+	sort.Slice(m.routes, func(i, j int) bool {
+		return countParams(m.routes[i].segments) < countParams(m.routes[j].segments)
+	})
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func countParams(segments []string) int {
+	n := 0
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+// Transport wraps next so every sampled response is validated. A nil next
+// uses http.DefaultTransport.
+//
+//	client.HTTPClient.Transport = monitor.Transport(client.HTTPClient.Transport)
+func (m *Monitor) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || !m.sample() {
+			return resp, err
+		}
+
+		var buf bytes.Buffer
+		n, readErr := io.Copy(&buf, io.LimitReader(resp.Body, m.maxBodyBytes+1))
+		if readErr != nil || n > m.maxBodyBytes {
+			resp.Body = readCloser{io.MultiReader(&buf, resp.Body), resp.Body}
+			return resp, err
+		}
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+		m.Check(req.Method, req.URL, resp.StatusCode, buf.Bytes())
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (m *Monitor) sample() bool {
+	if m.sampleRate >= 1 {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rand.Float64() < m.sampleRate
+}
+
+// Check validates one response body and records any findings.
+func (m *Monitor) Check(method string, u *url.URL, status int, body []byte) {
+	m.mu.Lock()
+	m.checked++
+	m.mu.Unlock()
+
+	r := m.match(method, u)
+	if r == nil {
+		m.record(Finding{Kind: UnknownEndpoint, Endpoint: method + " " + u.Path}, body)
+		return
+	}
+
+	schema, ok := r.endpoint.ResponseSchemas[strconv.Itoa(status)]
+	if !ok {
+		if status == http.StatusNotFound || status == http.StatusGone {
+			m.record(Finding{Kind: EndpointVanished, Endpoint: r.label, Observed: strconv.Itoa(status)}, body)
+		}
+		return
+	}
+	if schema == nil || len(body) == 0 {
+		return
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		m.record(Finding{Kind: TypeChanged, Endpoint: r.label, Field: "$", Expected: schema.Type, Observed: "non-json"}, body)
+		return
+	}
+	m.validate(r.label, "$", schema, value, body)
+}
+
+func (m *Monitor) match(method string, u *url.URL) *route {
+	path := strings.TrimPrefix(u.Path, m.basePath)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := range m.routes {
+		r := &m.routes[i]
+		if r.method != method || len(r.segments) != len(segments) {
+			continue
+		}
+		if r.host != "" && r.host != u.Host {
+			continue
+		}
+		matched := true
+		for j, segment := range r.segments {
+			if !strings.HasPrefix(segment, "{") && segment != segments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) validate(endpoint, field string, schema *Schema, value interface{}, body []byte) {
+	if schema == nil || schema.Type == "" || schema.Type == "any" {
+		return
+	}
+	observed := jsonType(value)
+	if observed == "null" {
+		if !schema.Nullable && schema.Type != "null" {
+			m.record(Finding{Kind: TypeChanged, Endpoint: endpoint, Field: field, Expected: schema.Type, Observed: observed}, body)
+		}
+		return
+	}
+	if observed != schema.Type && !(observed == "integer" && schema.Type == "number") {
+		m.record(Finding{Kind: TypeChanged, Endpoint: endpoint, Field: field, Expected: schema.Type, Observed: observed}, body)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			childSchema, ok := schema.Properties[name]
+			if !ok {
+				m.record(Finding{Kind: FieldAdded, Endpoint: endpoint, Field: field + "." + name, Observed: jsonType(child)}, body)
+				continue
+			}
+			m.validate(endpoint, field+"."+name, childSchema, child, body)
+		}
+		for name, childSchema := range schema.Properties {
+			if _, ok := v[name]; !ok && !childSchema.Nullable {
+				m.record(Finding{Kind: FieldMissing, Endpoint: endpoint, Field: field + "." + name, Expected: childSchema.Type}, body)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			m.validate(endpoint, field+"[]", schema.Items, item, body)
+		}
+	}
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+const maxSampleBytes = 512
+
+func (m *Monitor) record(f Finding, body []byte) {
+	key := string(f.Kind) + "|" + f.Endpoint + "|" + f.Field + "|" + f.Observed
+	now := time.Now()
+
+	m.mu.Lock()
+	existing, ok := m.findings[key]
+	if ok {
+		existing.Count++
+		existing.LastSeen = now
+		m.mu.Unlock()
+		return
+	}
+	if len(body) > maxSampleBytes {
+		body = body[:maxSampleBytes]
+	}
+	f.Sample = string(body)
+	f.Count = 1
+	f.FirstSeen, f.LastSeen = now, now
+	m.findings[key] = &f
+	handlers := m.handlers
+	m.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(f)
+	}
+	if len(m.sinks) > 0 {
+		m.alert(f)
+	}
+}
+
+const alertTimeout = 10 * time.Second
+
+func (m *Monitor) alert(f Finding) {
+	a := alert.Alert{
+		Source:   "drift",
+		API:      m.snapshot.APIName,
+		Kind:     string(f.Kind),
+		Endpoint: f.Endpoint,
+		Field:    f.Field,
+		Expected: f.Expected,
+		Observed: f.Observed,
+		Sample:   f.Sample,
+		Time:     f.FirstSeen,
+	}
+	for _, sink := range m.sinks {
+		m.pending.Add(1)
+		go func(sink alert.Sink) {
+			defer m.pending.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+			defer cancel()
+			if err := sink.Send(ctx, a); err != nil && m.onAlertError != nil {
+				m.onAlertError(a, err)
+			}
+		}(sink)
+	}
+}
+
+// Flush waits for alerts still being delivered to sinks.
+func (m *Monitor) Flush() {
+	m.pending.Wait()
+}
+
+// Findings returns every distinct finding so far, most frequent first.
+func (m *Monitor) Findings() []Finding {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	findings := make([]Finding, 0, len(m.findings))
+	for _, f := range m.findings {
+		findings = append(findings, *f)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Count != findings[j].Count {
+			return findings[i].Count > findings[j].Count
+		}
+		return findings[i].String() < findings[j].String()
+	})
+	return findings
+}
+
+// Report writes a summary of the findings to w.
+func (m *Monitor) Report(w io.Writer) error {
+	m.mu.Lock()
+	checked := m.checked
+	m.mu.Unlock()
+
+	findings := m.Findings()
+	if _, err := fmt.Fprintf(w, "schema %s v%d: %d responses checked, %d findings\n",
+		m.snapshot.APIName, m.snapshot.Version, checked, len(findings)); err != nil {
+		return err
+	}
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "  %s\n", f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset clears accumulated findings, for example after they were reported.
+func (m *Monitor) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.findings = make(map[string]*Finding)
+	m.checked = 0
+}
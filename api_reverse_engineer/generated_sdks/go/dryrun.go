@@ -0,0 +1,88 @@
+package example_api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DryRun returns a client whose calls prepare their requests, with body,
+// headers, credentials, and signature, and return them in a *DryRunError
+// instead of sending them. It shares everything else with c, as derived
+// clients do:
+//
+//	_, err := client.DryRun().CreateUser(ctx, data)
+//	var dryRun *DryRunError
+//	if errors.As(err, &dryRun) {
+//		fmt.Println(dryRun.Preview.Curl())
+//	}
+func (c *ExampleapiClient) DryRun() *ExampleapiClient {
+	return c.derive(WithDryRun())
+}
+
+// WithDryRun makes the call a dry run, as the calls of a DryRun client are.
+func WithDryRun() RequestOption {
+	return func(o *requestOptions) {
+		o.dryRun = true
+	}
+}
+
+// DryRunError is returned by a dry-run call, with the request it prepared.
+// Middleware and transports see requests only as they are sent, so their
+// changes are not in it.
+type DryRunError struct {
+	// Request is the prepared request, credentials included. Its body, if
+	// any, is unread, so sending the request makes the call.
+	Request *http.Request
+	// Preview describes Request with its credentials redacted.
+	Preview RequestPreview
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("%s: dry run, request to %s not sent", e.Preview.Operation, e.Preview.URL)
+}
+
+// RequestPreview describes a prepared request in a form fit to log or to
+// marshal as JSON. Credential headers and query parameters are REDACTED.
+type RequestPreview struct {
+	Operation string      `json:"operation"` // "POST /v1/users"
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Header    http.Header `json:"header"`
+	// Body is the encoded body, before compression; it is empty for
+	// streamed bodies such as uploads.
+	Body string `json:"body,omitempty"`
+}
+
+// Curl returns the request as a curl command, with credentials in shell
+// variables as WithDebug writes them.
+func (p RequestPreview) Curl() string {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		u = &url.URL{Opaque: p.URL}
+	}
+	return curlCommand(&http.Request{Method: p.Method, URL: u, Header: p.Header}, []byte(p.Body))
+}
+
+// dryRun returns the *DryRunError of a dry-run call of operation, which
+// prepared req with body.
+func (c *ExampleapiClient) dryRun(operation string, req *http.Request, body []byte) error {
+	header := req.Header.Clone()
+	for name, values := range header {
+		if c.isSecretHeader(name) {
+			for i := range values {
+				values[i] = "REDACTED"
+			}
+		}
+	}
+	return &DryRunError{
+		Request: req,
+		Preview: RequestPreview{
+			Operation: operation,
+			Method:    req.Method,
+			URL:       redactQuery(req.URL).String(),
+			Header:    header,
+			Body:      string(body),
+		},
+	}
+}
@@ -0,0 +1,177 @@
+package example_api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DumpTransport writes wire-level dumps of requests and responses, as
+// produced by httputil.DumpRequestOut and httputil.DumpResponse, for
+// diagnosing encoding and header issues. Credentials are redacted and bodies
+// are capped. Dumping is off until enabled and can be toggled at runtime,
+// for example from an admin endpoint in production:
+//
+//	dump := NewDumpTransport(nil, os.Stderr)
+//	client := NewExampleapiClient("", WithDumpTransport(dump))
+//	dump.SetEnabled(true)
+type DumpTransport struct {
+	// Next performs the requests; nil means the client's transport when
+	// installed with WithDumpTransport, else http.DefaultTransport.
+	Next http.RoundTripper
+	// MaxBody caps the bytes of each body written (default 4096; negative
+	// omits bodies).
+	MaxBody int
+
+	enabled atomic.Bool
+	mu      sync.Mutex
+	out     io.Writer
+}
+
+// NewDumpTransport creates a disabled DumpTransport writing to out
+// (os.Stderr if nil).
+func NewDumpTransport(next http.RoundTripper, out io.Writer) *DumpTransport {
+	if out == nil {
+		out = os.Stderr
+	}
+	return &DumpTransport{Next: next, MaxBody: 4096, out: out}
+}
+
+// WithDumpTransport installs dump in front of the client's transport.
+func WithDumpTransport(dump *DumpTransport) ClientOption {
+	return func(c *ExampleapiClient) {
+		if dump.Next == nil {
+			dump.Next = c.HTTPClient.Transport
+		}
+		c.HTTPClient.Transport = dump
+	}
+}
+
+// SetEnabled turns dumping on or off; it is safe to call concurrently with
+// requests.
+func (d *DumpTransport) SetEnabled(enabled bool) {
+	d.enabled.Store(enabled)
+}
+
+// Enabled reports whether dumping is on.
+func (d *DumpTransport) Enabled() bool {
+	return d.enabled.Load()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (d *DumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := d.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if !d.Enabled() {
+		return next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	requestDump := d.dumpRequest(req, body)
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		d.write(requestDump, []byte(fmt.Sprintf("# error: %v\n", err)))
+		return nil, err
+	}
+	responseDump, err := d.dumpResponse(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	d.write(requestDump, responseDump)
+	return resp, nil
+}
+
+func (d *DumpTransport) dumpRequest(req *http.Request, body []byte) []byte {
+	clone := req.Clone(req.Context())
+	clone.URL = redactQuery(req.URL)
+	clone.Header = redactHeaders(req.Header)
+	shown, truncated := d.capBody(body)
+	clone.Body = io.NopCloser(bytes.NewReader(shown))
+	clone.ContentLength = int64(len(shown))
+	clone.GetBody = nil
+
+	dump, err := httputil.DumpRequestOut(clone, d.MaxBody >= 0)
+	if err != nil {
+		return []byte(fmt.Sprintf("# cannot dump request: %v\n", err))
+	}
+	return appendTruncation(dump, truncated)
+}
+
+// dumpResponse dumps the headers and up to MaxBody bytes of the body,
+// leaving the full body readable by the caller.
+func (d *DumpTransport) dumpResponse(resp *http.Response) ([]byte, error) {
+	headers := *resp
+	headers.Header = redactHeaders(resp.Header)
+	dump, err := httputil.DumpResponse(&headers, false)
+	if err != nil {
+		return []byte(fmt.Sprintf("# cannot dump response: %v\n", err)), nil
+	}
+	if d.MaxBody < 0 {
+		return dump, nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, int64(d.MaxBody)+1))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	if len(head) > d.MaxBody {
+		dump = append(dump, head[:d.MaxBody]...)
+		return append(dump, "\n... (body truncated)"...), nil
+	}
+	return append(dump, head...), nil
+}
+
+func (d *DumpTransport) capBody(body []byte) ([]byte, int) {
+	if d.MaxBody < 0 || len(body) <= d.MaxBody {
+		return body, 0
+	}
+	return body[:d.MaxBody], len(body) - d.MaxBody
+}
+
+func appendTruncation(dump []byte, truncated int) []byte {
+	if truncated > 0 {
+		dump = append(dump, fmt.Sprintf("\n... (%d bytes truncated)", truncated)...)
+	}
+	return dump
+}
+
+func (d *DumpTransport) write(request, response []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprintf(d.out, "%s\n\n%s\n\n", bytes.TrimSpace(request), bytes.TrimSpace(response))
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name, values := range redacted {
+		if isSensitiveHeader(name) {
+			for i := range values {
+				values[i] = "REDACTED"
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,118 @@
+package example_api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrEndpointDisabled is returned (wrapped) by calls to an operation the
+// client's EndpointRegistry has disabled.
+var ErrEndpointDisabled = errors.New("endpoint disabled")
+
+// EndpointRegistry records which operations ("GET /v1/users/{id}") a client
+// may call. Everything is enabled until disabled, from configuration or from
+// discovering what a particular deployment serves. It is safe to change
+// while calls are in flight; the zero value enables every operation.
+type EndpointRegistry struct {
+	mu       sync.RWMutex
+	disabled map[string]bool
+	only     map[string]bool // non-nil after EnableOnly
+}
+
+// Disable makes calls to the given operations fail with ErrEndpointDisabled.
+func (r *EndpointRegistry) Disable(operations ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabled == nil {
+		r.disabled = make(map[string]bool)
+	}
+	for _, operation := range operations {
+		r.disabled[operation] = true
+	}
+}
+
+// Enable re-enables the given operations.
+func (r *EndpointRegistry) Enable(operations ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, operation := range operations {
+		delete(r.disabled, operation)
+		if r.only != nil {
+			r.only[operation] = true
+		}
+	}
+}
+
+// EnableOnly disables every operation but the given ones, e.g. those a
+// deployment advertises. It replaces any earlier EnableOnly and clears
+// Disable.
+func (r *EndpointRegistry) EnableOnly(operations ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled = nil
+	r.only = make(map[string]bool, len(operations))
+	for _, operation := range operations {
+		r.only[operation] = true
+	}
+}
+
+// Reset enables every operation again.
+func (r *EndpointRegistry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled, r.only = nil, nil
+}
+
+// Enabled reports whether operation may be called.
+func (r *EndpointRegistry) Enabled(operation string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.disabled[operation] {
+		return false
+	}
+	return r.only == nil || r.only[operation]
+}
+
+// Disabled returns the explicitly disabled operations, sorted.
+func (r *EndpointRegistry) Disabled() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	operations := make([]string, 0, len(r.disabled))
+	for operation := range r.disabled {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// WithEndpointRegistry gates the client's calls on r, so several clients
+// can share one set of runtime toggles.
+func WithEndpointRegistry(r *EndpointRegistry) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.endpoints = r
+	}
+}
+
+// WithDisabledEndpoints disables the given operations on the client's
+// registry.
+func WithDisabledEndpoints(operations ...string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.endpoints.Disable(operations...)
+	}
+}
+
+// Endpoints returns the registry gating the client's calls. Derived clients
+// share it.
+func (c *ExampleapiClient) Endpoints() *EndpointRegistry {
+	return c.endpoints
+}
+
+// checkEndpoint fails calls to disabled operations.
+func (c *ExampleapiClient) checkEndpoint(operation string) error {
+	if !c.endpoints.Enabled(operation) {
+		return fmt.Errorf("%s: %w", operation, ErrEndpointDisabled)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package example_api
+
+// PostStatus is the status of a post, one of the values seen in the captured
+// traffic. Other values still decode, so that one the API adds later does
+// not fail calls; Valid tells them apart.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+)
+
+// Valid reports whether v is one of the known PostStatus values.
+func (v PostStatus) Valid() bool {
+	switch v {
+	case PostStatusDraft, PostStatusPublished:
+		return true
+	}
+	return false
+}
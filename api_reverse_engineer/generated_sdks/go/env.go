@@ -0,0 +1,90 @@
+package example_api
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv. Standard HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY settings are honored too, unless EnvProxy
+// overrides them.
+const (
+	EnvEnvironment = "EXAMPLEAPI_ENV"      // entry of Environments to target
+	EnvProfile     = "EXAMPLEAPI_PROFILE"  // profile to load from the profiles file
+	EnvBaseURL     = "EXAMPLEAPI_BASE_URL" // API base URL
+	EnvToken       = "EXAMPLEAPI_TOKEN"    // bearer token
+	EnvAPIKey      = "EXAMPLEAPI_API_KEY"  // API key, sent as the captured requests sent theirs
+	EnvTimeout     = "EXAMPLEAPI_TIMEOUT"  // Go duration, or whole seconds
+	EnvProxy       = "EXAMPLEAPI_PROXY"    // proxy URL for all requests
+)
+
+// EnvOptions returns client options for the configuration in the
+// environment, starting with the environment named by EnvEnvironment and
+// the profile named by EnvProfile, if any.
+func EnvOptions() ([]ClientOption, error) {
+	var opts []ClientOption
+	if name := os.Getenv(EnvEnvironment); name != "" {
+		envOpts, err := EnvironmentOptions(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvEnvironment, err)
+		}
+		opts = append(opts, envOpts...)
+	}
+	if name := os.Getenv(EnvProfile); name != "" {
+		profiles, err := LoadProfiles("")
+		if err != nil {
+			return nil, err
+		}
+		profileOpts, err := profiles.Options(name)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, profileOpts...)
+	}
+
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		baseURL = strings.TrimSuffix(baseURL, "/")
+		opts = append(opts, func(c *ExampleapiClient) { c.BaseURL = baseURL })
+	}
+	if token := os.Getenv(EnvToken); token != "" {
+		opts = append(opts, func(c *ExampleapiClient) { c.SetAuthToken(token) })
+	}
+	if key := os.Getenv(EnvAPIKey); key != "" {
+		opts = append(opts, func(c *ExampleapiClient) { c.SetAPIKey(key) })
+	}
+	if value := os.Getenv(EnvTimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if seconds, convErr := strconv.Atoi(value); convErr == nil {
+			timeout, err = time.Duration(seconds)*time.Second, nil
+		}
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("%s: invalid timeout %q", EnvTimeout, value)
+		}
+		opts = append(opts, WithTotalTimeout(timeout))
+	}
+	if value := os.Getenv(EnvProxy); value != "" {
+		proxyURL, err := url.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvProxy, err)
+		}
+		opts = append(opts, WithProxy(proxyURL))
+	}
+	return opts, nil
+}
+
+// NewClientFromEnv creates a client configured from the environment, for
+// twelve-factor deployments. Settings apply in order of increasing
+// precedence: the built-in defaults, the environment named by
+// EnvEnvironment, the profile named by EnvProfile, the other Env*
+// variables, then opts.
+func NewClientFromEnv(opts ...ClientOption) (*ExampleapiClient, error) {
+	envOpts, err := EnvOptions()
+	if err != nil {
+		return nil, err
+	}
+	return NewExampleapiClient("", append(envOpts, opts...)...), nil
+}
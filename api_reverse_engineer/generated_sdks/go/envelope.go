@@ -0,0 +1,52 @@
+package example_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// responseEnvelopes maps the operations whose responses wrap their payload
+// in an envelope, next to metadata such as {"data": ..., "meta": ...}, to
+// the field holding the payload. Their methods return the payload; the
+// WithMeta variants return the other fields too.
+var responseEnvelopes = map[string]string{}
+
+// envelopePayload returns the payload of an enveloped response body, or
+// body itself when the operation's responses are not enveloped or this one
+// is not a JSON object. A missing payload is empty.
+func envelopePayload(operation string, body []byte) []byte {
+	field, ok := responseEnvelopes[operation]
+	if !ok {
+		return body
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body
+	}
+	return envelope[field]
+}
+
+// recordEnvelope stores the fields of the call's response envelope besides
+// its payload in meta, a pointer to the operation's Meta struct.
+func recordEnvelope(meta interface{}) RequestOption {
+	return func(o *requestOptions) {
+		o.envelope = meta
+	}
+}
+
+// decodeEnvelopeMeta decodes the fields of an enveloped response body into
+// meta, which has no field for the payload. Like decode, it leaves fields
+// of an unexpected type at their zero value.
+func (c *ExampleapiClient) decodeEnvelopeMeta(operation string, body []byte, meta interface{}) error {
+	if _, ok := responseEnvelopes[operation]; !ok || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	err := json.Unmarshal(body, meta)
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return err
+	}
+	c.applyTimeLayouts(operation, meta)
+	return nil
+}
@@ -0,0 +1,67 @@
+package example_api
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Environment is a deployment of the API.
+type Environment struct {
+	BaseURL string
+	// TokenEnv names the environment variable holding the environment's
+	// token or API key, sent when the variable is set.
+	TokenEnv string
+}
+
+// Environments are the deployments of the API, by name: those the captured
+// traffic reached, as told apart by their hosts. Add or change entries
+// before creating clients to reach others.
+var Environments = map[string]Environment{
+	"production": {BaseURL: "https://api.example.com", TokenEnv: "EXAMPLEAPI_TOKEN"},
+}
+
+// DefaultEnvironment is the environment clients created without a base URL
+// use.
+const DefaultEnvironment = "production"
+
+// EnvironmentNames returns the names of Environments, sorted.
+func EnvironmentNames() []string {
+	names := make([]string, 0, len(Environments))
+	for name := range Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewExampleapiClientForEnv creates a client for the named entry of
+// Environments, sending the credentials in its TokenEnv variable if set.
+// Options apply after the environment's settings:
+//
+//	client, err := NewExampleapiClientForEnv("staging", WithTotalTimeout(10*time.Second))
+func NewExampleapiClientForEnv(name string, opts ...ClientOption) (*ExampleapiClient, error) {
+	envOpts, err := EnvironmentOptions(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewExampleapiClient("", append(envOpts, opts...)...), nil
+}
+
+// EnvironmentOptions returns the client options for the named entry of
+// Environments.
+func EnvironmentOptions(name string) ([]ClientOption, error) {
+	env, ok := Environments[name]
+	if !ok {
+		return nil, fmt.Errorf("environment %q not found (have %v)", name, EnvironmentNames())
+	}
+	baseURL := strings.TrimSuffix(env.BaseURL, "/")
+	opts := []ClientOption{func(c *ExampleapiClient) { c.BaseURL = baseURL }}
+	if env.TokenEnv != "" {
+		if credential := os.Getenv(env.TokenEnv); credential != "" {
+			opts = append(opts, func(c *ExampleapiClient) { c.SetAPIKey(credential) })
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,294 @@
+package example_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// APIError is returned for responses with an error status. Its message is
+// the one registered with WithErrorMessages for the upstream error code or
+// status, in the request's locale, when there is one; the vendor's own
+// error is kept in Code, Message, Payload, and Body.
+//
+// Branch on the status with errors.Is and the sentinels below, or the Is
+// helpers, rather than on the message:
+//
+//	user, err := client.GetUser(ctx, id)
+//	switch {
+//	case IsNotFound(err):
+//		return nil, nil
+//	case errors.Is(err, ErrConflict):
+//		return nil, retryLater(err)
+//	}
+//
+// Use errors.As to read the fields.
+type APIError struct {
+	Operation  string // "GET /v1/users/{id}"
+	StatusCode int
+	Code       string // the vendor's error code, when the body has one
+	Message    string // the vendor's error message, when the body has one
+	RequestID  string // the server's request ID header, for support requests
+	Header     http.Header
+	Body       []byte
+	// Payload is the body decoded, when it is a JSON object.
+	Payload map[string]interface{}
+
+	userMessage string
+}
+
+// Sentinels an *APIError matches with errors.Is, by status code.
+var (
+	ErrBadRequest   = errors.New("bad request")       // 400
+	ErrUnauthorized = errors.New("unauthorized")      // 401
+	ErrForbidden    = errors.New("forbidden")         // 403
+	ErrNotFound     = errors.New("not found")         // 404
+	ErrConflict     = errors.New("conflict")          // 409
+	ErrValidation   = errors.New("validation failed") // 422
+	ErrRateLimited  = errors.New("rate limited")      // 429
+	ErrServer       = errors.New("server error")      // 5xx
+)
+
+// statusSentinels maps the status codes with a sentinel to it.
+var statusSentinels = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusUnprocessableEntity: ErrValidation,
+	http.StatusTooManyRequests:     ErrRateLimited,
+}
+
+// Is reports whether target is the sentinel for e's status code.
+func (e *APIError) Is(target error) bool {
+	if e.StatusCode >= 500 {
+		return target == ErrServer
+	}
+	sentinel, ok := statusSentinels[e.StatusCode]
+	return ok && target == sentinel
+}
+
+// Temporary reports whether the call may succeed if made again: the API
+// was rate limited or failed on its side.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is an API error with status 429.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsServerError reports whether err is an API error with a 5xx status.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServer)
+}
+
+// StatusCode returns the status code of the API error in err's chain, or 0
+// if there is none.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func (e *APIError) Error() string {
+	if e.userMessage != "" {
+		return e.userMessage
+	}
+	return e.Detail()
+}
+
+// Detail describes the error as the API reported it, for logs.
+func (e *APIError) Detail() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("API error: status=%d, request_id=%s, body=%s", e.StatusCode, e.RequestID, string(e.Body))
+	}
+	return fmt.Sprintf("API error: status=%d, body=%s", e.StatusCode, string(e.Body))
+}
+
+// errorCodeFields and errorMessageFields are the body fields read as the
+// vendor's error code and message, at the top level or in an "error"
+// object or the first element of an "errors" array.
+var (
+	errorCodeFields    = []string{"code", "error_code", "errorCode", "type", "error"}
+	errorMessageFields = []string{"message", "error_description", "errorMessage", "detail", "title", "error"}
+)
+
+// newAPIError builds the error for a response, localizing it with
+// messages for locale, an Accept-Language value. header may be nil.
+func newAPIError(operation string, status int, header http.Header, body []byte, messages *ErrorMessages, locale string) *APIError {
+	e := &APIError{Operation: operation, StatusCode: status, Header: header, RequestID: requestID(header), Body: body}
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil && payload != nil {
+		e.Payload = payload
+		objects := []map[string]interface{}{payload}
+		if nested, ok := payload["error"].(map[string]interface{}); ok {
+			objects = append(objects, nested)
+		}
+		if list, ok := payload["errors"].([]interface{}); ok && len(list) > 0 {
+			if first, ok := list[0].(map[string]interface{}); ok {
+				objects = append(objects, first)
+			}
+		}
+		for _, object := range objects {
+			if e.Code == "" {
+				e.Code = firstScalar(object, errorCodeFields)
+			}
+			if e.Message == "" {
+				e.Message = firstScalar(object, errorMessageFields)
+			}
+		}
+	}
+	if message, ok := messages.lookup(e.Code, status, locale); ok {
+		e.userMessage = message
+	}
+	return e
+}
+
+func firstScalar(object map[string]interface{}, fields []string) string {
+	for _, field := range fields {
+		switch value := object[field].(type) {
+		case string:
+			if value != "" {
+				return value
+			}
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// ErrorMessages maps upstream error codes to user-facing messages per
+// locale, so products embedding the SDK do not show vendor error strings to
+// end users. Keys are the vendor's error codes, HTTP status codes ("404"),
+// or "" for the message of errors nothing else matches. It is safe for
+// concurrent use.
+//
+//	messages := NewErrorMessages().
+//		Register("card_declined", "en", "Your card was declined.").
+//		Register("card_declined", "de", "Ihre Karte wurde abgelehnt.").
+//		Register("", "en", "Something went wrong. Please try again.")
+//	client := NewExampleapiClient("", WithErrorMessages(messages))
+type ErrorMessages struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string // code, then lowercase locale
+}
+
+// NewErrorMessages creates an empty table.
+func NewErrorMessages() *ErrorMessages {
+	return &ErrorMessages{messages: make(map[string]map[string]string)}
+}
+
+// LoadErrorMessages reads a table from a JSON file mapping codes to
+// messages by locale: {"card_declined": {"en": "...", "de": "..."}}.
+func LoadErrorMessages(path string) (*ErrorMessages, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var table map[string]map[string]string
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m := NewErrorMessages()
+	for code, messages := range table {
+		for locale, message := range messages {
+			m.Register(code, locale, message)
+		}
+	}
+	return m, nil
+}
+
+// Register sets the message for code in locale; an empty locale is the
+// fallback for locales without their own message.
+func (m *ErrorMessages) Register(code, locale, message string) *ErrorMessages {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.messages[code] == nil {
+		m.messages[code] = make(map[string]string)
+	}
+	m.messages[code][strings.ToLower(locale)] = message
+	return m
+}
+
+// Lookup returns the message for code in the first locale of
+// acceptLanguage (such as "fr-CA, fr;q=0.8") that has one, trying each tag,
+// then its language alone, then the fallback.
+func (m *ErrorMessages) Lookup(code, acceptLanguage string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	messages, ok := m.messages[code]
+	if !ok {
+		return "", false
+	}
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+		if tag == "" {
+			continue
+		}
+		if message, ok := messages[tag]; ok {
+			return message, true
+		}
+		language, _, _ := strings.Cut(tag, "-")
+		if message, ok := messages[language]; ok {
+			return message, true
+		}
+	}
+	message, ok := messages[""]
+	return message, ok
+}
+
+// lookup finds the message for an error by its code, then its status, then
+// the catch-all entry.
+func (m *ErrorMessages) lookup(code string, status int, locale string) (string, bool) {
+	if code != "" {
+		if message, ok := m.Lookup(code, locale); ok {
+			return message, true
+		}
+	}
+	if message, ok := m.Lookup(strconv.Itoa(status), locale); ok {
+		return message, true
+	}
+	return m.Lookup("", locale)
+}
+
+// WithErrorMessages formats the client's API errors with messages.
+func WithErrorMessages(messages *ErrorMessages) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.errorMessages = messages
+	}
+}
@@ -0,0 +1,286 @@
+package example_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/example/example_api/export"
+)
+
+// ExportOptions adjusts an export.
+type ExportOptions struct {
+	// Columns selects and orders columns by name, or by dotted prefix for
+	// nested objects; empty means every column.
+	Columns []string
+	// PageSize is the number of items to request per page; zero means the
+	// page size seen in the captured traffic, or the API's default.
+	PageSize int
+	// MaxRows stops the export after this many rows; zero means all.
+	MaxRows int
+	// RequestOptions apply to every page request.
+	RequestOptions []RequestOption
+}
+
+// exportSpec describes how a list endpoint pages and what its items hold.
+// An endpoint with no page or offset parameter and no cursor to follow is
+// read in one request.
+type exportSpec struct {
+	endpoint    string
+	items       string // the field holding the items; empty for a bare array
+	pageParam   string // 1-based page number
+	offsetParam string
+	limitParam  string
+	pageSize    int
+	moreField   string // a boolean saying whether more pages follow
+	totalField  string // the total number of items
+	cursorParam string // takes the cursor of the next page
+	nextField   string // dotted path of the next page's cursor, or URL
+	nextLink    bool   // the Link header gives the next page's URL
+	afterField  string // the field of the last item that is the next cursor
+	columns     []export.Column
+}
+
+// exportSpecs are the captured list endpoints, with columns derived from
+// the fields of their items.
+var exportSpecs = map[string]exportSpec{
+	"GET /v1/posts": {
+		endpoint:   "/v1/posts",
+		items:      "posts",
+		totalField: "total",
+		columns: []export.Column{
+			{Name: "id", Path: []string{"id"}, Type: export.String},
+			{Name: "author_id", Path: []string{"author_id"}, Type: export.Integer},
+			{Name: "content", Path: []string{"content"}, Type: export.String},
+			{Name: "likes", Path: []string{"likes"}, Type: export.Integer},
+			{Name: "published_at", Path: []string{"published_at"}, Type: export.Timestamp},
+			{Name: "tags", Path: []string{"tags"}, Type: export.JSON},
+			{Name: "title", Path: []string{"title"}, Type: export.String},
+			{Name: "views", Path: []string{"views"}, Type: export.Integer},
+		},
+	},
+	"GET /v1/users": {
+		endpoint:   "/v1/users",
+		items:      "users",
+		pageParam:  "page",
+		limitParam: "limit",
+		totalField: "total",
+		pageSize:   10,
+		columns: []export.Column{
+			{Name: "id", Path: []string{"id"}, Type: export.Integer},
+			{Name: "created_at", Path: []string{"created_at"}, Type: export.Timestamp},
+			{Name: "email", Path: []string{"email"}, Type: export.String},
+			{Name: "is_active", Path: []string{"is_active"}, Type: export.Boolean},
+			{Name: "name", Path: []string{"name"}, Type: export.String},
+		},
+	},
+}
+
+// ExportColumns returns the columns an export of operation, such as
+// "GET /v1/users", writes, or nil if the operation has no export.
+func ExportColumns(operation string) []export.Column {
+	return append([]export.Column(nil), exportSpecs[operation].columns...)
+}
+
+// ExportPosts writes every item of GET /v1/posts to w, in one request, then
+// closes w. It returns the number of rows written.
+func (c *ExampleapiClient) ExportPosts(ctx context.Context, w export.Writer, opts ExportOptions) (int, error) {
+	return c.export(ctx, exportSpecs["GET /v1/posts"], w, opts)
+}
+
+// ExportUsers writes every item of GET /v1/users to w, page by page, then
+// closes w. It returns the number of rows written.
+func (c *ExampleapiClient) ExportUsers(ctx context.Context, w export.Writer, opts ExportOptions) (int, error) {
+	return c.export(ctx, exportSpecs["GET /v1/users"], w, opts)
+}
+
+// export writes every item of spec's endpoint to w, then closes w. It
+// returns the number of rows written.
+func (c *ExampleapiClient) export(ctx context.Context, spec exportSpec, w export.Writer, opts ExportOptions) (int, error) {
+	columns := spec.columns
+	if len(opts.Columns) > 0 {
+		var err error
+		if columns, err = export.Select(columns, opts.Columns...); err != nil {
+			return 0, err
+		}
+	}
+	if err := w.WriteHeader(columns); err != nil {
+		return 0, err
+	}
+	rows := 0
+	err := c.listPages(ctx, spec, opts.PageSize, nil, opts.RequestOptions, func(items []map[string]interface{}) (bool, error) {
+		for _, item := range items {
+			if opts.MaxRows > 0 && rows >= opts.MaxRows {
+				return false, nil
+			}
+			if err := w.WriteRow(export.Row(columns, item)); err != nil {
+				return false, err
+			}
+			rows++
+		}
+		return opts.MaxRows <= 0 || rows < opts.MaxRows, nil
+	})
+	if err != nil {
+		return rows, fmt.Errorf("export: %w", err)
+	}
+	return rows, w.Close()
+}
+
+// listPages requests spec's endpoint with params page after page, passing
+// each page's items to fn, until the API reports no more or fn returns
+// false. A zero pageSize means the captured page size.
+func (c *ExampleapiClient) listPages(ctx context.Context, spec exportSpec, pageSize int, params url.Values, opts []RequestOption, fn func(items []map[string]interface{}) (bool, error)) error {
+	if pageSize <= 0 {
+		pageSize = spec.pageSize
+	}
+	seen := 0
+	var previous, cursor string
+	for page := 1; ; page++ {
+		query := url.Values{}
+		for name, values := range params {
+			query[name] = values
+		}
+		if spec.limitParam != "" && pageSize > 0 {
+			query.Set(spec.limitParam, strconv.Itoa(pageSize))
+		}
+		if spec.pageParam != "" {
+			query.Set(spec.pageParam, strconv.Itoa(page))
+		} else if spec.offsetParam != "" {
+			query.Set(spec.offsetParam, strconv.Itoa(seen))
+		}
+		if cursor != "" {
+			setCursor(query, spec.cursorParam, cursor)
+		}
+		var meta ResponseMeta
+		body, err := c.doRequest(ctx, "GET", spec.endpoint, spec.endpoint, query, nil, append(opts[:len(opts):len(opts)], recordMeta(&meta))...)
+		if err != nil {
+			return fmt.Errorf("%s page %d: %w", spec.endpoint, page, err)
+		}
+		items, envelope, err := parseExportPage(body, spec.items)
+		if err != nil {
+			return fmt.Errorf("%s page %d: %w", spec.endpoint, page, err)
+		}
+		if len(items) > 0 {
+			// An API ignoring the paging parameters would repeat its first
+			// page forever.
+			first, _ := json.Marshal(items[0])
+			if page > 1 && string(first) == previous {
+				return fmt.Errorf("%s: page %d repeats page %d", spec.endpoint, page, page-1)
+			}
+			previous = string(first)
+		}
+		next, err := fn(items)
+		if err != nil || !next {
+			return err
+		}
+		seen += len(items)
+		cursor = spec.nextCursor(meta.Header, envelope, items)
+		if !spec.more(envelope, len(items), seen, pageSize, cursor) {
+			return nil
+		}
+	}
+}
+
+// more reports whether another page follows one of got items, seen in
+// total, given the next page's cursor: as the response says, or else while
+// pages come back full.
+func (s exportSpec) more(envelope map[string]interface{}, got, seen, pageSize int, cursor string) bool {
+	cursored := s.nextField != "" || s.nextLink || s.afterField != ""
+	if (s.pageParam == "" && s.offsetParam == "" && !cursored) || got == 0 || (cursored && cursor == "") {
+		return false
+	}
+	if more, ok := envelope[s.moreField].(bool); ok {
+		return more
+	}
+	if total, ok := envelope[s.totalField].(json.Number); ok {
+		if n, err := total.Int64(); err == nil {
+			return int64(seen) < n
+		}
+	}
+	if s.nextField != "" || s.nextLink {
+		return true
+	}
+	return pageSize <= 0 || got >= pageSize
+}
+
+// nextCursor returns the cursor of the page after one answered with header,
+// envelope, and items, or "" if there is none.
+func (s exportSpec) nextCursor(header http.Header, envelope map[string]interface{}, items []map[string]interface{}) string {
+	switch {
+	case s.nextField != "":
+		var value interface{} = envelope
+		for _, name := range strings.Split(s.nextField, ".") {
+			object, _ := value.(map[string]interface{})
+			value = object[name]
+		}
+		cursor, _ := value.(string)
+		return cursor
+	case s.nextLink:
+		return nextLink(header)
+	case s.afterField != "" && len(items) > 0:
+		if value := items[len(items)-1][s.afterField]; value != nil {
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// linkNextPattern matches the URL of the next page in a Link header.
+var linkNextPattern = regexp.MustCompile(`<([^>]*)>\s*;[^,]*\brel="?next"?`)
+
+// nextLink returns the URL of the next page in header's Link, or "".
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		if match := linkNextPattern.FindStringSubmatch(value); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// setCursor sends the cursor of the next page with query: the query
+// parameters of a next-page URL, or else cursor as param.
+func setCursor(query url.Values, param, cursor string) {
+	if link, err := url.Parse(cursor); err == nil && (link.IsAbs() || strings.HasPrefix(cursor, "/")) {
+		for name, values := range link.Query() {
+			query[name] = values
+		}
+		return
+	}
+	query.Set(param, cursor)
+}
+
+// parseExportPage decodes a page's items and, for an enveloped list, the
+// envelope's other fields. Numbers stay json.Number so that large ids keep
+// their precision.
+func parseExportPage(body []byte, field string) ([]map[string]interface{}, map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var page interface{}
+	if err := decoder.Decode(&page); err != nil {
+		return nil, nil, err
+	}
+	var envelope map[string]interface{}
+	list := page
+	if field != "" {
+		envelope, _ = page.(map[string]interface{})
+		list = envelope[field]
+	}
+	values, ok := list.([]interface{})
+	if !ok && list != nil {
+		return nil, nil, fmt.Errorf("response has no %q array", field)
+	}
+	items := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		if item, ok := value.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	return items, envelope, nil
+}
@@ -0,0 +1,230 @@
+// Package export writes API list items as rows of a table, for loading into
+// spreadsheets, dataframes, and warehouses. Generated SDKs derive each list
+// endpoint's columns from the fields of its items and stream every page into
+// a Writer:
+//
+//	f, _ := os.Create("users.parquet")
+//	defer f.Close()
+//	n, err := client.ExportUsers(ctx, export.NewParquet(f), ExportOptions{})
+//
+// Nested objects become dotted columns ("profile.bio"); arrays and objects
+// without known fields are written as JSON text.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a column's values.
+type Type int
+
+const (
+	String    Type = iota // string
+	Integer               // int64
+	Number                // float64
+	Boolean               // bool
+	Timestamp             // time.Time, from an RFC 3339 string
+	JSON                  // string holding the value's JSON encoding
+)
+
+func (t Type) String() string {
+	switch t {
+	case Integer:
+		return "integer"
+	case Number:
+		return "number"
+	case Boolean:
+		return "boolean"
+	case Timestamp:
+		return "timestamp"
+	case JSON:
+		return "json"
+	}
+	return "string"
+}
+
+// Column is one column of an export: the field at Path in each item.
+type Column struct {
+	Name string   // header, such as "profile.bio"
+	Path []string // JSON field names from the item down to the value
+	Type Type
+}
+
+// Writer receives an export: the columns, then one row per item whose
+// values are nil or of the Go type listed for the column's Type, then Close.
+// Close finishes the output but does not close the underlying io.Writer.
+type Writer interface {
+	WriteHeader(columns []Column) error
+	WriteRow(row []interface{}) error
+	Close() error
+}
+
+// Select returns the columns named by names, in that order. A name may also
+// be a dotted prefix, selecting every column below it ("profile" selects
+// "profile.bio" and "profile.avatar").
+func Select(columns []Column, names ...string) ([]Column, error) {
+	var selected []Column
+	for _, name := range names {
+		found := false
+		for _, column := range columns {
+			if column.Name == name || strings.HasPrefix(column.Name, name+".") {
+				selected = append(selected, column)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("export: no column %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// Row extracts the columns' values from item, a JSON object decoded with
+// json.Decoder.UseNumber. Missing fields, nulls, and values that do not
+// convert to a column's type are nil.
+func Row(columns []Column, item map[string]interface{}) []interface{} {
+	row := make([]interface{}, len(columns))
+	for i, column := range columns {
+		row[i] = convert(lookup(item, column.Path), column.Type)
+	}
+	return row
+}
+
+func lookup(item map[string]interface{}, path []string) interface{} {
+	var value interface{} = item
+	for _, name := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+func convert(value interface{}, typ Type) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch typ {
+	case Integer:
+		switch v := value.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return n
+			}
+			if f, err := v.Float64(); err == nil && f == float64(int64(f)) {
+				return int64(f)
+			}
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	case Number:
+		switch v := value.(type) {
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f
+			}
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+		return nil
+	case Boolean:
+		switch v := value.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+		return nil
+	case Timestamp:
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t
+			}
+		}
+		return nil
+	case String:
+		switch v := value.(type) {
+		case string:
+			return v
+		case json.Number:
+			return v.String()
+		case bool:
+			return strconv.FormatBool(v)
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+// CSVWriter writes an export as CSV with a header line. Nulls are empty
+// cells and timestamps are RFC 3339.
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSV returns a Writer producing CSV on w.
+func NewCSV(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Comma sets the field delimiter, such as '\t' for TSV.
+func (c *CSVWriter) Comma(r rune) *CSVWriter {
+	c.w.Comma = r
+	return c
+}
+
+// WriteHeader implements Writer.
+func (c *CSVWriter) WriteHeader(columns []Column) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	return c.w.Write(header)
+}
+
+// WriteRow implements Writer.
+func (c *CSVWriter) WriteRow(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case time.Time:
+			record[i] = v.Format(time.RFC3339Nano)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+// Close implements Writer, flushing buffered rows.
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,381 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// DefaultRowGroupRows is the number of rows a ParquetWriter buffers before
+// writing them out as a row group.
+const DefaultRowGroupRows = 50000
+
+// ParquetWriter writes an export as a Parquet file of optional columns,
+// PLAIN-encoded and uncompressed so that it needs no dependencies; any
+// Parquet reader loads it. Integers are INT64, numbers DOUBLE, strings and
+// JSON UTF-8 BYTE_ARRAY, and timestamps INT64 microseconds since the epoch
+// in UTC.
+type ParquetWriter struct {
+	// RowGroupRows overrides DefaultRowGroupRows; larger groups compress
+	// better in later processing but hold more rows in memory.
+	RowGroupRows int
+
+	w         *countingWriter
+	columns   []Column
+	buffered  [][]interface{}
+	rowGroups []parquetRowGroup
+	rows      int64
+}
+
+// NewParquet returns a Writer producing a Parquet file on w.
+func NewParquet(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{w: &countingWriter{w: w}}
+}
+
+// WriteHeader implements Writer, writing the file's magic number.
+func (p *ParquetWriter) WriteHeader(columns []Column) error {
+	if p.columns != nil {
+		return errors.New("export: header already written")
+	}
+	p.columns = columns
+	p.buffered = make([][]interface{}, len(columns))
+	_, err := p.w.Write([]byte("PAR1"))
+	return err
+}
+
+// WriteRow implements Writer.
+func (p *ParquetWriter) WriteRow(row []interface{}) error {
+	if p.columns == nil {
+		return errors.New("export: row written before header")
+	}
+	for i := range p.columns {
+		var value interface{}
+		if i < len(row) {
+			value = row[i]
+		}
+		p.buffered[i] = append(p.buffered[i], value)
+	}
+	limit := p.RowGroupRows
+	if limit <= 0 {
+		limit = DefaultRowGroupRows
+	}
+	if len(p.buffered[0]) >= limit {
+		return p.flush()
+	}
+	return nil
+}
+
+// Close implements Writer, writing the last row group and the footer.
+func (p *ParquetWriter) Close() error {
+	if p.columns == nil {
+		if err := p.WriteHeader(nil); err != nil {
+			return err
+		}
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	footer := p.footer()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	for _, data := range [][]byte{footer, length[:], []byte("PAR1")} {
+		if _, err := p.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type parquetRowGroup struct {
+	rows    int64
+	size    int64
+	columns []parquetChunk
+}
+
+type parquetChunk struct {
+	offset int64 // of the data page header
+	values int64
+	size   int64
+}
+
+// flush writes the buffered rows as a row group of one data page per
+// column.
+func (p *ParquetWriter) flush() error {
+	if len(p.columns) == 0 || len(p.buffered[0]) == 0 {
+		return nil
+	}
+	group := parquetRowGroup{rows: int64(len(p.buffered[0]))}
+	for i, column := range p.columns {
+		page := encodePage(column.Type, p.buffered[i])
+		var header thriftWriter
+		header.i32(1, 0) // type: DATA_PAGE
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.beginStruct(5) // data_page_header
+		header.i32(1, int32(len(p.buffered[i])))
+		header.i32(2, 0) // encoding: PLAIN
+		header.i32(3, 3) // definition_level_encoding: RLE
+		header.i32(4, 3) // repetition_level_encoding: RLE
+		header.endStruct()
+		header.endStruct()
+
+		chunk := parquetChunk{offset: p.w.n, values: int64(len(p.buffered[i]))}
+		for _, data := range [][]byte{header.buf.Bytes(), page} {
+			if _, err := p.w.Write(data); err != nil {
+				return err
+			}
+		}
+		chunk.size = p.w.n - chunk.offset
+		group.size += chunk.size
+		group.columns = append(group.columns, chunk)
+		p.buffered[i] = p.buffered[i][:0]
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.rows += group.rows
+	return nil
+}
+
+// encodePage encodes values as a version 1 data page body: the definition
+// levels (1 for a value, 0 for null) in the RLE hybrid encoding, then the
+// non-null values PLAIN-encoded.
+func encodePage(typ Type, values []interface{}) []byte {
+	var levels bytes.Buffer
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == defined {
+			run++
+		}
+		writeUvarint(&levels, uint64(run)<<1)
+		if defined {
+			levels.WriteByte(1)
+		} else {
+			levels.WriteByte(0)
+		}
+		i += run
+	}
+
+	var page bytes.Buffer
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(levels.Len()))
+	page.Write(length[:])
+	page.Write(levels.Bytes())
+
+	var bits, nbits byte
+	var word [8]byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case bool:
+			if v {
+				bits |= 1 << nbits
+			}
+			if nbits++; nbits == 8 {
+				page.WriteByte(bits)
+				bits, nbits = 0, 0
+			}
+		case int64:
+			binary.LittleEndian.PutUint64(word[:], uint64(v))
+			page.Write(word[:])
+		case float64:
+			binary.LittleEndian.PutUint64(word[:], math.Float64bits(v))
+			page.Write(word[:])
+		case time.Time:
+			binary.LittleEndian.PutUint64(word[:], uint64(v.UnixMicro()))
+			page.Write(word[:])
+		case string:
+			binary.LittleEndian.PutUint32(length[:], uint32(len(v)))
+			page.Write(length[:])
+			page.WriteString(v)
+		}
+	}
+	if typ == Boolean && nbits > 0 {
+		page.WriteByte(bits)
+	}
+	return page.Bytes()
+}
+
+// Parquet physical and converted types.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetJSON            = 19
+)
+
+func physicalType(typ Type) int32 {
+	switch typ {
+	case Integer, Timestamp:
+		return parquetInt64
+	case Number:
+		return parquetDouble
+	case Boolean:
+		return parquetBoolean
+	}
+	return parquetByteArray
+}
+
+// footer encodes the FileMetaData.
+func (p *ParquetWriter) footer() []byte {
+	var t thriftWriter
+	t.i32(1, 1) // version
+	t.beginList(2, thriftStruct, len(p.columns)+1)
+	t.beginElement() // the root schema element groups the columns
+	t.str(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.endStruct()
+	for _, column := range p.columns {
+		t.beginElement()
+		t.i32(1, physicalType(column.Type))
+		t.i32(3, 1) // repetition_type: OPTIONAL
+		t.str(4, column.Name)
+		switch column.Type {
+		case String:
+			t.i32(6, parquetUTF8)
+		case JSON:
+			t.i32(6, parquetJSON)
+		case Timestamp:
+			t.i32(6, parquetTimestampMicros)
+		}
+		t.endStruct()
+	}
+	t.i64(3, p.rows)
+	t.beginList(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		t.beginElement()
+		t.beginList(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			column := p.columns[i]
+			t.beginElement()
+			t.i64(2, chunk.offset)
+			t.beginStruct(3) // meta_data
+			t.i32(1, physicalType(column.Type))
+			t.beginList(2, thriftI32, 2)
+			t.varint(0) // PLAIN
+			t.varint(3) // RLE
+			t.beginList(3, thriftBinary, 1)
+			t.bytes(column.Name)
+			t.i32(4, 0) // codec: UNCOMPRESSED
+			t.i64(5, chunk.values)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, group.size)
+		t.i64(3, group.rows)
+		t.endStruct()
+	}
+	t.str(6, "export")
+	t.endStruct()
+	return t.buf.Bytes()
+}
+
+// thriftWriter encodes structs in the Thrift compact protocol, which
+// Parquet uses for its page headers and footer. The top-level struct is
+// implicit; end it with endStruct.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // the previous field id of each enclosing struct
+	prev int16
+}
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.prev; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.prev = id
+}
+
+func (t *thriftWriter) varint(v int64) {
+	writeUvarint(&t.buf, uint64(v<<1)^uint64(v>>63))
+}
+
+func (t *thriftWriter) bytes(s string) {
+	writeUvarint(&t.buf, uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) str(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.bytes(s)
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.beginElement()
+}
+
+// beginElement opens a struct without a field header, as the elements of
+// a list are.
+func (t *thriftWriter) beginElement() {
+	t.last = append(t.last, t.prev)
+	t.prev = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	if n := len(t.last); n > 0 {
+		t.prev = t.last[n-1]
+		t.last = t.last[:n-1]
+	}
+}
+
+// beginList starts a list field of size elements of type elem, written
+// next: structs with beginElement, integers with varint, strings with bytes.
+func (t *thriftWriter) beginList(id int16, elem byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xf0 | elem)
+		writeUvarint(&t.buf, uint64(size))
+	}
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	buf.Write(scratch[:binary.PutUvarint(scratch[:], v)])
+}
+
+// countingWriter tracks the offset that Parquet metadata refers to.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,183 @@
+package example_api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/example/example_api/fixtures"
+)
+
+// FixtureFactory builds populated request and response values for tests
+// from the value distributions learned from captured traffic. The same seed
+// always yields the same values; a FixtureFactory is not safe for
+// concurrent use.
+type FixtureFactory struct {
+	gen *fixtures.Generator
+}
+
+// NewFixtureFactory creates a FixtureFactory seeded with seed.
+func NewFixtureFactory(seed int64) *FixtureFactory {
+	return &FixtureFactory{gen: fixtures.New(seed)}
+}
+
+func (f *FixtureFactory) fill(body json.RawMessage, err error, v interface{}) {
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: %v", err))
+	}
+}
+
+// ListPostsResponse returns a populated ListPostsResponse for GET /v1/posts, with
+// overrides applied in order.
+func (f *FixtureFactory) ListPostsResponse(overrides ...func(*ListPostsResponse)) *ListPostsResponse {
+	v := &ListPostsResponse{}
+	body, err := f.gen.ResponseFor("GET /v1/posts", 200)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewListPostsResponseFixture returns a ListPostsResponse populated with seed 1.
+func NewListPostsResponseFixture(overrides ...func(*ListPostsResponse)) *ListPostsResponse {
+	return NewFixtureFactory(1).ListPostsResponse(overrides...)
+}
+
+// CreatePostRequest returns a populated CreatePostRequest for POST /v1/posts, with
+// overrides applied in order.
+func (f *FixtureFactory) CreatePostRequest(overrides ...func(*CreatePostRequest)) *CreatePostRequest {
+	v := &CreatePostRequest{}
+	body, err := f.gen.Request("POST /v1/posts")
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewCreatePostRequestFixture returns a CreatePostRequest populated with seed 1.
+func NewCreatePostRequestFixture(overrides ...func(*CreatePostRequest)) *CreatePostRequest {
+	return NewFixtureFactory(1).CreatePostRequest(overrides...)
+}
+
+// CreatePostResponse returns a populated CreatePostResponse for POST /v1/posts, with
+// overrides applied in order.
+func (f *FixtureFactory) CreatePostResponse(overrides ...func(*CreatePostResponse)) *CreatePostResponse {
+	v := &CreatePostResponse{}
+	body, err := f.gen.ResponseFor("POST /v1/posts", 201)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewCreatePostResponseFixture returns a CreatePostResponse populated with seed 1.
+func NewCreatePostResponseFixture(overrides ...func(*CreatePostResponse)) *CreatePostResponse {
+	return NewFixtureFactory(1).CreatePostResponse(overrides...)
+}
+
+// ListUsersResponse returns a populated ListUsersResponse for GET /v1/users, with
+// overrides applied in order.
+func (f *FixtureFactory) ListUsersResponse(overrides ...func(*ListUsersResponse)) *ListUsersResponse {
+	v := &ListUsersResponse{}
+	body, err := f.gen.ResponseFor("GET /v1/users", 200)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewListUsersResponseFixture returns a ListUsersResponse populated with seed 1.
+func NewListUsersResponseFixture(overrides ...func(*ListUsersResponse)) *ListUsersResponse {
+	return NewFixtureFactory(1).ListUsersResponse(overrides...)
+}
+
+// CreateUserRequest returns a populated CreateUserRequest for POST /v1/users, with
+// overrides applied in order.
+func (f *FixtureFactory) CreateUserRequest(overrides ...func(*CreateUserRequest)) *CreateUserRequest {
+	v := &CreateUserRequest{}
+	body, err := f.gen.Request("POST /v1/users")
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewCreateUserRequestFixture returns a CreateUserRequest populated with seed 1.
+func NewCreateUserRequestFixture(overrides ...func(*CreateUserRequest)) *CreateUserRequest {
+	return NewFixtureFactory(1).CreateUserRequest(overrides...)
+}
+
+// CreateUserResponse returns a populated CreateUserResponse for POST /v1/users, with
+// overrides applied in order.
+func (f *FixtureFactory) CreateUserResponse(overrides ...func(*CreateUserResponse)) *CreateUserResponse {
+	v := &CreateUserResponse{}
+	body, err := f.gen.ResponseFor("POST /v1/users", 201)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewCreateUserResponseFixture returns a CreateUserResponse populated with seed 1.
+func NewCreateUserResponseFixture(overrides ...func(*CreateUserResponse)) *CreateUserResponse {
+	return NewFixtureFactory(1).CreateUserResponse(overrides...)
+}
+
+// GetUserResponse returns a populated GetUserResponse for GET /v1/users/{id}, with
+// overrides applied in order.
+func (f *FixtureFactory) GetUserResponse(overrides ...func(*GetUserResponse)) *GetUserResponse {
+	v := &GetUserResponse{}
+	body, err := f.gen.ResponseFor("GET /v1/users/{id}", 200)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewGetUserResponseFixture returns a GetUserResponse populated with seed 1.
+func NewGetUserResponseFixture(overrides ...func(*GetUserResponse)) *GetUserResponse {
+	return NewFixtureFactory(1).GetUserResponse(overrides...)
+}
+
+// UpdateUserRequest returns a populated UpdateUserRequest for PUT /v1/users/{id}, with
+// overrides applied in order.
+func (f *FixtureFactory) UpdateUserRequest(overrides ...func(*UpdateUserRequest)) *UpdateUserRequest {
+	v := &UpdateUserRequest{}
+	body, err := f.gen.Request("PUT /v1/users/{id}")
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewUpdateUserRequestFixture returns a UpdateUserRequest populated with seed 1.
+func NewUpdateUserRequestFixture(overrides ...func(*UpdateUserRequest)) *UpdateUserRequest {
+	return NewFixtureFactory(1).UpdateUserRequest(overrides...)
+}
+
+// UpdateUserResponse returns a populated UpdateUserResponse for PUT /v1/users/{id}, with
+// overrides applied in order.
+func (f *FixtureFactory) UpdateUserResponse(overrides ...func(*UpdateUserResponse)) *UpdateUserResponse {
+	v := &UpdateUserResponse{}
+	body, err := f.gen.ResponseFor("PUT /v1/users/{id}", 200)
+	f.fill(body, err, v)
+	for _, override := range overrides {
+		override(v)
+	}
+	return v
+}
+
+// NewUpdateUserResponseFixture returns a UpdateUserResponse populated with seed 1.
+func NewUpdateUserResponseFixture(overrides ...func(*UpdateUserResponse)) *UpdateUserResponse {
+	return NewFixtureFactory(1).UpdateUserResponse(overrides...)
+}
@@ -0,0 +1,456 @@
+// Package fake is an in-memory implementation of the API with real CRUD
+// semantics: created resources get ids and timestamps, lists paginate, and
+// updates and deletes are visible to later calls. Install it on any client
+// to run business logic without a network:
+//
+//	api := fake.New()
+//	client.HTTPClient.Transport = api.Transport()
+//
+// Integration tests that need a real endpoint, to exercise the client's
+// transport or another process, serve it over HTTP with Listen instead.
+// Resources and their routes were derived from the captured traffic and are
+// listed in resources.json.
+package fake
+
+import (
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//go:embed resources.json
+var resourcesJSON []byte
+
+// Resource describes one captured collection.
+type Resource struct {
+	Name       string   `json:"name"`
+	Collection string   `json:"collection"`
+	Item       string   `json:"item,omitempty"`
+	IDField    string   `json:"id_field"`
+	IDKind     string   `json:"id_kind"`
+	ListKey    string   `json:"list_key,omitempty"`
+	ListMeta   []string `json:"list_meta,omitempty"`
+	// DefaultLimit is the page size when a list request sets none.
+	DefaultLimit int                    `json:"default_limit,omitempty"`
+	CreatedAt    string                 `json:"created_at,omitempty"`
+	UpdatedAt    string                 `json:"updated_at,omitempty"`
+	Defaults     map[string]interface{} `json:"defaults,omitempty"`
+	WriteOnly    []string               `json:"write_only,omitempty"`
+	Routes       []string               `json:"routes"`
+	Statuses     map[string]int         `json:"statuses,omitempty"`
+}
+
+// Object is a stored resource instance.
+type Object = map[string]interface{}
+
+type collection struct {
+	resource *Resource
+	order    []string
+	items    map[string]Object
+	nextID   int64
+}
+
+// Server holds the in-memory state. It is an http.Handler and is safe for
+// concurrent use.
+type Server struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	collections []*collection
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithClock sets the clock used for created/updated timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) { s.now = now }
+}
+
+// New creates an empty fake.
+func New(opts ...Option) *Server {
+	var resources []*Resource
+	if err := json.Unmarshal(resourcesJSON, &resources); err != nil {
+		panic(fmt.Sprintf("fake: invalid embedded resources: %v", err))
+	}
+	s := &Server{now: time.Now}
+	for _, r := range resources {
+		s.collections = append(s.collections, &collection{resource: r, items: make(map[string]Object)})
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Resources lists the resources the fake implements.
+func (s *Server) Resources() []string {
+	names := make([]string, len(s.collections))
+	for i, c := range s.collections {
+		names[i] = c.resource.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Transport returns an http.RoundTripper that serves requests in memory.
+func (s *Server) Transport() http.RoundTripper {
+	return roundTripper{s}
+}
+
+type roundTripper struct{ s *Server }
+
+// Listen serves the fake on a local httptest.Server until the test ends and
+// returns its URL, the base URL of a client talking to it over HTTP:
+//
+//	server := fake.New()
+//	client := api.NewExampleapiClient(server.Listen(t))
+func (s *Server) Listen(t testing.TB) string {
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	rt.s.ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// Seed stores objects in a resource as if they had been created, assigning
+// ids and timestamps where missing, and returns the stored copies.
+func (s *Server) Seed(resource string, objects ...Object) ([]Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(resource)
+	if c == nil {
+		return nil, fmt.Errorf("fake: unknown resource %q", resource)
+	}
+	stored := make([]Object, len(objects))
+	for i, object := range objects {
+		stored[i] = copyObject(c.create(object, s.now()))
+	}
+	return stored, nil
+}
+
+// Get returns a copy of a stored object.
+func (s *Server) Get(resource, id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(resource)
+	if c == nil || c.items[id] == nil {
+		return nil, false
+	}
+	return copyObject(c.items[id]), true
+}
+
+// List returns copies of all objects of a resource in creation order.
+func (s *Server) List(resource string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(resource)
+	if c == nil {
+		return nil
+	}
+	objects := make([]Object, 0, len(c.order))
+	for _, id := range c.order {
+		objects = append(objects, copyObject(c.items[id]))
+	}
+	return objects
+}
+
+// Reset deletes all stored objects.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.collections {
+		c.order, c.items, c.nextID = nil, make(map[string]Object), 0
+	}
+}
+
+func (s *Server) collection(name string) *collection {
+	for _, c := range s.collections {
+		if c.resource.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	for _, c := range s.collections {
+		r := c.resource
+		if path == r.Collection {
+			s.serveCollection(w, req, c)
+			return
+		}
+		if id, ok := matchItem(r.Item, path); ok {
+			s.serveItem(w, req, c, r.Item, id)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+func matchItem(pattern, path string) (string, bool) {
+	if pattern == "" {
+		return "", false
+	}
+	prefix := pattern[:strings.LastIndex(pattern, "/")+1]
+	id := strings.TrimPrefix(path, prefix)
+	if !strings.HasPrefix(path, prefix) || id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+	return id, true
+}
+
+func (s *Server) allowed(w http.ResponseWriter, r *Resource, method, route string) bool {
+	for _, allowed := range r.Routes {
+		if allowed == method+" "+route {
+			return true
+		}
+	}
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": method + " not supported on " + route})
+	return false
+}
+
+func status(r *Resource, method string, fallback int) int {
+	if code, ok := r.Statuses[method]; ok {
+		return code
+	}
+	return fallback
+}
+
+func (s *Server) serveCollection(w http.ResponseWriter, req *http.Request, c *collection) {
+	r := c.resource
+	if !s.allowed(w, r, req.Method, r.Collection) {
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		s.list(w, req, c)
+	case http.MethodPost:
+		body, ok := readObject(w, req)
+		if !ok {
+			return
+		}
+		writeJSON(w, status(r, http.MethodPost, http.StatusCreated), c.create(body, s.now()))
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (s *Server) serveItem(w http.ResponseWriter, req *http.Request, c *collection, route, id string) {
+	r := c.resource
+	if !s.allowed(w, r, req.Method, route) {
+		return
+	}
+	object := c.items[id]
+	if object == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("%s %s not found", r.Name, id)})
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(w, status(r, http.MethodGet, http.StatusOK), object)
+	case http.MethodPut, http.MethodPatch:
+		body, ok := readObject(w, req)
+		if !ok {
+			return
+		}
+		if req.Method == http.MethodPut {
+			replaced := Object{r.IDField: object[r.IDField]}
+			if r.CreatedAt != "" {
+				replaced[r.CreatedAt] = object[r.CreatedAt]
+			}
+			object = replaced
+		}
+		for key, value := range body {
+			if key != r.IDField && key != r.CreatedAt {
+				object[key] = value
+			}
+		}
+		for _, key := range r.WriteOnly {
+			delete(object, key)
+		}
+		if r.UpdatedAt != "" {
+			object[r.UpdatedAt] = s.now().UTC().Format(time.RFC3339)
+		}
+		c.items[id] = object
+		writeJSON(w, status(r, req.Method, http.StatusOK), object)
+	case http.MethodDelete:
+		delete(c.items, id)
+		for i, existing := range c.order {
+			if existing == id {
+				c.order = append(c.order[:i], c.order[i+1:]...)
+				break
+			}
+		}
+		code := status(r, http.MethodDelete, http.StatusNoContent)
+		if code == http.StatusNoContent {
+			w.WriteHeader(code)
+			return
+		}
+		writeJSON(w, code, object)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// list paginates with page/limit (also per_page, page_size) or
+// offset/limit query parameters.
+func (s *Server) list(w http.ResponseWriter, req *http.Request, c *collection) {
+	query := req.URL.Query()
+	limit := c.resource.DefaultLimit
+	if limit <= 0 {
+		limit = len(c.order)
+	}
+	limit = firstInt(query, limit, "limit", "per_page", "page_size")
+	page := firstInt(query, 1, "page")
+	offset := firstInt(query, (page-1)*limit, "offset")
+	if limit <= 0 {
+		limit = len(c.order)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	items := make([]Object, 0, limit)
+	for i := offset; i < len(c.order) && len(items) < limit; i++ {
+		items = append(items, c.items[c.order[i]])
+	}
+
+	r := c.resource
+	if r.ListKey == "" {
+		writeJSON(w, status(r, http.MethodGet, http.StatusOK), items)
+		return
+	}
+	body := Object{r.ListKey: items}
+	for _, meta := range r.ListMeta {
+		switch meta {
+		case "total", "count", "total_count":
+			body[meta] = len(c.order)
+		case "page":
+			body[meta] = page
+		case "limit", "per_page", "page_size":
+			body[meta] = limit
+		case "offset":
+			body[meta] = offset
+		case "has_more":
+			body[meta] = offset+len(items) < len(c.order)
+		}
+	}
+	writeJSON(w, status(r, http.MethodGet, http.StatusOK), body)
+}
+
+func firstInt(query map[string][]string, fallback int, names ...string) int {
+	for _, name := range names {
+		if values := query[name]; len(values) > 0 {
+			if n, err := strconv.Atoi(values[0]); err == nil {
+				return n
+			}
+		}
+	}
+	return fallback
+}
+
+func (c *collection) create(body Object, now time.Time) Object {
+	r := c.resource
+	object := Object{}
+	for key, value := range r.Defaults {
+		object[key] = value
+	}
+	for key, value := range body {
+		object[key] = value
+	}
+	for _, key := range r.WriteOnly {
+		delete(object, key)
+	}
+	if _, ok := object[r.IDField]; !ok {
+		c.nextID++
+		switch r.IDKind {
+		case "integer":
+			object[r.IDField] = c.nextID
+		case "uuid":
+			object[r.IDField] = newUUID()
+		default:
+			object[r.IDField] = fmt.Sprintf("%s_%d", strings.TrimSuffix(r.Name, "s"), c.nextID)
+		}
+	}
+	stamp := now.UTC().Format(time.RFC3339)
+	if _, ok := object[r.CreatedAt]; !ok && r.CreatedAt != "" {
+		object[r.CreatedAt] = stamp
+	}
+	if _, ok := object[r.UpdatedAt]; !ok && r.UpdatedAt != "" {
+		object[r.UpdatedAt] = stamp
+	}
+
+	id := fmt.Sprint(object[r.IDField])
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil && n > c.nextID {
+		c.nextID = n
+	}
+	if _, exists := c.items[id]; !exists {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = object
+	return object
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func readObject(w http.ResponseWriter, req *http.Request) (Object, bool) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	object := Object{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return object, true
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&object); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body: " + err.Error()})
+		return nil, false
+	}
+	return object, true
+}
+
+func copyObject(object Object) Object {
+	data, _ := json.Marshal(object)
+	var copied Object
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.Decode(&copied)
+	return copied
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,291 @@
+// Package fixtures generates realistic but synthetic request and response
+// bodies for tests. Value distributions (formats, enum frequencies, ranges,
+// null rates, array lengths) were learned from anonymized captures and are
+// stored in profiles.json; no captured personal data is reproduced.
+package fixtures
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed profiles.json
+var profilesJSON []byte
+
+// Weighted is an enum value and its observed frequency.
+type Weighted struct {
+	Value  string  `json:"value"`
+	Weight float64 `json:"weight"`
+}
+
+// Node profiles one value in a body.
+type Node struct {
+	Kind       string             `json:"kind"`
+	NullRate   float64            `json:"null_rate,omitempty"`
+	Properties map[string]*Node   `json:"properties,omitempty"`
+	Presence   map[string]float64 `json:"presence,omitempty"`
+	Items      *Node              `json:"items,omitempty"`
+	MinItems   int                `json:"min_items,omitempty"`
+	MaxItems   int                `json:"max_items,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Enum       []Weighted         `json:"enum,omitempty"`
+	Pattern    string             `json:"pattern,omitempty"`
+	MinLength  int                `json:"min_length,omitempty"`
+	MaxLength  int                `json:"max_length,omitempty"`
+	Min        float64            `json:"min,omitempty"`
+	Max        float64            `json:"max,omitempty"`
+	Sequence   bool               `json:"sequence,omitempty"`
+	TrueRate   float64            `json:"true_rate,omitempty"`
+}
+
+// Profile holds the learned request body and per-status response bodies
+// of one endpoint.
+type Profile struct {
+	Request   *Node            `json:"request,omitempty"`
+	Responses map[string]*Node `json:"responses,omitempty"`
+}
+
+var profiles = mustLoadProfiles()
+
+func mustLoadProfiles() map[string]*Profile {
+	var p map[string]*Profile
+	if err := json.Unmarshal(profilesJSON, &p); err != nil {
+		panic(fmt.Sprintf("fixtures: invalid embedded profiles: %v", err))
+	}
+	return p
+}
+
+// Endpoints lists the endpoint labels ("GET /v1/users") with profiles.
+func Endpoints() []string {
+	endpoints := make([]string, 0, len(profiles))
+	for endpoint := range profiles {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// Generator produces fixtures. The same seed always yields the same
+// sequence of values. A Generator is not safe for concurrent use.
+type Generator struct {
+	rand     *rand.Rand
+	sequence map[*Node]int64
+}
+
+// New creates a Generator seeded with seed.
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed)), sequence: make(map[*Node]int64)}
+}
+
+// Response generates a body for the first successful status captured for
+// endpoint.
+func (g *Generator) Response(endpoint string) (json.RawMessage, error) {
+	profile, err := lookup(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]string, 0, len(profile.Responses))
+	for status := range profile.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if strings.HasPrefix(status, "2") {
+			return json.Marshal(g.value(profile.Responses[status]))
+		}
+	}
+	return nil, fmt.Errorf("fixtures: no successful response captured for %q", endpoint)
+}
+
+// ResponseFor generates a body for endpoint answering with status.
+func (g *Generator) ResponseFor(endpoint string, status int) (json.RawMessage, error) {
+	profile, err := lookup(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := profile.Responses[strconv.Itoa(status)]
+	if !ok {
+		return nil, fmt.Errorf("fixtures: no %d response captured for %q", status, endpoint)
+	}
+	return json.Marshal(g.value(node))
+}
+
+// Request generates a request body for endpoint.
+func (g *Generator) Request(endpoint string) (json.RawMessage, error) {
+	profile, err := lookup(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Request == nil {
+		return nil, fmt.Errorf("fixtures: no request body captured for %q", endpoint)
+	}
+	return json.Marshal(g.value(profile.Request))
+}
+
+// Fill generates a response for endpoint and decodes it into v, typically
+// one of the SDK's response types.
+func (g *Generator) Fill(endpoint string, v interface{}) error {
+	body, err := g.Response(endpoint)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Fixture fills v with the default (seed 1) response for endpoint.
+func Fixture(endpoint string, v interface{}) error {
+	return New(1).Fill(endpoint, v)
+}
+
+func lookup(endpoint string) (*Profile, error) {
+	profile, ok := profiles[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("fixtures: no profile for %q", endpoint)
+	}
+	return profile, nil
+}
+
+func (g *Generator) value(n *Node) interface{} {
+	if n == nil || n.Kind == "null" || (n.NullRate > 0 && g.rand.Float64() < n.NullRate) {
+		return nil
+	}
+	switch n.Kind {
+	case "object":
+		names := make([]string, 0, len(n.Properties))
+		for name := range n.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		object := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			if rate, ok := n.Presence[name]; ok && g.rand.Float64() >= rate {
+				continue
+			}
+			object[name] = g.value(n.Properties[name])
+		}
+		return object
+	case "array":
+		length := n.MinItems + g.rand.Intn(n.MaxItems-n.MinItems+1)
+		items := make([]interface{}, length)
+		for i := range items {
+			items[i] = g.value(n.Items)
+		}
+		return items
+	case "boolean":
+		return g.rand.Float64() < n.TrueRate
+	case "integer":
+		if n.Sequence {
+			next := int64(n.Min) + g.sequence[n]
+			g.sequence[n]++
+			return next
+		}
+		return int64(n.Min) + g.rand.Int63n(int64(n.Max-n.Min)+1)
+	case "number":
+		return math.Round((n.Min+g.rand.Float64()*(n.Max-n.Min))*100) / 100
+	case "string":
+		return g.string(n)
+	}
+	return nil
+}
+
+var (
+	firstNames = []string{"Alex", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn", "Rowan"}
+	lastNames  = []string{"Smith", "Garcia", "Chen", "Okafor", "Novak", "Silva", "Kim", "Larsen", "Patel", "Moreau"}
+	words      = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua")
+)
+
+// baseTime (2024-01-01) anchors dates whose captured range is unknown.
+const baseTime = 1704067200
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+func (g *Generator) string(n *Node) string {
+	switch n.Format {
+	case "email":
+		return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(g.pick(firstNames)), strings.ToLower(g.pick(lastNames)), g.rand.Intn(100))
+	case "name":
+		return g.pick(firstNames) + " " + g.pick(lastNames)
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "date-time", "date":
+		from, to := int64(n.Min), int64(n.Max)
+		if from == 0 && to == 0 {
+			from, to = baseTime, baseTime+365*24*60*60
+		}
+		t := time.Unix(from+g.rand.Int63n(to-from+1), 0).UTC()
+		if n.Format == "date" {
+			return t.Format("2006-01-02")
+		}
+		return t.Format(time.RFC3339)
+	case "url":
+		return fmt.Sprintf("https://example.com/%s/%d", g.pick(words), g.rand.Intn(10000))
+	case "enum":
+		return g.weighted(n.Enum)
+	case "pattern":
+		var b strings.Builder
+		for _, c := range n.Pattern {
+			switch c {
+			case '9':
+				b.WriteByte(byte('0' + g.rand.Intn(10)))
+			case 'A':
+				b.WriteByte(byte('A' + g.rand.Intn(26)))
+			case 'a':
+				b.WriteByte(byte('a' + g.rand.Intn(26)))
+			default:
+				b.WriteRune(c)
+			}
+		}
+		return b.String()
+	}
+
+	length := n.MinLength
+	if n.MaxLength > n.MinLength {
+		length += g.rand.Intn(n.MaxLength - n.MinLength + 1)
+	}
+	var b strings.Builder
+	for b.Len() < length {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(g.pick(words))
+	}
+	text := b.String()
+	if length > 0 && len(text) > length {
+		text = strings.TrimSpace(text[:length])
+	}
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+func (g *Generator) weighted(values []Weighted) string {
+	if len(values) == 0 {
+		return ""
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v.Weight
+	}
+	r := g.rand.Float64() * total
+	for _, v := range values {
+		if r < v.Weight {
+			return v.Value
+		}
+		r -= v.Weight
+	}
+	return values[len(values)-1].Value
+}
@@ -0,0 +1,98 @@
+package example_api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// pingEndpoint is the operation Ping calls: the API's health endpoint when
+// the capture has one, else the captured GET with the smallest response.
+const pingEndpoint = "/v1/posts"
+
+// Ping calls the API's cheapest read-only endpoint and reports whether it
+// answered successfully, checking connectivity and credentials at once.
+func (c *ExampleapiClient) Ping(ctx context.Context) error {
+	_, err := c.doRequest(ctx, "GET", pingEndpoint, pingEndpoint, nil, nil)
+	return err
+}
+
+// Readiness reports whether the API is usable, for the readiness probes of
+// services that depend on it. Results are cached, so frequent probes from
+// many replicas do not load the API:
+//
+//	http.Handle("/readyz", NewReadiness(client))
+type Readiness struct {
+	// Timeout bounds each Ping; zero means 2 seconds.
+	Timeout time.Duration
+	// CacheFor reuses a result for this long; zero means 10 seconds.
+	CacheFor time.Duration
+	// FailureThreshold is how many consecutive failed pings make the API
+	// unready; zero means 1.
+	FailureThreshold int
+
+	client *ExampleapiClient
+
+	mu       sync.Mutex
+	checked  time.Time
+	failures int
+	err      error
+}
+
+// NewReadiness creates a Readiness pinging client.
+func NewReadiness(client *ExampleapiClient) *Readiness {
+	return &Readiness{client: client}
+}
+
+// Check returns nil when the API is ready, or the error of the last ping.
+func (r *Readiness) Check(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cacheFor := r.CacheFor
+	if cacheFor == 0 {
+		cacheFor = 10 * time.Second
+	}
+	if !r.checked.IsZero() && time.Since(r.checked) < cacheFor {
+		return r.status()
+	}
+
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	r.err = r.client.Ping(ctx)
+	r.checked = time.Now()
+	if r.err != nil {
+		r.failures++
+	} else {
+		r.failures = 0
+	}
+	return r.status()
+}
+
+func (r *Readiness) status() error {
+	threshold := r.FailureThreshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	if r.failures >= threshold {
+		return r.err
+	}
+	return nil
+}
+
+// ServeHTTP answers 200 when the API is ready and 503 with the error
+// otherwise.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := r.Check(req.Context()); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(err.Error() + "\n"))
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
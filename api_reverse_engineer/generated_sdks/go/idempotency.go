@@ -0,0 +1,65 @@
+package example_api
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// capturedIdempotencyHeader is the header the captured requests carried
+// idempotency keys in, so that clients send them as well; empty when they
+// carried none.
+const capturedIdempotencyHeader = ""
+
+// WithIdempotencyKeys sends a new key in header, usually "Idempotency-Key",
+// with every POST, PUT, PATCH, and DELETE call, and the same key with each
+// retry of the call, so that a server deduplicating requests by key acts on
+// the call once. Calls with a key are retried like idempotent ones. An
+// empty header stops the keys.
+func WithIdempotencyKeys(header string) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.idempotencyHeader = header
+	}
+}
+
+// WithIdempotencyKey sends key as the call's idempotency key, in place of a
+// generated one, for instance to repeat a call whose outcome was lost with
+// the process that made it.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// assignIdempotencyKey gives a call with an unsafe method a new key when
+// the client sends keys and the caller supplied none.
+func (c *ExampleapiClient) assignIdempotencyKey(method string, options *requestOptions) {
+	if options.idempotencyKey != "" || c.idempotencyHeader == "" {
+		return
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		options.idempotencyKey = newIdempotencyKey()
+	}
+}
+
+// setIdempotencyKey sets the call's key, if it has one, on req.
+func (c *ExampleapiClient) setIdempotencyKey(req *http.Request, options *requestOptions) {
+	if options.idempotencyKey == "" {
+		return
+	}
+	header := c.idempotencyHeader
+	if header == "" {
+		header = "Idempotency-Key"
+	}
+	req.Header.Set(header, options.idempotencyKey)
+}
+
+// newIdempotencyKey returns a random (version 4) UUID.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,74 @@
+package example_api
+
+import (
+	"context"
+	"net/url"
+)
+
+// Instrumentation observes every API call, e.g. to trace it and record
+// metrics; the otel module implements it with OpenTelemetry.
+type Instrumentation interface {
+	// StartCall is called as a call starts. The call's requests are sent
+	// with the context it returns, and end is called once the call is done.
+	StartCall(ctx context.Context, call CallInfo) (_ context.Context, end func(CallResult))
+}
+
+// CallInfo describes an API call to its Instrumentation.
+type CallInfo struct {
+	Name      string // the client method, "Exampleapi.CreateUser"
+	Operation string // "POST /v1/users"
+	Method    string // "POST"
+	Endpoint  string // "/v1/users"
+}
+
+// CallResult is the outcome of an API call.
+type CallResult struct {
+	StatusCode int // the last response's status, 0 when none arrived
+	// URL is the last request's URL, credential query parameters redacted;
+	// nil when no request was sent.
+	URL      *url.URL
+	Attempts int // the requests sent, counting retries
+	Err      error
+}
+
+// WithInstrumentation reports every call the client makes to i.
+func WithInstrumentation(i Instrumentation) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.instrumentation = i
+	}
+}
+
+// operationNames maps each operation to the client method calling it.
+var operationNames = map[string]string{
+	"DELETE /v1/users/{id}": "DeleteUser",
+	"GET /v1/posts":         "ListPosts",
+	"GET /v1/users":         "ListUsers",
+	"GET /v1/users/{id}":    "GetUser",
+	"POST /v1/posts":        "CreatePost",
+	"POST /v1/users":        "CreateUser",
+	"PUT /v1/users/{id}":    "UpdateUser",
+}
+
+// instrument reports the start of a call to the client's instrumentation,
+// switching options to the context it returns, and returns the function
+// that reports the call's end.
+func (c *ExampleapiClient) instrument(method, endpoint string, options *requestOptions) func(err *error) {
+	if c.instrumentation == nil {
+		return func(*error) {}
+	}
+	operation := method + " " + endpoint
+	name := operation
+	if method, ok := operationNames[operation]; ok {
+		name = "Exampleapi." + method
+	}
+	ctx, end := c.instrumentation.StartCall(options.ctx, CallInfo{Name: name, Operation: operation, Method: method, Endpoint: endpoint})
+	options.ctx = ctx
+	return func(err *error) {
+		result := CallResult{StatusCode: options.status, Err: *err}
+		if options.url != nil {
+			result.URL = redactQuery(options.url)
+			result.Attempts = options.attempt
+		}
+		end(result)
+	}
+}
@@ -0,0 +1,282 @@
+package example_api
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime"
+	"strings"
+)
+
+// PayloadCodec transforms request and response bodies on the wire, for APIs
+// that wrap JSON in encryption or signatures. Bodies are JSON on the SDK
+// side of the codec.
+type PayloadCodec interface {
+	// EncodeRequest encodes a JSON request body, returning the body to send
+	// and its Content-Type.
+	EncodeRequest(operation string, body []byte) ([]byte, string, error)
+	// DecodeResponse turns a response body back into JSON.
+	DecodeResponse(operation, contentType string, body []byte) ([]byte, error)
+}
+
+// WithPayloadCodec applies codec to the given operations ("POST /v1/x").
+func WithPayloadCodec(codec PayloadCodec, operations ...string) ClientOption {
+	return func(c *ExampleapiClient) {
+		if c.codecs == nil {
+			c.codecs = make(map[string]PayloadCodec)
+		}
+		for _, operation := range operations {
+			c.codecs[operation] = codec
+		}
+	}
+}
+
+// joseOperations are the operations whose captured traffic used a JOSE
+// content type, mapped to whether their request bodies were encrypted.
+var joseOperations = map[string]bool{}
+
+// WithJOSE applies a JOSECodec to every operation captured with JOSE
+// payloads, encrypting request bodies where the capture did, and verifying
+// signed responses.
+func WithJOSE(keys JOSEKeyResolver) ClientOption {
+	return func(c *ExampleapiClient) {
+		for operation, encrypt := range joseOperations {
+			WithPayloadCodec(&JOSECodec{Keys: keys, EncryptRequests: encrypt}, operation)(c)
+		}
+	}
+}
+
+// ErrJWSInvalid is returned (wrapped) for a signed response that fails
+// verification.
+var ErrJWSInvalid = errors.New("invalid JWS signature")
+
+// JOSEKeyResolver supplies the keys for JOSE payloads, e.g. from a JWKS
+// endpoint or a key management service.
+type JOSEKeyResolver interface {
+	// EncryptionKey returns the key request bodies for operation are
+	// encrypted to, with its key ID: an *rsa.PublicKey (RSA-OAEP-256 with
+	// A256GCM) or a 16, 24, or 32 byte []byte used directly (dir with
+	// AES-GCM).
+	EncryptionKey(operation string) (key interface{}, keyID string, err error)
+	// VerificationKey returns the key for a JWS with the given key ID and
+	// algorithm: an *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, or
+	// a []byte HMAC secret.
+	VerificationKey(keyID, alg string) (interface{}, error)
+}
+
+// StaticJOSEKeys is a JOSEKeyResolver over fixed keys.
+type StaticJOSEKeys struct {
+	Encryption   interface{}
+	EncryptionID string
+	// Verification holds keys by key ID; "" matches any key ID.
+	Verification map[string]interface{}
+}
+
+// EncryptionKey implements JOSEKeyResolver.
+func (k StaticJOSEKeys) EncryptionKey(string) (interface{}, string, error) {
+	if k.Encryption == nil {
+		return nil, "", errors.New("no JWE encryption key")
+	}
+	return k.Encryption, k.EncryptionID, nil
+}
+
+// VerificationKey implements JOSEKeyResolver.
+func (k StaticJOSEKeys) VerificationKey(keyID, _ string) (interface{}, error) {
+	if key, ok := k.Verification[keyID]; ok {
+		return key, nil
+	}
+	if key, ok := k.Verification[""]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no JWS verification key %q", keyID)
+}
+
+// JOSECodec encrypts request bodies as compact JWE and verifies compact JWS
+// responses, returning their payload. Responses without a JOSE content type
+// pass through unchanged.
+type JOSECodec struct {
+	Keys            JOSEKeyResolver
+	EncryptRequests bool
+}
+
+type joseHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty,omitempty"`
+}
+
+var b64url = base64.RawURLEncoding
+
+// EncodeRequest implements PayloadCodec.
+func (j *JOSECodec) EncodeRequest(operation string, body []byte) ([]byte, string, error) {
+	if !j.EncryptRequests {
+		return body, "application/json", nil
+	}
+	key, keyID, err := j.Keys.EncryptionKey(operation)
+	if err != nil {
+		return nil, "", fmt.Errorf("jwe: %w", err)
+	}
+	header := joseHeader{Kid: keyID, Cty: "application/json"}
+	var cek, encryptedKey []byte
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		header.Alg, header.Enc = "RSA-OAEP-256", "A256GCM"
+		cek = make([]byte, 32)
+		if _, err := rand.Read(cek); err != nil {
+			return nil, "", err
+		}
+		if encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, key, cek, nil); err != nil {
+			return nil, "", fmt.Errorf("jwe: %w", err)
+		}
+	case []byte:
+		if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+			return nil, "", fmt.Errorf("jwe: dir key must be 16, 24, or 32 bytes, not %d", len(key))
+		}
+		header.Alg, header.Enc = "dir", fmt.Sprintf("A%dGCM", len(key)*8)
+		cek = key
+	default:
+		return nil, "", fmt.Errorf("jwe: unsupported key type %T", key)
+	}
+
+	headerJSON, _ := json.Marshal(header)
+	protected := b64url.EncodeToString(headerJSON)
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, "", err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, "", err
+	}
+	sealed := gcm.Seal(nil, iv, body, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+	compact := strings.Join([]string{
+		protected,
+		b64url.EncodeToString(encryptedKey),
+		b64url.EncodeToString(iv),
+		b64url.EncodeToString(ciphertext),
+		b64url.EncodeToString(tag),
+	}, ".")
+	return []byte(compact), "application/jose", nil
+}
+
+// DecodeResponse implements PayloadCodec.
+func (j *JOSECodec) DecodeResponse(operation, contentType string, body []byte) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/jose" && mediaType != "application/jwt" {
+		return body, nil
+	}
+	parts := strings.Split(strings.TrimSpace(string(body)), ".")
+	if len(parts) == 5 {
+		return nil, fmt.Errorf("%s: encrypted (JWE) responses are not supported", operation)
+	}
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%s: %w: not a compact JWS", operation, ErrJWSInvalid)
+	}
+	headerJSON, err := b64url.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+	}
+	var header joseHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+	}
+	signature, err := b64url.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+	}
+	key, err := j.Keys.VerificationKey(header.Kid, header.Alg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: jws: %w", operation, err)
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+	}
+	return b64url.DecodeString(parts[1])
+}
+
+// jwsHashes maps JWS algorithm suffixes to hashes.
+var jwsHashes = map[string]crypto.Hash{"256": crypto.SHA256, "384": crypto.SHA384, "512": crypto.SHA512}
+
+// verifySignature checks signature over input with key for a JWS algorithm
+// name, requiring the key type to match the algorithm family so an RSA
+// public key can never be used as an HMAC secret. ECDSA signatures may be
+// raw (JWS) or ASN.1 DER.
+func verifySignature(alg string, key interface{}, input, signature []byte) error {
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, input, signature) {
+			return errors.New("EdDSA verification failed")
+		}
+		return nil
+	}
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	hash, ok := jwsHashes[alg[2:]]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(input)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("%s needs a []byte key, not %T", alg, key)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("HMAC mismatch")
+		}
+	case "RS", "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an *rsa.PublicKey, not %T", alg, key)
+		}
+		if alg[0] == 'R' {
+			return rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+		}
+		return rsa.VerifyPSS(pub, hash, digest, signature, nil)
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an *ecdsa.PublicKey, not %T", alg, key)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			if !ecdsa.VerifyASN1(pub, digest, signature) {
+				return errors.New("ECDSA verification failed")
+			}
+			return nil
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("ECDSA verification failed")
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	return nil
+}
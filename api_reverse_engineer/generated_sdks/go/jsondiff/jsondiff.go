@@ -0,0 +1,298 @@
+// Package jsondiff compares JSON documents, or values that encode to JSON
+// such as SDK responses, and reports each difference by path
+// ("$.users[0].email") rather than a bare "not equal". Options skip what is
+// expected to differ: named fields, timestamps, identifiers, and the order
+// of arrays.
+//
+//	diffs, err := jsondiff.Compare(want, got, jsondiff.Options{
+//		Ignore:           []string{"etag", "$.meta.request_time"},
+//		IgnoreTimestamps: true,
+//		Unordered:        []string{"$.users[*].roles"},
+//	})
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind classifies a difference.
+type Kind string
+
+const (
+	Changed     Kind = "changed"      // scalar values differ
+	TypeChanged Kind = "type changed" // the values have different JSON types
+	Removed     Kind = "removed"      // present in A only
+	Added       Kind = "added"        // present in B only
+)
+
+// Difference is one place where two documents differ.
+type Difference struct {
+	Path string // "$.users[0].email"
+	Kind Kind
+	A, B interface{} // the decoded values at Path; nil on the side lacking it
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case Removed:
+		return fmt.Sprintf("%s: only in a: %s", d.Path, Describe(d.A))
+	case Added:
+		return fmt.Sprintf("%s: only in b: %s", d.Path, Describe(d.B))
+	case TypeChanged:
+		return fmt.Sprintf("%s: %s vs %s", d.Path, TypeOf(d.A), TypeOf(d.B))
+	}
+	return fmt.Sprintf("%s: %s vs %s", d.Path, Describe(d.A), Describe(d.B))
+}
+
+// Options adjusts what Compare reports.
+type Options struct {
+	// Ignore lists fields to skip, by name ("etag") or by path
+	// ("$.meta.total"), where "[*]" matches any array index.
+	Ignore []string
+	// IgnoreTimestamps skips the values of timestamp fields (named like
+	// created_at or updatedAt, or holding an ISO 8601 date) as long as both
+	// sides have one.
+	IgnoreTimestamps bool
+	// IgnoreIDs skips the values of identifier fields (id, uuid, user_id,
+	// userId, etag, or holding a UUID) as long as both sides have one.
+	IgnoreIDs bool
+	// Unordered lists the paths of arrays compared regardless of order,
+	// with "[*]" matching any index; "*" makes every array unordered.
+	Unordered []string
+	// Subset reports only what A has that B lacks or holds differently,
+	// for checking that a response contains an expected fragment.
+	Subset bool
+}
+
+var (
+	timestampFields = regexp.MustCompile(`(?i)^(.+_at|.+At|created|updated|modified|deleted|timestamp|ts|date|time|expires)$`)
+	timestampValues = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?)?$`)
+	idFields        = regexp.MustCompile(`(?i)^(id|uuid|guid|etag|version|request_?id|trace_?id|.+_id|.+Id)$`)
+	idValues        = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	arrayIndex      = regexp.MustCompile(`\[\d+\]`)
+)
+
+// Compare lists the differences between a and b, object fields in sorted
+// order and array items in index order. Each may be raw JSON ([]byte,
+// string, json.RawMessage) or any value that encodes to JSON, so SDK
+// structs, maps, and recorded bodies compare with each other.
+func Compare(a, b interface{}, opts Options) ([]Difference, error) {
+	left, err := Normalize(a)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: a: %w", err)
+	}
+	right, err := Normalize(b)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: b: %w", err)
+	}
+	c := &comparer{opts: opts, ignore: set(opts.Ignore), unordered: set(opts.Unordered)}
+	var diffs []Difference
+	c.compare("$", "", left, right, &diffs)
+	return diffs, nil
+}
+
+// Equal reports whether a and b have no differences under opts; values that
+// cannot be compared are not equal.
+func Equal(a, b interface{}, opts Options) bool {
+	diffs, err := Compare(a, b, opts)
+	return err == nil && len(diffs) == 0
+}
+
+// Normalize decodes v into the generic form Compare works on: maps,
+// slices, float64, string, bool, and nil.
+func Normalize(v interface{}) (interface{}, error) {
+	var data []byte
+	switch v := v.(type) {
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TypeOf names the JSON type of a normalized value.
+func TypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Describe renders a normalized value for messages: scalars as JSON,
+// objects and arrays by their type.
+func Describe(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+type comparer struct {
+	opts      Options
+	ignore    map[string]bool
+	unordered map[string]bool
+}
+
+func set(patterns []string) map[string]bool {
+	s := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		s[pattern] = true
+	}
+	return s
+}
+
+// matches reports whether path is listed in patterns, exactly or with its
+// array indexes replaced by "[*]".
+func matches(patterns map[string]bool, path string) bool {
+	return patterns[path] || patterns[arrayIndex.ReplaceAllString(path, "[*]")]
+}
+
+func (c *comparer) ignored(path, name string) bool {
+	return (name != "" && c.ignore[name]) || matches(c.ignore, path)
+}
+
+func (c *comparer) isUnordered(path string) bool {
+	return c.unordered["*"] || matches(c.unordered, path)
+}
+
+// volatile reports whether a scalar is a timestamp or identifier whose
+// value the options skip.
+func (c *comparer) volatile(name string, v interface{}) bool {
+	s, _ := v.(string)
+	if c.opts.IgnoreTimestamps && (timestampFields.MatchString(name) || timestampValues.MatchString(s)) {
+		return true
+	}
+	return c.opts.IgnoreIDs && (idFields.MatchString(name) || idValues.MatchString(s))
+}
+
+// compare appends the differences between a and b, found at path under the
+// field name, to diffs.
+func (c *comparer) compare(path, name string, a, b interface{}, diffs *[]Difference) {
+	if TypeOf(a) != TypeOf(b) {
+		*diffs = append(*diffs, Difference{path, TypeChanged, a, b})
+		return
+	}
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b := b.(map[string]interface{})
+		keys := make([]string, 0, len(a)+len(b))
+		for key := range a {
+			keys = append(keys, key)
+		}
+		for key := range b {
+			if _, ok := a[key]; !ok && !c.opts.Subset {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "." + key
+			if c.ignored(child, key) {
+				continue
+			}
+			valueA, inA := a[key]
+			valueB, inB := b[key]
+			switch {
+			case !inB:
+				*diffs = append(*diffs, Difference{child, Removed, valueA, nil})
+			case !inA:
+				*diffs = append(*diffs, Difference{child, Added, nil, valueB})
+			default:
+				c.compare(child, key, valueA, valueB, diffs)
+			}
+		}
+	case []interface{}:
+		b := b.([]interface{})
+		if c.isUnordered(path) {
+			c.compareUnordered(path, name, a, b, diffs)
+			return
+		}
+		for i := 0; i < len(a) || i < len(b); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(b):
+				*diffs = append(*diffs, Difference{child, Removed, a[i], nil})
+			case i >= len(a):
+				if !c.opts.Subset {
+					*diffs = append(*diffs, Difference{child, Added, nil, b[i]})
+				}
+			default:
+				c.compare(child, name, a[i], b[i], diffs)
+			}
+		}
+	default:
+		if a != b && !(c.volatile(name, a) && c.volatile(name, b)) {
+			*diffs = append(*diffs, Difference{path, Changed, a, b})
+		}
+	}
+}
+
+// compareUnordered pairs each item of a with an equal, unpaired item of b
+// and reports the items left over on either side.
+func (c *comparer) compareUnordered(path, name string, a, b []interface{}, diffs *[]Difference) {
+	paired := make([]bool, len(b))
+	for i, item := range a {
+		found := false
+		for j := range b {
+			if paired[j] {
+				continue
+			}
+			var itemDiffs []Difference
+			c.compare(fmt.Sprintf("%s[%d]", path, i), name, item, b[j], &itemDiffs)
+			if len(itemDiffs) == 0 {
+				paired[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			*diffs = append(*diffs, Difference{fmt.Sprintf("%s[%d]", path, i), Removed, item, nil})
+		}
+	}
+	if c.opts.Subset {
+		return
+	}
+	for j, item := range b {
+		if !paired[j] {
+			*diffs = append(*diffs, Difference{fmt.Sprintf("%s[%d]", path, j), Added, nil, item})
+		}
+	}
+}
+
+// Format lists diffs one per line, for test failures and reports.
+func Format(diffs []Difference) string {
+	lines := make([]string, len(diffs))
+	for i, diff := range diffs {
+		lines[i] = diff.String()
+	}
+	return strings.Join(lines, "\n")
+}
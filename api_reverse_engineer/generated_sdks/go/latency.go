@@ -0,0 +1,113 @@
+package example_api
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// capturedLatencies are the median latencies of the captured traffic, by
+// operation, for comparing the SDK's observed performance against the
+// original client's.
+var capturedLatencies = map[string]time.Duration{}
+
+// WithLatencyStats adds per-operation latency percentiles to
+// Stats().Endpoints. Each operation keeps a t-digest of at most about ten
+// kilobytes however many calls it sees.
+func WithLatencyStats() ClientOption {
+	return func(c *ExampleapiClient) {
+		c.stats.mu.Lock()
+		defer c.stats.mu.Unlock()
+		c.stats.latency = true
+	}
+}
+
+// tdigestCompression trades a digest's size for accuracy; at 100 it keeps
+// a few hundred centroids however many samples it sees.
+const tdigestCompression = 100
+
+// tdigest estimates quantiles of a stream in bounded memory by merging
+// nearby samples into weighted centroids, keeping them small near the
+// extremes so that high percentiles stay accurate.
+type tdigest struct {
+	centroids []centroid // sorted by mean
+	buffer    []float64
+	count     float64
+	min, max  float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+func (d *tdigest) add(x float64) {
+	if d.count == 0 && len(d.buffer) == 0 {
+		d.min, d.max = x, x
+	}
+	d.min, d.max = math.Min(d.min, x), math.Max(d.max, x)
+	d.buffer = append(d.buffer, x)
+	if len(d.buffer) >= 5*tdigestCompression {
+		d.compress()
+	}
+}
+
+// compress merges the buffered samples into the centroids.
+func (d *tdigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	points := make([]centroid, 0, len(d.centroids)+len(d.buffer))
+	points = append(points, d.centroids...)
+	for _, x := range d.buffer {
+		points = append(points, centroid{x, 1})
+	}
+	d.buffer = d.buffer[:0]
+	sort.Slice(points, func(i, j int) bool { return points[i].mean < points[j].mean })
+
+	total := 0.0
+	for _, p := range points {
+		total += p.weight
+	}
+	merged := make([]centroid, 0, 2*tdigestCompression)
+	current, before := points[0], 0.0
+	for _, p := range points[1:] {
+		q := (before + current.weight + p.weight/2) / total
+		if current.weight+p.weight <= 4*total*q*(1-q)/tdigestCompression {
+			current.weight += p.weight
+			current.mean += (p.mean - current.mean) * p.weight / current.weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, current)
+		current = p
+	}
+	d.centroids = append(merged, current)
+	d.count = total
+}
+
+// quantile estimates the q-th quantile, interpolating between centroid
+// centers and the observed extremes.
+func (d *tdigest) quantile(q float64) float64 {
+	d.compress()
+	if len(d.centroids) == 0 {
+		return 0
+	}
+	target := q * d.count
+	prevCenter, prevMean := 0.0, d.min
+	cumulative := 0.0
+	for _, c := range d.centroids {
+		center := cumulative + c.weight/2
+		if target < center {
+			if center == prevCenter {
+				return c.mean
+			}
+			return prevMean + (c.mean-prevMean)*(target-prevCenter)/(center-prevCenter)
+		}
+		prevCenter, prevMean = center, c.mean
+		cumulative += c.weight
+	}
+	if d.count == prevCenter {
+		return d.max
+	}
+	return prevMean + (d.max-prevMean)*(target-prevCenter)/(d.count-prevCenter)
+}
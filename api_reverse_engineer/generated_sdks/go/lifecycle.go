@@ -0,0 +1,77 @@
+package example_api
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientClosed is returned by calls made after Close.
+var ErrClientClosed = errors.New("client closed")
+
+// lifecycle tracks in-flight calls so Close can wait for them. Derived
+// clients share their parent's, so closing either closes both.
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+	hooks    []func(context.Context) error
+}
+
+// enter registers a call, failing once the client is closed.
+func (l *lifecycle) enter() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClientClosed
+	}
+	l.inflight.Add(1)
+	return nil
+}
+
+func (l *lifecycle) exit() {
+	l.inflight.Done()
+}
+
+// WithCloseFunc registers fn to run during Close once in-flight calls have
+// finished, e.g. to flush a metrics exporter or a drift monitor's alerts.
+func WithCloseFunc(fn func(ctx context.Context) error) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.lifecycle.hooks = append(c.lifecycle.hooks, fn)
+	}
+}
+
+// Close shuts the client down for a service's shutdown sequence: new calls
+// fail with ErrClientClosed, in-flight calls are waited for (until ctx is
+// done, when Close returns ctx.Err() and leaves them running), functions
+// registered with WithCloseFunc run, and idle connections are closed.
+// Closing again only closes idle connections.
+func (c *ExampleapiClient) Close(ctx context.Context) error {
+	c.lifecycle.mu.Lock()
+	c.lifecycle.closed = true
+	c.lifecycle.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.lifecycle.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	c.lifecycle.mu.Lock()
+	hooks := c.lifecycle.hooks
+	c.lifecycle.hooks = nil
+	c.lifecycle.mu.Unlock()
+	var errs []error
+	for _, hook := range hooks {
+		if err := runCloseFunc(ctx, hook); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.HTTPClient.CloseIdleConnections()
+	return errors.Join(errs...)
+}
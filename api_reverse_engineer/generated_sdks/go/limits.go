@@ -0,0 +1,83 @@
+package example_api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SizeLimitError reports a request body or response body larger than the
+// limit set with WithMaxRequestBytes or WithMaxResponseBytes.
+type SizeLimitError struct {
+	Direction string // "request" or "response"
+	Limit     int64
+	Size      int64 // bytes seen; for responses, at least Limit+1
+}
+
+func (e *SizeLimitError) Error() string {
+	return fmt.Sprintf("%s body exceeds the %d byte limit (%d bytes)", e.Direction, e.Limit, e.Size)
+}
+
+// WithMaxRequestBytes rejects calls whose serialized body is larger than n
+// bytes before they are sent.
+func WithMaxRequestBytes(n int64) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.limits.request = n
+	}
+}
+
+// WithMaxResponseBytes stops reading responses after n bytes and fails the
+// call, protecting the program from runaway responses.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.limits.response = n
+	}
+}
+
+// sizeLimits holds the byte limits; zero means unlimited.
+type sizeLimits struct {
+	request  int64
+	response int64
+}
+
+// checkRequest checks the size of a request body, -1 when unknown.
+func (l sizeLimits) checkRequest(size int64) error {
+	if l.request > 0 && size > l.request {
+		return &SizeLimitError{Direction: "request", Limit: l.request, Size: size}
+	}
+	return nil
+}
+
+// readResponse reads the response body, up to the response limit.
+func (l sizeLimits) readResponse(resp *http.Response) ([]byte, error) {
+	if l.response <= 0 {
+		return readAll(resp.Body, resp.ContentLength)
+	}
+	if resp.ContentLength > l.response {
+		return nil, &SizeLimitError{Direction: "response", Limit: l.response, Size: resp.ContentLength}
+	}
+	data, err := readAll(io.LimitReader(resp.Body, l.response+1), resp.ContentLength)
+	if err == nil && int64(len(data)) > l.response {
+		return nil, &SizeLimitError{Direction: "response", Limit: l.response, Size: int64(len(data))}
+	}
+	return data, err
+}
+
+// maxPresize caps the buffer allocated up front from a Content-Length, which
+// the server may get wrong.
+const maxPresize = 64 << 20
+
+// readAll reads r to the end into a buffer sized for size bytes, when
+// known, instead of growing it as io.ReadAll does.
+func readAll(r io.Reader, size int64) ([]byte, error) {
+	if size < 0 {
+		return io.ReadAll(r)
+	}
+	if size > maxPresize {
+		size = maxPresize
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size+bytes.MinRead))
+	_, err := buf.ReadFrom(r)
+	return buf.Bytes(), err
+}
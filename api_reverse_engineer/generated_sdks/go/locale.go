@@ -0,0 +1,52 @@
+package example_api
+
+import "strings"
+
+// localeHeader is a request header carrying the caller's locale.
+type localeHeader struct {
+	name      string
+	separator string // between language and region, as in "en_US"; empty sends the language alone
+}
+
+// localeHeaders are Accept-Language plus the vendor locale headers seen in
+// the captured traffic.
+var localeHeaders = []localeHeader{
+	{"Accept-Language", "-"},
+}
+
+// value formats locale for the header. Accept-Language takes the locale as
+// given, so it may list preferences ("fr-CA, fr;q=0.8"); vendor headers get
+// the first tag in their own format.
+func (h localeHeader) value(locale string) string {
+	if h.name == "Accept-Language" {
+		return locale
+	}
+	tag, _, _ := strings.Cut(locale, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.TrimSpace(tag)
+	if h.separator == "" {
+		language, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+		return language
+	}
+	return strings.NewReplacer("-", h.separator, "_", h.separator).Replace(tag)
+}
+
+// WithLocale requests localized field values and error messages in locale
+// (a BCP 47 tag such as "fr-CA") on every call, via Accept-Language and any
+// vendor locale headers the API uses.
+func WithLocale(locale string) ClientOption {
+	return func(c *ExampleapiClient) {
+		for _, h := range localeHeaders {
+			c.Headers[h.name] = h.value(locale)
+		}
+	}
+}
+
+// WithRequestLocale overrides the client's locale for one call.
+func WithRequestLocale(locale string) RequestOption {
+	return func(o *requestOptions) {
+		for _, h := range localeHeaders {
+			o.header.Set(h.name, h.value(locale))
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package example_api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Metrics serves a client's Stats in the Prometheus text exposition format,
+// so services embedding the client can scrape its traffic without wrapping
+// every call:
+//
+//	http.Handle("/metrics/exampleapi", client.Metrics("exampleapi"))
+//
+// Counters are labelled by operation, such as "GET /v1/users/{id}", and
+// response counters by status class too. Latency is a summary whose
+// quantiles are present when the client was created WithLatencyStats.
+type Metrics struct {
+	client    *ExampleapiClient
+	namespace string
+}
+
+// Metrics returns the client's metrics, with names starting with namespace.
+func (c *ExampleapiClient) Metrics(namespace string) *Metrics {
+	return &Metrics{client: c, namespace: namespace}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	stats := m.client.Stats()
+	m.client.stats.mu.Lock()
+	quantiles := m.client.stats.latency
+	m.client.stats.mu.Unlock()
+	operations := make([]string, 0, len(stats.Endpoints))
+	for operation := range stats.Endpoints {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+
+	var buf bytes.Buffer
+	metric := func(name, kind, help string) string {
+		name = m.metricName(name)
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		return name
+	}
+	counter := func(name, help string, value func(EndpointStats) int64) {
+		name = metric(name, "counter", help)
+		for _, operation := range operations {
+			fmt.Fprintf(&buf, "%s{operation=\"%s\"} %d\n", name, metricLabel(operation), value(stats.Endpoints[operation]))
+		}
+	}
+	counter("requests_total", "HTTP requests sent, retries included.", func(e EndpointStats) int64 { return e.Requests })
+	counter("errors_total", "Requests that got no response or an error status.", func(e EndpointStats) int64 { return e.Errors })
+	counter("retries_total", "Calls retried.", func(e EndpointStats) int64 { return e.Retries })
+	counter("sent_bytes_total", "Request body bytes sent.", func(e EndpointStats) int64 { return e.BytesSent })
+	counter("received_bytes_total", "Response body bytes received.", func(e EndpointStats) int64 { return e.BytesReceived })
+
+	name := metric("responses_total", "counter", "Responses received by status class.")
+	for _, operation := range operations {
+		responses := stats.Endpoints[operation].Responses
+		classes := make([]string, 0, len(responses))
+		for class := range responses {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(&buf, "%s{operation=\"%s\",class=\"%s\"} %d\n", name, metricLabel(operation), class, responses[class])
+		}
+	}
+
+	name = metric("request_duration_seconds", "summary", "Time until the response headers arrived.")
+	for _, operation := range operations {
+		e := stats.Endpoints[operation]
+		label := metricLabel(operation)
+		if quantiles {
+			for _, q := range []struct {
+				quantile string
+				value    float64
+			}{{"0.5", e.P50.Seconds()}, {"0.95", e.P95.Seconds()}, {"0.99", e.P99.Seconds()}} {
+				fmt.Fprintf(&buf, "%s{operation=\"%s\",quantile=\"%s\"} %g\n", name, label, q.quantile, q.value)
+			}
+		}
+		var count int64
+		for _, n := range e.Responses {
+			count += n
+		}
+		fmt.Fprintf(&buf, "%s_sum{operation=\"%s\"} %g\n", name, label, e.Latency.Seconds())
+		fmt.Fprintf(&buf, "%s_count{operation=\"%s\"} %d\n", name, label, count)
+	}
+
+	name = metric("in_flight_requests", "gauge", "Requests awaiting their response.")
+	fmt.Fprintf(&buf, "%s %d\n", name, stats.InFlight)
+	name = metric("schema_mismatches_total", "counter", "Response fields that did not match the generated types.")
+	fmt.Fprintf(&buf, "%s %d\n", name, stats.SchemaMismatches)
+	return buf.WriteTo(w)
+}
+
+func (m *Metrics) metricName(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+	return m.namespace + "_" + name
+}
+
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func metricLabel(value string) string {
+	return metricLabelEscaper.Replace(value)
+}
@@ -0,0 +1,63 @@
+package example_api
+
+import (
+	"net/http"
+)
+
+// Doer sends an HTTP request; *http.Client is one.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer a client sends its requests with, to log,
+// measure, retry, or rewrite them:
+//
+//	client.Use(func(next Doer) Doer {
+//		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Request-Source", "billing")
+//			return next.Do(req)
+//		})
+//	})
+//
+// Middleware sees each attempt of a call separately, after the client has
+// set its headers and credentials, and before the response is checked and
+// decoded.
+type Middleware func(next Doer) Doer
+
+// Use adds middleware to the client. The first added is the outermost, so
+// it sees a request first and its response last. Use is not safe to call
+// concurrently with requests; add middleware before sharing the client.
+func (c *ExampleapiClient) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+}
+
+// WithMiddleware adds middleware to the client, as Use does.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.Use(middleware...)
+	}
+}
+
+// doer returns the HTTP client wrapped in the client's middleware. Upgrade
+// requests, such as WebSocket handshakes, are sent without the client's
+// Timeout, which would cut the upgraded connection short.
+func (c *ExampleapiClient) doer(req *http.Request) Doer {
+	var doer Doer = c.HTTPClient
+	if c.HTTPClient.Timeout != 0 && req.Header.Get("Upgrade") != "" {
+		untimed := *c.HTTPClient
+		untimed.Timeout = 0
+		doer = &untimed
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+	return doer
+}
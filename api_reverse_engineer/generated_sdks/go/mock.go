@@ -0,0 +1,105 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ExampleapiAPI is the API methods of ExampleapiClient. Code calling the API
+// can depend on it rather than on the client, and be tested with a
+// MockExampleapiClient.
+type ExampleapiAPI interface {
+	// ListPosts performs GET /v1/posts
+	ListPosts(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error)
+	// CreatePost performs POST /v1/posts
+	CreatePost(ctx context.Context, data *CreatePostRequest, opts ...RequestOption) (*CreatePostResponse, error)
+	// ListUsers performs GET /v1/users
+	ListUsers(ctx context.Context, params *ListUsersParams, opts ...RequestOption) (*ListUsersResponse, error)
+	// CreateUser performs POST /v1/users
+	CreateUser(ctx context.Context, data *CreateUserRequest, opts ...RequestOption) (*CreateUserResponse, error)
+	// GetUser performs GET /v1/users/{id}
+	GetUser(ctx context.Context, id int64, opts ...RequestOption) (*GetUserResponse, error)
+	// UpdateUser performs PUT /v1/users/{id}
+	UpdateUser(ctx context.Context, id int64, data *UpdateUserRequest, opts ...RequestOption) (*UpdateUserResponse, error)
+	// DeleteUser performs DELETE /v1/users/{id}
+	DeleteUser(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error)
+}
+
+var _ ExampleapiAPI = (*ExampleapiClient)(nil)
+
+// MockExampleapiClient implements ExampleapiAPI with a function field per
+// method, which a test sets for the methods its code calls. Calling a method
+// whose function is nil panics:
+//
+//	api := &MockExampleapiClient{
+//		ListPostsFunc: func(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error) {
+//			return &ListPostsResponse{}, nil
+//		},
+//	}
+type MockExampleapiClient struct {
+	ListPostsFunc  func(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error)
+	CreatePostFunc func(ctx context.Context, data *CreatePostRequest, opts ...RequestOption) (*CreatePostResponse, error)
+	ListUsersFunc  func(ctx context.Context, params *ListUsersParams, opts ...RequestOption) (*ListUsersResponse, error)
+	CreateUserFunc func(ctx context.Context, data *CreateUserRequest, opts ...RequestOption) (*CreateUserResponse, error)
+	GetUserFunc    func(ctx context.Context, id int64, opts ...RequestOption) (*GetUserResponse, error)
+	UpdateUserFunc func(ctx context.Context, id int64, data *UpdateUserRequest, opts ...RequestOption) (*UpdateUserResponse, error)
+	DeleteUserFunc func(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error)
+}
+
+var _ ExampleapiAPI = (*MockExampleapiClient)(nil)
+
+// ListPosts calls ListPostsFunc.
+func (m *MockExampleapiClient) ListPosts(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error) {
+	if m.ListPostsFunc == nil {
+		panic("MockExampleapiClient.ListPosts called without ListPostsFunc")
+	}
+	return m.ListPostsFunc(ctx, opts...)
+}
+
+// CreatePost calls CreatePostFunc.
+func (m *MockExampleapiClient) CreatePost(ctx context.Context, data *CreatePostRequest, opts ...RequestOption) (*CreatePostResponse, error) {
+	if m.CreatePostFunc == nil {
+		panic("MockExampleapiClient.CreatePost called without CreatePostFunc")
+	}
+	return m.CreatePostFunc(ctx, data, opts...)
+}
+
+// ListUsers calls ListUsersFunc.
+func (m *MockExampleapiClient) ListUsers(ctx context.Context, params *ListUsersParams, opts ...RequestOption) (*ListUsersResponse, error) {
+	if m.ListUsersFunc == nil {
+		panic("MockExampleapiClient.ListUsers called without ListUsersFunc")
+	}
+	return m.ListUsersFunc(ctx, params, opts...)
+}
+
+// CreateUser calls CreateUserFunc.
+func (m *MockExampleapiClient) CreateUser(ctx context.Context, data *CreateUserRequest, opts ...RequestOption) (*CreateUserResponse, error) {
+	if m.CreateUserFunc == nil {
+		panic("MockExampleapiClient.CreateUser called without CreateUserFunc")
+	}
+	return m.CreateUserFunc(ctx, data, opts...)
+}
+
+// GetUser calls GetUserFunc.
+func (m *MockExampleapiClient) GetUser(ctx context.Context, id int64, opts ...RequestOption) (*GetUserResponse, error) {
+	if m.GetUserFunc == nil {
+		panic("MockExampleapiClient.GetUser called without GetUserFunc")
+	}
+	return m.GetUserFunc(ctx, id, opts...)
+}
+
+// UpdateUser calls UpdateUserFunc.
+func (m *MockExampleapiClient) UpdateUser(ctx context.Context, id int64, data *UpdateUserRequest, opts ...RequestOption) (*UpdateUserResponse, error) {
+	if m.UpdateUserFunc == nil {
+		panic("MockExampleapiClient.UpdateUser called without UpdateUserFunc")
+	}
+	return m.UpdateUserFunc(ctx, id, data, opts...)
+}
+
+// DeleteUser calls DeleteUserFunc.
+func (m *MockExampleapiClient) DeleteUser(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error) {
+	if m.DeleteUserFunc == nil {
+		panic("MockExampleapiClient.DeleteUser called without DeleteUserFunc")
+	}
+	return m.DeleteUserFunc(ctx, id, opts...)
+}
@@ -0,0 +1,213 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is an OAuth2 access token. Its fields are those of
+// golang.org/x/oauth2's Token.
+type Token struct {
+	AccessToken  string
+	TokenType    string // "Bearer" when empty
+	RefreshToken string
+	Expiry       time.Time // zero if the token does not expire
+}
+
+// header is the Authorization header value for t.
+func (t *Token) header() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenSource supplies OAuth2 tokens. It has the shape of
+// golang.org/x/oauth2's TokenSource, which adapts with TokenSourceFunc:
+//
+//	WithTokenSource(TokenSourceFunc(func() (*Token, error) {
+//		t, err := config.TokenSource(ctx).Token()
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &Token{AccessToken: t.AccessToken, TokenType: t.TokenType, Expiry: t.Expiry}, nil
+//	}))
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource.
+type TokenSourceFunc func() (*Token, error)
+
+// Token implements TokenSource.
+func (f TokenSourceFunc) Token() (*Token, error) {
+	return f()
+}
+
+// WithTokenSource sends the tokens of src in the Authorization header. A
+// token is reused until it is within 30 seconds of expiring or the API
+// rejects it with a 401, and src is then asked for a new one.
+func WithTokenSource(src TokenSource) ClientOption {
+	return withTokens(func(context.Context) (*Token, error) {
+		return src.Token()
+	})
+}
+
+// WithClientCredentialsConfig authenticates with tokens from config,
+// requested with the context of the call needing one.
+func WithClientCredentialsConfig(config *ClientCredentials) ClientOption {
+	return withTokens(config.Token)
+}
+
+func withTokens(fetch func(ctx context.Context) (*Token, error)) ClientOption {
+	credential := &secretCredential{secrets: &reuseTokenSource{fetch: fetch}, name: "token", header: "Authorization"}
+	return func(c *ExampleapiClient) {
+		c.credentials = append(c.credentials, credential)
+	}
+}
+
+// tokenLeeway is how long before it expires a token is replaced.
+const tokenLeeway = 30 * time.Second
+
+// reuseTokenSource caches the current token of a TokenSource.
+type reuseTokenSource struct {
+	fetch func(ctx context.Context) (*Token, error)
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// Secret returns the Authorization header value of the current token,
+// fetching a new one if needed.
+func (r *reuseTokenSource) Secret(ctx context.Context, _ string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token == nil || !r.token.Expiry.IsZero() && time.Until(r.token.Expiry) <= tokenLeeway {
+		token, err := r.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		if token == nil || token.AccessToken == "" {
+			return "", fmt.Errorf("token source returned no access token")
+		}
+		r.token = token
+	}
+	return r.token.header(), nil
+}
+
+// expire drops the current token if it is the rejected one.
+func (r *reuseTokenSource) expire(_, rejected string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != nil && r.token.header() == rejected {
+		r.token = nil
+	}
+}
+
+// ClientCredentials fetches tokens with the OAuth2 client credentials grant
+// (RFC 6749, section 4.4), like golang.org/x/oauth2/clientcredentials.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// EndpointParams are sent with every token request, e.g. an audience.
+	EndpointParams url.Values
+	// AuthInParams sends the client ID and secret in the request body
+	// rather than with HTTP Basic authentication.
+	AuthInParams bool
+	// HTTPClient makes the token requests; nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// TokenError is a token endpoint's refusal to issue a token.
+type TokenError struct {
+	StatusCode  int
+	Code        string // the OAuth2 error code, e.g. "invalid_client"
+	Description string
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("token endpoint: status=%d, error=%s: %s", e.StatusCode, e.Code, e.Description)
+	}
+	return fmt.Sprintf("token endpoint: status=%d, error=%s", e.StatusCode, e.Code)
+}
+
+// TokenSource returns a TokenSource requesting tokens with ctx.
+func (c *ClientCredentials) TokenSource(ctx context.Context) TokenSource {
+	return TokenSourceFunc(func() (*Token, error) {
+		return c.Token(ctx)
+	})
+}
+
+// Token requests a new token.
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	for key, values := range c.EndpointParams {
+		form[key] = values
+	}
+	if c.AuthInParams {
+		form.Set("client_id", c.ClientID)
+		form.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !c.AuthInParams {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	decodeErr := json.Unmarshal(body, &payload)
+	if resp.StatusCode >= 400 || payload.Error != "" {
+		code := payload.Error
+		if code == "" {
+			code = http.StatusText(resp.StatusCode)
+		}
+		return nil, &TokenError{StatusCode: resp.StatusCode, Code: code, Description: payload.ErrorDescription}
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("token endpoint: %w", decodeErr)
+	}
+	token := &Token{AccessToken: payload.AccessToken, TokenType: payload.TokenType, RefreshToken: payload.RefreshToken}
+	if payload.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
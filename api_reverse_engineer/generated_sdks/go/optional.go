@@ -0,0 +1,10 @@
+package example_api
+
+// Ptr returns a pointer to v, for the optional fields of request structs.
+// They are pointers so that a field left nil, which is not sent, differs
+// from one set to its zero value:
+//
+//	req.Nickname = Ptr("") // sends "nickname": ""
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,178 @@
+package example_api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestOption customizes a single call, overriding the client's
+// configuration for that call only:
+//
+//	posts, err := client.ListPosts(ctx, WithRequestLocale("de-DE"))
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	ctx     context.Context
+	header  http.Header
+	edits   []func(*http.Request)
+	baseURL string
+
+	// status is the response status code, recorded for the audit log.
+	status int
+	// statusOut, if set, receives status too, for callers of doRequest.
+	statusOut *int
+	// meta, if set, receives the response's metadata.
+	meta *ResponseMeta
+	// retry, if set, replaces the client's retry policy.
+	retry *RetryPolicy
+	// attempt is the attempt being made, counted from 1.
+	attempt int
+	// url is the URL of the last request sent, for instrumentation.
+	url *url.URL
+	// idempotencyKey, if set, is sent with every attempt at the call.
+	idempotencyKey string
+	// envelope, if set, receives the fields of an enveloped response
+	// besides its payload.
+	envelope interface{}
+	// timeout, if set, limits the call, retries included.
+	timeout time.Duration
+	// query holds query parameters replacing those of the call.
+	query url.Values
+	// dryRun, if set, returns the prepared request in a *DryRunError
+	// instead of sending it.
+	dryRun bool
+}
+
+// ResponseMeta describes the response to a call, beyond its decoded body.
+type ResponseMeta struct {
+	Operation  string // "GET /v1/users/{id}"
+	StatusCode int    // 0 when no response arrived
+	Header     http.Header
+	RequestID  string // the server's request ID header, for support requests
+	Duration   time.Duration
+}
+
+// requestIDHeaders are the response headers read as the server's request ID.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Amzn-RequestId", "X-Correlation-Id"}
+
+// requestID returns the first request ID header set in header.
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// record fills m from resp; m may be nil.
+func (m *ResponseMeta) record(operation string, resp *http.Response) {
+	if m == nil {
+		return
+	}
+	m.Operation = operation
+	m.StatusCode = resp.StatusCode
+	m.Header = resp.Header
+	m.RequestID = requestID(resp.Header)
+}
+
+// finish records the time since start; m may be nil.
+func (m *ResponseMeta) finish(start time.Time) {
+	if m != nil {
+		m.Duration = time.Since(start)
+	}
+}
+
+// WithHeader sends the header with the call, replacing the client's value
+// for it:
+//
+//	posts, err := client.ListPosts(ctx, WithHeader("X-Debug", "1"))
+func WithHeader(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(name, value)
+	}
+}
+
+// WithTimeout limits the call, retries included, to d, on top of any
+// deadline of its context. Streams and downloads must be read within d too.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithQueryParam sends the query parameter with the call, replacing any
+// value the method's parameters give it.
+func WithQueryParam(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.query == nil {
+			o.query = make(url.Values)
+		}
+		o.query.Set(name, value)
+	}
+}
+
+// recordStatus stores the call's response status code in status.
+func recordStatus(status *int) RequestOption {
+	return func(o *requestOptions) {
+		o.statusOut = status
+	}
+}
+
+// recordMeta stores the call's response metadata in meta.
+func recordMeta(meta *ResponseMeta) RequestOption {
+	return func(o *requestOptions) {
+		o.meta = meta
+	}
+}
+
+// newRequestOptions applies the client's default options, then the call's,
+// to a call made with ctx.
+func newRequestOptions(ctx context.Context, defaults, opts []RequestOption) *requestOptions {
+	o := &requestOptions{ctx: ctx, header: make(http.Header), attempt: 1}
+	for _, opt := range defaults {
+		opt(o)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// startTimeout bounds the call's context by its timeout, if any. The call
+// ends with cancel.
+func (o *requestOptions) startTimeout() (cancel context.CancelFunc) {
+	if o.timeout <= 0 {
+		return func() {}
+	}
+	o.ctx, cancel = context.WithTimeout(o.ctx, o.timeout)
+	return cancel
+}
+
+// queryParams returns params with the per-call query parameters set.
+func (o *requestOptions) queryParams(params url.Values) url.Values {
+	if len(o.query) == 0 {
+		return params
+	}
+	merged := make(url.Values, len(params)+len(o.query))
+	for name, values := range params {
+		merged[name] = values
+	}
+	for name, values := range o.query {
+		merged[name] = values
+	}
+	return merged
+}
+
+// apply sets the per-call headers on req, replacing the client's, then runs
+// the edits in order.
+func (o *requestOptions) apply(req *http.Request) {
+	for name, values := range o.header {
+		req.Header[name] = values
+	}
+	for _, edit := range o.edits {
+		edit(req)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/example/example_api/otel
+
+go 1.25.0
+
+require (
+	github.com/example/example_api v0.0.0
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/metric v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+replace github.com/example/example_api => ../
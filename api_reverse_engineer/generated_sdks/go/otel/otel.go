@@ -0,0 +1,166 @@
+// Package otel instruments an ExampleapiClient with OpenTelemetry: a client
+// span per API call, named after the client method ("Exampleapi.CreateUser")
+// and carrying the HTTP semantic convention attributes, a counter of calls,
+// and a histogram of their durations. The trace context is propagated in
+// the request headers.
+//
+//	client := api.NewExampleapiClient("", otel.WithTelemetry())
+//
+// It is a module of its own, so that only programs importing it depend on
+// OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	global "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	api "github.com/example/example_api"
+)
+
+// scope is the instrumentation scope of the tracer and meter.
+const scope = "github.com/example/example_api/otel"
+
+// Option configures WithTelemetry.
+type Option func(*config)
+
+type config struct {
+	tracers    trace.TracerProvider
+	meters     metric.MeterProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider creates spans with provider instead of the global
+// one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracers = provider
+	}
+}
+
+// WithMeterProvider records metrics with provider instead of the global
+// one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meters = provider
+	}
+}
+
+// WithPropagator injects the trace context into requests with propagator
+// instead of the global one.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// WithTelemetry instruments a client with the global tracer provider,
+// meter provider, and propagator, unless opts replace them.
+func WithTelemetry(opts ...Option) api.ClientOption {
+	cfg := config{
+		tracers:    global.GetTracerProvider(),
+		meters:     global.GetMeterProvider(),
+		propagator: global.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	t := newTelemetry(cfg)
+	return func(c *api.ExampleapiClient) {
+		api.WithInstrumentation(t)(c)
+		c.Use(t.propagate)
+	}
+}
+
+// telemetry is the api.Instrumentation recording spans and metrics.
+type telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	calls      metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+func newTelemetry(cfg config) *telemetry {
+	meter := cfg.meters.Meter(scope)
+	calls, err := meter.Int64Counter("exampleapi.client.calls",
+		metric.WithDescription("API calls made by the client, counting each call once however often it was retried."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		global.Handle(err)
+	}
+	duration, err := meter.Float64Histogram("exampleapi.client.call.duration",
+		metric.WithDescription("Duration of API calls, including retries."),
+		metric.WithUnit("s"))
+	if err != nil {
+		global.Handle(err)
+	}
+	return &telemetry{
+		tracer:     cfg.tracers.Tracer(scope),
+		propagator: cfg.propagator,
+		calls:      calls,
+		duration:   duration,
+	}
+}
+
+// StartCall starts the call's span.
+func (t *telemetry) StartCall(ctx context.Context, call api.CallInfo) (context.Context, func(api.CallResult)) {
+	start := time.Now()
+	attrs := []attribute.KeyValue{
+		attribute.String("api.operation", call.Name),
+		attribute.String("http.request.method", call.Method),
+		attribute.String("url.template", call.Endpoint),
+	}
+	ctx, span := t.tracer.Start(ctx, call.Name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, func(result api.CallResult) {
+		if result.URL != nil {
+			span.SetAttributes(attribute.String("url.full", result.URL.String()))
+			attrs = append(attrs, attribute.String("server.address", result.URL.Hostname()))
+			if port, err := strconv.Atoi(result.URL.Port()); err == nil {
+				attrs = append(attrs, attribute.Int("server.port", port))
+			}
+		}
+		if result.Attempts > 1 {
+			span.SetAttributes(attribute.Int("http.request.resend_count", result.Attempts-1))
+		}
+		if result.StatusCode != 0 {
+			attrs = append(attrs, attribute.Int("http.response.status_code", result.StatusCode))
+		}
+		if result.Err != nil {
+			attrs = append(attrs, attribute.String("error.type", errorType(result)))
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		}
+		span.SetAttributes(attrs...)
+		span.End()
+		set := metric.WithAttributes(attrs...)
+		t.calls.Add(ctx, 1, set)
+		t.duration.Record(ctx, time.Since(start).Seconds(), set)
+	}
+}
+
+// errorType is the error.type attribute of a failed call: its status code,
+// or the type of its error when it got no error status.
+func errorType(result api.CallResult) string {
+	if result.StatusCode >= 400 {
+		return strconv.Itoa(result.StatusCode)
+	}
+	return fmt.Sprintf("%T", result.Err)
+}
+
+// propagate is the middleware injecting the trace context of a request's
+// context into its headers.
+func (t *telemetry) propagate(next api.Doer) api.Doer {
+	return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		t.propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+		return next.Do(req)
+	})
+}
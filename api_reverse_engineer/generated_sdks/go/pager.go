@@ -0,0 +1,191 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Pager iterates the items of a paged list endpoint, requesting the next
+// page when the current one runs out:
+//
+//	it := client.ListUsersPager(ctx, nil)
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, page, offset int) ([]T, pageInfo, error)
+
+	page, offset int // of the next request
+	items        []T
+	index        int
+	pages        int
+	previous     string // the first item of the last page, as JSON
+	done         bool
+	err          error
+}
+
+// pageInfo is what a page says about the pages after it.
+type pageInfo struct {
+	hasMore  *bool // from a boolean field, when the envelope has one
+	total    int   // from a total field; -1 when the envelope has none
+	pageSize int   // the page size requested, or captured; 0 if unknown
+}
+
+// newPager starts at page (1-based) and offset, fetching lazily.
+func newPager[T any](ctx context.Context, page, offset int, fetch func(ctx context.Context, page, offset int) ([]T, pageInfo, error)) *Pager[T] {
+	if page < 1 {
+		page = 1
+	}
+	return &Pager[T]{ctx: ctx, fetch: fetch, page: page, offset: offset, index: -1}
+}
+
+// newCursorPager starts at the first page, fetching each later one with the
+// cursor fetch returned with the page before; an empty cursor ends the
+// iteration.
+func newCursorPager[T any](ctx context.Context, fetch func(ctx context.Context, cursor string) ([]T, pageInfo, string, error)) *Pager[T] {
+	cursor := ""
+	return newPager(ctx, 1, 0, func(ctx context.Context, _, _ int) ([]T, pageInfo, error) {
+		items, info, next, err := fetch(ctx, cursor)
+		if next == "" {
+			info.hasMore = new(bool)
+		}
+		cursor = next
+		return items, info, err
+	})
+}
+
+// withCursor sends the cursor of the next page with the call, as the query
+// parameters of a next-page URL, or else as param.
+func withCursor(param, cursor string) RequestOption {
+	return func(o *requestOptions) {
+		if o.query == nil {
+			o.query = make(url.Values)
+		}
+		setCursor(o.query, param, cursor)
+	}
+}
+
+// Next advances to the next item, requesting the next page if needed. It
+// returns false when the items are exhausted or a request fails; check Err
+// to tell which.
+func (p *Pager[T]) Next() bool {
+	for p.index+1 >= len(p.items) {
+		if p.done || p.err != nil {
+			return false
+		}
+		p.nextPage()
+	}
+	p.index++
+	return true
+}
+
+// Item returns the current item.
+func (p *Pager[T]) Item() T {
+	return p.items[p.index]
+}
+
+// NextPage requests the next page, reporting whether it has items; Page
+// then returns them, for handling items a page at a time:
+//
+//	for it.NextPage() {
+//		upsert(it.Page())
+//	}
+func (p *Pager[T]) NextPage() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	p.nextPage()
+	return len(p.items) > 0
+}
+
+// Page returns the items of the current page.
+func (p *Pager[T]) Page() []T {
+	return p.items
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+func (p *Pager[T]) nextPage() {
+	items, info, err := p.fetch(p.ctx, p.page, p.offset)
+	if err != nil {
+		p.items, p.index, p.err = nil, -1, err
+		return
+	}
+	if len(items) > 0 {
+		// An API ignoring the paging parameters would repeat its first
+		// page forever.
+		first, _ := json.Marshal(items[0])
+		if p.pages > 0 && string(first) == p.previous {
+			p.items, p.index = nil, -1
+			p.err = fmt.Errorf("page %d repeats page %d", p.page, p.page-1)
+			return
+		}
+		p.previous = string(first)
+	}
+	p.pages++
+	p.page++
+	p.offset += len(items)
+	p.items, p.index = items, -1
+	p.done = !info.more(len(items), p.offset)
+}
+
+// more reports whether another page follows one of got items, seen in
+// total: as the page says, or else while pages come back full.
+func (i pageInfo) more(got, seen int) bool {
+	switch {
+	case got == 0:
+		return false
+	case i.hasMore != nil:
+		return *i.hasMore
+	case i.total >= 0:
+		return seen < i.total
+	}
+	return i.pageSize <= 0 || got >= i.pageSize
+}
+
+// All requests every remaining page and returns their items.
+func (p *Pager[T]) All() ([]T, error) {
+	var all []T
+	for p.Next() {
+		all = append(all, p.Item())
+	}
+	return all, p.Err()
+}
+
+// ListUsersPager iterates the items of GET /v1/users page by page,
+// starting from params' Page.
+func (c *ExampleapiClient) ListUsersPager(ctx context.Context, params *ListUsersParams, opts ...RequestOption) *Pager[UserItem] {
+	var query ListUsersParams
+	if params != nil {
+		query = *params
+	}
+	return newPager(ctx, query.Page, 0, func(ctx context.Context, page, offset int) ([]UserItem, pageInfo, error) {
+		query.Page = page
+		result, err := c.ListUsers(ctx, &query, opts...)
+		if err != nil {
+			return nil, pageInfo{}, err
+		}
+		info := pageInfo{total: result.Total, pageSize: query.Limit}
+		if info.pageSize == 0 {
+			info.pageSize = 10 // the captured page size
+		}
+		return result.Users, info, nil
+	})
+}
+
+// ListUsersAll returns every item of GET /v1/users, requesting the
+// pages in turn.
+func (c *ExampleapiClient) ListUsersAll(ctx context.Context, params *ListUsersParams, opts ...RequestOption) ([]UserItem, error) {
+	return c.ListUsersPager(ctx, params, opts...).All()
+}
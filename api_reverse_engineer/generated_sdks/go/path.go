@@ -0,0 +1,38 @@
+package example_api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// pathSegment escapes a path parameter so that it stays one segment of the
+// path: "/", "?", "#", and other reserved characters are percent-encoded,
+// and "." and "..", which would be resolved as relative references, are
+// encoded in full.
+func pathSegment(value string) string {
+	switch value {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	return url.PathEscape(value)
+}
+
+// checkPath rejects a request path, ending with the path of endpoint, whose
+// parameters were left empty. It would address another resource, such as
+// the collection rather than one of its items.
+func checkPath(endpoint, path string) error {
+	pattern, segments := strings.Split(endpoint, "/"), strings.Split(path, "/")
+	if len(segments) < len(pattern) {
+		return nil
+	}
+	segments = segments[len(segments)-len(pattern):]
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && segments[i] == "" {
+			return fmt.Errorf("%s: empty path parameter %s", endpoint, strings.Trim(segment, "{}"))
+		}
+	}
+	return nil
+}
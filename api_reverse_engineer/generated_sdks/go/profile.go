@@ -0,0 +1,212 @@
+package example_api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProfilesEnv names the environment variable pointing at the profiles
+// file. Without it, ./exampleapi-profiles.json and then
+// $HOME/.config/exampleapi/profiles.json are tried.
+const ProfilesEnv = "EXAMPLEAPI_PROFILES"
+
+// Profile is one named target environment (sandbox, staging, production)
+// in a profiles file:
+//
+//	{
+//	  "profiles": {
+//	    "sandbox": {
+//	      "base_url": "https://sandbox.example.com",
+//	      "token_env": "SANDBOX_TOKEN",
+//	      "headers": {"X-Env": "sandbox"},
+//	      "timeout": "10s",
+//	      "rate_limit": {"requests": 5, "per": "1s"},
+//	      "tls": {"ca_file": "sandbox-ca.pem"},
+//	      "disabled_endpoints": ["DELETE /v1/users/{id}"]
+//	    }
+//	  }
+//	}
+//
+// Tokens are better referenced through token_env than stored inline.
+type Profile struct {
+	BaseURL           string            `json:"base_url"`
+	Token             string            `json:"token,omitempty"`
+	TokenEnv          string            `json:"token_env,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	Timeout           string            `json:"timeout,omitempty"`
+	RateLimit         *ProfileRateLimit `json:"rate_limit,omitempty"`
+	TLS               *ProfileTLS       `json:"tls,omitempty"`
+	DisabledEndpoints []string          `json:"disabled_endpoints,omitempty"`
+}
+
+// ProfileRateLimit caps a profile at Requests per Per (a Go duration).
+type ProfileRateLimit struct {
+	Requests int    `json:"requests"`
+	Per      string `json:"per"`
+}
+
+// ProfileTLS configures TLS for a profile. Relative paths are resolved
+// against the profiles file's directory.
+type ProfileTLS struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// Profiles is a loaded profiles file.
+type Profiles struct {
+	Profiles map[string]Profile `json:"profiles"`
+
+	dir string
+}
+
+// LoadProfiles reads a profiles file; an empty path uses ProfilesEnv or the
+// default locations.
+func LoadProfiles(path string) (*Profiles, error) {
+	if path == "" {
+		path = profilesPath()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load profiles: %w", err)
+	}
+	var profiles Profiles
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("load profiles %s: %w", path, err)
+	}
+	profiles.dir = filepath.Dir(path)
+	return &profiles, nil
+}
+
+func profilesPath() string {
+	if path := os.Getenv(ProfilesEnv); path != "" {
+		return path
+	}
+	if _, err := os.Stat("exampleapi-profiles.json"); err == nil {
+		return "exampleapi-profiles.json"
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "exampleapi", "profiles.json")
+}
+
+// Names returns the profile names, sorted.
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options returns the client options for the named profile.
+func (p *Profiles) Options(name string) ([]ClientOption, error) {
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found (have %v)", name, p.Names())
+	}
+	var opts []ClientOption
+	if profile.BaseURL != "" {
+		baseURL := strings.TrimSuffix(profile.BaseURL, "/")
+		opts = append(opts, func(c *ExampleapiClient) { c.BaseURL = baseURL })
+	}
+
+	token := profile.Token
+	if profile.TokenEnv != "" {
+		token = os.Getenv(profile.TokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("profile %q: environment variable %s is not set", name, profile.TokenEnv)
+		}
+	}
+	if token != "" {
+		opts = append(opts, func(c *ExampleapiClient) { c.SetAuthToken(token) })
+	}
+	for key, value := range profile.Headers {
+		key, value := key, value
+		opts = append(opts, func(c *ExampleapiClient) { c.SetHeader(key, value) })
+	}
+
+	if profile.Timeout != "" {
+		timeout, err := time.ParseDuration(profile.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: timeout: %w", name, err)
+		}
+		opts = append(opts, WithTotalTimeout(timeout))
+	}
+	if limit := profile.RateLimit; limit != nil {
+		per, err := time.ParseDuration(limit.Per)
+		if err != nil || limit.Requests <= 0 || per <= 0 {
+			return nil, fmt.Errorf("profile %q: rate_limit needs positive requests and per", name)
+		}
+		opts = append(opts, WithRateLimit(limit.Requests, per))
+	}
+	if profile.TLS != nil {
+		config, err := p.tlsConfig(profile.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		opts = append(opts, WithTLSConfig(config))
+	}
+	if len(profile.DisabledEndpoints) > 0 {
+		opts = append(opts, WithDisabledEndpoints(profile.DisabledEndpoints...))
+	}
+	return opts, nil
+}
+
+func (p *Profiles) tlsConfig(settings *ProfileTLS) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         settings.ServerName,
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+	}
+	if settings.CAFile != "" {
+		pem, err := os.ReadFile(p.resolve(settings.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("tls ca_file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("tls ca_file: no certificates found")
+		}
+	}
+	if settings.CertFile != "" || settings.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.resolve(settings.CertFile), p.resolve(settings.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func (p *Profiles) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.dir, path)
+}
+
+// NewClientFromProfile creates a client for the named profile from the
+// profiles file (see ProfilesEnv), so switching between sandbox, staging,
+// and production needs no code changes. opts are applied after the
+// profile's settings and take precedence.
+func NewClientFromProfile(name string, opts ...ClientOption) (*ExampleapiClient, error) {
+	profiles, err := LoadProfiles("")
+	if err != nil {
+		return nil, err
+	}
+	profileOpts, err := profiles.Options(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewExampleapiClient("", append(profileOpts, opts...)...), nil
+}
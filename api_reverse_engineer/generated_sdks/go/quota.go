@@ -0,0 +1,168 @@
+package example_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Quota is the API's rate-limit quota as last reported by response headers
+// (X-RateLimit-*, RateLimit-*, or Retry-After on a 429), less what
+// WaitForQuota has reserved since.
+type Quota struct {
+	Limit     int       // requests per window; zero if not reported
+	Remaining int       // requests left in the current window
+	Reset     time.Time // when the window resets; zero if not reported
+	Updated   time.Time // when a response last reported the quota
+}
+
+// Known reports whether any response has reported the quota yet.
+func (q Quota) Known() bool {
+	return !q.Updated.IsZero()
+}
+
+// quotaTracker is the quota model shared by a client and the clients
+// derived from it.
+type quotaTracker struct {
+	mu    sync.Mutex
+	quota Quota
+}
+
+// The quota headers; those seen in the captured traffic come first.
+var (
+	quotaLimitHeaders     = []string{"X-RateLimit-Limit", "RateLimit-Limit", "X-Rate-Limit-Limit"}
+	quotaRemainingHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining", "X-Rate-Limit-Remaining"}
+	quotaResetHeaders     = []string{"X-RateLimit-Reset", "RateLimit-Reset", "X-Rate-Limit-Reset"}
+)
+
+// update records the quota reported by resp, if any.
+func (t *quotaTracker) update(resp *http.Response) {
+	limit, hasLimit := headerInt(resp.Header, quotaLimitHeaders)
+	remaining, hasRemaining := headerInt(resp.Header, quotaRemainingHeaders)
+	reset, hasReset := headerInt(resp.Header, quotaResetHeaders)
+	now := time.Now()
+	var resetAt time.Time
+	if hasReset {
+		resetAt = resetTime(reset, now)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now); !retryAfter.IsZero() {
+			remaining, hasRemaining = 0, true
+			resetAt, hasReset = retryAfter, true
+		}
+	}
+	if !hasLimit && !hasRemaining && !hasReset {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if hasLimit {
+		t.quota.Limit = int(limit)
+	}
+	if hasRemaining {
+		t.quota.Remaining = int(remaining)
+	}
+	if hasReset {
+		t.quota.Reset = resetAt
+	}
+	t.quota.Updated = now
+}
+
+// reserve takes n requests from the quota, or returns how long to wait
+// before trying again.
+func (t *quotaTracker) reserve(n int) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q := &t.quota
+	if !q.Known() {
+		return 0, true
+	}
+	if q.Remaining < n && !q.Reset.IsZero() && !time.Now().Before(q.Reset) {
+		// The window has reset; assume a full quota until a response says
+		// otherwise.
+		if q.Limit == 0 {
+			return 0, true
+		}
+		q.Remaining, q.Reset = q.Limit, time.Time{}
+	}
+	if q.Remaining >= n {
+		q.Remaining -= n
+		return 0, true
+	}
+	if q.Reset.IsZero() {
+		// Without a reset time there is nothing to wait for.
+		return 0, true
+	}
+	return time.Until(q.Reset), false
+}
+
+// Quota returns the rate-limit quota as last reported by the API.
+func (c *ExampleapiClient) Quota() Quota {
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	return c.quota.quota
+}
+
+// WaitForQuota blocks until the quota allows n more requests, then reserves
+// them, so batch jobs can pace themselves instead of running into 429s. It
+// returns at once while the API has not reported a quota.
+func (c *ExampleapiClient) WaitForQuota(ctx context.Context, n int) error {
+	if q := c.Quota(); q.Limit > 0 && n > q.Limit {
+		return fmt.Errorf("wait for quota: %d requests exceed the limit of %d", n, q.Limit)
+	}
+	for {
+		wait, ok := c.quota.reserve(n)
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// headerInt returns the first of names present in header as an integer.
+func headerInt(header http.Header, names []string) (int64, bool) {
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			n, err := strconv.ParseFloat(value, 64)
+			return int64(n), err == nil
+		}
+	}
+	return 0, false
+}
+
+// resetTime interprets a reset header: Unix milliseconds or seconds for
+// large values, otherwise seconds from now.
+func resetTime(reset int64, now time.Time) time.Time {
+	switch {
+	case reset >= 1e12:
+		return time.UnixMilli(reset)
+	case reset >= 1e9:
+		return time.Unix(reset, 0)
+	default:
+		return now.Add(time.Duration(reset) * time.Second)
+	}
+}
+
+// parseRetryAfter parses Retry-After seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,109 @@
+package example_api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to n requests per interval, as a token
+// bucket holding up to n tokens: bursts of n go out at once, after which
+// calls block until a token frees up or their context is done. It panics
+// unless n and per are positive.
+func WithRateLimit(n int, per time.Duration) ClientOption {
+	if n <= 0 || per <= 0 {
+		panic(fmt.Sprintf("WithRateLimit(%d, %v): n and per must be positive", n, per))
+	}
+	return func(c *ExampleapiClient) {
+		c.limiter = newRateLimiter(n, per)
+	}
+}
+
+// WithQuotaWait makes every call wait, as WaitForQuota(ctx, 1) does, while
+// the API reports its quota used up, instead of sending requests that would
+// be answered with 429 until the window resets.
+func WithQuotaWait() ClientOption {
+	return func(c *ExampleapiClient) {
+		c.quotaWait = true
+	}
+}
+
+// RateLimitState is the client's view of its rate limits: the quota the API
+// last reported, and what is left of the client's own WithRateLimit bucket.
+type RateLimitState struct {
+	Quota
+	// Tokens is the number of requests the client's limiter lets through
+	// without waiting; -1 without WithRateLimit.
+	Tokens int
+}
+
+// RateLimitState returns the client's rate limit state.
+func (c *ExampleapiClient) RateLimitState() RateLimitState {
+	return RateLimitState{Quota: c.Quota(), Tokens: c.limiter.available()}
+}
+
+type rateLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	burst  float64
+	rate   float64 // tokens per second
+	last   time.Time
+}
+
+func newRateLimiter(n int, per time.Duration) *rateLimiter {
+	return &rateLimiter{
+		tokens: float64(n),
+		burst:  float64(n),
+		rate:   float64(n) / per.Seconds(),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last call; l.mu must be held.
+func (l *rateLimiter) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// wait blocks until a token is available and takes it, or until ctx is
+// done; it is a no-op on a nil limiter.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		l.refill()
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// available returns the whole tokens in the bucket, or -1 for a nil
+// limiter.
+func (l *rateLimiter) available() int {
+	if l == nil {
+		return -1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return int(l.tokens)
+}
@@ -0,0 +1,42 @@
+package example_api
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a panic recovered from code running inside a call, such as
+// a custom JSON unmarshaler, a schema mismatch handler, a RoundTripper, or
+// a SecretProvider, returned as the call's error instead of crashing the
+// program.
+type PanicError struct {
+	Operation string // "GET /v1/users/{id}", or "Close"
+	Attempt   int    // 1 for the first attempt
+	Value     interface{}
+	Stack     []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s (attempt %d): panic: %v", e.Operation, e.Attempt, e.Value)
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverPanic turns a panic into a *PanicError stored in *err. It must be
+// deferred directly.
+func recoverPanic(operation string, attempt int, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Operation: operation, Attempt: attempt, Value: r, Stack: debug.Stack()}
+	}
+}
+
+// runCloseFunc calls a WithCloseFunc function, recovering panics.
+func runCloseFunc(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer recoverPanic("Close", 1, &err)
+	return fn(ctx)
+}
@@ -0,0 +1,85 @@
+package example_api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// RedirectPolicy controls how a client follows 3xx responses. The zero value
+// follows up to 10 redirects and strips credentials when a redirect leaves
+// the original origin, e.g. for a 302 to a signed storage URL that rejects
+// requests carrying an Authorization header.
+type RedirectPolicy struct {
+	// NoFollow returns every 3xx response to the caller as a *RedirectError.
+	NoFollow bool
+	// MaxRedirects caps the redirects followed per call; zero means 10.
+	MaxRedirects int
+	// SameHostOnly returns redirects to another host as a *RedirectError
+	// instead of following them.
+	SameHostOnly bool
+	// KeepCredentials re-sends Authorization, cookies, and API key headers
+	// to other origins.
+	KeepCredentials bool
+}
+
+// RedirectError is a 3xx response the client did not follow. The caller
+// can fetch Location itself.
+type RedirectError struct {
+	StatusCode int
+	Location   string
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("redirect not followed: status=%d, location=%s", e.StatusCode, e.Location)
+}
+
+// WithRedirectPolicy sets how the client handles redirects.
+func WithRedirectPolicy(policy RedirectPolicy) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.HTTPClient.CheckRedirect = policy.check
+	}
+}
+
+// credentialHeader matches header names that carry credentials.
+var credentialHeader = regexp.MustCompile(`(?i)^(authorization|cookie)$|api[-_]?key|token|secret|auth`)
+
+// check implements http.Client.CheckRedirect.
+func (p RedirectPolicy) check(req *http.Request, via []*http.Request) error {
+	origin := via[0].URL
+	if p.NoFollow || p.SameHostOnly && req.URL.Host != origin.Host {
+		return http.ErrUseLastResponse
+	}
+	limit := p.MaxRedirects
+	if limit == 0 {
+		limit = 10
+	}
+	if len(via) > limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	if !p.KeepCredentials && !sameOrigin(req.URL, origin) {
+		for name := range req.Header {
+			if credentialHeader.MatchString(name) {
+				req.Header.Del(name)
+			}
+		}
+	}
+	return nil
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host
+}
+
+// redirectError returns a *RedirectError if resp is an unfollowed redirect.
+func redirectError(resp *http.Response) error {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return nil
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return nil
+	}
+	return &RedirectError{StatusCode: resp.StatusCode, Location: location.String()}
+}
@@ -0,0 +1,195 @@
+package example_api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy controls how a call is retried after a 429, a 5xx other than
+// 501, or a transient network error such as a reset connection or a dial
+// timeout. Waits grow exponentially from InitialBackoff, with jitter, unless
+// the response says how long to wait in Retry-After.
+//
+// Calls with a non-idempotent method (POST, PATCH) are retried only when the
+// request cannot have been processed: after a 429, or when the connection
+// was never made, unless they carry an idempotency key (see
+// WithIdempotencyKeys). Set RetryNonIdempotent to retry them like the others.
+type RetryPolicy struct {
+	// MaxAttempts is the most attempts per call, the first included; one or
+	// less disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts; zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier scales the wait after each attempt; zero means 2.
+	Multiplier float64
+	// Jitter varies each wait randomly by up to this fraction of it, so
+	// clients that failed together do not retry together.
+	Jitter float64
+	// MaxRetryAfter is the longest Retry-After honored; a response asking
+	// for a longer wait is returned instead. Zero means a minute.
+	MaxRetryAfter time.Duration
+	// RetryNonIdempotent retries POST and PATCH calls after errors the
+	// server may have acted on.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, waiting about 250ms, then
+// 500ms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Jitter:         0.2,
+}
+
+// WithRetry retries the client's calls with policy. Clients do not retry
+// by default.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.retry = &policy
+	}
+}
+
+// WithRetryPolicy retries the call with policy instead of the client's;
+// RetryPolicy{} disables retries for it.
+func WithRetryPolicy(policy RetryPolicy) RequestOption {
+	return func(o *requestOptions) {
+		o.retry = &policy
+	}
+}
+
+// sendRetrying makes attempts at a call until one succeeds, it fails with
+// an error not worth retrying, or the policy's attempts or the context's
+// deadline run out, returning the last attempt's result. Uploads are only
+// resent when their readers can seek back.
+func (c *ExampleapiClient) sendRetrying(method, endpoint, baseURL, path string, params url.Values, body interface{}, options *requestOptions) ([]byte, error) {
+	policy := c.retry
+	if options.retry != nil {
+		policy = options.retry
+	}
+	for attempt := 1; ; attempt++ {
+		responseBody, err := c.sendAttempt(attempt, method, endpoint, baseURL, path, params, body, options)
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts {
+			return responseBody, err
+		}
+		if upload, ok := body.(*uploadBody); ok && !upload.replayable() {
+			return responseBody, err
+		}
+		wait, ok := policy.delay(policy.replayable(method) || options.idempotencyKey != "", attempt, err, time.Now())
+		if !ok {
+			return responseBody, err
+		}
+		if deadline, ok := options.ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return responseBody, err
+		}
+		c.stats.retry(method + " " + endpoint)
+		if c.logger != nil {
+			c.logger.logRetry(options.ctx, method+" "+endpoint, attempt, wait, err)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-options.ctx.Done():
+			timer.Stop()
+			return responseBody, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *ExampleapiClient) sendAttempt(attempt int, method, endpoint, baseURL, path string, params url.Values, body interface{}, options *requestOptions) (responseBody []byte, err error) {
+	defer recoverPanic(method+" "+endpoint, attempt, &err)
+	options.attempt = attempt
+	return c.send(method, endpoint, baseURL, path, params, body, options)
+}
+
+// delay returns how long to wait before retrying a call that failed with
+// err on attempt, or false if it should not be retried. replayable tells
+// whether the call may be sent again after the server may have acted on it.
+func (p *RetryPolicy) delay(replayable bool, attempt int, err error, now time.Time) (time.Duration, bool) {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		if !apiErr.Temporary() || apiErr.StatusCode == http.StatusNotImplemented {
+			return 0, false
+		}
+		if apiErr.StatusCode != http.StatusTooManyRequests && !replayable {
+			return 0, false
+		}
+		if at := parseRetryAfter(apiErr.Header.Get("Retry-After"), now); !at.IsZero() {
+			limit := p.MaxRetryAfter
+			if limit <= 0 {
+				limit = time.Minute
+			}
+			wait := at.Sub(now)
+			if wait > limit {
+				return 0, false
+			}
+			if wait < 0 {
+				wait = 0
+			}
+			return wait, true
+		}
+	case transientNetworkError(err):
+		var opErr *net.OpError
+		refused := errors.As(err, &opErr) && opErr.Op == "dial"
+		if !refused && !replayable {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff returns the wait after attempt when the response gives none.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(wait)
+}
+
+// replayable reports whether a call with method may be sent again after
+// the server may have acted on it.
+func (p *RetryPolicy) replayable(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// transientNetworkError reports whether err is a network failure that may
+// not recur: a timeout, or a connection refused, reset, or cut short. The
+// call's own context ending is not one.
+func transientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
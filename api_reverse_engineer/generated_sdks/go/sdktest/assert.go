@@ -0,0 +1,153 @@
+package sdktest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/example/example_api/jsondiff"
+)
+
+// Assertions report failures with t.Errorf and return whether they passed,
+// so a test can stop early with `if !sdktest.Equal(...) { return }`.
+
+// NoError asserts that err is nil.
+func NoError(t testing.TB, err error) bool {
+	t.Helper()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return false
+	}
+	return true
+}
+
+// Equal asserts that want and got encode to the same JSON, reporting each
+// differing field by path ("users[0].email: want ..., got ...").
+func Equal(t testing.TB, want, got interface{}) bool {
+	t.Helper()
+	return EqualWith(t, want, got, jsondiff.Options{})
+}
+
+// EqualWith is Equal with ignore rules, for golden responses holding
+// timestamps, identifiers, or arrays in no particular order:
+//
+//	sdktest.EqualWith(t, golden, users, jsondiff.Options{IgnoreTimestamps: true})
+func EqualWith(t testing.TB, want, got interface{}, opts jsondiff.Options) bool {
+	t.Helper()
+	diffs, err := jsondiff.Compare(want, got, opts)
+	if err != nil {
+		t.Errorf("cannot compare: %v", err)
+		return false
+	}
+	if len(diffs) > 0 {
+		t.Errorf("values differ:\n  %s", strings.Join(describeDiffs(diffs), "\n  "))
+		return false
+	}
+	return true
+}
+
+// Diff lists the fields in which want and got differ after JSON encoding,
+// which makes SDK structs, maps and raw JSON comparable with each other.
+func Diff(want, got interface{}) ([]string, error) {
+	diffs, err := jsondiff.Compare(want, got, jsondiff.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return describeDiffs(diffs), nil
+}
+
+// JSONSubset asserts that every field in the JSON document want is present
+// in got with an equal value; fields of got not mentioned in want are
+// ignored. got may be raw JSON ([]byte, string, json.RawMessage) or any
+// value that encodes to JSON, such as an SDK response.
+func JSONSubset(t testing.TB, want string, got interface{}) bool {
+	t.Helper()
+	diffs, err := jsondiff.Compare(want, got, jsondiff.Options{Subset: true})
+	if err != nil {
+		t.Errorf("cannot compare: %v", err)
+		return false
+	}
+	if len(diffs) > 0 {
+		t.Errorf("JSON does not contain expected fields:\n  %s", strings.Join(describeDiffs(diffs), "\n  "))
+		return false
+	}
+	return true
+}
+
+// describeDiffs words differences between want (A) and got (B).
+func describeDiffs(diffs []jsondiff.Difference) []string {
+	lines := make([]string, len(diffs))
+	for i, d := range diffs {
+		path := strings.TrimPrefix(strings.TrimPrefix(d.Path, "$"), ".")
+		if path == "" {
+			path = "(root)"
+		}
+		switch d.Kind {
+		case jsondiff.Removed:
+			lines[i] = fmt.Sprintf("%s: missing, want %s", path, jsondiff.Describe(d.A))
+		case jsondiff.Added:
+			lines[i] = fmt.Sprintf("%s: unexpected %s", path, jsondiff.Describe(d.B))
+		default:
+			lines[i] = fmt.Sprintf("%s: want %s, got %s", path, jsondiff.Describe(d.A), jsondiff.Describe(d.B))
+		}
+	}
+	return lines
+}
+
+// Status asserts the status code of a response.
+func Status(t testing.TB, resp *http.Response, want int) bool {
+	t.Helper()
+	if resp == nil {
+		t.Errorf("status: want %d, got no response", want)
+		return false
+	}
+	if resp.StatusCode != want {
+		t.Errorf("status: want %d, got %d", want, resp.StatusCode)
+		return false
+	}
+	return true
+}
+
+var statusPattern = regexp.MustCompile(`status=(\d{3})`)
+
+// ErrorStatus asserts that err is an API error carrying the HTTP status
+// want.
+func ErrorStatus(t testing.TB, err error, want int) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("want API error with status %d, got nil", want)
+		return false
+	}
+	got, ok := StatusOf(err)
+	if !ok {
+		t.Errorf("want API error with status %d, got %v", want, err)
+		return false
+	}
+	if got != want {
+		t.Errorf("error status: want %d, got %d (%v)", want, got, err)
+		return false
+	}
+	return true
+}
+
+// StatusOf extracts the HTTP status from an error returned by the client.
+func StatusOf(err error) (int, bool) {
+	var coded interface{ StatusCode() int }
+	if errors.As(err, &coded) {
+		return coded.StatusCode(), true
+	}
+	text := err.Error()
+	var detailed interface{ Detail() string }
+	if errors.As(err, &detailed) {
+		text = detailed.Detail()
+	}
+	if m := statusPattern.FindStringSubmatch(text); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		return status, true
+	}
+	return 0, false
+}
@@ -0,0 +1,301 @@
+// Package sdktest provides helpers for testing code that uses the SDK.
+package sdktest
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//go:embed samples.json
+var samplesJSON []byte
+
+// Sample is one captured response, with the path parameters and query
+// string of the request that produced it.
+type Sample struct {
+	Path     string            `json:"path"`
+	Params   map[string]string `json:"params,omitempty"`
+	Query    string            `json:"query,omitempty"`
+	Status   int               `json:"status"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     string            `json:"body,omitempty"`
+	Encoding string            `json:"encoding,omitempty"`
+}
+
+// Request is a request received by a FixtureServer.
+type Request struct {
+	Method   string
+	Path     string
+	Query    string
+	Header   http.Header
+	Body     []byte
+	Endpoint string
+}
+
+type route struct {
+	method   string
+	pattern  string
+	segments []string
+	literals int
+	samples  []Sample
+	handler  http.Handler
+}
+
+// FixtureServer is an httptest.Server answering every captured endpoint
+// with its captured responses.
+type FixtureServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   []*route
+	requests []Request
+}
+
+// NewFixtureServer starts a FixtureServer that is closed when the test
+// ends. Point the client at it with NewExampleapiClient(srv.URL).
+//
+// A request is answered with the captured sample whose path parameters
+// and query match best; when no sample has the requested path parameters,
+// the first successful sample is served with the captured parameter values
+// in its body replaced by the requested ones, so GET /users/42 returns a
+// user with id 42.
+func NewFixtureServer(t testing.TB) *FixtureServer {
+	t.Helper()
+	var samples map[string][]Sample
+	if err := json.Unmarshal(samplesJSON, &samples); err != nil {
+		t.Fatalf("sdktest: invalid embedded samples: %v", err)
+	}
+	s := &FixtureServer{}
+	for endpoint, endpointSamples := range samples {
+		s.addRoute(endpoint, endpointSamples, nil)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle overrides the response for endpoint ("GET /v1/users/{id}"),
+// adding the route if it was never captured.
+func (s *FixtureServer) Handle(endpoint string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.routes {
+		if r.method+" "+r.pattern == endpoint {
+			r.handler = handler
+			return
+		}
+	}
+	s.addRoute(endpoint, nil, handler)
+}
+
+// Endpoints lists the endpoints the server answers.
+func (s *FixtureServer) Endpoints() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoints := make([]string, len(s.routes))
+	for i, r := range s.routes {
+		endpoints[i] = r.method + " " + r.pattern
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// Requests returns the requests received so far.
+func (s *FixtureServer) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *FixtureServer) addRoute(endpoint string, samples []Sample, handler http.Handler) {
+	method, pattern, _ := strings.Cut(endpoint, " ")
+	r := &route{method: method, pattern: pattern, segments: strings.Split(pattern, "/"), samples: samples, handler: handler}
+	for _, segment := range r.segments {
+		if !isParam(segment) {
+			r.literals++
+		}
+	}
+	s.routes = append(s.routes, r)
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func (r *route) match(path string) (map[string]string, bool) {
+	segments := strings.Split(path, "/")
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range r.segments {
+		switch {
+		case isParam(segment) && segments[i] != "":
+			params[strings.Trim(segment, "{}")] = segments[i]
+		case segment != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *FixtureServer) serve(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	var (
+		best       *route
+		params     map[string]string
+		pathExists bool
+	)
+	for _, r := range s.routes {
+		p, ok := r.match(req.URL.Path)
+		if !ok {
+			continue
+		}
+		pathExists = true
+		if r.method == req.Method && (best == nil || r.literals > best.literals) {
+			best, params = r, p
+		}
+	}
+	recorded := Request{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery, Header: req.Header.Clone(), Body: body}
+	var (
+		handler http.Handler
+		samples []Sample
+	)
+	if best != nil {
+		recorded.Endpoint = best.method + " " + best.pattern
+		handler, samples = best.handler, best.samples
+	}
+	s.requests = append(s.requests, recorded)
+	s.mu.Unlock()
+
+	switch {
+	case best == nil && pathExists:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("no captured %s for %s", req.Method, req.URL.Path))
+	case best == nil:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no captured endpoint for %s %s", req.Method, req.URL.Path))
+	case handler != nil:
+		handler.ServeHTTP(w, req)
+	case len(samples) == 0:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeSample(w, pickSample(samples, params, req.URL.RawQuery), params)
+	}
+}
+
+func pickSample(samples []Sample, params map[string]string, query string) Sample {
+	best, bestScore := samples[0], -1
+	for _, sample := range samples {
+		score := 0
+		if sameParams(sample.Params, params) {
+			score += 4
+		}
+		if sample.Query == query {
+			score += 2
+		}
+		if sample.Status >= 200 && sample.Status < 300 {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = sample, score
+		}
+	}
+	return best
+}
+
+func sameParams(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, value := range a {
+		if b[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func writeSample(w http.ResponseWriter, sample Sample, params map[string]string) {
+	body := []byte(sample.Body)
+	if sample.Encoding == "base64" {
+		body, _ = base64.StdEncoding.DecodeString(sample.Body)
+	} else if !sameParams(sample.Params, params) {
+		body = substituteParams(body, sample.Params, params)
+	}
+	for name, value := range sample.Headers {
+		w.Header().Set(name, value)
+	}
+	status := sample.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// substituteParams rewrites values in a JSON body that equal a captured
+// path parameter to the requested value, keeping numbers numeric.
+func substituteParams(body []byte, captured, requested map[string]string) []byte {
+	replacements := make(map[string]string)
+	for name, value := range captured {
+		if value != "" && requested[name] != "" {
+			replacements[value] = requested[name]
+		}
+	}
+	if len(replacements) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if dec.Decode(&doc) != nil {
+		return body
+	}
+	out, err := json.Marshal(substitute(doc, replacements))
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func substitute(v interface{}, replacements map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = substitute(child, replacements)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = substitute(child, replacements)
+		}
+	case string:
+		if replacement, ok := replacements[v]; ok {
+			return replacement
+		}
+	case json.Number:
+		if replacement, ok := replacements[v.String()]; ok {
+			if _, err := json.Number(replacement).Float64(); err == nil {
+				return json.Number(replacement)
+			}
+			return replacement
+		}
+	}
+	return v
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,171 @@
+package example_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSecretNotFound is returned (wrapped) by the bundled providers for
+// secrets they do not hold.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider supplies credentials by name, so they never have to be
+// hardcoded into client construction. Implement it over Vault, AWS Secrets
+// Manager, or the OS keychain; EnvSecrets and FileSecrets cover the simple
+// cases.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Secret implements SecretProvider.
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// EnvSecrets reads secrets from environment variables named Prefix+name.
+type EnvSecrets struct {
+	Prefix string
+}
+
+// Secret implements SecretProvider.
+func (e EnvSecrets) Secret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(e.Prefix + name)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s", ErrSecretNotFound, e.Prefix+name)
+	}
+	return value, nil
+}
+
+// FileSecrets reads secrets from files named after them in Dir, as mounted
+// by Docker and Kubernetes. Surrounding whitespace is trimmed.
+type FileSecrets struct {
+	Dir string
+}
+
+// Secret implements SecretProvider.
+func (f FileSecrets) Secret(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(f.Dir, filepath.Base(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %v", ErrSecretNotFound, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SecretCache caches the secrets of another provider for a TTL (forever
+// when zero), so remote stores are asked once per rotation rather than on
+// every request. It is safe for concurrent use.
+type SecretCache struct {
+	provider SecretProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value   string
+	fetched time.Time
+}
+
+// NewSecretCache wraps provider with a cache.
+func NewSecretCache(provider SecretProvider, ttl time.Duration) *SecretCache {
+	return &SecretCache{provider: provider, ttl: ttl, entries: make(map[string]cachedSecret)}
+}
+
+// Secret implements SecretProvider.
+func (c *SecretCache) Secret(ctx context.Context, name string) (string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	c.mu.Unlock()
+	if ok && (c.ttl == 0 || time.Since(entry.fetched) < c.ttl) {
+		return entry.value, nil
+	}
+
+	value, err := c.provider.Secret(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.entries[name] = cachedSecret{value: value, fetched: time.Now()}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate drops the cached value of name, e.g. after it was rotated.
+func (c *SecretCache) Invalidate(name string) {
+	c.mu.Lock()
+	delete(c.entries, name)
+	c.mu.Unlock()
+}
+
+func (c *SecretCache) expire(name, _ string) {
+	c.Invalidate(name)
+}
+
+// credentialSource supplies credential values; expire drops a value the API
+// rejected.
+type credentialSource interface {
+	Secret(ctx context.Context, name string) (string, error)
+	expire(name, rejected string)
+}
+
+// secretCredential is a header filled from a secret on every request.
+type secretCredential struct {
+	secrets credentialSource
+	name    string
+	header  string
+	prefix  string
+}
+
+// WithSecretToken sends the secret name from provider as the bearer token.
+// It is fetched on first use and cached for ttl; a 401 response drops the
+// cached value, so the call after a rotation picks up the new token.
+func WithSecretToken(provider SecretProvider, name string, ttl time.Duration) ClientOption {
+	return withSecret(provider, name, ttl, "Authorization", "Bearer ")
+}
+
+// WithSecretHeader is WithSecretToken for API keys sent verbatim in header.
+func WithSecretHeader(provider SecretProvider, name, header string, ttl time.Duration) ClientOption {
+	return withSecret(provider, name, ttl, header, "")
+}
+
+func withSecret(provider SecretProvider, name string, ttl time.Duration, header, prefix string) ClientOption {
+	credential := &secretCredential{secrets: NewSecretCache(provider, ttl), name: name, header: header, prefix: prefix}
+	return func(c *ExampleapiClient) {
+		c.credentials = append(c.credentials, credential)
+	}
+}
+
+// applyCredentials sets the secret headers on req.
+func (c *ExampleapiClient) applyCredentials(req *http.Request) error {
+	for _, credential := range c.credentials {
+		value, err := credential.secrets.Secret(req.Context(), credential.name)
+		if err != nil {
+			return fmt.Errorf("secret %s: %w", credential.name, err)
+		}
+		req.Header.Set(credential.header, credential.prefix+value)
+	}
+	return nil
+}
+
+// expireCredentials drops the cached secrets req carried after the API
+// rejected them.
+func (c *ExampleapiClient) expireCredentials(req *http.Request) {
+	for _, credential := range c.credentials {
+		rejected := strings.TrimPrefix(req.Header.Get(credential.header), credential.prefix)
+		credential.secrets.expire(credential.name, rejected)
+	}
+}
@@ -0,0 +1,300 @@
+package example_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Serializer encodes request bodies and decodes response bodies for one
+// media type. Register implementations for msgpack or vendor media types
+// with WithSerializer.
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// requestContentTypes maps the operations captured with request bodies in
+// another media type than JSON to that media type.
+var requestContentTypes = map[string]string{}
+
+// responseContentTypes maps the operations captured with structured response
+// bodies in another media type than JSON to that media type.
+var responseContentTypes = map[string]string{}
+
+// defaultSerializers are the serializers every client has; media types with
+// a +json or +xml suffix use the JSON and XML ones.
+var defaultSerializers = map[string]Serializer{
+	"application/json":                  JSONSerializer{},
+	"application/x-www-form-urlencoded": FormSerializer{},
+	"application/xml":                   XMLSerializer{},
+	"text/xml":                          XMLSerializer{},
+}
+
+// WithSerializer encodes and decodes mediaType bodies with s, replacing the
+// default for that media type.
+func WithSerializer(mediaType string, s Serializer) ClientOption {
+	return func(c *ExampleapiClient) {
+		if c.serializers == nil {
+			c.serializers = make(map[string]Serializer)
+		}
+		c.serializers[mediaType] = s
+	}
+}
+
+// serializer returns the serializer for mediaType, with the client's Codec
+// in place of JSONSerializer.
+func (c *ExampleapiClient) serializer(mediaType string) (Serializer, error) {
+	s, err := c.mediaSerializer(mediaType)
+	if _, isJSON := s.(JSONSerializer); isJSON {
+		return c.jsonCodec(), nil
+	}
+	return s, err
+}
+
+// mediaSerializer returns the serializer registered for mediaType.
+func (c *ExampleapiClient) mediaSerializer(mediaType string) (Serializer, error) {
+	if s, ok := c.serializers[mediaType]; ok {
+		return s, nil
+	}
+	if s, ok := defaultSerializers[mediaType]; ok {
+		return s, nil
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return JSONSerializer{}, nil
+	case strings.HasSuffix(mediaType, "+xml"):
+		return XMLSerializer{}, nil
+	}
+	return nil, fmt.Errorf("no serializer for %s; register one with WithSerializer", mediaType)
+}
+
+// responseSerializer returns the serializer for operation's responses, or
+// nil for JSON.
+func (c *ExampleapiClient) responseSerializer(operation string) (Serializer, error) {
+	mediaType, ok := responseContentTypes[operation]
+	if !ok {
+		return nil, nil
+	}
+	s, err := c.mediaSerializer(mediaType)
+	if _, isJSON := s.(JSONSerializer); isJSON {
+		return nil, nil
+	}
+	return s, err
+}
+
+// JSONSerializer is the default Serializer.
+type JSONSerializer struct{}
+
+// Marshal implements Serializer.
+func (JSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Serializer.
+func (JSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// FormSerializer encodes bodies as application/x-www-form-urlencoded, using
+// the JSON field names. Arrays become repeated fields and nested objects
+// JSON text.
+type FormSerializer struct{}
+
+// Marshal implements Serializer.
+func (FormSerializer) Marshal(v interface{}) ([]byte, error) {
+	form, err := formValues(v)
+	if err != nil {
+		return nil, fmt.Errorf("form: %w", err)
+	}
+	return []byte(form.Encode()), nil
+}
+
+// formValues returns the fields of v as form values, named as in JSON.
+// Arrays become repeated fields and nested objects JSON text.
+func formValues(v interface{}) (url.Values, error) {
+	fields, err := jsonObject(v)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	for name, value := range fields {
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, value := range values {
+			switch value := value.(type) {
+			case nil:
+			case string:
+				form.Add(name, value)
+			case map[string]interface{}, []interface{}:
+				text, _ := json.Marshal(value)
+				form.Add(name, string(text))
+			default:
+				form.Add(name, fmt.Sprint(value))
+			}
+		}
+	}
+	return form, nil
+}
+
+// Unmarshal implements Serializer; repeated fields decode as arrays.
+func (FormSerializer) Unmarshal(data []byte, v interface{}) error {
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return fmt.Errorf("form: %w", err)
+	}
+	fields := make(map[string]interface{}, len(form))
+	for name, values := range form {
+		if len(values) == 1 {
+			fields[name] = values[0]
+		} else {
+			fields[name] = values
+		}
+	}
+	return remarshal(fields, v)
+}
+
+// XMLSerializer maps XML to and from the JSON shape of the generated types:
+// child elements become fields, repeated elements arrays, and attributes
+// fields too. Root names the document element of encoded bodies; it
+// defaults to "request".
+type XMLSerializer struct {
+	Root string
+}
+
+// Marshal implements Serializer.
+func (s XMLSerializer) Marshal(v interface{}) ([]byte, error) {
+	var tree interface{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	root := s.Root
+	if root == "" {
+		root = "request"
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	writeXML(&buf, root, tree)
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Serializer; the document element itself is dropped.
+func (XMLSerializer) Unmarshal(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("xml: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			tree, err := readXML(decoder, start)
+			if err != nil {
+				return fmt.Errorf("xml: %w", err)
+			}
+			return remarshal(tree, v)
+		}
+	}
+}
+
+// readXML reads the element opened by start as a string (text only) or a
+// map of its attributes and children.
+func readXML(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		fields[attr.Name.Local] = attr.Value
+	}
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			child, err := readXML(decoder, token)
+			if err != nil {
+				return nil, err
+			}
+			name := token.Name.Local
+			switch existing := fields[name].(type) {
+			case nil:
+				fields[name] = child
+			case []interface{}:
+				fields[name] = append(existing, child)
+			default:
+				fields[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			if len(fields) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+			return fields, nil
+		}
+	}
+}
+
+// writeXML writes value as element name.
+func writeXML(w io.Writer, name string, value interface{}) {
+	switch value := value.(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range value {
+			writeXML(w, name, item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(w, "<%s>", name)
+		for _, key := range keys {
+			writeXML(w, key, value[key])
+		}
+		fmt.Fprintf(w, "</%s>", name)
+	default:
+		fmt.Fprintf(w, "<%s>", name)
+		xml.EscapeText(w, []byte(fmt.Sprint(value)))
+		fmt.Fprintf(w, "</%s>", name)
+	}
+}
+
+// jsonObject returns v's JSON fields, keeping numbers exact.
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// remarshal decodes a generic tree into v through JSON, so the generated
+// types' JSON field names apply.
+func remarshal(tree interface{}, v interface{}) error {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
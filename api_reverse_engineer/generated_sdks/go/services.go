@@ -0,0 +1,67 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// V1Service holds the methods of the /v1 API by resource:
+//
+//	result, err := client.V1.Posts.List(ctx)
+type V1Service struct {
+	Posts *V1PostsService
+	Users *V1UsersService
+}
+
+// V1PostsService holds the methods of /v1/posts.
+type V1PostsService struct {
+	client *ExampleapiClient
+}
+
+// List performs GET /v1/posts
+func (s *V1PostsService) List(ctx context.Context, opts ...RequestOption) (*ListPostsResponse, error) {
+	return s.client.ListPosts(ctx, opts...)
+}
+
+// Create performs POST /v1/posts
+func (s *V1PostsService) Create(ctx context.Context, data *CreatePostRequest, opts ...RequestOption) (*CreatePostResponse, error) {
+	return s.client.CreatePost(ctx, data, opts...)
+}
+
+// V1UsersService holds the methods of /v1/users.
+type V1UsersService struct {
+	client *ExampleapiClient
+}
+
+// List performs GET /v1/users
+func (s *V1UsersService) List(ctx context.Context, params *ListUsersParams, opts ...RequestOption) (*ListUsersResponse, error) {
+	return s.client.ListUsers(ctx, params, opts...)
+}
+
+// Create performs POST /v1/users
+func (s *V1UsersService) Create(ctx context.Context, data *CreateUserRequest, opts ...RequestOption) (*CreateUserResponse, error) {
+	return s.client.CreateUser(ctx, data, opts...)
+}
+
+// Get performs GET /v1/users/{id}
+func (s *V1UsersService) Get(ctx context.Context, id int64, opts ...RequestOption) (*GetUserResponse, error) {
+	return s.client.GetUser(ctx, id, opts...)
+}
+
+// Update performs PUT /v1/users/{id}
+func (s *V1UsersService) Update(ctx context.Context, id int64, data *UpdateUserRequest, opts ...RequestOption) (*UpdateUserResponse, error) {
+	return s.client.UpdateUser(ctx, id, data, opts...)
+}
+
+// Delete performs DELETE /v1/users/{id}
+func (s *V1UsersService) Delete(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error) {
+	return s.client.DeleteUser(ctx, id, opts...)
+}
+
+// bindVersions points the client's version namespaces at c.
+func (c *ExampleapiClient) bindVersions() {
+	c.V1 = &V1Service{
+		Posts: &V1PostsService{c},
+		Users: &V1UsersService{c},
+	}
+}
@@ -0,0 +1,218 @@
+package example_api
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response signature headers seen in the captured traffic, or the common
+// X-Signature and X-Timestamp when none were.
+const (
+	signatureHeader          = "X-Signature"
+	signatureTimestampHeader = "X-Timestamp"
+)
+
+// SignatureAlgorithm names a response signature algorithm, using the JWS
+// algorithm names.
+type SignatureAlgorithm string
+
+// Supported response signature algorithms.
+const (
+	SignatureHS256 SignatureAlgorithm = "HS256" // HMAC-SHA256, the default
+	SignatureHS512 SignatureAlgorithm = "HS512"
+	SignatureRS256 SignatureAlgorithm = "RS256"
+	SignaturePS256 SignatureAlgorithm = "PS256"
+	SignatureES256 SignatureAlgorithm = "ES256"
+	SignatureEdDSA SignatureAlgorithm = "EdDSA"
+)
+
+// SignatureKeySource returns the key verifying a response signature: a
+// []byte HMAC secret, *rsa.PublicKey, *ecdsa.PublicKey, or
+// ed25519.PublicKey. keyID is the signature's key ID ("keyId=..." in the
+// signature header), or empty.
+type SignatureKeySource interface {
+	SignatureKey(keyID string) (interface{}, error)
+}
+
+// SignatureKeyFunc adapts a function to SignatureKeySource.
+type SignatureKeyFunc func(keyID string) (interface{}, error)
+
+// SignatureKey implements SignatureKeySource.
+func (f SignatureKeyFunc) SignatureKey(keyID string) (interface{}, error) {
+	return f(keyID)
+}
+
+// StaticSignatureKey returns a SignatureKeySource for a single key.
+func StaticSignatureKey(key interface{}) SignatureKeySource {
+	return SignatureKeyFunc(func(string) (interface{}, error) { return key, nil })
+}
+
+// SignatureVerifier checks signed responses before they are decoded. The
+// signature covers the timestamp header's value, a dot, and the raw body,
+// unless Payload says otherwise. Signature headers may hold hex or base64,
+// optionally prefixed ("sha256=..."), or Stripe-style "t=...,v1=..." pairs
+// carrying the timestamp themselves.
+type SignatureVerifier struct {
+	Keys      SignatureKeySource
+	Algorithm SignatureAlgorithm // default SignatureHS256
+	// Header and TimestampHeader default to the headers seen in the capture.
+	Header          string
+	TimestampHeader string
+	// MaxSkew rejects timestamps further than this from now; zero means five
+	// minutes, negative disables the check.
+	MaxSkew time.Duration
+	// Payload builds the signed bytes from the timestamp and body.
+	Payload func(timestamp string, body []byte) []byte
+	// AllowUnsigned accepts responses without a signature header.
+	AllowUnsigned bool
+}
+
+// SignatureError reports a response whose signature is missing or invalid.
+// The response body is discarded.
+type SignatureError struct {
+	Operation string
+	Reason    string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("%s: response signature rejected: %s", e.Operation, e.Reason)
+}
+
+// WithResponseSignatures verifies every response with v, rejecting tampered
+// or replayed responses with a *SignatureError. Downloaded and streamed
+// bodies are held in memory until their end, where they are verified.
+func WithResponseSignatures(v SignatureVerifier) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.signatures = &v
+	}
+}
+
+// verify checks the signature of a response; it is a no-op on a nil
+// verifier.
+func (v *SignatureVerifier) verify(operation string, resp *http.Response, body []byte) error {
+	if v == nil {
+		return nil
+	}
+	reject := func(format string, args ...interface{}) error {
+		return &SignatureError{Operation: operation, Reason: fmt.Sprintf(format, args...)}
+	}
+	if v.Keys == nil {
+		return reject("SignatureVerifier has no Keys")
+	}
+	header := v.Header
+	if header == "" {
+		header = signatureHeader
+	}
+	timestampHeader := v.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = signatureTimestampHeader
+	}
+
+	value := resp.Header.Get(header)
+	if value == "" {
+		if v.AllowUnsigned {
+			return nil
+		}
+		return reject("missing %s header", header)
+	}
+	encoded, timestamp, keyID := parseSignatureHeader(value)
+	if timestamp == "" {
+		timestamp = resp.Header.Get(timestampHeader)
+	}
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return reject("%v", err)
+	}
+	signature, ok := decodeSignature(encoded)
+	if !ok {
+		return reject("undecodable signature %q", encoded)
+	}
+
+	key, err := v.Keys.SignatureKey(keyID)
+	if err != nil {
+		return reject("key: %v", err)
+	}
+	payload := []byte(timestamp + "." + string(body))
+	if v.Payload != nil {
+		payload = v.Payload(timestamp, body)
+	}
+	algorithm := v.Algorithm
+	if algorithm == "" {
+		algorithm = SignatureHS256
+	}
+	if err := verifySignature(string(algorithm), key, payload, signature); err != nil {
+		return reject("%v", err)
+	}
+	return nil
+}
+
+// checkTimestamp rejects timestamps outside MaxSkew of now. Timestamps are
+// Unix seconds or milliseconds, or RFC 3339.
+func (v *SignatureVerifier) checkTimestamp(timestamp string) error {
+	if v.MaxSkew < 0 {
+		return nil
+	}
+	maxSkew := v.MaxSkew
+	if maxSkew == 0 {
+		maxSkew = 5 * time.Minute
+	}
+	if timestamp == "" {
+		return fmt.Errorf("missing timestamp")
+	}
+	var signed time.Time
+	if n, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		signed = time.Unix(n, 0)
+		if n >= 1e11 {
+			signed = time.UnixMilli(n)
+		}
+	} else if signed, err = time.Parse(time.RFC3339, timestamp); err != nil {
+		return fmt.Errorf("unparseable timestamp %q", timestamp)
+	}
+	if skew := time.Since(signed); skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("timestamp %s is outside the allowed %s skew", timestamp, maxSkew)
+	}
+	return nil
+}
+
+// parseSignatureHeader splits "t=...,v1=...,keyId=..." style values; plain
+// values are returned as the signature, minus any "algorithm=" prefix.
+func parseSignatureHeader(value string) (signature, timestamp, keyID string) {
+	if !strings.Contains(value, ",") {
+		// Base64 padding is the only "=" a bare signature can contain.
+		if _, after, ok := strings.Cut(value, "="); ok && strings.Trim(after, "=") != "" {
+			return after, "", ""
+		}
+		return value, "", ""
+	}
+	for _, part := range strings.Split(value, ",") {
+		name, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch strings.ToLower(name) {
+		case "t", "ts", "timestamp":
+			timestamp = val
+		case "keyid", "kid":
+			keyID = strings.Trim(val, `"`)
+		case "v1", "s", "sig", "signature", "sha256", "sha512":
+			if signature == "" {
+				signature = strings.Trim(val, `"`)
+			}
+		}
+	}
+	return signature, timestamp, keyID
+}
+
+// decodeSignature decodes hex, or standard or URL-safe base64.
+func decodeSignature(encoded string) ([]byte, bool) {
+	if b, err := hex.DecodeString(encoded); err == nil {
+		return b, true
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := encoding.DecodeString(encoded); err == nil {
+			return b, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,112 @@
+//go:build go1.21
+
+package example_api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// LogLevels are the levels WithLogger logs at.
+type LogLevels struct {
+	Success slog.Level // an attempt answered with a status below 400
+	Failure slog.Level // an attempt answered with an error status, or not at all
+	Retry   slog.Level // a failed attempt about to be retried
+	// Headers is the level the logger must be enabled for to log request
+	// and response headers as well, sensitive ones redacted.
+	Headers slog.Level
+}
+
+// DefaultLogLevels logs attempts at Info, failures at Error, and retries at
+// Warn, with headers when the logger is enabled for Debug.
+var DefaultLogLevels = LogLevels{
+	Success: slog.LevelInfo,
+	Failure: slog.LevelError,
+	Retry:   slog.LevelWarn,
+	Headers: slog.LevelDebug,
+}
+
+// WithLogger logs every HTTP attempt the client makes to logger, with its
+// method, URL, status, duration, and attempt number, and every retry with
+// the wait before it. Credential query parameters are redacted from URLs,
+// and Authorization, cookies, API keys, and the client's credential headers
+// from logged headers.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.logger = &slogLogger{logger: logger, levels: DefaultLogLevels, client: c}
+	}
+}
+
+// WithLogLevels changes the levels of the logger set by an earlier
+// WithLogger; without one it does nothing.
+func WithLogLevels(levels LogLevels) ClientOption {
+	return func(c *ExampleapiClient) {
+		if l, ok := c.logger.(*slogLogger); ok {
+			l.levels = levels
+		}
+	}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+	levels LogLevels
+	client *ExampleapiClient
+}
+
+func (l *slogLogger) logAttempt(ctx context.Context, operation string, attempt int, req *http.Request, resp *http.Response, took time.Duration, err error) {
+	level := l.levels.Success
+	if err != nil || resp.StatusCode >= 400 {
+		level = l.levels.Failure
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.String("method", req.Method),
+		slog.String("url", redactQuery(req.URL).String()),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", took),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if l.logger.Enabled(ctx, l.levels.Headers) {
+		attrs = append(attrs, l.headers("request_headers", req.Header))
+		if resp != nil {
+			attrs = append(attrs, l.headers("response_headers", resp.Header))
+		}
+	}
+	l.logger.LogAttrs(ctx, level, "http request", attrs...)
+}
+
+func (l *slogLogger) logRetry(ctx context.Context, operation string, attempt int, wait time.Duration, err error) {
+	l.logger.LogAttrs(ctx, l.levels.Retry, "retrying http request",
+		slog.String("operation", operation),
+		slog.Int("attempt", attempt),
+		slog.Duration("wait", wait),
+		slog.String("error", err.Error()),
+	)
+}
+
+// headers returns header as a group, with sensitive values redacted.
+func (l *slogLogger) headers(key string, header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for _, name := range sortedKeys(header) {
+		value := header.Values(name)
+		if l.client.isSecretHeader(name) {
+			value = []string{"REDACTED"}
+		}
+		if len(value) == 1 {
+			attrs = append(attrs, slog.String(name, value[0]))
+		} else {
+			attrs = append(attrs, slog.Any(name, value))
+		}
+	}
+	return slog.Group(key, attrs...)
+}
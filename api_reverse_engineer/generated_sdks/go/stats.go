@@ -0,0 +1,242 @@
+package example_api
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats is a snapshot of a client's internal counters.
+type ClientStats struct {
+	Requests         int64            `json:"requests"`
+	RequestErrors    int64            `json:"request_errors"`
+	InFlight         int64            `json:"in_flight"`
+	Responses        map[string]int64 `json:"responses"`
+	Retries          int64            `json:"retries"`
+	BytesSent        int64            `json:"bytes_sent"`
+	BytesReceived    int64            `json:"bytes_received"`
+	SchemaMismatches int64            `json:"schema_mismatches"`
+	// Endpoints breaks the counters down by operation.
+	Endpoints map[string]EndpointStats `json:"endpoints,omitempty"`
+}
+
+// EndpointStats summarizes the calls to one operation since the client was
+// created. Latencies are the time until the response headers arrived;
+// errors are requests that got no response or an error status. Bytes are
+// those of the bodies on the wire, so compressed ones count as compressed.
+type EndpointStats struct {
+	Requests      int64            `json:"requests"`
+	Errors        int64            `json:"errors"`
+	ErrorRate     float64          `json:"error_rate"`
+	Responses     map[string]int64 `json:"responses"`
+	Retries       int64            `json:"retries"`
+	BytesSent     int64            `json:"bytes_sent"`
+	BytesReceived int64            `json:"bytes_received"`
+	// Latency is the total latency of the requests that got a response;
+	// divided by their number it is the mean.
+	Latency time.Duration `json:"latency"`
+	// P50, P95, and P99 are zero unless the client was created
+	// WithLatencyStats.
+	P50 time.Duration `json:"p50"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+	// Captured is the median latency recorded in the capture the SDK was
+	// generated from, or zero if the capture had no timings.
+	Captured time.Duration `json:"captured,omitempty"`
+}
+
+type clientStats struct {
+	requests atomic.Int64
+	errors   atomic.Int64
+	inFlight atomic.Int64
+	retries  atomic.Int64
+	sent     atomic.Int64
+	received atomic.Int64
+
+	mu        sync.Mutex
+	responses map[string]int64
+	endpoints map[string]*endpointStats
+	latency   bool // set by WithLatencyStats
+}
+
+// endpointStats counts the requests to one operation. Its byte counts are
+// added to as bodies are read, without holding clientStats.mu.
+type endpointStats struct {
+	requests  int64
+	errors    int64
+	retries   int64
+	responses map[string]int64
+	latency   time.Duration
+	digest    *tdigest // nil unless WithLatencyStats
+	sent      atomic.Int64
+	received  atomic.Int64
+}
+
+// endpoint returns the counters of operation, creating them on first use.
+// s.mu must be held.
+func (s *clientStats) endpoint(operation string) *endpointStats {
+	if s.endpoints == nil {
+		s.endpoints = make(map[string]*endpointStats)
+	}
+	e := s.endpoints[operation]
+	if e == nil {
+		e = &endpointStats{responses: make(map[string]int64)}
+		if s.latency {
+			e.digest = &tdigest{}
+		}
+		s.endpoints[operation] = e
+	}
+	return e
+}
+
+// requestStats tracks one request between clientStats.start and finish.
+type requestStats struct {
+	stats    *clientStats
+	endpoint *endpointStats
+	started  time.Time
+}
+
+// start records a request to operation about to be sent, and counts the
+// bytes of its body as the transport reads them.
+func (s *clientStats) start(operation string, req *http.Request) *requestStats {
+	s.requests.Add(1)
+	s.inFlight.Add(1)
+	s.mu.Lock()
+	e := s.endpoint(operation)
+	s.mu.Unlock()
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &countingBody{req.Body, &s.sent, &e.sent}
+	}
+	return &requestStats{stats: s, endpoint: e, started: time.Now()}
+}
+
+// finish records the response to the request, nil when none arrived, and
+// counts the bytes of its body as they are read.
+func (r *requestStats) finish(resp *http.Response) {
+	s, e := r.stats, r.endpoint
+	s.inFlight.Add(-1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.requests++
+	if resp == nil {
+		s.errors.Add(1)
+		e.errors++
+		return
+	}
+	if resp.StatusCode >= 400 {
+		e.errors++
+	}
+	took := time.Since(r.started)
+	e.latency += took
+	if e.digest != nil {
+		e.digest.add(float64(took))
+	}
+	class := fmt.Sprintf("%dxx", resp.StatusCode/100)
+	if s.responses == nil {
+		s.responses = make(map[string]int64)
+	}
+	s.responses[class]++
+	e.responses[class]++
+	// A switched protocol's body is the connection itself.
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body = &countingBody{resp.Body, &s.received, &e.received}
+	}
+}
+
+// retry records that a call to operation is about to be retried.
+func (s *clientStats) retry(operation string) {
+	s.retries.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoint(operation).retries++
+}
+
+// countingBody adds the bytes read through it to a client's total and to
+// an operation's.
+type countingBody struct {
+	io.ReadCloser
+	total, endpoint *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.total.Add(int64(n))
+	b.endpoint.Add(int64(n))
+	return n, err
+}
+
+func (e *endpointStats) snapshot(operation string) EndpointStats {
+	stats := EndpointStats{
+		Requests:      e.requests,
+		Errors:        e.errors,
+		Responses:     make(map[string]int64, len(e.responses)),
+		Retries:       e.retries,
+		BytesSent:     e.sent.Load(),
+		BytesReceived: e.received.Load(),
+		Latency:       e.latency,
+		Captured:      capturedLatencies[operation],
+	}
+	for class, count := range e.responses {
+		stats.Responses[class] = count
+	}
+	if e.requests > 0 {
+		stats.ErrorRate = float64(e.errors) / float64(e.requests)
+	}
+	if e.digest != nil {
+		stats.P50 = time.Duration(e.digest.quantile(0.50))
+		stats.P95 = time.Duration(e.digest.quantile(0.95))
+		stats.P99 = time.Duration(e.digest.quantile(0.99))
+	}
+	return stats
+}
+
+// Stats returns a snapshot of the client's counters: requests sent,
+// transport failures, requests in flight, responses by status class,
+// retries, body bytes sent and received, and schema mismatches seen while
+// decoding, plus the same by operation with their latencies.
+func (c *ExampleapiClient) Stats() ClientStats {
+	stats := ClientStats{
+		Requests:      c.stats.requests.Load(),
+		RequestErrors: c.stats.errors.Load(),
+		InFlight:      c.stats.inFlight.Load(),
+		Responses:     make(map[string]int64),
+		Retries:       c.stats.retries.Load(),
+		BytesSent:     c.stats.sent.Load(),
+		BytesReceived: c.stats.received.Load(),
+	}
+	c.stats.mu.Lock()
+	for class, count := range c.stats.responses {
+		stats.Responses[class] = count
+	}
+	if len(c.stats.endpoints) > 0 {
+		stats.Endpoints = make(map[string]EndpointStats, len(c.stats.endpoints))
+		for operation, endpoint := range c.stats.endpoints {
+			stats.Endpoints[operation] = endpoint.snapshot(operation)
+		}
+	}
+	c.stats.mu.Unlock()
+	for _, mismatch := range c.SchemaMismatches() {
+		stats.SchemaMismatches += mismatch.Count
+	}
+	return stats
+}
+
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes the client's Stats under name in expvar, for
+// environments that scrape /debug/vars; Metrics serves them to Prometheus.
+// Each client needs its own name; publishing a name twice returns an error instead of
+// panicking like expvar.Publish.
+func (c *ExampleapiClient) PublishExpvar(name string) error {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} { return c.Stats() }))
+	return nil
+}
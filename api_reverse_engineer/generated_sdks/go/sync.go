@@ -0,0 +1,308 @@
+package example_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSyncOverlap is how far before its checkpoint a sync reads again,
+// catching changes that became visible after later ones, such as writes
+// whose transactions committed out of timestamp order.
+const DefaultSyncOverlap = time.Minute
+
+// syncSpec is what an incremental sync of a list endpoint needs beyond
+// paging through it.
+type syncSpec struct {
+	list        exportSpec
+	cursorField string // the item timestamp the checkpoint follows
+	idField     string
+	// sinceParam filters the list to items changed since a time. Without
+	// one, every run reads all pages and drops the unchanged items itself.
+	sinceParam string
+	sinceUnix  bool // sinceParam takes Unix seconds rather than a timestamp
+}
+
+// syncSpecs are the captured list endpoints whose items carry a change or
+// creation timestamp.
+var syncSpecs = map[string]syncSpec{
+	"GET /v1/users": {
+		list:        exportSpecs["GET /v1/users"],
+		cursorField: "created_at",
+		idField:     "id",
+	},
+}
+
+// SyncRecord is an item that is new or changed since the last run.
+type SyncRecord struct {
+	ID        string
+	ChangedAt time.Time // the item's timestamp the sync follows
+	Raw       json.RawMessage
+}
+
+// Decode unmarshals the item into v.
+func (r SyncRecord) Decode(v interface{}) error {
+	return json.Unmarshal(r.Raw, v)
+}
+
+// key identifies the record's version for deduplication: an item changing
+// again is a new record.
+func (r SyncRecord) key() string {
+	id := r.ID
+	if id == "" {
+		sum := sha256.Sum256(r.Raw)
+		id = hex.EncodeToString(sum[:16])
+	}
+	return id + "@" + r.ChangedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// SyncHandler handles one record. An error stops the run before the
+// checkpoint advances, so records from the failed run that were not
+// handled are delivered again by the next one.
+type SyncHandler func(ctx context.Context, record SyncRecord) error
+
+// Sync pulls the items of a list endpoint that changed since its
+// checkpoint, turning the API into an incremental data source:
+//
+//	users := client.SyncUsers("warehouse").
+//		WithCheckpoints(FileCheckpointStore{Dir: "state"})
+//	n, err := users.Run(ctx, func(ctx context.Context, r SyncRecord) error {
+//		return upsert(ctx, r.ID, r.Raw)
+//	})
+//
+// Records are delivered at least once. Each run reads again from Overlap
+// before the checkpoint and drops the records earlier runs delivered.
+type Sync struct {
+	// Overlap overrides DefaultSyncOverlap.
+	Overlap time.Duration
+	// PageSize is the number of items to request per page; zero means the
+	// captured page size.
+	PageSize int
+	// RequestOptions apply to every page request.
+	RequestOptions []RequestOption
+
+	client *ExampleapiClient
+	spec   syncSpec
+	name   string
+	store  CheckpointStore
+
+	running    sync.Mutex // held for a whole run
+	mu         sync.Mutex
+	loaded     bool
+	checkpoint *Checkpoint
+}
+
+func (c *ExampleapiClient) newSync(name string, spec syncSpec) *Sync {
+	return &Sync{client: c, spec: spec, name: name}
+}
+
+// SyncUsers creates a sync of the items of GET /v1/users, following their
+// created_at timestamps. name identifies its checkpoint.
+func (c *ExampleapiClient) SyncUsers(name string) *Sync {
+	return c.newSync(name, syncSpecs["GET /v1/users"])
+}
+
+// WithCheckpoints saves the sync's checkpoint to store after every run and
+// resumes from it.
+func (s *Sync) WithCheckpoints(store CheckpointStore) *Sync {
+	s.store = store
+	return s
+}
+
+// From starts the sync at since when there is no saved checkpoint, rather
+// than with every item.
+func (s *Sync) From(since time.Time) *Sync {
+	s.checkpoint = &Checkpoint{Name: s.name, Cursor: since.UTC().Format(time.RFC3339Nano)}
+	return s
+}
+
+// Checkpoint returns the sync's current position: the latest timestamp
+// delivered, and the records delivered within Overlap of it.
+func (s *Sync) Checkpoint() Checkpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint == nil {
+		return Checkpoint{Name: s.name}
+	}
+	checkpoint := *s.checkpoint
+	checkpoint.Seen = append([]string(nil), s.checkpoint.Seen...)
+	return checkpoint
+}
+
+// Run delivers the records changed since the checkpoint to fn, in the
+// order the API lists them, then advances and saves the checkpoint. It
+// returns the number of records delivered. Items whose timestamp cannot be
+// read are delivered by the first run only.
+func (s *Sync) Run(ctx context.Context, fn SyncHandler) (int, error) {
+	s.running.Lock()
+	defer s.running.Unlock()
+	if err := s.load(ctx); err != nil {
+		return 0, err
+	}
+	var since time.Time
+	if s.checkpoint.Cursor != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339Nano, s.checkpoint.Cursor); err != nil {
+			return 0, fmt.Errorf("sync %s: checkpoint cursor: %w", s.name, err)
+		}
+	}
+	overlap := s.Overlap
+	if overlap <= 0 {
+		overlap = DefaultSyncOverlap
+	}
+	from := since.Add(-overlap)
+
+	layouts := s.client.timeLayouts
+	if layouts == nil {
+		layouts = TimeLayouts
+	}
+	params := url.Values{}
+	switch {
+	case s.spec.sinceParam == "" || since.IsZero():
+	case s.spec.sinceUnix:
+		params.Set(s.spec.sinceParam, strconv.FormatInt(from.Unix(), 10))
+	default:
+		params.Set(s.spec.sinceParam, strings.Trim(string(layouts.Format(from)), `"`))
+	}
+	seen := make(map[string]bool, len(s.checkpoint.Seen))
+	for _, key := range s.checkpoint.Seen {
+		seen[key] = true
+	}
+
+	latest, delivered := since, 0
+	var keys []string
+	err := s.client.listPages(ctx, s.spec.list, s.PageSize, params, s.RequestOptions, func(items []map[string]interface{}) (bool, error) {
+		for _, item := range items {
+			changed, err := syncTime(layouts, item[s.spec.cursorField])
+			if !since.IsZero() && (err != nil || changed.Before(from)) {
+				continue
+			}
+			raw, err := json.Marshal(item)
+			if err != nil {
+				return false, err
+			}
+			record := SyncRecord{ID: syncID(item[s.spec.idField]), ChangedAt: changed, Raw: raw}
+			key := record.key()
+			if !seen[key] {
+				if err := s.handle(ctx, fn, record); err != nil {
+					return false, err
+				}
+				seen[key] = true
+				keys = append(keys, key)
+				delivered++
+			}
+			if changed.After(latest) {
+				latest = changed
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		// Keep the cursor, but remember what was delivered so the next run
+		// does not repeat it.
+		s.remember(since, overlap, keys)
+		if saveErr := s.save(ctx); saveErr != nil {
+			return delivered, fmt.Errorf("sync %s: %w (saving checkpoint: %v)", s.name, err, saveErr)
+		}
+		return delivered, fmt.Errorf("sync %s: %w", s.name, err)
+	}
+	s.remember(latest, overlap, keys)
+	return delivered, s.save(ctx)
+}
+
+func (s *Sync) handle(ctx context.Context, fn SyncHandler, record SyncRecord) (err error) {
+	defer recoverPanic("sync "+s.name, 1, &err)
+	if err := fn(ctx, record); err != nil {
+		return fmt.Errorf("record %s: %w", record.key(), err)
+	}
+	return nil
+}
+
+// remember moves the checkpoint to latest and adds keys to the delivered
+// records, forgetting those the next run's overlap no longer reaches.
+func (s *Sync) remember(latest time.Time, overlap time.Duration, keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !latest.IsZero() {
+		s.checkpoint.Cursor = latest.UTC().Format(time.RFC3339Nano)
+	}
+	horizon := latest.Add(-overlap)
+	var kept []string
+	for _, key := range append(s.checkpoint.Seen, keys...) {
+		at, err := time.Parse(time.RFC3339Nano, key[strings.LastIndex(key, "@")+1:])
+		if err == nil && !at.Before(horizon) {
+			kept = append(kept, key)
+		}
+	}
+	s.checkpoint.Seen = kept
+}
+
+// load restores the saved checkpoint on the first run.
+func (s *Sync) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.store != nil {
+		saved, err := s.store.Load(ctx, s.name)
+		if err != nil {
+			return fmt.Errorf("sync %s: %w", s.name, err)
+		}
+		if saved != nil {
+			s.checkpoint = saved
+		}
+	}
+	if s.checkpoint == nil {
+		s.checkpoint = &Checkpoint{Name: s.name}
+	}
+	s.loaded = true
+	return nil
+}
+
+func (s *Sync) save(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	checkpoint := s.Checkpoint()
+	if err := s.store.Save(ctx, &checkpoint); err != nil {
+		return fmt.Errorf("sync %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// syncTime reads an item's timestamp, a string in one of the accepted
+// layouts or a number of Unix seconds or, if too large for those,
+// milliseconds.
+func syncTime(layouts *TimeLayoutRegistry, v interface{}) (time.Time, error) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		if f >= 1e11 {
+			return time.UnixMilli(int64(f)).UTC(), nil
+		}
+		return time.Unix(0, int64(f*1e9)).UTC(), nil
+	}
+	stamp, _ := json.Marshal(v)
+	return layouts.Parse(stamp)
+}
+
+// syncID renders an item's id field, a string or a number.
+func syncID(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
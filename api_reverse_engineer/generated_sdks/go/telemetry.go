@@ -0,0 +1,287 @@
+package example_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MismatchKind classifies a difference between a response and the schema
+// the SDK was generated from.
+type MismatchKind string
+
+const (
+	// UnknownField is a response field the SDK has no field for.
+	UnknownField MismatchKind = "unknown_field"
+	// MissingField is an SDK field absent from the response.
+	MissingField MismatchKind = "missing_field"
+	// TypeFallback is a value of an unexpected type; the field is left at
+	// its zero value instead of failing the call.
+	TypeFallback MismatchKind = "type_fallback"
+)
+
+// SchemaMismatch is one occurrence of a response deviating from the
+// generated types.
+type SchemaMismatch struct {
+	Operation string // "GET /v1/users/{id}"
+	Kind      MismatchKind
+	Field     string // path such as "users[].email"
+	Expected  string // Go type, empty for UnknownField
+	Observed  string // JSON type, empty for MissingField
+}
+
+// SchemaMismatchCount is a SchemaMismatch with the number of times it was
+// seen.
+type SchemaMismatchCount struct {
+	SchemaMismatch
+	Count int64
+}
+
+// WithSchemaMismatchHandler calls fn for every schema mismatch found while
+// decoding responses, e.g. to log or alert on schema drift.
+func WithSchemaMismatchHandler(fn func(SchemaMismatch)) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.schema.handler = fn
+	}
+}
+
+// SchemaMismatches returns how often each mismatch was seen since the
+// client was created, most frequent first. The totals quantify how stale
+// the reverse-engineered schema is getting.
+func (c *ExampleapiClient) SchemaMismatches() []SchemaMismatchCount {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	counts := make([]SchemaMismatchCount, 0, len(c.schema.counts))
+	for mismatch, count := range c.schema.counts {
+		counts = append(counts, SchemaMismatchCount{mismatch, count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		a, b := counts[i].SchemaMismatch, counts[j].SchemaMismatch
+		return a.Operation+a.Field+string(a.Kind) < b.Operation+b.Field+string(b.Kind)
+	})
+	return counts
+}
+
+type schemaTelemetry struct {
+	mu      sync.Mutex
+	counts  map[SchemaMismatch]int64
+	handler func(SchemaMismatch)
+}
+
+func (t *schemaTelemetry) record(m SchemaMismatch) {
+	t.mu.Lock()
+	if t.counts == nil {
+		t.counts = make(map[SchemaMismatch]int64)
+	}
+	t.counts[m]++
+	handler := t.handler
+	t.mu.Unlock()
+	if handler != nil {
+		handler(m)
+	}
+}
+
+// decode unmarshals a response body into v, recording every field that
+// does not match the generated type. Type mismatches fall back to zero
+// values rather than failing the call, as do timestamps no layout accepts.
+// Operations whose responses are not JSON use their media type's
+// serializer, without the field checks; JSON ones use the client's Codec
+// and its DecodeMode. An empty body, as a 204 No Content has, leaves v
+// unchanged. Enveloped responses decode their payload.
+func (c *ExampleapiClient) decode(operation string, body []byte, v interface{}) (err error) {
+	defer recoverPanic(operation, 1, &err)
+	body = envelopePayload(operation, body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	serializer, err := c.responseSerializer(operation)
+	if err != nil {
+		return err
+	}
+	method, endpoint, _ := strings.Cut(operation, " ")
+	pprof.Do(context.Background(), profileLabels(method, endpoint), func(context.Context) {
+		if serializer != nil {
+			err = serializer.Unmarshal(body, v)
+		} else {
+			err = c.jsonCodec().Unmarshal(body, v)
+		}
+		var typeErr *json.UnmarshalTypeError
+		if err != nil && !errors.As(err, &typeErr) {
+			return
+		}
+		err = nil
+		var raw interface{}
+		if serializer == nil && json.Unmarshal(body, &raw) == nil {
+			c.schema.compare(operation, "", raw, reflect.TypeOf(v).Elem())
+		}
+		c.applyTimeLayouts(operation, v)
+		if serializer == nil {
+			err = c.checkUnknownFields(operation, body, v)
+		}
+	})
+	return err
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// schemaValue is implemented by value types with custom decoding, such as
+// Decimal, that accept only some JSON values.
+type schemaValue interface {
+	acceptsJSON(raw interface{}) bool
+}
+
+func (t *schemaTelemetry) compare(operation, path string, raw interface{}, typ reflect.Type) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if raw == nil || typ.Kind() == reflect.Interface {
+		return
+	}
+	mismatch := func(kind MismatchKind, field, expected, observed string) {
+		t.record(SchemaMismatch{Operation: operation, Kind: kind, Field: field, Expected: expected, Observed: observed})
+	}
+	observed := jsonType(raw)
+	label := path
+	if label == "" {
+		label = "(root)"
+	}
+	if reflect.PtrTo(typ).Implements(unmarshalerType) {
+		if value, ok := reflect.Zero(typ).Interface().(schemaValue); ok && !value.acceptsJSON(raw) {
+			mismatch(TypeFallback, label, typ.String(), observed)
+		}
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			mismatch(TypeFallback, label, "object", observed)
+			return
+		}
+		known := make(map[string]bool, len(object))
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+			key, found := lookupKey(object, name)
+			if !found {
+				mismatch(MissingField, joinPath(path, name), field.Type.String(), "")
+				continue
+			}
+			known[key] = true
+			t.compare(operation, joinPath(path, name), object[key], field.Type)
+		}
+		for key, value := range object {
+			if !known[key] {
+				mismatch(UnknownField, joinPath(path, key), "", jsonType(value))
+			}
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			mismatch(TypeFallback, label, "object", observed)
+			return
+		}
+		for key, value := range object {
+			t.compare(operation, joinPath(path, key), value, typ.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]interface{})
+		if !ok {
+			if typ.Elem().Kind() == reflect.Uint8 && observed == "string" {
+				return // []byte is encoded as base64
+			}
+			mismatch(TypeFallback, label, typ.String(), observed)
+			return
+		}
+		for _, item := range items {
+			t.compare(operation, path+"[]", item, typ.Elem())
+		}
+	case reflect.String:
+		if observed != "string" {
+			mismatch(TypeFallback, label, "string", observed)
+		}
+	case reflect.Bool:
+		if observed != "boolean" {
+			mismatch(TypeFallback, label, "bool", observed)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := raw.(float64); !ok || n != math.Trunc(n) {
+			mismatch(TypeFallback, label, typ.String(), observed)
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := raw.(float64); !ok {
+			mismatch(TypeFallback, label, typ.String(), observed)
+		}
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// lookupKey finds name in object, falling back to the case-insensitive
+// match encoding/json also accepts.
+func lookupKey[V any](object map[string]V, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonType(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "unknown"
+}
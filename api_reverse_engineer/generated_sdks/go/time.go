@@ -0,0 +1,368 @@
+package example_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Special layouts for timestamps encoded as Unix epoch numbers (or numeric
+// strings).
+const (
+	LayoutUnix      = "unix"
+	LayoutUnixMilli = "unixmilli"
+)
+
+// observedTimeLayouts are the non-ISO layouts seen in the captured traffic.
+var observedTimeLayouts = []string{}
+
+// TimeLayoutRegistry lists the layouts accepted when decoding Time fields,
+// tried in order, and the layout used when encoding them. It is safe for
+// concurrent use.
+type TimeLayoutRegistry struct {
+	mu       sync.RWMutex
+	accepted []string
+	emit     string
+}
+
+// NewTimeLayoutRegistry creates a registry emitting emit and accepting
+// accepted (plus emit itself).
+func NewTimeLayoutRegistry(emit string, accepted ...string) *TimeLayoutRegistry {
+	r := &TimeLayoutRegistry{emit: emit}
+	r.Accept(emit)
+	r.Accept(accepted...)
+	return r
+}
+
+// TimeLayouts is the package-level registry used by every Time field unless
+// a client overrides it with WithTimeLayouts. It accepts RFC 3339, common
+// SQL-style layouts, Unix seconds and milliseconds, and the layouts observed
+// in the captured traffic, and emits RFC 3339.
+var TimeLayouts = NewTimeLayoutRegistry(time.RFC3339Nano, append([]string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	LayoutUnix,
+	LayoutUnixMilli,
+}, observedTimeLayouts...)...)
+
+// Accept adds layouts to the end of the accepted list.
+func (r *TimeLayoutRegistry) Accept(layouts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, layout := range layouts {
+		if !containsString(r.accepted, layout) {
+			r.accepted = append(r.accepted, layout)
+		}
+	}
+}
+
+// SetEmit sets the layout used when encoding, and accepts it.
+func (r *TimeLayoutRegistry) SetEmit(layout string) {
+	r.Accept(layout)
+	r.mu.Lock()
+	r.emit = layout
+	r.mu.Unlock()
+}
+
+// Parse decodes a JSON string or number with the first accepted layout
+// that matches.
+func (r *TimeLayoutRegistry) Parse(data []byte) (time.Time, error) {
+	r.mu.RLock()
+	accepted := r.accepted
+	r.mu.RUnlock()
+
+	text := string(bytes.Trim(data, `"`))
+	quoted := len(data) > 0 && data[0] == '"'
+	for _, layout := range accepted {
+		switch layout {
+		case LayoutUnix, LayoutUnixMilli:
+			n, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				continue
+			}
+			// With both accepted, values too large to be seconds (beyond the
+			// year 5138) are milliseconds.
+			if layout == LayoutUnixMilli || n >= 1e11 && containsString(accepted, LayoutUnixMilli) {
+				return time.UnixMilli(int64(n)).UTC(), nil
+			}
+			return time.Unix(0, int64(n*1e9)).UTC(), nil
+		default:
+			if !quoted {
+				continue
+			}
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("time %s matches none of the accepted layouts", data)
+}
+
+// Format encodes t as JSON with the emit layout.
+func (r *TimeLayoutRegistry) Format(t time.Time) []byte {
+	r.mu.RLock()
+	layout := r.emit
+	r.mu.RUnlock()
+	switch layout {
+	case LayoutUnix:
+		return strconv.AppendInt(nil, t.Unix(), 10)
+	case LayoutUnixMilli:
+		return strconv.AppendInt(nil, t.UnixMilli(), 10)
+	}
+	return strconv.AppendQuote(nil, t.Format(layout))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Time is a timestamp field decoded and encoded through a
+// TimeLayoutRegistry. A value that matches no accepted layout decodes to the
+// zero time without failing, keeping the raw text in Raw; clients record it
+// as a TypeFallback schema mismatch. JSON null decodes to the zero time, and
+// the zero time encodes as null.
+type Time struct {
+	time.Time
+
+	raw      string
+	registry *TimeLayoutRegistry
+}
+
+// NewTime wraps t.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// Raw returns the JSON text the value was decoded from.
+func (t Time) Raw() string {
+	return t.raw
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+	t.raw = string(data)
+	parsed, err := t.layouts().Parse(data)
+	if err != nil {
+		t.Time = time.Time{}
+		return nil
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. A zero value that was decoded
+// from text no layout matched encodes as that text again.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.Time.IsZero() {
+		if t.raw != "" {
+			return []byte(t.raw), nil
+		}
+		return []byte("null"), nil
+	}
+	return t.layouts().Format(t.Time), nil
+}
+
+func (t Time) layouts() *TimeLayoutRegistry {
+	if t.registry != nil {
+		return t.registry
+	}
+	return TimeLayouts
+}
+
+// valid reports whether the value decoded, or was never decoded.
+func (t Time) valid() bool {
+	return t.raw == "" || !t.Time.IsZero()
+}
+
+// epoch reports whether the value was decoded as a UnixTime or
+// UnixMilliTime, which ignore the client's layouts.
+func (t Time) epoch() bool {
+	return t.registry == unixSeconds || t.registry == unixMillis
+}
+
+// unixSeconds and unixMillis decode and encode UnixTime and UnixMilliTime
+// values.
+var (
+	unixSeconds = NewTimeLayoutRegistry(LayoutUnix, LayoutUnixMilli)
+	unixMillis  = NewTimeLayoutRegistry(LayoutUnixMilli)
+)
+
+// UnixTime is a timestamp field the API sends as Unix seconds. It decodes
+// like Time, taking values too large to be seconds as milliseconds, and
+// encodes as seconds whatever the emit layout.
+type UnixTime struct {
+	Time
+}
+
+// NewUnixTime wraps t.
+func NewUnixTime(t time.Time) UnixTime {
+	return UnixTime{Time: NewTime(t)}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	t.registry = unixSeconds
+	return t.Time.UnmarshalJSON(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	t.registry = unixSeconds
+	return t.Time.MarshalJSON()
+}
+
+// UnixMilliTime is a timestamp field the API sends as Unix milliseconds.
+type UnixMilliTime struct {
+	Time
+}
+
+// NewUnixMilliTime wraps t.
+func NewUnixMilliTime(t time.Time) UnixMilliTime {
+	return UnixMilliTime{Time: NewTime(t)}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *UnixMilliTime) UnmarshalJSON(data []byte) error {
+	t.registry = unixMillis
+	return t.Time.UnmarshalJSON(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t UnixMilliTime) MarshalJSON() ([]byte, error) {
+	t.registry = unixMillis
+	return t.Time.MarshalJSON()
+}
+
+// WithTimeLayouts makes the client decode and encode Time fields with
+// layouts instead of the package-level TimeLayouts, for APIs whose
+// endpoints disagree with other APIs in the same program.
+func WithTimeLayouts(layouts *TimeLayoutRegistry) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.timeLayouts = layouts
+	}
+}
+
+var timeType = reflect.TypeOf(Time{})
+
+// walkTimes calls fn for every addressable Time reachable from v, with its
+// field path; the Time of a UnixTime or UnixMilliTime has the path of the
+// field holding it.
+func walkTimes(path string, v reflect.Value, fn func(string, *Time)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkTimes(path, v.Elem(), fn)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if v.CanAddr() {
+				fn(path, v.Addr().Interface().(*Time))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Anonymous && field.Type == timeType {
+				walkTimes(path, v.Field(i), fn)
+			} else if name := jsonName(field); name != "" {
+				walkTimes(joinPath(path, name), v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTimes(path+"[]", v.Index(i), fn)
+		}
+	}
+}
+
+// applyTimeLayouts re-parses decoded Time values with the client's
+// registry and records values no layout matched.
+func (c *ExampleapiClient) applyTimeLayouts(operation string, v interface{}) {
+	walkTimes("", reflect.ValueOf(v), func(path string, t *Time) {
+		if c.timeLayouts != nil && t.raw != "" && !t.epoch() {
+			t.registry = c.timeLayouts
+			t.Time, _ = c.timeLayouts.Parse([]byte(t.raw))
+		}
+		if !t.valid() {
+			var raw interface{}
+			json.Unmarshal([]byte(t.raw), &raw)
+			c.schema.record(SchemaMismatch{Operation: operation, Kind: TypeFallback, Field: path, Expected: "Time", Observed: jsonType(raw)})
+		}
+	})
+}
+
+// withTimeLayouts returns the request body v to encode: v itself, or with
+// the client's own registry a copy of v whose Time values encode with it.
+// The caller's value is left as it is, as other goroutines may be reading
+// it or encoding it with another client.
+func (c *ExampleapiClient) withTimeLayouts(v interface{}) interface{} {
+	if c.timeLayouts == nil {
+		return v
+	}
+	copied := copyTimes(reflect.ValueOf(v))
+	walkTimes("", copied, func(_ string, t *Time) {
+		t.registry = c.timeLayouts
+	})
+	return copied.Interface()
+}
+
+// copyTimes copies v as deep as walkTimes reaches, so that the Time values
+// it finds are the copy's own; everything else is shared with v.
+func copyTimes(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(copyTimes(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(copyTimes(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(copyTimes(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyTimes(v.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyTimes(v.Index(i)))
+		}
+		return copied
+	}
+	return v
+}
@@ -0,0 +1,44 @@
+package example_api
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+type timedRequest struct {
+	At    Time   `json:"at"`
+	Times []Time `json:"times"`
+}
+
+// TestWithTimeLayouts encodes one request with clients using different
+// layouts at once; run it with -race.
+func TestWithTimeLayouts(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	request := &timedRequest{At: Time{Time: at}, Times: []Time{{Time: at}}}
+	layouts := map[string]string{
+		`{"at":"2024-01-15","times":["2024-01-15"]}`:                   "2006-01-02",
+		`{"at":"2024-01-15 10:30:00","times":["2024-01-15 10:30:00"]}`: "2006-01-02 15:04:05",
+	}
+
+	var wg sync.WaitGroup
+	for want, layout := range layouts {
+		wg.Add(1)
+		go func(want, layout string) {
+			defer wg.Done()
+			c := NewExampleapiClient("", WithTimeLayouts(NewTimeLayoutRegistry(layout)))
+			got, err := json.Marshal(c.withTimeLayouts(request))
+			if err != nil {
+				t.Error(err)
+			} else if string(got) != want {
+				t.Errorf("layout %q encoded %s, want %s", layout, got, want)
+			}
+		}(want, layout)
+	}
+	wg.Wait()
+
+	if request.At.registry != nil || request.Times[0].registry != nil {
+		t.Error("withTimeLayouts changed the caller's request")
+	}
+}
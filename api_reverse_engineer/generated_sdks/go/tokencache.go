@@ -0,0 +1,262 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TokenRefresher obtains a fresh token, e.g. from an OAuth token endpoint,
+// with its expiry; a zero expiry keeps the token until the API rejects it.
+type TokenRefresher func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// SharedTokenCache holds one token per credential key for every client
+// using it. Concurrent refreshes of a key collapse into one, so an
+// application building many short-lived clients refreshes once rather than
+// once per client; with a Store, processes share tokens too. It is safe for
+// concurrent use.
+type SharedTokenCache struct {
+	// Store, if set, shares tokens with other processes.
+	Store TokenStore
+	// Leeway refreshes tokens this long before they expire; zero means 30
+	// seconds.
+	Leeway time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*sharedToken
+}
+
+// DefaultTokenCache is the process-wide cache WithSharedToken uses when
+// given a nil cache.
+var DefaultTokenCache = &SharedTokenCache{}
+
+type sharedToken struct {
+	mu      sync.Mutex // held while refreshing
+	refresh TokenRefresher
+	token   string
+	expiry  time.Time
+}
+
+// TokenStore shares tokens between processes. Lock serializes refreshes of
+// a key across processes.
+type TokenStore interface {
+	Load(key string) (token string, expiry time.Time, err error)
+	Save(key, token string, expiry time.Time) error
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// WithSharedToken sends the token cache holds for key as the bearer token,
+// refreshing it with refresh when it is missing, about to expire, or
+// rejected with a 401. Clients passing the same cache and key share one
+// token; a nil cache means DefaultTokenCache.
+func WithSharedToken(cache *SharedTokenCache, key string, refresh TokenRefresher) ClientOption {
+	if cache == nil {
+		cache = DefaultTokenCache
+	}
+	cache.register(key, refresh)
+	credential := &secretCredential{secrets: cache, name: key, header: "Authorization", prefix: "Bearer "}
+	return func(c *ExampleapiClient) {
+		c.credentials = append(c.credentials, credential)
+	}
+}
+
+// register records the refresher for key; the first one registered wins.
+func (c *SharedTokenCache) register(key string, refresh TokenRefresher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*sharedToken)
+	}
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = &sharedToken{refresh: refresh}
+	}
+}
+
+func (c *SharedTokenCache) entry(key string) (*sharedToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("shared token %q: no refresher registered", key)
+	}
+	return entry, nil
+}
+
+// valid reports whether a token is usable for at least the leeway.
+func (c *SharedTokenCache) valid(token string, expiry time.Time) bool {
+	leeway := c.Leeway
+	if leeway == 0 {
+		leeway = 30 * time.Second
+	}
+	return token != "" && (expiry.IsZero() || time.Until(expiry) > leeway)
+}
+
+// Secret returns the token for key, refreshing it if needed.
+func (c *SharedTokenCache) Secret(ctx context.Context, key string) (string, error) {
+	entry, err := c.entry(key)
+	if err != nil {
+		return "", err
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if c.valid(entry.token, entry.expiry) {
+		return entry.token, nil
+	}
+	if c.Store == nil {
+		return c.refresh(ctx, key, entry)
+	}
+
+	// Another process may have refreshed already; check before and after
+	// taking the cross-process lock.
+	if token, expiry, err := c.Store.Load(key); err == nil && c.valid(token, expiry) {
+		entry.token, entry.expiry = token, expiry
+		return token, nil
+	}
+	unlock, err := c.Store.Lock(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("shared token %q: %w", key, err)
+	}
+	defer unlock()
+	if token, expiry, err := c.Store.Load(key); err == nil && c.valid(token, expiry) {
+		entry.token, entry.expiry = token, expiry
+		return token, nil
+	}
+	token, err := c.refresh(ctx, key, entry)
+	if err != nil {
+		return "", err
+	}
+	if err := c.Store.Save(key, entry.token, entry.expiry); err != nil {
+		return "", fmt.Errorf("shared token %q: %w", key, err)
+	}
+	return token, nil
+}
+
+func (c *SharedTokenCache) refresh(ctx context.Context, key string, entry *sharedToken) (string, error) {
+	token, expiry, err := entry.refresh(ctx)
+	if err != nil {
+		return "", fmt.Errorf("shared token %q: refresh: %w", key, err)
+	}
+	entry.token, entry.expiry = token, expiry
+	return token, nil
+}
+
+// expire drops the token for key if it is still the rejected one, so the
+// 401s of calls that raced a refresh do not discard the new token.
+func (c *SharedTokenCache) expire(key, rejected string) {
+	entry, err := c.entry(key)
+	if err != nil {
+		return
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.token == rejected {
+		entry.token, entry.expiry = "", time.Time{}
+	}
+	if c.Store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	unlock, err := c.Store.Lock(ctx, key)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	if token, _, err := c.Store.Load(key); err == nil && token == rejected {
+		c.Store.Save(key, "", time.Time{})
+	}
+}
+
+// FileTokenStore is a TokenStore keeping each key's token in Dir as
+// <key>.json, readable only by the current user, and locking with
+// <key>.lock files. Locks older than StaleLock (default 30 seconds) are
+// assumed abandoned by a crashed process.
+type FileTokenStore struct {
+	Dir       string
+	StaleLock time.Duration
+}
+
+type storedToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// Load implements TokenStore; a missing file is an empty token.
+func (f FileTokenStore) Load(key string) (string, time.Time, error) {
+	data, err := os.ReadFile(f.path(key, ".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var stored storedToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return "", time.Time{}, err
+	}
+	return stored.Token, stored.Expiry, nil
+}
+
+// Save implements TokenStore, replacing the file atomically.
+func (f FileTokenStore) Save(key, token string, expiry time.Time) error {
+	data, err := json.Marshal(storedToken{Token: token, Expiry: expiry})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.Dir, filepath.Base(key)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(key, ".json"))
+}
+
+// Lock implements TokenStore, polling until the lock file can be created.
+func (f FileTokenStore) Lock(ctx context.Context, key string) (func(), error) {
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	stale := f.StaleLock
+	if stale == 0 {
+		stale = 30 * time.Second
+	}
+	path := f.path(key, ".lock")
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (f FileTokenStore) path(key, ext string) string {
+	return filepath.Join(f.Dir, filepath.Base(key)+ext)
+}
@@ -0,0 +1,123 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/example/example_api/agenttool"
+)
+
+// Tools returns an agenttool.Tool for each of the client's operations, so
+// agents can discover and call them with JSON arguments. A tool's input is
+// an object of the operation's path and query parameters, plus "body" for
+// its request body; its output is the decoded response.
+func (c *ExampleapiClient) Tools() []agenttool.Tool {
+	return []agenttool.Tool{
+		agenttool.Func{
+			ToolName:        "list_posts",
+			ToolDescription: "List posts (GET /v1/posts).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{},"additionalProperties":false}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct{}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.ListPosts(ctx))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "create_post",
+			ToolDescription: "Create post (POST /v1/posts).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"body":{"type":"object","required":["title","content","tags","status"],"properties":{"title":{"type":"string"},"content":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}},"status":{"type":"string","enum":["draft","published"]}}}},"additionalProperties":false,"required":["body"]}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Body *CreatePostRequest `json:"body"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.CreatePost(ctx, in.Body))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "list_users",
+			ToolDescription: "List users (GET /v1/users).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"page":{"type":"integer"},"limit":{"type":"integer"}},"additionalProperties":false}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Page  int `json:"page"`
+					Limit int `json:"limit"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.ListUsers(ctx, &ListUsersParams{Page: in.Page, Limit: in.Limit}))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "create_user",
+			ToolDescription: "Create user (POST /v1/users).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"body":{"type":"object","required":["name","email","password","profile"],"properties":{"name":{"type":"string"},"email":{"type":"string","format":"email"},"password":{"type":"string"},"profile":{"type":"object","required":["bio","location"],"properties":{"bio":{"type":"string"},"location":{"type":"string"}}}}}},"additionalProperties":false,"required":["body"]}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Body *CreateUserRequest `json:"body"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.CreateUser(ctx, in.Body))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "get_user",
+			ToolDescription: "Get user (GET /v1/users/{id}).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"id":{"type":"integer"}},"additionalProperties":false,"required":["id"]}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Id int64 `json:"id"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.GetUser(ctx, in.Id))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "update_user",
+			ToolDescription: "Update user (PUT /v1/users/{id}).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"id":{"type":"integer"},"body":{"type":"object","properties":{"name":{"type":"string"},"email":{"type":"string","format":"email"},"is_active":{"type":"boolean"}}}},"additionalProperties":false,"required":["id","body"]}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Id   int64              `json:"id"`
+					Body *UpdateUserRequest `json:"body"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.UpdateUser(ctx, in.Id, in.Body))
+			},
+		},
+		agenttool.Func{
+			ToolName:        "delete_user",
+			ToolDescription: "Delete user (DELETE /v1/users/{id}).",
+			Schema:          json.RawMessage(`{"type":"object","properties":{"id":{"type":"integer"}},"additionalProperties":false,"required":["id"]}`),
+			Call: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+				var in struct {
+					Id int64 `json:"id"`
+				}
+				if err := agenttool.DecodeInput(input, &in); err != nil {
+					return nil, err
+				}
+				return toolOutput(c.DeleteUser(ctx, in.Id))
+			},
+		},
+	}
+}
+
+// toolOutput encodes an operation's return values as a tool's output.
+func toolOutput[T any](value T, err error) (json.RawMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,272 @@
+package example_api
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default per-phase timeouts of the client's transport. There is no overall
+// deadline by default, so long downloads and streams are not cut off once
+// the response has started; set one with WithTotalTimeout.
+const (
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 30 * time.Second
+)
+
+// Default connection pool settings. Go's default of 2 idle connections per
+// host makes concurrent SDK callers re-dial constantly; clients keep more.
+const (
+	DefaultMaxIdleConnsPerHost = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+const keepAliveInterval = 30 * time.Second
+
+// newTransport builds the transport clients are created with: a clone of
+// http.DefaultTransport, keeping its proxy and HTTP/2 settings, with the
+// default per-phase timeouts and pool settings.
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: DefaultDialTimeout, KeepAlive: keepAliveInterval}).DialContext
+	transport.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = DefaultResponseHeaderTimeout
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	return transport
+}
+
+// WithDialTimeout limits how long establishing a TCP connection may take.
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.DialContext = (&net.Dialer{Timeout: d, KeepAlive: keepAliveInterval}).DialContext
+		}
+	}
+}
+
+// WithTLSHandshakeTimeout limits how long the TLS handshake may take.
+func WithTLSHandshakeTimeout(d time.Duration) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.TLSHandshakeTimeout = d
+		}
+	}
+}
+
+// WithResponseHeaderTimeout limits how long to wait for the response headers
+// once the request is written; reading the body is not covered, so slow
+// streaming responses keep flowing.
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.ResponseHeaderTimeout = d
+		}
+	}
+}
+
+// WithTotalTimeout limits each call end to end, including reading the
+// response body. Zero means no limit.
+func WithTotalTimeout(d time.Duration) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.HTTPClient.Timeout = d
+	}
+}
+
+// WithMaxIdleConnsPerHost sets how many idle connections are kept per host
+// for reuse.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.MaxIdleConnsPerHost = n
+			if transport.MaxIdleConns != 0 && transport.MaxIdleConns < n {
+				transport.MaxIdleConns = n
+			}
+		}
+	}
+}
+
+// WithMaxConnsPerHost caps the connections per host, idle or in use; calls
+// beyond it wait for a free connection. Zero means no limit.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.MaxConnsPerHost = n
+		}
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection stays in the pool.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.IdleConnTimeout = d
+		}
+	}
+}
+
+// WithoutKeepAlives uses a new connection for every request, e.g. behind
+// load balancers that pin connections to one backend.
+func WithoutKeepAlives() ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.DisableKeepAlives = true
+		}
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to reach the API, e.g. to
+// trust a private CA or present a client certificate. It applies to the
+// client's *http.Transport, so it has no effect after HTTPClient.Transport
+// is replaced with another RoundTripper.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.TLSClientConfig = config
+		}
+	}
+}
+
+// ErrCertificatePin is returned when the server's certificate chain has no
+// key pinned with WithPinnedCertificates.
+var ErrCertificatePin = errors.New("server certificate does not match a pinned key")
+
+// WithPinnedCertificates trusts the server only if its certificate, or one
+// its certificate chains to, has a public key whose SHA-256 is among pins,
+// in place of checking the chain against the system roots. This reaches
+// APIs with self-signed certificates without turning verification off.
+// Pinning a CA's key requires the server's certificate to be signed by it
+// and valid for the host. Pins are base64, with or
+// without a "sha256/" prefix, as printed by
+//
+//	openssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+//
+// It extends the TLS configuration in place, so use it after WithTLSConfig.
+func WithPinnedCertificates(pins ...string) ClientOption {
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pinned[strings.TrimPrefix(pin, "sha256/")] = true
+	}
+	return func(c *ExampleapiClient) {
+		transport := c.transport()
+		if transport == nil {
+			return
+		}
+		config := &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			config = transport.TLSClientConfig.Clone()
+		}
+		// The chain is checked against the pins alone, below.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyPins(state, pinned)
+		}
+		transport.TLSClientConfig = config
+	}
+}
+
+// verifyPins accepts a connection whose leaf certificate has a pinned key,
+// or whose leaf chains, through the certificates the server sent, to one
+// that has. The handshake only proves the server holds the leaf's key, so
+// a pinned certificate merely sent along with another leaf is not enough.
+func verifyPins(state tls.ConnectionState, pinned map[string]bool) error {
+	certs := state.PeerCertificates
+	if len(certs) == 0 {
+		return ErrCertificatePin
+	}
+	if pinned[spkiPin(certs[0])] {
+		return nil
+	}
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		if pinned[spkiPin(cert)] {
+			roots.AddCert(cert)
+		} else {
+			intermediates.AddCert(cert)
+		}
+	}
+	opts := x509.VerifyOptions{DNSName: state.ServerName, Roots: roots, Intermediates: intermediates}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return fmt.Errorf("%w: %v", ErrCertificatePin, err)
+	}
+	return nil
+}
+
+// spkiPin is the base64 SHA-256 of cert's public key.
+func spkiPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// WithHTTP2 turns HTTP/2 on or off. It is on by default, negotiated with
+// servers that offer it; turning it off helps with servers and proxies that
+// handle HTTP/2 badly, and spreads concurrent calls over several
+// connections instead of multiplexing them on one.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(c *ExampleapiClient) {
+		transport := c.transport()
+		if transport == nil {
+			return
+		}
+		transport.ForceAttemptHTTP2 = enabled
+		if enabled {
+			transport.TLSNextProto = nil
+			return
+		}
+		// A non-nil, empty map keeps the transport from configuring HTTP/2,
+		// and servers must no longer be offered h2 when negotiating.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig != nil {
+			config := transport.TLSClientConfig.Clone()
+			config.NextProtos = nil
+			for _, proto := range transport.TLSClientConfig.NextProtos {
+				if proto != "h2" {
+					config.NextProtos = append(config.NextProtos, proto)
+				}
+			}
+			transport.TLSClientConfig = config
+		}
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL instead of the one
+// named by the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+// Like WithTLSConfig, it needs the client's *http.Transport.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *ExampleapiClient) {
+		if transport := c.transport(); transport != nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+}
+
+// transport returns the *http.Transport the client sends requests through,
+// looking behind a DumpTransport and installing a new one when none is set,
+// so options never mutate http.DefaultTransport. It returns nil for other
+// RoundTrippers.
+func (c *ExampleapiClient) transport() *http.Transport {
+	next := &c.HTTPClient.Transport
+	for {
+		switch t := (*next).(type) {
+		case nil:
+			transport := newTransport()
+			*next = transport
+			return transport
+		case *http.Transport:
+			return t
+		case *DumpTransport:
+			next = &t.Next
+		default:
+			return nil
+		}
+	}
+}
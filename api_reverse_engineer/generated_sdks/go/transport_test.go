@@ -0,0 +1,87 @@
+package example_api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testCertificate issues a certificate for host signed by parent (or
+// self-signed when parent is nil).
+func testCertificate(t *testing.T, host string, ca bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: host},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  ca,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if !ca {
+		template.DNSNames = []string{host}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestVerifyPins(t *testing.T) {
+	ca, caKey := testCertificate(t, "Pinned CA", true, nil, nil)
+	leaf, _ := testCertificate(t, "api.example.com", false, ca, caKey)
+	selfSigned, _ := testCertificate(t, "api.example.com", false, nil, nil)
+	attacker, _ := testCertificate(t, "api.example.com", false, nil, nil)
+
+	tests := []struct {
+		name   string
+		pins   []*x509.Certificate
+		chain  []*x509.Certificate
+		server string
+		ok     bool
+	}{
+		{"pinned leaf", []*x509.Certificate{selfSigned}, []*x509.Certificate{selfSigned}, "api.example.com", true},
+		{"leaf signed by pinned CA", []*x509.Certificate{ca}, []*x509.Certificate{leaf, ca}, "api.example.com", true},
+		{"pinned CA for another host", []*x509.Certificate{ca}, []*x509.Certificate{leaf, ca}, "other.example.com", false},
+		{"unpinned leaf", []*x509.Certificate{ca}, []*x509.Certificate{selfSigned}, "api.example.com", false},
+		{"pinned certificate appended to another leaf", []*x509.Certificate{selfSigned}, []*x509.Certificate{attacker, selfSigned}, "api.example.com", false},
+		{"pinned CA appended to another leaf", []*x509.Certificate{ca}, []*x509.Certificate{attacker, leaf, ca}, "api.example.com", false},
+		{"no certificates", []*x509.Certificate{ca}, nil, "api.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinned := map[string]bool{}
+			for _, cert := range tt.pins {
+				pinned[spkiPin(cert)] = true
+			}
+			err := verifyPins(tls.ConnectionState{PeerCertificates: tt.chain, ServerName: tt.server}, pinned)
+			if tt.ok && err != nil {
+				t.Fatalf("verifyPins: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrCertificatePin) {
+				t.Fatalf("verifyPins = %v, want ErrCertificatePin", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package example_api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// uploadBody is a request body streamed from the caller's readers rather
+// than serialized: a multipart form holding files, or raw bytes.
+type uploadBody struct {
+	fields      interface{} // the form's text fields, as a request struct
+	files       []formFile
+	raw         io.Reader
+	contentType string // of a raw body
+	offsets     []int64
+}
+
+// formFile is a file part of a multipart upload.
+type formFile struct {
+	field    string
+	filename string
+	content  io.Reader
+}
+
+// errUploadSpent is returned when an upload is resent after its readers
+// were read and cannot seek back.
+var errUploadSpent = errors.New("upload body was already read and cannot be resent")
+
+// multipartBody returns a multipart/form-data body holding the fields of
+// data, named as in JSON, followed by files; files with a nil reader are
+// left out.
+func multipartBody(data interface{}, files ...formFile) *uploadBody {
+	return &uploadBody{fields: data, files: files}
+}
+
+// binaryBody returns a body sending content as is, labeled contentType.
+func binaryBody(contentType string, content io.Reader) *uploadBody {
+	return &uploadBody{raw: content, contentType: contentType}
+}
+
+// WithContentType overrides the Content-Type of a raw upload, which defaults
+// to the media type seen in the capture.
+func WithContentType(mediaType string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("Content-Type", mediaType)
+	}
+}
+
+// readers returns the caller's readers in the body.
+func (u *uploadBody) readers() []io.Reader {
+	if u.raw != nil {
+		return []io.Reader{u.raw}
+	}
+	readers := make([]io.Reader, 0, len(u.files))
+	for _, f := range u.files {
+		if f.content != nil {
+			readers = append(readers, f.content)
+		}
+	}
+	return readers
+}
+
+// replayable reports whether the body can be sent again, as every reader
+// can seek back to where the first attempt started.
+func (u *uploadBody) replayable() bool {
+	for _, r := range u.readers() {
+		if _, ok := r.(io.Seeker); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// rewind seeks the readers back to where the first attempt started them,
+// or notes where that is on the first attempt.
+func (u *uploadBody) rewind() error {
+	readers := u.readers()
+	if u.offsets == nil {
+		u.offsets = make([]int64, len(readers))
+		for i, r := range readers {
+			if s, ok := r.(io.Seeker); ok {
+				u.offsets[i], _ = s.Seek(0, io.SeekCurrent)
+			}
+		}
+		return nil
+	}
+	if !u.replayable() {
+		return errUploadSpent
+	}
+	for i, r := range readers {
+		if _, err := r.(io.Seeker).Seek(u.offsets[i], io.SeekStart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attach sets the body of req, with its length when every reader's is
+// known, and its Content-Type unless a raw body's was given with
+// WithContentType. The form is only written once the request is sent.
+func (u *uploadBody) attach(req *http.Request) error {
+	if err := u.rewind(); err != nil {
+		return err
+	}
+	if u.raw != nil {
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", u.contentType)
+		}
+		setBody(req, io.NopCloser(u.raw), readerLength(u.raw))
+		return nil
+	}
+	fields, err := formValues(u.fields)
+	if err != nil {
+		return fmt.Errorf("multipart: %w", err)
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	form := multipart.NewWriter(pipeWriter)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	body := &formReader{pipe: pipeReader, write: func() {
+		pipeWriter.CloseWithError(writeForm(form, fields, u.files, true))
+	}}
+	setBody(req, body, formLength(form.Boundary(), fields, u.files))
+	return nil
+}
+
+// setBody sets the body of req, of length bytes or -1 when unknown.
+func setBody(req *http.Request, body io.ReadCloser, length int64) {
+	if length == 0 {
+		body.Close()
+		body = http.NoBody
+	}
+	req.Body, req.ContentLength = body, length
+}
+
+// formReader writes the form as the transport reads it, starting only then
+// so that a request given up before it is sent leaves no writer blocked.
+type formReader struct {
+	pipe  *io.PipeReader
+	write func()
+	once  sync.Once
+}
+
+func (r *formReader) Read(p []byte) (int, error) {
+	r.once.Do(func() { go r.write() })
+	return r.pipe.Read(p)
+}
+
+func (r *formReader) Close() error {
+	return r.pipe.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeForm writes the text fields and then the files to form, with each
+// file's content if withContent is set, and closes it.
+func writeForm(form *multipart.Writer, fields url.Values, files []formFile, withContent bool) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range fields[name] {
+			if err := form.WriteField(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range files {
+		if f.content == nil {
+			continue
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(f.field), quoteEscaper.Replace(f.filename)))
+		contentType := mime.TypeByExtension(path.Ext(f.filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if withContent {
+			if _, err := io.Copy(part, f.content); err != nil {
+				return err
+			}
+		}
+	}
+	return form.Close()
+}
+
+// formLength returns the length of the form writeForm writes with
+// boundary, or -1 when the length of a file is unknown.
+func formLength(boundary string, fields url.Values, files []formFile) int64 {
+	var length int64
+	for _, f := range files {
+		if f.content == nil {
+			continue
+		}
+		n := readerLength(f.content)
+		if n < 0 {
+			return -1
+		}
+		length += n
+	}
+	var counter countingWriter
+	form := multipart.NewWriter(&counter)
+	if err := form.SetBoundary(boundary); err != nil {
+		return -1
+	}
+	if err := writeForm(form, fields, files, false); err != nil {
+		return -1
+	}
+	return length + int64(counter)
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// readerLength returns the number of bytes left in r, or -1 when unknown.
+func readerLength(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		current, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(current, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - current
+	}
+	return -1
+}
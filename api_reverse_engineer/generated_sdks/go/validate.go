@@ -0,0 +1,162 @@
+package example_api
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError reports the fields of a request that break the
+// constraints inferred from the captured traffic: required fields, email
+// and URL formats, lengths, and enum values. Calls return it without
+// sending the request.
+type ValidationError struct {
+	Type   string // "CreateUserRequest"
+	Fields []FieldError
+}
+
+// FieldError is a field breaking a constraint.
+type FieldError struct {
+	Field   string // JSON path, such as "items[0].email"
+	Problem string
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		problems[i] = field.Field + " " + field.Problem
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Type, strings.Join(problems, "; "))
+}
+
+// WithoutRequestValidation sends requests without validating them first,
+// for when the inferred constraints are stricter than the API.
+func WithoutRequestValidation() ClientOption {
+	return func(c *ExampleapiClient) {
+		c.skipValidation = true
+	}
+}
+
+// validate validates a request body unless the client skips validation.
+func (c *ExampleapiClient) validate(request interface{ Validate() error }) error {
+	if c.skipValidation {
+		return nil
+	}
+	return request.Validate()
+}
+
+// validator collects the problems found by a Validate method.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field, problem string) {
+	v.fields = append(v.fields, FieldError{Field: field, Problem: problem})
+}
+
+func (v *validator) require(field string, present bool) {
+	if !present {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) email(field, value string) {
+	if address, err := mail.ParseAddress(value); value != "" && (err != nil || address.Address != value) {
+		v.add(field, "is not an email address")
+	}
+}
+
+func (v *validator) url(field, value string) {
+	if u, err := url.Parse(value); value != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+		v.add(field, "is not an absolute URL")
+	}
+}
+
+// length checks the length of a non-empty value in characters; max is -1
+// for no maximum.
+func (v *validator) length(field, value string, min, max int) {
+	n := utf8.RuneCountInString(value)
+	switch {
+	case value == "":
+	case min == max && n != min:
+		v.add(field, fmt.Sprintf("must be %d characters", min))
+	case n < min:
+		v.add(field, fmt.Sprintf("must be at least %d characters", min))
+	case max >= 0 && n > max:
+		v.add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+func (v *validator) enum(field, value string, valid bool) {
+	if value != "" && !valid {
+		v.add(field, fmt.Sprintf("has unknown value %q", value))
+	}
+}
+
+// nested adds the problems of a nested object, under field.
+func (v *validator) nested(field string, err error) {
+	if err == nil {
+		return
+	}
+	if invalid, ok := err.(*ValidationError); ok {
+		for _, problem := range invalid.Fields {
+			v.add(field+"."+problem.Field, problem.Problem)
+		}
+		return
+	}
+	v.add(field, err.Error())
+}
+
+func (v *validator) result(typ string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Type: typ, Fields: v.fields}
+}
+
+// Validate checks r against the constraints inferred from the captured
+// traffic, returning a *ValidationError listing the fields breaking them.
+func (r *CreatePostRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var v validator
+	v.require("title", r.Title != "")
+	v.require("content", r.Content != "")
+	v.require("status", r.Status != "")
+	v.enum("status", string(r.Status), r.Status.Valid())
+	return v.result("CreatePostRequest")
+}
+
+// Validate checks r against the constraints inferred from the captured
+// traffic, returning a *ValidationError listing the fields breaking them.
+func (r *CreateUserRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var v validator
+	v.email("email", r.Email)
+	v.nested("profile", r.Profile.Validate())
+	return v.result("CreateUserRequest")
+}
+
+// Validate checks r against the constraints inferred from the captured
+// traffic, returning a *ValidationError listing the fields breaking them.
+func (r *UserProfile) Validate() error {
+	return nil
+}
+
+// Validate checks r against the constraints inferred from the captured
+// traffic, returning a *ValidationError listing the fields breaking them.
+func (r *UpdateUserRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var v validator
+	if r.Email != nil {
+		v.email("email", *r.Email)
+	}
+	return v.result("UpdateUserRequest")
+}
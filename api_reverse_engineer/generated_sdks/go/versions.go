@@ -0,0 +1,76 @@
+package example_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// VersionReport describes which version of a multi-version operation served
+// a call made through its facade.
+type VersionReport struct {
+	Operation string // the operation that served the call, "GET /v1/users"
+	Version   string // its version, "v1"
+	Preferred string // the newest version, tried first
+	// FellBack lists the versions that answered 404 or 410, or were
+	// disabled, before Version; newest first.
+	FellBack []string
+	Err      error // the call's error, if no version served it
+}
+
+// VersionHook receives a VersionReport after every facade call.
+type VersionHook func(ctx context.Context, report VersionReport)
+
+// WithVersionHook reports which version served each facade call, to track
+// how far an upstream migration has progressed.
+func WithVersionHook(hook VersionHook) ClientOption {
+	return func(c *ExampleapiClient) {
+		c.versionHook = hook
+	}
+}
+
+// endpointVersion is one version of an operation, newest first in a
+// facade's list.
+type endpointVersion struct {
+	version  string
+	endpoint string
+	path     string
+}
+
+// doVersions calls the newest version of an operation, falling back to the
+// next when it answers 404 or 410 or is disabled. It returns the serving
+// operation for decoding; if every version fails, the newest's error is
+// returned.
+func (c *ExampleapiClient) doVersions(ctx context.Context, method string, versions []endpointVersion, params url.Values, body interface{}, opts ...RequestOption) ([]byte, string, error) {
+	report := VersionReport{Preferred: versions[0].version}
+	var firstErr error
+	for _, v := range versions {
+		var status int
+		responseBody, err := c.doRequest(ctx, method, v.endpoint, v.path, params, body, append(opts[:len(opts):len(opts)], recordStatus(&status))...)
+		if err == nil || !fallsBack(status, err) {
+			report.Operation, report.Version, report.Err = method+" "+v.endpoint, v.version, err
+			c.reportVersion(ctx, opts, report)
+			return responseBody, report.Operation, err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		report.FellBack = append(report.FellBack, v.version)
+	}
+	report.Err = firstErr
+	c.reportVersion(ctx, opts, report)
+	return nil, "", firstErr
+}
+
+// fallsBack reports whether a failed call should be retried on an older
+// version.
+func fallsBack(status int, err error) bool {
+	return status == http.StatusNotFound || status == http.StatusGone || errors.Is(err, ErrEndpointDisabled)
+}
+
+func (c *ExampleapiClient) reportVersion(ctx context.Context, opts []RequestOption, report VersionReport) {
+	if c.versionHook != nil {
+		c.versionHook(newRequestOptions(ctx, c.defaults, opts).ctx, report)
+	}
+}
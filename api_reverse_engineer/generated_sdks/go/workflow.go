@@ -0,0 +1,259 @@
+package example_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Workflow runs dependent calls as named steps (create user, then profile,
+// then role). When a step fails, the steps already done are compensated in
+// reverse order, since the API offers no transactions. With a
+// WorkflowStore, progress is saved after every step, so a run interrupted
+// by a crash or a cancelled context resumes where it stopped:
+//
+//	wf := NewWorkflow("onboard").
+//		Step("user", createUser, deleteUser).
+//		Step("profile", createProfile, nil)
+//	state, err := wf.Run(ctx, "onboard-alice")
+type Workflow struct {
+	name  string
+	steps []workflowStep
+	store WorkflowStore
+}
+
+// WorkflowFunc performs or compensates a step. Steps pass values, such as
+// the IDs of created resources, to later steps and to compensations through
+// state.
+type WorkflowFunc func(ctx context.Context, state *WorkflowState) error
+
+type workflowStep struct {
+	name       string
+	do         WorkflowFunc
+	compensate WorkflowFunc
+}
+
+// WorkflowState is the progress of one run, JSON-encoded by stores.
+type WorkflowState struct {
+	ID        string                     `json:"id"`
+	Completed []string                   `json:"completed"`
+	Values    map[string]json.RawMessage `json:"values,omitempty"`
+	// Compensating is set once a step has failed; resuming continues
+	// undoing the completed steps rather than running new ones, and a fully
+	// compensated run keeps returning its failure.
+	Compensating bool   `json:"compensating,omitempty"`
+	Failed       string `json:"failed,omitempty"`
+	Error        string `json:"error,omitempty"`
+	Done         bool   `json:"done,omitempty"`
+}
+
+// Set stores a JSON-encodable value for later steps.
+func (s *WorkflowState) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if s.Values == nil {
+		s.Values = make(map[string]json.RawMessage)
+	}
+	s.Values[key] = data
+	return nil
+}
+
+// Get decodes the value stored under key into v.
+func (s *WorkflowState) Get(key string, v interface{}) error {
+	data, ok := s.Values[key]
+	if !ok {
+		return fmt.Errorf("workflow %s: no value %q", s.ID, key)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WorkflowStore persists workflow progress. Load returns a nil state for
+// an unknown ID.
+type WorkflowStore interface {
+	Load(ctx context.Context, id string) (*WorkflowState, error)
+	Save(ctx context.Context, state *WorkflowState) error
+}
+
+// FileWorkflowStore keeps each run's state in Dir as <id>.json.
+type FileWorkflowStore struct {
+	Dir string
+}
+
+// Load implements WorkflowStore.
+func (f FileWorkflowStore) Load(_ context.Context, id string) (*WorkflowState, error) {
+	data, err := os.ReadFile(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state WorkflowState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("workflow %s: %w", id, err)
+	}
+	return &state, nil
+}
+
+// Save implements WorkflowStore, replacing the file atomically.
+func (f FileWorkflowStore) Save(_ context.Context, state *WorkflowState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp := f.path(state.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(state.ID))
+}
+
+func (f FileWorkflowStore) path(id string) string {
+	return filepath.Join(f.Dir, filepath.Base(id)+".json")
+}
+
+// WorkflowError reports the step that failed a run. Compensations that
+// failed too are listed; their steps' effects were left in place.
+type WorkflowError struct {
+	Workflow      string
+	Step          string
+	Err           error
+	Compensations []error
+}
+
+func (e *WorkflowError) Error() string {
+	msg := fmt.Sprintf("workflow %s: step %s: %v", e.Workflow, e.Step, e.Err)
+	if len(e.Compensations) > 0 {
+		failed := make([]string, len(e.Compensations))
+		for i, err := range e.Compensations {
+			failed[i] = err.Error()
+		}
+		msg += fmt.Sprintf(" (compensation failed: %s)", strings.Join(failed, "; "))
+	}
+	return msg
+}
+
+func (e *WorkflowError) Unwrap() error {
+	return e.Err
+}
+
+// NewWorkflow starts an empty workflow.
+func NewWorkflow(name string) *Workflow {
+	return &Workflow{name: name}
+}
+
+// Step appends a step. compensate undoes do and may be nil for steps with
+// nothing to undo, such as reads.
+func (w *Workflow) Step(name string, do, compensate WorkflowFunc) *Workflow {
+	w.steps = append(w.steps, workflowStep{name: name, do: do, compensate: compensate})
+	return w
+}
+
+// WithStore saves progress to store after every step and resumes runs
+// found there.
+func (w *Workflow) WithStore(store WorkflowStore) *Workflow {
+	w.store = store
+	return w
+}
+
+// Run runs the workflow under id, resuming the stored state if there is
+// one; a finished run returns at once. A failing step triggers compensation
+// and a *WorkflowError, and retrying needs a new id. A cancelled context
+// stops the run without compensating, so it can be resumed.
+func (w *Workflow) Run(ctx context.Context, id string) (*WorkflowState, error) {
+	state := &WorkflowState{ID: id}
+	if w.store != nil {
+		stored, err := w.store.Load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			state = stored
+		}
+	}
+	if state.Done {
+		return state, nil
+	}
+	if state.Compensating {
+		return state, w.compensate(ctx, state, errors.New(state.Error))
+	}
+
+	completed := make(map[string]bool, len(state.Completed))
+	for _, name := range state.Completed {
+		completed[name] = true
+	}
+	for _, step := range w.steps {
+		if completed[step.name] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return state, err
+		}
+		if err := w.runStep(ctx, step.name, step.do, state); err != nil {
+			if ctx.Err() != nil {
+				return state, err
+			}
+			state.Compensating, state.Failed, state.Error = true, step.name, err.Error()
+			return state, w.compensate(ctx, state, err)
+		}
+		state.Completed = append(state.Completed, step.name)
+		if err := w.save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+	state.Done = true
+	return state, w.save(ctx, state)
+}
+
+// compensate undoes the completed steps, newest first, saving progress so
+// an interrupted compensation resumes too.
+func (w *Workflow) compensate(ctx context.Context, state *WorkflowState, cause error) error {
+	werr := &WorkflowError{Workflow: w.name, Step: state.Failed, Err: cause}
+	if err := w.save(ctx, state); err != nil {
+		werr.Compensations = append(werr.Compensations, err)
+	}
+	for len(state.Completed) > 0 {
+		name := state.Completed[len(state.Completed)-1]
+		if step := w.step(name); step != nil && step.compensate != nil {
+			if err := w.runStep(ctx, "compensate "+name, step.compensate, state); err != nil {
+				werr.Compensations = append(werr.Compensations, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+		state.Completed = state.Completed[:len(state.Completed)-1]
+		if err := w.save(ctx, state); err != nil {
+			werr.Compensations = append(werr.Compensations, err)
+		}
+	}
+	return werr
+}
+
+// runStep calls fn, recovering panics.
+func (w *Workflow) runStep(ctx context.Context, name string, fn WorkflowFunc, state *WorkflowState) (err error) {
+	defer recoverPanic("workflow "+w.name+": "+name, 1, &err)
+	return fn(ctx, state)
+}
+
+func (w *Workflow) step(name string) *workflowStep {
+	for i := range w.steps {
+		if w.steps[i].name == name {
+			return &w.steps[i]
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) save(ctx context.Context, state *WorkflowState) error {
+	if w.store == nil {
+		return nil
+	}
+	return w.store.Save(ctx, state)
+}
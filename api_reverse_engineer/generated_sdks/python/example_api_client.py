@@ -5,24 +5,34 @@ import json
 from urllib.parse import urljoin, quote
 
 # Data Classes
+class ListPostsResponse:
+    posts: List[Dict[str, Any]]
+    total: int
+
+class CreatePostRequest:
+    title: str
+    content: str
+    tags: List[str]
+    status: str
+
 class ListUsersResponse:
     users: List[Dict[str, Any]]
     total: int
     page: int
     limit: int
 
-class ListUsersResponse:
-    id: int
+class CreateUserRequest:
     name: str
     email: str
-    created_at: str
-    is_active: bool
+    password: str
     profile: Dict[str, Any]
 
-class CreateUserRequest:
+class ListUsersResponse:
+    id: int
     name: str
     email: str
-    password: str
+    created_at: str
+    is_active: bool
     profile: Dict[str, Any]
 
 class UpdateUserRequest:
@@ -38,16 +48,6 @@ class UpdateUserResponse:
     updated_at: str
     is_active: bool
 
-class ListPostsResponse:
-    posts: List[Dict[str, Any]]
-    total: int
-
-class CreatePostRequest:
-    title: str
-    content: str
-    tags: List[str]
-    status: str
-
 
 class ExampleapiClient:
     """
@@ -69,25 +69,42 @@ class ExampleapiClient:
         """Set a custom header for all requests"""
         self.session.headers[key] = value
 
-    def list_users(self, **kwargs) -> Dict[str, Any]:
+    def list_posts(self, **kwargs) -> Dict[str, Any]:
         """
-        GET /v1/users
+        GET /v1/posts
         """
-        path = f"/v1/users"
+        path = f"/v1/posts"
         url = urljoin(self.base_url, path)
         
         response = self.session.get(url, **kwargs)
         response.raise_for_status()
         return response.json() if response.content else {}
     
-    def list_users(self, id: str, **kwargs) -> Dict[str, Any]:
+    def create_post(self, data: Optional[Dict[str, Any]] = None, **kwargs) -> Dict[str, Any]:
         """
-        GET /v1/users/{id}
+        POST /v1/posts
         
-        Path Parameters:
-            - id: str
+        Request Body:
+            Dict[str, Any]
         """
-        path = f"/v1/users/{quote(str(id))}"
+        path = f"/v1/posts"
+        url = urljoin(self.base_url, path)
+        
+        if data and isinstance(data, dict):
+            json_data = data
+        elif data:
+            json_data = data if isinstance(data, dict) else vars(data)
+        else:
+            json_data = None
+        response = self.session.post(url, json=json_data, **kwargs)
+        response.raise_for_status()
+        return response.json() if response.content else {}
+    
+    def list_users(self, **kwargs) -> Dict[str, Any]:
+        """
+        GET /v1/users
+        """
+        path = f"/v1/users"
         url = urljoin(self.base_url, path)
         
         response = self.session.get(url, **kwargs)
@@ -114,6 +131,20 @@ class ExampleapiClient:
         response.raise_for_status()
         return response.json() if response.content else {}
     
+    def list_users(self, id: str, **kwargs) -> Dict[str, Any]:
+        """
+        GET /v1/users/{id}
+        
+        Path Parameters:
+            - id: str
+        """
+        path = f"/v1/users/{quote(str(id))}"
+        url = urljoin(self.base_url, path)
+        
+        response = self.session.get(url, **kwargs)
+        response.raise_for_status()
+        return response.json() if response.content else {}
+    
     def update_user(self, id: str, data: Optional[Dict[str, Any]] = None, **kwargs) -> Dict[str, Any]:
         """
         PUT /v1/users/{id}
@@ -150,35 +181,4 @@ class ExampleapiClient:
         response = self.session.delete(url, **kwargs)
         response.raise_for_status()
         return response.json() if response.content else {}
-    
-    def list_posts(self, **kwargs) -> Dict[str, Any]:
-        """
-        GET /v1/posts
-        """
-        path = f"/v1/posts"
-        url = urljoin(self.base_url, path)
-        
-        response = self.session.get(url, **kwargs)
-        response.raise_for_status()
-        return response.json() if response.content else {}
-    
-    def create_post(self, data: Optional[Dict[str, Any]] = None, **kwargs) -> Dict[str, Any]:
-        """
-        POST /v1/posts
-        
-        Request Body:
-            Dict[str, Any]
-        """
-        path = f"/v1/posts"
-        url = urljoin(self.base_url, path)
-        
-        if data and isinstance(data, dict):
-            json_data = data
-        elif data:
-            json_data = data if isinstance(data, dict) else vars(data)
-        else:
-            json_data = None
-        response = self.session.post(url, json=json_data, **kwargs)
-        response.raise_for_status()
-        return response.json() if response.content else {}
     
\ No newline at end of file
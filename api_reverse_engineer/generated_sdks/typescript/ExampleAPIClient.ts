@@ -2,6 +2,18 @@
 // Base URL: https://api.example.com
 
 // Type Definitions
+interface ListPostsResponse {
+  posts: Record<string, any>[];
+  total: number;
+}
+
+interface CreatePostRequest {
+  title: string;
+  content: string;
+  tags: string[];
+  status: string;
+}
+
 interface ListUsersResponse {
   users: Record<string, any>[];
   total: number;
@@ -9,19 +21,19 @@ interface ListUsersResponse {
   limit: number;
 }
 
-interface ListUsersResponse {
-  id: number;
+interface CreateUserRequest {
   name: string;
   email: string;
-  created_at: string;
-  is_active: boolean;
+  password: string;
   profile: Record<string, any>;
 }
 
-interface CreateUserRequest {
+interface ListUsersResponse {
+  id: number;
   name: string;
   email: string;
-  password: string;
+  created_at: string;
+  is_active: boolean;
   profile: Record<string, any>;
 }
 
@@ -40,18 +52,6 @@ interface UpdateUserResponse {
   is_active: boolean;
 }
 
-interface ListPostsResponse {
-  posts: Record<string, any>[];
-  total: number;
-}
-
-interface CreatePostRequest {
-  title: string;
-  content: string;
-  tags: string[];
-  status: string;
-}
-
 // API Client
 export class ExampleapiClient {
   private baseUrl: string;
@@ -111,8 +111,8 @@ export class ExampleapiClient {
     return {} as T;
   }
 
-  async list_users(): Promise<Record<string, any>> {
-    const path = `/v1/users`;
+  async list_posts(): Promise<Record<string, any>> {
+    const path = `/v1/posts`;
     
     return this.request<Record<string, any>>({
       method: 'GET',
@@ -120,8 +120,18 @@ export class ExampleapiClient {
     });
   }
 
-  async list_users(id: string): Promise<Record<string, any>> {
-    const path = `/v1/users/${id}`;
+  async create_post(data?: Record<string, any>): Promise<any> {
+    const path = `/v1/posts`;
+    
+    return this.request<any>({
+      method: 'POST',
+      path,
+      body: data,
+    });
+  }
+
+  async list_users(): Promise<Record<string, any>> {
+    const path = `/v1/users`;
     
     return this.request<Record<string, any>>({
       method: 'GET',
@@ -139,41 +149,31 @@ export class ExampleapiClient {
     });
   }
 
-  async update_user(id: string, data?: Record<string, any>): Promise<Record<string, any>> {
+  async list_users(id: string): Promise<Record<string, any>> {
     const path = `/v1/users/${id}`;
     
     return this.request<Record<string, any>>({
-      method: 'PUT',
+      method: 'GET',
       path,
-      body: data,
     });
   }
 
-  async delete_user(id: string): Promise<any> {
+  async update_user(id: string, data?: Record<string, any>): Promise<Record<string, any>> {
     const path = `/v1/users/${id}`;
     
-    return this.request<any>({
-      method: 'DELETE',
-      path,
-    });
-  }
-
-  async list_posts(): Promise<Record<string, any>> {
-    const path = `/v1/posts`;
-    
     return this.request<Record<string, any>>({
-      method: 'GET',
+      method: 'PUT',
       path,
+      body: data,
     });
   }
 
-  async create_post(data?: Record<string, any>): Promise<any> {
-    const path = `/v1/posts`;
+  async delete_user(id: string): Promise<any> {
+    const path = `/v1/users/${id}`;
     
     return this.request<any>({
-      method: 'POST',
+      method: 'DELETE',
       path,
-      body: data,
     });
   }
 
@@ -1,35 +1,255 @@
-from typing import Dict, List, Any
+from typing import Dict, List, Any, Optional, Tuple
 from sdk_generator import SDKGenerator
 from traffic_parser import APIEndpoint
+from grpc_parser import GrpcParser, InferredMessage
+from webhook_parser import WebhookParser
+from websocket_parser import WebSocketParser
+from fixture_synthesizer import FixtureSynthesizer
 
 
 class GoSDKGenerator(SDKGenerator):
-    def generate(self, output_dir: str = 'generated_sdks/go') -> str:
-        package_name = self._to_snake_case(self.api_name).replace('-', '_')
+    def __init__(self, api_name: str, base_url: str, endpoints: Dict[str, APIEndpoint],
+                 grpc_parser: Optional[GrpcParser] = None,
+                 fixture_synthesizer: Optional[FixtureSynthesizer] = None,
+                 result_types: bool = False, struct_depth: int = 3,
+                 webhook_parser: Optional[WebhookParser] = None,
+                 environments: Optional[Dict[str, str]] = None,
+                 locked_names: Optional[Dict[str, Dict[str, str]]] = None,
+                 websocket_parser: Optional[WebSocketParser] = None):
+        super().__init__(api_name, base_url, endpoints)
+        self.grpc_parser = grpc_parser
+        self.webhook_parser = webhook_parser
+        self.websocket_parser = websocket_parser
+        # The deployments of the API the capture reached, by name. The client
+        # defaults to production, whichever host the capture started on.
+        self.environments = dict(environments or {})
+        if base_url not in self.environments.values():
+            self.environments = {'production': base_url, **{name: url for name, url in self.environments.items()
+                                                            if name != 'production'}}
+        self.default_environment = 'production' if 'production' in self.environments else next(iter(self.environments))
+        self.base_url = self.environments[self.default_environment]
+        self.fixture_synthesizer = fixture_synthesizer
+        # Methods return Result[T] rather than (T, error).
+        self.result_types = result_types
+        # Named string types for fields seen taking a few values each.
+        self.enum_fields, self.enum_values = self._find_go_enums()
+        self.optional_fields = self._find_go_optional_fields()
+        # Objects nested this many levels deep in a body get structs of
+        # their own; deeper ones stay maps.
+        self.struct_depth = struct_depth
+        # The names a previous generation gave operations and types, as read
+        # by read_names_lock; they are kept while they still fit.
+        self.locked_names = locked_names or {}
+        self.struct_types, self.nested_structs = self._find_go_structs()
+    
+    def _find_go_enums(self) -> Tuple[Dict[int, str], Dict[str, List[str]]]:
+        """The fields of request, response, and event bodies that the capture
+        shows taking two or more of a handful of values, or their arrays: the
+        named type of each field's schema, keyed by its id, and the values of
+        each type. Types are named after the resource and field, as
+        PostStatus for the status of /posts and /posts/{id} bodies, so all of
+        a resource's bodies share them; array items take the singular, as
+        PostLabel for labels."""
+        import re
+        fields, values = {}, {}
+        for endpoint in self.endpoints.values():
+            resource = self._go_resource(endpoint)
+            for schema in [endpoint.request_body_schema, *endpoint.response_schemas.values(), endpoint.event_schema]:
+                for name, prop in ((schema or {}).get('properties') or {}).items():
+                    for field, noun in ((prop, name), (prop.get('items'), re.sub(r'(?<!s)s$', '', name))):
+                        if isinstance(field, dict) and field.get('type') == 'string' and len(field.get('enum') or []) >= 2:
+                            type_name = resource + self._go_param_field(noun)
+                            fields[id(field)] = type_name
+                            values.setdefault(type_name, set()).update(field['enum'])
+        return fields, {name: sorted(found) for name, found in sorted(values.items())}
+    
+    def _go_resource(self, endpoint: APIEndpoint) -> str:
+        """The singular resource of endpoint's path for naming types, Post for
+        /v1/posts/{id}."""
+        import re
+        literals = [segment for segment in endpoint.path_pattern.split('/') if segment
+                    and not segment.startswith('{') and not re.fullmatch(self.VERSION_SEGMENT, segment)]
+        return self._go_param_field(literals[-1].rstrip('s')) if literals else ''
+    
+    def _go_success_status(self, endpoint: APIEndpoint) -> Optional[int]:
+        """The status of the response endpoint's method decodes: 200, or else
+        the first other 2xx captured, as a 201 answering a create."""
+        statuses = sorted(status for status in endpoint.response_schemas if 200 <= status < 300)
+        return 200 if 200 in statuses else next(iter(statuses), None)
+    
+    def _go_envelope(self, endpoint: APIEndpoint) -> str:
+        """The field holding the payload of endpoint's 2xx response, when
+        inference found the payload wrapped in an envelope next to metadata
+        such as {"data": ..., "meta": ...}; '' otherwise."""
+        schema = endpoint.response_schemas.get(self._go_success_status(endpoint)) or {}
+        field = schema.get('envelope', '')
+        if not field or field not in schema.get('properties', {}) or not self._go_json_call(endpoint):
+            return ''
+        return field
+    
+    def _go_envelope_meta(self, endpoint: APIEndpoint) -> Dict[str, Any]:
+        """The fields of endpoint's response envelope other than its payload,
+        as an object schema."""
+        schema = endpoint.response_schemas[self._go_success_status(endpoint)]
+        field = self._go_envelope(endpoint)
+        return {'type': 'object', 'properties': {name: prop for name, prop in schema['properties'].items() if name != field}}
+    
+    def _find_go_structs(self) -> Tuple[Dict[int, str], Dict[str, Dict[str, Any]]]:
+        """Named structs for the objects nested in request, response, and event
+        bodies, struct_depth levels deep at most: the type of each object's
+        schema, keyed by its id, and the schema of each type. Types are named
+        after the resource and the field path, as UserProfile for the profile
+        of /users bodies, and a list's items after the singular, as
+        PostComment for comments (PostItem for posts). The payload of a
+        response envelope is named after the resource alone, as Post. Objects
+        of the same name and shape share a struct; a differing shape gets a
+        number, or the name the names lock gives its field path."""
+        import re
+        self.struct_types, structs, shapes = {}, {}, {}
+        # The struct of each field path, as "GET /users Response.profile".
+        self.struct_labels = {}
+        locked = self.locked_names.get('structs', {})
+        reserved = set(locked.values())
+        
+        def visit(schema, prefix, depth, label, payload=''):
+            for field, prop in (schema.get('properties') or {}).items():
+                target, noun, path = prop, field, f"{label}.{field}"
+                if prop.get('type') == 'array' and isinstance(prop.get('items'), dict):
+                    target, noun, path = prop['items'], re.sub(r'(?<!s)s$', '', field), path + '[]'
+                if target.get('type') != 'object' or not target.get('properties') or depth >= self.struct_depth:
+                    continue
+                noun = self._go_param_field(noun)
+                base = prefix + ('Item' if noun == prefix else noun)
+                if field == payload:
+                    base = prefix or 'Item'
+                visit(target, base, depth + 1, path)
+                shape = self._generate_interface_from_schema('T', target, 'go')
+                name = locked.get(path, '')
+                if not re.fullmatch(re.escape(base) + r'\d*', name) or shapes.get(name, shape) != shape:
+                    name, number = base, 1
+                    while shapes.get(name, shape) != shape or (name in reserved and name not in shapes):
+                        number += 1
+                        name = f"{base}{number}"
+                shapes[name] = shape
+                structs.setdefault(name, target)
+                self.struct_types[id(target)] = name
+                self.struct_labels[path] = name
+        
+        for endpoint in self.endpoints.values():
+            status = self._go_success_status(endpoint)
+            schemas = [("Response", endpoint.response_schemas.get(status)), ("Event", endpoint.event_schema),
+                       ("Request", endpoint.request_body_schema)]
+            for kind, schema in schemas:
+                if isinstance(schema, dict) and schema.get('type') == 'object':
+                    visit(schema, self._go_resource(endpoint), 0, f"{endpoint.method} {endpoint.path_pattern} {kind}",
+                          self._go_envelope(endpoint) if kind == "Response" else '')
+        for event in (self.webhook_parser.events.values() if self.webhook_parser else []):
+            if event.schema.get('type') == 'object':
+                visit(event.schema, event.name, 0, f"webhook {event.name}")
+        return self.struct_types, structs
+    
+    def _go_partial_update(self, endpoint: APIEndpoint) -> bool:
+        """Whether endpoint updates a single resource in place (PATCH, or PUT
+        to a path ending in a parameter), so callers send only the fields
+        they change."""
+        return endpoint.method in ('PATCH', 'PUT') and endpoint.path_pattern.rstrip('/').endswith('}')
+    
+    def _find_go_optional_fields(self) -> set:
+        """The request body fields a caller may leave out, as ids of their
+        schemas: every top-level field of a partial update, and elsewhere the
+        fields the capture shows missing or null. Their Go fields are pointers
+        tagged omitempty, so a nil field is not sent while a pointer to an
+        empty value is."""
+        optional = set()
+        
+        def visit(schema, everything):
+            for name, prop in (schema.get('properties') or {}).items():
+                if everything or prop.get('nullable') or name not in schema.get('required', []):
+                    optional.add(id(prop))
+                if prop.get('type') == 'object':
+                    visit(prop, False)
+            if isinstance(schema.get('items'), dict):
+                visit(schema['items'], False)
+        
+        for endpoint in self.endpoints.values():
+            if endpoint.request_body_schema:
+                visit(endpoint.request_body_schema, self._go_partial_update(endpoint))
+        return optional
+    
+    def _generate_interface_from_schema(self, name: str, schema: Dict[str, Any], lang: str, indent: int = 0,
+                                        extra: bool = False) -> str:
+        """A Go struct for an object schema. With extra, as for response
+        types, the struct also gets the Extra map tolerant decoding keeps
+        unknown fields in (ExtraFields if a property takes that name)."""
+        if lang != 'go' or schema.get('type') != 'object' or not schema.get('properties'):
+            return super()._generate_interface_from_schema(name, schema, lang, indent)
+        indent_str = '  ' * indent
+        lines = [f"{indent_str}type {name} struct {{"]
+        names = set()
+        for prop_name, prop_schema in schema['properties'].items():
+            go_name, prop_type, tag = self._go_field(prop_name, prop_schema)
+            names.add(go_name)
+            lines.append(f'{indent_str}    {go_name} {prop_type} `json:"{tag}"`')
+        if extra:
+            lines += ['', f'{indent_str}    // Extra holds the fields the response had but this type lacks, kept',
+                      f'{indent_str}    // when the client decodes with DecodeTolerant.',
+                      f'{indent_str}    {"ExtraFields" if "Extra" in names else "Extra"} map[string]json.RawMessage `json:"-"`']
+        lines.append(f"{indent_str}}}")
+        return '\n'.join(lines)
+    
+    def _go_field(self, prop_name: str, prop_schema: Dict[str, Any]) -> Tuple[str, str, str]:
+        """The name, type, and JSON tag of the struct field for a property."""
+        prop_type = self._schema_to_type_hint(prop_schema, 'go')
+        if prop_type == 'interface{}' and prop_schema.get('conflicting_types'):
+            prop_type = 'json.RawMessage'
+        tag = prop_name
+        if id(prop_schema) in self.optional_fields:
+            tag += ',omitempty'
+            if not prop_type.startswith(('*', '[]', 'map[', 'interface{')):
+                prop_type = f"*{prop_type}"
+        return ''.join(word.capitalize() for word in prop_name.split('_')), prop_type, tag
+    
+    def _schema_to_type_hint(self, schema: Dict[str, Any], lang: str) -> str:
+        if lang == 'go' and id(schema) in self.enum_fields:
+            name = self.enum_fields[id(schema)]
+            return f"*{name}" if schema.get('nullable') else name
+        if lang == 'go' and id(schema) in self.struct_types:
+            name = self.struct_types[id(schema)]
+            return f"*{name}" if schema.get('nullable') else name
+        if lang == 'go' and schema.get('type') in ('integer', 'number') and schema.get('format') == 'date-time':
+            name = self.EPOCH_DATE_FORMATS.get(schema.get('date_format'), 'UnixTime')
+            return f"*{name}" if schema.get('nullable') else name
+        return super()._schema_to_type_hint(schema, lang)
+    
+    def generate(self, output_dir: str = 'generated_sdks/go', package_name: Optional[str] = None,
+                 module_dir: Optional[str] = None, module_name: Optional[str] = None) -> str:
+        package_name = package_name or self._to_snake_case(self.api_name).replace('-', '_')
         
         imports = [
             "package " + package_name,
             "",
             "import (",
             '\t"bytes"',
-            '\t"encoding/json"',
+            '\t"context"',
             '\t"fmt"',
             '\t"io"',
             '\t"net/http"',
             '\t"net/url"',
+            '\t"runtime/pprof"',
             '\t"strings"',
+            '\t"sync"',
             '\t"time"',
             ")",
             ""
         ]
+        if self._go_uses_int_path_params():
+            imports.insert(imports.index('\t"strings"'), '\t"strconv"')
         
         structs = []
         
         for endpoint_key, endpoint in self.endpoints.items():
-            method_name = self._path_to_method_name(endpoint.method, endpoint.path_pattern)
-            
             if endpoint.request_body_schema and endpoint.request_body_schema.get('type') == 'object':
-                request_struct_name = self._to_class_name(method_name) + "Request"
+                request_struct_name = self._go_struct_name(endpoint, "Request")
                 request_struct = self._generate_interface_from_schema(
                     request_struct_name,
                     endpoint.request_body_schema,
@@ -38,20 +258,52 @@ class GoSDKGenerator(SDKGenerator):
                 if request_struct and request_struct not in structs:
                     structs.append(request_struct)
             
+            if self._go_envelope(endpoint):
+                meta_schema = self._go_envelope_meta(endpoint)
+                if meta_schema['properties']:
+                    meta_struct = self._generate_interface_from_schema(
+                        self._go_struct_name(endpoint, "Meta"), meta_schema, 'go')
+                    if meta_struct not in structs:
+                        structs.append(meta_struct)
+            
             for status, response_schema in endpoint.response_schemas.items():
-                if status == 200 and response_schema.get('type') == 'object':
-                    response_struct_name = self._to_class_name(method_name) + "Response"
+                if self._go_envelope(endpoint):
+                    break
+                if status == self._go_success_status(endpoint) and response_schema.get('type') == 'object':
+                    response_struct_name = self._go_struct_name(endpoint, "Response")
                     response_struct = self._generate_interface_from_schema(
                         response_struct_name,
                         response_schema,
-                        'go'
+                        'go',
+                        extra=True
                     )
                     if response_struct and response_struct not in structs:
                         structs.append(response_struct)
+            
+            if endpoint.stream and endpoint.event_schema.get('type') == 'object':
+                event_struct = self._generate_interface_from_schema(
+                    self._go_struct_name(endpoint, "Event"),
+                    endpoint.event_schema,
+                    'go',
+                    extra=True
+                )
+                if event_struct and event_struct not in structs:
+                    structs.append(event_struct)
+            
+            params_struct = self._generate_go_params_struct(endpoint)
+            if params_struct:
+                structs.append(params_struct)
         
         client_struct = self._generate_go_client_struct()
         client_methods = self._generate_go_client_methods()
         
+        # Only the objects the client decodes keep the fields they lack.
+        decoded = {name for label, name in self.struct_labels.items() if ' Request.' not in label}
+        structs.extend(self._generate_interface_from_schema(name, schema, 'go', extra=name in decoded)
+                       for name, schema in self.nested_structs.items())
+        if any('json.RawMessage' in struct for struct in structs + [client_methods]):
+            imports.insert(imports.index('\t"context"') + 1, '\t"encoding/json"')
+        
         content = '\n'.join(imports)
         
         if structs:
@@ -69,132 +321,1699 @@ class GoSDKGenerator(SDKGenerator):
         with open(output_file, 'w') as f:
             f.write(content)
         
-        self._generate_go_mod(output_dir, package_name)
+        self._write_go_file(f"{output_dir}/debug.go", self._generate_go_debug(package_name))
+        self._write_go_file(f"{output_dir}/slog.go", self._generate_go_slog(package_name))
+        self._write_go_file(f"{output_dir}/instrument.go", self._generate_go_instrument(package_name))
+        self._write_go_file(f"{output_dir}/dump.go", self._generate_go_dump(package_name))
+        self._write_go_file(f"{output_dir}/dryrun.go", self._generate_go_dryrun(package_name))
+        self._write_go_file(f"{output_dir}/telemetry.go", self._generate_go_telemetry(package_name))
+        self._write_go_file(f"{output_dir}/stats.go", self._generate_go_stats(package_name))
+        self._write_go_file(f"{output_dir}/metrics.go", self._generate_go_metrics(package_name))
+        self._write_go_file(f"{output_dir}/latency.go", self._generate_go_latency(package_name, self._captured_latencies()))
+        self._write_go_file(f"{output_dir}/time.go", self._generate_go_time(package_name, self._observed_time_layouts()))
+        self._write_go_file(f"{output_dir}/time_test.go", self._generate_go_time_test(package_name))
+        self._write_go_file(f"{output_dir}/decimal.go", self._generate_go_decimal(package_name))
+        self._write_go_file(f"{output_dir}/options.go", self._generate_go_options(package_name))
+        self._write_go_file(f"{output_dir}/optional.go", self._generate_go_optional(package_name))
+        self._write_go_file(f"{output_dir}/baseurl.go", self._generate_go_baseurl(package_name))
+        self._write_go_file(f"{output_dir}/ratelimit.go", self._generate_go_ratelimit(package_name))
+        self._write_go_file(f"{output_dir}/transport.go", self._generate_go_transport(package_name))
+        self._write_go_file(f"{output_dir}/transport_test.go", self._generate_go_transport_test(package_name))
+        self._write_go_file(f"{output_dir}/client_test.go", self._generate_go_client_test(package_name))
+        self._write_go_file(f"{output_dir}/profile.go", self._generate_go_profile(package_name))
+        self._write_go_file(f"{output_dir}/env.go", self._generate_go_env(package_name))
+        self._write_go_file(f"{output_dir}/environment.go", self._generate_go_environment(package_name))
+        self._write_go_file(f"{output_dir}/secret.go", self._generate_go_secret(package_name))
+        self._write_go_file(f"{output_dir}/tokencache.go", self._generate_go_tokencache(package_name))
+        self._write_go_file(f"{output_dir}/oauth.go", self._generate_go_oauth(package_name, self._detect_token_endpoint()))
+        self._write_go_file(f"{output_dir}/errors.go", self._generate_go_errors(package_name))
+        self._write_go_file(f"{output_dir}/limits.go", self._generate_go_limits(package_name))
+        self._write_go_file(f"{output_dir}/compression.go", self._generate_go_compression(package_name))
+        self._write_go_file(f"{output_dir}/compression_test.go", self._generate_go_compression_test(package_name))
+        self._write_go_file(f"{output_dir}/lifecycle.go", self._generate_go_lifecycle(package_name))
+        self._write_go_file(f"{output_dir}/recover.go", self._generate_go_recover(package_name))
+        self._write_go_file(f"{output_dir}/path.go", self._generate_go_path(package_name))
+        self._write_go_file(f"{output_dir}/upload.go", self._generate_go_upload(package_name))
+        self._write_go_file(f"{output_dir}/redirect.go", self._generate_go_redirect(package_name))
+        self._write_go_file(f"{output_dir}/retry.go", self._generate_go_retry(package_name))
+        self._write_go_file(f"{output_dir}/idempotency.go", self._generate_go_idempotency(package_name, self._captured_idempotency_header()))
+        self._write_go_file(f"{output_dir}/cache.go", self._generate_go_cache(package_name))
+        self._write_go_file(f"{output_dir}/breaker.go", self._generate_go_breaker(package_name))
+        self._write_go_file(f"{output_dir}/validate.go", self._generate_go_validate(package_name))
+        self._write_go_file(f"{output_dir}/middleware.go", self._generate_go_middleware(package_name))
+        self._write_go_file(f"{output_dir}/mock.go", self._generate_go_mock(package_name))
+        self._write_go_file(f"{output_dir}/checksum.go", self._generate_go_checksum(package_name))
+        self._write_go_file(f"{output_dir}/checksum_test.go", self._generate_go_checksum_test(package_name))
+        self._write_go_file(f"{output_dir}/jose.go", self._generate_go_jose(package_name, self._jose_operations()))
+        self._write_go_file(f"{output_dir}/serializer.go", self._generate_go_serializer(package_name, *self._serializer_content_types()))
+        self._write_go_file(f"{output_dir}/codec.go", self._generate_go_codec(package_name))
+        self._write_go_file(f"{output_dir}/envelope.go", self._generate_go_envelope(package_name))
+        self._write_go_file(f"{output_dir}/signature.go", self._generate_go_signature(package_name, *self._observed_signature_headers()))
+        self._write_go_file(f"{output_dir}/signer.go", self._generate_go_signer(package_name, self._observed_request_signing()))
+        self._write_go_file(f"{output_dir}/auth.go", self._generate_go_auth(package_name, self._captured_auth()))
+        self._write_go_file(f"{output_dir}/audit.go", self._generate_go_audit(package_name))
+        self._write_go_file(f"{output_dir}/quota.go", self._generate_go_quota(package_name))
+        self._write_go_file(f"{output_dir}/endpoints.go", self._generate_go_endpoints(package_name))
+        self._write_go_file(f"{output_dir}/versions.go", self._generate_go_versions(package_name))
+        services = self._go_version_services()
+        if services:
+            self._write_go_file(f"{output_dir}/services.go", self._generate_go_services(package_name, services))
+        self._write_go_file(f"{output_dir}/workflow.go", self._generate_go_workflow(package_name))
+        self._write_go_file(f"{output_dir}/health.go", self._generate_go_health(package_name, self._ping_endpoint()))
+        self._write_go_file(f"{output_dir}/locale.go", self._generate_go_locale(package_name, self._observed_locale_headers()))
+        tenant_scope = self._detect_tenant_scope()
+        if tenant_scope:
+            self._write_go_file(f"{output_dir}/tenant.go", self._generate_go_tenant(package_name, *tenant_scope))
+        batch = self._detect_batch_endpoint()
+        if batch:
+            self._write_go_file(f"{output_dir}/batch.go", self._generate_go_batch(package_name, batch))
+        bulk = self._go_bulk_operations()
+        if bulk:
+            self._write_go_file(f"{output_dir}/bulk.go", self._generate_go_bulk(package_name, bulk))
+        if self.webhook_parser and self.webhook_parser.events:
+            self._write_go_file(f"{output_dir}/webhook.go", self._generate_go_webhook(package_name))
+        if self.websocket_parser and self.websocket_parser.channels:
+            self._write_go_file(f"{output_dir}/websocket.go", self._generate_go_websocket(package_name))
+        feed = self._detect_change_feed()
+        if feed:
+            self._write_go_file(f"{output_dir}/changefeed.go", self._generate_go_changefeed(package_name, feed))
+        if any(endpoint.download for endpoint in self.endpoints.values()):
+            self._write_go_file(f"{output_dir}/download.go", self._generate_go_download(package_name))
+        streams = [endpoint for endpoint in self.endpoints.values() if endpoint.stream]
+        if streams:
+            self._write_go_file(f"{output_dir}/stream.go", self._generate_go_stream(package_name, streams[0]))
+        if self.result_types:
+            self._write_go_file(f"{output_dir}/result.go", self._generate_go_result(package_name))
+        if self.enum_values:
+            self._write_go_file(f"{output_dir}/enums.go", self._generate_go_enums(package_name))
+        
+        if self.grpc_parser and self.grpc_parser.methods:
+            with open(f"{output_dir}/grpc_types.go", 'w') as f:
+                f.write(self._generate_go_grpc_types(package_name))
+            with open(f"{output_dir}/inferred.proto", 'w') as f:
+                f.write(self.grpc_parser.render_proto())
+        
+        if self.fixture_synthesizer:
+            labels = [f"{endpoint.method} {endpoint.path_pattern}" for endpoint in self.endpoints.values()]
+            self._write_go_file(f"{output_dir}/fixtures/fixtures.go", self._generate_go_fixtures())
+            self._write_go_file(f"{output_dir}/fixtures/profiles.json", self.fixture_synthesizer.render_profiles(labels))
+            fixtures_import = self._go_import_path(module_dir or output_dir, module_name or package_name, f"{output_dir}/fixtures")
+            self._write_go_file(f"{output_dir}/factories.go", self._generate_go_factories(package_name, fixtures_import))
+        self._write_go_file(f"{output_dir}/sdktest/server.go", self._generate_go_sdktest_server())
+        self._write_go_file(f"{output_dir}/sdktest/samples.json", self._render_server_samples())
+        self._write_go_file(f"{output_dir}/sdktest/assert.go", self._generate_go_sdktest_assert(f"github.com/example/{module_name or package_name}"))
+        self._write_go_file(f"{output_dir}/fake/fake.go", self._generate_go_fake())
+        self._write_go_file(f"{output_dir}/fake/resources.json", self._render_fake_resources())
+        sdk_import = self._go_import_path(module_dir or output_dir, module_name or package_name, output_dir)
+        self._write_go_file(f"{output_dir}/cmd/selftest/main.go", self._generate_go_selftest(sdk_import))
+        self._write_go_file(f"{output_dir}/cmd/compare/main.go", self._generate_go_compare(sdk_import, f"github.com/example/{module_name or package_name}"))
+        
+        module_root = module_dir or output_dir
+        os.makedirs(module_root, exist_ok=True)
+        self._generate_go_mod(module_root, module_name or package_name)
+        self._write_go_file(f"{module_root}/capturestore/store.go", self._generate_go_capture_store())
+        module_path = f"github.com/example/{module_name or package_name}"
+        self._write_go_file(f"{module_root}/alert/alert.go", self._generate_go_alert())
+        self._write_go_file(f"{module_root}/drift/drift.go", self._generate_go_drift(module_path))
+        self._write_go_file(f"{module_root}/vcr/vcr.go", self._generate_go_vcr(module_path))
+        self._write_go_file(f"{module_root}/jsondiff/jsondiff.go", self._generate_go_jsondiff())
+        self._write_go_file(f"{module_root}/agenttool/agenttool.go", self._generate_go_agenttool())
+        self._write_go_file(f"{module_root}/otel/go.mod", self._generate_go_otel_mod(module_path))
+        self._write_go_file(f"{module_root}/otel/otel.go", self._generate_go_otel(module_path, sdk_import))
+        self._write_go_file(f"{output_dir}/tools.go", self._generate_go_tools(package_name, f"{module_path}/agenttool"))
+        self._write_go_file(f"{output_dir}/cmd/{self._go_cli_name()}/main.go", self._generate_go_cli(sdk_import, module_path))
+        self._write_go_file(f"{module_root}/export/export.go", self._generate_go_export_package())
+        self._write_go_file(f"{module_root}/export/parquet.go", self._generate_go_export_parquet())
+        self._write_go_file(f"{output_dir}/export.go", self._generate_go_export(package_name, f"{module_path}/export"))
+        pager_specs = self._pager_specs()
+        if pager_specs:
+            self._write_go_file(f"{output_dir}/pager.go", self._generate_go_pager(package_name, pager_specs))
+        poll_specs = self._poll_specs()
+        if poll_specs:
+            self._write_go_file(f"{output_dir}/poll.go", self._generate_go_poll(package_name, poll_specs))
+        sync_specs = self._sync_specs()
+        if sync_specs:
+            self._write_go_file(f"{output_dir}/sync.go", self._generate_go_sync(package_name, sync_specs))
+        if sync_specs or self._detect_change_feed():
+            self._write_go_file(f"{output_dir}/checkpoint.go", self._generate_go_checkpoint(package_name))
+        self._write_go_file(f"{output_dir}/openapi.yaml", self._generate_openapi())
+        self._write_go_file(f"{output_dir}/{self.NAMES_LOCK}", self._render_names_lock())
         self._generate_readme(output_dir)
         
         return output_file
     
+    # Written beside the SDK: the names given to operations and types, so
+    # regenerating from a new capture keeps them even where a newcomer
+    # would otherwise take a name or shift a number.
+    NAMES_LOCK = 'names.lock.json'
+    
+    @staticmethod
+    def read_names_lock(output_dir: str) -> Dict[str, Dict[str, str]]:
+        """The names lock a previous generation wrote to output_dir, or none
+        when there was none."""
+        import json
+        import os
+        path = os.path.join(output_dir, GoSDKGenerator.NAMES_LOCK)
+        if not os.path.exists(path):
+            return {}
+        with open(path, 'r') as f:
+            return json.load(f)
+    
+    def _render_names_lock(self) -> str:
+        """The names lock: operations by method and path, body structs by
+        method, path, and kind, and nested structs by field path."""
+        import json
+        struct_names = self._go_struct_names()
+        types = {}
+        for endpoint in self.endpoints.values():
+            for kind in ("Request", "Response", "Meta", "Event"):
+                if (id(endpoint), kind) in struct_names:
+                    types[f"{endpoint.method} {endpoint.path_pattern} {kind}"] = struct_names[(id(endpoint), kind)]
+        lock = {
+            'operations': {f"{method} {path}": name for (method, path), name in self._go_operation_names().items()},
+            'types': types,
+            'structs': self.struct_labels,
+        }
+        return json.dumps(lock, indent=2, sort_keys=True) + '\n'
+    
+    def _generate_openapi(self) -> str:
+        from openapi import OpenAPIExporter
+        operation_ids = {key: self._go_name(endpoint) for key, endpoint in self.endpoints.items()}
+        return OpenAPIExporter(self.api_name, self.base_url, self.endpoints, self.environments,
+                               operation_ids, self._captured_auth()).render()
+    
+    def _go_import_path(self, module_root: str, module_name: str, package_dir: str) -> str:
+        import os
+        relative = os.path.relpath(package_dir, module_root).replace(os.sep, '/')
+        module_path = f"github.com/example/{module_name}"
+        return module_path if relative == '.' else f"{module_path}/{relative}"
+    
+    # The Go types of timestamps the capture shows as Unix epoch numbers.
+    EPOCH_DATE_FORMATS = {'unix': 'UnixTime', 'unixmilli': 'UnixMilliTime'}
+    
+    STRPTIME_TO_GO = {
+        '%Y': '2006', '%y': '06', '%m': '01', '%d': '02', '%H': '15', '%I': '03',
+        '%M': '04', '%S': '05', '%f': '000000', '%p': 'PM', '%z': '-0700', '%Z': 'MST',
+        '%b': 'Jan', '%B': 'January', '%a': 'Mon', '%A': 'Monday', '%j': '002', '%%': '%',
+    }
+    
+    def _observed_time_layouts(self) -> List[str]:
+        """Go layouts for the non-ISO date formats recorded in the schemas."""
+        import re
+        layouts = []
+        
+        def visit(schema):
+            if not isinstance(schema, dict):
+                return
+            date_format = schema.get('date_format')
+            if date_format and date_format not in self.EPOCH_DATE_FORMATS:
+                codes = re.findall(r'%.', date_format)
+                if all(code in self.STRPTIME_TO_GO for code in codes):
+                    layout = re.sub(r'%.', lambda m: self.STRPTIME_TO_GO[m.group(0)], date_format)
+                    if layout not in layouts:
+                        layouts.append(layout)
+            for prop in (schema.get('properties') or {}).values():
+                visit(prop)
+            visit(schema.get('items'))
+        
+        for endpoint in self.endpoints.values():
+            visit(endpoint.request_body_schema)
+            for _, schema in sorted(endpoint.response_schemas.items()):
+                visit(schema)
+        return layouts
+    
+    LOCALE_HEADER_PATTERN = r'locale|language|(^|-)lang$'
+    
+    SIGNATURE_HEADER_PATTERN = r'(^|-)signature(-\d+)?$'
+    SIGNATURE_TIMESTAMP_PATTERN = r'(^|-)(signature-)?timestamp$'
+    
+    def _observed_signature_headers(self) -> Tuple[str, str]:
+        """The response signature and timestamp headers seen in the capture,
+        defaulting to X-Signature and X-Timestamp."""
+        import re
+        signature, timestamp = 'X-Signature', 'X-Timestamp'
+        found_signature = found_timestamp = False
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                for name in (example.get('response', {}).get('headers') or {}):
+                    if not found_signature and re.search(self.SIGNATURE_HEADER_PATTERN, name.lower()):
+                        signature, found_signature = name, True
+                    elif not found_timestamp and re.search(self.SIGNATURE_TIMESTAMP_PATTERN, name.lower()):
+                        timestamp, found_timestamp = name, True
+        return signature, timestamp
+    
+    SIGV4_HOST_PATTERN = r'(?:^|\.)([a-z0-9-]+)\.([a-z]{2}(?:-gov)?-[a-z]+-\d)\.amazonaws\.com$'
+    
+    def _observed_request_signing(self) -> Dict[str, str]:
+        """The request signing conventions seen in the capture: the signature
+        and timestamp headers, the signature's encoding and the timestamp's
+        format, and the region and service of SigV4-signed requests."""
+        import re
+        from urllib.parse import urlparse
+        signing = {'header': 'X-Signature', 'timestamp_header': 'X-Timestamp', 'encoding': 'hex',
+                   'timestamp_format': 'unix', 'sigv4_region': '', 'sigv4_service': ''}
+        found_signature = found_timestamp = sigv4 = False
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                for name, value in (example.get('request', {}).get('headers') or {}).items():
+                    lowered, value = name.lower(), str(value)
+                    if lowered == 'x-amz-date':
+                        sigv4 = True
+                    elif lowered == 'authorization':
+                        scope = re.search(r'Credential=[^/]+/\d{8}/([^/]+)/([^/]+)/aws4_request', value)
+                        if scope:
+                            signing['sigv4_region'], signing['sigv4_service'] = scope.groups()
+                    elif not found_signature and re.search(self.SIGNATURE_HEADER_PATTERN, lowered):
+                        found_signature = True
+                        signing['header'] = name
+                        signature = value.split('=', 1)[-1] if re.match(r'^\w+=[^=]', value) else value
+                        if not re.fullmatch(r'[0-9a-fA-F]+', signature):
+                            signing['encoding'] = 'base64'
+                    elif not found_timestamp and re.search(self.SIGNATURE_TIMESTAMP_PATTERN, lowered):
+                        found_timestamp = True
+                        signing['timestamp_header'] = name
+                        if not value.isdigit():
+                            signing['timestamp_format'] = 'rfc3339'
+                        elif len(value) >= 13:
+                            signing['timestamp_format'] = 'unixmilli'
+        if sigv4 and not signing['sigv4_region']:
+            host = re.search(self.SIGV4_HOST_PATTERN, urlparse(self.base_url).netloc.split(':')[0])
+            if host:
+                signing['sigv4_service'], signing['sigv4_region'] = host.groups()
+        return signing
+    
+    JOSE_CONTENT_TYPES = ('application/jose', 'application/jwt')
+    
+    def _jose_operations(self) -> Dict[str, bool]:
+        """Operations whose captured requests or responses carried a JOSE
+        content type, mapped to whether their request bodies did (JWE)."""
+        operations = {}
+        for endpoint in self.endpoints.values():
+            sides = {side for example in endpoint.examples for side in ('request', 'response')
+                     if self._content_type(example.get(side, {})).startswith(self.JOSE_CONTENT_TYPES)}
+            if sides:
+                operations[f"{endpoint.method} {endpoint.path_pattern}"] = 'request' in sides
+        return operations
+    
+    def _serializer_content_types(self) -> Tuple[Dict[str, str], Dict[str, str]]:
+        """The request and response media types of operations captured with
+        bodies in another format than JSON. JOSE payloads are left to their
+        codec, and responses only count in structured formats, as text and
+        binary bodies are not decoded into types; streamed events are JSON, and
+        uploads and downloads are passed through as they are."""
+        requests, responses = {}, {}
+        for endpoint in self.endpoints.values():
+            operation = f"{endpoint.method} {endpoint.path_pattern}"
+            for example in endpoint.examples:
+                for side, found in (('request', requests), ('response', responses)):
+                    message = example.get(side, {})
+                    if side == 'request' and endpoint.upload:
+                        continue
+                    media_type = self._content_type(message)
+                    if (not media_type or not message.get('body') or media_type == 'application/json'
+                            or media_type.startswith(self.JOSE_CONTENT_TYPES)):
+                        continue
+                    if side == 'response' and (endpoint.stream or endpoint.download or media_type.endswith('+json') or not (
+                            media_type.startswith('application/') or media_type == 'text/xml')
+                            or media_type == 'application/octet-stream'):
+                        continue
+                    found.setdefault(operation, media_type)
+        return requests, responses
+    
+    def _request_encodings(self) -> Dict[str, str]:
+        """The operations the server was seen accepting compressed request
+        bodies for, with the encoding: one a captured request was sent with
+        and that did not fail, or gzip when a response to the operation lists
+        it in Accept-Encoding, as servers advertising request codings do
+        (RFC 7694). Uploads are left as they are, being mostly compressed
+        already."""
+        encodings = {}
+        for endpoint in self.endpoints.values():
+            if endpoint.upload or endpoint.method in ('GET', 'HEAD', 'DELETE', 'OPTIONS'):
+                continue
+            operation = f"{endpoint.method} {endpoint.path_pattern}"
+            for example in endpoint.examples:
+                request, response = example.get('request', {}), example.get('response', {})
+                sent = self._header(request, 'content-encoding').lower()
+                sent = 'gzip' if sent == 'x-gzip' else sent
+                accepted = [coding.split(';')[0].strip().lower()
+                            for coding in self._header(response, 'accept-encoding').split(',')]
+                if sent in ('gzip', 'deflate') and response.get('status', 200) < 400:
+                    encodings[operation] = sent
+                elif 'gzip' in accepted:
+                    encodings.setdefault(operation, 'gzip')
+        return encodings
+    
+    def _header(self, message: Dict[str, Any], name: str) -> str:
+        headers = message.get('headers') or {}
+        return next((str(v).strip() for k, v in headers.items() if k.lower() == name), '')
+    
+    def _content_type(self, message: Dict[str, Any]) -> str:
+        headers = message.get('headers') or {}
+        value = next((str(v) for k, v in headers.items() if k.lower() == 'content-type'), '')
+        return value.split(';')[0].strip().lower()
+    
+    def _observed_locale_headers(self) -> List[Tuple[str, str]]:
+        """Accept-Language plus the vendor locale headers sent in the capture,
+        with the separator their values use between language and region."""
+        import re
+        headers = [('Accept-Language', '-')]
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                for name, value in (example.get('request', {}).get('headers') or {}).items():
+                    lower = name.lower()
+                    if lower in ('accept-language', 'content-language') or not re.search(self.LOCALE_HEADER_PATTERN, lower):
+                        continue
+                    if all(lower != known.lower() for known, _ in headers):
+                        separator = '_' if '_' in str(value) else '-' if '-' in str(value) else ''
+                        headers.append((name, separator))
+        return headers
+    
+    TENANT_NAMES = ('org', 'orgs', 'organization', 'organizations', 'tenant', 'tenants', 'workspace',
+                    'workspaces', 'account', 'accounts', 'team', 'teams', 'company', 'companies')
+    
+    HEALTH_PATHS = ('health', 'healthz', 'healthcheck', 'health-check', 'ping', 'status', 'ready', 'readyz',
+                    'livez', 'heartbeat')
+    
+    def _ping_endpoint(self) -> str:
+        """The path Ping calls: a captured health endpoint, else the GET
+        without parameters whose captured responses were smallest, else
+        the API root."""
+        candidates = [e for e in self.endpoints.values() if e.method == 'GET' and not e.path_params and self._go_json_call(e)]
+        for endpoint in candidates:
+            if endpoint.path_pattern.rstrip('/').rsplit('/', 1)[-1].lower() in self.HEALTH_PATHS:
+                return endpoint.path_pattern
+        
+        def size(endpoint):
+            bodies = [example.get('response', {}).get('body') or '' for example in endpoint.examples]
+            return min((len(body) if isinstance(body, str) else len(str(body)) for body in bodies), default=0)
+        
+        candidates = [e for e in candidates if not e.query_params] or candidates
+        return min(candidates, key=size).path_pattern if candidates else '/'
+    
+    def _detect_tenant_scope(self) -> Optional[Tuple[str, str]]:
+        """The request header and/or path collection ("orgs" in /orgs/{id}/...)
+        scoping at least half of the endpoints to a tenant, or None."""
+        import re
+        from collections import Counter
+        headers, collections, names = Counter(), Counter(), {}
+        for endpoint in self.endpoints.values():
+            seen = set()
+            for example in endpoint.examples:
+                for name in (example.get('request', {}).get('headers') or {}):
+                    words = [w for w in re.split(r'[-_]', name.lower()) if w not in ('x', 'id')]
+                    if len(words) == 1 and words[0] in self.TENANT_NAMES:
+                        names.setdefault(name.lower(), name)
+                        seen.add(name.lower())
+            headers.update(seen)
+            segments = endpoint.path_pattern.split('/')
+            for i, segment in enumerate(segments[:-1]):
+                if segment.lower() in self.TENANT_NAMES and segments[i + 1].startswith('{'):
+                    collections[segment] += 1
+                    break
+        
+        def pick(counter):
+            if counter:
+                value, count = counter.most_common(1)[0]
+                if count * 2 >= len(self.endpoints):
+                    return value
+            return ''
+        
+        header, collection = names.get(pick(headers), ''), pick(collections)
+        return (header, collection) if header or collection else None
+    
+    def _generate_go_factories(self, package_name: str, fixtures_import: str) -> str:
+        """Seeded factories for every request/response struct, backed by the
+        fixtures package's learned value distributions."""
+        factories = []
+        seen = set()
+        for endpoint in self.endpoints.values():
+            label = f"{endpoint.method} {endpoint.path_pattern}"
+            candidates = [(self._go_struct_name(endpoint, "Request"), endpoint.request_body_schema, f'f.gen.Request("{label}")')]
+            status = self._go_success_status(endpoint)
+            envelope = self._go_envelope(endpoint)
+            if envelope:
+                # The envelope's payload, as its method returns it.
+                payload = endpoint.response_schemas[status]['properties'][envelope]
+                call = f'f.gen.ResponseFor("{label}", {status})\n\tbody = envelopePayload("{label}", body)'
+                candidates.append((self.struct_types.get(id(payload), ''), payload, call))
+            elif status:
+                candidates.append((self._go_struct_name(endpoint, "Response"), endpoint.response_schemas[status], f'f.gen.ResponseFor("{label}", {status})'))
+            for struct_name, schema, call in candidates:
+                if schema.get('type') != 'object' or not schema.get('properties') or not struct_name or struct_name in seen:
+                    continue
+                seen.add(struct_name)
+                factories.append(f"""// {struct_name} returns a populated {struct_name} for {label}, with
+// overrides applied in order.
+func (f *FixtureFactory) {struct_name}(overrides ...func(*{struct_name})) *{struct_name} {{
+\tv := &{struct_name}{{}}
+\tbody, err := {call}
+\tf.fill(body, err, v)
+\tfor _, override := range overrides {{
+\t\toverride(v)
+\t}}
+\treturn v
+}}
+
+// New{struct_name}Fixture returns a {struct_name} populated with seed 1.
+func New{struct_name}Fixture(overrides ...func(*{struct_name})) *{struct_name} {{
+\treturn NewFixtureFactory(1).{struct_name}(overrides...)
+}}""")
+        
+        return f"""package {package_name}
+
+import (
+\t"encoding/json"
+\t"fmt"
+
+\t"{fixtures_import}"
+)
+
+// FixtureFactory builds populated request and response values for tests
+// from the value distributions learned from captured traffic. The same seed
+// always yields the same values; a FixtureFactory is not safe for
+// concurrent use.
+type FixtureFactory struct {{
+\tgen *fixtures.Generator
+}}
+
+// NewFixtureFactory creates a FixtureFactory seeded with seed.
+func NewFixtureFactory(seed int64) *FixtureFactory {{
+\treturn &FixtureFactory{{gen: fixtures.New(seed)}}
+}}
+
+func (f *FixtureFactory) fill(body json.RawMessage, err error, v interface{{}}) {{
+\tif err == nil {{
+\t\terr = json.Unmarshal(body, v)
+\t}}
+\tif err != nil {{
+\t\tpanic(fmt.Sprintf("fixtures: %v", err))
+\t}}
+}}
+
+""" + '\n\n'.join(factories) + '\n'
+    
+    SAMPLE_HEADERS = ('content-type', 'cache-control', 'etag', 'link', 'location', 'retry-after')
+    MAX_SERVER_SAMPLES = 5
+
+    def _render_server_samples(self) -> str:
+        """Captured responses per endpoint for the sdktest fixture server."""
+        import json
+        from urllib.parse import urlparse
+        samples = {}
+        for endpoint in self.endpoints.values():
+            pattern = endpoint.path_pattern.split('/')
+            endpoint_samples = []
+            for example in endpoint.examples[:self.MAX_SERVER_SAMPLES]:
+                request, response = example.get('request', {}), example.get('response', {})
+                url = urlparse(request.get('url', ''))
+                segments = url.path.split('/')
+                params = {}
+                if len(segments) == len(pattern):
+                    params = {p[1:-1]: v for p, v in zip(pattern, segments) if p.startswith('{') and p.endswith('}')}
+                body = response.get('body')
+                sample = {'path': url.path, 'status': response.get('status', 200)}
+                headers = {k: v for k, v in sorted(response.get('headers', {}).items())
+                           if k.lower() in self.SAMPLE_HEADERS}
+                if headers:
+                    sample['headers'] = headers
+                if params:
+                    sample['params'] = params
+                if url.query:
+                    sample['query'] = url.query
+                if body:
+                    sample['body'] = body if isinstance(body, str) else json.dumps(body, separators=(',', ':'))
+                if response.get('encoding') == 'base64':
+                    sample['encoding'] = 'base64'
+                endpoint_samples.append(sample)
+            samples[f"{endpoint.method} {endpoint.path_pattern}"] = endpoint_samples
+        return json.dumps(samples, indent=2, sort_keys=True) + '\n'
+    
+    CREATED_FIELDS = ('created_at', 'createdAt', 'created', 'inserted_at')
+    UPDATED_FIELDS = ('updated_at', 'updatedAt', 'modified_at', 'modifiedAt', 'updated')
+    LIST_META_FIELDS = ('total', 'count', 'total_count', 'page', 'limit', 'per_page', 'page_size', 'offset', 'has_more')
+
+    def _render_fake_resources(self) -> str:
+        """Collections with CRUD routes for the in-memory fake package."""
+        import json
+        import re
+        collections: Dict[str, Dict[str, Any]] = {}
+        for endpoint in self.endpoints.values():
+            segments = endpoint.path_pattern.rstrip('/').split('/')
+            is_item = segments[-1].startswith('{') and segments[-1].endswith('}')
+            path = '/'.join(segments[:-1]) if is_item else endpoint.path_pattern.rstrip('/')
+            if not path or path.split('/')[-1].startswith('{'):
+                continue
+            resource = collections.setdefault(path, {'routes': [], 'statuses': {}, 'items': [], 'list': None})
+            resource['routes'].append(f"{endpoint.method} {endpoint.path_pattern}")
+            resource.setdefault('request_fields', set()).update(endpoint.request_body_schema.get('properties', {}))
+            if is_item:
+                resource['item'] = endpoint.path_pattern
+            for status, schema in sorted(endpoint.response_schemas.items()):
+                if 200 <= status < 300:
+                    resource['statuses'].setdefault(endpoint.method, status)
+                    if is_item or endpoint.method == 'POST':
+                        resource['items'].append(schema)
+                    elif endpoint.method == 'GET':
+                        resource['list'] = schema
+            if not endpoint.response_schemas and endpoint.examples:
+                resource['statuses'].setdefault(endpoint.method, endpoint.examples[0]['response'].get('status', 200))
+
+        resources = []
+        for path, found in sorted(collections.items()):
+            list_schema = found['list'] or {}
+            list_key, list_meta = '', []
+            if list_schema.get('type') == 'object':
+                for key, prop in list_schema.get('properties', {}).items():
+                    if prop.get('type') == 'array' and not list_key:
+                        list_key = key
+                        found['items'].append(prop.get('items', {}))
+                    elif key in self.LIST_META_FIELDS:
+                        list_meta.append(key)
+                        if key in ('limit', 'per_page', 'page_size') and isinstance(prop.get('example'), int):
+                            found['default_limit'] = prop['example']
+            elif list_schema.get('type') == 'array':
+                found['items'].append(list_schema.get('items', {}))
+            if not found['items'] and not list_key:
+                continue
+
+            properties: Dict[str, Any] = {}
+            for schema in found['items']:
+                for key, prop in schema.get('properties', {}).items():
+                    properties.setdefault(key, prop)
+            id_field = 'id' if 'id' in properties or not properties else next(
+                (key for key in properties if key.endswith('_id') or key.endswith('Id')), 'id')
+            id_schema = properties.get(id_field, {})
+            id_kind = 'integer' if id_schema.get('type') == 'integer' else 'string'
+            if id_schema.get('type') == 'string' and re.match(r'^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-', str(id_schema.get('example', ''))):
+                id_kind = 'uuid'
+
+            defaults = {}
+            for key, prop in sorted(properties.items()):
+                if key == id_field:
+                    continue
+                if prop.get('type') == 'boolean' and 'example' in prop:
+                    defaults[key] = prop['example']
+                elif prop.get('type') in ('integer', 'number') and not (key.endswith('_id') or key.endswith('Id')):
+                    defaults[key] = 0
+                elif prop.get('type') == 'array':
+                    defaults[key] = []
+
+            resource = {
+                'name': path.split('/')[-1],
+                'collection': path,
+                'id_field': id_field,
+                'id_kind': id_kind,
+                'routes': sorted(found['routes']),
+                'statuses': found['statuses']
+            }
+            if found.get('item'):
+                resource['item'] = found['item']
+            if list_key:
+                resource['list_key'] = list_key
+            if list_meta:
+                resource['list_meta'] = list_meta
+            if found.get('default_limit'):
+                resource['default_limit'] = found['default_limit']
+            for field, candidates in (('created_at', self.CREATED_FIELDS), ('updated_at', self.UPDATED_FIELDS)):
+                name = next((key for key in candidates if key in properties), None)
+                if name:
+                    resource[field] = name
+            for key in (resource.get('created_at'), resource.get('updated_at')):
+                defaults.pop(key, None)
+            if defaults:
+                resource['defaults'] = defaults
+            write_only = sorted(found.get('request_fields', set()) - set(properties)) if properties else []
+            if write_only:
+                resource['write_only'] = write_only
+            resources.append(resource)
+        return json.dumps(resources, indent=2, sort_keys=True) + '\n'
+    
+    def _write_go_file(self, path: str, content: str):
+        import os
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        with open(path, 'w') as f:
+            f.write(content)
+    
+    def _go_version_fields(self) -> str:
+        """The client's version namespace fields, after a blank line, if
+        the capture had versioned paths."""
+        services = self._go_version_services()
+        if not services:
+            return ''
+        names = ' and '.join([', '.join(s['field'] for s in services[:-1]), services[-1]['field']] if len(services) > 1 else [services[0]['field']])
+        service, resource = services[-1], services[-1]['resources'][0]
+        import textwrap
+        comment = textwrap.wrap(f"{names} group the API methods by version and resource, as "
+                                f"{service['field']}.{resource['field']}.{resource['methods'][0][0]}, sharing the "
+                                "client's configuration.", 70)
+        if len(services) == 1:
+            comment = textwrap.wrap(' '.join(comment).replace(' group ', ' groups ', 1), 70)
+        rows = self._align_go_columns([[s['field'], f"*{s['type']}"] for s in services])
+        return '\n' + '\n'.join('\t// ' + line for line in comment) + '\n' + '\n'.join(rows) + '\n'
+    
     def _generate_go_client_struct(self) -> str:
+        versions = bool(self._go_version_services())
+        bind_versions = "\tc.bindVersions()\n" if versions else ''
+        bind_derived = "\tderived.bindVersions()\n" if versions else ''
         return f"""type {self.class_name}Client struct {{
 \tBaseURL    string
 \tHTTPClient *http.Client
-\tHeaders    map[string]string
+\t// Headers are sent with every request. Change them with SetHeader and
+\t// SetAuthToken, which are safe while calls are in flight, or derive a
+\t// client with WithHeader; writing to the map directly is not.
+\tHeaders map[string]string
+{self._go_version_fields()}
+\theaderMu    *sync.RWMutex
+\tdebug       *debugLogger
+\tlogger      callLogger
+\tschema      *schemaTelemetry
+\tstats       *clientStats
+\ttimeLayouts *TimeLayoutRegistry
+\tlimiter     *rateLimiter
+\tcredentials []*secretCredential
+\tlimits      sizeLimits
+\tlifecycle   *lifecycle
+\tchecksums   checksumPolicy
+\tcodecs      map[string]PayloadCodec
+\tserializers map[string]Serializer
+\tcodec       Codec
+\tdecodeMode  DecodeMode
+\tsignatures  *SignatureVerifier
+\tsigner      Signer
+\tauditSink   AuditSink
+\tquota       *quotaTracker
+\tquotaWait   bool
+\tendpoints   *EndpointRegistry
+\tversionHook VersionHook
+\tretry       *RetryPolicy
+\tcache       ResponseCache
+\tbreaker     *circuitBreaker
+\tmiddleware  []Middleware
+\tdefaults    []RequestOption
+
+\tacceptEncoding    string
+\tcompressMinSize   int
+\terrorMessages     *ErrorMessages
+\tinstrumentation   Instrumentation
+\tidempotencyHeader string
+\tqueryCredentials  map[string]string
+\tskipValidation    bool
 }}
 
+// ClientOption configures a client created with New{self.class_name}Client.
+type ClientOption func(*{self.class_name}Client)
+
 // New{self.class_name}Client creates a new API client
-func New{self.class_name}Client(baseURL string) *{self.class_name}Client {{
+func New{self.class_name}Client(baseURL string, opts ...ClientOption) *{self.class_name}Client {{
 \tif baseURL == "" {{
-\t\tbaseURL = "{self.base_url}"
+\t\tbaseURL = defaultBaseURL
 \t}}
-\treturn &{self.class_name}Client{{
+\tc := &{self.class_name}Client{{
 \t\tBaseURL:    strings.TrimSuffix(baseURL, "/"),
-\t\tHTTPClient: &http.Client{{Timeout: 30 * time.Second}},
+\t\tHTTPClient: &http.Client{{Transport: newTransport(), CheckRedirect: RedirectPolicy{{}}.check}},
 \t\tHeaders:    make(map[string]string),
+\t\theaderMu:   &sync.RWMutex{{}},
+\t\tschema:     &schemaTelemetry{{}},
+\t\tstats:      &clientStats{{}},
+\t\tlifecycle:  &lifecycle{{}},
+\t\tquota:      &quotaTracker{{}},
+\t\tendpoints:  &EndpointRegistry{{}},
+
+\t\tacceptEncoding:    DefaultAcceptEncoding,
+\t\tcompressMinSize:   DefaultCompressMinSize,
+\t\tidempotencyHeader: capturedIdempotencyHeader,
 \t}}
+{bind_versions}\tfor _, opt := range opts {{
+\t\topt(c)
+\t}}
+\treturn c
 }}
 
-// SetAuthToken sets the authorization token
+// SetAuthToken sets the authorization token. It is safe to call while
+// other goroutines make calls, which send the old token or the new one.
 func (c *{self.class_name}Client) SetAuthToken(token string) {{
-\tc.Headers["Authorization"] = fmt.Sprintf("Bearer %s", token)
+\tc.SetHeader("Authorization", fmt.Sprintf("Bearer %s", token))
 }}
 
-// SetHeader sets a custom header
+// SetHeader sets a custom header. It is safe to call while other
+// goroutines make calls.
 func (c *{self.class_name}Client) SetHeader(key, value string) {{
+\tc.headerMu.Lock()
+\tdefer c.headerMu.Unlock()
 \tc.Headers[key] = value
 }}
 
-// doRequest performs the HTTP request
-func (c *{self.class_name}Client) doRequest(method, path string, params url.Values, body interface{{}}) ([]byte, error) {{
-\tfullURL := c.BaseURL + path
+// WithHeader returns a client that also sends the header, leaving c as it
+// is; it shares everything else with c, as derived clients do. Use it to
+// give part of a program its own headers without affecting calls in flight.
+func (c *{self.class_name}Client) WithHeader(key, value string) *{self.class_name}Client {{
+\tderived := c.derive()
+\tderived.Headers[key] = value
+\treturn derived
+}}
+
+// WithAuthToken returns a client that authorizes with token, leaving c as
+// it is, e.g. to act for one user of a multi-user service.
+func (c *{self.class_name}Client) WithAuthToken(token string) *{self.class_name}Client {{
+\treturn c.WithHeader("Authorization", fmt.Sprintf("Bearer %s", token))
+}}
+
+// derive returns a copy of c that shares its HTTP client, telemetry, stats,
+// lifecycle, quota, and endpoint registry, and applies defaults to every
+// call before any per-call options. Its headers are its own.
+func (c *{self.class_name}Client) derive(defaults ...RequestOption) *{self.class_name}Client {{
+\tc.headerMu.RLock()
+\tderived := *c // queryCredentials is replaced under the lock
+\tc.headerMu.RUnlock()
+\tderived.Headers = c.headers()
+\tderived.headerMu = &sync.RWMutex{{}}
+\tderived.defaults = append(append([]RequestOption(nil), c.defaults...), defaults...)
+{bind_derived}\treturn &derived
+}}
+
+// headers returns a copy of the client's headers.
+func (c *{self.class_name}Client) headers() map[string]string {{
+\tc.headerMu.RLock()
+\tdefer c.headerMu.RUnlock()
+\theaders := make(map[string]string, len(c.Headers))
+\tfor key, value := range c.Headers {{
+\t\theaders[key] = value
+\t}}
+\treturn headers
+}}
+
+// doRequest performs the HTTP request with ctx, labelled with the endpoint
+// and method for CPU and goroutine profiles.
+func (c *{self.class_name}Client) doRequest(ctx context.Context, method, endpoint, path string, params url.Values, body interface{{}}, opts ...RequestOption) (responseBody []byte, err error) {{
+\tif err := c.lifecycle.enter(); err != nil {{
+\t\treturn nil, err
+\t}}
+\tdefer c.lifecycle.exit()
+\toptions := newRequestOptions(ctx, c.defaults, opts)
+\tcancel := options.startTimeout()
+\tdefer cancel()
+\tc.assignIdempotencyKey(method, options)
+\tdefer c.instrument(method, endpoint, options)(&err)
+\tdefer c.audit(method, endpoint, path, options, time.Now(), &err)
+\tdefer options.meta.finish(time.Now())
+\tif err := c.checkEndpoint(method + " " + endpoint); err != nil {{
+\t\treturn nil, err
+\t}}
+\tdefer recoverPanic(method+" "+endpoint, 1, &err)
+\tbaseURL := c.baseURL(method, endpoint, options)
+\tpprof.Do(options.ctx, profileLabels(method, endpoint), func(context.Context) {{
+\t\tresponseBody, err = c.sendRetrying(method, endpoint, baseURL, path, params, body, options)
+\t}})
+\tif err == nil && options.envelope != nil {{
+\t\terr = c.decodeEnvelopeMeta(method+" "+endpoint, responseBody, options.envelope)
+\t}}
+\treturn responseBody, err
+}}
+
+// profileLabels are the pprof labels attached to every API call.
+func profileLabels(method, endpoint string) pprof.LabelSet {{
+\treturn pprof.Labels("endpoint", endpoint, "method", method)
+}}
+
+// send performs the HTTP request
+func (c *{self.class_name}Client) send(method, endpoint, baseURL, path string, params url.Values, body interface{{}}, options *requestOptions) ([]byte, error) {{
+\toperation := method + " " + endpoint
+\tcodec := c.codecs[operation]
+\treq, jsonBody, err := c.newRequest(method, endpoint, baseURL, path, params, body, options)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tcached := c.revalidate(req)
+\tif options.dryRun {{
+\t\treturn nil, c.dryRun(operation, req, jsonBody)
+\t}}
+\tresp, err := c.roundTrip(operation, req, options)
+\tif err != nil {{
+\t\tc.debug.log(req, jsonBody, nil, nil, err)
+\t\treturn nil, err
+\t}}
+\tdefer resp.Body.Close()
+\tif cached != nil && resp.StatusCode == http.StatusNotModified {{
+\t\tresp = c.cachedResponse(req, resp, cached)
+\t}}
+\t
+\twire, err := c.limits.readResponse(resp)
+\tif err != nil {{
+\t\tc.debug.log(req, jsonBody, resp, nil, err)
+\t\treturn nil, err
+\t}}
+\tc.storeResponse(req, resp, wire)
+\tresponseBody, err := inflate(resp, wire, c.limits.response)
+\tc.debug.log(req, jsonBody, resp, responseBody, err)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tif err := c.checksums.verify(operation, resp, wire); err != nil {{
+\t\treturn nil, err
+\t}}
+\tif err := c.signatures.verify(operation, resp, responseBody); err != nil {{
+\t\treturn nil, err
+\t}}
+\tif codec != nil {{
+\t\tif responseBody, err = codec.DecodeResponse(operation, resp.Header.Get("Content-Type"), responseBody); err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t}}
+\t
+\tif resp.StatusCode >= 400 {{
+\t\treturn nil, newAPIError(operation, resp.StatusCode, resp.Header, responseBody, c.errorMessages, req.Header.Get("Accept-Language"))
+\t}}
+\tif err := redirectError(resp); err != nil {{
+\t\treturn nil, err
+\t}}
+\t
+\treturn responseBody, nil
+}}
+
+// openResponse makes a call without reading the response body, for streams
+// and downloads. The caller closes the body and then calls release. Error
+// statuses are read and returned as an *APIError.
+func (c *{self.class_name}Client) openResponse(ctx context.Context, method, endpoint, path string, params url.Values, body interface{{}}, opts []RequestOption) (resp *http.Response, release func(), err error) {{
+\tif err := c.lifecycle.enter(); err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tdefer func() {{
+\t\tif resp == nil {{
+\t\t\tc.lifecycle.exit()
+\t\t}}
+\t}}()
+\toperation := method + " " + endpoint
+\toptions := newRequestOptions(ctx, c.defaults, opts)
+\tcancel := options.startTimeout()
+\tdefer func() {{
+\t\tif resp == nil {{
+\t\t\tcancel()
+\t\t}}
+\t}}()
+\tc.assignIdempotencyKey(method, options)
+\tdefer c.instrument(method, endpoint, options)(&err)
+\tdefer c.audit(method, endpoint, path, options, time.Now(), &err)
+\tif err := c.checkEndpoint(operation); err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tdefer recoverPanic(operation, 1, &err)
+\treq, jsonBody, err := c.newRequest(method, endpoint, c.baseURL(method, endpoint, options), path, params, body, options)
+\tif err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tif options.dryRun {{
+\t\treturn nil, nil, c.dryRun(operation, req, jsonBody)
+\t}}
+\tresp, err = c.roundTrip(operation, req, options)
+\tif err != nil {{
+\t\tc.debug.log(req, jsonBody, nil, nil, err)
+\t\treturn nil, nil, err
+\t}}
+\tif resp.StatusCode >= 400 {{
+\t\tdefer resp.Body.Close()
+\t\tresponseBody, err := c.limits.readResponse(resp)
+\t\tc.debug.log(req, jsonBody, resp, responseBody, err)
+\t\tif err != nil {{
+\t\t\treturn nil, nil, err
+\t\t}}
+\t\treturn nil, nil, newAPIError(operation, resp.StatusCode, resp.Header, responseBody, c.errorMessages, req.Header.Get("Accept-Language"))
+\t}}
+\tif err := redirectError(resp); err != nil {{
+\t\tresp.Body.Close()
+\t\tc.debug.log(req, jsonBody, resp, nil, err)
+\t\treturn nil, nil, err
+\t}}
+\tc.debug.log(req, jsonBody, resp, nil, nil)
+\tresp.Body = c.verifiedBody(operation, resp)
+\treturn resp, func() {{
+\t\tcancel()
+\t\tc.lifecycle.exit()
+\t}}, nil
+}}
+
+// newRequest builds the HTTP request of a call, with its body encoded and
+// the client's headers and credentials set, and returns the encoded body.
+func (c *{self.class_name}Client) newRequest(method, endpoint, baseURL, path string, params url.Values, body interface{{}}, options *requestOptions) (*http.Request, []byte, error) {{
+\toperation := method + " " + endpoint
+\tcodec := c.codecs[operation]
+\tupload, _ := body.(*uploadBody)
+\tif upload != nil {{
+\t\tbody = nil
+\t}}
+\tfullURL := baseURL + path
+\tparams = options.queryParams(params)
 \tif params != nil && len(params) > 0 {{
 \t\tfullURL = fullURL + "?" + params.Encode()
 \t}}
 \t
 \tvar bodyReader io.Reader
+\tvar jsonBody, wireBody []byte
+\tvar contentEncoding string
+\tcontentType := "application/json"
+\tif mediaType, ok := requestContentTypes[operation]; ok {{
+\t\tcontentType = mediaType
+\t}}
 \tif body != nil {{
-\t\tjsonBody, err := json.Marshal(body)
+\t\tbody = c.withTimeLayouts(body)
+\t\tserializer, err := c.serializer(contentType)
 \t\tif err != nil {{
-\t\t\treturn nil, err
+\t\t\treturn nil, nil, err
+\t\t}}
+\t\tjsonBody, err = serializer.Marshal(body)
+\t\tif err != nil {{
+\t\t\treturn nil, nil, err
+\t\t}}
+\t\tif codec != nil {{
+\t\t\tif jsonBody, contentType, err = codec.EncodeRequest(operation, jsonBody); err != nil {{
+\t\t\t\treturn nil, nil, err
+\t\t\t}}
+\t\t}}
+\t\tif err := c.limits.checkRequest(int64(len(jsonBody))); err != nil {{
+\t\t\treturn nil, nil, err
+\t\t}}
+\t\tif wireBody, contentEncoding, err = c.compressRequest(operation, jsonBody); err != nil {{
+\t\t\treturn nil, nil, err
 \t\t}}
-\t\tbodyReader = bytes.NewBuffer(jsonBody)
+\t\tbodyReader = bytes.NewBuffer(wireBody)
 \t}}
 \t
-\treq, err := http.NewRequest(method, fullURL, bodyReader)
+\treq, err := http.NewRequestWithContext(options.ctx, method, fullURL, bodyReader)
 \tif err != nil {{
-\t\treturn nil, err
+\t\treturn nil, nil, err
 \t}}
 \t
 \tif body != nil {{
-\t\treq.Header.Set("Content-Type", "application/json")
+\t\treq.Header.Set("Content-Type", contentType)
+\t}}
+\tif contentEncoding != "" {{
+\t\treq.Header.Set("Content-Encoding", contentEncoding)
+\t}}
+\tif c.acceptEncoding != "" {{
+\t\treq.Header.Set("Accept-Encoding", c.acceptEncoding)
 \t}}
 \t
+\tc.headerMu.RLock()
 \tfor key, value := range c.Headers {{
 \t\treq.Header.Set(key, value)
 \t}}
-\t
-\tresp, err := c.HTTPClient.Do(req)
+\tc.headerMu.RUnlock()
+\tc.applyQueryCredentials(req)
+\tif err := c.applyCredentials(req); err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tc.setIdempotencyKey(req, options)
+\toptions.apply(req)
+\tif err := checkPath(endpoint, req.URL.EscapedPath()); err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tif upload != nil {{
+\t\tif err := upload.attach(req); err != nil {{
+\t\t\treturn nil, nil, err
+\t\t}}
+\t\tif err := c.limits.checkRequest(req.ContentLength); err != nil {{
+\t\t\treq.Body.Close()
+\t\t\treturn nil, nil, err
+\t\t}}
+\t}}
+\tif err := c.sign(req, wireBody); err != nil {{
+\t\tif upload != nil {{
+\t\t\treq.Body.Close()
+\t\t}}
+\t\treturn nil, nil, err
+\t}}
+\treturn req, jsonBody, nil
+}}
+
+// roundTrip sends req once the client's rate limits and circuit breaker
+// allow, and records the response's status, stats, and quota.
+func (c *{self.class_name}Client) roundTrip(operation string, req *http.Request, options *requestOptions) (*http.Response, error) {{
+\tif err := c.limiter.wait(options.ctx); err != nil {{
+\t\treturn nil, err
+\t}}
+\tif c.quotaWait {{
+\t\tif err := c.WaitForQuota(options.ctx, 1); err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t}}
+\treport, err := c.breaker.allow(c.circuitKey(operation, req))
 \tif err != nil {{
 \t\treturn nil, err
 \t}}
-\tdefer resp.Body.Close()
-\t
-\tresponseBody, err := io.ReadAll(resp.Body)
+\toptions.url = req.URL
+\trequest := c.stats.start(operation, req)
+\tresp, err := c.doer(req).Do(req)
+\treport(options.ctx, resp, err)
+\tif c.logger != nil {{
+\t\tc.logger.logAttempt(options.ctx, operation, options.attempt, req, resp, time.Since(request.started), err)
+\t}}
 \tif err != nil {{
+\t\trequest.finish(nil)
 \t\treturn nil, err
 \t}}
-\t
-\tif resp.StatusCode >= 400 {{
-\t\treturn nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(responseBody))
+\trequest.finish(resp)
+\toptions.status = resp.StatusCode
+\tif options.statusOut != nil {{
+\t\t*options.statusOut = resp.StatusCode
 \t}}
-\t
-\treturn responseBody, nil
+\toptions.meta.record(operation, resp)
+\tc.quota.update(resp)
+\tif resp.StatusCode == http.StatusUnauthorized {{
+\t\tc.expireCredentials(req)
+\t}}
+\treturn resp, nil
 }}"""
     
     def _generate_go_client_methods(self) -> str:
         methods = []
         
         for endpoint_key, endpoint in self.endpoints.items():
-            method_name = self._path_to_method_name(endpoint.method, endpoint.path_pattern)
-            method_name_go = self._to_class_name(method_name)
-            
-            method_lines = self._generate_go_method(method_name_go, endpoint)
+            method_lines = self._generate_go_method(self._go_name(endpoint), endpoint)
             methods.append('\n'.join(method_lines))
+        methods.extend(self._generate_go_version_facades())
+        if self.result_types:
+            methods = ['\n\n'.join(self._go_result_method(function) for function in method.split('\n\n'))
+                       for method in methods]
         
         return '\n\n'.join(methods)
     
+    def _go_client_signatures(self) -> List[Tuple[str, str, List[str], str]]:
+        """The comment, name, parameters, and results of every API method of
+        the client, as generated."""
+        import re
+        signatures = []
+        for method in self._generate_go_client_methods().split('\n\n'):
+            lines = method.split('\n')
+            func = next(i for i, line in enumerate(lines) if line.startswith('func '))
+            name, params, results = re.match(r'func \(c \*\w+\) (\w+)\((.*)\) (.+) \{$', lines[func]).groups()
+            signatures.append(('\n'.join(lines[:func]), name, params.split(', '), results))
+        return signatures
+    
+    def _go_readme_mocks(self) -> str:
+        """The README section on the API interface and its mock."""
+        _, name, _, _ = self._go_client_signatures()[0]
+        return f"""## Mocking
+
+`{self.class_name}API` is the interface of the client's API methods. Code
+taking it instead of `*{self.class_name}Client` can be unit tested with a
+`Mock{self.class_name}Client`, which has a function field per method:
+
+```go
+type Service struct {{
+    API {self.class_name}API
+}}
+
+svc := Service{{API: &Mock{self.class_name}Client{{
+    {name}Func: func(ctx context.Context, ...) {{ ... }},
+}}}}
+```
+
+"""
+    
+    def _generate_go_mock(self, package_name: str) -> str:
+        """The client's API interface and a mock implementing it."""
+        client, api, mock = f"{self.class_name}Client", f"{self.class_name}API", f"Mock{self.class_name}Client"
+        signatures = self._go_client_signatures()
+        width = max(len(name) for _, name, _, _ in signatures) + len('Func')
+        methods, fields, implementations = [], [], []
+        for comment, name, params, results in signatures:
+            methods.append('\n'.join(f"\t{line}" for line in comment.split('\n')))
+            methods.append(f"\t{name}({', '.join(params)}) {results}")
+            fields.append(f"\t{name + 'Func':<{width}} func({', '.join(params)}) {results}")
+            args = ', '.join(param.split(' ')[0] + ('...' if '...' in param else '') for param in params)
+            implementations.append(
+                f"// {name} calls {name}Func.\n"
+                f"func (m *{mock}) {name}({', '.join(params)}) {results} {{\n"
+                f"\tif m.{name}Func == nil {{\n"
+                f"\t\tpanic(\"{mock}.{name} called without {name}Func\")\n"
+                f"\t}}\n"
+                f"\treturn m.{name}Func({args})\n"
+                f"}}\n")
+        methods = '\n'.join(methods)
+        fields = '\n'.join(fields)
+        implementations = '\n'.join(implementations)
+        _, name, params, results = signatures[0]
+        value = results.strip('()').split(', ')[0]
+        if value.startswith('*'):
+            value = f"&{value[1:]}{{}}, nil"
+        elif value.startswith('Result['):
+            value = f"{value}{{}}"
+        else:
+            value = "nil, nil"
+        imports = '\t"context"'
+        if any('json.RawMessage' in results for _, _, _, results in signatures):
+            imports += '\n\t"encoding/json"'
+        if any(' io.' in param for _, _, params, _ in signatures for param in params):
+            imports += '\n\t"io"'
+        return f"""package {package_name}
+
+import (
+{imports}
+)
+
+// {api} is the API methods of {client}. Code calling the API
+// can depend on it rather than on the client, and be tested with a
+// {mock}.
+type {api} interface {{
+{methods}
+}}
+
+var _ {api} = (*{client})(nil)
+
+// {mock} implements {api} with a function field per
+// method, which a test sets for the methods its code calls. Calling a method
+// whose function is nil panics:
+//
+//\tapi := &{mock}{{
+//\t\t{name}Func: func({', '.join(params)}) {results} {{
+//\t\t\treturn {value}
+//\t\t}},
+//\t}}
+type {mock} struct {{
+{fields}
+}}
+
+var _ {api} = (*{mock})(nil)
+
+{implementations}"""
+    
+    def _go_result_method(self, method: str) -> str:
+        """Rewrites a method returning (T, error) to return Result[T], recording
+        the response metadata of its call."""
+        import re
+        # Downloads and their To variants hand back the body, not a decoded value.
+        if any(call in method for call in ('openStream[', 'c.download(', 'io.Copy(w, download)', 'recordEnvelope(')):
+            return method
+        # Requests failing validation return before meta is declared.
+        lines, meta = [], ''
+        for line in method.split('\n'):
+            signature = re.match(r'(func \(c \*\w+\) \w+\(.*\)) \(\*?(.+), error\) \{$', line)
+            if signature:
+                result = f"Result[{signature.group(2)}]"
+                line = f"{signature.group(1)} {result} {{"
+            elif 'c.doRequest(' in line or 'c.doVersions(' in line:
+                lines.append("\tvar meta ResponseMeta")
+                meta = "Meta: meta, "
+            if line.endswith('opts...)'):
+                line = line[:-len('opts...)')] + 'append(opts[:len(opts):len(opts)], recordMeta(&meta))...)'
+            elif re.fullmatch(r'\t+var zero .+', line):
+                continue
+            elif re.fullmatch(r'\t+return (nil|zero), err', line):
+                line = line.split('return')[0] + f"return {result}{{{meta}Err: err}}"
+            elif re.fullmatch(r'\t+return &?result, nil', line):
+                line = line.split('return')[0] + f"return {result}{{Value: result, Meta: meta}}"
+            lines.append(line)
+        return '\n'.join(lines)
+    
+    VERSION_SEGMENT = r'v(\d+)'
+    
+    def _version_groups(self) -> Dict[Tuple[str, str], List[Tuple[int, APIEndpoint]]]:
+        """Operations captured under more than one /vN/ path segment, keyed by
+        method and version-less path, newest version first."""
+        import re
+        groups = {}
+        for endpoint in self.endpoints.values():
+            segments = endpoint.path_pattern.split('/')
+            for i, segment in enumerate(segments):
+                match = re.fullmatch(self.VERSION_SEGMENT, segment)
+                if match:
+                    key = (endpoint.method, '/'.join(segments[:i] + ['{version}'] + segments[i + 1:]))
+                    groups.setdefault(key, []).append((int(match.group(1)), endpoint))
+                    break
+        return {key: sorted(versions, key=lambda v: -v[0])
+                for key, versions in groups.items() if len({v for v, _ in versions}) > 1}
+    
+    def _go_operation_key(self, endpoint: APIEndpoint) -> Tuple[str, str]:
+        """The operation endpoint belongs to: its method and path, with the
+        version segment replaced when it was captured under several."""
+        for key, versions in self._version_groups().items():
+            if any(versioned is endpoint for _, versioned in versions):
+                return key
+        return (endpoint.method, endpoint.path_pattern)
+    
+    def _go_name_candidates(self, method: str, path: str) -> List[str]:
+        """Names for an operation, from the plain one to the most qualified:
+        then one that tells an item from its collection (get_user beside
+        list_users), then ones adding the parent resources (list_user_posts
+        beside list_posts)."""
+        import re
+        literals = [p for p in path.split('/')
+                    if p and not p.startswith('{') and not re.fullmatch(self.VERSION_SEGMENT, p)]
+        candidates = [self._path_to_method_name(method, path)]
+        if not literals:
+            return candidates
+        item = path.rstrip('/').split('/')[-1].startswith('{')
+        verbs = {'GET': 'get' if item or not literals[-1].endswith('s') else 'list', 'POST': 'create',
+                 'PUT': 'update', 'PATCH': 'patch', 'DELETE': 'delete'}
+        verb = verbs.get(method, method.lower())
+        resource = literals[-1] if verb == 'list' else literals[-1].rstrip('s')
+        for depth in range(len(literals)):
+            parents = [parent.rstrip('s') for parent in literals[len(literals) - 1 - depth:-1]]
+            candidates.append('_'.join([verb] + parents + [resource]))
+        return [self._to_snake_case(name).replace('-', '_') for name in candidates]
+    
+    def _go_spec_name(self, endpoint: APIEndpoint, key: Tuple[str, str]) -> str:
+        """The name an OpenAPI spec gave endpoint's operation, as its
+        operationId, made an exported Go identifier and without the version
+        suffix _go_name adds back."""
+        import re
+        name = re.sub(r'[^0-9A-Za-z_]+', '_', endpoint.operation_id).strip('_')
+        if '_' in name:
+            name = self._to_class_name(name)
+        if key in self._version_groups():
+            name = re.sub(r'V\d+$', '', name)
+        if not name or name[0].isdigit():
+            return ''
+        return name[0].upper() + name[1:]
+    
+    def _go_operation_names(self) -> Dict[Tuple[str, str], str]:
+        """The Go method name of every operation, keyed by _go_operation_key.
+        A spec's operationId, or else the names lock, fixes an operation's
+        name. Operations whose names clash take their next candidate until
+        they are told apart; any still clashing are numbered."""
+        operations, spec_names = {}, {}
+        locked = self.locked_names.get('operations', {})
+        for endpoint in self.endpoints.values():
+            key = self._go_operation_key(endpoint)
+            named = self._go_spec_name(endpoint, key) or locked.get(f"{key[0]} {key[1]}", '')
+            if named:
+                operations[key] = [self._to_snake_case(named)]
+                spec_names[key] = named
+            operations.setdefault(key, self._go_name_candidates(*key))
+        chosen = {key: 0 for key in operations}
+        while True:
+            by_name = {}
+            for key, level in chosen.items():
+                by_name.setdefault(operations[key][level], []).append(key)
+            clashing = [keys for keys in by_name.values() if len(keys) > 1]
+            advanced = False
+            for keys in clashing:
+                for key in keys:
+                    if chosen[key] + 1 < len(operations[key]):
+                        chosen[key] += 1
+                        advanced = True
+            if not advanced:
+                break
+        names, taken = {}, set()
+        for key in sorted(chosen, key=lambda key: (key not in spec_names, key)):
+            name = spec_names.get(key) or self._to_class_name(operations[key][chosen[key]])
+            unique, n = name, 2
+            while unique in taken:
+                unique, n = f"{name}{n}", n + 1
+            taken.add(unique)
+            names[key] = unique
+        return names
+    
+    def _go_name(self, endpoint: APIEndpoint) -> str:
+        """The Go method (and struct prefix) for endpoint, suffixed with its
+        version when the operation was captured under several, leaving the
+        plain name to the version facade."""
+        key = self._go_operation_key(endpoint)
+        name = self._go_operation_names()[key]
+        for version, versioned in self._version_groups().get(key, []):
+            if versioned is endpoint:
+                return f"{name}V{version}"
+        return name
+    
+    def _go_struct_names(self) -> Dict[Tuple[int, str], str]:
+        """The Go struct for each endpoint's object request body, 2xx response
+        (or the metadata of its envelope), and streamed event, keyed by the
+        endpoint's id and "Request", "Response", "Meta", or "Event".
+        Schemas that render to the same struct share one name: the one the
+        names lock gives any of them, or else the first one's."""
+        locked = self.locked_names.get('types', {})
+        members, by_shape = [], {}
+        for endpoint in self.endpoints.values():
+            schemas = [("Request", endpoint.request_body_schema)]
+            status = self._go_success_status(endpoint)
+            if self._go_envelope(endpoint):
+                if self._go_envelope_meta(endpoint)['properties']:
+                    schemas.append(("Meta", self._go_envelope_meta(endpoint)))
+            elif status:
+                schemas.append(("Response", endpoint.response_schemas[status]))
+            if endpoint.stream:
+                schemas.append(("Event", endpoint.event_schema))
+            for kind, schema in schemas:
+                shape = self._generate_interface_from_schema('T', schema or {}, 'go')
+                if shape:
+                    members.append((endpoint, kind, shape))
+                    name = locked.get(f"{endpoint.method} {endpoint.path_pattern} {kind}")
+                    if name and shape not in by_shape and name not in by_shape.values():
+                        by_shape[shape] = name
+        names = {}
+        for endpoint, kind, shape in members:
+            if shape not in by_shape:
+                name, number = self._go_name(endpoint) + kind, 1
+                while f"{name}{number if number > 1 else ''}" in by_shape.values():
+                    number += 1
+                by_shape[shape] = f"{name}{number if number > 1 else ''}"
+            names[(id(endpoint), kind)] = by_shape[shape]
+        return names
+    
+    def _go_struct_name(self, endpoint: APIEndpoint, kind: str) -> str:
+        """The Go struct for endpoint's request body or 2xx response; see
+        _go_struct_names."""
+        return self._go_struct_names().get((id(endpoint), kind), self._go_name(endpoint) + kind)
+    
+    def _go_facade_versions(self) -> List[List[Tuple[int, APIEndpoint]]]:
+        """The multi-version operations that get a facade, newest version
+        first. Operations whose versions take different path parameters or
+        different request, response, or query types, or that stream or move
+        files, get none: an older version's body would be decoded into the
+        newest version's types."""
+        groups = []
+        for key, versions in self._version_groups().items():
+            newest = versions[0][1]
+            if any(set(endpoint.path_params) != set(newest.path_params) for _, endpoint in versions[1:]):
+                continue
+            if not all(self._go_json_call(endpoint) for _, endpoint in versions):
+                continue
+            name = self._go_operation_names()[key]
+            # Query parameter structs are named after each version's method.
+            signatures = {(self._generate_go_method(name, endpoint)[1].replace(f"*{self._go_name(endpoint)}Params", "*Params"),
+                           tuple(sorted(self._go_query_params(endpoint).items())))
+                          for _, endpoint in versions}
+            if len(signatures) > 1:
+                continue
+            groups.append(versions)
+        return groups
+    
+    def _generate_go_version_facades(self) -> List[str]:
+        """Methods calling the newest version of a multi-version operation and
+        falling back to older ones on 404 or 410; see _go_facade_versions."""
+        import re
+        facades = []
+        for versions in self._go_facade_versions():
+            newest = versions[0][1]
+            older = [(version, endpoint) for version, endpoint in versions[1:]]
+            name = self._go_operation_names()[self._go_operation_key(newest)]
+            lines = self._generate_go_method(name, newest)
+            lines[0] = (f"// {name} performs {newest.method} {newest.path_pattern}, falling back to\n"
+                        f"// {', then '.join(e.path_pattern for _, e in older)} when a newer version answers 404 or 410.")
+            call = next(i for i, line in enumerate(lines) if 'c.doRequest(' in line)
+            params_arg, body_arg = re.search(r'path, (\w+), (\w+), opts\.\.\.\)', lines[call]).groups()
+            replacement = []
+            for version, endpoint in older:
+                var = f"pathV{version}"
+                if endpoint.path_params:
+                    replacement.append(f"\t{var} := `{endpoint.path_pattern}`")
+                    for param, param_type in self._go_path_params(newest):
+                        replacement.append(f"\t{var} = strings.Replace({var}, \"{{{param}}}\", {self._go_path_segment(param, param_type)}, 1)")
+                else:
+                    replacement.append(f"\t{var} := \"{endpoint.path_pattern}\"")
+            replacement.append(f"\tresponseBody, operation, err := c.doVersions(ctx, \"{newest.method}\", []endpointVersion{{")
+            replacement.append(f"\t\t{{\"v{versions[0][0]}\", \"{newest.path_pattern}\", path}},")
+            for version, endpoint in older:
+                replacement.append(f"\t\t{{\"v{version}\", \"{endpoint.path_pattern}\", pathV{version}}},")
+            replacement.append(f"\t}}, {params_arg}, {body_arg}, opts...)")
+            lines[call:call + 1] = replacement
+            decode = f'c.decode("{newest.method} {newest.path_pattern}", '
+            lines = [line.replace(decode, 'c.decode(operation, ') for line in lines]
+            facades.append('\n'.join(lines))
+        return facades
+    
+    def _go_version_services(self) -> List[Dict[str, Any]]:
+        """The version namespaces of the client, one per /vN/ path segment
+        captured, oldest first: each holds a service per resource, the
+        first literal segment after the version, whose methods call the
+        client's methods for that resource's operations under that version.
+        A method is named after its operation less the resource, List for
+        ListUsers and ListPosts for ListUserPosts, unless that clashes."""
+        import re
+        signatures = {name: (comment, params, results) for comment, name, params, results in self._go_client_signatures()}
+        versions = {}
+        for endpoint in self.endpoints.values():
+            segments = [segment for segment in endpoint.path_pattern.split('/') if segment]
+            index = next((i for i, segment in enumerate(segments) if re.fullmatch(self.VERSION_SEGMENT, segment)), None)
+            if index is None or self._go_name(endpoint) not in signatures:
+                continue
+            segment = next((segment for segment in segments[index + 1:] if not segment.startswith('{')), None)
+            if segment is None:
+                continue
+            version = int(segments[index][1:])
+            versions.setdefault(version, {}).setdefault(segment, []).append(endpoint)
+        services = []
+        for version, resources in sorted(versions.items()):
+            service = {'version': f"v{version}", 'field': f"V{version}", 'type': f"V{version}Service", 'resources': []}
+            for segment, endpoints in sorted(resources.items()):
+                plural, singular = self._go_param_field(segment), self._go_param_field(segment.rstrip('s'))
+                methods = []
+                for endpoint in endpoints:
+                    operation = self._go_operation_names()[self._go_operation_key(endpoint)]
+                    noun = plural if plural in operation else singular
+                    short = operation.replace(noun, '', 1)
+                    if not re.fullmatch(r'[A-Z]\w*', short):
+                        short = operation
+                    methods.append([short, operation, self._go_name(endpoint)])
+                for method in methods:
+                    if sum(other[0] == method[0] for other in methods) > 1:
+                        method[0] = method[1]
+                field = plural if re.fullmatch(r'[A-Z]\w*', plural) else 'R' + plural
+                service['resources'].append({
+                    'segment': segment,
+                    'field': field,
+                    'type': f"V{version}{field}Service",
+                    'methods': [(short, name, *signatures[name]) for short, _, name in methods],
+                })
+            services.append(service)
+        return services
+    
+    def _go_readme_version_services(self) -> str:
+        """The README paragraph on the version namespaces, if any."""
+        services = self._go_version_services()
+        if not services:
+            return ''
+        service, resource = services[-1], services[-1]['resources'][0]
+        short, name = resource['methods'][0][:2]
+        import textwrap
+        fields = [f"`{s['field']}`" for s in services]
+        holders = (f"{', '.join(fields[:-1])} and {fields[-1]} each hold" if len(fields) > 1
+                   else f"{fields[0]} holds")
+        text = (f"The client also groups its methods by version and resource: {holders} "
+                f"a service per resource under that path prefix, so "
+                f"`client.{service['field']}.{resource['field']}.{short}` calls `{name}`. The services "
+                "share the client's transport, credentials, retries, and other configuration, so an "
+                "API serving some resources under `/v2` and others under `/v1` needs only one "
+                "client, and a client from `WithHeader` or `DryRun` gets services of its own.")
+        return '\n' + textwrap.fill(text, 76) + '\n'
+    
+    def _generate_go_services(self, package_name: str, services: List[Dict[str, Any]]) -> str:
+        """Version namespaces grouping the client's methods by resource, as
+        client.V2.Users.List."""
+        types, binds = [], []
+        needs_io = needs_json = False
+        for service in services:
+            resources = service['resources']
+            fields = self._align_go_columns([[r['field'], f"*{r['type']}"] for r in resources])
+            example = resources[0]
+            short, _, _, params, _ = example['methods'][0]
+            args = ', '.join(['ctx'] + ['nil' if param.split(' ')[1].startswith('*') else param.split(' ')[0] for param in params[1:-1]])
+            types.append(f"""// {service['type']} holds the methods of the /{service['version']} API by resource:
+//
+//\tresult, err := client.{service['field']}.{example['field']}.{short}({args})
+type {service['type']} struct {{
+""" + '\n'.join(fields) + "\n}")
+            for resource in resources:
+                types.append(f"// {resource['type']} holds the methods of /{service['version']}/{resource['segment']}.\n"
+                             f"type {resource['type']} struct {{\n\tclient *{self.class_name}Client\n}}")
+                for short, name, comment, params, results in resource['methods']:
+                    needs_io = needs_io or any(' io.' in param for param in params)
+                    needs_json = needs_json or 'json.RawMessage' in results
+                    args = ', '.join(param.split(' ')[0] + ('...' if '...' in param else '') for param in params)
+                    comment = comment.replace(f"// {name} ", f"// {short} ", 1)
+                    types.append(f"{comment}\n"
+                                 f"func (s *{resource['type']}) {short}({', '.join(params)}) {results} {{\n"
+                                 f"\treturn s.client.{name}({args})\n}}")
+            rows = self._align_go_columns([[f"{r['field']}:", f"&{r['type']}{{c}},"] for r in resources])
+            binds.append(f"\tc.{service['field']} = &{service['type']}{{\n" + '\n'.join('\t' + row for row in rows) + "\n\t}")
+        imports = '\t"context"' + ('\n\t"encoding/json"' if needs_json else '') + ('\n\t"io"' if needs_io else '')
+        return f"""package {package_name}
+
+import (
+{imports}
+)
+
+""" + '\n\n'.join(types) + f"""
+
+// bindVersions points the client's version namespaces at c.
+func (c *{self.class_name}Client) bindVersions() {{
+""" + '\n'.join(binds) + "\n}\n"
+    
+    def _go_query_params(self, endpoint: APIEndpoint) -> Dict[str, str]:
+        """The query parameters endpoint's method takes, by name and JSON type:
+        those captured, less any API key, and for a GET answering with a list envelope, the
+        paging parameters that its page, offset, or limit fields echo."""
+        params = dict(endpoint.query_params)
+        mode, credential = self._captured_auth()
+        if mode == 'query':
+            params.pop(credential, None)
+        schema = endpoint.response_schemas.get(200, {})
+        properties = schema.get('properties', {})
+        if endpoint.method == 'GET' and any(prop.get('type') == 'array' for prop in properties.values()):
+            for name in ('page', 'offset') + self.EXPORT_LIMIT_PARAMS:
+                if properties.get(name, {}).get('type') == 'integer':
+                    params.setdefault(name, 'integer')
+        return params
+    
+    def _go_param_field(self, name: str) -> str:
+        """The Params struct field for the query parameter name."""
+        import re
+        field = self._to_class_name(re.sub(r'\W+', '_', name).strip('_')) or 'Param'
+        return field if field[0].isalpha() else 'P' + field
+    
+    def _generate_go_params_struct(self, endpoint: APIEndpoint) -> str:
+        """The struct holding the query parameters of endpoint's method, with
+        the method building its url.Values; empty if it takes none."""
+        query_params = self._go_query_params(endpoint)
+        if not query_params:
+            return ''
+        name = self._go_name(endpoint) + "Params"
+        fields = [[self._go_param_field(param), self._schema_to_type_hint({'type': param_type}, 'go'), f'`url:"{param}"`']
+                  for param, param_type in query_params.items()]
+        lines = [f"// {name} holds the query parameters of {self._go_name(endpoint)}; zero",
+                 "// values are left out.",
+                 f"type {name} struct {{"]
+        lines.extend(self._align_go_columns(fields))
+        lines.extend([
+            "",
+            "\t// Extra holds parameters sent as given, such as zero values or filters",
+            "\t// not seen in the captured traffic.",
+            "\tExtra url.Values",
+            "}",
+            "",
+            f"func (p *{name}) values() url.Values {{",
+            "\tif p == nil {",
+            "\t\treturn nil",
+            "\t}",
+            "\tquery := url.Values{}",
+            "\tfor name, values := range p.Extra {",
+            "\t\tquery[name] = append([]string(nil), values...)",
+            "\t}",
+        ])
+        for (field, go_type, _), param in zip(fields, query_params):
+            zero = {'string': '""', 'bool': 'false', 'interface{}': 'nil'}.get(go_type, '0')
+            if go_type.startswith('[]') or go_type.startswith('map['):
+                zero = 'nil'
+            lines.extend([
+                f"\tif p.{field} != {zero} {{",
+                f"\t\tquery.Set(\"{param}\", fmt.Sprint(p.{field}))",
+                "\t}",
+            ])
+        lines.extend(["\treturn query", "}"])
+        return '\n'.join(lines)
+    
+    def _go_path_params(self, endpoint: APIEndpoint) -> List[Tuple[str, str]]:
+        """endpoint's path parameters in argument order, with their Go types:
+        int64 when every captured value is a decimal integer, else string."""
+        import re
+        from urllib.parse import urlparse
+        pattern = endpoint.path_pattern.strip('/').split('/')
+        values = {}
+        for example in endpoint.examples:
+            segments = urlparse(example.get('request', {}).get('url', '')).path.strip('/').split('/')
+            if len(segments) < len(pattern):
+                continue
+            for name, segment in zip(pattern, segments[len(segments) - len(pattern):]):
+                if name.startswith('{') and name.endswith('}'):
+                    values.setdefault(name[1:-1], []).append(segment)
+        
+        def integer(value):
+            return re.fullmatch(r'0|[1-9][0-9]{0,17}', value) is not None
+        
+        return [(param, 'int64' if values.get(param) and all(integer(v) for v in values[param]) else 'string')
+                for param in sorted(endpoint.path_params)]
+    
+    def _go_placeholder_args(self, endpoint: APIEndpoint) -> List[str]:
+        """Zero arguments for endpoint's method after ctx, for README examples."""
+        args = ['0' if param_type == 'int64' else '""' for _, param_type in self._go_path_params(endpoint)]
+        args += ['nil'] * (bool(self._go_query_params(endpoint)) + bool(endpoint.request_body_schema))
+        args += ['f', '"upload.bin"'] * len(endpoint.upload_files)
+        return args + ['f'] * (endpoint.upload == 'binary')
+    
+    def _go_json_call(self, endpoint: APIEndpoint) -> bool:
+        """Whether endpoint's method sends and returns JSON, rather than
+        streaming events or moving files, so batches, tools, and facades can
+        call it."""
+        return not (endpoint.stream or endpoint.upload or endpoint.download)
+    
+    def _go_upload_files(self, endpoint: APIEndpoint) -> List[Tuple[str, str, str]]:
+        """The file fields of a multipart upload, with the names of the reader
+        and filename parameters passing each: file and filename for a lone
+        one."""
+        import re
+        if len(endpoint.upload_files) == 1:
+            return [(endpoint.upload_files[0], "file", "filename")]
+        names = [self._to_camel_case(re.sub(r'\W+', '_', field).strip('_').lower() or 'file') for field in endpoint.upload_files]
+        return [(field, name, name + "Filename") for field, name in zip(endpoint.upload_files, names)]
+    
+    def _go_upload_content_type(self, endpoint: APIEndpoint) -> str:
+        """The media type a raw upload was captured with."""
+        return next((media_type for media_type in (self._content_type(example.get('request', {}))
+                                                   for example in endpoint.examples) if media_type),
+                    'application/octet-stream')
+    
+    def _go_path_segment(self, param: str, param_type: str) -> str:
+        """The Go expression putting a path parameter into the path."""
+        param_go = self._to_camel_case(param)
+        if param_type == 'int64':
+            return f"strconv.FormatInt({param_go}, 10)"
+        return f"pathSegment({param_go})"
+    
+    def _go_uses_int_path_params(self) -> bool:
+        return any(param_type == 'int64' for endpoint in self.endpoints.values()
+                   for _, param_type in self._go_path_params(endpoint))
+    
     def _generate_go_method(self, method_name: str, endpoint: APIEndpoint) -> List[str]:
         lines = []
         
-        params = []
+        params = ["ctx context.Context"]
         path_replacements = []
         
-        for param in sorted(endpoint.path_params):
+        for param, param_type in self._go_path_params(endpoint):
             param_go = self._to_camel_case(param)
-            params.append(f"{param_go} string")
-            path_replacements.append((f"{{{param}}}", param_go))
+            params.append(f"{param_go} {param_type}")
+            path_replacements.append((f"{{{param}}}", self._go_path_segment(param, param_type)))
         
-        if endpoint.query_params:
-            for param, param_type in endpoint.query_params.items():
-                param_go = self._to_camel_case(param)
-                type_hint = self._schema_to_type_hint({'type': param_type}, 'go')
-                params.append(f"{param_go} *{type_hint}")
+        query_params = self._go_query_params(endpoint)
+        if query_params:
+            params.append(f"params *{self._go_name(endpoint)}Params")
         
         if endpoint.request_body_schema:
             body_type = self._schema_to_type_hint(endpoint.request_body_schema, 'go')
             if endpoint.request_body_schema.get('type') == 'object' and endpoint.request_body_schema.get('properties'):
-                struct_name = self._to_class_name(self._path_to_method_name(endpoint.method, endpoint.path_pattern)) + "Request"
+                struct_name = self._go_struct_name(endpoint, "Request")
                 params.append(f"data *{struct_name}")
             else:
                 params.append(f"data {body_type}")
+        # Encrypted (JWE) request bodies leave no schema to infer a type from.
+        encrypted_body = not endpoint.request_body_schema and any(
+            self._content_type(example.get('request', {})).startswith(self.JOSE_CONTENT_TYPES)
+            for example in endpoint.examples)
+        if encrypted_body:
+            params.append("data interface{}")
+        for _, reader, filename in self._go_upload_files(endpoint):
+            params.append(f"{reader} io.Reader, {filename} string")
+        if endpoint.upload == 'binary':
+            params.append("body io.Reader")
         
-        response_type = "map[string]interface{}"
-        if 200 in endpoint.response_schemas:
-            if endpoint.response_schemas[200].get('type') == 'object' and endpoint.response_schemas[200].get('properties'):
-                response_type = "*" + self._to_class_name(self._path_to_method_name(endpoint.method, endpoint.path_pattern)) + "Response"
+        # Without a captured body there is nothing to type, so the body is
+        # returned as it came.
+        response_type = "json.RawMessage"
+        status = self._go_success_status(endpoint)
+        envelope = self._go_envelope(endpoint)
+        if envelope:
+            payload = endpoint.response_schemas[status]['properties'][envelope]
+            response_type = self._schema_to_type_hint(payload, 'go')
+            if payload.get('type') == 'object' and payload.get('properties'):
+                response_type = "*" + response_type.lstrip('*')
+        elif status:
+            if endpoint.response_schemas[status].get('type') == 'object' and endpoint.response_schemas[status].get('properties'):
+                response_type = "*" + self._go_struct_name(endpoint, "Response")
             else:
-                response_type = self._schema_to_type_hint(endpoint.response_schemas[200], 'go')
+                response_type = self._schema_to_type_hint(endpoint.response_schemas[status], 'go')
         
-        param_str = ', '.join(params) if params else ''
+        params.append("opts ...RequestOption")
+        param_str = ', '.join(params)
         
         lines.append(f"// {method_name} performs {endpoint.method} {endpoint.path_pattern}")
         lines.append(f"func (c *{self.class_name}Client) {method_name}({param_str}) ({response_type}, error) {{")
@@ -207,28 +2026,40 @@ func (c *{self.class_name}Client) doRequest(method, path string, params url.Valu
         else:
             lines.append(f"\tpath := \"{path}\"")
         
-        if endpoint.query_params:
-            lines.append(f"\t")
-            lines.append(f"\tparams := url.Values{{}}")
-            for param, _ in endpoint.query_params.items():
-                param_go = self._to_camel_case(param)
-                lines.append(f"\tif {param_go} != nil {{")
-                lines.append(f"\t\tparams.Set(\"{param}\", fmt.Sprintf(\"%v\", *{param_go}))")
-                lines.append(f"\t}}")
+        if query_params:
+            lines.append(f"\tquery := params.values()")
+        if any(param.startswith('data *') for param in params):
+            lines.append(f"\tif err := c.validate(data); err != nil {{")
+            if endpoint.stream or endpoint.download or response_type.startswith(('*', '[]', 'map[', 'interface{')):
+                lines.append(f"\t\treturn nil, err")
+            else:
+                lines.append(f"\t\tvar zero {response_type}")
+                lines.append(f"\t\treturn zero, err")
+            lines.append(f"\t}}")
         
         lines.append(f"\t")
         
-        if endpoint.query_params:
-            params_arg = "params"
+        if query_params:
+            params_arg = "query"
         else:
             params_arg = "nil"
         
-        if endpoint.request_body_schema:
+        if endpoint.request_body_schema or encrypted_body:
             body_arg = "data"
         else:
             body_arg = "nil"
+        if endpoint.upload == 'multipart':
+            files = ''.join(f", formFile{{\"{field}\", {filename}, {reader}}}" for field, reader, filename in self._go_upload_files(endpoint))
+            body_arg = f"multipartBody({body_arg}{files})"
+        elif endpoint.upload == 'binary':
+            body_arg = f"binaryBody(\"{self._go_upload_content_type(endpoint)}\", body)"
+        
+        if endpoint.stream:
+            return self._go_stream_method(lines, endpoint, params_arg, body_arg)
+        if endpoint.download:
+            return self._go_download_methods(lines, endpoint, params_arg, body_arg)
         
-        lines.append(f"\tresponseBody, err := c.doRequest(\"{endpoint.method}\", path, {params_arg}, {body_arg})")
+        lines.append(f"\tresponseBody, err := c.doRequest(ctx, \"{endpoint.method}\", \"{endpoint.path_pattern}\", path, {params_arg}, {body_arg}, opts...)")
         lines.append(f"\tif err != nil {{")
         lines.append(f"\t\treturn nil, err")
         lines.append(f"\t}}")
@@ -236,96 +2067,19411 @@ func (c *{self.class_name}Client) doRequest(method, path string, params url.Valu
         
         if response_type.startswith("*"):
             lines.append(f"\tvar result {response_type[1:]}")
-            lines.append(f"\tif err := json.Unmarshal(responseBody, &result); err != nil {{")
+            lines.append(f"\tif err := c.decode(\"{endpoint.method} {endpoint.path_pattern}\", responseBody, &result); err != nil {{")
             lines.append(f"\t\treturn nil, err")
             lines.append(f"\t}}")
             lines.append(f"\treturn &result, nil")
-        elif response_type == "map[string]interface{}":
+        elif response_type == "json.RawMessage":
             lines.append(f"\tvar result {response_type}")
-            lines.append(f"\tif err := json.Unmarshal(responseBody, &result); err != nil {{")
+            lines.append(f"\tif err := c.decode(\"{endpoint.method} {endpoint.path_pattern}\", responseBody, &result); err != nil {{")
             lines.append(f"\t\treturn nil, err")
             lines.append(f"\t}}")
             lines.append(f"\treturn result, nil")
         else:
             lines.append(f"\tvar result {response_type}")
-            lines.append(f"\tif err := json.Unmarshal(responseBody, &result); err != nil {{")
-            lines.append(f"\t\tvar zero {response_type}")
-            lines.append(f"\t\treturn zero, err")
+            lines.append(f"\tif err := c.decode(\"{endpoint.method} {endpoint.path_pattern}\", responseBody, &result); err != nil {{")
+            if response_type.startswith(('[]', 'interface{')):
+                lines.append(f"\t\treturn nil, err")
+            else:
+                lines.append(f"\t\tvar zero {response_type}")
+                lines.append(f"\t\treturn zero, err")
             lines.append(f"\t}}")
             lines.append(f"\treturn result, nil")
         
         lines.append(f"}}")
         
+        if envelope and self._go_envelope_meta(endpoint)['properties']:
+            lines.extend(self._go_envelope_meta_method(method_name, endpoint, params, response_type))
         return lines
     
-    def _generate_go_mod(self, output_dir: str, package_name: str):
-        go_mod = f"""module github.com/example/{package_name}
-
-go 1.20
-"""
+    def _go_envelope_meta_method(self, method_name: str, endpoint: APIEndpoint, params: List[str],
+                                 response_type: str) -> List[str]:
+        """A WithMeta variant of the method for an enveloped response,
+        returning the envelope's other fields beside the payload."""
+        meta = self._go_struct_name(endpoint, "Meta")
+        args = ', '.join(param.split(' ')[0] for param in params[:-1])
+        call = f"c.{method_name}({args}, append(opts[:len(opts):len(opts)], recordEnvelope(&meta))...)"
+        # Result[T] holds the payload by value.
+        value = self.result_types and response_type.startswith('*')
+        zero = 'nil' if response_type.startswith(('*', '[]', 'map[', 'interface{')) else 'zero'
+        return [
+            "",
+            f"// {method_name}WithMeta performs {endpoint.method} {endpoint.path_pattern} like {method_name}, also",
+            f"// returning the fields of the response envelope besides the payload",
+            f"func (c *{self.class_name}Client) {method_name}WithMeta({', '.join(params)}) ({response_type}, *{meta}, error) {{",
+            f"\tvar meta {meta}",
+            f"\tresult, err := {call}" + (".Get()" if self.result_types else ""),
+            "\tif err != nil {",
+        ] + ([f"\t\tvar zero {response_type}"] if zero == 'zero' else []) + [
+            f"\t\treturn {zero}, nil, err",
+            "\t}",
+            f"\treturn {'&' if value else ''}result, &meta, nil",
+            "}",
+        ]
+    
+    def _go_event_type(self, endpoint: APIEndpoint) -> str:
+        """The Go type each event of a streaming endpoint decodes to."""
+        schema = endpoint.event_schema
+        if schema.get('type') == 'object' and schema.get('properties'):
+            return self._go_struct_name(endpoint, "Event")
+        if schema:
+            return self._schema_to_type_hint(schema, 'go')
+        return "map[string]interface{}"
+    
+    def _go_stream_method(self, lines: List[str], endpoint: APIEndpoint, params_arg: str, body_arg: str) -> List[str]:
+        """Finishes a method for a streaming endpoint, returning an EventStream
+        in place of the decoded body."""
+        import re
+        event_type = self._go_event_type(endpoint)
+        lines[0] += ", returning its events as they arrive"
+        lines[1] = re.sub(r'\) \(.+, error\) \{$', f") (*EventStream[{event_type}], error) {{", lines[1])
+        lines.append(f"\treturn openStream[{event_type}](ctx, c, \"{endpoint.method}\", \"{endpoint.path_pattern}\", path, {params_arg}, {body_arg}, opts)")
+        lines.append("}")
+        return lines
+    
+    def _go_download_methods(self, lines: List[str], endpoint: APIEndpoint, params_arg: str, body_arg: str) -> List[str]:
+        """Finishes a method for an endpoint returning files, handing over the
+        unread body as a Download, followed by a To variant copying it to an
+        io.Writer."""
+        import re
+        name = re.match(r'func \(c \*\w+\) (\w+)\(', lines[1]).group(1)
+        params = re.match(r'func \(c \*\w+\) \w+\((.*)\) \(', lines[1]).group(1).split(', ')
+        args = ', '.join(param.split(' ')[0] + ('...' if '...' in param else '') for param in params)
+        lines[0] += ", returning the body unread"
+        lines[1] = re.sub(r'\) \(.+, error\) \{$', ") (*Download, error) {", lines[1])
+        lines.append(f"\treturn c.download(ctx, \"{endpoint.method}\", \"{endpoint.path_pattern}\", path, {params_arg}, {body_arg}, opts)")
+        lines.append("}")
+        lines.append("")
+        lines.append(f"// {name}To performs {endpoint.method} {endpoint.path_pattern}, copying the body to w")
+        lines.append(f"func (c *{self.class_name}Client) {name}To({', '.join(params[:-1] + ['w io.Writer', params[-1]])}) (int64, error) {{")
+        lines.append(f"\tdownload, err := c.{name}({args})")
+        lines.append(f"\tif err != nil {{")
+        lines.append(f"\t\treturn 0, err")
+        lines.append(f"\t}}")
+        lines.append(f"\tdefer download.Close()")
+        lines.append(f"\treturn io.Copy(w, download)")
+        lines.append("}")
+        return lines
+    
+    def _generate_go_grpc_types(self, package_name: str) -> str:
+        structs = []
+        for grpc_method in self.grpc_parser.methods.values():
+            for message in (grpc_method.request, grpc_method.response):
+                structs.extend(self._generate_go_grpc_struct(grpc_method.service, grpc_method.path, message))
         
-        with open(f"{output_dir}/go.mod", 'w') as f:
-            f.write(go_mod)
+        content = f"package {package_name}\n\n"
+        content += "// Types below were recovered from protobuf wire data without server\n"
+        content += "// reflection. Field names are placeholders and each field notes how\n"
+        content += "// confident the inference is; see inferred.proto for the matching schema.\n\n"
+        content += '\n\n'.join(structs) + '\n'
+        return content
     
-    def _generate_readme(self, output_dir: str):
-        package_name = self._to_snake_case(self.api_name).replace('-', '_')
+    def _generate_go_grpc_struct(self, service: str, path: str, message: InferredMessage) -> List[str]:
+        wire_types = {
+            'int64': 'varint', 'bool': 'varint', 'double': 'fixed64', 'fixed64': 'fixed64',
+            'float': 'fixed32', 'fixed32': 'fixed32', 'string': 'bytes', 'bytes': 'bytes', 'message': 'bytes'
+        }
+        go_types = {
+            'int64': 'int64', 'bool': 'bool', 'double': 'float64', 'fixed64': 'uint64',
+            'float': 'float32', 'fixed32': 'uint32', 'string': 'string', 'bytes': '[]byte'
+        }
         
-        readme = f"""# {self.class_name} Go SDK
+        struct_name = f"{service}{message.name}"
+        lines = [
+            f"// {struct_name} was inferred from {message.samples} message(s) on {path}.",
+            f"type {struct_name} struct {{"
+        ]
+        rows = []
+        nested = []
+        for number in sorted(message.fields):
+            inferred = message.fields[number]
+            if inferred.kind == 'message' and inferred.message:
+                go_type = f"*{service}{inferred.message.name}"
+                nested.extend(self._generate_go_grpc_struct(service, path, inferred.message))
+            else:
+                go_type = go_types.get(inferred.kind, '[]byte')
+            if inferred.repeated:
+                go_type = f"[]{go_type}"
+            cardinality = 'rep' if inferred.repeated else 'opt'
+            tag = f'`protobuf:"{wire_types[inferred.kind]},{number},{cardinality}" json:"field_{number},omitempty"`'
+            rows.append([f"Field{number}", go_type, tag, f"// confidence: {inferred.confidence:.2f}"])
+        lines.extend(self._align_go_columns(rows))
+        lines.append("}")
+        return ['\n'.join(lines)] + nested
+    
+    def _align_go_columns(self, rows: List[List[str]], indent: str = '\t') -> List[str]:
+        widths = [max(len(row[i]) for row in rows) for i in range(len(rows[0]))] if rows else []
+        lines = []
+        for row in rows:
+            cells = [cell.ljust(widths[i]) for i, cell in enumerate(row[:-1])] + [row[-1]]
+            lines.append(indent + ' '.join(cells).rstrip())
+        return lines
+    
+    def _generate_go_capture_store(self) -> str:
+        return """// Package capturestore reads capture stores written by the API reverse
+// engineering tool (cli.py --capture-store) so Go code can query recorded
+// samples per endpoint without loading every body up front.
+package capturestore
 
-Auto-generated Go SDK for {self.api_name} API.
+import (
+\t"bufio"
+\t"compress/gzip"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"os"
+\t"path/filepath"
+\t"sort"
+\t"sync"
+)
 
-## Installation
+// Decoder wraps a compressed blob reader with a decompressing reader.
+type Decoder func(r io.Reader) (io.ReadCloser, error)
 
-```bash
-go get github.com/example/{package_name}
-```
+var (
+\tdecodersMu sync.RWMutex
+\tdecoders   = map[string]Decoder{
+\t\t"gzip": func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+\t}
+)
 
-## Usage
+// RegisterDecoder installs a decoder for a blob codec. gzip is built in;
+// stores written with zstd need one registered, for example backed by
+// github.com/klauspost/compress/zstd.
+func RegisterDecoder(codec string, decoder Decoder) {
+\tdecodersMu.Lock()
+\tdefer decodersMu.Unlock()
+\tdecoders[codec] = decoder
+}
 
-```go
-package main
+// Message is the stored half of an exchange. Body holds the sha256 digest
+// of the body blob, not the body itself; use Store.Body to read it.
+type Message struct {
+\tHeaders  map[string]string `json:"headers,omitempty"`
+\tEncoding string            `json:"encoding,omitempty"`
+\tBody     string            `json:"body,omitempty"`
+}
+
+// Record is one distinct captured exchange. Count is how many identical
+// exchanges were collapsed into it.
+type Record struct {
+\tID        string  `json:"id"`
+\tEndpoint  string  `json:"endpoint"`
+\tMethod    string  `json:"method"`
+\tURL       string  `json:"url"`
+\tStatus    int     `json:"status"`
+\tStartedAt string  `json:"started_at,omitempty"`
+\tCount     int     `json:"count"`
+\tRequest   Message `json:"request"`
+\tResponse  Message `json:"response"`
+}
+
+// Query filters records. Zero-valued fields match everything.
+type Query struct {
+\tEndpoint string
+\tMethod   string
+\tStatus   int
+\tLimit    int
+}
+
+// Store is a read-only view of a capture store directory.
+type Store struct {
+\troot       string
+\tcodec      string
+\trecords    []Record
+\tbyEndpoint map[string][]int
+}
+
+// Open loads the index of the capture store at root.
+func Open(root string) (*Store, error) {
+\tmetaFile, err := os.ReadFile(filepath.Join(root, "store.json"))
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tvar meta struct {
+\t\tVersion int    `json:"version"`
+\t\tCodec   string `json:"codec"`
+\t}
+\tif err := json.Unmarshal(metaFile, &meta); err != nil {
+\t\treturn nil, fmt.Errorf("capturestore: invalid store.json: %w", err)
+\t}
+
+\ts := &Store{root: root, codec: meta.Codec, byEndpoint: make(map[string][]int)}
+
+\tindex, err := os.Open(filepath.Join(root, "index.jsonl"))
+\tif os.IsNotExist(err) {
+\t\treturn s, nil
+\t}
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tdefer index.Close()
+
+\tscanner := bufio.NewScanner(index)
+\tscanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+\tfor scanner.Scan() {
+\t\tif len(scanner.Bytes()) == 0 {
+\t\t\tcontinue
+\t\t}
+\t\tvar record Record
+\t\tif err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+\t\t\treturn nil, fmt.Errorf("capturestore: invalid index record: %w", err)
+\t\t}
+\t\ts.byEndpoint[record.Endpoint] = append(s.byEndpoint[record.Endpoint], len(s.records))
+\t\ts.records = append(s.records, record)
+\t}
+\treturn s, scanner.Err()
+}
+
+// Codec reports the compression codec used for blobs.
+func (s *Store) Codec() string {
+\treturn s.codec
+}
+
+// Endpoints lists the endpoint labels ("GET /v1/users/{id}") in the store.
+func (s *Store) Endpoints() []string {
+\tendpoints := make([]string, 0, len(s.byEndpoint))
+\tfor endpoint := range s.byEndpoint {
+\t\tendpoints = append(endpoints, endpoint)
+\t}
+\tsort.Strings(endpoints)
+\treturn endpoints
+}
+
+// Samples returns every record captured for endpoint.
+func (s *Store) Samples(endpoint string) []Record {
+\treturn s.Query(Query{Endpoint: endpoint})
+}
+
+// Query returns the records matching q in capture order.
+func (s *Store) Query(q Query) []Record {
+\tcandidates := s.records
+\tif q.Endpoint != "" {
+\t\tindexes := s.byEndpoint[q.Endpoint]
+\t\tcandidates = make([]Record, len(indexes))
+\t\tfor i, idx := range indexes {
+\t\t\tcandidates[i] = s.records[idx]
+\t\t}
+\t}
+
+\tvar matched []Record
+\tfor _, record := range candidates {
+\t\tif q.Method != "" && record.Method != q.Method {
+\t\t\tcontinue
+\t\t}
+\t\tif q.Status != 0 && record.Status != q.Status {
+\t\t\tcontinue
+\t\t}
+\t\tmatched = append(matched, record)
+\t\tif q.Limit > 0 && len(matched) == q.Limit {
+\t\t\tbreak
+\t\t}
+\t}
+\treturn matched
+}
+
+// Body reads and decompresses the blob with the given digest. An empty
+// digest yields a nil body.
+func (s *Store) Body(digest string) ([]byte, error) {
+\tif digest == "" {
+\t\treturn nil, nil
+\t}
+\tdecodersMu.RLock()
+\tdecoder, ok := decoders[s.codec]
+\tdecodersMu.RUnlock()
+\tif !ok {
+\t\treturn nil, fmt.Errorf("capturestore: no decoder registered for codec %q", s.codec)
+\t}
+\tif len(digest) < 2 {
+\t\treturn nil, fmt.Errorf("capturestore: invalid digest %q", digest)
+\t}
+
+\tf, err := os.Open(filepath.Join(s.root, "blobs", digest[:2], digest))
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tdefer f.Close()
+
+\tr, err := decoder(f)
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tdefer r.Close()
+\treturn io.ReadAll(r)
+}
+
+// DecodeResponse unmarshals the response body of record into v.
+func (s *Store) DecodeResponse(record Record, v interface{}) error {
+\tbody, err := s.Body(record.Response.Body)
+\tif err != nil {
+\t\treturn err
+\t}
+\treturn json.Unmarshal(body, v)
+}
+"""
+    
+    def _generate_go_alert(self) -> str:
+        return """// Package alert delivers schema-violation alerts raised by the drift
+// monitor (and any other validator) to pluggable sinks: generic webhooks,
+// Slack incoming webhooks, and a Prometheus metric that alert rules can
+// fire on.
+package alert
 
 import (
-    "fmt"
-    "log"
-    "{package_name}"
+\t"bytes"
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"sort"
+\t"strings"
+\t"sync"
+\t"time"
 )
 
-func main() {{
-    // Initialize the client
-    client := {package_name}.New{self.class_name}Client("{self.base_url}")
-    
-    // Set authentication if needed
-    client.SetAuthToken("your-token-here")
-    
-    // Make API calls
+// Alert describes one schema violation.
+type Alert struct {
+\tSource   string    `json:"source"`
+\tAPI      string    `json:"api,omitempty"`
+\tKind     string    `json:"kind"`
+\tEndpoint string    `json:"endpoint"`
+\tField    string    `json:"field,omitempty"`
+\tExpected string    `json:"expected,omitempty"`
+\tObserved string    `json:"observed,omitempty"`
+\tSample   string    `json:"sample,omitempty"`
+\tTime     time.Time `json:"time"`
+}
+
+// Summary renders the alert as a single line.
+func (a Alert) Summary() string {
+\tlocation := strings.TrimSpace(a.Endpoint + " " + a.Field)
+\tsummary := fmt.Sprintf("[%s] %s: %s", a.Source, a.Kind, location)
+\tif a.API != "" {
+\t\tsummary = fmt.Sprintf("[%s] %s %s: %s", a.Source, a.API, a.Kind, location)
+\t}
+\tif a.Expected != "" || a.Observed != "" {
+\t\tsummary += fmt.Sprintf(" (expected %s, observed %s)", orNone(a.Expected), orNone(a.Observed))
+\t}
+\treturn summary
+}
+
+func orNone(s string) string {
+\tif s == "" {
+\t\treturn "none"
+\t}
+\treturn s
+}
+
+// Sink receives alerts.
+type Sink interface {
+\tSend(ctx context.Context, a Alert) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, a Alert) error
+
+// Send calls f.
+func (f SinkFunc) Send(ctx context.Context, a Alert) error { return f(ctx, a) }
+
+// Multi fans an alert out to every sink and joins their errors.
+func Multi(sinks ...Sink) Sink {
+\treturn SinkFunc(func(ctx context.Context, a Alert) error {
+\t\tvar errs []error
+\t\tfor _, sink := range sinks {
+\t\t\tif err := sink.Send(ctx, a); err != nil {
+\t\t\t\terrs = append(errs, err)
+\t\t\t}
+\t\t}
+\t\treturn errors.Join(errs...)
+\t})
+}
+
+// Webhook POSTs each alert as JSON to URL.
+type Webhook struct {
+\tURL     string
+\tHeaders map[string]string
+\tClient  *http.Client
+}
+
+// Send implements Sink.
+func (w *Webhook) Send(ctx context.Context, a Alert) error {
+\treturn postJSON(ctx, w.Client, w.URL, w.Headers, a)
+}
+
+// Slack posts alerts to a Slack incoming webhook.
+type Slack struct {
+\tWebhookURL string
+\t// Channel overrides the webhook's default channel when set.
+\tChannel string
+\tClient  *http.Client
+}
+
+// Send implements Sink.
+func (s *Slack) Send(ctx context.Context, a Alert) error {
+\ttext := fmt.Sprintf(":warning: *Schema violation* %s", a.Summary())
+\tif a.Sample != "" {
+\t\ttext += fmt.Sprintf("\\n```%s```", a.Sample)
+\t}
+\tpayload := map[string]string{"text": text}
+\tif s.Channel != "" {
+\t\tpayload["channel"] = s.Channel
+\t}
+\treturn postJSON(ctx, s.Client, s.WebhookURL, nil, payload)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, v interface{}) error {
+\tbody, err := json.Marshal(v)
+\tif err != nil {
+\t\treturn err
+\t}
+\treq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+\tif err != nil {
+\t\treturn err
+\t}
+\treq.Header.Set("Content-Type", "application/json")
+\tfor key, value := range headers {
+\t\treq.Header.Set(key, value)
+\t}
+\tif client == nil {
+\t\tclient = http.DefaultClient
+\t}
+\tresp, err := client.Do(req)
+\tif err != nil {
+\t\treturn err
+\t}
+\tdefer resp.Body.Close()
+\tio.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+\tif resp.StatusCode >= 300 {
+\t\treturn fmt.Errorf("alert: %s responded with status %d", url, resp.StatusCode)
+\t}
+\treturn nil
+}
+
+type seriesKey struct {
+\tsource, api, kind, endpoint, field string
+}
+
+type series struct {
+\tcount    float64
+\tlastSeen time.Time
+}
+
+// Prometheus counts alerts and serves them in the Prometheus text format,
+// so alert rules such as
+//
+//\tincrease(example_schema_violations_total[15m]) > 0
+//
+// can page on upstream API changes. Mount it on a metrics endpoint.
+type Prometheus struct {
+\tnamespace string
+\tmu        sync.Mutex
+\tseries    map[seriesKey]*series
+}
+
+// NewPrometheus creates a Prometheus sink whose metric names start with
+// namespace.
+func NewPrometheus(namespace string) *Prometheus {
+\treturn &Prometheus{namespace: namespace, series: make(map[seriesKey]*series)}
+}
+
+// Send implements Sink.
+func (p *Prometheus) Send(_ context.Context, a Alert) error {
+\tkey := seriesKey{a.Source, a.API, a.Kind, a.Endpoint, a.Field}
+\tp.mu.Lock()
+\tdefer p.mu.Unlock()
+\ts, ok := p.series[key]
+\tif !ok {
+\t\ts = &series{}
+\t\tp.series[key] = s
+\t}
+\ts.count++
+\ts.lastSeen = a.Time
+\treturn nil
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+\tw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+\tp.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+\tp.mu.Lock()
+\tkeys := make([]seriesKey, 0, len(p.series))
+\tvalues := make(map[seriesKey]series, len(p.series))
+\tfor key, s := range p.series {
+\t\tkeys = append(keys, key)
+\t\tvalues[key] = *s
+\t}
+\tp.mu.Unlock()
+\tsort.Slice(keys, func(i, j int) bool { return labels(keys[i]) < labels(keys[j]) })
+
+\tvar buf bytes.Buffer
+\tname := p.metricName("schema_violations_total")
+\tfmt.Fprintf(&buf, "# HELP %s Distinct schema violations alerted on.\\n# TYPE %s counter\\n", name, name)
+\tfor _, key := range keys {
+\t\tfmt.Fprintf(&buf, "%s{%s} %g\\n", name, labels(key), values[key].count)
+\t}
+\tname = p.metricName("schema_violation_last_seen_timestamp_seconds")
+\tfmt.Fprintf(&buf, "# HELP %s Unix time a schema violation was last observed.\\n# TYPE %s gauge\\n", name, name)
+\tfor _, key := range keys {
+\t\tfmt.Fprintf(&buf, "%s{%s} %d\\n", name, labels(key), values[key].lastSeen.Unix())
+\t}
+\treturn buf.WriteTo(w)
+}
+
+func (p *Prometheus) metricName(name string) string {
+\tif p.namespace == "" {
+\t\treturn name
+\t}
+\treturn p.namespace + "_" + name
+}
+
+var labelEscaper = strings.NewReplacer(`\\`, `\\\\`, `"`, `\\"`, "\\n", `\\n`)
+
+func labels(key seriesKey) string {
+\tpairs := [][2]string{{"source", key.source}, {"api", key.api}, {"kind", key.kind}, {"endpoint", key.endpoint}, {"field", key.field}}
+\tparts := make([]string, 0, len(pairs))
+\tfor _, pair := range pairs {
+\t\tparts = append(parts, fmt.Sprintf(`%s="%s"`, pair[0], labelEscaper.Replace(pair[1])))
+\t}
+\treturn strings.Join(parts, ",")
+}
 """
-        
-        example_method = None
-        for endpoint_key, endpoint in self.endpoints.items():
-            if endpoint.method == 'GET' and not endpoint.path_params:
-                method_name = self._path_to_method_name(endpoint.method, endpoint.path_pattern)
-                method_name_go = self._to_class_name(method_name)
-                readme += f"    response, err := client.{method_name_go}()\n"
-                readme += f"    if err != nil {{\n"
-                readme += f"        log.Fatal(err)\n"
-                readme += f"    }}\n"
-                readme += f"    fmt.Printf(\"%+v\\n\", response)\n"
-                break
-        
-        readme += """}}
-```
+    
+    def _generate_go_drift(self, module_path: str) -> str:
+        return f"""// Package drift watches live API responses for divergence from a schema
+// published to the registry by the API reverse engineering tool
+// (cli.py --registry). Wrap the client's transport with a Monitor to
+// validate a sample of responses and accumulate findings over time.
+package drift
 
-## Available Methods
+import (
+\t"bytes"
+\t"context"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"math/rand"
+\t"net/http"
+\t"net/url"
+\t"os"
+\t"path/filepath"
+\t"regexp"
+\t"sort"
+\t"strconv"
+\t"strings"
+\t"sync"
+\t"time"
+
+\t"{module_path}/alert"
+)
+
+// Schema is a JSON-schema-like node as inferred by the tool.
+type Schema struct {{
+\tType       string             `json:"type"`
+\tFormat     string             `json:"format,omitempty"`
+\tNullable   bool               `json:"nullable,omitempty"`
+\tProperties map[string]*Schema `json:"properties,omitempty"`
+\tItems      *Schema            `json:"items,omitempty"`
+}}
+
+// Endpoint is the registered shape of one operation.
+type Endpoint struct {{
+\tMethod          string             `json:"method"`
+\tPath            string             `json:"path"`
+\tPathParams      []string           `json:"path_params"`
+\tRequestSchema   *Schema            `json:"request_body_schema"`
+\tResponseSchemas map[string]*Schema `json:"response_schemas"`
+}}
+
+// Snapshot is one version of an API's schema in the registry.
+type Snapshot struct {{
+\tAPIName        string               `json:"api_name"`
+\tBaseURL        string               `json:"base_url"`
+\tVersion        int                  `json:"version"`
+\tCreatedAt      string               `json:"created_at"`
+\tFingerprint    string               `json:"fingerprint"`
+\tSourceCaptures []string             `json:"source_captures"`
+\tEndpoints      map[string]*Endpoint `json:"endpoints"`
+}}
+
+// LoadSnapshot reads a registry snapshot file.
+func LoadSnapshot(path string) (*Snapshot, error) {{
+\tdata, err := os.ReadFile(path)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar snapshot Snapshot
+\tif err := json.Unmarshal(data, &snapshot); err != nil {{
+\t\treturn nil, fmt.Errorf("drift: invalid snapshot %s: %w", path, err)
+\t}}
+\treturn &snapshot, nil
+}}
+
+var unsafeNameChars = regexp.MustCompile(`[^0-9A-Za-z_.-]+`)
+
+// LoadLatest reads the newest version of apiName from a filesystem
+// registry rooted at dir.
+func LoadLatest(dir, apiName string) (*Snapshot, error) {{
+\tapiDir := filepath.Join(dir, unsafeNameChars.ReplaceAllString(apiName, "_"))
+\tmatches, err := filepath.Glob(filepath.Join(apiDir, "v*.json"))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tlatest, latestPath := 0, ""
+\tfor _, match := range matches {{
+\t\tversion, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), "v"), ".json"))
+\t\tif err == nil && version > latest {{
+\t\t\tlatest, latestPath = version, match
+\t\t}}
+\t}}
+\tif latestPath == "" {{
+\t\treturn nil, fmt.Errorf("drift: no schema versions for %q in %s", apiName, dir)
+\t}}
+\treturn LoadSnapshot(latestPath)
+}}
+
+// Kind classifies a finding.
+type Kind string
+
+const (
+\t// FieldAdded is a response field absent from the registered schema.
+\tFieldAdded Kind = "field_added"
+\t// FieldMissing is a required field that was absent from a response.
+\tFieldMissing Kind = "field_missing"
+\t// TypeChanged is a value whose JSON type differs from the schema.
+\tTypeChanged Kind = "type_changed"
+\t// EndpointVanished is a registered endpoint answering 404 or 410.
+\tEndpointVanished Kind = "endpoint_vanished"
+\t// UnknownEndpoint is a response for a route not in the schema.
+\tUnknownEndpoint Kind = "unknown_endpoint"
+)
+
+// Finding is one distinct divergence, aggregated across occurrences.
+type Finding struct {{
+\tKind      Kind
+\tEndpoint  string
+\tField     string
+\tExpected  string
+\tObserved  string
+\tSample    string
+\tCount     int
+\tFirstSeen time.Time
+\tLastSeen  time.Time
+}}
+
+func (f Finding) String() string {{
+\tlocation := strings.TrimSpace(f.Endpoint + " " + f.Field)
+\tif f.Kind == TypeChanged {{
+\t\treturn fmt.Sprintf("%s: %s: expected %s, observed %s (x%d)", f.Kind, location, f.Expected, f.Observed, f.Count)
+\t}}
+\treturn fmt.Sprintf("%s: %s (x%d)", f.Kind, location, f.Count)
+}}
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithSampleRate validates only the given fraction (0-1] of responses.
+func WithSampleRate(rate float64) Option {{
+\treturn func(m *Monitor) {{ m.sampleRate = rate }}
+}}
+
+// WithMaxBodyBytes skips validation of responses larger than n bytes.
+func WithMaxBodyBytes(n int64) Option {{
+\treturn func(m *Monitor) {{ m.maxBodyBytes = n }}
+}}
+
+// WithHandler calls fn the first time each distinct finding is seen.
+func WithHandler(fn func(Finding)) Option {{
+\treturn func(m *Monitor) {{ m.handlers = append(m.handlers, fn) }}
+}}
+
+// WithSink delivers an alert.Alert to sink the first time each distinct
+// finding is seen. Delivery happens in the background so the response is
+// not held up; call Flush to wait for pending deliveries.
+func WithSink(sink alert.Sink) Option {{
+\treturn func(m *Monitor) {{ m.sinks = append(m.sinks, sink) }}
+}}
+
+// WithAlertErrorHandler is called when a sink fails to deliver an alert.
+func WithAlertErrorHandler(fn func(alert.Alert, error)) Option {{
+\treturn func(m *Monitor) {{ m.onAlertError = fn }}
+}}
+
+type route struct {{
+\tlabel    string
+\tmethod   string
+\thost     string
+\tsegments []string
+\tendpoint *Endpoint
+}}
+
+// Monitor validates responses against a snapshot. It is safe for
+// concurrent use.
+type Monitor struct {{
+\tsnapshot     *Snapshot
+\tbasePath     string
+\troutes       []route
+\tsampleRate   float64
+\tmaxBodyBytes int64
+\thandlers     []func(Finding)
+\tsinks        []alert.Sink
+\tonAlertError func(alert.Alert, error)
+\tpending      sync.WaitGroup
+
+\tmu       sync.Mutex
+\tfindings map[string]*Finding
+\tchecked  int
+\trand     *rand.Rand
+}}
+
+// NewMonitor creates a Monitor for snapshot.
+func NewMonitor(snapshot *Snapshot, opts ...Option) *Monitor {{
+\tm := &Monitor{{
+\t\tsnapshot:     snapshot,
+\t\tsampleRate:   1,
+\t\tmaxBodyBytes: 1 << 20,
+\t\tfindings:     make(map[string]*Finding),
+\t\trand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+\t}}
+\tif base, err := url.Parse(snapshot.BaseURL); err == nil {{
+\t\tm.basePath = strings.TrimSuffix(base.Path, "/")
+\t}}
+\tfor label, endpoint := range snapshot.Endpoints {{
+\t\tr := route{{label: label, method: endpoint.Method, endpoint: endpoint}}
+\t\tif _, target, ok := strings.Cut(label, " "); ok && strings.Contains(target, "://") {{
+\t\t\tif u, err := url.Parse(target); err == nil {{
+\t\t\t\tr.host = u.Host
+\t\t\t}}
+\t\t}}
+\t\tr.segments = strings.Split(strings.Trim(endpoint.Path, "/"), "/")
+\t\tm.routes = append(m.routes, r)
+\t}}
+\t// Prefer literal segments over parameters when several routes match.
+\tsort.Slice(m.routes, func(i, j int) bool {{
+\t\treturn countParams(m.routes[i].segments) < countParams(m.routes[j].segments)
+\t}})
+\tfor _, opt := range opts {{
+\t\topt(m)
+\t}}
+\treturn m
+}}
+
+func countParams(segments []string) int {{
+\tn := 0
+\tfor _, segment := range segments {{
+\t\tif strings.HasPrefix(segment, "{{") {{
+\t\t\tn++
+\t\t}}
+\t}}
+\treturn n
+}}
+
+// Transport wraps next so every sampled response is validated. A nil next
+// uses http.DefaultTransport.
+//
+//\tclient.HTTPClient.Transport = monitor.Transport(client.HTTPClient.Transport)
+func (m *Monitor) Transport(next http.RoundTripper) http.RoundTripper {{
+\tif next == nil {{
+\t\tnext = http.DefaultTransport
+\t}}
+\treturn roundTripperFunc(func(req *http.Request) (*http.Response, error) {{
+\t\tresp, err := next.RoundTrip(req)
+\t\tif err != nil || !m.sample() {{
+\t\t\treturn resp, err
+\t\t}}
+
+\t\tvar buf bytes.Buffer
+\t\tn, readErr := io.Copy(&buf, io.LimitReader(resp.Body, m.maxBodyBytes+1))
+\t\tif readErr != nil || n > m.maxBodyBytes {{
+\t\t\tresp.Body = readCloser{{io.MultiReader(&buf, resp.Body), resp.Body}}
+\t\t\treturn resp, err
+\t\t}}
+\t\tresp.Body.Close()
+\t\tresp.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+\t\tm.Check(req.Method, req.URL, resp.StatusCode, buf.Bytes())
+\t\treturn resp, err
+\t}})
+}}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {{ return f(req) }}
+
+type readCloser struct {{
+\tio.Reader
+\tio.Closer
+}}
+
+func (m *Monitor) sample() bool {{
+\tif m.sampleRate >= 1 {{
+\t\treturn true
+\t}}
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\treturn m.rand.Float64() < m.sampleRate
+}}
+
+// Check validates one response body and records any findings.
+func (m *Monitor) Check(method string, u *url.URL, status int, body []byte) {{
+\tm.mu.Lock()
+\tm.checked++
+\tm.mu.Unlock()
+
+\tr := m.match(method, u)
+\tif r == nil {{
+\t\tm.record(Finding{{Kind: UnknownEndpoint, Endpoint: method + " " + u.Path}}, body)
+\t\treturn
+\t}}
+
+\tschema, ok := r.endpoint.ResponseSchemas[strconv.Itoa(status)]
+\tif !ok {{
+\t\tif status == http.StatusNotFound || status == http.StatusGone {{
+\t\t\tm.record(Finding{{Kind: EndpointVanished, Endpoint: r.label, Observed: strconv.Itoa(status)}}, body)
+\t\t}}
+\t\treturn
+\t}}
+\tif schema == nil || len(body) == 0 {{
+\t\treturn
+\t}}
+
+\tvar value interface{{}}
+\tdecoder := json.NewDecoder(bytes.NewReader(body))
+\tdecoder.UseNumber()
+\tif err := decoder.Decode(&value); err != nil {{
+\t\tm.record(Finding{{Kind: TypeChanged, Endpoint: r.label, Field: "$", Expected: schema.Type, Observed: "non-json"}}, body)
+\t\treturn
+\t}}
+\tm.validate(r.label, "$", schema, value, body)
+}}
+
+func (m *Monitor) match(method string, u *url.URL) *route {{
+\tpath := strings.TrimPrefix(u.Path, m.basePath)
+\tsegments := strings.Split(strings.Trim(path, "/"), "/")
+\tfor i := range m.routes {{
+\t\tr := &m.routes[i]
+\t\tif r.method != method || len(r.segments) != len(segments) {{
+\t\t\tcontinue
+\t\t}}
+\t\tif r.host != "" && r.host != u.Host {{
+\t\t\tcontinue
+\t\t}}
+\t\tmatched := true
+\t\tfor j, segment := range r.segments {{
+\t\t\tif !strings.HasPrefix(segment, "{{") && segment != segments[j] {{
+\t\t\t\tmatched = false
+\t\t\t\tbreak
+\t\t\t}}
+\t\t}}
+\t\tif matched {{
+\t\t\treturn r
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+func (m *Monitor) validate(endpoint, field string, schema *Schema, value interface{{}}, body []byte) {{
+\tif schema == nil || schema.Type == "" || schema.Type == "any" {{
+\t\treturn
+\t}}
+\tobserved := jsonType(value)
+\tif observed == "null" {{
+\t\tif !schema.Nullable && schema.Type != "null" {{
+\t\t\tm.record(Finding{{Kind: TypeChanged, Endpoint: endpoint, Field: field, Expected: schema.Type, Observed: observed}}, body)
+\t\t}}
+\t\treturn
+\t}}
+\tif observed != schema.Type && !(observed == "integer" && schema.Type == "number") {{
+\t\tm.record(Finding{{Kind: TypeChanged, Endpoint: endpoint, Field: field, Expected: schema.Type, Observed: observed}}, body)
+\t\treturn
+\t}}
+
+\tswitch v := value.(type) {{
+\tcase map[string]interface{{}}:
+\t\tfor name, child := range v {{
+\t\t\tchildSchema, ok := schema.Properties[name]
+\t\t\tif !ok {{
+\t\t\t\tm.record(Finding{{Kind: FieldAdded, Endpoint: endpoint, Field: field + "." + name, Observed: jsonType(child)}}, body)
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tm.validate(endpoint, field+"."+name, childSchema, child, body)
+\t\t}}
+\t\tfor name, childSchema := range schema.Properties {{
+\t\t\tif _, ok := v[name]; !ok && !childSchema.Nullable {{
+\t\t\t\tm.record(Finding{{Kind: FieldMissing, Endpoint: endpoint, Field: field + "." + name, Expected: childSchema.Type}}, body)
+\t\t\t}}
+\t\t}}
+\tcase []interface{{}}:
+\t\tfor _, item := range v {{
+\t\t\tm.validate(endpoint, field+"[]", schema.Items, item, body)
+\t\t}}
+\t}}
+}}
+
+func jsonType(value interface{{}}) string {{
+\tswitch v := value.(type) {{
+\tcase nil:
+\t\treturn "null"
+\tcase bool:
+\t\treturn "boolean"
+\tcase json.Number:
+\t\tif !strings.ContainsAny(v.String(), ".eE") {{
+\t\t\treturn "integer"
+\t\t}}
+\t\treturn "number"
+\tcase string:
+\t\treturn "string"
+\tcase []interface{{}}:
+\t\treturn "array"
+\tdefault:
+\t\treturn "object"
+\t}}
+}}
+
+const maxSampleBytes = 512
+
+func (m *Monitor) record(f Finding, body []byte) {{
+\tkey := string(f.Kind) + "|" + f.Endpoint + "|" + f.Field + "|" + f.Observed
+\tnow := time.Now()
+
+\tm.mu.Lock()
+\texisting, ok := m.findings[key]
+\tif ok {{
+\t\texisting.Count++
+\t\texisting.LastSeen = now
+\t\tm.mu.Unlock()
+\t\treturn
+\t}}
+\tif len(body) > maxSampleBytes {{
+\t\tbody = body[:maxSampleBytes]
+\t}}
+\tf.Sample = string(body)
+\tf.Count = 1
+\tf.FirstSeen, f.LastSeen = now, now
+\tm.findings[key] = &f
+\thandlers := m.handlers
+\tm.mu.Unlock()
+
+\tfor _, handler := range handlers {{
+\t\thandler(f)
+\t}}
+\tif len(m.sinks) > 0 {{
+\t\tm.alert(f)
+\t}}
+}}
+
+const alertTimeout = 10 * time.Second
+
+func (m *Monitor) alert(f Finding) {{
+\ta := alert.Alert{{
+\t\tSource:   "drift",
+\t\tAPI:      m.snapshot.APIName,
+\t\tKind:     string(f.Kind),
+\t\tEndpoint: f.Endpoint,
+\t\tField:    f.Field,
+\t\tExpected: f.Expected,
+\t\tObserved: f.Observed,
+\t\tSample:   f.Sample,
+\t\tTime:     f.FirstSeen,
+\t}}
+\tfor _, sink := range m.sinks {{
+\t\tm.pending.Add(1)
+\t\tgo func(sink alert.Sink) {{
+\t\t\tdefer m.pending.Done()
+\t\t\tctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+\t\t\tdefer cancel()
+\t\t\tif err := sink.Send(ctx, a); err != nil && m.onAlertError != nil {{
+\t\t\t\tm.onAlertError(a, err)
+\t\t\t}}
+\t\t}}(sink)
+\t}}
+}}
+
+// Flush waits for alerts still being delivered to sinks.
+func (m *Monitor) Flush() {{
+\tm.pending.Wait()
+}}
+
+// Findings returns every distinct finding so far, most frequent first.
+func (m *Monitor) Findings() []Finding {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\tfindings := make([]Finding, 0, len(m.findings))
+\tfor _, f := range m.findings {{
+\t\tfindings = append(findings, *f)
+\t}}
+\tsort.Slice(findings, func(i, j int) bool {{
+\t\tif findings[i].Count != findings[j].Count {{
+\t\t\treturn findings[i].Count > findings[j].Count
+\t\t}}
+\t\treturn findings[i].String() < findings[j].String()
+\t}})
+\treturn findings
+}}
 
+// Report writes a summary of the findings to w.
+func (m *Monitor) Report(w io.Writer) error {{
+\tm.mu.Lock()
+\tchecked := m.checked
+\tm.mu.Unlock()
+
+\tfindings := m.Findings()
+\tif _, err := fmt.Fprintf(w, "schema %s v%d: %d responses checked, %d findings\\n",
+\t\tm.snapshot.APIName, m.snapshot.Version, checked, len(findings)); err != nil {{
+\t\treturn err
+\t}}
+\tfor _, f := range findings {{
+\t\tif _, err := fmt.Fprintf(w, "  %s\\n", f); err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+// Reset clears accumulated findings, for example after they were reported.
+func (m *Monitor) Reset() {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\tm.findings = make(map[string]*Finding)
+\tm.checked = 0
+}}
 """
-        
-        for endpoint_key, endpoint in self.endpoints.items():
-            method_name = self._path_to_method_name(endpoint.method, endpoint.path_pattern)
-            method_name_go = self._to_class_name(method_name)
-            readme += f"- `{method_name_go}()` - {endpoint.method} {endpoint.path_pattern}\n"
-        
-        readme += """
+    
+    def _generate_go_fixtures(self) -> str:
+        return """// Package fixtures generates realistic but synthetic request and response
+// bodies for tests. Value distributions (formats, enum frequencies, ranges,
+// null rates, array lengths) were learned from anonymized captures and are
+// stored in profiles.json; no captured personal data is reproduced.
+package fixtures
+
+import (
+\t_ "embed"
+\t"encoding/json"
+\t"fmt"
+\t"math"
+\t"math/rand"
+\t"sort"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+//go:embed profiles.json
+var profilesJSON []byte
+
+// Weighted is an enum value and its observed frequency.
+type Weighted struct {
+\tValue  string  `json:"value"`
+\tWeight float64 `json:"weight"`
+}
+
+// Node profiles one value in a body.
+type Node struct {
+\tKind       string             `json:"kind"`
+\tNullRate   float64            `json:"null_rate,omitempty"`
+\tProperties map[string]*Node   `json:"properties,omitempty"`
+\tPresence   map[string]float64 `json:"presence,omitempty"`
+\tItems      *Node              `json:"items,omitempty"`
+\tMinItems   int                `json:"min_items,omitempty"`
+\tMaxItems   int                `json:"max_items,omitempty"`
+\tFormat     string             `json:"format,omitempty"`
+\tEnum       []Weighted         `json:"enum,omitempty"`
+\tPattern    string             `json:"pattern,omitempty"`
+\tMinLength  int                `json:"min_length,omitempty"`
+\tMaxLength  int                `json:"max_length,omitempty"`
+\tMin        float64            `json:"min,omitempty"`
+\tMax        float64            `json:"max,omitempty"`
+\tSequence   bool               `json:"sequence,omitempty"`
+\tTrueRate   float64            `json:"true_rate,omitempty"`
+}
+
+// Profile holds the learned request body and per-status response bodies
+// of one endpoint.
+type Profile struct {
+\tRequest   *Node            `json:"request,omitempty"`
+\tResponses map[string]*Node `json:"responses,omitempty"`
+}
+
+var profiles = mustLoadProfiles()
+
+func mustLoadProfiles() map[string]*Profile {
+\tvar p map[string]*Profile
+\tif err := json.Unmarshal(profilesJSON, &p); err != nil {
+\t\tpanic(fmt.Sprintf("fixtures: invalid embedded profiles: %v", err))
+\t}
+\treturn p
+}
+
+// Endpoints lists the endpoint labels ("GET /v1/users") with profiles.
+func Endpoints() []string {
+\tendpoints := make([]string, 0, len(profiles))
+\tfor endpoint := range profiles {
+\t\tendpoints = append(endpoints, endpoint)
+\t}
+\tsort.Strings(endpoints)
+\treturn endpoints
+}
+
+// Generator produces fixtures. The same seed always yields the same
+// sequence of values. A Generator is not safe for concurrent use.
+type Generator struct {
+\trand     *rand.Rand
+\tsequence map[*Node]int64
+}
+
+// New creates a Generator seeded with seed.
+func New(seed int64) *Generator {
+\treturn &Generator{rand: rand.New(rand.NewSource(seed)), sequence: make(map[*Node]int64)}
+}
+
+// Response generates a body for the first successful status captured for
+// endpoint.
+func (g *Generator) Response(endpoint string) (json.RawMessage, error) {
+\tprofile, err := lookup(endpoint)
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tstatuses := make([]string, 0, len(profile.Responses))
+\tfor status := range profile.Responses {
+\t\tstatuses = append(statuses, status)
+\t}
+\tsort.Strings(statuses)
+\tfor _, status := range statuses {
+\t\tif strings.HasPrefix(status, "2") {
+\t\t\treturn json.Marshal(g.value(profile.Responses[status]))
+\t\t}
+\t}
+\treturn nil, fmt.Errorf("fixtures: no successful response captured for %q", endpoint)
+}
+
+// ResponseFor generates a body for endpoint answering with status.
+func (g *Generator) ResponseFor(endpoint string, status int) (json.RawMessage, error) {
+\tprofile, err := lookup(endpoint)
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tnode, ok := profile.Responses[strconv.Itoa(status)]
+\tif !ok {
+\t\treturn nil, fmt.Errorf("fixtures: no %d response captured for %q", status, endpoint)
+\t}
+\treturn json.Marshal(g.value(node))
+}
+
+// Request generates a request body for endpoint.
+func (g *Generator) Request(endpoint string) (json.RawMessage, error) {
+\tprofile, err := lookup(endpoint)
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tif profile.Request == nil {
+\t\treturn nil, fmt.Errorf("fixtures: no request body captured for %q", endpoint)
+\t}
+\treturn json.Marshal(g.value(profile.Request))
+}
+
+// Fill generates a response for endpoint and decodes it into v, typically
+// one of the SDK's response types.
+func (g *Generator) Fill(endpoint string, v interface{}) error {
+\tbody, err := g.Response(endpoint)
+\tif err != nil {
+\t\treturn err
+\t}
+\treturn json.Unmarshal(body, v)
+}
+
+// Fixture fills v with the default (seed 1) response for endpoint.
+func Fixture(endpoint string, v interface{}) error {
+\treturn New(1).Fill(endpoint, v)
+}
+
+func lookup(endpoint string) (*Profile, error) {
+\tprofile, ok := profiles[endpoint]
+\tif !ok {
+\t\treturn nil, fmt.Errorf("fixtures: no profile for %q", endpoint)
+\t}
+\treturn profile, nil
+}
+
+func (g *Generator) value(n *Node) interface{} {
+\tif n == nil || n.Kind == "null" || (n.NullRate > 0 && g.rand.Float64() < n.NullRate) {
+\t\treturn nil
+\t}
+\tswitch n.Kind {
+\tcase "object":
+\t\tnames := make([]string, 0, len(n.Properties))
+\t\tfor name := range n.Properties {
+\t\t\tnames = append(names, name)
+\t\t}
+\t\tsort.Strings(names)
+\t\tobject := make(map[string]interface{}, len(names))
+\t\tfor _, name := range names {
+\t\t\tif rate, ok := n.Presence[name]; ok && g.rand.Float64() >= rate {
+\t\t\t\tcontinue
+\t\t\t}
+\t\t\tobject[name] = g.value(n.Properties[name])
+\t\t}
+\t\treturn object
+\tcase "array":
+\t\tlength := n.MinItems + g.rand.Intn(n.MaxItems-n.MinItems+1)
+\t\titems := make([]interface{}, length)
+\t\tfor i := range items {
+\t\t\titems[i] = g.value(n.Items)
+\t\t}
+\t\treturn items
+\tcase "boolean":
+\t\treturn g.rand.Float64() < n.TrueRate
+\tcase "integer":
+\t\tif n.Sequence {
+\t\t\tnext := int64(n.Min) + g.sequence[n]
+\t\t\tg.sequence[n]++
+\t\t\treturn next
+\t\t}
+\t\treturn int64(n.Min) + g.rand.Int63n(int64(n.Max-n.Min)+1)
+\tcase "number":
+\t\treturn math.Round((n.Min+g.rand.Float64()*(n.Max-n.Min))*100) / 100
+\tcase "string":
+\t\treturn g.string(n)
+\t}
+\treturn nil
+}
+
+var (
+\tfirstNames = []string{"Alex", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn", "Rowan"}
+\tlastNames  = []string{"Smith", "Garcia", "Chen", "Okafor", "Novak", "Silva", "Kim", "Larsen", "Patel", "Moreau"}
+\twords      = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua")
+)
+
+// baseTime (2024-01-01) anchors dates whose captured range is unknown.
+const baseTime = 1704067200
+
+func (g *Generator) pick(values []string) string {
+\treturn values[g.rand.Intn(len(values))]
+}
+
+func (g *Generator) string(n *Node) string {
+\tswitch n.Format {
+\tcase "email":
+\t\treturn fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(g.pick(firstNames)), strings.ToLower(g.pick(lastNames)), g.rand.Intn(100))
+\tcase "name":
+\t\treturn g.pick(firstNames) + " " + g.pick(lastNames)
+\tcase "uuid":
+\t\tb := make([]byte, 16)
+\t\tg.rand.Read(b)
+\t\tb[6] = b[6]&0x0f | 0x40
+\t\tb[8] = b[8]&0x3f | 0x80
+\t\treturn fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+\tcase "date-time", "date":
+\t\tfrom, to := int64(n.Min), int64(n.Max)
+\t\tif from == 0 && to == 0 {
+\t\t\tfrom, to = baseTime, baseTime+365*24*60*60
+\t\t}
+\t\tt := time.Unix(from+g.rand.Int63n(to-from+1), 0).UTC()
+\t\tif n.Format == "date" {
+\t\t\treturn t.Format("2006-01-02")
+\t\t}
+\t\treturn t.Format(time.RFC3339)
+\tcase "url":
+\t\treturn fmt.Sprintf("https://example.com/%s/%d", g.pick(words), g.rand.Intn(10000))
+\tcase "enum":
+\t\treturn g.weighted(n.Enum)
+\tcase "pattern":
+\t\tvar b strings.Builder
+\t\tfor _, c := range n.Pattern {
+\t\t\tswitch c {
+\t\t\tcase '9':
+\t\t\t\tb.WriteByte(byte('0' + g.rand.Intn(10)))
+\t\t\tcase 'A':
+\t\t\t\tb.WriteByte(byte('A' + g.rand.Intn(26)))
+\t\t\tcase 'a':
+\t\t\t\tb.WriteByte(byte('a' + g.rand.Intn(26)))
+\t\t\tdefault:
+\t\t\t\tb.WriteRune(c)
+\t\t\t}
+\t\t}
+\t\treturn b.String()
+\t}
+
+\tlength := n.MinLength
+\tif n.MaxLength > n.MinLength {
+\t\tlength += g.rand.Intn(n.MaxLength - n.MinLength + 1)
+\t}
+\tvar b strings.Builder
+\tfor b.Len() < length {
+\t\tif b.Len() > 0 {
+\t\t\tb.WriteByte(' ')
+\t\t}
+\t\tb.WriteString(g.pick(words))
+\t}
+\ttext := b.String()
+\tif length > 0 && len(text) > length {
+\t\ttext = strings.TrimSpace(text[:length])
+\t}
+\tif text == "" {
+\t\treturn text
+\t}
+\treturn strings.ToUpper(text[:1]) + text[1:]
+}
+
+func (g *Generator) weighted(values []Weighted) string {
+\tif len(values) == 0 {
+\t\treturn ""
+\t}
+\ttotal := 0.0
+\tfor _, v := range values {
+\t\ttotal += v.Weight
+\t}
+\tr := g.rand.Float64() * total
+\tfor _, v := range values {
+\t\tif r < v.Weight {
+\t\t\treturn v.Value
+\t\t}
+\t\tr -= v.Weight
+\t}
+\treturn values[len(values)-1].Value
+}
+"""
+    
+    def _generate_go_vcr(self, module_path: str) -> str:
+        return """// Package vcr records HTTP interactions to JSON cassettes and replays them
+// deterministically, so integration tests against the API run hermetically.
+// A Recorder is an http.RoundTripper and works with any generated client:
+//
+//\trec, err := vcr.New("testdata/cassettes/list_users.json")
+//\tdefer rec.Stop()
+//\tclient.HTTPClient.Transport = rec
+//
+// In tests, ForTest does the same with a cassette named after the test.
+// Credentials are redacted before anything is written to disk.
+package vcr
+
+import (
+\t"bytes"
+\t"encoding/base64"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/url"
+\t"os"
+\t"path/filepath"
+\t"reflect"
+\t"sort"
+\t"strings"
+\t"sync"
+\t"testing"
+\t"time"
+\t"unicode/utf8"
+
+\t\"""" + module_path + """/jsondiff"
+)
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+\t// ModeAuto replays when the cassette exists and records otherwise.
+\tModeAuto Mode = iota
+\t// ModeReplay only serves recorded interactions and never hits the network.
+\tModeReplay
+\t// ModeRecord always hits the network and overwrites the cassette.
+\tModeRecord
+\t// ModeReplayOrRecord replays matching interactions and records new ones.
+\tModeReplayOrRecord
+)
+
+// ModeFromEnv reads the mode from VCR_MODE ("record", "replay",
+// "new_episodes"), falling back to ModeAuto.
+func ModeFromEnv() Mode {
+\tswitch strings.ToLower(os.Getenv("VCR_MODE")) {
+\tcase "record":
+\t\treturn ModeRecord
+\tcase "replay":
+\t\treturn ModeReplay
+\tcase "new_episodes":
+\t\treturn ModeReplayOrRecord
+\t}
+\treturn ModeAuto
+}
+
+// Request is the recorded form of an HTTP request.
+type Request struct {
+\tMethod  string      `json:"method"`
+\tURL     string      `json:"url"`
+\tHeaders http.Header `json:"headers,omitempty"`
+\tBody    string      `json:"body,omitempty"`
+}
+
+// Response is the recorded form of an HTTP response. Bodies that are not
+// valid UTF-8 are stored base64-encoded.
+type Response struct {
+\tStatus       int         `json:"status"`
+\tHeaders      http.Header `json:"headers,omitempty"`
+\tBody         string      `json:"body,omitempty"`
+\tBodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Interaction is one recorded exchange.
+type Interaction struct {
+\tRequest    Request   `json:"request"`
+\tResponse   Response  `json:"response"`
+\tRecordedAt time.Time `json:"recorded_at"`
+}
+
+// Cassette is the on-disk file format.
+type Cassette struct {
+\tVersion      int            `json:"version"`
+\tInteractions []*Interaction `json:"interactions"`
+}
+
+// Matcher reports whether a recorded request matches an outgoing one.
+// The outgoing request has already been redacted like recorded ones.
+type Matcher func(outgoing, recorded Request) bool
+
+// MatchMethod matches on the HTTP method.
+func MatchMethod(outgoing, recorded Request) bool {
+\treturn outgoing.Method == recorded.Method
+}
+
+// MatchPath matches on scheme, host and path, ignoring the query string.
+func MatchPath(outgoing, recorded Request) bool {
+\ta, errA := url.Parse(outgoing.URL)
+\tb, errB := url.Parse(recorded.URL)
+\treturn errA == nil && errB == nil && a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path
+}
+
+// MatchQuery matches on query parameters regardless of their order,
+// skipping the ignored ones (for example timestamps or nonces).
+func MatchQuery(ignore ...string) Matcher {
+\treturn func(outgoing, recorded Request) bool {
+\t\ta, errA := url.Parse(outgoing.URL)
+\t\tb, errB := url.Parse(recorded.URL)
+\t\tif errA != nil || errB != nil {
+\t\t\treturn false
+\t\t}
+\t\tqa, qb := a.Query(), b.Query()
+\t\tfor _, name := range ignore {
+\t\t\tqa.Del(name)
+\t\t\tqb.Del(name)
+\t\t}
+\t\treturn reflect.DeepEqual(qa, qb)
+\t}
+}
+
+// MatchBody matches request bodies, comparing JSON bodies semantically.
+func MatchBody(outgoing, recorded Request) bool {
+\treturn outgoing.Body == recorded.Body || jsondiff.Equal(outgoing.Body, recorded.Body, jsondiff.Options{})
+}
+
+// MatchBodyIgnoring matches request bodies like MatchBody, skipping what
+// opts ignores, such as client-generated timestamps and nonces:
+//
+//\tvcr.MatchBodyIgnoring(jsondiff.Options{Ignore: []string{"nonce"}, IgnoreTimestamps: true})
+func MatchBodyIgnoring(opts jsondiff.Options) Matcher {
+\treturn func(outgoing, recorded Request) bool {
+\t\treturn outgoing.Body == recorded.Body || jsondiff.Equal(outgoing.Body, recorded.Body, opts)
+\t}
+}
+
+// MatchHeaders matches on the values of the named headers.
+func MatchHeaders(names ...string) Matcher {
+\treturn func(outgoing, recorded Request) bool {
+\t\tfor _, name := range names {
+\t\t\tif !reflect.DeepEqual(outgoing.Headers.Values(name), recorded.Headers.Values(name)) {
+\t\t\t\treturn false
+\t\t\t}
+\t\t}
+\t\treturn true
+\t}
+}
+
+// MatchAll combines matchers.
+func MatchAll(matchers ...Matcher) Matcher {
+\treturn func(outgoing, recorded Request) bool {
+\t\tfor _, m := range matchers {
+\t\t\tif !m(outgoing, recorded) {
+\t\t\t\treturn false
+\t\t\t}
+\t\t}
+\t\treturn true
+\t}
+}
+
+// DefaultMatcher matches method, URL (query order-insensitive) and body.
+var DefaultMatcher = MatchAll(MatchMethod, MatchPath, MatchQuery(), MatchBody)
+
+// Redacted replaces credentials in cassettes.
+const Redacted = "REDACTED"
+
+var (
+\tdefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "Api-Key", "X-Auth-Token", "X-Access-Token"}
+\tdefaultRedactParams  = []string{"api_key", "apikey", "access_token", "token", "key", "signature", "sig", "client_secret"}
+)
+
+// NoMatchError is returned in replay mode when no recorded interaction
+// matches a request.
+type NoMatchError struct {
+\tMethod string
+\tURL    string
+}
+
+func (e *NoMatchError) Error() string {
+\treturn fmt.Sprintf("vcr: no recorded interaction for %s %s", e.Method, e.URL)
+}
+
+// ErrNotFound matches any *NoMatchError with errors.Is.
+var ErrNotFound = errors.New("vcr: no recorded interaction")
+
+// Is reports whether target is ErrNotFound.
+func (e *NoMatchError) Is(target error) bool { return target == ErrNotFound }
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the recording mode (default ModeAuto).
+func WithMode(mode Mode) Option {
+\treturn func(r *Recorder) { r.mode = mode }
+}
+
+// WithMatcher replaces DefaultMatcher.
+func WithMatcher(m Matcher) Option {
+\treturn func(r *Recorder) { r.matcher = m }
+}
+
+// WithTransport sets the transport used when recording (default
+// http.DefaultTransport).
+func WithTransport(rt http.RoundTripper) Option {
+\treturn func(r *Recorder) { r.transport = rt }
+}
+
+// WithRedactHeaders redacts additional headers.
+func WithRedactHeaders(names ...string) Option {
+\treturn func(r *Recorder) { r.redactHeaders = append(r.redactHeaders, names...) }
+}
+
+// WithRedactParams redacts additional query parameters.
+func WithRedactParams(names ...string) Option {
+\treturn func(r *Recorder) { r.redactParams = append(r.redactParams, names...) }
+}
+
+// WithSanitizer runs fn on every interaction before it is saved, for
+// example to scrub personal data from bodies.
+func WithSanitizer(fn func(*Interaction)) Option {
+\treturn func(r *Recorder) { r.sanitizers = append(r.sanitizers, fn) }
+}
+
+// WithRepeats lets a recorded interaction answer more than one request.
+// By default each interaction is played back once, in order.
+func WithRepeats() Option {
+\treturn func(r *Recorder) { r.repeats = true }
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+// It is safe for concurrent use.
+type Recorder struct {
+\tpath          string
+\tmode          Mode
+\tmatcher       Matcher
+\ttransport     http.RoundTripper
+\tredactHeaders []string
+\tredactParams  []string
+\tsanitizers    []func(*Interaction)
+\trepeats       bool
+
+\tmu       sync.Mutex
+\tcassette *Cassette
+\tused     []bool
+\tdirty    bool
+}
+
+// New opens the cassette at path, which need not exist yet.
+func New(path string, opts ...Option) (*Recorder, error) {
+\tr := &Recorder{
+\t\tpath:          path,
+\t\tmatcher:       DefaultMatcher,
+\t\ttransport:     http.DefaultTransport,
+\t\tredactHeaders: append([]string(nil), defaultRedactHeaders...),
+\t\tredactParams:  append([]string(nil), defaultRedactParams...),
+\t\tcassette:      &Cassette{Version: 1},
+\t}
+\tfor _, opt := range opts {
+\t\topt(r)
+\t}
+
+\tdata, err := os.ReadFile(path)
+\tswitch {
+\tcase err == nil:
+\t\tif r.mode == ModeAuto {
+\t\t\tr.mode = ModeReplay
+\t\t}
+\t\tif r.mode != ModeRecord {
+\t\t\tif err := json.Unmarshal(data, r.cassette); err != nil {
+\t\t\t\treturn nil, fmt.Errorf("vcr: invalid cassette %s: %w", path, err)
+\t\t\t}
+\t\t}
+\tcase os.IsNotExist(err):
+\t\tif r.mode == ModeAuto {
+\t\t\tr.mode = ModeRecord
+\t\t}
+\t\tif r.mode == ModeReplay {
+\t\t\treturn nil, fmt.Errorf("vcr: cassette %s does not exist (record it with VCR_MODE=record)", path)
+\t\t}
+\tdefault:
+\t\treturn nil, err
+\t}
+\tr.used = make([]bool, len(r.cassette.Interactions))
+\treturn r, nil
+}
+
+// ForTest opens the cassette testdata/cassettes/<test name>.json for t,
+// in the mode VCR_MODE selects unless opts set one, and saves it when the
+// test ends. Install it as the client's transport:
+//
+//\tfunc TestListUsers(t *testing.T) {
+//\t\tclient := api.New""" + self.class_name + """Client("")
+//\t\tclient.HTTPClient.Transport = vcr.ForTest(t)
+//\t\t...
+//\t}
+//
+// Once recorded, the test replays the cassette without the network or
+// credentials; run it with VCR_MODE=record to record it again.
+func ForTest(t testing.TB, opts ...Option) *Recorder {
+\tt.Helper()
+\tpath := filepath.Join("testdata", "cassettes", filepath.FromSlash(t.Name())+".json")
+\trec, err := New(path, append([]Option{WithMode(ModeFromEnv())}, opts...)...)
+\tif err != nil {
+\t\tt.Fatal(err)
+\t}
+\tt.Cleanup(func() {
+\t\tif err := rec.Stop(); err != nil {
+\t\t\tt.Errorf("vcr: saving %s: %v", path, err)
+\t\t}
+\t})
+\treturn rec
+}
+
+// Mode reports the effective mode.
+func (r *Recorder) Mode() Mode {
+\treturn r.mode
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+\tvar body []byte
+\tif req.Body != nil {
+\t\tvar err error
+\t\tbody, err = io.ReadAll(req.Body)
+\t\treq.Body.Close()
+\t\tif err != nil {
+\t\t\treturn nil, err
+\t\t}
+\t\treq.Body = io.NopCloser(bytes.NewReader(body))
+\t}
+\toutgoing := r.redactRequest(Request{Method: req.Method, URL: req.URL.String(), Headers: req.Header.Clone(), Body: string(body)})
+
+\tif r.mode != ModeRecord {
+\t\tif interaction := r.match(outgoing); interaction != nil {
+\t\t\treturn interaction.Response.toHTTP(req)
+\t\t}
+\t\tif r.mode == ModeReplay {
+\t\t\treturn nil, &NoMatchError{Method: req.Method, URL: outgoing.URL}
+\t\t}
+\t}
+\treturn r.record(req, outgoing)
+}
+
+func (r *Recorder) match(outgoing Request) *Interaction {
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tvar repeat *Interaction
+\tfor i, interaction := range r.cassette.Interactions {
+\t\tif !r.matcher(outgoing, interaction.Request) {
+\t\t\tcontinue
+\t\t}
+\t\tif !r.used[i] {
+\t\t\tr.used[i] = true
+\t\t\treturn interaction
+\t\t}
+\t\tif r.repeats {
+\t\t\trepeat = interaction
+\t\t}
+\t}
+\treturn repeat
+}
+
+func (r *Recorder) record(req *http.Request, outgoing Request) (*http.Response, error) {
+\tresp, err := r.transport.RoundTrip(req)
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tbody, err := io.ReadAll(resp.Body)
+\tresp.Body.Close()
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\tresp.Body = io.NopCloser(bytes.NewReader(body))
+
+\tinteraction := &Interaction{
+\t\tRequest:    outgoing,
+\t\tResponse:   Response{Status: resp.StatusCode, Headers: r.redactHeaderValues(resp.Header.Clone())},
+\t\tRecordedAt: time.Now().UTC(),
+\t}
+\tif utf8.Valid(body) {
+\t\tinteraction.Response.Body = string(body)
+\t} else {
+\t\tinteraction.Response.Body = base64.StdEncoding.EncodeToString(body)
+\t\tinteraction.Response.BodyEncoding = "base64"
+\t}
+\tfor _, sanitize := range r.sanitizers {
+\t\tsanitize(interaction)
+\t}
+
+\tr.mu.Lock()
+\tr.cassette.Interactions = append(r.cassette.Interactions, interaction)
+\tr.used = append(r.used, true)
+\tr.dirty = true
+\tr.mu.Unlock()
+\treturn resp, nil
+}
+
+// Stop writes newly recorded interactions to the cassette. It is a no-op
+// when nothing was recorded.
+func (r *Recorder) Stop() error {
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tif !r.dirty {
+\t\treturn nil
+\t}
+\tvar buf bytes.Buffer
+\tenc := json.NewEncoder(&buf)
+\tenc.SetEscapeHTML(false)
+\tenc.SetIndent("", "  ")
+\tif err := enc.Encode(r.cassette); err != nil {
+\t\treturn err
+\t}
+\tif err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+\t\treturn err
+\t}
+\tif err := os.WriteFile(r.path, buf.Bytes(), 0o644); err != nil {
+\t\treturn err
+\t}
+\tr.dirty = false
+\treturn nil
+}
+
+func (r *Recorder) redactRequest(req Request) Request {
+\treq.Headers = r.redactHeaderValues(req.Headers)
+\tif u, err := url.Parse(req.URL); err == nil && u.RawQuery != "" {
+\t\tquery := u.Query()
+\t\tfor name := range query {
+\t\t\tfor _, sensitive := range r.redactParams {
+\t\t\t\tif strings.EqualFold(name, sensitive) {
+\t\t\t\t\tquery.Set(name, Redacted)
+\t\t\t\t}
+\t\t\t}
+\t\t}
+\t\tu.RawQuery = query.Encode()
+\t\treq.URL = u.String()
+\t}
+\treturn req
+}
+
+func (r *Recorder) redactHeaderValues(headers http.Header) http.Header {
+\tfor _, name := range r.redactHeaders {
+\t\tif values := headers.Values(name); len(values) > 0 {
+\t\t\tredacted := make([]string, len(values))
+\t\t\tfor i := range redacted {
+\t\t\t\tredacted[i] = Redacted
+\t\t\t}
+\t\t\theaders[http.CanonicalHeaderKey(name)] = redacted
+\t\t}
+\t}
+\treturn headers
+}
+
+func (resp Response) toHTTP(req *http.Request) (*http.Response, error) {
+\tbody := []byte(resp.Body)
+\tif resp.BodyEncoding == "base64" {
+\t\tvar err error
+\t\tif body, err = base64.StdEncoding.DecodeString(resp.Body); err != nil {
+\t\t\treturn nil, fmt.Errorf("vcr: invalid recorded body: %w", err)
+\t\t}
+\t}
+\theaders := resp.Headers.Clone()
+\tif headers == nil {
+\t\theaders = make(http.Header)
+\t}
+\treturn &http.Response{
+\t\tStatus:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+\t\tStatusCode:    resp.Status,
+\t\tProto:         "HTTP/1.1",
+\t\tProtoMajor:    1,
+\t\tProtoMinor:    1,
+\t\tHeader:        headers,
+\t\tBody:          io.NopCloser(bytes.NewReader(body)),
+\t\tContentLength: int64(len(body)),
+\t\tRequest:       req,
+\t}, nil
+}
+
+// Unused lists recorded interactions that were never played back, useful
+// for asserting that a test exercised everything it recorded.
+func (r *Recorder) Unused() []string {
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tvar unused []string
+\tfor i, interaction := range r.cassette.Interactions {
+\t\tif !r.used[i] {
+\t\t\tunused = append(unused, interaction.Request.Method+" "+interaction.Request.URL)
+\t\t}
+\t}
+\tsort.Strings(unused)
+\treturn unused
+}
+"""
+    
+    def _generate_go_sdktest_server(self) -> str:
+        return f"""// Package sdktest provides helpers for testing code that uses the SDK.
+package sdktest
+
+import (
+\t"bytes"
+\t_ "embed"
+\t"encoding/base64"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/http/httptest"
+\t"sort"
+\t"strings"
+\t"sync"
+\t"testing"
+)
+
+//go:embed samples.json
+var samplesJSON []byte
+
+// Sample is one captured response, with the path parameters and query
+// string of the request that produced it.
+type Sample struct {{
+\tPath     string            `json:"path"`
+\tParams   map[string]string `json:"params,omitempty"`
+\tQuery    string            `json:"query,omitempty"`
+\tStatus   int               `json:"status"`
+\tHeaders  map[string]string `json:"headers,omitempty"`
+\tBody     string            `json:"body,omitempty"`
+\tEncoding string            `json:"encoding,omitempty"`
+}}
+
+// Request is a request received by a FixtureServer.
+type Request struct {{
+\tMethod   string
+\tPath     string
+\tQuery    string
+\tHeader   http.Header
+\tBody     []byte
+\tEndpoint string
+}}
+
+type route struct {{
+\tmethod   string
+\tpattern  string
+\tsegments []string
+\tliterals int
+\tsamples  []Sample
+\thandler  http.Handler
+}}
+
+// FixtureServer is an httptest.Server answering every captured endpoint
+// with its captured responses.
+type FixtureServer struct {{
+\t*httptest.Server
+
+\tmu       sync.Mutex
+\troutes   []*route
+\trequests []Request
+}}
+
+// NewFixtureServer starts a FixtureServer that is closed when the test
+// ends. Point the client at it with New{self.class_name}Client(srv.URL).
+//
+// A request is answered with the captured sample whose path parameters
+// and query match best; when no sample has the requested path parameters,
+// the first successful sample is served with the captured parameter values
+// in its body replaced by the requested ones, so GET /users/42 returns a
+// user with id 42.
+func NewFixtureServer(t testing.TB) *FixtureServer {{
+\tt.Helper()
+\tvar samples map[string][]Sample
+\tif err := json.Unmarshal(samplesJSON, &samples); err != nil {{
+\t\tt.Fatalf("sdktest: invalid embedded samples: %v", err)
+\t}}
+\ts := &FixtureServer{{}}
+\tfor endpoint, endpointSamples := range samples {{
+\t\ts.addRoute(endpoint, endpointSamples, nil)
+\t}}
+\ts.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+\tt.Cleanup(s.Close)
+\treturn s
+}}
+
+// Handle overrides the response for endpoint ("GET /v1/users/{{id}}"),
+// adding the route if it was never captured.
+func (s *FixtureServer) Handle(endpoint string, handler http.HandlerFunc) {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tfor _, r := range s.routes {{
+\t\tif r.method+" "+r.pattern == endpoint {{
+\t\t\tr.handler = handler
+\t\t\treturn
+\t\t}}
+\t}}
+\ts.addRoute(endpoint, nil, handler)
+}}
+
+// Endpoints lists the endpoints the server answers.
+func (s *FixtureServer) Endpoints() []string {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tendpoints := make([]string, len(s.routes))
+\tfor i, r := range s.routes {{
+\t\tendpoints[i] = r.method + " " + r.pattern
+\t}}
+\tsort.Strings(endpoints)
+\treturn endpoints
+}}
+
+// Requests returns the requests received so far.
+func (s *FixtureServer) Requests() []Request {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\treturn append([]Request(nil), s.requests...)
+}}
+
+func (s *FixtureServer) addRoute(endpoint string, samples []Sample, handler http.Handler) {{
+\tmethod, pattern, _ := strings.Cut(endpoint, " ")
+\tr := &route{{method: method, pattern: pattern, segments: strings.Split(pattern, "/"), samples: samples, handler: handler}}
+\tfor _, segment := range r.segments {{
+\t\tif !isParam(segment) {{
+\t\t\tr.literals++
+\t\t}}
+\t}}
+\ts.routes = append(s.routes, r)
+}}
+
+func isParam(segment string) bool {{
+\treturn strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}")
+}}
+
+func (r *route) match(path string) (map[string]string, bool) {{
+\tsegments := strings.Split(path, "/")
+\tif len(segments) != len(r.segments) {{
+\t\treturn nil, false
+\t}}
+\tparams := make(map[string]string)
+\tfor i, segment := range r.segments {{
+\t\tswitch {{
+\t\tcase isParam(segment) && segments[i] != "":
+\t\t\tparams[strings.Trim(segment, "{{}}")] = segments[i]
+\t\tcase segment != segments[i]:
+\t\t\treturn nil, false
+\t\t}}
+\t}}
+\treturn params, true
+}}
+
+func (s *FixtureServer) serve(w http.ResponseWriter, req *http.Request) {{
+\tbody, _ := io.ReadAll(req.Body)
+\treq.Body = io.NopCloser(bytes.NewReader(body))
+
+\ts.mu.Lock()
+\tvar (
+\t\tbest       *route
+\t\tparams     map[string]string
+\t\tpathExists bool
+\t)
+\tfor _, r := range s.routes {{
+\t\tp, ok := r.match(req.URL.Path)
+\t\tif !ok {{
+\t\t\tcontinue
+\t\t}}
+\t\tpathExists = true
+\t\tif r.method == req.Method && (best == nil || r.literals > best.literals) {{
+\t\t\tbest, params = r, p
+\t\t}}
+\t}}
+\trecorded := Request{{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery, Header: req.Header.Clone(), Body: body}}
+\tvar (
+\t\thandler http.Handler
+\t\tsamples []Sample
+\t)
+\tif best != nil {{
+\t\trecorded.Endpoint = best.method + " " + best.pattern
+\t\thandler, samples = best.handler, best.samples
+\t}}
+\ts.requests = append(s.requests, recorded)
+\ts.mu.Unlock()
+
+\tswitch {{
+\tcase best == nil && pathExists:
+\t\twriteError(w, http.StatusMethodNotAllowed, fmt.Sprintf("no captured %s for %s", req.Method, req.URL.Path))
+\tcase best == nil:
+\t\twriteError(w, http.StatusNotFound, fmt.Sprintf("no captured endpoint for %s %s", req.Method, req.URL.Path))
+\tcase handler != nil:
+\t\thandler.ServeHTTP(w, req)
+\tcase len(samples) == 0:
+\t\tw.WriteHeader(http.StatusNoContent)
+\tdefault:
+\t\twriteSample(w, pickSample(samples, params, req.URL.RawQuery), params)
+\t}}
+}}
+
+func pickSample(samples []Sample, params map[string]string, query string) Sample {{
+\tbest, bestScore := samples[0], -1
+\tfor _, sample := range samples {{
+\t\tscore := 0
+\t\tif sameParams(sample.Params, params) {{
+\t\t\tscore += 4
+\t\t}}
+\t\tif sample.Query == query {{
+\t\t\tscore += 2
+\t\t}}
+\t\tif sample.Status >= 200 && sample.Status < 300 {{
+\t\t\tscore++
+\t\t}}
+\t\tif score > bestScore {{
+\t\t\tbest, bestScore = sample, score
+\t\t}}
+\t}}
+\treturn best
+}}
+
+func sameParams(a, b map[string]string) bool {{
+\tif len(a) != len(b) {{
+\t\treturn false
+\t}}
+\tfor name, value := range a {{
+\t\tif b[name] != value {{
+\t\t\treturn false
+\t\t}}
+\t}}
+\treturn true
+}}
+
+func writeSample(w http.ResponseWriter, sample Sample, params map[string]string) {{
+\tbody := []byte(sample.Body)
+\tif sample.Encoding == "base64" {{
+\t\tbody, _ = base64.StdEncoding.DecodeString(sample.Body)
+\t}} else if !sameParams(sample.Params, params) {{
+\t\tbody = substituteParams(body, sample.Params, params)
+\t}}
+\tfor name, value := range sample.Headers {{
+\t\tw.Header().Set(name, value)
+\t}}
+\tstatus := sample.Status
+\tif status == 0 {{
+\t\tstatus = http.StatusOK
+\t}}
+\tw.WriteHeader(status)
+\tw.Write(body)
+}}
+
+// substituteParams rewrites values in a JSON body that equal a captured
+// path parameter to the requested value, keeping numbers numeric.
+func substituteParams(body []byte, captured, requested map[string]string) []byte {{
+\treplacements := make(map[string]string)
+\tfor name, value := range captured {{
+\t\tif value != "" && requested[name] != "" {{
+\t\t\treplacements[value] = requested[name]
+\t\t}}
+\t}}
+\tif len(replacements) == 0 {{
+\t\treturn body
+\t}}
+\tdec := json.NewDecoder(bytes.NewReader(body))
+\tdec.UseNumber()
+\tvar doc interface{{}}
+\tif dec.Decode(&doc) != nil {{
+\t\treturn body
+\t}}
+\tout, err := json.Marshal(substitute(doc, replacements))
+\tif err != nil {{
+\t\treturn body
+\t}}
+\treturn out
+}}
+
+func substitute(v interface{{}}, replacements map[string]string) interface{{}} {{
+\tswitch v := v.(type) {{
+\tcase map[string]interface{{}}:
+\t\tfor key, child := range v {{
+\t\t\tv[key] = substitute(child, replacements)
+\t\t}}
+\tcase []interface{{}}:
+\t\tfor i, child := range v {{
+\t\t\tv[i] = substitute(child, replacements)
+\t\t}}
+\tcase string:
+\t\tif replacement, ok := replacements[v]; ok {{
+\t\t\treturn replacement
+\t\t}}
+\tcase json.Number:
+\t\tif replacement, ok := replacements[v.String()]; ok {{
+\t\t\tif _, err := json.Number(replacement).Float64(); err == nil {{
+\t\t\t\treturn json.Number(replacement)
+\t\t\t}}
+\t\t\treturn replacement
+\t\t}}
+\t}}
+\treturn v
+}}
+
+func writeError(w http.ResponseWriter, status int, message string) {{
+\tw.Header().Set("Content-Type", "application/json")
+\tw.WriteHeader(status)
+\tjson.NewEncoder(w).Encode(map[string]string{{"error": message}})
+}}
+"""
+    
+    def _generate_go_fake(self) -> str:
+        return """// Package fake is an in-memory implementation of the API with real CRUD
+// semantics: created resources get ids and timestamps, lists paginate, and
+// updates and deletes are visible to later calls. Install it on any client
+// to run business logic without a network:
+//
+//\tapi := fake.New()
+//\tclient.HTTPClient.Transport = api.Transport()
+//
+// Integration tests that need a real endpoint, to exercise the client's
+// transport or another process, serve it over HTTP with Listen instead.
+// Resources and their routes were derived from the captured traffic and are
+// listed in resources.json.
+package fake
+
+import (
+\t"bytes"
+\t"crypto/rand"
+\t_ "embed"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/http/httptest"
+\t"sort"
+\t"strconv"
+\t"strings"
+\t"sync"
+\t"testing"
+\t"time"
+)
+
+//go:embed resources.json
+var resourcesJSON []byte
+
+// Resource describes one captured collection.
+type Resource struct {
+\tName       string   `json:"name"`
+\tCollection string   `json:"collection"`
+\tItem       string   `json:"item,omitempty"`
+\tIDField    string   `json:"id_field"`
+\tIDKind     string   `json:"id_kind"`
+\tListKey    string   `json:"list_key,omitempty"`
+\tListMeta   []string `json:"list_meta,omitempty"`
+\t// DefaultLimit is the page size when a list request sets none.
+\tDefaultLimit int                    `json:"default_limit,omitempty"`
+\tCreatedAt    string                 `json:"created_at,omitempty"`
+\tUpdatedAt    string                 `json:"updated_at,omitempty"`
+\tDefaults     map[string]interface{} `json:"defaults,omitempty"`
+\tWriteOnly    []string               `json:"write_only,omitempty"`
+\tRoutes       []string               `json:"routes"`
+\tStatuses     map[string]int         `json:"statuses,omitempty"`
+}
+
+// Object is a stored resource instance.
+type Object = map[string]interface{}
+
+type collection struct {
+\tresource *Resource
+\torder    []string
+\titems    map[string]Object
+\tnextID   int64
+}
+
+// Server holds the in-memory state. It is an http.Handler and is safe for
+// concurrent use.
+type Server struct {
+\tmu          sync.Mutex
+\tnow         func() time.Time
+\tcollections []*collection
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithClock sets the clock used for created/updated timestamps.
+func WithClock(now func() time.Time) Option {
+\treturn func(s *Server) { s.now = now }
+}
+
+// New creates an empty fake.
+func New(opts ...Option) *Server {
+\tvar resources []*Resource
+\tif err := json.Unmarshal(resourcesJSON, &resources); err != nil {
+\t\tpanic(fmt.Sprintf("fake: invalid embedded resources: %v", err))
+\t}
+\ts := &Server{now: time.Now}
+\tfor _, r := range resources {
+\t\ts.collections = append(s.collections, &collection{resource: r, items: make(map[string]Object)})
+\t}
+\tfor _, opt := range opts {
+\t\topt(s)
+\t}
+\treturn s
+}
+
+// Resources lists the resources the fake implements.
+func (s *Server) Resources() []string {
+\tnames := make([]string, len(s.collections))
+\tfor i, c := range s.collections {
+\t\tnames[i] = c.resource.Name
+\t}
+\tsort.Strings(names)
+\treturn names
+}
+
+// Transport returns an http.RoundTripper that serves requests in memory.
+func (s *Server) Transport() http.RoundTripper {
+\treturn roundTripper{s}
+}
+
+type roundTripper struct{ s *Server }
+
+// Listen serves the fake on a local httptest.Server until the test ends and
+// returns its URL, the base URL of a client talking to it over HTTP:
+//
+//\tserver := fake.New()
+//\tclient := api.New""" + self.class_name + """Client(server.Listen(t))
+func (s *Server) Listen(t testing.TB) string {
+\tsrv := httptest.NewServer(s)
+\tt.Cleanup(srv.Close)
+\treturn srv.URL
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+\trec := httptest.NewRecorder()
+\trt.s.ServeHTTP(rec, req)
+\tresp := rec.Result()
+\tresp.Request = req
+\treturn resp, nil
+}
+
+// Seed stores objects in a resource as if they had been created, assigning
+// ids and timestamps where missing, and returns the stored copies.
+func (s *Server) Seed(resource string, objects ...Object) ([]Object, error) {
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tc := s.collection(resource)
+\tif c == nil {
+\t\treturn nil, fmt.Errorf("fake: unknown resource %q", resource)
+\t}
+\tstored := make([]Object, len(objects))
+\tfor i, object := range objects {
+\t\tstored[i] = copyObject(c.create(object, s.now()))
+\t}
+\treturn stored, nil
+}
+
+// Get returns a copy of a stored object.
+func (s *Server) Get(resource, id string) (Object, bool) {
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tc := s.collection(resource)
+\tif c == nil || c.items[id] == nil {
+\t\treturn nil, false
+\t}
+\treturn copyObject(c.items[id]), true
+}
+
+// List returns copies of all objects of a resource in creation order.
+func (s *Server) List(resource string) []Object {
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tc := s.collection(resource)
+\tif c == nil {
+\t\treturn nil
+\t}
+\tobjects := make([]Object, 0, len(c.order))
+\tfor _, id := range c.order {
+\t\tobjects = append(objects, copyObject(c.items[id]))
+\t}
+\treturn objects
+}
+
+// Reset deletes all stored objects.
+func (s *Server) Reset() {
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tfor _, c := range s.collections {
+\t\tc.order, c.items, c.nextID = nil, make(map[string]Object), 0
+\t}
+}
+
+func (s *Server) collection(name string) *collection {
+\tfor _, c := range s.collections {
+\t\tif c.resource.Name == name {
+\t\t\treturn c
+\t\t}
+\t}
+\treturn nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+
+\tpath := strings.TrimSuffix(req.URL.Path, "/")
+\tfor _, c := range s.collections {
+\t\tr := c.resource
+\t\tif path == r.Collection {
+\t\t\ts.serveCollection(w, req, c)
+\t\t\treturn
+\t\t}
+\t\tif id, ok := matchItem(r.Item, path); ok {
+\t\t\ts.serveItem(w, req, c, r.Item, id)
+\t\t\treturn
+\t\t}
+\t}
+\twriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+func matchItem(pattern, path string) (string, bool) {
+\tif pattern == "" {
+\t\treturn "", false
+\t}
+\tprefix := pattern[:strings.LastIndex(pattern, "/")+1]
+\tid := strings.TrimPrefix(path, prefix)
+\tif !strings.HasPrefix(path, prefix) || id == "" || strings.Contains(id, "/") {
+\t\treturn "", false
+\t}
+\treturn id, true
+}
+
+func (s *Server) allowed(w http.ResponseWriter, r *Resource, method, route string) bool {
+\tfor _, allowed := range r.Routes {
+\t\tif allowed == method+" "+route {
+\t\t\treturn true
+\t\t}
+\t}
+\twriteJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": method + " not supported on " + route})
+\treturn false
+}
+
+func status(r *Resource, method string, fallback int) int {
+\tif code, ok := r.Statuses[method]; ok {
+\t\treturn code
+\t}
+\treturn fallback
+}
+
+func (s *Server) serveCollection(w http.ResponseWriter, req *http.Request, c *collection) {
+\tr := c.resource
+\tif !s.allowed(w, r, req.Method, r.Collection) {
+\t\treturn
+\t}
+\tswitch req.Method {
+\tcase http.MethodGet:
+\t\ts.list(w, req, c)
+\tcase http.MethodPost:
+\t\tbody, ok := readObject(w, req)
+\t\tif !ok {
+\t\t\treturn
+\t\t}
+\t\twriteJSON(w, status(r, http.MethodPost, http.StatusCreated), c.create(body, s.now()))
+\tdefault:
+\t\twriteJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+\t}
+}
+
+func (s *Server) serveItem(w http.ResponseWriter, req *http.Request, c *collection, route, id string) {
+\tr := c.resource
+\tif !s.allowed(w, r, req.Method, route) {
+\t\treturn
+\t}
+\tobject := c.items[id]
+\tif object == nil {
+\t\twriteJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("%s %s not found", r.Name, id)})
+\t\treturn
+\t}
+\tswitch req.Method {
+\tcase http.MethodGet:
+\t\twriteJSON(w, status(r, http.MethodGet, http.StatusOK), object)
+\tcase http.MethodPut, http.MethodPatch:
+\t\tbody, ok := readObject(w, req)
+\t\tif !ok {
+\t\t\treturn
+\t\t}
+\t\tif req.Method == http.MethodPut {
+\t\t\treplaced := Object{r.IDField: object[r.IDField]}
+\t\t\tif r.CreatedAt != "" {
+\t\t\t\treplaced[r.CreatedAt] = object[r.CreatedAt]
+\t\t\t}
+\t\t\tobject = replaced
+\t\t}
+\t\tfor key, value := range body {
+\t\t\tif key != r.IDField && key != r.CreatedAt {
+\t\t\t\tobject[key] = value
+\t\t\t}
+\t\t}
+\t\tfor _, key := range r.WriteOnly {
+\t\t\tdelete(object, key)
+\t\t}
+\t\tif r.UpdatedAt != "" {
+\t\t\tobject[r.UpdatedAt] = s.now().UTC().Format(time.RFC3339)
+\t\t}
+\t\tc.items[id] = object
+\t\twriteJSON(w, status(r, req.Method, http.StatusOK), object)
+\tcase http.MethodDelete:
+\t\tdelete(c.items, id)
+\t\tfor i, existing := range c.order {
+\t\t\tif existing == id {
+\t\t\t\tc.order = append(c.order[:i], c.order[i+1:]...)
+\t\t\t\tbreak
+\t\t\t}
+\t\t}
+\t\tcode := status(r, http.MethodDelete, http.StatusNoContent)
+\t\tif code == http.StatusNoContent {
+\t\t\tw.WriteHeader(code)
+\t\t\treturn
+\t\t}
+\t\twriteJSON(w, code, object)
+\tdefault:
+\t\twriteJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+\t}
+}
+
+// list paginates with page/limit (also per_page, page_size) or
+// offset/limit query parameters.
+func (s *Server) list(w http.ResponseWriter, req *http.Request, c *collection) {
+\tquery := req.URL.Query()
+\tlimit := c.resource.DefaultLimit
+\tif limit <= 0 {
+\t\tlimit = len(c.order)
+\t}
+\tlimit = firstInt(query, limit, "limit", "per_page", "page_size")
+\tpage := firstInt(query, 1, "page")
+\toffset := firstInt(query, (page-1)*limit, "offset")
+\tif limit <= 0 {
+\t\tlimit = len(c.order)
+\t}
+\tif offset < 0 {
+\t\toffset = 0
+\t}
+
+\titems := make([]Object, 0, limit)
+\tfor i := offset; i < len(c.order) && len(items) < limit; i++ {
+\t\titems = append(items, c.items[c.order[i]])
+\t}
+
+\tr := c.resource
+\tif r.ListKey == "" {
+\t\twriteJSON(w, status(r, http.MethodGet, http.StatusOK), items)
+\t\treturn
+\t}
+\tbody := Object{r.ListKey: items}
+\tfor _, meta := range r.ListMeta {
+\t\tswitch meta {
+\t\tcase "total", "count", "total_count":
+\t\t\tbody[meta] = len(c.order)
+\t\tcase "page":
+\t\t\tbody[meta] = page
+\t\tcase "limit", "per_page", "page_size":
+\t\t\tbody[meta] = limit
+\t\tcase "offset":
+\t\t\tbody[meta] = offset
+\t\tcase "has_more":
+\t\t\tbody[meta] = offset+len(items) < len(c.order)
+\t\t}
+\t}
+\twriteJSON(w, status(r, http.MethodGet, http.StatusOK), body)
+}
+
+func firstInt(query map[string][]string, fallback int, names ...string) int {
+\tfor _, name := range names {
+\t\tif values := query[name]; len(values) > 0 {
+\t\t\tif n, err := strconv.Atoi(values[0]); err == nil {
+\t\t\t\treturn n
+\t\t\t}
+\t\t}
+\t}
+\treturn fallback
+}
+
+func (c *collection) create(body Object, now time.Time) Object {
+\tr := c.resource
+\tobject := Object{}
+\tfor key, value := range r.Defaults {
+\t\tobject[key] = value
+\t}
+\tfor key, value := range body {
+\t\tobject[key] = value
+\t}
+\tfor _, key := range r.WriteOnly {
+\t\tdelete(object, key)
+\t}
+\tif _, ok := object[r.IDField]; !ok {
+\t\tc.nextID++
+\t\tswitch r.IDKind {
+\t\tcase "integer":
+\t\t\tobject[r.IDField] = c.nextID
+\t\tcase "uuid":
+\t\t\tobject[r.IDField] = newUUID()
+\t\tdefault:
+\t\t\tobject[r.IDField] = fmt.Sprintf("%s_%d", strings.TrimSuffix(r.Name, "s"), c.nextID)
+\t\t}
+\t}
+\tstamp := now.UTC().Format(time.RFC3339)
+\tif _, ok := object[r.CreatedAt]; !ok && r.CreatedAt != "" {
+\t\tobject[r.CreatedAt] = stamp
+\t}
+\tif _, ok := object[r.UpdatedAt]; !ok && r.UpdatedAt != "" {
+\t\tobject[r.UpdatedAt] = stamp
+\t}
+
+\tid := fmt.Sprint(object[r.IDField])
+\tif n, err := strconv.ParseInt(id, 10, 64); err == nil && n > c.nextID {
+\t\tc.nextID = n
+\t}
+\tif _, exists := c.items[id]; !exists {
+\t\tc.order = append(c.order, id)
+\t}
+\tc.items[id] = object
+\treturn object
+}
+
+func newUUID() string {
+\tb := make([]byte, 16)
+\trand.Read(b)
+\tb[6] = b[6]&0x0f | 0x40
+\tb[8] = b[8]&0x3f | 0x80
+\treturn fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func readObject(w http.ResponseWriter, req *http.Request) (Object, bool) {
+\tdata, err := io.ReadAll(req.Body)
+\tif err != nil {
+\t\twriteJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+\t\treturn nil, false
+\t}
+\tobject := Object{}
+\tif len(bytes.TrimSpace(data)) == 0 {
+\t\treturn object, true
+\t}
+\tdec := json.NewDecoder(bytes.NewReader(data))
+\tdec.UseNumber()
+\tif err := dec.Decode(&object); err != nil {
+\t\twriteJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body: " + err.Error()})
+\t\treturn nil, false
+\t}
+\treturn object, true
+}
+
+func copyObject(object Object) Object {
+\tdata, _ := json.Marshal(object)
+\tvar copied Object
+\tdec := json.NewDecoder(bytes.NewReader(data))
+\tdec.UseNumber()
+\tdec.Decode(&copied)
+\treturn copied
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+\tw.Header().Set("Content-Type", "application/json")
+\tw.WriteHeader(code)
+\tjson.NewEncoder(w).Encode(v)
+}
+"""
+    
+    def _generate_go_sdktest_assert(self, module_path: str) -> str:
+        return """package sdktest
+
+import (
+\t"errors"
+\t"fmt"
+\t"net/http"
+\t"regexp"
+\t"strconv"
+\t"strings"
+\t"testing"
+
+\t\"""" + module_path + """/jsondiff"
+)
+
+// Assertions report failures with t.Errorf and return whether they passed,
+// so a test can stop early with `if !sdktest.Equal(...) { return }`.
+
+// NoError asserts that err is nil.
+func NoError(t testing.TB, err error) bool {
+\tt.Helper()
+\tif err != nil {
+\t\tt.Errorf("unexpected error: %v", err)
+\t\treturn false
+\t}
+\treturn true
+}
+
+// Equal asserts that want and got encode to the same JSON, reporting each
+// differing field by path ("users[0].email: want ..., got ...").
+func Equal(t testing.TB, want, got interface{}) bool {
+\tt.Helper()
+\treturn EqualWith(t, want, got, jsondiff.Options{})
+}
+
+// EqualWith is Equal with ignore rules, for golden responses holding
+// timestamps, identifiers, or arrays in no particular order:
+//
+//\tsdktest.EqualWith(t, golden, users, jsondiff.Options{IgnoreTimestamps: true})
+func EqualWith(t testing.TB, want, got interface{}, opts jsondiff.Options) bool {
+\tt.Helper()
+\tdiffs, err := jsondiff.Compare(want, got, opts)
+\tif err != nil {
+\t\tt.Errorf("cannot compare: %v", err)
+\t\treturn false
+\t}
+\tif len(diffs) > 0 {
+\t\tt.Errorf("values differ:\\n  %s", strings.Join(describeDiffs(diffs), "\\n  "))
+\t\treturn false
+\t}
+\treturn true
+}
+
+// Diff lists the fields in which want and got differ after JSON encoding,
+// which makes SDK structs, maps and raw JSON comparable with each other.
+func Diff(want, got interface{}) ([]string, error) {
+\tdiffs, err := jsondiff.Compare(want, got, jsondiff.Options{})
+\tif err != nil {
+\t\treturn nil, err
+\t}
+\treturn describeDiffs(diffs), nil
+}
+
+// JSONSubset asserts that every field in the JSON document want is present
+// in got with an equal value; fields of got not mentioned in want are
+// ignored. got may be raw JSON ([]byte, string, json.RawMessage) or any
+// value that encodes to JSON, such as an SDK response.
+func JSONSubset(t testing.TB, want string, got interface{}) bool {
+\tt.Helper()
+\tdiffs, err := jsondiff.Compare(want, got, jsondiff.Options{Subset: true})
+\tif err != nil {
+\t\tt.Errorf("cannot compare: %v", err)
+\t\treturn false
+\t}
+\tif len(diffs) > 0 {
+\t\tt.Errorf("JSON does not contain expected fields:\\n  %s", strings.Join(describeDiffs(diffs), "\\n  "))
+\t\treturn false
+\t}
+\treturn true
+}
+
+// describeDiffs words differences between want (A) and got (B).
+func describeDiffs(diffs []jsondiff.Difference) []string {
+\tlines := make([]string, len(diffs))
+\tfor i, d := range diffs {
+\t\tpath := strings.TrimPrefix(strings.TrimPrefix(d.Path, "$"), ".")
+\t\tif path == "" {
+\t\t\tpath = "(root)"
+\t\t}
+\t\tswitch d.Kind {
+\t\tcase jsondiff.Removed:
+\t\t\tlines[i] = fmt.Sprintf("%s: missing, want %s", path, jsondiff.Describe(d.A))
+\t\tcase jsondiff.Added:
+\t\t\tlines[i] = fmt.Sprintf("%s: unexpected %s", path, jsondiff.Describe(d.B))
+\t\tdefault:
+\t\t\tlines[i] = fmt.Sprintf("%s: want %s, got %s", path, jsondiff.Describe(d.A), jsondiff.Describe(d.B))
+\t\t}
+\t}
+\treturn lines
+}
+
+// Status asserts the status code of a response.
+func Status(t testing.TB, resp *http.Response, want int) bool {
+\tt.Helper()
+\tif resp == nil {
+\t\tt.Errorf("status: want %d, got no response", want)
+\t\treturn false
+\t}
+\tif resp.StatusCode != want {
+\t\tt.Errorf("status: want %d, got %d", want, resp.StatusCode)
+\t\treturn false
+\t}
+\treturn true
+}
+
+var statusPattern = regexp.MustCompile(`status=(\\d{3})`)
+
+// ErrorStatus asserts that err is an API error carrying the HTTP status
+// want.
+func ErrorStatus(t testing.TB, err error, want int) bool {
+\tt.Helper()
+\tif err == nil {
+\t\tt.Errorf("want API error with status %d, got nil", want)
+\t\treturn false
+\t}
+\tgot, ok := StatusOf(err)
+\tif !ok {
+\t\tt.Errorf("want API error with status %d, got %v", want, err)
+\t\treturn false
+\t}
+\tif got != want {
+\t\tt.Errorf("error status: want %d, got %d (%v)", want, got, err)
+\t\treturn false
+\t}
+\treturn true
+}
+
+// StatusOf extracts the HTTP status from an error returned by the client.
+func StatusOf(err error) (int, bool) {
+\tvar coded interface{ StatusCode() int }
+\tif errors.As(err, &coded) {
+\t\treturn coded.StatusCode(), true
+\t}
+\ttext := err.Error()
+\tvar detailed interface{ Detail() string }
+\tif errors.As(err, &detailed) {
+\t\ttext = detailed.Detail()
+\t}
+\tif m := statusPattern.FindStringSubmatch(text); m != nil {
+\t\tstatus, _ := strconv.Atoi(m[1])
+\t\treturn status, true
+\t}
+\treturn 0, false
+}
+"""
+    
+    def _generate_go_debug(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/url"
+\t"sort"
+\t"strings"
+\t"sync"
+\t"time"
+)
+
+// WithDebug writes every request to w as a copy-pasteable curl command,
+// followed by the raw response. Credentials are replaced by shell
+// variables ("Authorization: $AUTHORIZATION"), so the command can be re-run
+// after exporting them, and credential query parameters are redacted.
+func WithDebug(w io.Writer) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.debug = &debugLogger{{w: w}}
+\t}}
+}}
+
+// maxDebugBody caps how much of each body is written by WithDebug.
+const maxDebugBody = 64 << 10
+
+var sensitiveHeaders = map[string]bool{{
+\t"Authorization":       true,
+\t"Proxy-Authorization": true,
+\t"Cookie":              true,
+\t"Set-Cookie":          true,
+\t"X-Api-Key":           true,
+\t"Api-Key":             true,
+\t"X-Auth-Token":        true,
+\t"X-Access-Token":      true,
+\t"X-Csrf-Token":        true,
+}}
+
+var sensitiveParams = map[string]bool{{
+\t"api_key":       true,
+\t"apikey":        true,
+\t"access_token":  true,
+\t"token":         true,
+\t"key":           true,
+\t"signature":     true,
+\t"sig":           true,
+\t"client_secret": true,
+}}
+
+// isSensitiveHeader reports whether a header usually carries credentials,
+// or carries an API key set with WithAPIKeyHeader.
+func isSensitiveHeader(name string) bool {{
+\tname = http.CanonicalHeaderKey(name)
+\t_, credential := credentialHeaders.Load(name)
+\treturn sensitiveHeaders[name] || credential
+}}
+
+// isSecretHeader reports whether a header is sensitive or carries one of
+// the client's credentials.
+func (c *{self.class_name}Client) isSecretHeader(name string) bool {{
+\tif isSensitiveHeader(name) {{
+\t\treturn true
+\t}}
+\tfor _, credential := range c.credentials {{
+\t\tif http.CanonicalHeaderKey(credential.header) == http.CanonicalHeaderKey(name) {{
+\t\t\treturn true
+\t\t}}
+\t}}
+\treturn false
+}}
+
+// callLogger logs the attempts and retries of calls; WithLogger sets one
+// on Go 1.21 and later.
+type callLogger interface {{
+\tlogAttempt(ctx context.Context, operation string, attempt int, req *http.Request, resp *http.Response, took time.Duration, err error)
+\tlogRetry(ctx context.Context, operation string, attempt int, wait time.Duration, err error)
+}}
+
+// redactQuery returns a copy of u with credential query parameters
+// replaced by REDACTED.
+func redactQuery(u *url.URL) *url.URL {{
+\tredacted := *u
+\tif u.RawQuery == "" {{
+\t\treturn &redacted
+\t}}
+\tquery := u.Query()
+\tfor name := range query {{
+\t\t_, credential := credentialParams.Load(strings.ToLower(name))
+\t\tif sensitiveParams[strings.ToLower(name)] || credential {{
+\t\t\tquery.Set(name, "REDACTED")
+\t\t}}
+\t}}
+\tredacted.RawQuery = query.Encode()
+\treturn &redacted
+}}
+
+type debugLogger struct {{
+\tmu sync.Mutex
+\tw  io.Writer
+}}
+
+// log writes one exchange; it is a no-op on a nil logger so call sites
+// need no checks.
+func (d *debugLogger) log(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error) {{
+\tif d == nil {{
+\t\treturn
+\t}}
+\tvar b strings.Builder
+\tb.WriteString(curlCommand(req, reqBody))
+\tb.WriteString("\\n")
+\tif resp != nil {{
+\t\tfmt.Fprintf(&b, "< %s %s\\n", resp.Proto, resp.Status)
+\t\tfor _, name := range sortedKeys(resp.Header) {{
+\t\t\tfor _, value := range resp.Header[name] {{
+\t\t\t\tif isSensitiveHeader(name) {{
+\t\t\t\t\tvalue = "REDACTED"
+\t\t\t\t}}
+\t\t\t\tfmt.Fprintf(&b, "< %s: %s\\n", name, value)
+\t\t\t}}
+\t\t}}
+\t\tb.WriteString("<\\n")
+\t\tb.WriteString(truncateBody(respBody))
+\t\tb.WriteString("\\n")
+\t}}
+\tif err != nil {{
+\t\tfmt.Fprintf(&b, "# error: %v\\n", err)
+\t}}
+\tb.WriteString("\\n")
+
+\td.mu.Lock()
+\tdefer d.mu.Unlock()
+\tio.WriteString(d.w, b.String())
+}}
+
+func curlCommand(req *http.Request, body []byte) string {{
+\tparts := []string{{"curl"}}
+\tif req.Method != http.MethodGet {{
+\t\tparts = append(parts, "-X "+req.Method)
+\t}}
+\tparts = append(parts, shellQuote(redactQuery(req.URL).String()))
+\tfor _, name := range sortedKeys(req.Header) {{
+\t\tfor _, value := range req.Header[name] {{
+\t\t\tif isSensitiveHeader(name) {{
+\t\t\t\tvariable := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+\t\t\t\tparts = append(parts, fmt.Sprintf(`-H "%s: $%s"`, name, variable))
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tparts = append(parts, "-H "+shellQuote(name+": "+value))
+\t\t}}
+\t}}
+\tif len(body) > 0 {{
+\t\tparts = append(parts, "--data-raw "+shellQuote(truncateBody(body)))
+\t}}
+\treturn strings.Join(parts, " \\\\\\n  ")
+}}
+
+func shellQuote(s string) string {{
+\treturn "'" + strings.ReplaceAll(s, "'", `'\\''`) + "'"
+}}
+
+func truncateBody(body []byte) string {{
+\tif len(body) <= maxDebugBody {{
+\t\treturn string(body)
+\t}}
+\treturn fmt.Sprintf("%s... (%d bytes truncated)", body[:maxDebugBody], len(body)-maxDebugBody)
+}}
+
+func sortedKeys(header http.Header) []string {{
+\tkeys := make([]string, 0, len(header))
+\tfor key := range header {{
+\t\tkeys = append(keys, key)
+\t}}
+\tsort.Strings(keys)
+\treturn keys
+}}
+"""
+    
+    def _generate_go_dryrun(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"net/http"
+\t"net/url"
+)
+
+// DryRun returns a client whose calls prepare their requests, with body,
+// headers, credentials, and signature, and return them in a *DryRunError
+// instead of sending them. It shares everything else with c, as derived
+// clients do:
+//
+//\t_, err := client.DryRun().CreateUser(ctx, data)
+//\tvar dryRun *DryRunError
+//\tif errors.As(err, &dryRun) {{
+//\t\tfmt.Println(dryRun.Preview.Curl())
+//\t}}
+func (c *{self.class_name}Client) DryRun() *{self.class_name}Client {{
+\treturn c.derive(WithDryRun())
+}}
+
+// WithDryRun makes the call a dry run, as the calls of a DryRun client are.
+func WithDryRun() RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.dryRun = true
+\t}}
+}}
+
+// DryRunError is returned by a dry-run call, with the request it prepared.
+// Middleware and transports see requests only as they are sent, so their
+// changes are not in it.
+type DryRunError struct {{
+\t// Request is the prepared request, credentials included. Its body, if
+\t// any, is unread, so sending the request makes the call.
+\tRequest *http.Request
+\t// Preview describes Request with its credentials redacted.
+\tPreview RequestPreview
+}}
+
+func (e *DryRunError) Error() string {{
+\treturn fmt.Sprintf("%s: dry run, request to %s not sent", e.Preview.Operation, e.Preview.URL)
+}}
+
+// RequestPreview describes a prepared request in a form fit to log or to
+// marshal as JSON. Credential headers and query parameters are REDACTED.
+type RequestPreview struct {{
+\tOperation string      `json:"operation"` // "POST /v1/users"
+\tMethod    string      `json:"method"`
+\tURL       string      `json:"url"`
+\tHeader    http.Header `json:"header"`
+\t// Body is the encoded body, before compression; it is empty for
+\t// streamed bodies such as uploads.
+\tBody string `json:"body,omitempty"`
+}}
+
+// Curl returns the request as a curl command, with credentials in shell
+// variables as WithDebug writes them.
+func (p RequestPreview) Curl() string {{
+\tu, err := url.Parse(p.URL)
+\tif err != nil {{
+\t\tu = &url.URL{{Opaque: p.URL}}
+\t}}
+\treturn curlCommand(&http.Request{{Method: p.Method, URL: u, Header: p.Header}}, []byte(p.Body))
+}}
+
+// dryRun returns the *DryRunError of a dry-run call of operation, which
+// prepared req with body.
+func (c *{self.class_name}Client) dryRun(operation string, req *http.Request, body []byte) error {{
+\theader := req.Header.Clone()
+\tfor name, values := range header {{
+\t\tif c.isSecretHeader(name) {{
+\t\t\tfor i := range values {{
+\t\t\t\tvalues[i] = "REDACTED"
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn &DryRunError{{
+\t\tRequest: req,
+\t\tPreview: RequestPreview{{
+\t\t\tOperation: operation,
+\t\t\tMethod:    req.Method,
+\t\t\tURL:       redactQuery(req.URL).String(),
+\t\t\tHeader:    header,
+\t\t\tBody:      string(body),
+\t\t}},
+\t}}
+}}
+"""
+    
+    def _generate_go_dump(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/http/httputil"
+\t"os"
+\t"sync"
+\t"sync/atomic"
+)
+
+// DumpTransport writes wire-level dumps of requests and responses, as
+// produced by httputil.DumpRequestOut and httputil.DumpResponse, for
+// diagnosing encoding and header issues. Credentials are redacted and bodies
+// are capped. Dumping is off until enabled and can be toggled at runtime,
+// for example from an admin endpoint in production:
+//
+//\tdump := NewDumpTransport(nil, os.Stderr)
+//\tclient := New{self.class_name}Client("", WithDumpTransport(dump))
+//\tdump.SetEnabled(true)
+type DumpTransport struct {{
+\t// Next performs the requests; nil means the client's transport when
+\t// installed with WithDumpTransport, else http.DefaultTransport.
+\tNext http.RoundTripper
+\t// MaxBody caps the bytes of each body written (default 4096; negative
+\t// omits bodies).
+\tMaxBody int
+
+\tenabled atomic.Bool
+\tmu      sync.Mutex
+\tout     io.Writer
+}}
+
+// NewDumpTransport creates a disabled DumpTransport writing to out
+// (os.Stderr if nil).
+func NewDumpTransport(next http.RoundTripper, out io.Writer) *DumpTransport {{
+\tif out == nil {{
+\t\tout = os.Stderr
+\t}}
+\treturn &DumpTransport{{Next: next, MaxBody: 4096, out: out}}
+}}
+
+// WithDumpTransport installs dump in front of the client's transport.
+func WithDumpTransport(dump *DumpTransport) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif dump.Next == nil {{
+\t\t\tdump.Next = c.HTTPClient.Transport
+\t\t}}
+\t\tc.HTTPClient.Transport = dump
+\t}}
+}}
+
+// SetEnabled turns dumping on or off; it is safe to call concurrently with
+// requests.
+func (d *DumpTransport) SetEnabled(enabled bool) {{
+\td.enabled.Store(enabled)
+}}
+
+// Enabled reports whether dumping is on.
+func (d *DumpTransport) Enabled() bool {{
+\treturn d.enabled.Load()
+}}
+
+// RoundTrip implements http.RoundTripper.
+func (d *DumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {{
+\tnext := d.Next
+\tif next == nil {{
+\t\tnext = http.DefaultTransport
+\t}}
+\tif !d.Enabled() {{
+\t\treturn next.RoundTrip(req)
+\t}}
+
+\tvar body []byte
+\tif req.Body != nil && req.Body != http.NoBody {{
+\t\tvar err error
+\t\tbody, err = io.ReadAll(req.Body)
+\t\treq.Body.Close()
+\t\tif err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\treq.Body = io.NopCloser(bytes.NewReader(body))
+\t}}
+\trequestDump := d.dumpRequest(req, body)
+
+\tresp, err := next.RoundTrip(req)
+\tif err != nil {{
+\t\td.write(requestDump, []byte(fmt.Sprintf("# error: %v\\n", err)))
+\t\treturn nil, err
+\t}}
+\tresponseDump, err := d.dumpResponse(resp)
+\tif err != nil {{
+\t\tresp.Body.Close()
+\t\treturn nil, err
+\t}}
+\td.write(requestDump, responseDump)
+\treturn resp, nil
+}}
+
+func (d *DumpTransport) dumpRequest(req *http.Request, body []byte) []byte {{
+\tclone := req.Clone(req.Context())
+\tclone.URL = redactQuery(req.URL)
+\tclone.Header = redactHeaders(req.Header)
+\tshown, truncated := d.capBody(body)
+\tclone.Body = io.NopCloser(bytes.NewReader(shown))
+\tclone.ContentLength = int64(len(shown))
+\tclone.GetBody = nil
+
+\tdump, err := httputil.DumpRequestOut(clone, d.MaxBody >= 0)
+\tif err != nil {{
+\t\treturn []byte(fmt.Sprintf("# cannot dump request: %v\\n", err))
+\t}}
+\treturn appendTruncation(dump, truncated)
+}}
+
+// dumpResponse dumps the headers and up to MaxBody bytes of the body,
+// leaving the full body readable by the caller.
+func (d *DumpTransport) dumpResponse(resp *http.Response) ([]byte, error) {{
+\theaders := *resp
+\theaders.Header = redactHeaders(resp.Header)
+\tdump, err := httputil.DumpResponse(&headers, false)
+\tif err != nil {{
+\t\treturn []byte(fmt.Sprintf("# cannot dump response: %v\\n", err)), nil
+\t}}
+\tif d.MaxBody < 0 {{
+\t\treturn dump, nil
+\t}}
+
+\thead, err := io.ReadAll(io.LimitReader(resp.Body, int64(d.MaxBody)+1))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tresp.Body = struct {{
+\t\tio.Reader
+\t\tio.Closer
+\t}}{{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}}
+
+\tif len(head) > d.MaxBody {{
+\t\tdump = append(dump, head[:d.MaxBody]...)
+\t\treturn append(dump, "\\n... (body truncated)"...), nil
+\t}}
+\treturn append(dump, head...), nil
+}}
+
+func (d *DumpTransport) capBody(body []byte) ([]byte, int) {{
+\tif d.MaxBody < 0 || len(body) <= d.MaxBody {{
+\t\treturn body, 0
+\t}}
+\treturn body[:d.MaxBody], len(body) - d.MaxBody
+}}
+
+func appendTruncation(dump []byte, truncated int) []byte {{
+\tif truncated > 0 {{
+\t\tdump = append(dump, fmt.Sprintf("\\n... (%d bytes truncated)", truncated)...)
+\t}}
+\treturn dump
+}}
+
+func (d *DumpTransport) write(request, response []byte) {{
+\td.mu.Lock()
+\tdefer d.mu.Unlock()
+\tfmt.Fprintf(d.out, "%s\\n\\n%s\\n\\n", bytes.TrimSpace(request), bytes.TrimSpace(response))
+}}
+
+func redactHeaders(header http.Header) http.Header {{
+\tredacted := header.Clone()
+\tfor name, values := range redacted {{
+\t\tif isSensitiveHeader(name) {{
+\t\t\tfor i := range values {{
+\t\t\t\tvalues[i] = "REDACTED"
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn redacted
+}}
+"""
+    
+    def _generate_go_stats(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"expvar"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"sync"
+\t"sync/atomic"
+\t"time"
+)
+
+// ClientStats is a snapshot of a client's internal counters.
+type ClientStats struct {{
+\tRequests         int64            `json:"requests"`
+\tRequestErrors    int64            `json:"request_errors"`
+\tInFlight         int64            `json:"in_flight"`
+\tResponses        map[string]int64 `json:"responses"`
+\tRetries          int64            `json:"retries"`
+\tBytesSent        int64            `json:"bytes_sent"`
+\tBytesReceived    int64            `json:"bytes_received"`
+\tSchemaMismatches int64            `json:"schema_mismatches"`
+\t// Endpoints breaks the counters down by operation.
+\tEndpoints map[string]EndpointStats `json:"endpoints,omitempty"`
+}}
+
+// EndpointStats summarizes the calls to one operation since the client was
+// created. Latencies are the time until the response headers arrived;
+// errors are requests that got no response or an error status. Bytes are
+// those of the bodies on the wire, so compressed ones count as compressed.
+type EndpointStats struct {{
+\tRequests      int64            `json:"requests"`
+\tErrors        int64            `json:"errors"`
+\tErrorRate     float64          `json:"error_rate"`
+\tResponses     map[string]int64 `json:"responses"`
+\tRetries       int64            `json:"retries"`
+\tBytesSent     int64            `json:"bytes_sent"`
+\tBytesReceived int64            `json:"bytes_received"`
+\t// Latency is the total latency of the requests that got a response;
+\t// divided by their number it is the mean.
+\tLatency time.Duration `json:"latency"`
+\t// P50, P95, and P99 are zero unless the client was created
+\t// WithLatencyStats.
+\tP50 time.Duration `json:"p50"`
+\tP95 time.Duration `json:"p95"`
+\tP99 time.Duration `json:"p99"`
+\t// Captured is the median latency recorded in the capture the SDK was
+\t// generated from, or zero if the capture had no timings.
+\tCaptured time.Duration `json:"captured,omitempty"`
+}}
+
+type clientStats struct {{
+\trequests atomic.Int64
+\terrors   atomic.Int64
+\tinFlight atomic.Int64
+\tretries  atomic.Int64
+\tsent     atomic.Int64
+\treceived atomic.Int64
+
+\tmu        sync.Mutex
+\tresponses map[string]int64
+\tendpoints map[string]*endpointStats
+\tlatency   bool // set by WithLatencyStats
+}}
+
+// endpointStats counts the requests to one operation. Its byte counts are
+// added to as bodies are read, without holding clientStats.mu.
+type endpointStats struct {{
+\trequests  int64
+\terrors    int64
+\tretries   int64
+\tresponses map[string]int64
+\tlatency   time.Duration
+\tdigest    *tdigest // nil unless WithLatencyStats
+\tsent      atomic.Int64
+\treceived  atomic.Int64
+}}
+
+// endpoint returns the counters of operation, creating them on first use.
+// s.mu must be held.
+func (s *clientStats) endpoint(operation string) *endpointStats {{
+\tif s.endpoints == nil {{
+\t\ts.endpoints = make(map[string]*endpointStats)
+\t}}
+\te := s.endpoints[operation]
+\tif e == nil {{
+\t\te = &endpointStats{{responses: make(map[string]int64)}}
+\t\tif s.latency {{
+\t\t\te.digest = &tdigest{{}}
+\t\t}}
+\t\ts.endpoints[operation] = e
+\t}}
+\treturn e
+}}
+
+// requestStats tracks one request between clientStats.start and finish.
+type requestStats struct {{
+\tstats    *clientStats
+\tendpoint *endpointStats
+\tstarted  time.Time
+}}
+
+// start records a request to operation about to be sent, and counts the
+// bytes of its body as the transport reads them.
+func (s *clientStats) start(operation string, req *http.Request) *requestStats {{
+\ts.requests.Add(1)
+\ts.inFlight.Add(1)
+\ts.mu.Lock()
+\te := s.endpoint(operation)
+\ts.mu.Unlock()
+\tif req.Body != nil && req.Body != http.NoBody {{
+\t\treq.Body = &countingBody{{req.Body, &s.sent, &e.sent}}
+\t}}
+\treturn &requestStats{{stats: s, endpoint: e, started: time.Now()}}
+}}
+
+// finish records the response to the request, nil when none arrived, and
+// counts the bytes of its body as they are read.
+func (r *requestStats) finish(resp *http.Response) {{
+\ts, e := r.stats, r.endpoint
+\ts.inFlight.Add(-1)
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\te.requests++
+\tif resp == nil {{
+\t\ts.errors.Add(1)
+\t\te.errors++
+\t\treturn
+\t}}
+\tif resp.StatusCode >= 400 {{
+\t\te.errors++
+\t}}
+\ttook := time.Since(r.started)
+\te.latency += took
+\tif e.digest != nil {{
+\t\te.digest.add(float64(took))
+\t}}
+\tclass := fmt.Sprintf("%dxx", resp.StatusCode/100)
+\tif s.responses == nil {{
+\t\ts.responses = make(map[string]int64)
+\t}}
+\ts.responses[class]++
+\te.responses[class]++
+\t// A switched protocol's body is the connection itself.
+\tif resp.StatusCode != http.StatusSwitchingProtocols {{
+\t\tresp.Body = &countingBody{{resp.Body, &s.received, &e.received}}
+\t}}
+}}
+
+// retry records that a call to operation is about to be retried.
+func (s *clientStats) retry(operation string) {{
+\ts.retries.Add(1)
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\ts.endpoint(operation).retries++
+}}
+
+// countingBody adds the bytes read through it to a client's total and to
+// an operation's.
+type countingBody struct {{
+\tio.ReadCloser
+\ttotal, endpoint *atomic.Int64
+}}
+
+func (b *countingBody) Read(p []byte) (int, error) {{
+\tn, err := b.ReadCloser.Read(p)
+\tb.total.Add(int64(n))
+\tb.endpoint.Add(int64(n))
+\treturn n, err
+}}
+
+func (e *endpointStats) snapshot(operation string) EndpointStats {{
+\tstats := EndpointStats{{
+\t\tRequests:      e.requests,
+\t\tErrors:        e.errors,
+\t\tResponses:     make(map[string]int64, len(e.responses)),
+\t\tRetries:       e.retries,
+\t\tBytesSent:     e.sent.Load(),
+\t\tBytesReceived: e.received.Load(),
+\t\tLatency:       e.latency,
+\t\tCaptured:      capturedLatencies[operation],
+\t}}
+\tfor class, count := range e.responses {{
+\t\tstats.Responses[class] = count
+\t}}
+\tif e.requests > 0 {{
+\t\tstats.ErrorRate = float64(e.errors) / float64(e.requests)
+\t}}
+\tif e.digest != nil {{
+\t\tstats.P50 = time.Duration(e.digest.quantile(0.50))
+\t\tstats.P95 = time.Duration(e.digest.quantile(0.95))
+\t\tstats.P99 = time.Duration(e.digest.quantile(0.99))
+\t}}
+\treturn stats
+}}
+
+// Stats returns a snapshot of the client's counters: requests sent,
+// transport failures, requests in flight, responses by status class,
+// retries, body bytes sent and received, and schema mismatches seen while
+// decoding, plus the same by operation with their latencies.
+func (c *{self.class_name}Client) Stats() ClientStats {{
+\tstats := ClientStats{{
+\t\tRequests:      c.stats.requests.Load(),
+\t\tRequestErrors: c.stats.errors.Load(),
+\t\tInFlight:      c.stats.inFlight.Load(),
+\t\tResponses:     make(map[string]int64),
+\t\tRetries:       c.stats.retries.Load(),
+\t\tBytesSent:     c.stats.sent.Load(),
+\t\tBytesReceived: c.stats.received.Load(),
+\t}}
+\tc.stats.mu.Lock()
+\tfor class, count := range c.stats.responses {{
+\t\tstats.Responses[class] = count
+\t}}
+\tif len(c.stats.endpoints) > 0 {{
+\t\tstats.Endpoints = make(map[string]EndpointStats, len(c.stats.endpoints))
+\t\tfor operation, endpoint := range c.stats.endpoints {{
+\t\t\tstats.Endpoints[operation] = endpoint.snapshot(operation)
+\t\t}}
+\t}}
+\tc.stats.mu.Unlock()
+\tfor _, mismatch := range c.SchemaMismatches() {{
+\t\tstats.SchemaMismatches += mismatch.Count
+\t}}
+\treturn stats
+}}
+
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes the client's Stats under name in expvar, for
+// environments that scrape /debug/vars; Metrics serves them to Prometheus.
+// Each client needs its own name; publishing a name twice returns an error instead of
+// panicking like expvar.Publish.
+func (c *{self.class_name}Client) PublishExpvar(name string) error {{
+\texpvarMu.Lock()
+\tdefer expvarMu.Unlock()
+\tif expvar.Get(name) != nil {{
+\t\treturn fmt.Errorf("expvar %q is already published", name)
+\t}}
+\texpvar.Publish(name, expvar.Func(func() interface{{}} {{ return c.Stats() }}))
+\treturn nil
+}}
+"""
+    
+    def _generate_go_metrics(self, package_name: str) -> str:
+        namespace = self._env_prefix().lower()
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"sort"
+\t"strings"
+)
+
+// Metrics serves a client's Stats in the Prometheus text exposition format,
+// so services embedding the client can scrape its traffic without wrapping
+// every call:
+//
+//\thttp.Handle("/metrics/{namespace}", client.Metrics("{namespace}"))
+//
+// Counters are labelled by operation, such as "GET /v1/users/{{id}}", and
+// response counters by status class too. Latency is a summary whose
+// quantiles are present when the client was created WithLatencyStats.
+type Metrics struct {{
+\tclient    *{self.class_name}Client
+\tnamespace string
+}}
+
+// Metrics returns the client's metrics, with names starting with namespace.
+func (c *{self.class_name}Client) Metrics(namespace string) *Metrics {{
+\treturn &Metrics{{client: c, namespace: namespace}}
+}}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {{
+\tw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+\tm.WriteTo(w)
+}}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {{
+\tstats := m.client.Stats()
+\tm.client.stats.mu.Lock()
+\tquantiles := m.client.stats.latency
+\tm.client.stats.mu.Unlock()
+\toperations := make([]string, 0, len(stats.Endpoints))
+\tfor operation := range stats.Endpoints {{
+\t\toperations = append(operations, operation)
+\t}}
+\tsort.Strings(operations)
+
+\tvar buf bytes.Buffer
+\tmetric := func(name, kind, help string) string {{
+\t\tname = m.metricName(name)
+\t\tfmt.Fprintf(&buf, "# HELP %s %s\\n# TYPE %s %s\\n", name, help, name, kind)
+\t\treturn name
+\t}}
+\tcounter := func(name, help string, value func(EndpointStats) int64) {{
+\t\tname = metric(name, "counter", help)
+\t\tfor _, operation := range operations {{
+\t\t\tfmt.Fprintf(&buf, "%s{{operation=\\"%s\\"}} %d\\n", name, metricLabel(operation), value(stats.Endpoints[operation]))
+\t\t}}
+\t}}
+\tcounter("requests_total", "HTTP requests sent, retries included.", func(e EndpointStats) int64 {{ return e.Requests }})
+\tcounter("errors_total", "Requests that got no response or an error status.", func(e EndpointStats) int64 {{ return e.Errors }})
+\tcounter("retries_total", "Calls retried.", func(e EndpointStats) int64 {{ return e.Retries }})
+\tcounter("sent_bytes_total", "Request body bytes sent.", func(e EndpointStats) int64 {{ return e.BytesSent }})
+\tcounter("received_bytes_total", "Response body bytes received.", func(e EndpointStats) int64 {{ return e.BytesReceived }})
+
+\tname := metric("responses_total", "counter", "Responses received by status class.")
+\tfor _, operation := range operations {{
+\t\tresponses := stats.Endpoints[operation].Responses
+\t\tclasses := make([]string, 0, len(responses))
+\t\tfor class := range responses {{
+\t\t\tclasses = append(classes, class)
+\t\t}}
+\t\tsort.Strings(classes)
+\t\tfor _, class := range classes {{
+\t\t\tfmt.Fprintf(&buf, "%s{{operation=\\"%s\\",class=\\"%s\\"}} %d\\n", name, metricLabel(operation), class, responses[class])
+\t\t}}
+\t}}
+
+\tname = metric("request_duration_seconds", "summary", "Time until the response headers arrived.")
+\tfor _, operation := range operations {{
+\t\te := stats.Endpoints[operation]
+\t\tlabel := metricLabel(operation)
+\t\tif quantiles {{
+\t\t\tfor _, q := range []struct {{
+\t\t\t\tquantile string
+\t\t\t\tvalue    float64
+\t\t\t}}{{{{"0.5", e.P50.Seconds()}}, {{"0.95", e.P95.Seconds()}}, {{"0.99", e.P99.Seconds()}}}} {{
+\t\t\t\tfmt.Fprintf(&buf, "%s{{operation=\\"%s\\",quantile=\\"%s\\"}} %g\\n", name, label, q.quantile, q.value)
+\t\t\t}}
+\t\t}}
+\t\tvar count int64
+\t\tfor _, n := range e.Responses {{
+\t\t\tcount += n
+\t\t}}
+\t\tfmt.Fprintf(&buf, "%s_sum{{operation=\\"%s\\"}} %g\\n", name, label, e.Latency.Seconds())
+\t\tfmt.Fprintf(&buf, "%s_count{{operation=\\"%s\\"}} %d\\n", name, label, count)
+\t}}
+
+\tname = metric("in_flight_requests", "gauge", "Requests awaiting their response.")
+\tfmt.Fprintf(&buf, "%s %d\\n", name, stats.InFlight)
+\tname = metric("schema_mismatches_total", "counter", "Response fields that did not match the generated types.")
+\tfmt.Fprintf(&buf, "%s %d\\n", name, stats.SchemaMismatches)
+\treturn buf.WriteTo(w)
+}}
+
+func (m *Metrics) metricName(name string) string {{
+\tif m.namespace == "" {{
+\t\treturn name
+\t}}
+\treturn m.namespace + "_" + name
+}}
+
+var metricLabelEscaper = strings.NewReplacer(`\\`, `\\\\`, `"`, `\\"`, "\\n", `\\n`)
+
+func metricLabel(value string) string {{
+\treturn metricLabelEscaper.Replace(value)
+}}
+"""
+    
+    def _generate_go_telemetry(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"math"
+\t"reflect"
+\t"runtime/pprof"
+\t"sort"
+\t"strings"
+\t"sync"
+)
+
+// MismatchKind classifies a difference between a response and the schema
+// the SDK was generated from.
+type MismatchKind string
+
+const (
+\t// UnknownField is a response field the SDK has no field for.
+\tUnknownField MismatchKind = "unknown_field"
+\t// MissingField is an SDK field absent from the response.
+\tMissingField MismatchKind = "missing_field"
+\t// TypeFallback is a value of an unexpected type; the field is left at
+\t// its zero value instead of failing the call.
+\tTypeFallback MismatchKind = "type_fallback"
+)
+
+// SchemaMismatch is one occurrence of a response deviating from the
+// generated types.
+type SchemaMismatch struct {{
+\tOperation string // "GET /v1/users/{{id}}"
+\tKind      MismatchKind
+\tField     string // path such as "users[].email"
+\tExpected  string // Go type, empty for UnknownField
+\tObserved  string // JSON type, empty for MissingField
+}}
+
+// SchemaMismatchCount is a SchemaMismatch with the number of times it was
+// seen.
+type SchemaMismatchCount struct {{
+\tSchemaMismatch
+\tCount int64
+}}
+
+// WithSchemaMismatchHandler calls fn for every schema mismatch found while
+// decoding responses, e.g. to log or alert on schema drift.
+func WithSchemaMismatchHandler(fn func(SchemaMismatch)) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.schema.handler = fn
+\t}}
+}}
+
+// SchemaMismatches returns how often each mismatch was seen since the
+// client was created, most frequent first. The totals quantify how stale
+// the reverse-engineered schema is getting.
+func (c *{self.class_name}Client) SchemaMismatches() []SchemaMismatchCount {{
+\tc.schema.mu.Lock()
+\tdefer c.schema.mu.Unlock()
+\tcounts := make([]SchemaMismatchCount, 0, len(c.schema.counts))
+\tfor mismatch, count := range c.schema.counts {{
+\t\tcounts = append(counts, SchemaMismatchCount{{mismatch, count}})
+\t}}
+\tsort.Slice(counts, func(i, j int) bool {{
+\t\tif counts[i].Count != counts[j].Count {{
+\t\t\treturn counts[i].Count > counts[j].Count
+\t\t}}
+\t\ta, b := counts[i].SchemaMismatch, counts[j].SchemaMismatch
+\t\treturn a.Operation+a.Field+string(a.Kind) < b.Operation+b.Field+string(b.Kind)
+\t}})
+\treturn counts
+}}
+
+type schemaTelemetry struct {{
+\tmu      sync.Mutex
+\tcounts  map[SchemaMismatch]int64
+\thandler func(SchemaMismatch)
+}}
+
+func (t *schemaTelemetry) record(m SchemaMismatch) {{
+\tt.mu.Lock()
+\tif t.counts == nil {{
+\t\tt.counts = make(map[SchemaMismatch]int64)
+\t}}
+\tt.counts[m]++
+\thandler := t.handler
+\tt.mu.Unlock()
+\tif handler != nil {{
+\t\thandler(m)
+\t}}
+}}
+
+// decode unmarshals a response body into v, recording every field that
+// does not match the generated type. Type mismatches fall back to zero
+// values rather than failing the call, as do timestamps no layout accepts.
+// Operations whose responses are not JSON use their media type's
+// serializer, without the field checks; JSON ones use the client's Codec
+// and its DecodeMode. An empty body, as a 204 No Content has, leaves v
+// unchanged. Enveloped responses decode their payload.
+func (c *{self.class_name}Client) decode(operation string, body []byte, v interface{{}}) (err error) {{
+\tdefer recoverPanic(operation, 1, &err)
+\tbody = envelopePayload(operation, body)
+\tif len(bytes.TrimSpace(body)) == 0 {{
+\t\treturn nil
+\t}}
+\tserializer, err := c.responseSerializer(operation)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tmethod, endpoint, _ := strings.Cut(operation, " ")
+\tpprof.Do(context.Background(), profileLabels(method, endpoint), func(context.Context) {{
+\t\tif serializer != nil {{
+\t\t\terr = serializer.Unmarshal(body, v)
+\t\t}} else {{
+\t\t\terr = c.jsonCodec().Unmarshal(body, v)
+\t\t}}
+\t\tvar typeErr *json.UnmarshalTypeError
+\t\tif err != nil && !errors.As(err, &typeErr) {{
+\t\t\treturn
+\t\t}}
+\t\terr = nil
+\t\tvar raw interface{{}}
+\t\tif serializer == nil && json.Unmarshal(body, &raw) == nil {{
+\t\t\tc.schema.compare(operation, "", raw, reflect.TypeOf(v).Elem())
+\t\t}}
+\t\tc.applyTimeLayouts(operation, v)
+\t\tif serializer == nil {{
+\t\t\terr = c.checkUnknownFields(operation, body, v)
+\t\t}}
+\t}})
+\treturn err
+}}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// schemaValue is implemented by value types with custom decoding, such as
+// Decimal, that accept only some JSON values.
+type schemaValue interface {{
+\tacceptsJSON(raw interface{{}}) bool
+}}
+
+func (t *schemaTelemetry) compare(operation, path string, raw interface{{}}, typ reflect.Type) {{
+\tfor typ.Kind() == reflect.Ptr {{
+\t\ttyp = typ.Elem()
+\t}}
+\tif raw == nil || typ.Kind() == reflect.Interface {{
+\t\treturn
+\t}}
+\tmismatch := func(kind MismatchKind, field, expected, observed string) {{
+\t\tt.record(SchemaMismatch{{Operation: operation, Kind: kind, Field: field, Expected: expected, Observed: observed}})
+\t}}
+\tobserved := jsonType(raw)
+\tlabel := path
+\tif label == "" {{
+\t\tlabel = "(root)"
+\t}}
+\tif reflect.PtrTo(typ).Implements(unmarshalerType) {{
+\t\tif value, ok := reflect.Zero(typ).Interface().(schemaValue); ok && !value.acceptsJSON(raw) {{
+\t\t\tmismatch(TypeFallback, label, typ.String(), observed)
+\t\t}}
+\t\treturn
+\t}}
+
+\tswitch typ.Kind() {{
+\tcase reflect.Struct:
+\t\tobject, ok := raw.(map[string]interface{{}})
+\t\tif !ok {{
+\t\t\tmismatch(TypeFallback, label, "object", observed)
+\t\t\treturn
+\t\t}}
+\t\tknown := make(map[string]bool, len(object))
+\t\tfor i := 0; i < typ.NumField(); i++ {{
+\t\t\tfield := typ.Field(i)
+\t\t\tname := jsonName(field)
+\t\t\tif name == "" {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tkey, found := lookupKey(object, name)
+\t\t\tif !found {{
+\t\t\t\tmismatch(MissingField, joinPath(path, name), field.Type.String(), "")
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tknown[key] = true
+\t\t\tt.compare(operation, joinPath(path, name), object[key], field.Type)
+\t\t}}
+\t\tfor key, value := range object {{
+\t\t\tif !known[key] {{
+\t\t\t\tmismatch(UnknownField, joinPath(path, key), "", jsonType(value))
+\t\t\t}}
+\t\t}}
+\tcase reflect.Map:
+\t\tobject, ok := raw.(map[string]interface{{}})
+\t\tif !ok {{
+\t\t\tmismatch(TypeFallback, label, "object", observed)
+\t\t\treturn
+\t\t}}
+\t\tfor key, value := range object {{
+\t\t\tt.compare(operation, joinPath(path, key), value, typ.Elem())
+\t\t}}
+\tcase reflect.Slice, reflect.Array:
+\t\titems, ok := raw.([]interface{{}})
+\t\tif !ok {{
+\t\t\tif typ.Elem().Kind() == reflect.Uint8 && observed == "string" {{
+\t\t\t\treturn // []byte is encoded as base64
+\t\t\t}}
+\t\t\tmismatch(TypeFallback, label, typ.String(), observed)
+\t\t\treturn
+\t\t}}
+\t\tfor _, item := range items {{
+\t\t\tt.compare(operation, path+"[]", item, typ.Elem())
+\t\t}}
+\tcase reflect.String:
+\t\tif observed != "string" {{
+\t\t\tmismatch(TypeFallback, label, "string", observed)
+\t\t}}
+\tcase reflect.Bool:
+\t\tif observed != "boolean" {{
+\t\t\tmismatch(TypeFallback, label, "bool", observed)
+\t\t}}
+\tcase reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+\t\treflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+\t\tif n, ok := raw.(float64); !ok || n != math.Trunc(n) {{
+\t\t\tmismatch(TypeFallback, label, typ.String(), observed)
+\t\t}}
+\tcase reflect.Float32, reflect.Float64:
+\t\tif _, ok := raw.(float64); !ok {{
+\t\t\tmismatch(TypeFallback, label, typ.String(), observed)
+\t\t}}
+\t}}
+}}
+
+func jsonName(field reflect.StructField) string {{
+\tif field.PkgPath != "" {{
+\t\treturn ""
+\t}}
+\ttag := field.Tag.Get("json")
+\tif tag == "-" {{
+\t\treturn ""
+\t}}
+\tif name, _, _ := strings.Cut(tag, ","); name != "" {{
+\t\treturn name
+\t}}
+\treturn field.Name
+}}
+
+// lookupKey finds name in object, falling back to the case-insensitive
+// match encoding/json also accepts.
+func lookupKey[V any](object map[string]V, name string) (string, bool) {{
+\tif _, ok := object[name]; ok {{
+\t\treturn name, true
+\t}}
+\tfor key := range object {{
+\t\tif strings.EqualFold(key, name) {{
+\t\t\treturn key, true
+\t\t}}
+\t}}
+\treturn "", false
+}}
+
+func joinPath(path, name string) string {{
+\tif path == "" {{
+\t\treturn name
+\t}}
+\treturn path + "." + name
+}}
+
+func jsonType(v interface{{}}) string {{
+\tswitch n := v.(type) {{
+\tcase nil:
+\t\treturn "null"
+\tcase map[string]interface{{}}:
+\t\treturn "object"
+\tcase []interface{{}}:
+\t\treturn "array"
+\tcase string:
+\t\treturn "string"
+\tcase bool:
+\t\treturn "boolean"
+\tcase float64:
+\t\tif n == math.Trunc(n) {{
+\t\t\treturn "integer"
+\t\t}}
+\t\treturn "number"
+\t}}
+\treturn "unknown"
+}}
+"""
+    
+    def _generate_go_time(self, package_name: str, time_layouts: List[str]) -> str:
+        import json
+        observed = ', '.join(json.dumps(layout) for layout in time_layouts)
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"encoding/json"
+\t"fmt"
+\t"reflect"
+\t"strconv"
+\t"sync"
+\t"time"
+)
+
+// Special layouts for timestamps encoded as Unix epoch numbers (or numeric
+// strings).
+const (
+\tLayoutUnix      = "unix"
+\tLayoutUnixMilli = "unixmilli"
+)
+
+// observedTimeLayouts are the non-ISO layouts seen in the captured traffic.
+var observedTimeLayouts = []string{{{observed}}}
+
+// TimeLayoutRegistry lists the layouts accepted when decoding Time fields,
+// tried in order, and the layout used when encoding them. It is safe for
+// concurrent use.
+type TimeLayoutRegistry struct {{
+\tmu       sync.RWMutex
+\taccepted []string
+\temit     string
+}}
+
+// NewTimeLayoutRegistry creates a registry emitting emit and accepting
+// accepted (plus emit itself).
+func NewTimeLayoutRegistry(emit string, accepted ...string) *TimeLayoutRegistry {{
+\tr := &TimeLayoutRegistry{{emit: emit}}
+\tr.Accept(emit)
+\tr.Accept(accepted...)
+\treturn r
+}}
+
+// TimeLayouts is the package-level registry used by every Time field unless
+// a client overrides it with WithTimeLayouts. It accepts RFC 3339, common
+// SQL-style layouts, Unix seconds and milliseconds, and the layouts observed
+// in the captured traffic, and emits RFC 3339.
+var TimeLayouts = NewTimeLayoutRegistry(time.RFC3339Nano, append([]string{{
+\ttime.RFC3339,
+\t"2006-01-02T15:04:05",
+\t"2006-01-02 15:04:05",
+\t"2006-01-02",
+\tLayoutUnix,
+\tLayoutUnixMilli,
+}}, observedTimeLayouts...)...)
+
+// Accept adds layouts to the end of the accepted list.
+func (r *TimeLayoutRegistry) Accept(layouts ...string) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tfor _, layout := range layouts {{
+\t\tif !containsString(r.accepted, layout) {{
+\t\t\tr.accepted = append(r.accepted, layout)
+\t\t}}
+\t}}
+}}
+
+// SetEmit sets the layout used when encoding, and accepts it.
+func (r *TimeLayoutRegistry) SetEmit(layout string) {{
+\tr.Accept(layout)
+\tr.mu.Lock()
+\tr.emit = layout
+\tr.mu.Unlock()
+}}
+
+// Parse decodes a JSON string or number with the first accepted layout
+// that matches.
+func (r *TimeLayoutRegistry) Parse(data []byte) (time.Time, error) {{
+\tr.mu.RLock()
+\taccepted := r.accepted
+\tr.mu.RUnlock()
+
+\ttext := string(bytes.Trim(data, `"`))
+\tquoted := len(data) > 0 && data[0] == '"'
+\tfor _, layout := range accepted {{
+\t\tswitch layout {{
+\t\tcase LayoutUnix, LayoutUnixMilli:
+\t\t\tn, err := strconv.ParseFloat(text, 64)
+\t\t\tif err != nil {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\t// With both accepted, values too large to be seconds (beyond the
+\t\t\t// year 5138) are milliseconds.
+\t\t\tif layout == LayoutUnixMilli || n >= 1e11 && containsString(accepted, LayoutUnixMilli) {{
+\t\t\t\treturn time.UnixMilli(int64(n)).UTC(), nil
+\t\t\t}}
+\t\t\treturn time.Unix(0, int64(n*1e9)).UTC(), nil
+\t\tdefault:
+\t\t\tif !quoted {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tif t, err := time.Parse(layout, text); err == nil {{
+\t\t\t\treturn t, nil
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn time.Time{{}}, fmt.Errorf("time %s matches none of the accepted layouts", data)
+}}
+
+// Format encodes t as JSON with the emit layout.
+func (r *TimeLayoutRegistry) Format(t time.Time) []byte {{
+\tr.mu.RLock()
+\tlayout := r.emit
+\tr.mu.RUnlock()
+\tswitch layout {{
+\tcase LayoutUnix:
+\t\treturn strconv.AppendInt(nil, t.Unix(), 10)
+\tcase LayoutUnixMilli:
+\t\treturn strconv.AppendInt(nil, t.UnixMilli(), 10)
+\t}}
+\treturn strconv.AppendQuote(nil, t.Format(layout))
+}}
+
+func containsString(values []string, value string) bool {{
+\tfor _, v := range values {{
+\t\tif v == value {{
+\t\t\treturn true
+\t\t}}
+\t}}
+\treturn false
+}}
+
+// Time is a timestamp field decoded and encoded through a
+// TimeLayoutRegistry. A value that matches no accepted layout decodes to the
+// zero time without failing, keeping the raw text in Raw; clients record it
+// as a TypeFallback schema mismatch. JSON null decodes to the zero time, and
+// the zero time encodes as null.
+type Time struct {{
+\ttime.Time
+
+\traw      string
+\tregistry *TimeLayoutRegistry
+}}
+
+// NewTime wraps t.
+func NewTime(t time.Time) Time {{
+\treturn Time{{Time: t}}
+}}
+
+// Raw returns the JSON text the value was decoded from.
+func (t Time) Raw() string {{
+\treturn t.raw
+}}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {{
+\tif string(data) == "null" {{
+\t\t*t = Time{{}}
+\t\treturn nil
+\t}}
+\tt.raw = string(data)
+\tparsed, err := t.layouts().Parse(data)
+\tif err != nil {{
+\t\tt.Time = time.Time{{}}
+\t\treturn nil
+\t}}
+\tt.Time = parsed
+\treturn nil
+}}
+
+// MarshalJSON implements json.Marshaler. A zero value that was decoded
+// from text no layout matched encodes as that text again.
+func (t Time) MarshalJSON() ([]byte, error) {{
+\tif t.Time.IsZero() {{
+\t\tif t.raw != "" {{
+\t\t\treturn []byte(t.raw), nil
+\t\t}}
+\t\treturn []byte("null"), nil
+\t}}
+\treturn t.layouts().Format(t.Time), nil
+}}
+
+func (t Time) layouts() *TimeLayoutRegistry {{
+\tif t.registry != nil {{
+\t\treturn t.registry
+\t}}
+\treturn TimeLayouts
+}}
+
+// valid reports whether the value decoded, or was never decoded.
+func (t Time) valid() bool {{
+\treturn t.raw == "" || !t.Time.IsZero()
+}}
+
+// epoch reports whether the value was decoded as a UnixTime or
+// UnixMilliTime, which ignore the client's layouts.
+func (t Time) epoch() bool {{
+\treturn t.registry == unixSeconds || t.registry == unixMillis
+}}
+
+// unixSeconds and unixMillis decode and encode UnixTime and UnixMilliTime
+// values.
+var (
+\tunixSeconds = NewTimeLayoutRegistry(LayoutUnix, LayoutUnixMilli)
+\tunixMillis  = NewTimeLayoutRegistry(LayoutUnixMilli)
+)
+
+// UnixTime is a timestamp field the API sends as Unix seconds. It decodes
+// like Time, taking values too large to be seconds as milliseconds, and
+// encodes as seconds whatever the emit layout.
+type UnixTime struct {{
+\tTime
+}}
+
+// NewUnixTime wraps t.
+func NewUnixTime(t time.Time) UnixTime {{
+\treturn UnixTime{{Time: NewTime(t)}}
+}}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *UnixTime) UnmarshalJSON(data []byte) error {{
+\tt.registry = unixSeconds
+\treturn t.Time.UnmarshalJSON(data)
+}}
+
+// MarshalJSON implements json.Marshaler.
+func (t UnixTime) MarshalJSON() ([]byte, error) {{
+\tt.registry = unixSeconds
+\treturn t.Time.MarshalJSON()
+}}
+
+// UnixMilliTime is a timestamp field the API sends as Unix milliseconds.
+type UnixMilliTime struct {{
+\tTime
+}}
+
+// NewUnixMilliTime wraps t.
+func NewUnixMilliTime(t time.Time) UnixMilliTime {{
+\treturn UnixMilliTime{{Time: NewTime(t)}}
+}}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *UnixMilliTime) UnmarshalJSON(data []byte) error {{
+\tt.registry = unixMillis
+\treturn t.Time.UnmarshalJSON(data)
+}}
+
+// MarshalJSON implements json.Marshaler.
+func (t UnixMilliTime) MarshalJSON() ([]byte, error) {{
+\tt.registry = unixMillis
+\treturn t.Time.MarshalJSON()
+}}
+
+// WithTimeLayouts makes the client decode and encode Time fields with
+// layouts instead of the package-level TimeLayouts, for APIs whose
+// endpoints disagree with other APIs in the same program.
+func WithTimeLayouts(layouts *TimeLayoutRegistry) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.timeLayouts = layouts
+\t}}
+}}
+
+var timeType = reflect.TypeOf(Time{{}})
+
+// walkTimes calls fn for every addressable Time reachable from v, with its
+// field path; the Time of a UnixTime or UnixMilliTime has the path of the
+// field holding it.
+func walkTimes(path string, v reflect.Value, fn func(string, *Time)) {{
+\tswitch v.Kind() {{
+\tcase reflect.Ptr, reflect.Interface:
+\t\tif !v.IsNil() {{
+\t\t\twalkTimes(path, v.Elem(), fn)
+\t\t}}
+\tcase reflect.Struct:
+\t\tif v.Type() == timeType {{
+\t\t\tif v.CanAddr() {{
+\t\t\t\tfn(path, v.Addr().Interface().(*Time))
+\t\t\t}}
+\t\t\treturn
+\t\t}}
+\t\tfor i := 0; i < v.NumField(); i++ {{
+\t\t\tfield := v.Type().Field(i)
+\t\t\tif field.Anonymous && field.Type == timeType {{
+\t\t\t\twalkTimes(path, v.Field(i), fn)
+\t\t\t}} else if name := jsonName(field); name != "" {{
+\t\t\t\twalkTimes(joinPath(path, name), v.Field(i), fn)
+\t\t\t}}
+\t\t}}
+\tcase reflect.Slice, reflect.Array:
+\t\tfor i := 0; i < v.Len(); i++ {{
+\t\t\twalkTimes(path+"[]", v.Index(i), fn)
+\t\t}}
+\t}}
+}}
+
+// applyTimeLayouts re-parses decoded Time values with the client's
+// registry and records values no layout matched.
+func (c *{self.class_name}Client) applyTimeLayouts(operation string, v interface{{}}) {{
+\twalkTimes("", reflect.ValueOf(v), func(path string, t *Time) {{
+\t\tif c.timeLayouts != nil && t.raw != "" && !t.epoch() {{
+\t\t\tt.registry = c.timeLayouts
+\t\t\tt.Time, _ = c.timeLayouts.Parse([]byte(t.raw))
+\t\t}}
+\t\tif !t.valid() {{
+\t\t\tvar raw interface{{}}
+\t\t\tjson.Unmarshal([]byte(t.raw), &raw)
+\t\t\tc.schema.record(SchemaMismatch{{Operation: operation, Kind: TypeFallback, Field: path, Expected: "Time", Observed: jsonType(raw)}})
+\t\t}}
+\t}})
+}}
+
+// withTimeLayouts returns the request body v to encode: v itself, or with
+// the client's own registry a copy of v whose Time values encode with it.
+// The caller's value is left as it is, as other goroutines may be reading
+// it or encoding it with another client.
+func (c *{self.class_name}Client) withTimeLayouts(v interface{{}}) interface{{}} {{
+\tif c.timeLayouts == nil {{
+\t\treturn v
+\t}}
+\tcopied := copyTimes(reflect.ValueOf(v))
+\twalkTimes("", copied, func(_ string, t *Time) {{
+\t\tt.registry = c.timeLayouts
+\t}})
+\treturn copied.Interface()
+}}
+
+// copyTimes copies v as deep as walkTimes reaches, so that the Time values
+// it finds are the copy's own; everything else is shared with v.
+func copyTimes(v reflect.Value) reflect.Value {{
+\tswitch v.Kind() {{
+\tcase reflect.Ptr:
+\t\tif v.IsNil() {{
+\t\t\treturn v
+\t\t}}
+\t\tcopied := reflect.New(v.Type().Elem())
+\t\tcopied.Elem().Set(copyTimes(v.Elem()))
+\t\treturn copied
+\tcase reflect.Interface:
+\t\tif v.IsNil() {{
+\t\t\treturn v
+\t\t}}
+\t\tcopied := reflect.New(v.Type()).Elem()
+\t\tcopied.Set(copyTimes(v.Elem()))
+\t\treturn copied
+\tcase reflect.Struct:
+\t\tcopied := reflect.New(v.Type()).Elem()
+\t\tcopied.Set(v)
+\t\tfor i := 0; i < v.NumField(); i++ {{
+\t\t\tif copied.Field(i).CanSet() {{
+\t\t\t\tcopied.Field(i).Set(copyTimes(v.Field(i)))
+\t\t\t}}
+\t\t}}
+\t\treturn copied
+\tcase reflect.Slice:
+\t\tif v.IsNil() {{
+\t\t\treturn v
+\t\t}}
+\t\tcopied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+\t\tfor i := 0; i < v.Len(); i++ {{
+\t\t\tcopied.Index(i).Set(copyTimes(v.Index(i)))
+\t\t}}
+\t\treturn copied
+\tcase reflect.Array:
+\t\tcopied := reflect.New(v.Type()).Elem()
+\t\tfor i := 0; i < v.Len(); i++ {{
+\t\t\tcopied.Index(i).Set(copyTimes(v.Index(i)))
+\t\t}}
+\t\treturn copied
+\t}}
+\treturn v
+}}
+"""
+    
+    def _generate_go_time_test(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"encoding/json"
+\t"sync"
+\t"testing"
+\t"time"
+)
+
+type timedRequest struct {{
+\tAt    Time   `json:"at"`
+\tTimes []Time `json:"times"`
+}}
+
+// TestWithTimeLayouts encodes one request with clients using different
+// layouts at once; run it with -race.
+func TestWithTimeLayouts(t *testing.T) {{
+\tat := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+\trequest := &timedRequest{{At: Time{{Time: at}}, Times: []Time{{{{Time: at}}}}}}
+\tlayouts := map[string]string{{
+\t\t`{{"at":"2024-01-15","times":["2024-01-15"]}}`:                   "2006-01-02",
+\t\t`{{"at":"2024-01-15 10:30:00","times":["2024-01-15 10:30:00"]}}`: "2006-01-02 15:04:05",
+\t}}
+
+\tvar wg sync.WaitGroup
+\tfor want, layout := range layouts {{
+\t\twg.Add(1)
+\t\tgo func(want, layout string) {{
+\t\t\tdefer wg.Done()
+\t\t\tc := New{self.class_name}Client("", WithTimeLayouts(NewTimeLayoutRegistry(layout)))
+\t\t\tgot, err := json.Marshal(c.withTimeLayouts(request))
+\t\t\tif err != nil {{
+\t\t\t\tt.Error(err)
+\t\t\t}} else if string(got) != want {{
+\t\t\t\tt.Errorf("layout %q encoded %s, want %s", layout, got, want)
+\t\t\t}}
+\t\t}}(want, layout)
+\t}}
+\twg.Wait()
+
+\tif request.At.registry != nil || request.Times[0].registry != nil {{
+\t\tt.Error("withTimeLayouts changed the caller's request")
+\t}}
+}}
+"""
+    
+    def _generate_go_decimal(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"math/big"
+\t"reflect"
+\t"strconv"
+\t"strings"
+)
+
+// Decimal is an exact base-10 number for monetary and other decimal fields,
+// so arithmetic never drifts the way float64 does (0.1 + 0.2 is exactly
+// 0.3). It decodes from JSON numbers and numeric strings, keeps the scale it
+// was written with ("10.50" stays "10.50"), and encodes in the same form it
+// was decoded from. The zero value is 0.
+type Decimal struct {{
+\tunscaled *big.Int // nil means zero
+\tscale    int32    // digits after the decimal point
+\tquoted   bool     // encoded as a JSON string
+}}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1050, 2) is 10.50.
+func NewDecimal(unscaled int64, scale int32) Decimal {{
+\treturn Decimal{{unscaled: big.NewInt(unscaled), scale: scale}}.normalize()
+}}
+
+// ParseDecimal parses a decimal such as "-12.345" or "1e3".
+func ParseDecimal(s string) (Decimal, error) {{
+\ttext := strings.TrimSpace(s)
+\tmantissa, exponent := text, int64(0)
+\tif i := strings.IndexAny(text, "eE"); i >= 0 {{
+\t\tvar err error
+\t\tmantissa = text[:i]
+\t\tif exponent, err = strconv.ParseInt(text[i+1:], 10, 32); err != nil {{
+\t\t\treturn Decimal{{}}, fmt.Errorf("invalid decimal %q", s)
+\t\t}}
+\t}}
+\tnegative := strings.HasPrefix(mantissa, "-")
+\tif negative || strings.HasPrefix(mantissa, "+") {{
+\t\tmantissa = mantissa[1:]
+\t}}
+\twhole, frac, _ := strings.Cut(mantissa, ".")
+\tdigits := whole + frac
+\tif digits == "" || strings.Trim(digits, "0123456789") != "" {{
+\t\treturn Decimal{{}}, fmt.Errorf("invalid decimal %q", s)
+\t}}
+\tunscaled, _ := new(big.Int).SetString(digits, 10)
+\tif negative {{
+\t\tunscaled.Neg(unscaled)
+\t}}
+\tscale := int64(len(frac)) - exponent
+\tif scale > maxDecimalScale || scale < -maxDecimalScale {{
+\t\treturn Decimal{{}}, fmt.Errorf("decimal %q: exponent out of range", s)
+\t}}
+\treturn Decimal{{unscaled: unscaled, scale: int32(scale)}}.normalize(), nil
+}}
+
+// maxDecimalScale bounds the scale ParseDecimal accepts either way, so that
+// an input such as "1e2000000000" is rejected rather than expanded into a
+// two-billion-digit integer.
+const maxDecimalScale = 10000
+
+// MustParseDecimal is ParseDecimal for literals; it panics on invalid input.
+func MustParseDecimal(s string) Decimal {{
+\td, err := ParseDecimal(s)
+\tif err != nil {{
+\t\tpanic(err)
+\t}}
+\treturn d
+}}
+
+// DecimalFromFloat converts f using its shortest exact representation, so
+// DecimalFromFloat(0.1) is 0.1 rather than 0.1000000000000000055511151231257827.
+// It panics on NaN and infinities.
+func DecimalFromFloat(f float64) Decimal {{
+\treturn MustParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}}
+
+// normalize removes negative scales, which ParseDecimal produces for
+// exponents.
+func (d Decimal) normalize() Decimal {{
+\tif d.scale < 0 {{
+\t\td.unscaled = new(big.Int).Mul(d.int(), pow10(-d.scale))
+\t\td.scale = 0
+\t}}
+\treturn d
+}}
+
+func (d Decimal) int() *big.Int {{
+\tif d.unscaled == nil {{
+\t\treturn new(big.Int)
+\t}}
+\treturn d.unscaled
+}}
+
+func pow10(n int32) *big.Int {{
+\treturn new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}}
+
+// rescale returns d's unscaled value at a scale of at least d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {{
+\tif scale <= d.scale {{
+\t\treturn d.int()
+\t}}
+\treturn new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}}
+
+func maxScale(a, b Decimal) int32 {{
+\tif a.scale > b.scale {{
+\t\treturn a.scale
+\t}}
+\treturn b.scale
+}}
+
+// Add returns d + other. Results keep d's JSON form.
+func (d Decimal) Add(other Decimal) Decimal {{
+\tscale := maxScale(d, other)
+\treturn Decimal{{unscaled: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale, quoted: d.quoted}}
+}}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {{
+\treturn d.Add(other.Neg())
+}}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {{
+\treturn Decimal{{unscaled: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale, quoted: d.quoted}}
+}}
+
+// Div returns d / other rounded half away from zero to places digits. It
+// panics if other is zero.
+func (d Decimal) Div(other Decimal, places int32) Decimal {{
+\tif other.IsZero() {{
+\t\tpanic("decimal division by zero")
+\t}}
+\t// d / other = (d.unscaled / other.unscaled) * 10^(other.scale - d.scale)
+\tnum, den := d.int(), other.int()
+\tif shift := places + other.scale - d.scale; shift >= 0 {{
+\t\tnum = new(big.Int).Mul(num, pow10(shift))
+\t}} else {{
+\t\tden = new(big.Int).Mul(den, pow10(-shift))
+\t}}
+\treturn Decimal{{unscaled: divRound(num, den), scale: places, quoted: d.quoted}}
+}}
+
+// Round returns d rounded half away from zero to places digits after the
+// decimal point; values with fewer digits are returned unchanged.
+func (d Decimal) Round(places int32) Decimal {{
+\tif places < 0 || d.scale <= places {{
+\t\treturn d
+\t}}
+\treturn Decimal{{unscaled: divRound(d.int(), pow10(d.scale-places)), scale: places, quoted: d.quoted}}
+}}
+
+func divRound(num, den *big.Int) *big.Int {{
+\tquo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+\t// Round away from zero when |rem| * 2 >= |den|.
+\tif new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(new(big.Int).Abs(den)) >= 0 {{
+\t\tif num.Sign()*den.Sign() < 0 {{
+\t\t\tquo.Sub(quo, big.NewInt(1))
+\t\t}} else {{
+\t\t\tquo.Add(quo, big.NewInt(1))
+\t\t}}
+\t}}
+\treturn quo
+}}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {{
+\td.unscaled = new(big.Int).Neg(d.int())
+\treturn d
+}}
+
+// Cmp returns -1, 0, or +1 as d is less than, equal to, or greater than
+// other; 10.5 and 10.50 are equal.
+func (d Decimal) Cmp(other Decimal) int {{
+\tscale := maxScale(d, other)
+\treturn d.rescale(scale).Cmp(other.rescale(scale))
+}}
+
+// Equal reports whether d and other are numerically equal.
+func (d Decimal) Equal(other Decimal) bool {{
+\treturn d.Cmp(other) == 0
+}}
+
+// Sign returns -1, 0, or +1.
+func (d Decimal) Sign() int {{
+\treturn d.int().Sign()
+}}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {{
+\treturn d.Sign() == 0
+}}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {{
+\treturn d.scale
+}}
+
+// Float64 returns the nearest float64, for display or statistics only.
+func (d Decimal) Float64() float64 {{
+\tf, _ := strconv.ParseFloat(d.String(), 64)
+\treturn f
+}}
+
+// String formats d with its full scale, e.g. "-0.50".
+func (d Decimal) String() string {{
+\tdigits := new(big.Int).Abs(d.int()).String()
+\tsign := ""
+\tif d.Sign() < 0 {{
+\t\tsign = "-"
+\t}}
+\tif d.scale == 0 {{
+\t\treturn sign + digits
+\t}}
+\tif pad := int(d.scale) + 1 - len(digits); pad > 0 {{
+\t\tdigits = strings.Repeat("0", pad) + digits
+\t}}
+\tpoint := len(digits) - int(d.scale)
+\treturn sign + digits[:point] + "." + digits[point:]
+}}
+
+// AsString returns d encoded as a JSON string ("10.50") rather than a
+// number, for APIs that expect decimal strings in requests.
+func (d Decimal) AsString() Decimal {{
+\td.quoted = true
+\treturn d
+}}
+
+var decimalType = reflect.TypeOf(Decimal{{}})
+
+// UnmarshalJSON implements json.Unmarshaler. Values that are not decimals
+// fail as a *json.UnmarshalTypeError, which the client tolerates.
+func (d *Decimal) UnmarshalJSON(data []byte) error {{
+\tif string(data) == "null" {{
+\t\t*d = Decimal{{}}
+\t\treturn nil
+\t}}
+\tquoted := len(data) > 0 && data[0] == '"'
+\ttext := string(data)
+\tif quoted {{
+\t\tif err := json.Unmarshal(data, &text); err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\tparsed, err := ParseDecimal(text)
+\tif err != nil {{
+\t\tvar raw interface{{}}
+\t\tjson.Unmarshal(data, &raw)
+\t\treturn &json.UnmarshalTypeError{{Value: jsonType(raw), Type: decimalType}}
+\t}}
+\tparsed.quoted = quoted
+\t*d = parsed
+\treturn nil
+}}
+
+// MarshalJSON implements json.Marshaler.
+func (d Decimal) MarshalJSON() ([]byte, error) {{
+\tif d.quoted {{
+\t\treturn strconv.AppendQuote(nil, d.String()), nil
+\t}}
+\treturn []byte(d.String()), nil
+}}
+
+// acceptsJSON reports whether raw decodes as a Decimal, for schema
+// mismatch telemetry.
+func (Decimal) acceptsJSON(raw interface{{}}) bool {{
+\tswitch v := raw.(type) {{
+\tcase float64:
+\t\treturn true
+\tcase string:
+\t\t_, err := ParseDecimal(v)
+\t\treturn err == nil
+\t}}
+\treturn false
+}}
+
+// ErrCurrencyMismatch is returned when combining Money in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// moneyAmountKeys and moneyCurrencyKeys are the JSON keys recognized in
+// money objects, in order of preference when encoding.
+var (
+\tmoneyAmountKeys   = []string{{"amount", "value"}}
+\tmoneyCurrencyKeys = []string{{"currency", "currency_code"}}
+)
+
+// Money is an exact amount in a currency, decoded from objects such as
+// {{"amount": "10.50", "currency": "USD"}}. It encodes with the keys it was
+// decoded with.
+type Money struct {{
+\tAmount   Decimal
+\tCurrency string
+
+\tamountKey, currencyKey string
+}}
+
+// NewMoney returns amount in currency.
+func NewMoney(amount Decimal, currency string) Money {{
+\treturn Money{{Amount: amount, Currency: currency}}
+}}
+
+// Add returns m + other, or ErrCurrencyMismatch.
+func (m Money) Add(other Money) (Money, error) {{
+\tif m.Currency != other.Currency {{
+\t\treturn Money{{}}, fmt.Errorf("%w: cannot add %s to %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+\t}}
+\tm.Amount = m.Amount.Add(other.Amount)
+\treturn m, nil
+}}
+
+// Sub returns m - other, or ErrCurrencyMismatch.
+func (m Money) Sub(other Money) (Money, error) {{
+\tif m.Currency != other.Currency {{
+\t\treturn Money{{}}, fmt.Errorf("%w: cannot subtract %s from %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+\t}}
+\tm.Amount = m.Amount.Sub(other.Amount)
+\treturn m, nil
+}}
+
+// String formats m as "10.50 USD".
+func (m Money) String() string {{
+\treturn m.Amount.String() + " " + m.Currency
+}}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Money) UnmarshalJSON(data []byte) error {{
+\tvar object map[string]json.RawMessage
+\tif err := json.Unmarshal(data, &object); err != nil {{
+\t\treturn err
+\t}}
+\t*m = Money{{}}
+\tfor _, key := range moneyCurrencyKeys {{
+\t\tif raw, ok := object[key]; ok {{
+\t\t\tm.currencyKey = key
+\t\t\tif err := json.Unmarshal(raw, &m.Currency); err != nil {{
+\t\t\t\treturn err
+\t\t\t}}
+\t\t\tbreak
+\t\t}}
+\t}}
+\tfor _, key := range moneyAmountKeys {{
+\t\tif raw, ok := object[key]; ok {{
+\t\t\tm.amountKey = key
+\t\t\treturn m.Amount.UnmarshalJSON(raw)
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {{
+\tamountKey, currencyKey := m.amountKey, m.currencyKey
+\tif amountKey == "" {{
+\t\tamountKey = moneyAmountKeys[0]
+\t}}
+\tif currencyKey == "" {{
+\t\tcurrencyKey = moneyCurrencyKeys[0]
+\t}}
+\tamount, _ := m.Amount.MarshalJSON()
+\tvar buf bytes.Buffer
+\tbuf.WriteByte('{{')
+\twriteJSONKey(&buf, amountKey)
+\tbuf.Write(amount)
+\tbuf.WriteByte(',')
+\twriteJSONKey(&buf, currencyKey)
+\tcurrency, err := json.Marshal(m.Currency)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tbuf.Write(currency)
+\tbuf.WriteByte('}}')
+\treturn buf.Bytes(), nil
+}}
+
+func writeJSONKey(buf *bytes.Buffer, key string) {{
+\tencoded, _ := json.Marshal(key)
+\tbuf.Write(encoded)
+\tbuf.WriteByte(':')
+}}
+
+// acceptsJSON reports whether raw decodes as Money.
+func (Money) acceptsJSON(raw interface{{}}) bool {{
+\tobject, ok := raw.(map[string]interface{{}})
+\tif !ok {{
+\t\treturn false
+\t}}
+\tfor _, key := range moneyAmountKeys {{
+\t\tif amount, ok := object[key]; ok {{
+\t\t\treturn Decimal{{}}.acceptsJSON(amount)
+\t\t}}
+\t}}
+\treturn false
+}}
+"""
+    
+    def _generate_go_locale(self, package_name: str, locale_headers: List[Tuple[str, str]]) -> str:
+        import json
+        headers = ''.join(f'\t{{{json.dumps(name)}, {json.dumps(separator)}}},\n' for name, separator in locale_headers)
+        return f"""package {package_name}
+
+import "strings"
+
+// localeHeader is a request header carrying the caller's locale.
+type localeHeader struct {{
+\tname      string
+\tseparator string // between language and region, as in "en_US"; empty sends the language alone
+}}
+
+// localeHeaders are Accept-Language plus the vendor locale headers seen in
+// the captured traffic.
+var localeHeaders = []localeHeader{{
+{headers}}}
+
+// value formats locale for the header. Accept-Language takes the locale as
+// given, so it may list preferences ("fr-CA, fr;q=0.8"); vendor headers get
+// the first tag in their own format.
+func (h localeHeader) value(locale string) string {{
+\tif h.name == "Accept-Language" {{
+\t\treturn locale
+\t}}
+\ttag, _, _ := strings.Cut(locale, ",")
+\ttag, _, _ = strings.Cut(tag, ";")
+\ttag = strings.TrimSpace(tag)
+\tif h.separator == "" {{
+\t\tlanguage, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+\t\treturn language
+\t}}
+\treturn strings.NewReplacer("-", h.separator, "_", h.separator).Replace(tag)
+}}
+
+// WithLocale requests localized field values and error messages in locale
+// (a BCP 47 tag such as "fr-CA") on every call, via Accept-Language and any
+// vendor locale headers the API uses.
+func WithLocale(locale string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tfor _, h := range localeHeaders {{
+\t\t\tc.Headers[h.name] = h.value(locale)
+\t\t}}
+\t}}
+}}
+
+// WithRequestLocale overrides the client's locale for one call.
+func WithRequestLocale(locale string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\tfor _, h := range localeHeaders {{
+\t\t\to.header.Set(h.name, h.value(locale))
+\t\t}}
+\t}}
+}}
+"""
+    
+    def _generate_go_tenant(self, package_name: str, tenant_header: str, tenant_collection: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"net/http"
+\t"net/url"
+\t"strings"
+)
+
+// The captured API scopes calls to a tenant through this request header,
+// or through the path segment following tenantCollection ("/orgs/{{id}}/...").
+const (
+\ttenantHeader     = "{tenant_header}"
+\ttenantCollection = "{tenant_collection}"
+)
+
+// WithTenant returns a copy of the client scoped to tenant id: every call
+// carries it automatically, so callers need not thread it through each
+// method. The copy shares the original's HTTP client, telemetry, and stats;
+// the original is unchanged.
+func (c *{self.class_name}Client) WithTenant(id string) *{self.class_name}Client {{
+\treturn c.derive(WithRequestTenant(id))
+}}
+
+// WithRequestTenant overrides the tenant for one call.
+func WithRequestTenant(id string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.edits = append(o.edits, func(req *http.Request) {{
+\t\t\tscopeToTenant(req, id)
+\t\t}})
+\t}}
+}}
+
+func scopeToTenant(req *http.Request, id string) {{
+\tif tenantHeader != "" {{
+\t\treq.Header.Set(tenantHeader, id)
+\t}}
+\tif tenantCollection == "" {{
+\t\treturn
+\t}}
+\tsegments := strings.Split(req.URL.EscapedPath(), "/")
+\tfor i := 0; i < len(segments)-1; i++ {{
+\t\tif segments[i] == tenantCollection {{
+\t\t\tsegments[i+1] = url.PathEscape(id)
+\t\t\tescaped := strings.Join(segments, "/")
+\t\t\tif path, err := url.PathUnescape(escaped); err == nil {{
+\t\t\t\treq.URL.Path, req.URL.RawPath = path, escaped
+\t\t\t}}
+\t\t\treturn
+\t\t}}
+\t}}
+}}
+"""
+    
+    def _generate_go_baseurl(self, package_name: str) -> str:
+        import json
+        from urllib.parse import urlparse
+        hosts = {urlparse(url).netloc for url in self.environments.values()}
+        endpoint_hosts = ''.join(
+            f'\t{json.dumps(f"{endpoint.method} {endpoint.path_pattern}")}: {json.dumps(endpoint.origin)},\n'
+            for endpoint in self.endpoints.values()
+            if endpoint.origin and urlparse(endpoint.origin).netloc not in hosts)
+        if endpoint_hosts:
+            endpoint_hosts = '\n' + endpoint_hosts
+        return f"""package {package_name}
+
+import "strings"
+
+// defaultBaseURL is the base URL of DefaultEnvironment.
+const defaultBaseURL = "{self.base_url}"
+
+// endpointBaseURLs maps endpoints captured on another host than
+// defaultBaseURL, such as uploads or exports, to that host.
+var endpointBaseURLs = map[string]string{{{endpoint_hosts}}}
+
+// WithBaseURL sends one call to baseURL instead of the client's BaseURL, for
+// endpoints served from another host (uploads., exports., auth.).
+func WithBaseURL(baseURL string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.baseURL = strings.TrimSuffix(baseURL, "/")
+\t}}
+}}
+
+// baseURL returns where a call goes: the per-call WithBaseURL, else the host
+// the endpoint was captured on while the client uses the default base URL,
+// else the client's BaseURL. Pointing BaseURL elsewhere (a staging API or a
+// test server) therefore sends every endpoint there.
+func (c *{self.class_name}Client) baseURL(method, endpoint string, options *requestOptions) string {{
+\tif options.baseURL != "" {{
+\t\treturn options.baseURL
+\t}}
+\tif host, ok := endpointBaseURLs[method+" "+endpoint]; ok && c.BaseURL == defaultBaseURL {{
+\t\treturn host
+\t}}
+\treturn c.BaseURL
+}}
+"""
+    
+    def _generate_go_ratelimit(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"sync"
+\t"time"
+)
+
+// WithRateLimit limits the client to n requests per interval, as a token
+// bucket holding up to n tokens: bursts of n go out at once, after which
+// calls block until a token frees up or their context is done. It panics
+// unless n and per are positive.
+func WithRateLimit(n int, per time.Duration) ClientOption {{
+\tif n <= 0 || per <= 0 {{
+\t\tpanic(fmt.Sprintf("WithRateLimit(%d, %v): n and per must be positive", n, per))
+\t}}
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.limiter = newRateLimiter(n, per)
+\t}}
+}}
+
+// WithQuotaWait makes every call wait, as WaitForQuota(ctx, 1) does, while
+// the API reports its quota used up, instead of sending requests that would
+// be answered with 429 until the window resets.
+func WithQuotaWait() ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.quotaWait = true
+\t}}
+}}
+
+// RateLimitState is the client's view of its rate limits: the quota the API
+// last reported, and what is left of the client's own WithRateLimit bucket.
+type RateLimitState struct {{
+\tQuota
+\t// Tokens is the number of requests the client's limiter lets through
+\t// without waiting; -1 without WithRateLimit.
+\tTokens int
+}}
+
+// RateLimitState returns the client's rate limit state.
+func (c *{self.class_name}Client) RateLimitState() RateLimitState {{
+\treturn RateLimitState{{Quota: c.Quota(), Tokens: c.limiter.available()}}
+}}
+
+type rateLimiter struct {{
+\tmu     sync.Mutex
+\ttokens float64
+\tburst  float64
+\trate   float64 // tokens per second
+\tlast   time.Time
+}}
+
+func newRateLimiter(n int, per time.Duration) *rateLimiter {{
+\treturn &rateLimiter{{
+\t\ttokens: float64(n),
+\t\tburst:  float64(n),
+\t\trate:   float64(n) / per.Seconds(),
+\t\tlast:   time.Now(),
+\t}}
+}}
+
+// refill adds the tokens earned since the last call; l.mu must be held.
+func (l *rateLimiter) refill() {{
+\tnow := time.Now()
+\tl.tokens += now.Sub(l.last).Seconds() * l.rate
+\tif l.tokens > l.burst {{
+\t\tl.tokens = l.burst
+\t}}
+\tl.last = now
+}}
+
+// wait blocks until a token is available and takes it, or until ctx is
+// done; it is a no-op on a nil limiter.
+func (l *rateLimiter) wait(ctx context.Context) error {{
+\tif l == nil {{
+\t\treturn nil
+\t}}
+\tfor {{
+\t\tl.mu.Lock()
+\t\tl.refill()
+\t\tif l.tokens >= 1 {{
+\t\t\tl.tokens--
+\t\t\tl.mu.Unlock()
+\t\t\treturn nil
+\t\t}}
+\t\tdelay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+\t\tl.mu.Unlock()
+\t\ttimer := time.NewTimer(delay)
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\ttimer.Stop()
+\t\t\treturn ctx.Err()
+\t\tcase <-timer.C:
+\t\t}}
+\t}}
+}}
+
+// available returns the whole tokens in the bucket, or -1 for a nil
+// limiter.
+func (l *rateLimiter) available() int {{
+\tif l == nil {{
+\t\treturn -1
+\t}}
+\tl.mu.Lock()
+\tdefer l.mu.Unlock()
+\tl.refill()
+\treturn int(l.tokens)
+}}
+"""
+    
+    def _env_prefix(self) -> str:
+        """Prefix for the environment variables the Go client reads, e.g. EXAMPLEAPI."""
+        import re
+        return re.sub(r"[^A-Z0-9]+", "_", self.class_name.upper()).strip("_")
+    
+    def _go_environment_credentials(self, name: str) -> str:
+        """The environment variable holding the credentials for environment
+        name: the plain EnvToken or EnvAPIKey variable for production, else
+        one naming the environment, as EXAMPLEAPI_STAGING_TOKEN."""
+        import re
+        suffix = 'TOKEN' if self._captured_auth()[0] == 'bearer' else 'API_KEY'
+        if name == 'production':
+            return f"{self._env_prefix()}_{suffix}"
+        return f"{self._env_prefix()}_{re.sub(r'[^A-Z0-9]+', '_', name.upper())}_{suffix}"
+    
+    def _generate_go_environment(self, package_name: str) -> str:
+        import json
+        names = sorted(self.environments, key=lambda name: (name != self.default_environment, name))
+        width = max(len(json.dumps(name)) + 1 for name in names)
+        entries = ''.join(
+            f'\t{(json.dumps(name) + ":").ljust(width)} {{BaseURL: {json.dumps(self.environments[name])}, '
+            f'TokenEnv: {json.dumps(self._go_environment_credentials(name))}}},\n'
+            for name in names)
+        other = next((name for name in names if name != self.default_environment), 'staging')
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"os"
+\t"sort"
+\t"strings"
+)
+
+// Environment is a deployment of the API.
+type Environment struct {{
+\tBaseURL string
+\t// TokenEnv names the environment variable holding the environment's
+\t// token or API key, sent when the variable is set.
+\tTokenEnv string
+}}
+
+// Environments are the deployments of the API, by name: those the captured
+// traffic reached, as told apart by their hosts. Add or change entries
+// before creating clients to reach others.
+var Environments = map[string]Environment{{
+{entries}}}
+
+// DefaultEnvironment is the environment clients created without a base URL
+// use.
+const DefaultEnvironment = "{self.default_environment}"
+
+// EnvironmentNames returns the names of Environments, sorted.
+func EnvironmentNames() []string {{
+\tnames := make([]string, 0, len(Environments))
+\tfor name := range Environments {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\treturn names
+}}
+
+// New{self.class_name}ClientForEnv creates a client for the named entry of
+// Environments, sending the credentials in its TokenEnv variable if set.
+// Options apply after the environment's settings:
+//
+//\tclient, err := New{self.class_name}ClientForEnv("{other}", WithTotalTimeout(10*time.Second))
+func New{self.class_name}ClientForEnv(name string, opts ...ClientOption) (*{self.class_name}Client, error) {{
+\tenvOpts, err := EnvironmentOptions(name)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\treturn New{self.class_name}Client("", append(envOpts, opts...)...), nil
+}}
+
+// EnvironmentOptions returns the client options for the named entry of
+// Environments.
+func EnvironmentOptions(name string) ([]ClientOption, error) {{
+\tenv, ok := Environments[name]
+\tif !ok {{
+\t\treturn nil, fmt.Errorf("environment %q not found (have %v)", name, EnvironmentNames())
+\t}}
+\tbaseURL := strings.TrimSuffix(env.BaseURL, "/")
+\topts := []ClientOption{{func(c *{self.class_name}Client) {{ c.BaseURL = baseURL }}}}
+\tif env.TokenEnv != "" {{
+\t\tif credential := os.Getenv(env.TokenEnv); credential != "" {{
+\t\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.SetAPIKey(credential) }})
+\t\t}}
+\t}}
+\treturn opts, nil
+}}
+"""
+    
+    def _generate_go_profile(self, package_name: str) -> str:
+        env_prefix = self._env_prefix()
+        file_prefix = env_prefix.lower().replace("_", "-")
+        return f"""package {package_name}
+
+import (
+\t"crypto/tls"
+\t"crypto/x509"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"os"
+\t"path/filepath"
+\t"sort"
+\t"strings"
+\t"time"
+)
+
+// ProfilesEnv names the environment variable pointing at the profiles
+// file. Without it, ./{file_prefix}-profiles.json and then
+// $HOME/.config/{file_prefix}/profiles.json are tried.
+const ProfilesEnv = "{env_prefix}_PROFILES"
+
+// Profile is one named target environment (sandbox, staging, production)
+// in a profiles file:
+//
+//\t{{
+//\t  "profiles": {{
+//\t    "sandbox": {{
+//\t      "base_url": "https://sandbox.example.com",
+//\t      "token_env": "SANDBOX_TOKEN",
+//\t      "headers": {{"X-Env": "sandbox"}},
+//\t      "timeout": "10s",
+//\t      "rate_limit": {{"requests": 5, "per": "1s"}},
+//\t      "tls": {{"ca_file": "sandbox-ca.pem"}},
+//\t      "disabled_endpoints": ["DELETE /v1/users/{{id}}"]
+//\t    }}
+//\t  }}
+//\t}}
+//
+// Tokens are better referenced through token_env than stored inline.
+type Profile struct {{
+\tBaseURL           string            `json:"base_url"`
+\tToken             string            `json:"token,omitempty"`
+\tTokenEnv          string            `json:"token_env,omitempty"`
+\tHeaders           map[string]string `json:"headers,omitempty"`
+\tTimeout           string            `json:"timeout,omitempty"`
+\tRateLimit         *ProfileRateLimit `json:"rate_limit,omitempty"`
+\tTLS               *ProfileTLS       `json:"tls,omitempty"`
+\tDisabledEndpoints []string          `json:"disabled_endpoints,omitempty"`
+}}
+
+// ProfileRateLimit caps a profile at Requests per Per (a Go duration).
+type ProfileRateLimit struct {{
+\tRequests int    `json:"requests"`
+\tPer      string `json:"per"`
+}}
+
+// ProfileTLS configures TLS for a profile. Relative paths are resolved
+// against the profiles file's directory.
+type ProfileTLS struct {{
+\tCAFile             string `json:"ca_file,omitempty"`
+\tCertFile           string `json:"cert_file,omitempty"`
+\tKeyFile            string `json:"key_file,omitempty"`
+\tServerName         string `json:"server_name,omitempty"`
+\tInsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}}
+
+// Profiles is a loaded profiles file.
+type Profiles struct {{
+\tProfiles map[string]Profile `json:"profiles"`
+
+\tdir string
+}}
+
+// LoadProfiles reads a profiles file; an empty path uses ProfilesEnv or the
+// default locations.
+func LoadProfiles(path string) (*Profiles, error) {{
+\tif path == "" {{
+\t\tpath = profilesPath()
+\t}}
+\tdata, err := os.ReadFile(path)
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("load profiles: %w", err)
+\t}}
+\tvar profiles Profiles
+\tif err := json.Unmarshal(data, &profiles); err != nil {{
+\t\treturn nil, fmt.Errorf("load profiles %s: %w", path, err)
+\t}}
+\tprofiles.dir = filepath.Dir(path)
+\treturn &profiles, nil
+}}
+
+func profilesPath() string {{
+\tif path := os.Getenv(ProfilesEnv); path != "" {{
+\t\treturn path
+\t}}
+\tif _, err := os.Stat("{file_prefix}-profiles.json"); err == nil {{
+\t\treturn "{file_prefix}-profiles.json"
+\t}}
+\thome, _ := os.UserHomeDir()
+\treturn filepath.Join(home, ".config", "{file_prefix}", "profiles.json")
+}}
+
+// Names returns the profile names, sorted.
+func (p *Profiles) Names() []string {{
+\tnames := make([]string, 0, len(p.Profiles))
+\tfor name := range p.Profiles {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\treturn names
+}}
+
+// Options returns the client options for the named profile.
+func (p *Profiles) Options(name string) ([]ClientOption, error) {{
+\tprofile, ok := p.Profiles[name]
+\tif !ok {{
+\t\treturn nil, fmt.Errorf("profile %q not found (have %v)", name, p.Names())
+\t}}
+\tvar opts []ClientOption
+\tif profile.BaseURL != "" {{
+\t\tbaseURL := strings.TrimSuffix(profile.BaseURL, "/")
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.BaseURL = baseURL }})
+\t}}
+
+\ttoken := profile.Token
+\tif profile.TokenEnv != "" {{
+\t\ttoken = os.Getenv(profile.TokenEnv)
+\t\tif token == "" {{
+\t\t\treturn nil, fmt.Errorf("profile %q: environment variable %s is not set", name, profile.TokenEnv)
+\t\t}}
+\t}}
+\tif token != "" {{
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.SetAuthToken(token) }})
+\t}}
+\tfor key, value := range profile.Headers {{
+\t\tkey, value := key, value
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.SetHeader(key, value) }})
+\t}}
+
+\tif profile.Timeout != "" {{
+\t\ttimeout, err := time.ParseDuration(profile.Timeout)
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("profile %q: timeout: %w", name, err)
+\t\t}}
+\t\topts = append(opts, WithTotalTimeout(timeout))
+\t}}
+\tif limit := profile.RateLimit; limit != nil {{
+\t\tper, err := time.ParseDuration(limit.Per)
+\t\tif err != nil || limit.Requests <= 0 || per <= 0 {{
+\t\t\treturn nil, fmt.Errorf("profile %q: rate_limit needs positive requests and per", name)
+\t\t}}
+\t\topts = append(opts, WithRateLimit(limit.Requests, per))
+\t}}
+\tif profile.TLS != nil {{
+\t\tconfig, err := p.tlsConfig(profile.TLS)
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("profile %q: %w", name, err)
+\t\t}}
+\t\topts = append(opts, WithTLSConfig(config))
+\t}}
+\tif len(profile.DisabledEndpoints) > 0 {{
+\t\topts = append(opts, WithDisabledEndpoints(profile.DisabledEndpoints...))
+\t}}
+\treturn opts, nil
+}}
+
+func (p *Profiles) tlsConfig(settings *ProfileTLS) (*tls.Config, error) {{
+\tconfig := &tls.Config{{
+\t\tServerName:         settings.ServerName,
+\t\tInsecureSkipVerify: settings.InsecureSkipVerify,
+\t}}
+\tif settings.CAFile != "" {{
+\t\tpem, err := os.ReadFile(p.resolve(settings.CAFile))
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("tls ca_file: %w", err)
+\t\t}}
+\t\tconfig.RootCAs = x509.NewCertPool()
+\t\tif !config.RootCAs.AppendCertsFromPEM(pem) {{
+\t\t\treturn nil, errors.New("tls ca_file: no certificates found")
+\t\t}}
+\t}}
+\tif settings.CertFile != "" || settings.KeyFile != "" {{
+\t\tcert, err := tls.LoadX509KeyPair(p.resolve(settings.CertFile), p.resolve(settings.KeyFile))
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("tls client certificate: %w", err)
+\t\t}}
+\t\tconfig.Certificates = []tls.Certificate{{cert}}
+\t}}
+\treturn config, nil
+}}
+
+func (p *Profiles) resolve(path string) string {{
+\tif path == "" || filepath.IsAbs(path) {{
+\t\treturn path
+\t}}
+\treturn filepath.Join(p.dir, path)
+}}
+
+// NewClientFromProfile creates a client for the named profile from the
+// profiles file (see ProfilesEnv), so switching between sandbox, staging,
+// and production needs no code changes. opts are applied after the
+// profile's settings and take precedence.
+func NewClientFromProfile(name string, opts ...ClientOption) (*{self.class_name}Client, error) {{
+\tprofiles, err := LoadProfiles("")
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tprofileOpts, err := profiles.Options(name)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\treturn New{self.class_name}Client("", append(profileOpts, opts...)...), nil
+}}
+"""
+    
+    def _generate_go_env(self, package_name: str) -> str:
+        env_prefix = self._env_prefix()
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"net/url"
+\t"os"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+// Environment variables read by NewClientFromEnv. Standard HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY settings are honored too, unless EnvProxy
+// overrides them.
+const (
+\tEnvEnvironment = "{env_prefix}_ENV"      // entry of Environments to target
+\tEnvProfile     = "{env_prefix}_PROFILE"  // profile to load from the profiles file
+\tEnvBaseURL     = "{env_prefix}_BASE_URL" // API base URL
+\tEnvToken       = "{env_prefix}_TOKEN"    // bearer token
+\tEnvAPIKey      = "{env_prefix}_API_KEY"  // API key, sent as the captured requests sent theirs
+\tEnvTimeout     = "{env_prefix}_TIMEOUT"  // Go duration, or whole seconds
+\tEnvProxy       = "{env_prefix}_PROXY"    // proxy URL for all requests
+)
+
+// EnvOptions returns client options for the configuration in the
+// environment, starting with the environment named by EnvEnvironment and
+// the profile named by EnvProfile, if any.
+func EnvOptions() ([]ClientOption, error) {{
+\tvar opts []ClientOption
+\tif name := os.Getenv(EnvEnvironment); name != "" {{
+\t\tenvOpts, err := EnvironmentOptions(name)
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("%s: %w", EnvEnvironment, err)
+\t\t}}
+\t\topts = append(opts, envOpts...)
+\t}}
+\tif name := os.Getenv(EnvProfile); name != "" {{
+\t\tprofiles, err := LoadProfiles("")
+\t\tif err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\tprofileOpts, err := profiles.Options(name)
+\t\tif err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\topts = append(opts, profileOpts...)
+\t}}
+
+\tif baseURL := os.Getenv(EnvBaseURL); baseURL != "" {{
+\t\tbaseURL = strings.TrimSuffix(baseURL, "/")
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.BaseURL = baseURL }})
+\t}}
+\tif token := os.Getenv(EnvToken); token != "" {{
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.SetAuthToken(token) }})
+\t}}
+\tif key := os.Getenv(EnvAPIKey); key != "" {{
+\t\topts = append(opts, func(c *{self.class_name}Client) {{ c.SetAPIKey(key) }})
+\t}}
+\tif value := os.Getenv(EnvTimeout); value != "" {{
+\t\ttimeout, err := time.ParseDuration(value)
+\t\tif seconds, convErr := strconv.Atoi(value); convErr == nil {{
+\t\t\ttimeout, err = time.Duration(seconds)*time.Second, nil
+\t\t}}
+\t\tif err != nil || timeout < 0 {{
+\t\t\treturn nil, fmt.Errorf("%s: invalid timeout %q", EnvTimeout, value)
+\t\t}}
+\t\topts = append(opts, WithTotalTimeout(timeout))
+\t}}
+\tif value := os.Getenv(EnvProxy); value != "" {{
+\t\tproxyURL, err := url.Parse(value)
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("%s: %w", EnvProxy, err)
+\t\t}}
+\t\topts = append(opts, WithProxy(proxyURL))
+\t}}
+\treturn opts, nil
+}}
+
+// NewClientFromEnv creates a client configured from the environment, for
+// twelve-factor deployments. Settings apply in order of increasing
+// precedence: the built-in defaults, the environment named by
+// EnvEnvironment, the profile named by EnvProfile, the other Env*
+// variables, then opts.
+func NewClientFromEnv(opts ...ClientOption) (*{self.class_name}Client, error) {{
+\tenvOpts, err := EnvOptions()
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\treturn New{self.class_name}Client("", append(envOpts, opts...)...), nil
+}}
+"""
+    
+    def _generate_go_secret(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"errors"
+\t"fmt"
+\t"net/http"
+\t"os"
+\t"path/filepath"
+\t"strings"
+\t"sync"
+\t"time"
+)
+
+// ErrSecretNotFound is returned (wrapped) by the bundled providers for
+// secrets they do not hold.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider supplies credentials by name, so they never have to be
+// hardcoded into client construction. Implement it over Vault, AWS Secrets
+// Manager, or the OS keychain; EnvSecrets and FileSecrets cover the simple
+// cases.
+type SecretProvider interface {{
+\tSecret(ctx context.Context, name string) (string, error)
+}}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Secret implements SecretProvider.
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {{
+\treturn f(ctx, name)
+}}
+
+// EnvSecrets reads secrets from environment variables named Prefix+name.
+type EnvSecrets struct {{
+\tPrefix string
+}}
+
+// Secret implements SecretProvider.
+func (e EnvSecrets) Secret(_ context.Context, name string) (string, error) {{
+\tvalue, ok := os.LookupEnv(e.Prefix + name)
+\tif !ok {{
+\t\treturn "", fmt.Errorf("%w: environment variable %s", ErrSecretNotFound, e.Prefix+name)
+\t}}
+\treturn value, nil
+}}
+
+// FileSecrets reads secrets from files named after them in Dir, as mounted
+// by Docker and Kubernetes. Surrounding whitespace is trimmed.
+type FileSecrets struct {{
+\tDir string
+}}
+
+// Secret implements SecretProvider.
+func (f FileSecrets) Secret(_ context.Context, name string) (string, error) {{
+\tdata, err := os.ReadFile(filepath.Join(f.Dir, filepath.Base(name)))
+\tif errors.Is(err, os.ErrNotExist) {{
+\t\treturn "", fmt.Errorf("%w: %v", ErrSecretNotFound, err)
+\t}}
+\tif err != nil {{
+\t\treturn "", err
+\t}}
+\treturn strings.TrimSpace(string(data)), nil
+}}
+
+// SecretCache caches the secrets of another provider for a TTL (forever
+// when zero), so remote stores are asked once per rotation rather than on
+// every request. It is safe for concurrent use.
+type SecretCache struct {{
+\tprovider SecretProvider
+\tttl      time.Duration
+
+\tmu      sync.Mutex
+\tentries map[string]cachedSecret
+}}
+
+type cachedSecret struct {{
+\tvalue   string
+\tfetched time.Time
+}}
+
+// NewSecretCache wraps provider with a cache.
+func NewSecretCache(provider SecretProvider, ttl time.Duration) *SecretCache {{
+\treturn &SecretCache{{provider: provider, ttl: ttl, entries: make(map[string]cachedSecret)}}
+}}
+
+// Secret implements SecretProvider.
+func (c *SecretCache) Secret(ctx context.Context, name string) (string, error) {{
+\tc.mu.Lock()
+\tentry, ok := c.entries[name]
+\tc.mu.Unlock()
+\tif ok && (c.ttl == 0 || time.Since(entry.fetched) < c.ttl) {{
+\t\treturn entry.value, nil
+\t}}
+
+\tvalue, err := c.provider.Secret(ctx, name)
+\tif err != nil {{
+\t\treturn "", err
+\t}}
+\tc.mu.Lock()
+\tc.entries[name] = cachedSecret{{value: value, fetched: time.Now()}}
+\tc.mu.Unlock()
+\treturn value, nil
+}}
+
+// Invalidate drops the cached value of name, e.g. after it was rotated.
+func (c *SecretCache) Invalidate(name string) {{
+\tc.mu.Lock()
+\tdelete(c.entries, name)
+\tc.mu.Unlock()
+}}
+
+func (c *SecretCache) expire(name, _ string) {{
+\tc.Invalidate(name)
+}}
+
+// credentialSource supplies credential values; expire drops a value the API
+// rejected.
+type credentialSource interface {{
+\tSecret(ctx context.Context, name string) (string, error)
+\texpire(name, rejected string)
+}}
+
+// secretCredential is a header filled from a secret on every request.
+type secretCredential struct {{
+\tsecrets credentialSource
+\tname    string
+\theader  string
+\tprefix  string
+}}
+
+// WithSecretToken sends the secret name from provider as the bearer token.
+// It is fetched on first use and cached for ttl; a 401 response drops the
+// cached value, so the call after a rotation picks up the new token.
+func WithSecretToken(provider SecretProvider, name string, ttl time.Duration) ClientOption {{
+\treturn withSecret(provider, name, ttl, "Authorization", "Bearer ")
+}}
+
+// WithSecretHeader is WithSecretToken for API keys sent verbatim in header.
+func WithSecretHeader(provider SecretProvider, name, header string, ttl time.Duration) ClientOption {{
+\treturn withSecret(provider, name, ttl, header, "")
+}}
+
+func withSecret(provider SecretProvider, name string, ttl time.Duration, header, prefix string) ClientOption {{
+\tcredential := &secretCredential{{secrets: NewSecretCache(provider, ttl), name: name, header: header, prefix: prefix}}
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.credentials = append(c.credentials, credential)
+\t}}
+}}
+
+// applyCredentials sets the secret headers on req.
+func (c *{self.class_name}Client) applyCredentials(req *http.Request) error {{
+\tfor _, credential := range c.credentials {{
+\t\tvalue, err := credential.secrets.Secret(req.Context(), credential.name)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("secret %s: %w", credential.name, err)
+\t\t}}
+\t\treq.Header.Set(credential.header, credential.prefix+value)
+\t}}
+\treturn nil
+}}
+
+// expireCredentials drops the cached secrets req carried after the API
+// rejected them.
+func (c *{self.class_name}Client) expireCredentials(req *http.Request) {{
+\tfor _, credential := range c.credentials {{
+\t\trejected := strings.TrimPrefix(req.Header.Get(credential.header), credential.prefix)
+\t\tcredential.secrets.expire(credential.name, rejected)
+\t}}
+}}
+"""
+    
+    def _generate_go_limits(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"fmt"
+\t"io"
+\t"net/http"
+)
+
+// SizeLimitError reports a request body or response body larger than the
+// limit set with WithMaxRequestBytes or WithMaxResponseBytes.
+type SizeLimitError struct {{
+\tDirection string // "request" or "response"
+\tLimit     int64
+\tSize      int64 // bytes seen; for responses, at least Limit+1
+}}
+
+func (e *SizeLimitError) Error() string {{
+\treturn fmt.Sprintf("%s body exceeds the %d byte limit (%d bytes)", e.Direction, e.Limit, e.Size)
+}}
+
+// WithMaxRequestBytes rejects calls whose serialized body is larger than n
+// bytes before they are sent.
+func WithMaxRequestBytes(n int64) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.limits.request = n
+\t}}
+}}
+
+// WithMaxResponseBytes stops reading responses after n bytes and fails the
+// call, protecting the program from runaway responses.
+func WithMaxResponseBytes(n int64) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.limits.response = n
+\t}}
+}}
+
+// sizeLimits holds the byte limits; zero means unlimited.
+type sizeLimits struct {{
+\trequest  int64
+\tresponse int64
+}}
+
+// checkRequest checks the size of a request body, -1 when unknown.
+func (l sizeLimits) checkRequest(size int64) error {{
+\tif l.request > 0 && size > l.request {{
+\t\treturn &SizeLimitError{{Direction: "request", Limit: l.request, Size: size}}
+\t}}
+\treturn nil
+}}
+
+// readResponse reads the response body, up to the response limit.
+func (l sizeLimits) readResponse(resp *http.Response) ([]byte, error) {{
+\tif l.response <= 0 {{
+\t\treturn readAll(resp.Body, resp.ContentLength)
+\t}}
+\tif resp.ContentLength > l.response {{
+\t\treturn nil, &SizeLimitError{{Direction: "response", Limit: l.response, Size: resp.ContentLength}}
+\t}}
+\tdata, err := readAll(io.LimitReader(resp.Body, l.response+1), resp.ContentLength)
+\tif err == nil && int64(len(data)) > l.response {{
+\t\treturn nil, &SizeLimitError{{Direction: "response", Limit: l.response, Size: int64(len(data))}}
+\t}}
+\treturn data, err
+}}
+
+// maxPresize caps the buffer allocated up front from a Content-Length, which
+// the server may get wrong.
+const maxPresize = 64 << 20
+
+// readAll reads r to the end into a buffer sized for size bytes, when
+// known, instead of growing it as io.ReadAll does.
+func readAll(r io.Reader, size int64) ([]byte, error) {{
+\tif size < 0 {{
+\t\treturn io.ReadAll(r)
+\t}}
+\tif size > maxPresize {{
+\t\tsize = maxPresize
+\t}}
+\tbuf := bytes.NewBuffer(make([]byte, 0, size+bytes.MinRead))
+\t_, err := buf.ReadFrom(r)
+\treturn buf.Bytes(), err
+}}
+"""
+    
+    def _generate_go_client_test(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"net/http"
+\t"net/http/httptest"
+\t"sync"
+\t"testing"
+)
+
+// TestConcurrentHeaders changes the client's headers and credentials while
+// other goroutines make calls and derive clients; run it with -race.
+func TestConcurrentHeaders(t *testing.T) {{
+\tserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {{
+\t\tw.Write([]byte(`{{}}`))
+\t}}))
+\tdefer server.Close()
+\tc := New{self.class_name}Client(server.URL)
+
+\tvar wg sync.WaitGroup
+\tfor i := 0; i < 8; i++ {{
+\t\twg.Add(2)
+\t\tgo func(i int) {{
+\t\t\tdefer wg.Done()
+\t\t\tc.SetAuthToken(fmt.Sprint("token-", i))
+\t\t\tc.SetHeader("X-Request-Tag", fmt.Sprint(i))
+\t\t\tc.setCookie("session", fmt.Sprint(i))
+\t\t\tc.setQueryCredential("api_key", fmt.Sprint(i))
+\t\t}}(i)
+\t\tgo func(i int) {{
+\t\t\tdefer wg.Done()
+\t\t\tderived := c.WithHeader("X-Worker", fmt.Sprint(i))
+\t\t\tfor _, client := range []*{self.class_name}Client{{c, derived}} {{
+\t\t\t\tif _, err := client.doRequest(context.Background(), "GET", "/ping", "/ping", nil, nil); err != nil {{
+\t\t\t\t\tt.Error(err)
+\t\t\t\t}}
+\t\t\t}}
+\t\t}}(i)
+\t}}
+\twg.Wait()
+
+\tif got := c.headers()["X-Worker"]; got != "" {{
+\t\tt.Errorf("WithHeader changed the parent client: X-Worker = %q", got)
+\t}}
+}}
+
+func TestWithHeaderLeavesParent(t *testing.T) {{
+\tc := New{self.class_name}Client("")
+\tc.SetHeader("X-Tag", "parent")
+\tderived := c.WithHeader("X-Tag", "derived")
+\tc.SetHeader("X-Other", "parent")
+
+\tif got := c.headers()["X-Tag"]; got != "parent" {{
+\t\tt.Errorf("parent X-Tag = %q, want parent", got)
+\t}}
+\tif got := derived.headers()["X-Tag"]; got != "derived" {{
+\t\tt.Errorf("derived X-Tag = %q, want derived", got)
+\t}}
+\tif got, ok := derived.headers()["X-Other"]; ok {{
+\t\tt.Errorf("derived client saw a later parent header: X-Other = %q", got)
+\t}}
+}}
+"""
+    
+    def _generate_go_transport_test(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"crypto/ecdsa"
+\t"crypto/elliptic"
+\t"crypto/rand"
+\t"crypto/tls"
+\t"crypto/x509"
+\t"crypto/x509/pkix"
+\t"errors"
+\t"math/big"
+\t"testing"
+\t"time"
+)
+
+// testCertificate issues a certificate for host signed by parent (or
+// self-signed when parent is nil).
+func testCertificate(t *testing.T, host string, ca bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {{
+\tt.Helper()
+\tkey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+\tif err != nil {{
+\t\tt.Fatal(err)
+\t}}
+\ttemplate := &x509.Certificate{{
+\t\tSerialNumber:          big.NewInt(time.Now().UnixNano()),
+\t\tSubject:               pkix.Name{{CommonName: host}},
+\t\tNotBefore:             time.Now().Add(-time.Hour),
+\t\tNotAfter:              time.Now().Add(time.Hour),
+\t\tIsCA:                  ca,
+\t\tBasicConstraintsValid: true,
+\t\tKeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+\t\tExtKeyUsage:           []x509.ExtKeyUsage{{x509.ExtKeyUsageServerAuth}},
+\t}}
+\tif !ca {{
+\t\ttemplate.DNSNames = []string{{host}}
+\t}}
+\tif parent == nil {{
+\t\tparent, parentKey = template, key
+\t}}
+\tder, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+\tif err != nil {{
+\t\tt.Fatal(err)
+\t}}
+\tcert, err := x509.ParseCertificate(der)
+\tif err != nil {{
+\t\tt.Fatal(err)
+\t}}
+\treturn cert, key
+}}
+
+func TestVerifyPins(t *testing.T) {{
+\tca, caKey := testCertificate(t, "Pinned CA", true, nil, nil)
+\tleaf, _ := testCertificate(t, "api.example.com", false, ca, caKey)
+\tselfSigned, _ := testCertificate(t, "api.example.com", false, nil, nil)
+\tattacker, _ := testCertificate(t, "api.example.com", false, nil, nil)
+
+\ttests := []struct {{
+\t\tname   string
+\t\tpins   []*x509.Certificate
+\t\tchain  []*x509.Certificate
+\t\tserver string
+\t\tok     bool
+\t}}{{
+\t\t{{"pinned leaf", []*x509.Certificate{{selfSigned}}, []*x509.Certificate{{selfSigned}}, "api.example.com", true}},
+\t\t{{"leaf signed by pinned CA", []*x509.Certificate{{ca}}, []*x509.Certificate{{leaf, ca}}, "api.example.com", true}},
+\t\t{{"pinned CA for another host", []*x509.Certificate{{ca}}, []*x509.Certificate{{leaf, ca}}, "other.example.com", false}},
+\t\t{{"unpinned leaf", []*x509.Certificate{{ca}}, []*x509.Certificate{{selfSigned}}, "api.example.com", false}},
+\t\t{{"pinned certificate appended to another leaf", []*x509.Certificate{{selfSigned}}, []*x509.Certificate{{attacker, selfSigned}}, "api.example.com", false}},
+\t\t{{"pinned CA appended to another leaf", []*x509.Certificate{{ca}}, []*x509.Certificate{{attacker, leaf, ca}}, "api.example.com", false}},
+\t\t{{"no certificates", []*x509.Certificate{{ca}}, nil, "api.example.com", false}},
+\t}}
+\tfor _, tt := range tests {{
+\t\tt.Run(tt.name, func(t *testing.T) {{
+\t\t\tpinned := map[string]bool{{}}
+\t\t\tfor _, cert := range tt.pins {{
+\t\t\t\tpinned[spkiPin(cert)] = true
+\t\t\t}}
+\t\t\terr := verifyPins(tls.ConnectionState{{PeerCertificates: tt.chain, ServerName: tt.server}}, pinned)
+\t\t\tif tt.ok && err != nil {{
+\t\t\t\tt.Fatalf("verifyPins: %v", err)
+\t\t\t}}
+\t\t\tif !tt.ok && !errors.Is(err, ErrCertificatePin) {{
+\t\t\t\tt.Fatalf("verifyPins = %v, want ErrCertificatePin", err)
+\t\t\t}}
+\t\t}})
+\t}}
+}}
+"""
+    
+    def _generate_go_transport(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"crypto/sha256"
+\t"crypto/tls"
+\t"crypto/x509"
+\t"encoding/base64"
+\t"errors"
+\t"fmt"
+\t"net"
+\t"net/http"
+\t"net/url"
+\t"strings"
+\t"time"
+)
+
+// Default per-phase timeouts of the client's transport. There is no overall
+// deadline by default, so long downloads and streams are not cut off once
+// the response has started; set one with WithTotalTimeout.
+const (
+\tDefaultDialTimeout           = 10 * time.Second
+\tDefaultTLSHandshakeTimeout   = 10 * time.Second
+\tDefaultResponseHeaderTimeout = 30 * time.Second
+)
+
+// Default connection pool settings. Go's default of 2 idle connections per
+// host makes concurrent SDK callers re-dial constantly; clients keep more.
+const (
+\tDefaultMaxIdleConnsPerHost = 32
+\tDefaultIdleConnTimeout     = 90 * time.Second
+)
+
+const keepAliveInterval = 30 * time.Second
+
+// newTransport builds the transport clients are created with: a clone of
+// http.DefaultTransport, keeping its proxy and HTTP/2 settings, with the
+// default per-phase timeouts and pool settings.
+func newTransport() *http.Transport {{
+\ttransport := http.DefaultTransport.(*http.Transport).Clone()
+\ttransport.DialContext = (&net.Dialer{{Timeout: DefaultDialTimeout, KeepAlive: keepAliveInterval}}).DialContext
+\ttransport.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+\ttransport.ResponseHeaderTimeout = DefaultResponseHeaderTimeout
+\ttransport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+\ttransport.IdleConnTimeout = DefaultIdleConnTimeout
+\treturn transport
+}}
+
+// WithDialTimeout limits how long establishing a TCP connection may take.
+func WithDialTimeout(d time.Duration) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.DialContext = (&net.Dialer{{Timeout: d, KeepAlive: keepAliveInterval}}).DialContext
+\t\t}}
+\t}}
+}}
+
+// WithTLSHandshakeTimeout limits how long the TLS handshake may take.
+func WithTLSHandshakeTimeout(d time.Duration) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.TLSHandshakeTimeout = d
+\t\t}}
+\t}}
+}}
+
+// WithResponseHeaderTimeout limits how long to wait for the response headers
+// once the request is written; reading the body is not covered, so slow
+// streaming responses keep flowing.
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.ResponseHeaderTimeout = d
+\t\t}}
+\t}}
+}}
+
+// WithTotalTimeout limits each call end to end, including reading the
+// response body. Zero means no limit.
+func WithTotalTimeout(d time.Duration) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.HTTPClient.Timeout = d
+\t}}
+}}
+
+// WithMaxIdleConnsPerHost sets how many idle connections are kept per host
+// for reuse.
+func WithMaxIdleConnsPerHost(n int) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.MaxIdleConnsPerHost = n
+\t\t\tif transport.MaxIdleConns != 0 && transport.MaxIdleConns < n {{
+\t\t\t\ttransport.MaxIdleConns = n
+\t\t\t}}
+\t\t}}
+\t}}
+}}
+
+// WithMaxConnsPerHost caps the connections per host, idle or in use; calls
+// beyond it wait for a free connection. Zero means no limit.
+func WithMaxConnsPerHost(n int) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.MaxConnsPerHost = n
+\t\t}}
+\t}}
+}}
+
+// WithIdleConnTimeout sets how long an idle connection stays in the pool.
+func WithIdleConnTimeout(d time.Duration) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.IdleConnTimeout = d
+\t\t}}
+\t}}
+}}
+
+// WithoutKeepAlives uses a new connection for every request, e.g. behind
+// load balancers that pin connections to one backend.
+func WithoutKeepAlives() ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.DisableKeepAlives = true
+\t\t}}
+\t}}
+}}
+
+// WithTLSConfig sets the TLS configuration used to reach the API, e.g. to
+// trust a private CA or present a client certificate. It applies to the
+// client's *http.Transport, so it has no effect after HTTPClient.Transport
+// is replaced with another RoundTripper.
+func WithTLSConfig(config *tls.Config) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.TLSClientConfig = config
+\t\t}}
+\t}}
+}}
+
+// ErrCertificatePin is returned when the server's certificate chain has no
+// key pinned with WithPinnedCertificates.
+var ErrCertificatePin = errors.New("server certificate does not match a pinned key")
+
+// WithPinnedCertificates trusts the server only if its certificate, or one
+// its certificate chains to, has a public key whose SHA-256 is among pins,
+// in place of checking the chain against the system roots. This reaches
+// APIs with self-signed certificates without turning verification off.
+// Pinning a CA's key requires the server's certificate to be signed by it
+// and valid for the host. Pins are base64, with or
+// without a "sha256/" prefix, as printed by
+//
+//\topenssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+//
+// It extends the TLS configuration in place, so use it after WithTLSConfig.
+func WithPinnedCertificates(pins ...string) ClientOption {{
+\tpinned := make(map[string]bool, len(pins))
+\tfor _, pin := range pins {{
+\t\tpinned[strings.TrimPrefix(pin, "sha256/")] = true
+\t}}
+\treturn func(c *{self.class_name}Client) {{
+\t\ttransport := c.transport()
+\t\tif transport == nil {{
+\t\t\treturn
+\t\t}}
+\t\tconfig := &tls.Config{{}}
+\t\tif transport.TLSClientConfig != nil {{
+\t\t\tconfig = transport.TLSClientConfig.Clone()
+\t\t}}
+\t\t// The chain is checked against the pins alone, below.
+\t\tconfig.InsecureSkipVerify = true
+\t\tconfig.VerifyConnection = func(state tls.ConnectionState) error {{
+\t\t\treturn verifyPins(state, pinned)
+\t\t}}
+\t\ttransport.TLSClientConfig = config
+\t}}
+}}
+
+// verifyPins accepts a connection whose leaf certificate has a pinned key,
+// or whose leaf chains, through the certificates the server sent, to one
+// that has. The handshake only proves the server holds the leaf's key, so
+// a pinned certificate merely sent along with another leaf is not enough.
+func verifyPins(state tls.ConnectionState, pinned map[string]bool) error {{
+\tcerts := state.PeerCertificates
+\tif len(certs) == 0 {{
+\t\treturn ErrCertificatePin
+\t}}
+\tif pinned[spkiPin(certs[0])] {{
+\t\treturn nil
+\t}}
+\troots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+\tfor _, cert := range certs[1:] {{
+\t\tif pinned[spkiPin(cert)] {{
+\t\t\troots.AddCert(cert)
+\t\t}} else {{
+\t\t\tintermediates.AddCert(cert)
+\t\t}}
+\t}}
+\topts := x509.VerifyOptions{{DNSName: state.ServerName, Roots: roots, Intermediates: intermediates}}
+\tif _, err := certs[0].Verify(opts); err != nil {{
+\t\treturn fmt.Errorf("%w: %v", ErrCertificatePin, err)
+\t}}
+\treturn nil
+}}
+
+// spkiPin is the base64 SHA-256 of cert's public key.
+func spkiPin(cert *x509.Certificate) string {{
+\tsum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+\treturn base64.StdEncoding.EncodeToString(sum[:])
+}}
+
+// WithHTTP2 turns HTTP/2 on or off. It is on by default, negotiated with
+// servers that offer it; turning it off helps with servers and proxies that
+// handle HTTP/2 badly, and spreads concurrent calls over several
+// connections instead of multiplexing them on one.
+func WithHTTP2(enabled bool) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\ttransport := c.transport()
+\t\tif transport == nil {{
+\t\t\treturn
+\t\t}}
+\t\ttransport.ForceAttemptHTTP2 = enabled
+\t\tif enabled {{
+\t\t\ttransport.TLSNextProto = nil
+\t\t\treturn
+\t\t}}
+\t\t// A non-nil, empty map keeps the transport from configuring HTTP/2,
+\t\t// and servers must no longer be offered h2 when negotiating.
+\t\ttransport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{{}}
+\t\tif transport.TLSClientConfig != nil {{
+\t\t\tconfig := transport.TLSClientConfig.Clone()
+\t\t\tconfig.NextProtos = nil
+\t\t\tfor _, proto := range transport.TLSClientConfig.NextProtos {{
+\t\t\t\tif proto != "h2" {{
+\t\t\t\t\tconfig.NextProtos = append(config.NextProtos, proto)
+\t\t\t\t}}
+\t\t\t}}
+\t\t\ttransport.TLSClientConfig = config
+\t\t}}
+\t}}
+}}
+
+// WithProxy sends requests through the proxy at proxyURL instead of the one
+// named by the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+// Like WithTLSConfig, it needs the client's *http.Transport.
+func WithProxy(proxyURL *url.URL) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif transport := c.transport(); transport != nil {{
+\t\t\ttransport.Proxy = http.ProxyURL(proxyURL)
+\t\t}}
+\t}}
+}}
+
+// transport returns the *http.Transport the client sends requests through,
+// looking behind a DumpTransport and installing a new one when none is set,
+// so options never mutate http.DefaultTransport. It returns nil for other
+// RoundTrippers.
+func (c *{self.class_name}Client) transport() *http.Transport {{
+\tnext := &c.HTTPClient.Transport
+\tfor {{
+\t\tswitch t := (*next).(type) {{
+\t\tcase nil:
+\t\t\ttransport := newTransport()
+\t\t\t*next = transport
+\t\t\treturn transport
+\t\tcase *http.Transport:
+\t\t\treturn t
+\t\tcase *DumpTransport:
+\t\t\tnext = &t.Next
+\t\tdefault:
+\t\t\treturn nil
+\t\t}}
+\t}}
+}}
+"""
+    
+    def _generate_go_lifecycle(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"errors"
+\t"sync"
+)
+
+// ErrClientClosed is returned by calls made after Close.
+var ErrClientClosed = errors.New("client closed")
+
+// lifecycle tracks in-flight calls so Close can wait for them. Derived
+// clients share their parent's, so closing either closes both.
+type lifecycle struct {{
+\tmu       sync.Mutex
+\tclosed   bool
+\tinflight sync.WaitGroup
+\thooks    []func(context.Context) error
+}}
+
+// enter registers a call, failing once the client is closed.
+func (l *lifecycle) enter() error {{
+\tl.mu.Lock()
+\tdefer l.mu.Unlock()
+\tif l.closed {{
+\t\treturn ErrClientClosed
+\t}}
+\tl.inflight.Add(1)
+\treturn nil
+}}
+
+func (l *lifecycle) exit() {{
+\tl.inflight.Done()
+}}
+
+// WithCloseFunc registers fn to run during Close once in-flight calls have
+// finished, e.g. to flush a metrics exporter or a drift monitor's alerts.
+func WithCloseFunc(fn func(ctx context.Context) error) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.lifecycle.hooks = append(c.lifecycle.hooks, fn)
+\t}}
+}}
+
+// Close shuts the client down for a service's shutdown sequence: new calls
+// fail with ErrClientClosed, in-flight calls are waited for (until ctx is
+// done, when Close returns ctx.Err() and leaves them running), functions
+// registered with WithCloseFunc run, and idle connections are closed.
+// Closing again only closes idle connections.
+func (c *{self.class_name}Client) Close(ctx context.Context) error {{
+\tc.lifecycle.mu.Lock()
+\tc.lifecycle.closed = true
+\tc.lifecycle.mu.Unlock()
+
+\tdone := make(chan struct{{}})
+\tgo func() {{
+\t\tc.lifecycle.inflight.Wait()
+\t\tclose(done)
+\t}}()
+\tselect {{
+\tcase <-done:
+\tcase <-ctx.Done():
+\t\treturn ctx.Err()
+\t}}
+
+\tc.lifecycle.mu.Lock()
+\thooks := c.lifecycle.hooks
+\tc.lifecycle.hooks = nil
+\tc.lifecycle.mu.Unlock()
+\tvar errs []error
+\tfor _, hook := range hooks {{
+\t\tif err := runCloseFunc(ctx, hook); err != nil {{
+\t\t\terrs = append(errs, err)
+\t\t}}
+\t}}
+\tc.HTTPClient.CloseIdleConnections()
+\treturn errors.Join(errs...)
+}}
+"""
+    
+    def _generate_go_optional(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+// Ptr returns a pointer to v, for the optional fields of request structs.
+// They are pointers so that a field left nil, which is not sent, differs
+// from one set to its zero value:
+//
+//\treq.Nickname = Ptr("") // sends "nickname": ""
+func Ptr[T any](v T) *T {{
+\treturn &v
+}}
+"""
+    
+    def _generate_go_path(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"net/url"
+\t"strings"
+)
+
+// pathSegment escapes a path parameter so that it stays one segment of the
+// path: "/", "?", "#", and other reserved characters are percent-encoded,
+// and "." and "..", which would be resolved as relative references, are
+// encoded in full.
+func pathSegment(value string) string {{
+\tswitch value {{
+\tcase ".":
+\t\treturn "%2E"
+\tcase "..":
+\t\treturn "%2E%2E"
+\t}}
+\treturn url.PathEscape(value)
+}}
+
+// checkPath rejects a request path, ending with the path of endpoint, whose
+// parameters were left empty. It would address another resource, such as
+// the collection rather than one of its items.
+func checkPath(endpoint, path string) error {{
+\tpattern, segments := strings.Split(endpoint, "/"), strings.Split(path, "/")
+\tif len(segments) < len(pattern) {{
+\t\treturn nil
+\t}}
+\tsegments = segments[len(segments)-len(pattern):]
+\tfor i, segment := range pattern {{
+\t\tif strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}") && segments[i] == "" {{
+\t\t\treturn fmt.Errorf("%s: empty path parameter %s", endpoint, strings.Trim(segment, "{{}}"))
+\t\t}}
+\t}}
+\treturn nil
+}}
+"""
+    
+    def _generate_go_recover(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"runtime/debug"
+)
+
+// PanicError is a panic recovered from code running inside a call, such as
+// a custom JSON unmarshaler, a schema mismatch handler, a RoundTripper, or
+// a SecretProvider, returned as the call's error instead of crashing the
+// program.
+type PanicError struct {{
+\tOperation string // "GET /v1/users/{{id}}", or "Close"
+\tAttempt   int    // 1 for the first attempt
+\tValue     interface{{}}
+\tStack     []byte
+}}
+
+func (e *PanicError) Error() string {{
+\treturn fmt.Sprintf("%s (attempt %d): panic: %v", e.Operation, e.Attempt, e.Value)
+}}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {{
+\terr, _ := e.Value.(error)
+\treturn err
+}}
+
+// recoverPanic turns a panic into a *PanicError stored in *err. It must be
+// deferred directly.
+func recoverPanic(operation string, attempt int, err *error) {{
+\tif r := recover(); r != nil {{
+\t\t*err = &PanicError{{Operation: operation, Attempt: attempt, Value: r, Stack: debug.Stack()}}
+\t}}
+}}
+
+// runCloseFunc calls a WithCloseFunc function, recovering panics.
+func runCloseFunc(ctx context.Context, fn func(context.Context) error) (err error) {{
+\tdefer recoverPanic("Close", 1, &err)
+\treturn fn(ctx)
+}}
+"""
+    
+    def _generate_go_redirect(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"net/http"
+\t"net/url"
+\t"regexp"
+)
+
+// RedirectPolicy controls how a client follows 3xx responses. The zero value
+// follows up to 10 redirects and strips credentials when a redirect leaves
+// the original origin, e.g. for a 302 to a signed storage URL that rejects
+// requests carrying an Authorization header.
+type RedirectPolicy struct {{
+\t// NoFollow returns every 3xx response to the caller as a *RedirectError.
+\tNoFollow bool
+\t// MaxRedirects caps the redirects followed per call; zero means 10.
+\tMaxRedirects int
+\t// SameHostOnly returns redirects to another host as a *RedirectError
+\t// instead of following them.
+\tSameHostOnly bool
+\t// KeepCredentials re-sends Authorization, cookies, and API key headers
+\t// to other origins.
+\tKeepCredentials bool
+}}
+
+// RedirectError is a 3xx response the client did not follow. The caller
+// can fetch Location itself.
+type RedirectError struct {{
+\tStatusCode int
+\tLocation   string
+}}
+
+func (e *RedirectError) Error() string {{
+\treturn fmt.Sprintf("redirect not followed: status=%d, location=%s", e.StatusCode, e.Location)
+}}
+
+// WithRedirectPolicy sets how the client handles redirects.
+func WithRedirectPolicy(policy RedirectPolicy) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.HTTPClient.CheckRedirect = policy.check
+\t}}
+}}
+
+// credentialHeader matches header names that carry credentials.
+var credentialHeader = regexp.MustCompile(`(?i)^(authorization|cookie)$|api[-_]?key|token|secret|auth`)
+
+// check implements http.Client.CheckRedirect.
+func (p RedirectPolicy) check(req *http.Request, via []*http.Request) error {{
+\torigin := via[0].URL
+\tif p.NoFollow || p.SameHostOnly && req.URL.Host != origin.Host {{
+\t\treturn http.ErrUseLastResponse
+\t}}
+\tlimit := p.MaxRedirects
+\tif limit == 0 {{
+\t\tlimit = 10
+\t}}
+\tif len(via) > limit {{
+\t\treturn fmt.Errorf("stopped after %d redirects", limit)
+\t}}
+\tif !p.KeepCredentials && !sameOrigin(req.URL, origin) {{
+\t\tfor name := range req.Header {{
+\t\t\tif credentialHeader.MatchString(name) {{
+\t\t\t\treq.Header.Del(name)
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+func sameOrigin(a, b *url.URL) bool {{
+\treturn a.Scheme == b.Scheme && a.Host == b.Host
+}}
+
+// redirectError returns a *RedirectError if resp is an unfollowed redirect.
+func redirectError(resp *http.Response) error {{
+\tif resp.StatusCode < 300 || resp.StatusCode >= 400 {{
+\t\treturn nil
+\t}}
+\tlocation, err := resp.Location()
+\tif err != nil {{
+\t\treturn nil
+\t}}
+\treturn &RedirectError{{StatusCode: resp.StatusCode, Location: location.String()}}
+}}
+"""
+    
+    IDEMPOTENCY_HEADERS = ('idempotency-key', 'x-idempotency-key')
+    
+    def _captured_idempotency_header(self) -> str:
+        """The idempotency key header the captured requests carried, or ""."""
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                for name in (example.get('request', {}).get('headers') or {}):
+                    if name.lower() in self.IDEMPOTENCY_HEADERS:
+                        return '-'.join(word.capitalize() for word in name.split('-'))
+        return ''
+    
+    def _generate_go_idempotency(self, package_name: str, captured_header: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"crypto/rand"
+\t"fmt"
+\t"net/http"
+)
+
+// capturedIdempotencyHeader is the header the captured requests carried
+// idempotency keys in, so that clients send them as well; empty when they
+// carried none.
+const capturedIdempotencyHeader = "{captured_header}"
+
+// WithIdempotencyKeys sends a new key in header, usually "Idempotency-Key",
+// with every POST, PUT, PATCH, and DELETE call, and the same key with each
+// retry of the call, so that a server deduplicating requests by key acts on
+// the call once. Calls with a key are retried like idempotent ones. An
+// empty header stops the keys.
+func WithIdempotencyKeys(header string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.idempotencyHeader = header
+\t}}
+}}
+
+// WithIdempotencyKey sends key as the call's idempotency key, in place of a
+// generated one, for instance to repeat a call whose outcome was lost with
+// the process that made it.
+func WithIdempotencyKey(key string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.idempotencyKey = key
+\t}}
+}}
+
+// assignIdempotencyKey gives a call with an unsafe method a new key when
+// the client sends keys and the caller supplied none.
+func (c *{self.class_name}Client) assignIdempotencyKey(method string, options *requestOptions) {{
+\tif options.idempotencyKey != "" || c.idempotencyHeader == "" {{
+\t\treturn
+\t}}
+\tswitch method {{
+\tcase http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+\t\toptions.idempotencyKey = newIdempotencyKey()
+\t}}
+}}
+
+// setIdempotencyKey sets the call's key, if it has one, on req.
+func (c *{self.class_name}Client) setIdempotencyKey(req *http.Request, options *requestOptions) {{
+\tif options.idempotencyKey == "" {{
+\t\treturn
+\t}}
+\theader := c.idempotencyHeader
+\tif header == "" {{
+\t\theader = "Idempotency-Key"
+\t}}
+\treq.Header.Set(header, options.idempotencyKey)
+}}
+
+// newIdempotencyKey returns a random (version 4) UUID.
+func newIdempotencyKey() string {{
+\tb := make([]byte, 16)
+\trand.Read(b)
+\tb[6] = b[6]&0x0f | 0x40
+\tb[8] = b[8]&0x3f | 0x80
+\treturn fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}}
+"""
+    
+    AUTH_KEY_HEADERS = ('x-api-key', 'api-key', 'apikey', 'x-auth-token', 'x-access-token')
+    AUTH_KEY_PARAMS = ('api_key', 'apikey', 'api-key', 'key', 'access_token', 'token', 'auth')
+    AUTH_COOKIE_PATTERN = r'sess|sid|auth|token|jwt'
+    
+    def _captured_auth(self) -> Tuple[str, str]:
+        """How the captured requests carried credentials, as a mode (bearer,
+        basic, header, query, or cookie) and the header, query parameter, or
+        cookie name it uses; the most common mode wins, bearer if none."""
+        import re
+        from collections import Counter
+        from urllib.parse import urlparse, parse_qsl
+        seen = Counter()
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                request = example.get('request', {})
+                for name, value in (request.get('headers') or {}).items():
+                    lowered, value = name.lower(), str(value)
+                    scheme = value.split(' ', 1)[0].lower()
+                    if lowered == 'authorization' and scheme in ('bearer', 'basic'):
+                        seen[(scheme, 'Authorization')] += 1
+                    elif lowered in self.AUTH_KEY_HEADERS:
+                        seen[('header', '-'.join(word.capitalize() for word in name.split('-')))] += 1
+                    elif lowered == 'cookie':
+                        cookies = [part.strip().split('=', 1)[0] for part in value.split(';') if '=' in part]
+                        cookie = next((c for c in cookies if re.search(self.AUTH_COOKIE_PATTERN, c.lower())), '')
+                        if cookie:
+                            seen[('cookie', cookie)] += 1
+                for param, _ in parse_qsl(urlparse(request.get('url') or '').query, keep_blank_values=True):
+                    if param.lower() in self.AUTH_KEY_PARAMS:
+                        seen[('query', param)] += 1
+        if not seen:
+            return 'bearer', 'Authorization'
+        order = ('bearer', 'header', 'query', 'basic', 'cookie')
+        return max(seen, key=lambda auth: (seen[auth], -order.index(auth[0])))
+    
+    def _generate_go_auth(self, package_name: str, auth: Tuple[str, str]) -> str:
+        mode, name = auth
+        sends, set_key = {
+            'bearer': ('as a bearer token', 'c.SetAuthToken(key)'),
+            'basic': ('as the user name of basic auth, with an empty password', 'c.SetHeader("Authorization", basicAuth(key, ""))'),
+            'header': (f'in the {name} header', f'c.setHeaderCredential("{name}", key)'),
+            'query': (f'as the {name} query parameter', f'c.setQueryCredential("{name}", key)'),
+            'cookie': (f'as the {name} cookie', f'c.setCookie("{name}", key)'),
+        }[mode]
+        import textwrap
+        set_api_key_doc = textwrap.fill(
+            f'SetAPIKey sets the API key, sent the way the captured requests sent theirs: {sends}. '
+            'It is safe to call while other goroutines make calls.', width=77, initial_indent='// ', subsequent_indent='// ')
+        return f"""package {package_name}
+
+import (
+\t"encoding/base64"
+\t"net/http"
+\t"strings"
+\t"sync"
+)
+
+{set_api_key_doc}
+func (c *{self.class_name}Client) SetAPIKey(key string) {{
+\t{set_key}
+}}
+
+// WithAPIKeyHeader sends key in header, e.g. X-Api-Key.
+func WithAPIKeyHeader(header, key string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.setHeaderCredential(header, key)
+\t}}
+}}
+
+// WithAPIKeyQuery sends key as the query parameter param, e.g. api_key, of
+// every request. Debug output and logs redact it.
+func WithAPIKeyQuery(param, key string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.setQueryCredential(param, key)
+\t}}
+}}
+
+// WithBasicAuth authorizes with HTTP basic auth.
+func WithBasicAuth(username, password string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.SetHeader("Authorization", basicAuth(username, password))
+\t}}
+}}
+
+// WithSessionCookie sends the cookie name=value with every request, for
+// APIs authenticated by a browser session. Cookies from several calls are
+// combined.
+func WithSessionCookie(name, value string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.setCookie(name, value)
+\t}}
+}}
+
+func basicAuth(username, password string) string {{
+\treturn "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}}
+
+// setCookie adds name=value to the Cookie header, replacing any earlier
+// value of name.
+func (c *{self.class_name}Client) setCookie(name, value string) {{
+\tc.headerMu.Lock()
+\tdefer c.headerMu.Unlock()
+\tcookies := []string{{name + "=" + value}}
+\tfor _, cookie := range strings.Split(c.Headers["Cookie"], ";") {{
+\t\tcookie = strings.TrimSpace(cookie)
+\t\tif cookie != "" && !strings.HasPrefix(cookie, name+"=") {{
+\t\t\tcookies = append(cookies, cookie)
+\t\t}}
+\t}}
+\tc.Headers["Cookie"] = strings.Join(cookies, "; ")
+}}
+
+// credentialHeaders holds the canonical names of the headers clients send
+// API keys in, so that they are redacted like the usual credential headers.
+var credentialHeaders sync.Map
+
+// setHeaderCredential sends key in header.
+func (c *{self.class_name}Client) setHeaderCredential(header, key string) {{
+\tcredentialHeaders.Store(http.CanonicalHeaderKey(header), true)
+\tc.SetHeader(header, key)
+}}
+
+// credentialParams holds the names of the query parameters clients send
+// credentials in, lowercased, so that they are redacted like the usual ones.
+var credentialParams sync.Map
+
+// setQueryCredential sends key as the query parameter param. The map is
+// replaced rather than changed, so derived clients can share it.
+func (c *{self.class_name}Client) setQueryCredential(param, key string) {{
+\tc.headerMu.Lock()
+\tdefer c.headerMu.Unlock()
+\tcredentials := map[string]string{{param: key}}
+\tfor name, value := range c.queryCredentials {{
+\t\tif name != param {{
+\t\t\tcredentials[name] = value
+\t\t}}
+\t}}
+\tc.queryCredentials = credentials
+\tcredentialParams.Store(strings.ToLower(param), true)
+}}
+
+// applyQueryCredentials adds the client's query credentials to req.
+func (c *{self.class_name}Client) applyQueryCredentials(req *http.Request) {{
+\tc.headerMu.RLock()
+\tcredentials := c.queryCredentials
+\tc.headerMu.RUnlock()
+\tif len(credentials) == 0 {{
+\t\treturn
+\t}}
+\tquery := req.URL.Query()
+\tfor param, key := range credentials {{
+\t\tquery.Set(param, key)
+\t}}
+\treq.URL.RawQuery = query.Encode()
+}}
+"""
+    
+    def _generate_go_signer(self, package_name: str, signing: Dict[str, str]) -> str:
+        return f"""package {package_name}
+
+import (
+\t"crypto/hmac"
+\t"crypto/sha256"
+\t"encoding/base64"
+\t"encoding/hex"
+\t"errors"
+\t"fmt"
+\t"hash"
+\t"net/http"
+\t"net/url"
+\t"sort"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+// Request signing conventions seen in the captured traffic: the headers
+// carrying signatures and their timestamps, and how each was written. They
+// default to X-Signature and X-Timestamp, hex, and Unix seconds.
+const (
+\trequestSignatureHeader   = "{signing['header']}"
+\trequestTimestampHeader   = "{signing['timestamp_header']}"
+\trequestSignatureEncoding = "{signing['encoding']}"
+\trequestTimestampFormat   = "{signing['timestamp_format']}"
+)
+
+// The AWS region and service of the Signature Version 4 requests in the
+// captured traffic; empty when there were none.
+const (
+\tcapturedSigV4Region  = "{signing['sigv4_region']}"
+\tcapturedSigV4Service = "{signing['sigv4_service']}"
+)
+
+// Signer signs each request just before it is sent, after its headers and
+// credentials are set, and again for every retry. body is the encoded
+// request body, nil when there is none or it is a streamed upload.
+type Signer interface {{
+\tSign(req *http.Request, body []byte) error
+}}
+
+// SignerFunc adapts a function to Signer.
+type SignerFunc func(req *http.Request, body []byte) error
+
+// Sign implements Signer.
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {{
+\treturn f(req, body)
+}}
+
+// WithSigner signs every request with s, for APIs that authenticate
+// requests by signature instead of, or as well as, a token. Middleware
+// runs after signing, so it must not change what the signature covers.
+func WithSigner(s Signer) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.signer = s
+\t}}
+}}
+
+// sign signs req with the client's signer, if any.
+func (c *{self.class_name}Client) sign(req *http.Request, body []byte) error {{
+\tif c.signer == nil {{
+\t\treturn nil
+\t}}
+\tif err := c.signer.Sign(req, body); err != nil {{
+\t\treturn fmt.Errorf("signing request: %w", err)
+\t}}
+\treturn nil
+}}
+
+// HMACSigner signs requests the way most private APIs do: an HMAC of the
+// timestamp, method, path and query, and body, sent in Header alongside the
+// timestamp in TimestampHeader.
+type HMACSigner struct {{
+\tSecret []byte
+\t// Hash defaults to SHA-256.
+\tHash func() hash.Hash
+\t// Header and TimestampHeader default to the headers seen in the capture.
+\tHeader          string
+\tTimestampHeader string
+\t// DigestHeader, if set, receives the hex SHA-256 of the body.
+\tDigestHeader string
+\t// Encoding is "hex" or "base64"; TimestampFormat is "unix", "unixmilli",
+\t// or "rfc3339". Both default to what the capture used.
+\tEncoding        string
+\tTimestampFormat string
+\t// Payload builds the signed bytes, replacing the default
+\t// timestamp + method + path and query + body.
+\tPayload func(timestamp string, req *http.Request, body []byte) []byte
+\t// Now defaults to time.Now.
+\tNow func() time.Time
+}}
+
+// Sign implements Signer.
+func (s HMACSigner) Sign(req *http.Request, body []byte) error {{
+\tif len(s.Secret) == 0 {{
+\t\treturn errors.New("HMACSigner: no secret")
+\t}}
+\tnow := time.Now()
+\tif s.Now != nil {{
+\t\tnow = s.Now()
+\t}}
+\ttimestamp, err := formatSigningTime(now, firstNonEmpty(s.TimestampFormat, requestTimestampFormat))
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tpayload := []byte(timestamp + req.Method + req.URL.RequestURI() + string(body))
+\tif s.Payload != nil {{
+\t\tpayload = s.Payload(timestamp, req, body)
+\t}}
+\tnewHash := s.Hash
+\tif newHash == nil {{
+\t\tnewHash = sha256.New
+\t}}
+\tmac := hmac.New(newHash, s.Secret)
+\tmac.Write(payload)
+\tsignature := hex.EncodeToString(mac.Sum(nil))
+\tswitch encoding := firstNonEmpty(s.Encoding, requestSignatureEncoding); encoding {{
+\tcase "hex":
+\tcase "base64":
+\t\tsignature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+\tdefault:
+\t\treturn fmt.Errorf("HMACSigner: unknown encoding %q", encoding)
+\t}}
+\tif s.DigestHeader != "" {{
+\t\treq.Header.Set(s.DigestHeader, hexSHA256(body))
+\t}}
+\treq.Header.Set(firstNonEmpty(s.TimestampHeader, requestTimestampHeader), timestamp)
+\treq.Header.Set(firstNonEmpty(s.Header, requestSignatureHeader), signature)
+\treturn nil
+}}
+
+func formatSigningTime(t time.Time, format string) (string, error) {{
+\tswitch format {{
+\tcase "unix":
+\t\treturn strconv.FormatInt(t.Unix(), 10), nil
+\tcase "unixmilli":
+\t\treturn strconv.FormatInt(t.UnixMilli(), 10), nil
+\tcase "rfc3339":
+\t\treturn t.UTC().Format(time.RFC3339), nil
+\t}}
+\treturn "", fmt.Errorf("HMACSigner: unknown timestamp format %q", format)
+}}
+
+// SigV4Signer signs requests with AWS Signature Version 4, as used by AWS
+// services and API Gateway endpoints behind IAM authorization. Its
+// Authorization header replaces any the client set.
+type SigV4Signer struct {{
+\tAccessKeyID     string
+\tSecretAccessKey string
+\tSessionToken    string // for temporary credentials
+\t// Region and Service default to those seen in the capture.
+\tRegion  string
+\tService string
+\t// Now defaults to time.Now.
+\tNow func() time.Time
+}}
+
+// Sign implements Signer.
+func (s SigV4Signer) Sign(req *http.Request, body []byte) error {{
+\tregion := firstNonEmpty(s.Region, capturedSigV4Region)
+\tservice := firstNonEmpty(s.Service, capturedSigV4Service)
+\tif s.AccessKeyID == "" || s.SecretAccessKey == "" || region == "" || service == "" {{
+\t\treturn errors.New("SigV4Signer: access key, secret key, region, and service are required")
+\t}}
+\tnow := time.Now()
+\tif s.Now != nil {{
+\t\tnow = s.Now()
+\t}}
+\tamzDate := now.UTC().Format("20060102T150405Z")
+\tdate := amzDate[:8]
+
+\t// A streamed body cannot be hashed in advance; S3 accepts it unsigned.
+\tpayloadHash := hexSHA256(body)
+\tif body == nil && req.Body != nil && req.Body != http.NoBody {{
+\t\tpayloadHash = "UNSIGNED-PAYLOAD"
+\t}}
+\treq.Header.Set("X-Amz-Date", amzDate)
+\tif service == "s3" || payloadHash == "UNSIGNED-PAYLOAD" {{
+\t\treq.Header.Set("X-Amz-Content-Sha256", payloadHash)
+\t}}
+\tif s.SessionToken != "" {{
+\t\treq.Header.Set("X-Amz-Security-Token", s.SessionToken)
+\t}}
+
+\thost := req.Host
+\tif host == "" {{
+\t\thost = req.URL.Host
+\t}}
+\theaders := map[string]string{{"host": host}}
+\tfor name, values := range req.Header {{
+\t\tlower := strings.ToLower(name)
+\t\tif lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {{
+\t\t\ttrimmed := make([]string, len(values))
+\t\t\tfor i, value := range values {{
+\t\t\t\ttrimmed[i] = strings.Join(strings.Fields(value), " ")
+\t\t\t}}
+\t\t\theaders[lower] = strings.Join(trimmed, ",")
+\t\t}}
+\t}}
+\tnames := make([]string, 0, len(headers))
+\tfor name := range headers {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\tvar canonicalHeaders strings.Builder
+\tfor _, name := range names {{
+\t\tcanonicalHeaders.WriteString(name + ":" + headers[name] + "\\n")
+\t}}
+\tsignedHeaders := strings.Join(names, ";")
+
+\tpath := req.URL.EscapedPath()
+\tif path == "" {{
+\t\tpath = "/"
+\t}}
+\tcanonicalRequest := strings.Join([]string{{
+\t\treq.Method, path, canonicalQuery(req.URL.Query()), canonicalHeaders.String(), signedHeaders, payloadHash,
+\t}}, "\\n")
+\tscope := date + "/" + region + "/" + service + "/aws4_request"
+\tstringToSign := "AWS4-HMAC-SHA256\\n" + amzDate + "\\n" + scope + "\\n" + hexSHA256([]byte(canonicalRequest))
+
+\tkey := []byte("AWS4" + s.SecretAccessKey)
+\tfor _, part := range []string{{date, region, service, "aws4_request"}} {{
+\t\tkey = hmacSHA256(key, part)
+\t}}
+\treq.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+\t\ts.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+\treturn nil
+}}
+
+// canonicalQuery escapes query as SigV4 requires, spaces as %20 rather
+// than +, and sorts it by name, then value.
+func canonicalQuery(query url.Values) string {{
+\tescape := func(s string) string {{
+\t\treturn strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+\t}}
+\tescaped := make(map[string][]string, len(query))
+\tnames := make([]string, 0, len(query))
+\tfor name, values := range query {{
+\t\tname = escape(name)
+\t\tnames = append(names, name)
+\t\tfor _, value := range values {{
+\t\t\tescaped[name] = append(escaped[name], escape(value))
+\t\t}}
+\t}}
+\tsort.Strings(names)
+\tpairs := make([]string, 0, len(names))
+\tfor _, name := range names {{
+\t\tsort.Strings(escaped[name])
+\t\tfor _, value := range escaped[name] {{
+\t\t\tpairs = append(pairs, name+"="+value)
+\t\t}}
+\t}}
+\treturn strings.Join(pairs, "&")
+}}
+
+func hmacSHA256(key []byte, data string) []byte {{
+\tmac := hmac.New(sha256.New, key)
+\tmac.Write([]byte(data))
+\treturn mac.Sum(nil)
+}}
+
+func hexSHA256(data []byte) string {{
+\tsum := sha256.Sum256(data)
+\treturn hex.EncodeToString(sum[:])
+}}
+
+func firstNonEmpty(values ...string) string {{
+\tfor _, value := range values {{
+\t\tif value != "" {{
+\t\t\treturn value
+\t\t}}
+\t}}
+\treturn ""
+}}
+"""
+    
+    def _go_validated_structs(self) -> Dict[str, Dict[str, Any]]:
+        """The request structs and the named structs nested in them, the types
+        given a Validate method, by name with their schemas."""
+        structs = {}
+        
+        def visit(schema):
+            for prop in schema.get('properties', {}).values():
+                for child in (prop, prop.get('items') or {}):
+                    name = self.struct_types.get(id(child))
+                    if name and name not in structs:
+                        structs[name] = self.nested_structs[name]
+                        visit(structs[name])
+        
+        for endpoint in self.endpoints.values():
+            schema = endpoint.request_body_schema
+            if schema and schema.get('type') == 'object' and schema.get('properties'):
+                structs.setdefault(self._go_struct_name(endpoint, "Request"), schema)
+                visit(schema)
+        return structs
+    
+    def _go_check_statements(self, value: str, deref: str, go_type: str, schema: Dict[str, Any],
+                             field: str, validated: Dict[str, Dict[str, Any]]) -> List[str]:
+        """Statements checking value, of go_type, against the constraints of
+        its schema. deref is value without any pointer, and field the Go
+        expression naming it in problems."""
+        if go_type.startswith('*'):
+            inner = self._go_check_statements(value, f"*{value}", go_type[1:], schema, field, validated)
+            return [f"if {value} != nil {{"] + ['\t' + line for line in inner] + ["}"] if inner else []
+        if go_type.startswith('[]'):
+            if not field.startswith('"'):
+                return []
+            inner = self._go_check_statements('item', 'item', go_type[2:], schema.get('items') or {},
+                                               f'fmt.Sprintf("{field[1:-1]}[%d]", i)', validated)
+            return [f"for i, item := range {value} {{"] + ['\t' + line for line in inner] + ["}"] if inner else []
+        if go_type in validated:
+            return [f"v.nested({field}, {value}.Validate())"]
+        if self.enum_fields.get(id(schema)) == go_type:
+            return [f"v.enum({field}, string({deref}), {value}.Valid())"]
+        checks = []
+        if go_type == 'string':
+            if schema.get('format') == 'email':
+                checks.append(f"v.email({field}, {deref})")
+            elif schema.get('format') == 'uri':
+                checks.append(f"v.url({field}, {deref})")
+            if 'minLength' in schema or 'maxLength' in schema:
+                checks.append(f"v.length({field}, {deref}, {schema.get('minLength', 0)}, {schema.get('maxLength', -1)})")
+        return checks
+    
+    # Bodies a field must have been present in before it is required.
+    REQUIRED_MIN_SAMPLES = 3
+    
+    def _go_validate_method(self, name: str, schema: Dict[str, Any], validated: Dict[str, Dict[str, Any]]) -> str:
+        required = set()
+        if schema.get('samples', 1) >= self.REQUIRED_MIN_SAMPLES:
+            required = set(schema.get('required') or [])
+        checks = []
+        for prop_name, prop in schema['properties'].items():
+            go_name, go_type, _ = self._go_field(prop_name, prop)
+            value, field = f"r.{go_name}", f'"{prop_name}"'
+            # A required string is one no capture left out or empty.
+            if (prop_name in required and prop.get('min_length', 1) > 0
+                    and (go_type == 'string' or self.enum_fields.get(id(prop)) == go_type)):
+                checks.append(f'v.require({field}, {value} != "")')
+            checks.extend(self._go_check_statements(value, value, go_type, prop, field, validated))
+        lines = [
+            f"// Validate checks r against the constraints inferred from the captured",
+            f"// traffic, returning a *ValidationError listing the fields breaking them.",
+            f"func (r *{name}) Validate() error {{",
+        ]
+        if not checks:
+            return '\n'.join(lines + ["\treturn nil", "}"])
+        lines += ["\tif r == nil {", "\t\treturn nil", "\t}", "\tvar v validator"]
+        lines += ['\t' + line for line in checks]
+        lines += [f'\treturn v.result("{name}")', "}"]
+        return '\n'.join(lines)
+    
+    def _generate_go_validate(self, package_name: str) -> str:
+        validated = self._go_validated_structs()
+        methods = [self._go_validate_method(name, schema, validated) for name, schema in validated.items()]
+        return f"""package {package_name}
+
+import (
+\t"fmt"
+\t"net/mail"
+\t"net/url"
+\t"strings"
+\t"unicode/utf8"
+)
+
+// ValidationError reports the fields of a request that break the
+// constraints inferred from the captured traffic: required fields, email
+// and URL formats, lengths, and enum values. Calls return it without
+// sending the request.
+type ValidationError struct {{
+\tType   string // "CreateUserRequest"
+\tFields []FieldError
+}}
+
+// FieldError is a field breaking a constraint.
+type FieldError struct {{
+\tField   string // JSON path, such as "items[0].email"
+\tProblem string
+}}
+
+func (e *ValidationError) Error() string {{
+\tproblems := make([]string, len(e.Fields))
+\tfor i, field := range e.Fields {{
+\t\tproblems[i] = field.Field + " " + field.Problem
+\t}}
+\treturn fmt.Sprintf("invalid %s: %s", e.Type, strings.Join(problems, "; "))
+}}
+
+// WithoutRequestValidation sends requests without validating them first,
+// for when the inferred constraints are stricter than the API.
+func WithoutRequestValidation() ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.skipValidation = true
+\t}}
+}}
+
+// validate validates a request body unless the client skips validation.
+func (c *{self.class_name}Client) validate(request interface{{ Validate() error }}) error {{
+\tif c.skipValidation {{
+\t\treturn nil
+\t}}
+\treturn request.Validate()
+}}
+
+// validator collects the problems found by a Validate method.
+type validator struct {{
+\tfields []FieldError
+}}
+
+func (v *validator) add(field, problem string) {{
+\tv.fields = append(v.fields, FieldError{{Field: field, Problem: problem}})
+}}
+
+func (v *validator) require(field string, present bool) {{
+\tif !present {{
+\t\tv.add(field, "is required")
+\t}}
+}}
+
+func (v *validator) email(field, value string) {{
+\tif address, err := mail.ParseAddress(value); value != "" && (err != nil || address.Address != value) {{
+\t\tv.add(field, "is not an email address")
+\t}}
+}}
+
+func (v *validator) url(field, value string) {{
+\tif u, err := url.Parse(value); value != "" && (err != nil || u.Scheme == "" || u.Host == "") {{
+\t\tv.add(field, "is not an absolute URL")
+\t}}
+}}
+
+// length checks the length of a non-empty value in characters; max is -1
+// for no maximum.
+func (v *validator) length(field, value string, min, max int) {{
+\tn := utf8.RuneCountInString(value)
+\tswitch {{
+\tcase value == "":
+\tcase min == max && n != min:
+\t\tv.add(field, fmt.Sprintf("must be %d characters", min))
+\tcase n < min:
+\t\tv.add(field, fmt.Sprintf("must be at least %d characters", min))
+\tcase max >= 0 && n > max:
+\t\tv.add(field, fmt.Sprintf("must be at most %d characters", max))
+\t}}
+}}
+
+func (v *validator) enum(field, value string, valid bool) {{
+\tif value != "" && !valid {{
+\t\tv.add(field, fmt.Sprintf("has unknown value %q", value))
+\t}}
+}}
+
+// nested adds the problems of a nested object, under field.
+func (v *validator) nested(field string, err error) {{
+\tif err == nil {{
+\t\treturn
+\t}}
+\tif invalid, ok := err.(*ValidationError); ok {{
+\t\tfor _, problem := range invalid.Fields {{
+\t\t\tv.add(field+"."+problem.Field, problem.Problem)
+\t\t}}
+\t\treturn
+\t}}
+\tv.add(field, err.Error())
+}}
+
+func (v *validator) result(typ string) error {{
+\tif len(v.fields) == 0 {{
+\t\treturn nil
+\t}}
+\treturn &ValidationError{{Type: typ, Fields: v.fields}}
+}}
+""" + ''.join('\n' + method + '\n' for method in methods)
+    
+    def _generate_go_breaker(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"errors"
+\t"fmt"
+\t"net/http"
+\t"sync"
+\t"time"
+)
+
+// ErrCircuitOpen is returned (wrapped), without a request being sent, by
+// calls whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of one circuit of a breaker.
+type CircuitState int
+
+const (
+\t// CircuitClosed lets every request through.
+\tCircuitClosed CircuitState = iota
+\t// CircuitOpen fails requests fast until its cooldown has passed.
+\tCircuitOpen
+\t// CircuitHalfOpen lets probe requests through; the first to succeed
+\t// closes the circuit and the first to fail opens it again.
+\tCircuitHalfOpen
+)
+
+func (s CircuitState) String() string {{
+\tswitch s {{
+\tcase CircuitClosed:
+\t\treturn "closed"
+\tcase CircuitOpen:
+\t\treturn "open"
+\tcase CircuitHalfOpen:
+\t\treturn "half-open"
+\t}}
+\treturn fmt.Sprintf("CircuitState(%d)", int(s))
+}}
+
+// BreakerPolicy configures a circuit breaker. A request fails when no
+// response arrives, other than because its caller's context ended, or the
+// response is a 5xx other than 501; any other response succeeds.
+type BreakerPolicy struct {{
+\t// FailureThreshold is the consecutive failures that open a circuit;
+\t// zero means 5.
+\tFailureThreshold int
+\t// Cooldown is how long an open circuit fails requests before letting a
+\t// probe through; zero means 30 seconds.
+\tCooldown time.Duration
+\t// HalfOpenProbes is how many probes may be in flight at once; zero
+\t// means 1.
+\tHalfOpenProbes int
+\t// PerOperation keeps a circuit per operation ("GET /v1/users/{{id}}")
+\t// rather than per host, for APIs whose endpoints fail independently.
+\tPerOperation bool
+\t// OnStateChange, if set, is called when a circuit changes state.
+\tOnStateChange func(circuit string, from, to CircuitState)
+}}
+
+// WithCircuitBreaker stops sending requests to a host, or an operation,
+// after policy.FailureThreshold consecutive failures, failing calls with
+// ErrCircuitOpen instead, so batch jobs fail fast rather than hammering an
+// API that is down. After the cooldown, probe requests test whether it has
+// recovered. Every attempt of a retried call counts, and an open circuit
+// ends the retries.
+func WithCircuitBreaker(policy BreakerPolicy) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.breaker = &circuitBreaker{{policy: policy, circuits: make(map[string]*breakerCircuit)}}
+\t}}
+}}
+
+// CircuitStates returns the state of each circuit of the client's breaker
+// that has seen a request, keyed by host or operation.
+func (c *{self.class_name}Client) CircuitStates() map[string]CircuitState {{
+\tstates := make(map[string]CircuitState)
+\tif c.breaker == nil {{
+\t\treturn states
+\t}}
+\tc.breaker.mu.Lock()
+\tdefer c.breaker.mu.Unlock()
+\tfor key, circuit := range c.breaker.circuits {{
+\t\tstates[key] = circuit.current(c.breaker.cooldown(), time.Now())
+\t}}
+\treturn states
+}}
+
+type circuitBreaker struct {{
+\tpolicy BreakerPolicy
+
+\tmu       sync.Mutex
+\tcircuits map[string]*breakerCircuit
+}}
+
+type breakerCircuit struct {{
+\tstate    CircuitState
+\tfailures int
+\topened   time.Time
+\tprobes   int
+}}
+
+// current is the circuit's state, an open circuit becoming half-open once
+// the cooldown has passed.
+func (c *breakerCircuit) current(cooldown time.Duration, now time.Time) CircuitState {{
+\tif c.state == CircuitOpen && now.Sub(c.opened) >= cooldown {{
+\t\treturn CircuitHalfOpen
+\t}}
+\treturn c.state
+}}
+
+func (b *circuitBreaker) cooldown() time.Duration {{
+\tif b.policy.Cooldown <= 0 {{
+\t\treturn 30 * time.Second
+\t}}
+\treturn b.policy.Cooldown
+}}
+
+// allow admits a request on the circuit key, returning the function that
+// reports its outcome, or an error wrapping ErrCircuitOpen. It admits
+// every request on a nil breaker.
+func (b *circuitBreaker) allow(key string) (func(ctx context.Context, resp *http.Response, err error), error) {{
+\tif b == nil {{
+\t\treturn func(context.Context, *http.Response, error) {{}}, nil
+\t}}
+\tb.mu.Lock()
+\tcircuit := b.circuits[key]
+\tif circuit == nil {{
+\t\tcircuit = &breakerCircuit{{}}
+\t\tb.circuits[key] = circuit
+\t}}
+\tnow := time.Now()
+\tfrom := circuit.state
+\tprobe := false
+\tswitch circuit.current(b.cooldown(), now) {{
+\tcase CircuitOpen:
+\t\twait := b.cooldown() - now.Sub(circuit.opened)
+\t\tb.mu.Unlock()
+\t\treturn nil, fmt.Errorf("%w: %s (next probe in %s)", ErrCircuitOpen, key, wait.Round(time.Millisecond))
+\tcase CircuitHalfOpen:
+\t\tprobes := b.policy.HalfOpenProbes
+\t\tif probes <= 0 {{
+\t\t\tprobes = 1
+\t\t}}
+\t\tif circuit.probes >= probes {{
+\t\t\tb.mu.Unlock()
+\t\t\treturn nil, fmt.Errorf("%w: %s (probe in flight)", ErrCircuitOpen, key)
+\t\t}}
+\t\tcircuit.state = CircuitHalfOpen
+\t\tcircuit.probes++
+\t\tprobe = true
+\t}}
+\tb.mu.Unlock()
+\tif probe {{
+\t\tb.changed(key, from, CircuitHalfOpen)
+\t}}
+
+\treturn func(ctx context.Context, resp *http.Response, err error) {{
+\t\tb.mu.Lock()
+\t\tfrom := circuit.state
+\t\tif probe {{
+\t\t\tcircuit.probes--
+\t\t}}
+\t\tswitch {{
+\t\tcase err != nil && ctx.Err() != nil:
+\t\t\t// The caller gave up; that says nothing about the server.
+\t\tcase err != nil || resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+\t\t\tcircuit.failures++
+\t\t\tif circuit.state == CircuitHalfOpen || circuit.failures >= b.threshold() {{
+\t\t\t\tcircuit.state, circuit.opened = CircuitOpen, time.Now()
+\t\t\t}}
+\t\tdefault:
+\t\t\tcircuit.state, circuit.failures = CircuitClosed, 0
+\t\t}}
+\t\tto := circuit.state
+\t\tb.mu.Unlock()
+\t\tb.changed(key, from, to)
+\t}}, nil
+}}
+
+func (b *circuitBreaker) threshold() int {{
+\tif b.policy.FailureThreshold <= 0 {{
+\t\treturn 5
+\t}}
+\treturn b.policy.FailureThreshold
+}}
+
+// changed calls the policy's OnStateChange if the state changed.
+func (b *circuitBreaker) changed(key string, from, to CircuitState) {{
+\tif from != to && b.policy.OnStateChange != nil {{
+\t\tb.policy.OnStateChange(key, from, to)
+\t}}
+}}
+
+// circuitKey is the breaker circuit a request to operation belongs to.
+func (c *{self.class_name}Client) circuitKey(operation string, req *http.Request) string {{
+\tif c.breaker != nil && c.breaker.policy.PerOperation {{
+\t\treturn operation
+\t}}
+\treturn req.URL.Host
+}}
+"""
+    
+    def _generate_go_cache(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"container/list"
+\t"crypto/sha256"
+\t"encoding/hex"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"sort"
+\t"strings"
+\t"sync"
+)
+
+// ResponseCache stores the responses to GET calls that carried an ETag or
+// Last-Modified header. Implementations must be safe for concurrent use.
+type ResponseCache interface {{
+\tGet(key string) (*CachedResponse, bool)
+\tPut(key string, response *CachedResponse)
+}}
+
+// CachedResponse is a stored 200 response. Body is the body as received,
+// before any Content-Encoding is removed.
+type CachedResponse struct {{
+\tHeader http.Header
+\tBody   []byte
+}}
+
+// WithResponseCache makes GET calls conditional: a stored response's ETag
+// is sent as If-None-Match and its Last-Modified as If-Modified-Since, and a
+// 304 Not Modified is answered from cache. Every call still reaches the
+// server, so cached data is never stale, but unchanged resources cost no
+// body, and APIs that do not count 304s against rate limits cost no quota.
+//
+// Entries are keyed by URL and by the credentials sent, so clients with
+// different tokens can share a cache. Streams and downloads are not cached.
+func WithResponseCache(cache ResponseCache) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.cache = cache
+\t}}
+}}
+
+// MemoryCache is a ResponseCache holding the most recently used responses
+// in memory.
+type MemoryCache struct {{
+\tmu         sync.Mutex
+\tmaxEntries int
+\torder      *list.List
+\tentries    map[string]*list.Element
+}}
+
+type memoryEntry struct {{
+\tkey      string
+\tresponse *CachedResponse
+}}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries responses;
+// zero or less means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {{
+\treturn &MemoryCache{{maxEntries: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}}
+}}
+
+// Get returns the response stored under key.
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\telement, ok := m.entries[key]
+\tif !ok {{
+\t\treturn nil, false
+\t}}
+\tm.order.MoveToFront(element)
+\treturn element.Value.(*memoryEntry).response, true
+}}
+
+// Put stores response under key, evicting the least recently used response
+// when the cache is full.
+func (m *MemoryCache) Put(key string, response *CachedResponse) {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\tif element, ok := m.entries[key]; ok {{
+\t\telement.Value.(*memoryEntry).response = response
+\t\tm.order.MoveToFront(element)
+\t\treturn
+\t}}
+\tm.entries[key] = m.order.PushFront(&memoryEntry{{key: key, response: response}})
+\tif m.maxEntries > 0 && m.order.Len() > m.maxEntries {{
+\t\toldest := m.order.Back()
+\t\tm.order.Remove(oldest)
+\t\tdelete(m.entries, oldest.Value.(*memoryEntry).key)
+\t}}
+}}
+
+// Len returns the number of stored responses.
+func (m *MemoryCache) Len() int {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\treturn m.order.Len()
+}}
+
+// cacheKey identifies req's response: its URL and a digest of its secret
+// headers, so that one client's responses are never served to another.
+func (c *{self.class_name}Client) cacheKey(req *http.Request) string {{
+\tnames := make([]string, 0, len(req.Header))
+\tfor name := range req.Header {{
+\t\tif c.isSecretHeader(name) {{
+\t\t\tnames = append(names, name)
+\t\t}}
+\t}}
+\tsort.Strings(names)
+\tdigest := sha256.New()
+\tfor _, name := range names {{
+\t\tfmt.Fprintf(digest, "%s: %q\\n", name, req.Header[name])
+\t}}
+\treturn req.URL.String() + " " + hex.EncodeToString(digest.Sum(nil))
+}}
+
+// revalidate makes req conditional on the stored response to it, which it
+// returns; it returns nil when req is not cacheable or nothing is stored.
+// Validators the caller set are left alone.
+func (c *{self.class_name}Client) revalidate(req *http.Request) *CachedResponse {{
+\tif c.cache == nil || req.Method != http.MethodGet {{
+\t\treturn nil
+\t}}
+\tcached, ok := c.cache.Get(c.cacheKey(req))
+\tif !ok {{
+\t\treturn nil
+\t}}
+\tif etag := cached.Header.Get("ETag"); etag != "" && req.Header.Get("If-None-Match") == "" {{
+\t\treq.Header.Set("If-None-Match", etag)
+\t}}
+\tif modified := cached.Header.Get("Last-Modified"); modified != "" && req.Header.Get("If-Modified-Since") == "" {{
+\t\treq.Header.Set("If-Modified-Since", modified)
+\t}}
+\treturn cached
+}}
+
+// cachedResponse answers a 304 to req with the stored response, its
+// headers updated from the 304's as HTTP caches do, and stores the update.
+func (c *{self.class_name}Client) cachedResponse(req *http.Request, notModified *http.Response, cached *CachedResponse) *http.Response {{
+\theader := cached.Header.Clone()
+\tfor name, values := range notModified.Header {{
+\t\tswitch name {{
+\t\tcase "Content-Length", "Content-Encoding", "Content-Type", "Transfer-Encoding":
+\t\tdefault:
+\t\t\theader[name] = values
+\t\t}}
+\t}}
+\tc.cache.Put(c.cacheKey(req), &CachedResponse{{Header: header, Body: cached.Body}})
+\treturn &http.Response{{
+\t\tStatus:        "200 OK",
+\t\tStatusCode:    http.StatusOK,
+\t\tProto:         notModified.Proto,
+\t\tProtoMajor:    notModified.ProtoMajor,
+\t\tProtoMinor:    notModified.ProtoMinor,
+\t\tHeader:        header,
+\t\tBody:          io.NopCloser(bytes.NewReader(cached.Body)),
+\t\tContentLength: int64(len(cached.Body)),
+\t\tRequest:       notModified.Request,
+\t}}
+}}
+
+// storeResponse stores a 200 response to a GET request if it carries a
+// validator; wire is its body as received.
+func (c *{self.class_name}Client) storeResponse(req *http.Request, resp *http.Response, wire []byte) {{
+\tif c.cache == nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {{
+\t\treturn
+\t}}
+\tif resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {{
+\t\treturn
+\t}}
+\tif strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {{
+\t\treturn
+\t}}
+\tc.cache.Put(c.cacheKey(req), &CachedResponse{{Header: resp.Header.Clone(), Body: wire}})
+}}
+"""
+    
+    def _generate_go_retry(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"errors"
+\t"io"
+\t"math"
+\t"math/rand"
+\t"net"
+\t"net/http"
+\t"net/url"
+\t"time"
+)
+
+// RetryPolicy controls how a call is retried after a 429, a 5xx other than
+// 501, or a transient network error such as a reset connection or a dial
+// timeout. Waits grow exponentially from InitialBackoff, with jitter, unless
+// the response says how long to wait in Retry-After.
+//
+// Calls with a non-idempotent method (POST, PATCH) are retried only when the
+// request cannot have been processed: after a 429, or when the connection
+// was never made, unless they carry an idempotency key (see
+// WithIdempotencyKeys). Set RetryNonIdempotent to retry them like the others.
+type RetryPolicy struct {{
+\t// MaxAttempts is the most attempts per call, the first included; one or
+\t// less disables retries.
+\tMaxAttempts int
+\t// InitialBackoff is the wait before the second attempt.
+\tInitialBackoff time.Duration
+\t// MaxBackoff caps the wait between attempts; zero means no cap.
+\tMaxBackoff time.Duration
+\t// Multiplier scales the wait after each attempt; zero means 2.
+\tMultiplier float64
+\t// Jitter varies each wait randomly by up to this fraction of it, so
+\t// clients that failed together do not retry together.
+\tJitter float64
+\t// MaxRetryAfter is the longest Retry-After honored; a response asking
+\t// for a longer wait is returned instead. Zero means a minute.
+\tMaxRetryAfter time.Duration
+\t// RetryNonIdempotent retries POST and PATCH calls after errors the
+\t// server may have acted on.
+\tRetryNonIdempotent bool
+}}
+
+// DefaultRetryPolicy makes up to 3 attempts, waiting about 250ms, then
+// 500ms.
+var DefaultRetryPolicy = RetryPolicy{{
+\tMaxAttempts:    3,
+\tInitialBackoff: 250 * time.Millisecond,
+\tMaxBackoff:     10 * time.Second,
+\tJitter:         0.2,
+}}
+
+// WithRetry retries the client's calls with policy. Clients do not retry
+// by default.
+func WithRetry(policy RetryPolicy) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.retry = &policy
+\t}}
+}}
+
+// WithRetryPolicy retries the call with policy instead of the client's;
+// RetryPolicy{{}} disables retries for it.
+func WithRetryPolicy(policy RetryPolicy) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.retry = &policy
+\t}}
+}}
+
+// sendRetrying makes attempts at a call until one succeeds, it fails with
+// an error not worth retrying, or the policy's attempts or the context's
+// deadline run out, returning the last attempt's result. Uploads are only
+// resent when their readers can seek back.
+func (c *{self.class_name}Client) sendRetrying(method, endpoint, baseURL, path string, params url.Values, body interface{{}}, options *requestOptions) ([]byte, error) {{
+\tpolicy := c.retry
+\tif options.retry != nil {{
+\t\tpolicy = options.retry
+\t}}
+\tfor attempt := 1; ; attempt++ {{
+\t\tresponseBody, err := c.sendAttempt(attempt, method, endpoint, baseURL, path, params, body, options)
+\t\tif err == nil || policy == nil || attempt >= policy.MaxAttempts {{
+\t\t\treturn responseBody, err
+\t\t}}
+\t\tif upload, ok := body.(*uploadBody); ok && !upload.replayable() {{
+\t\t\treturn responseBody, err
+\t\t}}
+\t\twait, ok := policy.delay(policy.replayable(method) || options.idempotencyKey != "", attempt, err, time.Now())
+\t\tif !ok {{
+\t\t\treturn responseBody, err
+\t\t}}
+\t\tif deadline, ok := options.ctx.Deadline(); ok && time.Until(deadline) < wait {{
+\t\t\treturn responseBody, err
+\t\t}}
+\t\tc.stats.retry(method + " " + endpoint)
+\t\tif c.logger != nil {{
+\t\t\tc.logger.logRetry(options.ctx, method+" "+endpoint, attempt, wait, err)
+\t\t}}
+\t\ttimer := time.NewTimer(wait)
+\t\tselect {{
+\t\tcase <-options.ctx.Done():
+\t\t\ttimer.Stop()
+\t\t\treturn responseBody, err
+\t\tcase <-timer.C:
+\t\t}}
+\t}}
+}}
+
+func (c *{self.class_name}Client) sendAttempt(attempt int, method, endpoint, baseURL, path string, params url.Values, body interface{{}}, options *requestOptions) (responseBody []byte, err error) {{
+\tdefer recoverPanic(method+" "+endpoint, attempt, &err)
+\toptions.attempt = attempt
+\treturn c.send(method, endpoint, baseURL, path, params, body, options)
+}}
+
+// delay returns how long to wait before retrying a call that failed with
+// err on attempt, or false if it should not be retried. replayable tells
+// whether the call may be sent again after the server may have acted on it.
+func (p *RetryPolicy) delay(replayable bool, attempt int, err error, now time.Time) (time.Duration, bool) {{
+\tvar apiErr *APIError
+\tswitch {{
+\tcase errors.As(err, &apiErr):
+\t\tif !apiErr.Temporary() || apiErr.StatusCode == http.StatusNotImplemented {{
+\t\t\treturn 0, false
+\t\t}}
+\t\tif apiErr.StatusCode != http.StatusTooManyRequests && !replayable {{
+\t\t\treturn 0, false
+\t\t}}
+\t\tif at := parseRetryAfter(apiErr.Header.Get("Retry-After"), now); !at.IsZero() {{
+\t\t\tlimit := p.MaxRetryAfter
+\t\t\tif limit <= 0 {{
+\t\t\t\tlimit = time.Minute
+\t\t\t}}
+\t\t\twait := at.Sub(now)
+\t\t\tif wait > limit {{
+\t\t\t\treturn 0, false
+\t\t\t}}
+\t\t\tif wait < 0 {{
+\t\t\t\twait = 0
+\t\t\t}}
+\t\t\treturn wait, true
+\t\t}}
+\tcase transientNetworkError(err):
+\t\tvar opErr *net.OpError
+\t\trefused := errors.As(err, &opErr) && opErr.Op == "dial"
+\t\tif !refused && !replayable {{
+\t\t\treturn 0, false
+\t\t}}
+\tdefault:
+\t\treturn 0, false
+\t}}
+\treturn p.backoff(attempt), true
+}}
+
+// backoff returns the wait after attempt when the response gives none.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {{
+\tmultiplier := p.Multiplier
+\tif multiplier <= 0 {{
+\t\tmultiplier = 2
+\t}}
+\twait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+\tif p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {{
+\t\twait = float64(p.MaxBackoff)
+\t}}
+\tif p.Jitter > 0 {{
+\t\twait *= 1 + p.Jitter*(2*rand.Float64()-1)
+\t}}
+\treturn time.Duration(wait)
+}}
+
+// replayable reports whether a call with method may be sent again after
+// the server may have acted on it.
+func (p *RetryPolicy) replayable(method string) bool {{
+\tswitch method {{
+\tcase http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+\t\treturn true
+\t}}
+\treturn p.RetryNonIdempotent
+}}
+
+// transientNetworkError reports whether err is a network failure that may
+// not recur: a timeout, or a connection refused, reset, or cut short. The
+// call's own context ending is not one.
+func transientNetworkError(err error) bool {{
+\tif errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {{
+\t\treturn false
+\t}}
+\tvar opErr *net.OpError
+\tif errors.As(err, &opErr) {{
+\t\treturn true
+\t}}
+\tvar netErr net.Error
+\tif errors.As(err, &netErr) && netErr.Timeout() {{
+\t\treturn true
+\t}}
+\treturn errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}}
+"""
+    
+    def _generate_go_middleware(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"net/http"
+)
+
+// Doer sends an HTTP request; *http.Client is one.
+type Doer interface {{
+\tDo(req *http.Request) (*http.Response, error)
+}}
+
+// DoerFunc adapts a function to a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {{
+\treturn f(req)
+}}
+
+// Middleware wraps the Doer a client sends its requests with, to log,
+// measure, retry, or rewrite them:
+//
+//\tclient.Use(func(next Doer) Doer {{
+//\t\treturn DoerFunc(func(req *http.Request) (*http.Response, error) {{
+//\t\t\treq.Header.Set("X-Request-Source", "billing")
+//\t\t\treturn next.Do(req)
+//\t\t}})
+//\t}})
+//
+// Middleware sees each attempt of a call separately, after the client has
+// set its headers and credentials, and before the response is checked and
+// decoded.
+type Middleware func(next Doer) Doer
+
+// Use adds middleware to the client. The first added is the outermost, so
+// it sees a request first and its response last. Use is not safe to call
+// concurrently with requests; add middleware before sharing the client.
+func (c *{self.class_name}Client) Use(middleware ...Middleware) {{
+\tc.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+}}
+
+// WithMiddleware adds middleware to the client, as Use does.
+func WithMiddleware(middleware ...Middleware) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.Use(middleware...)
+\t}}
+}}
+
+// doer returns the HTTP client wrapped in the client's middleware. Upgrade
+// requests, such as WebSocket handshakes, are sent without the client's
+// Timeout, which would cut the upgraded connection short.
+func (c *{self.class_name}Client) doer(req *http.Request) Doer {{
+\tvar doer Doer = c.HTTPClient
+\tif c.HTTPClient.Timeout != 0 && req.Header.Get("Upgrade") != "" {{
+\t\tuntimed := *c.HTTPClient
+\t\tuntimed.Timeout = 0
+\t\tdoer = &untimed
+\t}}
+\tfor i := len(c.middleware) - 1; i >= 0; i-- {{
+\t\tdoer = c.middleware[i](doer)
+\t}}
+\treturn doer
+}}
+"""
+    
+    def _generate_go_upload(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"errors"
+\t"fmt"
+\t"io"
+\t"mime"
+\t"mime/multipart"
+\t"net/http"
+\t"net/textproto"
+\t"net/url"
+\t"path"
+\t"sort"
+\t"strings"
+\t"sync"
+)
+
+// uploadBody is a request body streamed from the caller's readers rather
+// than serialized: a multipart form holding files, or raw bytes.
+type uploadBody struct {{
+\tfields      interface{{}} // the form's text fields, as a request struct
+\tfiles       []formFile
+\traw         io.Reader
+\tcontentType string // of a raw body
+\toffsets     []int64
+}}
+
+// formFile is a file part of a multipart upload.
+type formFile struct {{
+\tfield    string
+\tfilename string
+\tcontent  io.Reader
+}}
+
+// errUploadSpent is returned when an upload is resent after its readers
+// were read and cannot seek back.
+var errUploadSpent = errors.New("upload body was already read and cannot be resent")
+
+// multipartBody returns a multipart/form-data body holding the fields of
+// data, named as in JSON, followed by files; files with a nil reader are
+// left out.
+func multipartBody(data interface{{}}, files ...formFile) *uploadBody {{
+\treturn &uploadBody{{fields: data, files: files}}
+}}
+
+// binaryBody returns a body sending content as is, labeled contentType.
+func binaryBody(contentType string, content io.Reader) *uploadBody {{
+\treturn &uploadBody{{raw: content, contentType: contentType}}
+}}
+
+// WithContentType overrides the Content-Type of a raw upload, which defaults
+// to the media type seen in the capture.
+func WithContentType(mediaType string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.header.Set("Content-Type", mediaType)
+\t}}
+}}
+
+// readers returns the caller's readers in the body.
+func (u *uploadBody) readers() []io.Reader {{
+\tif u.raw != nil {{
+\t\treturn []io.Reader{{u.raw}}
+\t}}
+\treaders := make([]io.Reader, 0, len(u.files))
+\tfor _, f := range u.files {{
+\t\tif f.content != nil {{
+\t\t\treaders = append(readers, f.content)
+\t\t}}
+\t}}
+\treturn readers
+}}
+
+// replayable reports whether the body can be sent again, as every reader
+// can seek back to where the first attempt started.
+func (u *uploadBody) replayable() bool {{
+\tfor _, r := range u.readers() {{
+\t\tif _, ok := r.(io.Seeker); !ok {{
+\t\t\treturn false
+\t\t}}
+\t}}
+\treturn true
+}}
+
+// rewind seeks the readers back to where the first attempt started them,
+// or notes where that is on the first attempt.
+func (u *uploadBody) rewind() error {{
+\treaders := u.readers()
+\tif u.offsets == nil {{
+\t\tu.offsets = make([]int64, len(readers))
+\t\tfor i, r := range readers {{
+\t\t\tif s, ok := r.(io.Seeker); ok {{
+\t\t\t\tu.offsets[i], _ = s.Seek(0, io.SeekCurrent)
+\t\t\t}}
+\t\t}}
+\t\treturn nil
+\t}}
+\tif !u.replayable() {{
+\t\treturn errUploadSpent
+\t}}
+\tfor i, r := range readers {{
+\t\tif _, err := r.(io.Seeker).Seek(u.offsets[i], io.SeekStart); err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+// attach sets the body of req, with its length when every reader's is
+// known, and its Content-Type unless a raw body's was given with
+// WithContentType. The form is only written once the request is sent.
+func (u *uploadBody) attach(req *http.Request) error {{
+\tif err := u.rewind(); err != nil {{
+\t\treturn err
+\t}}
+\tif u.raw != nil {{
+\t\tif req.Header.Get("Content-Type") == "" {{
+\t\t\treq.Header.Set("Content-Type", u.contentType)
+\t\t}}
+\t\tsetBody(req, io.NopCloser(u.raw), readerLength(u.raw))
+\t\treturn nil
+\t}}
+\tfields, err := formValues(u.fields)
+\tif err != nil {{
+\t\treturn fmt.Errorf("multipart: %w", err)
+\t}}
+\tpipeReader, pipeWriter := io.Pipe()
+\tform := multipart.NewWriter(pipeWriter)
+\treq.Header.Set("Content-Type", form.FormDataContentType())
+\tbody := &formReader{{pipe: pipeReader, write: func() {{
+\t\tpipeWriter.CloseWithError(writeForm(form, fields, u.files, true))
+\t}}}}
+\tsetBody(req, body, formLength(form.Boundary(), fields, u.files))
+\treturn nil
+}}
+
+// setBody sets the body of req, of length bytes or -1 when unknown.
+func setBody(req *http.Request, body io.ReadCloser, length int64) {{
+\tif length == 0 {{
+\t\tbody.Close()
+\t\tbody = http.NoBody
+\t}}
+\treq.Body, req.ContentLength = body, length
+}}
+
+// formReader writes the form as the transport reads it, starting only then
+// so that a request given up before it is sent leaves no writer blocked.
+type formReader struct {{
+\tpipe  *io.PipeReader
+\twrite func()
+\tonce  sync.Once
+}}
+
+func (r *formReader) Read(p []byte) (int, error) {{
+\tr.once.Do(func() {{ go r.write() }})
+\treturn r.pipe.Read(p)
+}}
+
+func (r *formReader) Close() error {{
+\treturn r.pipe.Close()
+}}
+
+var quoteEscaper = strings.NewReplacer("\\\\", "\\\\\\\\", `"`, "\\\\\\"")
+
+// writeForm writes the text fields and then the files to form, with each
+// file's content if withContent is set, and closes it.
+func writeForm(form *multipart.Writer, fields url.Values, files []formFile, withContent bool) error {{
+\tnames := make([]string, 0, len(fields))
+\tfor name := range fields {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\tfor _, name := range names {{
+\t\tfor _, value := range fields[name] {{
+\t\t\tif err := form.WriteField(name, value); err != nil {{
+\t\t\t\treturn err
+\t\t\t}}
+\t\t}}
+\t}}
+\tfor _, f := range files {{
+\t\tif f.content == nil {{
+\t\t\tcontinue
+\t\t}}
+\t\theader := make(textproto.MIMEHeader)
+\t\theader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+\t\t\tquoteEscaper.Replace(f.field), quoteEscaper.Replace(f.filename)))
+\t\tcontentType := mime.TypeByExtension(path.Ext(f.filename))
+\t\tif contentType == "" {{
+\t\t\tcontentType = "application/octet-stream"
+\t\t}}
+\t\theader.Set("Content-Type", contentType)
+\t\tpart, err := form.CreatePart(header)
+\t\tif err != nil {{
+\t\t\treturn err
+\t\t}}
+\t\tif withContent {{
+\t\t\tif _, err := io.Copy(part, f.content); err != nil {{
+\t\t\t\treturn err
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn form.Close()
+}}
+
+// formLength returns the length of the form writeForm writes with
+// boundary, or -1 when the length of a file is unknown.
+func formLength(boundary string, fields url.Values, files []formFile) int64 {{
+\tvar length int64
+\tfor _, f := range files {{
+\t\tif f.content == nil {{
+\t\t\tcontinue
+\t\t}}
+\t\tn := readerLength(f.content)
+\t\tif n < 0 {{
+\t\t\treturn -1
+\t\t}}
+\t\tlength += n
+\t}}
+\tvar counter countingWriter
+\tform := multipart.NewWriter(&counter)
+\tif err := form.SetBoundary(boundary); err != nil {{
+\t\treturn -1
+\t}}
+\tif err := writeForm(form, fields, files, false); err != nil {{
+\t\treturn -1
+\t}}
+\treturn length + int64(counter)
+}}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {{
+\t*w += countingWriter(len(p))
+\treturn len(p), nil
+}}
+
+// readerLength returns the number of bytes left in r, or -1 when unknown.
+func readerLength(r io.Reader) int64 {{
+\tswitch r := r.(type) {{
+\tcase interface{{ Len() int }}:
+\t\treturn int64(r.Len())
+\tcase io.Seeker:
+\t\tcurrent, err := r.Seek(0, io.SeekCurrent)
+\t\tif err != nil {{
+\t\t\treturn -1
+\t\t}}
+\t\tend, err := r.Seek(0, io.SeekEnd)
+\t\tif err != nil {{
+\t\t\treturn -1
+\t\t}}
+\t\tif _, err := r.Seek(current, io.SeekStart); err != nil {{
+\t\t\treturn -1
+\t\t}}
+\t\treturn end - current
+\t}}
+\treturn -1
+}}
+"""
+    
+    def _generate_go_download(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"io"
+\t"mime"
+\t"net/http"
+\t"net/url"
+)
+
+// Download is a response body handed over unread, for files, exports, and
+// images that are not decoded: read it, or copy it with io.Copy, and close
+// it when done.
+type Download struct {{
+\tio.ReadCloser
+\tContentType   string // the Content-Type header, e.g. "text/csv"
+\tContentLength int64  // -1 when unknown
+\tFilename      string // from Content-Disposition, when the server names the file
+\tHeader        http.Header
+\trelease       func()
+}}
+
+// Close closes the body. It is safe to call more than once.
+func (d *Download) Close() error {{
+\terr := d.ReadCloser.Close()
+\tif d.release != nil {{
+\t\td.release()
+\t\td.release = nil
+\t}}
+\treturn err
+}}
+
+// downloadHeaders leaves Accept-Encoding to the HTTP transport, which
+// inflates a compressed body as it is read instead of all at once.
+func downloadHeaders(o *requestOptions) {{
+\to.edits = append(o.edits, func(req *http.Request) {{
+\t\treq.Header.Del("Accept-Encoding")
+\t}})
+}}
+
+// download makes a call whose response body is a file, returning the body
+// unread.
+func (c *{self.class_name}Client) download(ctx context.Context, method, endpoint, path string, params url.Values, body interface{{}}, opts []RequestOption) (*Download, error) {{
+\tresp, release, err := c.openResponse(ctx, method, endpoint, path, params, body, append([]RequestOption{{downloadHeaders}}, opts...))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar filename string
+\tif _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {{
+\t\tfilename = params["filename"]
+\t}}
+\treturn &Download{{
+\t\tReadCloser:    resp.Body,
+\t\tContentType:   resp.Header.Get("Content-Type"),
+\t\tContentLength: resp.ContentLength,
+\t\tFilename:      filename,
+\t\tHeader:        resp.Header,
+\t\trelease:       release,
+\t}}, nil
+}}
+"""
+    
+    def _go_enum_constants(self, name: str) -> List[Tuple[str, str]]:
+        """The constants of enum type name, with the value of each."""
+        import re
+        constants, used = [], set()
+        for value in self.enum_values[name]:
+            constant = name + (self._go_param_field(value) if re.search(r'\w', value) else 'Empty')
+            while constant in used:
+                constant += '_'
+            used.add(constant)
+            constants.append((constant, value))
+        return constants
+    
+    def _go_readme_enums(self) -> str:
+        """The README section on enum types, with an example from a body
+        field of one; '' if the capture showed none."""
+        if not self.enum_values:
+            return ''
+        example = ''
+        for endpoint in self.endpoints.values():
+            schemas = [endpoint.response_schemas.get(self._go_success_status(endpoint)), endpoint.request_body_schema]
+            fields = [(name, prop) for schema in schemas for name, prop in ((schema or {}).get('properties') or {}).items()
+                      if self.enum_fields.get(id(prop)) in self.enum_values]
+            if fields:
+                name, prop = fields[0]
+                type_name, field = self.enum_fields[id(prop)], self._go_field(name, prop)[0]
+                words = self._to_snake_case(type_name).split('_')
+                var = words[0]
+                example = f"""
+```go
+if {var}.{field} == {self._go_enum_constants(type_name)[0][0]} {{
+    // ...
+}}
+if !{var}.{field}.Valid() {{
+    log.Printf("unknown {' '.join(words)} %q", {var}.{field})
+}}
+```
+"""
+                break
+        return f"""## Enums
+
+String fields the captured traffic showed taking only a few values get a
+type of their own, named after the resource and field, with a constant per
+value. Assigning a misspelled constant fails to compile, and `Valid` tells
+the known values from ones the API added since the capture, which still
+decode rather than failing the call{':' if example else '.'}
+{example}
+"""
+    
+    def _generate_go_enums(self, package_name: str) -> str:
+        """The named string types of _find_go_enums, with a constant per value
+        and a Valid method."""
+        import json
+        import textwrap
+        types = []
+        for name in self.enum_values:
+            constants = self._go_enum_constants(name)
+            words = self._to_snake_case(name).split('_')
+            width = max(len(constant) for constant, _ in constants)
+            doc = (f"{name} is the {' '.join(words[1:])} of a {words[0]}, one of the values seen in "
+                   "the captured traffic. Other values still decode, so that one the API adds "
+                   "later does not fail calls; Valid tells them apart.")
+            lines = [f"// {line}" for line in textwrap.wrap(doc, 74)]
+            lines += [
+                f"type {name} string",
+                "",
+                "const (",
+            ]
+            lines.extend(f"\t{constant:<{width}} {name} = {json.dumps(value)}" for constant, value in constants)
+            lines.extend([
+                ")",
+                "",
+                f"// Valid reports whether v is one of the known {name} values.",
+                f"func (v {name}) Valid() bool {{",
+                "\tswitch v {",
+                f"\tcase {', '.join(constant for constant, _ in constants)}:",
+                "\t\treturn true",
+                "\t}",
+                "\treturn false",
+                "}",
+            ])
+            types.append('\n'.join(lines))
+        return f"package {package_name}\n\n" + '\n\n'.join(types) + '\n'
+    
+    def _generate_go_instrument(self, package_name: str) -> str:
+        keys = sorted((f'"{endpoint.method} {endpoint.path_pattern}":', self._go_name(endpoint))
+                      for endpoint in self.endpoints.values())
+        width = max((len(key) for key, _ in keys), default=0) + 1
+        names = ''.join(f'\t{key:<{width}}"{name}",\n' for key, name in keys)
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"net/url"
+)
+
+// Instrumentation observes every API call, e.g. to trace it and record
+// metrics; the otel module implements it with OpenTelemetry.
+type Instrumentation interface {{
+\t// StartCall is called as a call starts. The call's requests are sent
+\t// with the context it returns, and end is called once the call is done.
+\tStartCall(ctx context.Context, call CallInfo) (_ context.Context, end func(CallResult))
+}}
+
+// CallInfo describes an API call to its Instrumentation.
+type CallInfo struct {{
+\tName      string // the client method, "{self.class_name}.CreateUser"
+\tOperation string // "POST /v1/users"
+\tMethod    string // "POST"
+\tEndpoint  string // "/v1/users"
+}}
+
+// CallResult is the outcome of an API call.
+type CallResult struct {{
+\tStatusCode int // the last response's status, 0 when none arrived
+\t// URL is the last request's URL, credential query parameters redacted;
+\t// nil when no request was sent.
+\tURL      *url.URL
+\tAttempts int // the requests sent, counting retries
+\tErr      error
+}}
+
+// WithInstrumentation reports every call the client makes to i.
+func WithInstrumentation(i Instrumentation) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.instrumentation = i
+\t}}
+}}
+
+// operationNames maps each operation to the client method calling it.
+var operationNames = map[string]string{{
+{names}}}
+
+// instrument reports the start of a call to the client's instrumentation,
+// switching options to the context it returns, and returns the function
+// that reports the call's end.
+func (c *{self.class_name}Client) instrument(method, endpoint string, options *requestOptions) func(err *error) {{
+\tif c.instrumentation == nil {{
+\t\treturn func(*error) {{}}
+\t}}
+\toperation := method + " " + endpoint
+\tname := operation
+\tif method, ok := operationNames[operation]; ok {{
+\t\tname = "{self.class_name}." + method
+\t}}
+\tctx, end := c.instrumentation.StartCall(options.ctx, CallInfo{{Name: name, Operation: operation, Method: method, Endpoint: endpoint}})
+\toptions.ctx = ctx
+\treturn func(err *error) {{
+\t\tresult := CallResult{{StatusCode: options.status, Err: *err}}
+\t\tif options.url != nil {{
+\t\t\tresult.URL = redactQuery(options.url)
+\t\t\tresult.Attempts = options.attempt
+\t\t}}
+\t\tend(result)
+\t}}
+}}
+"""
+    
+    def _generate_go_otel(self, module_path: str, sdk_import: str) -> str:
+        return f"""// Package otel instruments an {self.class_name}Client with OpenTelemetry: a client
+// span per API call, named after the client method ("{self.class_name}.CreateUser")
+// and carrying the HTTP semantic convention attributes, a counter of calls,
+// and a histogram of their durations. The trace context is propagated in
+// the request headers.
+//
+//\tclient := api.New{self.class_name}Client("", otel.WithTelemetry())
+//
+// It is a module of its own, so that only programs importing it depend on
+// OpenTelemetry.
+package otel
+
+import (
+\t"context"
+\t"fmt"
+\t"net/http"
+\t"strconv"
+\t"time"
+
+\tglobal "go.opentelemetry.io/otel"
+\t"go.opentelemetry.io/otel/attribute"
+\t"go.opentelemetry.io/otel/codes"
+\t"go.opentelemetry.io/otel/metric"
+\t"go.opentelemetry.io/otel/propagation"
+\t"go.opentelemetry.io/otel/trace"
+
+\tapi "{sdk_import}"
+)
+
+// scope is the instrumentation scope of the tracer and meter.
+const scope = "{module_path}/otel"
+
+// Option configures WithTelemetry.
+type Option func(*config)
+
+type config struct {{
+\ttracers    trace.TracerProvider
+\tmeters     metric.MeterProvider
+\tpropagator propagation.TextMapPropagator
+}}
+
+// WithTracerProvider creates spans with provider instead of the global
+// one.
+func WithTracerProvider(provider trace.TracerProvider) Option {{
+\treturn func(c *config) {{
+\t\tc.tracers = provider
+\t}}
+}}
+
+// WithMeterProvider records metrics with provider instead of the global
+// one.
+func WithMeterProvider(provider metric.MeterProvider) Option {{
+\treturn func(c *config) {{
+\t\tc.meters = provider
+\t}}
+}}
+
+// WithPropagator injects the trace context into requests with propagator
+// instead of the global one.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {{
+\treturn func(c *config) {{
+\t\tc.propagator = propagator
+\t}}
+}}
+
+// WithTelemetry instruments a client with the global tracer provider,
+// meter provider, and propagator, unless opts replace them.
+func WithTelemetry(opts ...Option) api.ClientOption {{
+\tcfg := config{{
+\t\ttracers:    global.GetTracerProvider(),
+\t\tmeters:     global.GetMeterProvider(),
+\t\tpropagator: global.GetTextMapPropagator(),
+\t}}
+\tfor _, opt := range opts {{
+\t\topt(&cfg)
+\t}}
+\tt := newTelemetry(cfg)
+\treturn func(c *api.{self.class_name}Client) {{
+\t\tapi.WithInstrumentation(t)(c)
+\t\tc.Use(t.propagate)
+\t}}
+}}
+
+// telemetry is the api.Instrumentation recording spans and metrics.
+type telemetry struct {{
+\ttracer     trace.Tracer
+\tpropagator propagation.TextMapPropagator
+\tcalls      metric.Int64Counter
+\tduration   metric.Float64Histogram
+}}
+
+func newTelemetry(cfg config) *telemetry {{
+\tmeter := cfg.meters.Meter(scope)
+\tcalls, err := meter.Int64Counter("{self.class_name.lower()}.client.calls",
+\t\tmetric.WithDescription("API calls made by the client, counting each call once however often it was retried."),
+\t\tmetric.WithUnit("{{call}}"))
+\tif err != nil {{
+\t\tglobal.Handle(err)
+\t}}
+\tduration, err := meter.Float64Histogram("{self.class_name.lower()}.client.call.duration",
+\t\tmetric.WithDescription("Duration of API calls, including retries."),
+\t\tmetric.WithUnit("s"))
+\tif err != nil {{
+\t\tglobal.Handle(err)
+\t}}
+\treturn &telemetry{{
+\t\ttracer:     cfg.tracers.Tracer(scope),
+\t\tpropagator: cfg.propagator,
+\t\tcalls:      calls,
+\t\tduration:   duration,
+\t}}
+}}
+
+// StartCall starts the call's span.
+func (t *telemetry) StartCall(ctx context.Context, call api.CallInfo) (context.Context, func(api.CallResult)) {{
+\tstart := time.Now()
+\tattrs := []attribute.KeyValue{{
+\t\tattribute.String("api.operation", call.Name),
+\t\tattribute.String("http.request.method", call.Method),
+\t\tattribute.String("url.template", call.Endpoint),
+\t}}
+\tctx, span := t.tracer.Start(ctx, call.Name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+\treturn ctx, func(result api.CallResult) {{
+\t\tif result.URL != nil {{
+\t\t\tspan.SetAttributes(attribute.String("url.full", result.URL.String()))
+\t\t\tattrs = append(attrs, attribute.String("server.address", result.URL.Hostname()))
+\t\t\tif port, err := strconv.Atoi(result.URL.Port()); err == nil {{
+\t\t\t\tattrs = append(attrs, attribute.Int("server.port", port))
+\t\t\t}}
+\t\t}}
+\t\tif result.Attempts > 1 {{
+\t\t\tspan.SetAttributes(attribute.Int("http.request.resend_count", result.Attempts-1))
+\t\t}}
+\t\tif result.StatusCode != 0 {{
+\t\t\tattrs = append(attrs, attribute.Int("http.response.status_code", result.StatusCode))
+\t\t}}
+\t\tif result.Err != nil {{
+\t\t\tattrs = append(attrs, attribute.String("error.type", errorType(result)))
+\t\t\tspan.RecordError(result.Err)
+\t\t\tspan.SetStatus(codes.Error, result.Err.Error())
+\t\t}}
+\t\tspan.SetAttributes(attrs...)
+\t\tspan.End()
+\t\tset := metric.WithAttributes(attrs...)
+\t\tt.calls.Add(ctx, 1, set)
+\t\tt.duration.Record(ctx, time.Since(start).Seconds(), set)
+\t}}
+}}
+
+// errorType is the error.type attribute of a failed call: its status code,
+// or the type of its error when it got no error status.
+func errorType(result api.CallResult) string {{
+\tif result.StatusCode >= 400 {{
+\t\treturn strconv.Itoa(result.StatusCode)
+\t}}
+\treturn fmt.Sprintf("%T", result.Err)
+}}
+
+// propagate is the middleware injecting the trace context of a request's
+// context into its headers.
+func (t *telemetry) propagate(next api.Doer) api.Doer {{
+\treturn api.DoerFunc(func(req *http.Request) (*http.Response, error) {{
+\t\tt.propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+\t\treturn next.Do(req)
+\t}})
+}}
+"""
+    
+    def _generate_go_otel_mod(self, module_path: str) -> str:
+        return f"""module {module_path}/otel
+
+go 1.25.0
+
+require (
+\t{module_path} v0.0.0
+\tgo.opentelemetry.io/otel v1.46.0
+\tgo.opentelemetry.io/otel/metric v1.46.0
+\tgo.opentelemetry.io/otel/trace v1.46.0
+)
+
+replace {module_path} => ../
+"""
+    
+    def _generate_go_slog(self, package_name: str) -> str:
+        return f"""//go:build go1.21
+
+package {package_name}
+
+import (
+\t"context"
+\t"log/slog"
+\t"net/http"
+\t"time"
+)
+
+// LogLevels are the levels WithLogger logs at.
+type LogLevels struct {{
+\tSuccess slog.Level // an attempt answered with a status below 400
+\tFailure slog.Level // an attempt answered with an error status, or not at all
+\tRetry   slog.Level // a failed attempt about to be retried
+\t// Headers is the level the logger must be enabled for to log request
+\t// and response headers as well, sensitive ones redacted.
+\tHeaders slog.Level
+}}
+
+// DefaultLogLevels logs attempts at Info, failures at Error, and retries at
+// Warn, with headers when the logger is enabled for Debug.
+var DefaultLogLevels = LogLevels{{
+\tSuccess: slog.LevelInfo,
+\tFailure: slog.LevelError,
+\tRetry:   slog.LevelWarn,
+\tHeaders: slog.LevelDebug,
+}}
+
+// WithLogger logs every HTTP attempt the client makes to logger, with its
+// method, URL, status, duration, and attempt number, and every retry with
+// the wait before it. Credential query parameters are redacted from URLs,
+// and Authorization, cookies, API keys, and the client's credential headers
+// from logged headers.
+func WithLogger(logger *slog.Logger) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.logger = &slogLogger{{logger: logger, levels: DefaultLogLevels, client: c}}
+\t}}
+}}
+
+// WithLogLevels changes the levels of the logger set by an earlier
+// WithLogger; without one it does nothing.
+func WithLogLevels(levels LogLevels) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif l, ok := c.logger.(*slogLogger); ok {{
+\t\t\tl.levels = levels
+\t\t}}
+\t}}
+}}
+
+type slogLogger struct {{
+\tlogger *slog.Logger
+\tlevels LogLevels
+\tclient *{self.class_name}Client
+}}
+
+func (l *slogLogger) logAttempt(ctx context.Context, operation string, attempt int, req *http.Request, resp *http.Response, took time.Duration, err error) {{
+\tlevel := l.levels.Success
+\tif err != nil || resp.StatusCode >= 400 {{
+\t\tlevel = l.levels.Failure
+\t}}
+\tif !l.logger.Enabled(ctx, level) {{
+\t\treturn
+\t}}
+\tattrs := []slog.Attr{{
+\t\tslog.String("operation", operation),
+\t\tslog.String("method", req.Method),
+\t\tslog.String("url", redactQuery(req.URL).String()),
+\t\tslog.Int("attempt", attempt),
+\t\tslog.Duration("duration", took),
+\t}}
+\tif resp != nil {{
+\t\tattrs = append(attrs, slog.Int("status", resp.StatusCode))
+\t}}
+\tif err != nil {{
+\t\tattrs = append(attrs, slog.String("error", err.Error()))
+\t}}
+\tif l.logger.Enabled(ctx, l.levels.Headers) {{
+\t\tattrs = append(attrs, l.headers("request_headers", req.Header))
+\t\tif resp != nil {{
+\t\t\tattrs = append(attrs, l.headers("response_headers", resp.Header))
+\t\t}}
+\t}}
+\tl.logger.LogAttrs(ctx, level, "http request", attrs...)
+}}
+
+func (l *slogLogger) logRetry(ctx context.Context, operation string, attempt int, wait time.Duration, err error) {{
+\tl.logger.LogAttrs(ctx, l.levels.Retry, "retrying http request",
+\t\tslog.String("operation", operation),
+\t\tslog.Int("attempt", attempt),
+\t\tslog.Duration("wait", wait),
+\t\tslog.String("error", err.Error()),
+\t)
+}}
+
+// headers returns header as a group, with sensitive values redacted.
+func (l *slogLogger) headers(key string, header http.Header) slog.Attr {{
+\tattrs := make([]any, 0, len(header))
+\tfor _, name := range sortedKeys(header) {{
+\t\tvalue := header.Values(name)
+\t\tif l.client.isSecretHeader(name) {{
+\t\t\tvalue = []string{{"REDACTED"}}
+\t\t}}
+\t\tif len(value) == 1 {{
+\t\t\tattrs = append(attrs, slog.String(name, value[0]))
+\t\t}} else {{
+\t\t\tattrs = append(attrs, slog.Any(name, value))
+\t\t}}
+\t}}
+\treturn slog.Group(key, attrs...)
+}}
+"""
+    
+    def _generate_go_stream(self, package_name: str, example: APIEndpoint) -> str:
+        call = f"client.{self._go_name(example)}({', '.join(['ctx'] + self._go_placeholder_args(example))})"
+        return f"""package {package_name}
+
+import (
+\t"bufio"
+\t"bytes"
+\t"context"
+\t"net/url"
+\t"strconv"
+\t"strings"
+\t"sync"
+\t"sync/atomic"
+\t"time"
+)
+
+// Event is one event of a streamed response: a server-sent event, or a
+// line of a JSON lines stream, which has no Type or ID.
+type Event[T any] struct {{
+\tType string // the event field; "message" when the event has none
+\tID   string // the id field, or the last id seen before this event
+\tData T      // the data field, decoded from JSON
+\tRaw  []byte // the data field as received
+}}
+
+// EventStream reads the events of a streamed response as they arrive,
+// rather than waiting for the response to end:
+//
+//\tstream, err := {call}
+//\tif err != nil {{
+//\t\treturn err
+//\t}}
+//\tdefer stream.Close()
+//\tfor stream.Next() {{
+//\t\tevent := stream.Event()
+//\t}}
+//\tif err := stream.Err(); err != nil {{
+//\t\treturn err
+//\t}}
+//
+// The stream ends when the server closes it, when ctx is done, or at a
+// "[DONE]" data field as some APIs send last. An EventStream is not safe
+// for concurrent use, but Close may be called from another goroutine to
+// stop a blocked Next.
+type EventStream[T any] struct {{
+\tsse       bool
+\tdecode    func(data []byte, v *T) error
+\tbody      interface{{ Close() error }}
+\tscanner   *bufio.Scanner
+\tevent     Event[T]
+\tlastID    string
+\tretry     time.Duration
+\terr       error
+\tdone      bool
+\tclosed    atomic.Bool
+\tcloseOnce sync.Once
+\trelease   func()
+}}
+
+// maxEventBytes bounds the size of one event when the client has no
+// response size limit.
+const maxEventBytes = 1 << 20
+
+// WithLastEventID sends a Last-Event-ID header, so a server-sent event
+// stream resumes after the event with id, e.g. the LastEventID of a stream
+// that broke off.
+func WithLastEventID(id string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.header.Set("Last-Event-ID", id)
+\t}}
+}}
+
+// streamHeaders asks for an event stream, uncompressed so that each event
+// can be read as soon as it arrives.
+func streamHeaders(o *requestOptions) {{
+\to.header.Set("Accept", "text/event-stream, application/x-ndjson")
+\to.header.Set("Accept-Encoding", "identity")
+}}
+
+// openStream makes a streaming call and returns its events, decoding each
+// into a T. The response body stays open until the stream is closed.
+func openStream[T any](ctx context.Context, c *{self.class_name}Client, method, endpoint, path string, params url.Values, body interface{{}}, opts []RequestOption) (*EventStream[T], error) {{
+\tresp, release, err := c.openResponse(ctx, method, endpoint, path, params, body, append([]RequestOption{{streamHeaders}}, opts...))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\toperation := method + " " + endpoint
+\tmaxEvent := c.limits.response
+\tif maxEvent <= 0 {{
+\t\tmaxEvent = maxEventBytes
+\t}}
+\tscanner := bufio.NewScanner(resp.Body)
+\tscanner.Buffer(make([]byte, 0, 4096), int(maxEvent))
+\tcontentType := strings.ToLower(resp.Header.Get("Content-Type"))
+\treturn &EventStream[T]{{
+\t\tsse: strings.HasPrefix(contentType, "text/event-stream"),
+\t\tdecode: func(data []byte, v *T) error {{
+\t\t\treturn c.decode(operation, data, v)
+\t\t}},
+\t\tbody:    resp.Body,
+\t\tscanner: scanner,
+\t\trelease: release,
+\t}}, nil
+}}
+
+// Next reads the next event, blocking until it arrives. It returns false at
+// the end of the stream or on an error; check Err to tell which.
+func (s *EventStream[T]) Next() bool {{
+\tif s.done {{
+\t\treturn false
+\t}}
+\tdata, ok := s.read()
+\tif ok && string(data) == "[DONE]" {{
+\t\tok = false
+\t}}
+\tif !ok {{
+\t\tif err := s.scanner.Err(); err != nil && s.err == nil && !s.closed.Load() {{
+\t\t\ts.err = err
+\t\t}}
+\t\ts.done = true
+\t\ts.Close()
+\t\treturn false
+\t}}
+\ts.event.Raw = data
+\tvar value T
+\tif err := s.decode(data, &value); err != nil {{
+\t\ts.err = err
+\t\ts.done = true
+\t\ts.Close()
+\t\treturn false
+\t}}
+\ts.event.Data = value
+\treturn true
+}}
+
+// read returns the data of the next event, keeping its type and id in
+// s.event.
+func (s *EventStream[T]) read() ([]byte, bool) {{
+\tif !s.sse {{
+\t\tfor s.scanner.Scan() {{
+\t\t\tif line := bytes.TrimSpace(s.scanner.Bytes()); len(line) > 0 {{
+\t\t\t\treturn append([]byte(nil), line...), true
+\t\t\t}}
+\t\t}}
+\t\treturn nil, false
+\t}}
+
+\tvar data []byte
+\teventType, hasData := "", false
+\tfor s.scanner.Scan() {{
+\t\tline := s.scanner.Bytes()
+\t\tif len(line) == 0 {{
+\t\t\tif !hasData {{
+\t\t\t\teventType = ""
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tif eventType == "" {{
+\t\t\t\teventType = "message"
+\t\t\t}}
+\t\t\ts.event.Type, s.event.ID = eventType, s.lastID
+\t\t\treturn data, true
+\t\t}}
+\t\tif line[0] == ':' {{
+\t\t\tcontinue // a comment, often sent to keep the connection open
+\t\t}}
+\t\tfield, value, _ := bytes.Cut(line, []byte(":"))
+\t\tvalue = bytes.TrimPrefix(value, []byte(" "))
+\t\tswitch string(field) {{
+\t\tcase "event":
+\t\t\teventType = string(value)
+\t\tcase "data":
+\t\t\tif hasData {{
+\t\t\t\tdata = append(data, '\\n')
+\t\t\t}}
+\t\t\tdata, hasData = append(data, value...), true
+\t\tcase "id":
+\t\t\tif bytes.IndexByte(value, 0) < 0 {{
+\t\t\t\ts.lastID = string(value)
+\t\t\t}}
+\t\tcase "retry":
+\t\t\tif ms, err := strconv.Atoi(string(value)); err == nil {{
+\t\t\t\ts.retry = time.Duration(ms) * time.Millisecond
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn nil, false
+}}
+
+// Event returns the current event.
+func (s *EventStream[T]) Event() Event[T] {{
+\treturn s.event
+}}
+
+// Err returns the error that ended the stream, if any.
+func (s *EventStream[T]) Err() error {{
+\treturn s.err
+}}
+
+// LastEventID returns the id of the last event that had one, to resume
+// the stream from with WithLastEventID.
+func (s *EventStream[T]) LastEventID() string {{
+\treturn s.lastID
+}}
+
+// Retry returns the reconnection delay the server asked for, or zero.
+func (s *EventStream[T]) Retry() time.Duration {{
+\treturn s.retry
+}}
+
+// Close closes the response body. It is safe to call more than once.
+func (s *EventStream[T]) Close() error {{
+\tvar err error
+\ts.closeOnce.Do(func() {{
+\t\ts.closed.Store(true)
+\t\terr = s.body.Close()
+\t\ts.release()
+\t}})
+\treturn err
+}}
+
+// Events sends the events of the stream on a channel, which is closed when
+// the stream ends; Err then reports why. The stream is closed when ctx is
+// done.
+func (s *EventStream[T]) Events(ctx context.Context) <-chan Event[T] {{
+\tevents := make(chan Event[T])
+\tgo func() {{
+\t\tdefer close(events)
+\t\tdone := make(chan struct{{}})
+\t\tdefer close(done)
+\t\tgo func() {{
+\t\t\tselect {{
+\t\t\tcase <-ctx.Done():
+\t\t\t\ts.Close()
+\t\t\tcase <-done:
+\t\t\t}}
+\t\t}}()
+\t\tfor s.Next() {{
+\t\t\tselect {{
+\t\t\tcase events <- s.Event():
+\t\t\tcase <-ctx.Done():
+\t\t\t\ts.Close()
+\t\t\t\treturn
+\t\t\t}}
+\t\t}}
+\t}}()
+\treturn events
+}}
+"""
+    
+    def _generate_go_checksum(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"crypto/md5"
+\t"crypto/sha1"
+\t"crypto/sha256"
+\t"crypto/sha512"
+\t"encoding/base64"
+\t"fmt"
+\t"hash"
+\t"hash/crc32"
+\t"io"
+\t"net/http"
+\t"strings"
+)
+
+// ChecksumError reports a response body that does not match the checksum
+// the server sent with it.
+type ChecksumError struct {{
+\tOperation string // "GET /v1/files/{{id}}"
+\tHeader    string // header carrying the checksum, e.g. "Content-MD5"
+\tAlgorithm string // "md5", "sha-256", "crc32c", ...
+\tExpected  string // base64, as sent
+\tActual    string // base64, computed
+}}
+
+func (e *ChecksumError) Error() string {{
+\treturn fmt.Sprintf("%s: %s checksum mismatch in %s: expected %s, got %s", e.Operation, e.Algorithm, e.Header, e.Expected, e.Actual)
+}}
+
+// WithoutChecksumValidation stops verifying response checksums for the given
+// operations ("GET /v1/files/{{id}}"), or for every operation when none are
+// given. Checksums are verified by default whenever a response carries
+// Content-MD5, x-amz-checksum-*, Digest, or Content-Digest headers.
+func WithoutChecksumValidation(operations ...string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif len(operations) == 0 {{
+\t\t\tc.checksums.disabled = true
+\t\t\treturn
+\t\t}}
+\t\tif c.checksums.skip == nil {{
+\t\t\tc.checksums.skip = make(map[string]bool)
+\t\t}}
+\t\tfor _, operation := range operations {{
+\t\t\tc.checksums.skip[operation] = true
+\t\t}}
+\t}}
+}}
+
+// checksumPolicy holds which operations skip checksum validation.
+type checksumPolicy struct {{
+\tdisabled bool
+\tskip     map[string]bool
+}}
+
+// checksumAlgorithms maps algorithm names, as used in Digest headers and
+// x-amz-checksum-* suffixes, to hash constructors.
+var checksumAlgorithms = map[string]func() hash.Hash{{
+\t"md5":     md5.New,
+\t"sha":     sha1.New,
+\t"sha1":    sha1.New,
+\t"sha-256": sha256.New,
+\t"sha256":  sha256.New,
+\t"sha-512": sha512.New,
+\t"sha512":  sha512.New,
+\t"crc32":   func() hash.Hash {{ return crc32.NewIEEE() }},
+\t"crc32c":  func() hash.Hash {{ return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }},
+}}
+
+// verify checks body against every checksum header in resp it understands.
+func (p checksumPolicy) verify(operation string, resp *http.Response, body []byte) error {{
+\tfor _, digest := range p.digests(operation, resp) {{
+\t\tdigest.hash.Write(body)
+\t\tif err := digest.check(operation); err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+// responseDigest is a checksum a response carries, with the hash of the
+// body to compare it with.
+type responseDigest struct {{
+\theader    string
+\talgorithm string
+\texpected  string // base64
+\thash      hash.Hash
+}}
+
+// digests returns the checksums in resp's headers whose algorithm is
+// known, with empty hashes. Bodies the transport decompressed have none,
+// as their checksums cover the compressed bytes.
+func (p checksumPolicy) digests(operation string, resp *http.Response) []*responseDigest {{
+\tif p.disabled || p.skip[operation] || resp.Uncompressed {{
+\t\treturn nil
+\t}}
+\tvar digests []*responseDigest
+\tadd := func(header, algorithm, expected string) {{
+\t\tif newHash, ok := checksumAlgorithms[algorithm]; ok {{
+\t\t\tdigests = append(digests, &responseDigest{{header: header, algorithm: algorithm, expected: expected, hash: newHash()}})
+\t\t}}
+\t}}
+\tfor header, values := range resp.Header {{
+\t\tname := strings.ToLower(header)
+\t\tfor _, value := range values {{
+\t\t\tswitch {{
+\t\t\tcase name == "content-md5":
+\t\t\t\tadd(header, "md5", value)
+\t\t\tcase strings.HasPrefix(name, "x-amz-checksum-"):
+\t\t\t\tadd(header, strings.TrimPrefix(name, "x-amz-checksum-"), value)
+\t\t\tcase name == "digest" || name == "content-digest":
+\t\t\t\t// "sha-256=abc=, md5=def=" (RFC 3230) or "sha-256=:abc=:" (RFC 9530).
+\t\t\t\tfor _, part := range strings.Split(value, ",") {{
+\t\t\t\t\tif algorithm, digest, ok := strings.Cut(strings.TrimSpace(part), "="); ok {{
+\t\t\t\t\t\tadd(header, strings.ToLower(algorithm), strings.Trim(digest, ":"))
+\t\t\t\t\t}}
+\t\t\t\t}}
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn digests
+}}
+
+// check compares the hash of the body written so far with the expected
+// digest.
+func (d *responseDigest) check(operation string) error {{
+\tsum := d.hash.Sum(nil)
+\tif want, err := base64.StdEncoding.DecodeString(d.expected); err == nil && bytes.Equal(want, sum) {{
+\t\treturn nil
+\t}}
+\treturn &ChecksumError{{Operation: operation, Header: d.header, Algorithm: d.algorithm, Expected: d.expected, Actual: base64.StdEncoding.EncodeToString(sum)}}
+}}
+
+// verifiedBody applies the checks send makes on a buffered body to the
+// body of a download or stream, which is read as it arrives: checksums are
+// computed while it is read, and a signed body is kept until its end to
+// verify the signature. A failed check is returned by the Read reaching
+// the end of the body, in place of io.EOF, so what was read before is not
+// to be trusted until then.
+func (c *{self.class_name}Client) verifiedBody(operation string, resp *http.Response) io.ReadCloser {{
+\tdigests := c.checksums.digests(operation, resp)
+\tif len(digests) == 0 && c.signatures == nil {{
+\t\treturn resp.Body
+\t}}
+\treturn &verifyingReader{{ReadCloser: resp.Body, operation: operation, resp: resp, digests: digests, signatures: c.signatures}}
+}}
+
+// verifyingReader checks a response body once it has been read to the end.
+type verifyingReader struct {{
+\tio.ReadCloser
+\toperation  string
+\tresp       *http.Response
+\tdigests    []*responseDigest
+\tsignatures *SignatureVerifier
+\tsigned     bytes.Buffer // the body, for the signature
+\terr        error        // the result at the end of the body
+}}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {{
+\tif r.err != nil {{
+\t\treturn 0, r.err
+\t}}
+\tn, err := r.ReadCloser.Read(p)
+\tfor _, digest := range r.digests {{
+\t\tdigest.hash.Write(p[:n])
+\t}}
+\tif r.signatures != nil {{
+\t\tr.signed.Write(p[:n])
+\t}}
+\tif err == io.EOF {{
+\t\tr.err = r.verify()
+\t\tif r.err == nil {{
+\t\t\tr.err = io.EOF
+\t\t}}
+\t\treturn n, r.err
+\t}}
+\treturn n, err
+}}
+
+func (r *verifyingReader) verify() error {{
+\tfor _, digest := range r.digests {{
+\t\tif err := digest.check(r.operation); err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\terr := r.signatures.verify(r.operation, r.resp, r.signed.Bytes())
+\tr.signed = bytes.Buffer{{}}
+\treturn err
+}}
+"""
+    
+    def _generate_go_checksum_test(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"crypto/hmac"
+\t"crypto/sha256"
+\t"encoding/base64"
+\t"errors"
+\t"io"
+\t"net/http"
+\t"strings"
+\t"testing"
+)
+
+func TestVerifiedBody(t *testing.T) {{
+\tconst body = `{{"id":1}}`
+\tsum := sha256.Sum256([]byte(body))
+\tgood := base64.StdEncoding.EncodeToString(sum[:])
+\tbad := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+\ttests := []struct {{
+\t\tname    string
+\t\theader  string
+\t\topts    []ClientOption
+\t\twantErr bool
+\t}}{{
+\t\t{{"matching digest", "sha-256=:" + good + ":", nil, false}},
+\t\t{{"mismatched digest", "sha-256=:" + bad + ":", nil, true}},
+\t\t{{"unknown algorithm", "unixsum=30637", nil, false}},
+\t\t{{"validation off", "sha-256=:" + bad + ":", []ClientOption{{WithoutChecksumValidation("GET /v1/users/{{id}}")}}, false}},
+\t}}
+\tfor _, tt := range tests {{
+\t\tt.Run(tt.name, func(t *testing.T) {{
+\t\t\tc := New{self.class_name}Client("", tt.opts...)
+\t\t\tresp := &http.Response{{Header: http.Header{{"Content-Digest": {{tt.header}}}}, Body: io.NopCloser(strings.NewReader(body))}}
+\t\t\tgot, err := io.ReadAll(c.verifiedBody("GET /v1/users/{{id}}", resp))
+\t\t\tif string(got) != body {{
+\t\t\t\tt.Errorf("read %q, want %q", got, body)
+\t\t\t}}
+\t\t\tvar checksumErr *ChecksumError
+\t\t\tif tt.wantErr != errors.As(err, &checksumErr) {{
+\t\t\t\tt.Errorf("err = %v, want ChecksumError: %v", err, tt.wantErr)
+\t\t\t}}
+\t\t}})
+\t}}
+}}
+
+func TestVerifiedBodySignature(t *testing.T) {{
+\tconst body = `{{"id":1}}`
+\tkey := []byte("secret")
+\tmac := hmac.New(sha256.New, key)
+\tmac.Write([]byte("1." + body))
+\tsignature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+\tc := New{self.class_name}Client("", WithResponseSignatures(SignatureVerifier{{
+\t\tKeys:            StaticSignatureKey(key),
+\t\tHeader:          "X-Signature",
+\t\tTimestampHeader: "X-Timestamp",
+\t\tMaxSkew:         -1,
+\t}}))
+\tfor _, tt := range []struct {{
+\t\tname, body string
+\t\twantErr    bool
+\t}}{{
+\t\t{{"signed", body, false}},
+\t\t{{"tampered", `{{"id":2}}`, true}},
+\t}} {{
+\t\tt.Run(tt.name, func(t *testing.T) {{
+\t\t\tresp := &http.Response{{
+\t\t\t\tHeader: http.Header{{"X-Signature": {{signature}}, "X-Timestamp": {{"1"}}}},
+\t\t\t\tBody:   io.NopCloser(strings.NewReader(tt.body)),
+\t\t\t}}
+\t\t\t_, err := io.ReadAll(c.verifiedBody("GET /v1/users/{{id}}", resp))
+\t\t\tvar signatureErr *SignatureError
+\t\t\tif tt.wantErr != errors.As(err, &signatureErr) {{
+\t\t\t\tt.Errorf("err = %v, want SignatureError: %v", err, tt.wantErr)
+\t\t\t}}
+\t\t}})
+\t}}
+}}
+
+func TestVerifiedBodyWithoutKeys(t *testing.T) {{
+\tc := New{self.class_name}Client("", WithResponseSignatures(SignatureVerifier{{Header: "X-Signature", TimestampHeader: "X-Timestamp", MaxSkew: -1}}))
+\tresp := &http.Response{{
+\t\tHeader: http.Header{{"X-Signature": {{"c2lnbmF0dXJl"}}, "X-Timestamp": {{"1"}}}},
+\t\tBody:   io.NopCloser(strings.NewReader(`{{}}`)),
+\t}}
+\t_, err := io.ReadAll(c.verifiedBody("GET /v1/users/{{id}}", resp))
+\tvar signatureErr *SignatureError
+\tif !errors.As(err, &signatureErr) {{
+\t\tt.Errorf("err = %v, want SignatureError", err)
+\t}}
+}}
+"""
+    
+    def _generate_go_jose(self, package_name: str, jose_operations: Dict[str, bool]) -> str:
+        import json
+        width = max((len(json.dumps(operation)) + 1 for operation in jose_operations), default=0)
+        operations = ''.join(f'\t{(json.dumps(operation) + ":").ljust(width)} {str(encrypt).lower()},\n'
+                             for operation, encrypt in sorted(jose_operations.items()))
+        if operations:
+            operations = '\n' + operations
+        return f"""package {package_name}
+
+import (
+\t"crypto"
+\t"crypto/aes"
+\t"crypto/cipher"
+\t"crypto/ecdsa"
+\t"crypto/ed25519"
+\t"crypto/hmac"
+\t"crypto/rand"
+\t"crypto/rsa"
+\t"crypto/sha256"
+\t_ "crypto/sha512"
+\t"encoding/base64"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"math/big"
+\t"mime"
+\t"strings"
+)
+
+// PayloadCodec transforms request and response bodies on the wire, for APIs
+// that wrap JSON in encryption or signatures. Bodies are JSON on the SDK
+// side of the codec.
+type PayloadCodec interface {{
+\t// EncodeRequest encodes a JSON request body, returning the body to send
+\t// and its Content-Type.
+\tEncodeRequest(operation string, body []byte) ([]byte, string, error)
+\t// DecodeResponse turns a response body back into JSON.
+\tDecodeResponse(operation, contentType string, body []byte) ([]byte, error)
+}}
+
+// WithPayloadCodec applies codec to the given operations ("POST /v1/x").
+func WithPayloadCodec(codec PayloadCodec, operations ...string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif c.codecs == nil {{
+\t\t\tc.codecs = make(map[string]PayloadCodec)
+\t\t}}
+\t\tfor _, operation := range operations {{
+\t\t\tc.codecs[operation] = codec
+\t\t}}
+\t}}
+}}
+
+// joseOperations are the operations whose captured traffic used a JOSE
+// content type, mapped to whether their request bodies were encrypted.
+var joseOperations = map[string]bool{{{operations}}}
+
+// WithJOSE applies a JOSECodec to every operation captured with JOSE
+// payloads, encrypting request bodies where the capture did, and verifying
+// signed responses.
+func WithJOSE(keys JOSEKeyResolver) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tfor operation, encrypt := range joseOperations {{
+\t\t\tWithPayloadCodec(&JOSECodec{{Keys: keys, EncryptRequests: encrypt}}, operation)(c)
+\t\t}}
+\t}}
+}}
+
+// ErrJWSInvalid is returned (wrapped) for a signed response that fails
+// verification.
+var ErrJWSInvalid = errors.New("invalid JWS signature")
+
+// JOSEKeyResolver supplies the keys for JOSE payloads, e.g. from a JWKS
+// endpoint or a key management service.
+type JOSEKeyResolver interface {{
+\t// EncryptionKey returns the key request bodies for operation are
+\t// encrypted to, with its key ID: an *rsa.PublicKey (RSA-OAEP-256 with
+\t// A256GCM) or a 16, 24, or 32 byte []byte used directly (dir with
+\t// AES-GCM).
+\tEncryptionKey(operation string) (key interface{{}}, keyID string, err error)
+\t// VerificationKey returns the key for a JWS with the given key ID and
+\t// algorithm: an *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, or
+\t// a []byte HMAC secret.
+\tVerificationKey(keyID, alg string) (interface{{}}, error)
+}}
+
+// StaticJOSEKeys is a JOSEKeyResolver over fixed keys.
+type StaticJOSEKeys struct {{
+\tEncryption   interface{{}}
+\tEncryptionID string
+\t// Verification holds keys by key ID; "" matches any key ID.
+\tVerification map[string]interface{{}}
+}}
+
+// EncryptionKey implements JOSEKeyResolver.
+func (k StaticJOSEKeys) EncryptionKey(string) (interface{{}}, string, error) {{
+\tif k.Encryption == nil {{
+\t\treturn nil, "", errors.New("no JWE encryption key")
+\t}}
+\treturn k.Encryption, k.EncryptionID, nil
+}}
+
+// VerificationKey implements JOSEKeyResolver.
+func (k StaticJOSEKeys) VerificationKey(keyID, _ string) (interface{{}}, error) {{
+\tif key, ok := k.Verification[keyID]; ok {{
+\t\treturn key, nil
+\t}}
+\tif key, ok := k.Verification[""]; ok {{
+\t\treturn key, nil
+\t}}
+\treturn nil, fmt.Errorf("no JWS verification key %q", keyID)
+}}
+
+// JOSECodec encrypts request bodies as compact JWE and verifies compact JWS
+// responses, returning their payload. Responses without a JOSE content type
+// pass through unchanged.
+type JOSECodec struct {{
+\tKeys            JOSEKeyResolver
+\tEncryptRequests bool
+}}
+
+type joseHeader struct {{
+\tAlg string `json:"alg"`
+\tEnc string `json:"enc,omitempty"`
+\tKid string `json:"kid,omitempty"`
+\tCty string `json:"cty,omitempty"`
+}}
+
+var b64url = base64.RawURLEncoding
+
+// EncodeRequest implements PayloadCodec.
+func (j *JOSECodec) EncodeRequest(operation string, body []byte) ([]byte, string, error) {{
+\tif !j.EncryptRequests {{
+\t\treturn body, "application/json", nil
+\t}}
+\tkey, keyID, err := j.Keys.EncryptionKey(operation)
+\tif err != nil {{
+\t\treturn nil, "", fmt.Errorf("jwe: %w", err)
+\t}}
+\theader := joseHeader{{Kid: keyID, Cty: "application/json"}}
+\tvar cek, encryptedKey []byte
+\tswitch key := key.(type) {{
+\tcase *rsa.PublicKey:
+\t\theader.Alg, header.Enc = "RSA-OAEP-256", "A256GCM"
+\t\tcek = make([]byte, 32)
+\t\tif _, err := rand.Read(cek); err != nil {{
+\t\t\treturn nil, "", err
+\t\t}}
+\t\tif encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, key, cek, nil); err != nil {{
+\t\t\treturn nil, "", fmt.Errorf("jwe: %w", err)
+\t\t}}
+\tcase []byte:
+\t\tif len(key) != 16 && len(key) != 24 && len(key) != 32 {{
+\t\t\treturn nil, "", fmt.Errorf("jwe: dir key must be 16, 24, or 32 bytes, not %d", len(key))
+\t\t}}
+\t\theader.Alg, header.Enc = "dir", fmt.Sprintf("A%dGCM", len(key)*8)
+\t\tcek = key
+\tdefault:
+\t\treturn nil, "", fmt.Errorf("jwe: unsupported key type %T", key)
+\t}}
+
+\theaderJSON, _ := json.Marshal(header)
+\tprotected := b64url.EncodeToString(headerJSON)
+\tblock, err := aes.NewCipher(cek)
+\tif err != nil {{
+\t\treturn nil, "", err
+\t}}
+\tgcm, err := cipher.NewGCM(block)
+\tif err != nil {{
+\t\treturn nil, "", err
+\t}}
+\tiv := make([]byte, gcm.NonceSize())
+\tif _, err := rand.Read(iv); err != nil {{
+\t\treturn nil, "", err
+\t}}
+\tsealed := gcm.Seal(nil, iv, body, []byte(protected))
+\tciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+\tcompact := strings.Join([]string{{
+\t\tprotected,
+\t\tb64url.EncodeToString(encryptedKey),
+\t\tb64url.EncodeToString(iv),
+\t\tb64url.EncodeToString(ciphertext),
+\t\tb64url.EncodeToString(tag),
+\t}}, ".")
+\treturn []byte(compact), "application/jose", nil
+}}
+
+// DecodeResponse implements PayloadCodec.
+func (j *JOSECodec) DecodeResponse(operation, contentType string, body []byte) ([]byte, error) {{
+\tmediaType, _, _ := mime.ParseMediaType(contentType)
+\tif mediaType != "application/jose" && mediaType != "application/jwt" {{
+\t\treturn body, nil
+\t}}
+\tparts := strings.Split(strings.TrimSpace(string(body)), ".")
+\tif len(parts) == 5 {{
+\t\treturn nil, fmt.Errorf("%s: encrypted (JWE) responses are not supported", operation)
+\t}}
+\tif len(parts) != 3 {{
+\t\treturn nil, fmt.Errorf("%s: %w: not a compact JWS", operation, ErrJWSInvalid)
+\t}}
+\theaderJSON, err := b64url.DecodeString(parts[0])
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+\t}}
+\tvar header joseHeader
+\tif err := json.Unmarshal(headerJSON, &header); err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+\t}}
+\tsignature, err := b64url.DecodeString(parts[2])
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+\t}}
+\tkey, err := j.Keys.VerificationKey(header.Kid, header.Alg)
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("%s: jws: %w", operation, err)
+\t}}
+\tif err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w: %v", operation, ErrJWSInvalid, err)
+\t}}
+\treturn b64url.DecodeString(parts[1])
+}}
+
+// jwsHashes maps JWS algorithm suffixes to hashes.
+var jwsHashes = map[string]crypto.Hash{{"256": crypto.SHA256, "384": crypto.SHA384, "512": crypto.SHA512}}
+
+// verifySignature checks signature over input with key for a JWS algorithm
+// name, requiring the key type to match the algorithm family so an RSA
+// public key can never be used as an HMAC secret. ECDSA signatures may be
+// raw (JWS) or ASN.1 DER.
+func verifySignature(alg string, key interface{{}}, input, signature []byte) error {{
+\tif alg == "EdDSA" {{
+\t\tpub, ok := key.(ed25519.PublicKey)
+\t\tif !ok || !ed25519.Verify(pub, input, signature) {{
+\t\t\treturn errors.New("EdDSA verification failed")
+\t\t}}
+\t\treturn nil
+\t}}
+\tif len(alg) != 5 {{
+\t\treturn fmt.Errorf("unsupported algorithm %q", alg)
+\t}}
+\thash, ok := jwsHashes[alg[2:]]
+\tif !ok {{
+\t\treturn fmt.Errorf("unsupported algorithm %q", alg)
+\t}}
+\th := hash.New()
+\th.Write(input)
+\tdigest := h.Sum(nil)
+
+\tswitch alg[:2] {{
+\tcase "HS":
+\t\tsecret, ok := key.([]byte)
+\t\tif !ok {{
+\t\t\treturn fmt.Errorf("%s needs a []byte key, not %T", alg, key)
+\t\t}}
+\t\tmac := hmac.New(hash.New, secret)
+\t\tmac.Write(input)
+\t\tif !hmac.Equal(mac.Sum(nil), signature) {{
+\t\t\treturn errors.New("HMAC mismatch")
+\t\t}}
+\tcase "RS", "PS":
+\t\tpub, ok := key.(*rsa.PublicKey)
+\t\tif !ok {{
+\t\t\treturn fmt.Errorf("%s needs an *rsa.PublicKey, not %T", alg, key)
+\t\t}}
+\t\tif alg[0] == 'R' {{
+\t\t\treturn rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+\t\t}}
+\t\treturn rsa.VerifyPSS(pub, hash, digest, signature, nil)
+\tcase "ES":
+\t\tpub, ok := key.(*ecdsa.PublicKey)
+\t\tif !ok {{
+\t\t\treturn fmt.Errorf("%s needs an *ecdsa.PublicKey, not %T", alg, key)
+\t\t}}
+\t\tsize := (pub.Curve.Params().BitSize + 7) / 8
+\t\tif len(signature) != 2*size {{
+\t\t\tif !ecdsa.VerifyASN1(pub, digest, signature) {{
+\t\t\t\treturn errors.New("ECDSA verification failed")
+\t\t\t}}
+\t\t\treturn nil
+\t\t}}
+\t\tr := new(big.Int).SetBytes(signature[:size])
+\t\ts := new(big.Int).SetBytes(signature[size:])
+\t\tif !ecdsa.Verify(pub, digest, r, s) {{
+\t\t\treturn errors.New("ECDSA verification failed")
+\t\t}}
+\tdefault:
+\t\treturn fmt.Errorf("unsupported algorithm %q", alg)
+\t}}
+\treturn nil
+}}
+"""
+    
+    def _generate_go_signature(self, package_name: str, signature_header: str, timestamp_header: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"encoding/base64"
+\t"encoding/hex"
+\t"fmt"
+\t"net/http"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+// Response signature headers seen in the captured traffic, or the common
+// X-Signature and X-Timestamp when none were.
+const (
+\tsignatureHeader          = "{signature_header}"
+\tsignatureTimestampHeader = "{timestamp_header}"
+)
+
+// SignatureAlgorithm names a response signature algorithm, using the JWS
+// algorithm names.
+type SignatureAlgorithm string
+
+// Supported response signature algorithms.
+const (
+\tSignatureHS256 SignatureAlgorithm = "HS256" // HMAC-SHA256, the default
+\tSignatureHS512 SignatureAlgorithm = "HS512"
+\tSignatureRS256 SignatureAlgorithm = "RS256"
+\tSignaturePS256 SignatureAlgorithm = "PS256"
+\tSignatureES256 SignatureAlgorithm = "ES256"
+\tSignatureEdDSA SignatureAlgorithm = "EdDSA"
+)
+
+// SignatureKeySource returns the key verifying a response signature: a
+// []byte HMAC secret, *rsa.PublicKey, *ecdsa.PublicKey, or
+// ed25519.PublicKey. keyID is the signature's key ID ("keyId=..." in the
+// signature header), or empty.
+type SignatureKeySource interface {{
+\tSignatureKey(keyID string) (interface{{}}, error)
+}}
+
+// SignatureKeyFunc adapts a function to SignatureKeySource.
+type SignatureKeyFunc func(keyID string) (interface{{}}, error)
+
+// SignatureKey implements SignatureKeySource.
+func (f SignatureKeyFunc) SignatureKey(keyID string) (interface{{}}, error) {{
+\treturn f(keyID)
+}}
+
+// StaticSignatureKey returns a SignatureKeySource for a single key.
+func StaticSignatureKey(key interface{{}}) SignatureKeySource {{
+\treturn SignatureKeyFunc(func(string) (interface{{}}, error) {{ return key, nil }})
+}}
+
+// SignatureVerifier checks signed responses before they are decoded. The
+// signature covers the timestamp header's value, a dot, and the raw body,
+// unless Payload says otherwise. Signature headers may hold hex or base64,
+// optionally prefixed ("sha256=..."), or Stripe-style "t=...,v1=..." pairs
+// carrying the timestamp themselves.
+type SignatureVerifier struct {{
+\tKeys      SignatureKeySource
+\tAlgorithm SignatureAlgorithm // default SignatureHS256
+\t// Header and TimestampHeader default to the headers seen in the capture.
+\tHeader          string
+\tTimestampHeader string
+\t// MaxSkew rejects timestamps further than this from now; zero means five
+\t// minutes, negative disables the check.
+\tMaxSkew time.Duration
+\t// Payload builds the signed bytes from the timestamp and body.
+\tPayload func(timestamp string, body []byte) []byte
+\t// AllowUnsigned accepts responses without a signature header.
+\tAllowUnsigned bool
+}}
+
+// SignatureError reports a response whose signature is missing or invalid.
+// The response body is discarded.
+type SignatureError struct {{
+\tOperation string
+\tReason    string
+}}
+
+func (e *SignatureError) Error() string {{
+\treturn fmt.Sprintf("%s: response signature rejected: %s", e.Operation, e.Reason)
+}}
+
+// WithResponseSignatures verifies every response with v, rejecting tampered
+// or replayed responses with a *SignatureError. Downloaded and streamed
+// bodies are held in memory until their end, where they are verified.
+func WithResponseSignatures(v SignatureVerifier) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.signatures = &v
+\t}}
+}}
+
+// verify checks the signature of a response; it is a no-op on a nil
+// verifier.
+func (v *SignatureVerifier) verify(operation string, resp *http.Response, body []byte) error {{
+\tif v == nil {{
+\t\treturn nil
+\t}}
+\treject := func(format string, args ...interface{{}}) error {{
+\t\treturn &SignatureError{{Operation: operation, Reason: fmt.Sprintf(format, args...)}}
+\t}}
+\tif v.Keys == nil {{
+\t\treturn reject("SignatureVerifier has no Keys")
+\t}}
+\theader := v.Header
+\tif header == "" {{
+\t\theader = signatureHeader
+\t}}
+\ttimestampHeader := v.TimestampHeader
+\tif timestampHeader == "" {{
+\t\ttimestampHeader = signatureTimestampHeader
+\t}}
+
+\tvalue := resp.Header.Get(header)
+\tif value == "" {{
+\t\tif v.AllowUnsigned {{
+\t\t\treturn nil
+\t\t}}
+\t\treturn reject("missing %s header", header)
+\t}}
+\tencoded, timestamp, keyID := parseSignatureHeader(value)
+\tif timestamp == "" {{
+\t\ttimestamp = resp.Header.Get(timestampHeader)
+\t}}
+\tif err := v.checkTimestamp(timestamp); err != nil {{
+\t\treturn reject("%v", err)
+\t}}
+\tsignature, ok := decodeSignature(encoded)
+\tif !ok {{
+\t\treturn reject("undecodable signature %q", encoded)
+\t}}
+
+\tkey, err := v.Keys.SignatureKey(keyID)
+\tif err != nil {{
+\t\treturn reject("key: %v", err)
+\t}}
+\tpayload := []byte(timestamp + "." + string(body))
+\tif v.Payload != nil {{
+\t\tpayload = v.Payload(timestamp, body)
+\t}}
+\talgorithm := v.Algorithm
+\tif algorithm == "" {{
+\t\talgorithm = SignatureHS256
+\t}}
+\tif err := verifySignature(string(algorithm), key, payload, signature); err != nil {{
+\t\treturn reject("%v", err)
+\t}}
+\treturn nil
+}}
+
+// checkTimestamp rejects timestamps outside MaxSkew of now. Timestamps are
+// Unix seconds or milliseconds, or RFC 3339.
+func (v *SignatureVerifier) checkTimestamp(timestamp string) error {{
+\tif v.MaxSkew < 0 {{
+\t\treturn nil
+\t}}
+\tmaxSkew := v.MaxSkew
+\tif maxSkew == 0 {{
+\t\tmaxSkew = 5 * time.Minute
+\t}}
+\tif timestamp == "" {{
+\t\treturn fmt.Errorf("missing timestamp")
+\t}}
+\tvar signed time.Time
+\tif n, err := strconv.ParseInt(timestamp, 10, 64); err == nil {{
+\t\tsigned = time.Unix(n, 0)
+\t\tif n >= 1e11 {{
+\t\t\tsigned = time.UnixMilli(n)
+\t\t}}
+\t}} else if signed, err = time.Parse(time.RFC3339, timestamp); err != nil {{
+\t\treturn fmt.Errorf("unparseable timestamp %q", timestamp)
+\t}}
+\tif skew := time.Since(signed); skew > maxSkew || skew < -maxSkew {{
+\t\treturn fmt.Errorf("timestamp %s is outside the allowed %s skew", timestamp, maxSkew)
+\t}}
+\treturn nil
+}}
+
+// parseSignatureHeader splits "t=...,v1=...,keyId=..." style values; plain
+// values are returned as the signature, minus any "algorithm=" prefix.
+func parseSignatureHeader(value string) (signature, timestamp, keyID string) {{
+\tif !strings.Contains(value, ",") {{
+\t\t// Base64 padding is the only "=" a bare signature can contain.
+\t\tif _, after, ok := strings.Cut(value, "="); ok && strings.Trim(after, "=") != "" {{
+\t\t\treturn after, "", ""
+\t\t}}
+\t\treturn value, "", ""
+\t}}
+\tfor _, part := range strings.Split(value, ",") {{
+\t\tname, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+\t\tswitch strings.ToLower(name) {{
+\t\tcase "t", "ts", "timestamp":
+\t\t\ttimestamp = val
+\t\tcase "keyid", "kid":
+\t\t\tkeyID = strings.Trim(val, `"`)
+\t\tcase "v1", "s", "sig", "signature", "sha256", "sha512":
+\t\t\tif signature == "" {{
+\t\t\t\tsignature = strings.Trim(val, `"`)
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn signature, timestamp, keyID
+}}
+
+// decodeSignature decodes hex, or standard or URL-safe base64.
+func decodeSignature(encoded string) ([]byte, bool) {{
+\tif b, err := hex.DecodeString(encoded); err == nil {{
+\t\treturn b, true
+\t}}
+\tfor _, encoding := range []*base64.Encoding{{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}} {{
+\t\tif b, err := encoding.DecodeString(encoded); err == nil {{
+\t\t\treturn b, true
+\t\t}}
+\t}}
+\treturn nil, false
+}}
+"""
+    
+    def _generate_go_options(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"net/http"
+\t"net/url"
+\t"time"
+)
+
+// RequestOption customizes a single call, overriding the client's
+// configuration for that call only:
+//
+//\tposts, err := client.ListPosts(ctx, WithRequestLocale("de-DE"))
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {{
+\tctx     context.Context
+\theader  http.Header
+\tedits   []func(*http.Request)
+\tbaseURL string
+
+\t// status is the response status code, recorded for the audit log.
+\tstatus int
+\t// statusOut, if set, receives status too, for callers of doRequest.
+\tstatusOut *int
+\t// meta, if set, receives the response's metadata.
+\tmeta *ResponseMeta
+\t// retry, if set, replaces the client's retry policy.
+\tretry *RetryPolicy
+\t// attempt is the attempt being made, counted from 1.
+\tattempt int
+\t// url is the URL of the last request sent, for instrumentation.
+\turl *url.URL
+\t// idempotencyKey, if set, is sent with every attempt at the call.
+\tidempotencyKey string
+\t// envelope, if set, receives the fields of an enveloped response
+\t// besides its payload.
+\tenvelope interface{{}}
+\t// timeout, if set, limits the call, retries included.
+\ttimeout time.Duration
+\t// query holds query parameters replacing those of the call.
+\tquery url.Values
+\t// dryRun, if set, returns the prepared request in a *DryRunError
+\t// instead of sending it.
+\tdryRun bool
+}}
+
+// ResponseMeta describes the response to a call, beyond its decoded body.
+type ResponseMeta struct {{
+\tOperation  string // "GET /v1/users/{{id}}"
+\tStatusCode int    // 0 when no response arrived
+\tHeader     http.Header
+\tRequestID  string // the server's request ID header, for support requests
+\tDuration   time.Duration
+}}
+
+// requestIDHeaders are the response headers read as the server's request ID.
+var requestIDHeaders = []string{{"X-Request-Id", "Request-Id", "X-Amzn-RequestId", "X-Correlation-Id"}}
+
+// requestID returns the first request ID header set in header.
+func requestID(header http.Header) string {{
+\tfor _, name := range requestIDHeaders {{
+\t\tif id := header.Get(name); id != "" {{
+\t\t\treturn id
+\t\t}}
+\t}}
+\treturn ""
+}}
+
+// record fills m from resp; m may be nil.
+func (m *ResponseMeta) record(operation string, resp *http.Response) {{
+\tif m == nil {{
+\t\treturn
+\t}}
+\tm.Operation = operation
+\tm.StatusCode = resp.StatusCode
+\tm.Header = resp.Header
+\tm.RequestID = requestID(resp.Header)
+}}
+
+// finish records the time since start; m may be nil.
+func (m *ResponseMeta) finish(start time.Time) {{
+\tif m != nil {{
+\t\tm.Duration = time.Since(start)
+\t}}
+}}
+
+// WithHeader sends the header with the call, replacing the client's value
+// for it:
+//
+//\tposts, err := client.ListPosts(ctx, WithHeader("X-Debug", "1"))
+func WithHeader(name, value string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.header.Set(name, value)
+\t}}
+}}
+
+// WithTimeout limits the call, retries included, to d, on top of any
+// deadline of its context. Streams and downloads must be read within d too.
+func WithTimeout(d time.Duration) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.timeout = d
+\t}}
+}}
+
+// WithQueryParam sends the query parameter with the call, replacing any
+// value the method's parameters give it.
+func WithQueryParam(name, value string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\tif o.query == nil {{
+\t\t\to.query = make(url.Values)
+\t\t}}
+\t\to.query.Set(name, value)
+\t}}
+}}
+
+// recordStatus stores the call's response status code in status.
+func recordStatus(status *int) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.statusOut = status
+\t}}
+}}
+
+// recordMeta stores the call's response metadata in meta.
+func recordMeta(meta *ResponseMeta) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.meta = meta
+\t}}
+}}
+
+// newRequestOptions applies the client's default options, then the call's,
+// to a call made with ctx.
+func newRequestOptions(ctx context.Context, defaults, opts []RequestOption) *requestOptions {{
+\to := &requestOptions{{ctx: ctx, header: make(http.Header), attempt: 1}}
+\tfor _, opt := range defaults {{
+\t\topt(o)
+\t}}
+\tfor _, opt := range opts {{
+\t\topt(o)
+\t}}
+\treturn o
+}}
+
+// startTimeout bounds the call's context by its timeout, if any. The call
+// ends with cancel.
+func (o *requestOptions) startTimeout() (cancel context.CancelFunc) {{
+\tif o.timeout <= 0 {{
+\t\treturn func() {{}}
+\t}}
+\to.ctx, cancel = context.WithTimeout(o.ctx, o.timeout)
+\treturn cancel
+}}
+
+// queryParams returns params with the per-call query parameters set.
+func (o *requestOptions) queryParams(params url.Values) url.Values {{
+\tif len(o.query) == 0 {{
+\t\treturn params
+\t}}
+\tmerged := make(url.Values, len(params)+len(o.query))
+\tfor name, values := range params {{
+\t\tmerged[name] = values
+\t}}
+\tfor name, values := range o.query {{
+\t\tmerged[name] = values
+\t}}
+\treturn merged
+}}
+
+// apply sets the per-call headers on req, replacing the client's, then runs
+// the edits in order.
+func (o *requestOptions) apply(req *http.Request) {{
+\tfor name, values := range o.header {{
+\t\treq.Header[name] = values
+\t}}
+\tfor _, edit := range o.edits {{
+\t\tedit(req)
+\t}}
+}}
+"""
+    
+    def _generate_go_audit(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"net/url"
+\t"strings"
+\t"time"
+)
+
+// AuditRecord describes one API call, for access logs of the data pulled
+// through the SDK.
+type AuditRecord struct {{
+\tTime        time.Time         // when the call started
+\tCaller      string            // from ContextWithCaller; empty if unset
+\tOperation   string            // "GET /v1/users/{{id}}"
+\tPath        string            // "/v1/users/42"
+\tResourceIDs map[string]string // path parameters: {{"id": "42"}}
+\tStatusCode  int               // 0 when no response arrived
+\tErr         error             // nil on success
+\tDuration    time.Duration
+}}
+
+// Outcome is "success" or "error".
+func (r AuditRecord) Outcome() string {{
+\tif r.Err != nil {{
+\t\treturn "error"
+\t}}
+\treturn "success"
+}}
+
+// AuditSink receives a record of every API call. Audit runs synchronously
+// after the call, so slow sinks should buffer.
+type AuditSink interface {{
+\tAudit(ctx context.Context, record AuditRecord)
+}}
+
+// AuditSinkFunc adapts a function to AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord)
+
+// Audit implements AuditSink.
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) {{
+\tf(ctx, record)
+}}
+
+// WithAuditSink sends a record of every call to sink.
+func WithAuditSink(sink AuditSink) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.auditSink = sink
+\t}}
+}}
+
+type callerKey struct{{}}
+
+// ContextWithCaller returns a context identifying the caller on whose
+// behalf calls made with it run, for the audit log.
+func ContextWithCaller(ctx context.Context, caller string) context.Context {{
+\treturn context.WithValue(ctx, callerKey{{}}, caller)
+}}
+
+// CallerFromContext returns the caller set with ContextWithCaller.
+func CallerFromContext(ctx context.Context) string {{
+\tcaller, _ := ctx.Value(callerKey{{}}).(string)
+\treturn caller
+}}
+
+// audit sends the record of a finished call to the audit sink. It is
+// deferred by doRequest, and a panicking sink fails the call.
+func (c *{self.class_name}Client) audit(method, endpoint, path string, options *requestOptions, start time.Time, err *error) {{
+\tif c.auditSink == nil {{
+\t\treturn
+\t}}
+\toperation := method + " " + endpoint
+\tdefer recoverPanic(operation, 1, err)
+\tc.auditSink.Audit(options.ctx, AuditRecord{{
+\t\tTime:        start,
+\t\tCaller:      CallerFromContext(options.ctx),
+\t\tOperation:   operation,
+\t\tPath:        path,
+\t\tResourceIDs: pathParams(endpoint, path),
+\t\tStatusCode:  options.status,
+\t\tErr:         *err,
+\t\tDuration:    time.Since(start),
+\t}})
+}}
+
+// pathParams matches path against the endpoint pattern's {{name}} segments.
+func pathParams(pattern, path string) map[string]string {{
+\tpatternSegments := strings.Split(pattern, "/")
+\tpathSegments := strings.Split(path, "/")
+\tif len(patternSegments) != len(pathSegments) {{
+\t\treturn nil
+\t}}
+\tvar params map[string]string
+\tfor i, segment := range patternSegments {{
+\t\tif strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}") {{
+\t\t\tif params == nil {{
+\t\t\t\tparams = make(map[string]string)
+\t\t\t}}
+\t\t\tvalue, err := url.PathUnescape(pathSegments[i])
+\t\t\tif err != nil {{
+\t\t\t\tvalue = pathSegments[i]
+\t\t\t}}
+\t\t\tparams[segment[1:len(segment)-1]] = value
+\t\t}}
+\t}}
+\treturn params
+}}
+"""
+    
+    RATE_LIMIT_HEADERS = {
+        'limit': ['X-RateLimit-Limit', 'RateLimit-Limit', 'X-Rate-Limit-Limit'],
+        'remaining': ['X-RateLimit-Remaining', 'RateLimit-Remaining', 'X-Rate-Limit-Remaining'],
+        'reset': ['X-RateLimit-Reset', 'RateLimit-Reset', 'X-Rate-Limit-Reset'],
+    }
+    
+    def _observed_rate_limit_headers(self) -> Dict[str, List[str]]:
+        """The rate-limit limit, remaining, and reset headers to read: those
+        the captured responses carried, then the common ones."""
+        import re
+        headers = {kind: [] for kind in self.RATE_LIMIT_HEADERS}
+        for endpoint in self.endpoints.values():
+            for example in endpoint.examples:
+                for name in (example.get('response', {}).get('headers') or {}):
+                    match = re.search(r'rate[-_]?limit.*[-_](limit|remaining|reset)$', name.lower())
+                    if match and all(name.lower() != seen.lower() for seen in headers[match.group(1)]):
+                        headers[match.group(1)].append(name)
+        for kind, common in self.RATE_LIMIT_HEADERS.items():
+            seen = {name.lower() for name in headers[kind]}
+            headers[kind] += [name for name in common if name.lower() not in seen]
+        return headers
+    
+    def _generate_go_quota(self, package_name: str) -> str:
+        rate_limit_headers = {kind: ', '.join(f'"{name}"' for name in names)
+                              for kind, names in self._observed_rate_limit_headers().items()}
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"net/http"
+\t"strconv"
+\t"sync"
+\t"time"
+)
+
+// Quota is the API's rate-limit quota as last reported by response headers
+// (X-RateLimit-*, RateLimit-*, or Retry-After on a 429), less what
+// WaitForQuota has reserved since.
+type Quota struct {{
+\tLimit     int       // requests per window; zero if not reported
+\tRemaining int       // requests left in the current window
+\tReset     time.Time // when the window resets; zero if not reported
+\tUpdated   time.Time // when a response last reported the quota
+}}
+
+// Known reports whether any response has reported the quota yet.
+func (q Quota) Known() bool {{
+\treturn !q.Updated.IsZero()
+}}
+
+// quotaTracker is the quota model shared by a client and the clients
+// derived from it.
+type quotaTracker struct {{
+\tmu    sync.Mutex
+\tquota Quota
+}}
+
+// The quota headers; those seen in the captured traffic come first.
+var (
+\tquotaLimitHeaders     = []string{{{rate_limit_headers['limit']}}}
+\tquotaRemainingHeaders = []string{{{rate_limit_headers['remaining']}}}
+\tquotaResetHeaders     = []string{{{rate_limit_headers['reset']}}}
+)
+
+// update records the quota reported by resp, if any.
+func (t *quotaTracker) update(resp *http.Response) {{
+\tlimit, hasLimit := headerInt(resp.Header, quotaLimitHeaders)
+\tremaining, hasRemaining := headerInt(resp.Header, quotaRemainingHeaders)
+\treset, hasReset := headerInt(resp.Header, quotaResetHeaders)
+\tnow := time.Now()
+\tvar resetAt time.Time
+\tif hasReset {{
+\t\tresetAt = resetTime(reset, now)
+\t}}
+\tif resp.StatusCode == http.StatusTooManyRequests {{
+\t\tif retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now); !retryAfter.IsZero() {{
+\t\t\tremaining, hasRemaining = 0, true
+\t\t\tresetAt, hasReset = retryAfter, true
+\t\t}}
+\t}}
+\tif !hasLimit && !hasRemaining && !hasReset {{
+\t\treturn
+\t}}
+
+\tt.mu.Lock()
+\tdefer t.mu.Unlock()
+\tif hasLimit {{
+\t\tt.quota.Limit = int(limit)
+\t}}
+\tif hasRemaining {{
+\t\tt.quota.Remaining = int(remaining)
+\t}}
+\tif hasReset {{
+\t\tt.quota.Reset = resetAt
+\t}}
+\tt.quota.Updated = now
+}}
+
+// reserve takes n requests from the quota, or returns how long to wait
+// before trying again.
+func (t *quotaTracker) reserve(n int) (time.Duration, bool) {{
+\tt.mu.Lock()
+\tdefer t.mu.Unlock()
+\tq := &t.quota
+\tif !q.Known() {{
+\t\treturn 0, true
+\t}}
+\tif q.Remaining < n && !q.Reset.IsZero() && !time.Now().Before(q.Reset) {{
+\t\t// The window has reset; assume a full quota until a response says
+\t\t// otherwise.
+\t\tif q.Limit == 0 {{
+\t\t\treturn 0, true
+\t\t}}
+\t\tq.Remaining, q.Reset = q.Limit, time.Time{{}}
+\t}}
+\tif q.Remaining >= n {{
+\t\tq.Remaining -= n
+\t\treturn 0, true
+\t}}
+\tif q.Reset.IsZero() {{
+\t\t// Without a reset time there is nothing to wait for.
+\t\treturn 0, true
+\t}}
+\treturn time.Until(q.Reset), false
+}}
+
+// Quota returns the rate-limit quota as last reported by the API.
+func (c *{self.class_name}Client) Quota() Quota {{
+\tc.quota.mu.Lock()
+\tdefer c.quota.mu.Unlock()
+\treturn c.quota.quota
+}}
+
+// WaitForQuota blocks until the quota allows n more requests, then reserves
+// them, so batch jobs can pace themselves instead of running into 429s. It
+// returns at once while the API has not reported a quota.
+func (c *{self.class_name}Client) WaitForQuota(ctx context.Context, n int) error {{
+\tif q := c.Quota(); q.Limit > 0 && n > q.Limit {{
+\t\treturn fmt.Errorf("wait for quota: %d requests exceed the limit of %d", n, q.Limit)
+\t}}
+\tfor {{
+\t\twait, ok := c.quota.reserve(n)
+\t\tif ok {{
+\t\t\treturn nil
+\t\t}}
+\t\ttimer := time.NewTimer(wait)
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\ttimer.Stop()
+\t\t\treturn ctx.Err()
+\t\tcase <-timer.C:
+\t\t}}
+\t}}
+}}
+
+// headerInt returns the first of names present in header as an integer.
+func headerInt(header http.Header, names []string) (int64, bool) {{
+\tfor _, name := range names {{
+\t\tif value := header.Get(name); value != "" {{
+\t\t\tn, err := strconv.ParseFloat(value, 64)
+\t\t\treturn int64(n), err == nil
+\t\t}}
+\t}}
+\treturn 0, false
+}}
+
+// resetTime interprets a reset header: Unix milliseconds or seconds for
+// large values, otherwise seconds from now.
+func resetTime(reset int64, now time.Time) time.Time {{
+\tswitch {{
+\tcase reset >= 1e12:
+\t\treturn time.UnixMilli(reset)
+\tcase reset >= 1e9:
+\t\treturn time.Unix(reset, 0)
+\tdefault:
+\t\treturn now.Add(time.Duration(reset) * time.Second)
+\t}}
+}}
+
+// parseRetryAfter parses Retry-After seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Time {{
+\tif value == "" {{
+\t\treturn time.Time{{}}
+\t}}
+\tif seconds, err := strconv.Atoi(value); err == nil {{
+\t\treturn now.Add(time.Duration(seconds) * time.Second)
+\t}}
+\tif t, err := http.ParseTime(value); err == nil {{
+\t\treturn t
+\t}}
+\treturn time.Time{{}}
+}}
+"""
+    
+    def _generate_go_endpoints(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"errors"
+\t"fmt"
+\t"sort"
+\t"sync"
+)
+
+// ErrEndpointDisabled is returned (wrapped) by calls to an operation the
+// client's EndpointRegistry has disabled.
+var ErrEndpointDisabled = errors.New("endpoint disabled")
+
+// EndpointRegistry records which operations ("GET /v1/users/{{id}}") a client
+// may call. Everything is enabled until disabled, from configuration or from
+// discovering what a particular deployment serves. It is safe to change
+// while calls are in flight; the zero value enables every operation.
+type EndpointRegistry struct {{
+\tmu       sync.RWMutex
+\tdisabled map[string]bool
+\tonly     map[string]bool // non-nil after EnableOnly
+}}
+
+// Disable makes calls to the given operations fail with ErrEndpointDisabled.
+func (r *EndpointRegistry) Disable(operations ...string) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tif r.disabled == nil {{
+\t\tr.disabled = make(map[string]bool)
+\t}}
+\tfor _, operation := range operations {{
+\t\tr.disabled[operation] = true
+\t}}
+}}
+
+// Enable re-enables the given operations.
+func (r *EndpointRegistry) Enable(operations ...string) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tfor _, operation := range operations {{
+\t\tdelete(r.disabled, operation)
+\t\tif r.only != nil {{
+\t\t\tr.only[operation] = true
+\t\t}}
+\t}}
+}}
+
+// EnableOnly disables every operation but the given ones, e.g. those a
+// deployment advertises. It replaces any earlier EnableOnly and clears
+// Disable.
+func (r *EndpointRegistry) EnableOnly(operations ...string) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tr.disabled = nil
+\tr.only = make(map[string]bool, len(operations))
+\tfor _, operation := range operations {{
+\t\tr.only[operation] = true
+\t}}
+}}
+
+// Reset enables every operation again.
+func (r *EndpointRegistry) Reset() {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tr.disabled, r.only = nil, nil
+}}
+
+// Enabled reports whether operation may be called.
+func (r *EndpointRegistry) Enabled(operation string) bool {{
+\tr.mu.RLock()
+\tdefer r.mu.RUnlock()
+\tif r.disabled[operation] {{
+\t\treturn false
+\t}}
+\treturn r.only == nil || r.only[operation]
+}}
+
+// Disabled returns the explicitly disabled operations, sorted.
+func (r *EndpointRegistry) Disabled() []string {{
+\tr.mu.RLock()
+\tdefer r.mu.RUnlock()
+\toperations := make([]string, 0, len(r.disabled))
+\tfor operation := range r.disabled {{
+\t\toperations = append(operations, operation)
+\t}}
+\tsort.Strings(operations)
+\treturn operations
+}}
+
+// WithEndpointRegistry gates the client's calls on r, so several clients
+// can share one set of runtime toggles.
+func WithEndpointRegistry(r *EndpointRegistry) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.endpoints = r
+\t}}
+}}
+
+// WithDisabledEndpoints disables the given operations on the client's
+// registry.
+func WithDisabledEndpoints(operations ...string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.endpoints.Disable(operations...)
+\t}}
+}}
+
+// Endpoints returns the registry gating the client's calls. Derived clients
+// share it.
+func (c *{self.class_name}Client) Endpoints() *EndpointRegistry {{
+\treturn c.endpoints
+}}
+
+// checkEndpoint fails calls to disabled operations.
+func (c *{self.class_name}Client) checkEndpoint(operation string) error {{
+\tif !c.endpoints.Enabled(operation) {{
+\t\treturn fmt.Errorf("%s: %w", operation, ErrEndpointDisabled)
+\t}}
+\treturn nil
+}}
+"""
+    
+    def _generate_go_versions(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"errors"
+\t"net/http"
+\t"net/url"
+)
+
+// VersionReport describes which version of a multi-version operation served
+// a call made through its facade.
+type VersionReport struct {{
+\tOperation string // the operation that served the call, "GET /v1/users"
+\tVersion   string // its version, "v1"
+\tPreferred string // the newest version, tried first
+\t// FellBack lists the versions that answered 404 or 410, or were
+\t// disabled, before Version; newest first.
+\tFellBack []string
+\tErr      error // the call's error, if no version served it
+}}
+
+// VersionHook receives a VersionReport after every facade call.
+type VersionHook func(ctx context.Context, report VersionReport)
+
+// WithVersionHook reports which version served each facade call, to track
+// how far an upstream migration has progressed.
+func WithVersionHook(hook VersionHook) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.versionHook = hook
+\t}}
+}}
+
+// endpointVersion is one version of an operation, newest first in a
+// facade's list.
+type endpointVersion struct {{
+\tversion  string
+\tendpoint string
+\tpath     string
+}}
+
+// doVersions calls the newest version of an operation, falling back to the
+// next when it answers 404 or 410 or is disabled. It returns the serving
+// operation for decoding; if every version fails, the newest's error is
+// returned.
+func (c *{self.class_name}Client) doVersions(ctx context.Context, method string, versions []endpointVersion, params url.Values, body interface{{}}, opts ...RequestOption) ([]byte, string, error) {{
+\treport := VersionReport{{Preferred: versions[0].version}}
+\tvar firstErr error
+\tfor _, v := range versions {{
+\t\tvar status int
+\t\tresponseBody, err := c.doRequest(ctx, method, v.endpoint, v.path, params, body, append(opts[:len(opts):len(opts)], recordStatus(&status))...)
+\t\tif err == nil || !fallsBack(status, err) {{
+\t\t\treport.Operation, report.Version, report.Err = method+" "+v.endpoint, v.version, err
+\t\t\tc.reportVersion(ctx, opts, report)
+\t\t\treturn responseBody, report.Operation, err
+\t\t}}
+\t\tif firstErr == nil {{
+\t\t\tfirstErr = err
+\t\t}}
+\t\treport.FellBack = append(report.FellBack, v.version)
+\t}}
+\treport.Err = firstErr
+\tc.reportVersion(ctx, opts, report)
+\treturn nil, "", firstErr
+}}
+
+// fallsBack reports whether a failed call should be retried on an older
+// version.
+func fallsBack(status int, err error) bool {{
+\treturn status == http.StatusNotFound || status == http.StatusGone || errors.Is(err, ErrEndpointDisabled)
+}}
+
+func (c *{self.class_name}Client) reportVersion(ctx context.Context, opts []RequestOption, report VersionReport) {{
+\tif c.versionHook != nil {{
+\t\tc.versionHook(newRequestOptions(ctx, c.defaults, opts).ctx, report)
+\t}}
+}}
+"""
+    
+    BATCH_METHOD_FIELDS = ('method', 'httpMethod', 'http_method', 'verb')
+    BATCH_URL_FIELDS = ('url', 'relative_url', 'relativeUrl', 'path', 'uri')
+    BATCH_ID_FIELDS = ('id', 'requestId', 'request_id')
+    BATCH_BODY_FIELDS = ('body', 'payload', 'data')
+    BATCH_STATUS_FIELDS = ('status', 'code', 'status_code', 'statusCode')
+    
+    def _example_json(self, body) -> Any:
+        """A captured body as JSON data, or None."""
+        import json
+        if isinstance(body, (dict, list)):
+            return body
+        try:
+            return json.loads(body) if isinstance(body, str) and body else None
+        except json.JSONDecodeError:
+            return None
+    
+    def _object_array(self, data) -> Optional[Tuple[str, List[Dict[str, Any]]]]:
+        """The envelope's object array and the field holding it ('' for a bare array)."""
+        if isinstance(data, list):
+            candidates = [('', data)]
+        elif isinstance(data, dict):
+            candidates = list(data.items())
+        else:
+            return None
+        for key, value in candidates:
+            if isinstance(value, list) and value and all(isinstance(item, dict) for item in value):
+                return key, value
+        return None
+    
+    def _common_field(self, items: List[Dict[str, Any]], names) -> str:
+        """The first of names every item has, or ''."""
+        return next((name for name in names if all(name in item for item in items)), '')
+    
+    def _detect_batch_endpoint(self) -> Optional[Dict[str, Any]]:
+        """The first POST endpoint whose captured body is an array of
+        sub-requests (method and URL fields) answered by an array of results
+        with status fields, with the envelope's field names, or None."""
+        load, array, common = self._example_json, self._object_array, self._common_field
+        for endpoint in self.endpoints.values():
+            if endpoint.method != 'POST':
+                continue
+            for example in endpoint.examples:
+                requests = array(load(example.get('request', {}).get('body')))
+                results = array(load(example.get('response', {}).get('body')))
+                if not requests or not results:
+                    continue
+                (requests_field, items), (responses_field, answers) = requests, results
+                method, url = common(items, self.BATCH_METHOD_FIELDS), common(items, self.BATCH_URL_FIELDS)
+                status = common(answers, self.BATCH_STATUS_FIELDS)
+                if not method or not url or not status:
+                    continue
+                urls = [str(item[url]) for item in items]
+                directory = endpoint.path_pattern.rsplit('/', 1)[0]
+                nested = any(u.lstrip('/').startswith(directory.lstrip('/') + '/') for u in urls)
+                id_field = common(items, self.BATCH_ID_FIELDS)
+                return {
+                    'endpoint': endpoint.path_pattern,
+                    'requests': requests_field,
+                    'method': method,
+                    'url': url,
+                    'id': id_field if id_field and all(id_field in answer for answer in answers) else '',
+                    'body': next((name for name in self.BATCH_BODY_FIELDS if any(name in item for item in items)), 'body'),
+                    'responses': responses_field,
+                    'status': status,
+                    'result': next((name for name in self.BATCH_BODY_FIELDS if any(name in answer for answer in answers)), 'body'),
+                    'prefix': directory if directory and not nested else '',
+                    'relative': all(not u.startswith('/') for u in urls),
+                }
+        return None
+    
+    def _generate_go_batch(self, package_name: str, batch: Dict[str, Any]) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"net/url"
+\t"strconv"
+\t"strings"
+)
+
+// The captured batch endpoint and the JSON fields of its envelope. Empty
+// requests and responses fields mean the envelope is a bare array; an empty
+// id field means results are matched to calls by position.
+const (
+\tbatchEndpoint       = "{batch['endpoint']}"
+\tbatchRequestsField  = "{batch['requests']}"
+\tbatchMethodField    = "{batch['method']}"
+\tbatchURLField       = "{batch['url']}"
+\tbatchIDField        = "{batch['id']}"
+\tbatchBodyField      = "{batch['body']}"
+\tbatchResponsesField = "{batch['responses']}"
+\tbatchStatusField    = "{batch['status']}"
+\tbatchResultField    = "{batch['result']}"
+\t// batchPathPrefix is stripped from sub-request paths, which the captured
+\t// API resolves relative to it.
+\tbatchPathPrefix = "{batch['prefix']}"
+\t// batchRelativeURLs drops the leading slash from sub-request paths.
+\tbatchRelativeURLs = {'true' if batch['relative'] else 'false'}
+)
+
+// ErrBatchNotSent is returned by BatchCall.Result before the batch is sent.
+var ErrBatchNotSent = errors.New("batch not sent")
+
+// Batch packs several calls into one request to the batch endpoint. Add
+// calls with its operation methods, Send it, then read each call's Result:
+//
+//\tbatch := client.NewBatch()
+//\tuser := batch.GetUser("42")
+//\tposts := batch.ListPosts()
+//\tif err := batch.Send(ctx); err != nil {{
+//\t\treturn err
+//\t}}
+//\tu, err := user.Result()
+type Batch struct {{
+\tclient *{self.class_name}Client
+\tcalls  []*batchCall
+}}
+
+// batchCall is one sub-request of a Batch and, once sent, its outcome.
+type batchCall struct {{
+\tmethod   string
+\tendpoint string
+\tpath     string
+\tparams   url.Values
+\tbody     interface{{}}
+
+\tsent   bool
+\tstatus int
+\tresult json.RawMessage
+\terr    error
+}}
+
+// BatchCall is a call added to a Batch. T is the type the corresponding
+// client method returns.
+type BatchCall[T any] struct {{
+\tclient *{self.class_name}Client
+\tcall   *batchCall
+}}
+
+// NewBatch starts an empty batch.
+func (c *{self.class_name}Client) NewBatch() *Batch {{
+\treturn &Batch{{client: c}}
+}}
+
+// Len returns the number of calls in the batch.
+func (b *Batch) Len() int {{
+\treturn len(b.calls)
+}}
+
+// Result returns the call's decoded result, or its error: ErrBatchNotSent,
+// ErrEndpointDisabled, or the API error for this call alone.
+func (bc *BatchCall[T]) Result() (T, error) {{
+\tvar result T
+\tif !bc.call.sent {{
+\t\treturn result, ErrBatchNotSent
+\t}}
+\tif bc.call.err != nil {{
+\t\treturn result, bc.call.err
+\t}}
+\terr := bc.client.decode(bc.call.method+" "+bc.call.endpoint, bc.call.result, &result)
+\treturn result, err
+}}
+
+// StatusCode returns the call's own status code once the batch is sent.
+func (bc *BatchCall[T]) StatusCode() int {{
+\treturn bc.call.status
+}}
+
+// addBatchCall appends a call to b.
+func addBatchCall[T any](b *Batch, method, endpoint, path string, params url.Values, body interface{{}}) *BatchCall[T] {{
+\tcall := &batchCall{{method: method, endpoint: endpoint, path: path, params: params, body: body}}
+\tb.calls = append(b.calls, call)
+\treturn &BatchCall[T]{{client: b.client, call: call}}
+}}
+
+// Send sends the batch's calls in one request and distributes the results.
+// It returns an error only when the batch request itself fails; per-call
+// failures are reported by each call's Result. Calls to disabled endpoints
+// are left out of the request.
+func (b *Batch) Send(ctx context.Context, opts ...RequestOption) error {{
+\tvar items []map[string]interface{{}}
+\tvar included []*batchCall
+\tfor _, call := range b.calls {{
+\t\tcall.sent, call.status, call.result, call.err = true, 0, nil, nil
+\t\tif err := b.client.checkEndpoint(call.method + " " + call.endpoint); err != nil {{
+\t\t\tcall.err = err
+\t\t\tcontinue
+\t\t}}
+\t\tif request, ok := call.body.(interface{{ Validate() error }}); ok {{
+\t\t\tif err := b.client.validate(request); err != nil {{
+\t\t\t\tcall.err = err
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t}}
+\t\titem := map[string]interface{{}}{{
+\t\t\tbatchMethodField: call.method,
+\t\t\tbatchURLField:    batchURL(call.path, call.params),
+\t\t}}
+\t\tif batchIDField != "" {{
+\t\t\titem[batchIDField] = strconv.Itoa(len(included))
+\t\t}}
+\t\tif call.body != nil {{
+\t\t\titem[batchBodyField] = b.client.withTimeLayouts(call.body)
+\t\t}}
+\t\titems = append(items, item)
+\t\tincluded = append(included, call)
+\t}}
+\tif len(included) == 0 {{
+\t\treturn nil
+\t}}
+
+\tvar envelope interface{{}} = items
+\tif batchRequestsField != "" {{
+\t\tenvelope = map[string]interface{{}}{{batchRequestsField: items}}
+\t}}
+\tresponseBody, err := b.client.doRequest(ctx, "POST", batchEndpoint, batchEndpoint, nil, envelope, opts...)
+\tif err != nil {{
+\t\tfor _, call := range included {{
+\t\t\tcall.err = err
+\t\t}}
+\t\treturn err
+\t}}
+\tresults, err := batchResults(responseBody)
+\tif err != nil {{
+\t\treturn fmt.Errorf("batch: %w", err)
+\t}}
+
+\tanswered := make([]bool, len(included))
+\tfor position, result := range results {{
+\t\tindex := position
+\t\tif batchIDField != "" {{
+\t\t\tvar id string
+\t\t\tif unquoteJSON(result[batchIDField], &id) != nil {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tif index, err = strconv.Atoi(id); err != nil {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t}}
+\t\tif index < 0 || index >= len(included) {{
+\t\t\tcontinue
+\t\t}}
+\t\tcall := included[index]
+\t\tanswered[index] = true
+\t\tjson.Unmarshal(result[batchStatusField], &call.status)
+\t\tcall.result = result[batchResultField]
+\t\tvar text string
+\t\tif json.Unmarshal(call.result, &text) == nil {{
+\t\t\t// Some APIs return each body as a JSON-encoded string.
+\t\t\tcall.result = json.RawMessage(text)
+\t\t}}
+\t\tif call.status >= 400 {{
+\t\t\tcall.err = newAPIError(call.method+" "+call.endpoint, call.status, nil, call.result, b.client.errorMessages, b.client.Headers["Accept-Language"])
+\t\t}}
+\t}}
+\tfor index, call := range included {{
+\t\tif !answered[index] {{
+\t\t\tcall.err = fmt.Errorf("batch: no result for %s %s", call.method, call.path)
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+// batchURL is the sub-request URL for path and params.
+func batchURL(path string, params url.Values) string {{
+\tpath = strings.TrimPrefix(path, batchPathPrefix)
+\tif batchRelativeURLs {{
+\t\tpath = strings.TrimPrefix(path, "/")
+\t}}
+\tif len(params) > 0 {{
+\t\tpath += "?" + params.Encode()
+\t}}
+\treturn path
+}}
+
+// batchResults extracts the per-call results from a batch response.
+func batchResults(body []byte) ([]map[string]json.RawMessage, error) {{
+\tif batchResponsesField != "" {{
+\t\tvar envelope map[string]json.RawMessage
+\t\tif err := json.Unmarshal(body, &envelope); err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\tbody = envelope[batchResponsesField]
+\t}}
+\tvar results []map[string]json.RawMessage
+\terr := json.Unmarshal(body, &results)
+\treturn results, err
+}}
+
+// unquoteJSON decodes a JSON string or number into s.
+func unquoteJSON(raw json.RawMessage, s *string) error {{
+\tif err := json.Unmarshal(raw, s); err == nil {{
+\t\treturn nil
+\t}}
+\tvar n json.Number
+\tif err := json.Unmarshal(raw, &n); err != nil {{
+\t\treturn err
+\t}}
+\t*s = n.String()
+\treturn nil
+}}
+""" + self._generate_go_batch_methods(batch['endpoint'])
+    
+    def _generate_go_batch_methods(self, batch_endpoint: str) -> str:
+        """A Batch method per operation, taking the client method's arguments
+        and returning a typed BatchCall."""
+        import re
+        methods = []
+        for endpoint in self.endpoints.values():
+            if not self._go_json_call(endpoint) or endpoint.method == 'POST' and endpoint.path_pattern == batch_endpoint:
+                continue
+            name = self._go_name(endpoint)
+            lines = self._generate_go_method(name, endpoint)
+            params, response_type = re.match(r'func \(c \*\w+\) \w+\((.*)\) \((.*), error\) \{$', lines[1]).groups()
+            params = re.sub(r'^ctx context\.Context,? ?|,? ?opts \.\.\.RequestOption$', '', params)
+            call = next(i for i, line in enumerate(lines) if 'c.doRequest(' in line)
+            params_arg, body_arg = re.search(r'path, (\w+), (\w+), opts\.\.\.\)', lines[call]).groups()
+            # Send validates request bodies, failing only the call at fault.
+            body = [line if line.strip() else '' for line in lines[2:call]]
+            if body and body[-1] == '' and 'c.validate(data)' in '\n'.join(body):
+                start = next(i for i, line in enumerate(body) if 'c.validate(data)' in line)
+                end = body.index('\t}', start)
+                del body[start:end + 1]
+            while body and not body[-1]:
+                body.pop()
+            methods.append('\n'.join([
+                f"// {name} adds {endpoint.method} {endpoint.path_pattern} to the batch.",
+                f"func (b *Batch) {name}({params}) *BatchCall[{response_type}] {{",
+                *body,
+                f"\treturn addBatchCall[{response_type}](b, \"{endpoint.method}\", \"{endpoint.path_pattern}\", path, {params_arg}, {body_arg})",
+                "}",
+            ]))
+        return '\n' + '\n\n'.join(methods) + '\n'
+    
+    def _go_bulk_operations(self) -> List[Dict[str, str]]:
+        """The methods worth calling for many items at once: JSON calls taking
+        a request body or a single path parameter and nothing else, each with
+        the name of its bulk helper, the helper's item parameter and type, and
+        the type of each result. Versioned methods are left to their facade."""
+        import re
+        endpoints = {self._go_name(endpoint): endpoint for endpoint in self.endpoints.values()}
+        for key, versions in self._version_groups().items():
+            endpoints[self._go_operation_names()[key]] = versions[0][1]
+        signatures = self._go_client_signatures()
+        taken = {name for _, name, _, _ in signatures}
+        operations = []
+        for _, name, params, results in signatures:
+            endpoint = endpoints.get(name)
+            if not endpoint or not self._go_json_call(endpoint) or len(params) != 3:
+                continue
+            if self.result_types:
+                result = re.fullmatch(r'Result\[(.+)\]', results).group(1)
+            else:
+                result = re.fullmatch(r'\((.+), error\)', results).group(1)
+            arg, arg_type = params[1].split(' ', 1)
+            if arg == 'params' or arg_type.endswith('io.Reader'):
+                continue
+            if arg == 'data' and arg_type.startswith('*'):
+                item, item_type, call = 'requests', arg_type[1:], '&data'
+            else:
+                item, item_type, call = 'bodies' if arg == 'data' else self._go_plural(arg), arg_type, arg
+            bulk = self._go_plural(name) + 'Batch'
+            if bulk in taken:
+                continue
+            operations.append({'name': name, 'bulk': bulk, 'items': item, 'arg': arg, 'call': call,
+                               'type': item_type, 'result': result, 'operation': f"{endpoint.method} {endpoint.path_pattern}"})
+        return operations
+    
+    def _go_plural(self, name: str) -> str:
+        """The English plural of the last word of a Go identifier, as
+        CreateUsers for CreateUser or ids for id. Names ending in a number
+        are left alone."""
+        import re
+        if name[-1:].isdigit():
+            return name
+        if re.search(r'(s|x|z|ch|sh)$', name):
+            return name + 'es'
+        if re.search(r'[^aeiouAEIOU]y$', name):
+            return name[:-1] + 'ies'
+        return name + 's'
+    
+    def _generate_go_bulk(self, package_name: str, operations: List[Dict[str, str]]) -> str:
+        import textwrap
+        methods = []
+        for op in operations:
+            call = f"\t\treturn c.{op['name']}(ctx, {op['call']}, opts...)" + ('.Get()' if self.result_types else '')
+            doc = (f"{op['bulk']} calls {op['name']} ({op['operation']}) for each of {op['items']}, "
+                   f"a few at a time, returning their results in the same order.")
+            methods.append('\n'.join([
+                *('// ' + line for line in textwrap.wrap(doc, 74)),
+                f"func (c *{self.class_name}Client) {op['bulk']}(ctx context.Context, {op['items']} []{op['type']}, opts ...BulkOption) ([]BulkResult[{op['result']}], error) {{",
+                f"\treturn runBulk(ctx, {op['items']}, opts, func(ctx context.Context, {op['arg']} {op['type']}, opts []RequestOption) ({op['result']}, error) {{",
+                call,
+                "\t})",
+                "}",
+            ]))
+        example = next((op for op in operations if op['items'] == 'requests'), operations[0])
+        encoding_json = '\n\t"encoding/json"' if any('json.RawMessage' in op['result'] for op in operations) else ''
+        return f"""package {package_name}
+
+import (
+\t"context"{encoding_json}
+\t"errors"
+\t"fmt"
+\t"sort"
+\t"sync"
+\t"sync/atomic"
+)
+
+// DefaultConcurrency is the number of calls a bulk helper makes at once
+// unless Concurrency says otherwise.
+const DefaultConcurrency = 4
+
+// ErrBulkSkipped is the error of the items a bulk helper run with
+// StopOnError did not call because an earlier call failed.
+var ErrBulkSkipped = errors.New("skipped after an earlier call failed")
+
+// BulkOption adjusts a call to a bulk helper such as {example['bulk']}.
+type BulkOption func(*bulkOptions)
+
+type bulkOptions struct {{
+\tconcurrency    int
+\tstopOnError    bool
+\trequestOptions []RequestOption
+}}
+
+// Concurrency sets how many calls a bulk helper makes at once. Every call
+// still waits for the client's rate limiter and circuit breaker, so a high
+// value cannot push the client past its configured rate.
+func Concurrency(n int) BulkOption {{
+\treturn func(o *bulkOptions) {{
+\t\tif n < 1 {{
+\t\t\tn = 1
+\t\t}}
+\t\to.concurrency = n
+\t}}
+}}
+
+// StopOnError stops a bulk helper from starting calls once one has failed.
+// Calls already under way finish; the rest fail with ErrBulkSkipped.
+func StopOnError() BulkOption {{
+\treturn func(o *bulkOptions) {{
+\t\to.stopOnError = true
+\t}}
+}}
+
+// WithBulkRequestOptions applies opts to every call a bulk helper makes.
+func WithBulkRequestOptions(opts ...RequestOption) BulkOption {{
+\treturn func(o *bulkOptions) {{
+\t\to.requestOptions = append(o.requestOptions, opts...)
+\t}}
+}}
+
+// BulkResult is the outcome of one item of a bulk call.
+type BulkResult[T any] struct {{
+\tIndex int // the item's position in the slice passed in
+\tValue T
+\tErr   error
+}}
+
+// BulkError is the error of a bulk call some of whose items failed. The
+// results still hold the value of every item that succeeded:
+//
+//\tresults, err := client.{example['bulk']}(ctx, {example['items']}, Concurrency(5))
+//\tvar bulkErr *BulkError
+//\tif errors.As(err, &bulkErr) {{
+//\t\tfor index, err := range bulkErr.Errors {{
+//\t\t\tlog.Printf("item %d: %v", index, err)
+//\t\t}}
+//\t}}
+type BulkError struct {{
+\tTotal  int           // the number of items
+\tErrors map[int]error // by the index of the failed item
+}}
+
+func (e *BulkError) Error() string {{
+\tfirst := e.indexes()[0]
+\treturn fmt.Sprintf("%d of %d calls failed; item %d: %v", len(e.Errors), e.Total, first, e.Errors[first])
+}}
+
+// Unwrap returns the items' errors in item order, so errors.Is and
+// errors.As find, say, an *APIError among them.
+func (e *BulkError) Unwrap() []error {{
+\terrs := make([]error, 0, len(e.Errors))
+\tfor _, index := range e.indexes() {{
+\t\terrs = append(errs, e.Errors[index])
+\t}}
+\treturn errs
+}}
+
+func (e *BulkError) indexes() []int {{
+\tindexes := make([]int, 0, len(e.Errors))
+\tfor index := range e.Errors {{
+\t\tindexes = append(indexes, index)
+\t}}
+\tsort.Ints(indexes)
+\treturn indexes
+}}
+
+// runBulk calls call for every item from a pool of workers and collects the
+// results in item order. The error is a *BulkError if any item failed.
+// Items not yet started when ctx is done fail with its error.
+func runBulk[T, R any](ctx context.Context, items []T, opts []BulkOption, call func(context.Context, T, []RequestOption) (R, error)) ([]BulkResult[R], error) {{
+\to := bulkOptions{{concurrency: DefaultConcurrency}}
+\tfor _, opt := range opts {{
+\t\topt(&o)
+\t}}
+\tresults := make([]BulkResult[R], len(items))
+\tindexes := make(chan int)
+\tvar failed atomic.Bool
+\tvar wg sync.WaitGroup
+\tfor worker := 0; worker < o.concurrency && worker < len(items); worker++ {{
+\t\twg.Add(1)
+\t\tgo func() {{
+\t\t\tdefer wg.Done()
+\t\t\tfor i := range indexes {{
+\t\t\t\tresult := &results[i]
+\t\t\t\tresult.Index = i
+\t\t\t\tif err := ctx.Err(); err != nil {{
+\t\t\t\t\tresult.Err = err
+\t\t\t\t\tcontinue
+\t\t\t\t}}
+\t\t\t\tif o.stopOnError && failed.Load() {{
+\t\t\t\t\tresult.Err = ErrBulkSkipped
+\t\t\t\t\tcontinue
+\t\t\t\t}}
+\t\t\t\tresult.Value, result.Err = call(ctx, items[i], o.requestOptions)
+\t\t\t\tif result.Err != nil {{
+\t\t\t\t\tfailed.Store(true)
+\t\t\t\t}}
+\t\t\t}}
+\t\t}}()
+\t}}
+\tfor i := range items {{
+\t\tindexes <- i
+\t}}
+\tclose(indexes)
+\twg.Wait()
+
+\tbulkErr := &BulkError{{Total: len(items), Errors: make(map[int]error)}}
+\tfor _, result := range results {{
+\t\tif result.Err != nil {{
+\t\t\tbulkErr.Errors[result.Index] = result.Err
+\t\t}}
+\t}}
+\tif len(bulkErr.Errors) > 0 {{
+\t\treturn results, bulkErr
+\t}}
+\treturn results, nil
+}}
+
+""" + '\n\n'.join(methods) + '\n'
+    
+    def _go_webhook_events(self) -> List[Dict[str, Any]]:
+        """The captured webhook event types, each with its payload struct and
+        the name of its handler method, OnUserCreated for user.created. Struct
+        names already taken by the client's types get a Webhook prefix."""
+        taken = set(self.nested_structs) | set(self._go_struct_names().values())
+        events = []
+        for event in sorted(self.webhook_parser.events.values(), key=lambda event: event.name):
+            struct = event.name + 'Event'
+            if struct in taken:
+                struct = 'Webhook' + struct
+            events.append({'event': event, 'struct': struct, 'handler': 'On' + event.name,
+                           'constant': 'Webhook' + event.name if event.event_type else ''})
+        return events
+    
+    def _generate_go_webhook(self, package_name: str) -> str:
+        import json
+        signature = self.webhook_parser.signature
+        events = self._go_webhook_events()
+        constants = [(e['constant'], json.dumps(e['event'].event_type)) for e in events if e['constant']]
+        width = max((len(name) for name, _ in constants), default=0)
+        constant_block = ''
+        if constants:
+            constant_block = ("// Webhook event types, as the API sends them.\nconst (\n"
+                              + ''.join(f"\t{name:<{width}} = {value}\n" for name, value in constants) + ")\n\n")
+        types, methods = [], []
+        for e in events:
+            event = e['event']
+            label = f"each {event.event_type} delivery" if event.event_type else "each webhook delivery"
+            rows = []
+            for prop_name, prop_schema in (event.schema.get('properties') or {}).items():
+                go_name, prop_type, tag = self._go_field(prop_name, prop_schema)
+                rows.append([go_name, prop_type, f'`json:"{tag}"`'])
+            body = '\n'.join(self._align_go_columns(rows))
+            types.append(f"// {e['struct']} is the payload of {label}.\n"
+                         f"type {e['struct']} struct {{\n{body + chr(10) if body else ''}}}")
+            key = e['constant'] or '""'
+            methods.append(f"// {e['handler']} calls fn with {label}.\n"
+                           f"func (h *WebhookHandler) {e['handler']}(fn func(context.Context, {e['struct']}) error) {{\n"
+                           f"\tonWebhook(h, {key}, fn)\n}}")
+        example = events[0]
+        header = signature.header if signature else ''
+        header = '-'.join(word.capitalize() for word in header.split('-'))
+        event_header = '-'.join(word.capitalize() for word in (self.webhook_parser.event_header or '').split('-') if word)
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"context"
+\t"crypto/hmac"
+\t"crypto/sha256"
+\t"encoding/base64"
+\t"encoding/hex"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+// The captured webhook deliveries' signature header and how its value is
+// computed: "standard" (Standard Webhooks, a base64 HMAC of the delivery's
+// id, timestamp, and body), "stripe" (a hex HMAC of the timestamp and
+// body), "hub" (a hex HMAC of the body), "hmac" (an HMAC of the body in
+// webhookSignatureEncoding after webhookSignaturePrefix), or empty when
+// deliveries were not signed.
+const (
+\twebhookSignatureHeader   = "{header}"
+\twebhookSignatureScheme   = "{signature.scheme if signature else ''}"
+\twebhookSignatureEncoding = "{signature.encoding if signature else ''}"
+\twebhookSignaturePrefix   = "{signature.prefix if signature else ''}"
+\t// webhookEventHeader names a delivery's event type; when empty, the type
+\t// is the payload's webhookEventField.
+\twebhookEventHeader = "{event_header}"
+\twebhookEventField  = "{self.webhook_parser.discriminator or ''}"
+)
+
+{constant_block}{chr(10).join(type_ + chr(10) for type_ in types)}
+// DefaultWebhookTolerance is how far the timestamp a delivery's signature
+// covers may be from the current time before the delivery is rejected as a
+// replay.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// maxWebhookBody bounds the deliveries WebhookHandler reads.
+const maxWebhookBody = 5 << 20
+
+// ErrWebhookSignature is returned by VerifyWebhook for deliveries whose
+// signature is missing, does not match, or covers a timestamp outside the
+// tolerance.
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookDelivery is a verified delivery of an event type no handler was
+// registered for.
+type WebhookDelivery struct {{
+\tType    string
+\tHeader  http.Header
+\tPayload json.RawMessage
+}}
+
+// WebhookHandler is an http.Handler receiving the API's webhook
+// deliveries. It verifies each delivery's signature, decodes the payload
+// into the type of its event, and calls the function registered for it:
+//
+//\twebhooks := NewWebhookHandler(os.Getenv("WEBHOOK_SECRET"))
+//\twebhooks.{example['handler']}(func(ctx context.Context, event {example['struct']}) error {{
+//\t\treturn process(ctx, event)
+//\t}})
+//\thttp.Handle("/webhooks", webhooks)
+//
+// A delivery is answered 401 when its signature does not verify, 400 when
+// its payload does not decode, and 500 when the function returns an error,
+// so that the sender retries it. Register functions before serving.
+type WebhookHandler struct {{
+\t// Tolerance overrides DefaultWebhookTolerance; a negative value turns
+\t// the timestamp check off.
+\tTolerance time.Duration
+\t// Now returns the current time; nil means time.Now.
+\tNow func() time.Time
+
+\tsecret    []byte
+\thandlers  map[string]func(context.Context, []byte) error
+\tunhandled func(context.Context, WebhookDelivery) error
+}}
+
+// NewWebhookHandler returns a handler verifying deliveries with secret, the
+// signing secret the API issued for the webhook endpoint.
+func NewWebhookHandler(secret string) *WebhookHandler {{
+\treturn &WebhookHandler{{secret: []byte(secret), handlers: make(map[string]func(context.Context, []byte) error)}}
+}}
+
+{(chr(10) + chr(10)).join(methods)}
+
+// OnUnhandled calls fn for deliveries of event types without a function of
+// their own, which are otherwise acknowledged and dropped.
+func (h *WebhookHandler) OnUnhandled(fn func(context.Context, WebhookDelivery) error) {{
+\th.unhandled = fn
+}}
+
+// webhookPayloadError is a delivery whose payload did not decode.
+type webhookPayloadError struct {{
+\terr error
+}}
+
+func (e *webhookPayloadError) Error() string {{
+\treturn "decoding webhook payload: " + e.err.Error()
+}}
+
+func onWebhook[T any](h *WebhookHandler, eventType string, fn func(context.Context, T) error) {{
+\th.handlers[eventType] = func(ctx context.Context, payload []byte) error {{
+\t\tvar event T
+\t\tif err := json.Unmarshal(payload, &event); err != nil {{
+\t\t\treturn &webhookPayloadError{{err}}
+\t\t}}
+\t\treturn fn(ctx, event)
+\t}}
+}}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {{
+\tif r.Method != http.MethodPost {{
+\t\tw.Header().Set("Allow", http.MethodPost)
+\t\thttp.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+\t\treturn
+\t}}
+\tbody, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+\tif err != nil {{
+\t\thttp.Error(w, "reading body: "+err.Error(), http.StatusBadRequest)
+\t\treturn
+\t}}
+\tnow, tolerance := time.Now(), h.Tolerance
+\tif h.Now != nil {{
+\t\tnow = h.Now()
+\t}}
+\tif tolerance == 0 {{
+\t\ttolerance = DefaultWebhookTolerance
+\t}}
+\tif err := verifyWebhook(h.secret, r.Header, body, now, tolerance); err != nil {{
+\t\thttp.Error(w, err.Error(), http.StatusUnauthorized)
+\t\treturn
+\t}}
+
+\teventType := webhookEventType(r.Header, body)
+\tif handler, ok := h.handlers[eventType]; ok {{
+\t\terr = handler(r.Context(), body)
+\t}} else if h.unhandled != nil {{
+\t\terr = h.unhandled(r.Context(), WebhookDelivery{{Type: eventType, Header: r.Header, Payload: body}})
+\t}}
+\tvar payloadErr *webhookPayloadError
+\tswitch {{
+\tcase errors.As(err, &payloadErr):
+\t\thttp.Error(w, err.Error(), http.StatusBadRequest)
+\tcase err != nil:
+\t\thttp.Error(w, err.Error(), http.StatusInternalServerError)
+\tdefault:
+\t\tw.WriteHeader(http.StatusOK)
+\t}}
+}}
+
+// webhookEventType returns a delivery's event type, from its header or its
+// payload.
+func webhookEventType(header http.Header, body []byte) string {{
+\tif webhookEventHeader != "" {{
+\t\treturn header.Get(webhookEventHeader)
+\t}}
+\tif webhookEventField == "" {{
+\t\treturn ""
+\t}}
+\tvar payload map[string]json.RawMessage
+\tvar eventType string
+\tif json.Unmarshal(body, &payload) == nil {{
+\t\tjson.Unmarshal(payload[webhookEventField], &eventType)
+\t}}
+\treturn eventType
+}}
+
+// VerifyWebhook checks the signature of a delivery received outside a
+// WebhookHandler, with the default tolerance. It returns an error wrapping
+// ErrWebhookSignature if the delivery is not authentic.
+func VerifyWebhook(secret string, header http.Header, body []byte) error {{
+\treturn verifyWebhook([]byte(secret), header, body, time.Now(), DefaultWebhookTolerance)
+}}
+
+func verifyWebhook(secret []byte, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {{
+\tif webhookSignatureScheme == "" {{
+\t\treturn nil
+\t}}
+\tif len(secret) == 0 {{
+\t\treturn fmt.Errorf("%w: no secret configured", ErrWebhookSignature)
+\t}}
+\tvalue := header.Get(webhookSignatureHeader)
+\tif value == "" {{
+\t\treturn fmt.Errorf("%w: missing %s header", ErrWebhookSignature, webhookSignatureHeader)
+\t}}
+\tvar timestamp, signed string
+\tvar candidates []string
+\tencode := hex.EncodeToString
+\tswitch webhookSignatureScheme {{
+\tcase "standard":
+\t\t// Standard Webhooks secrets are base64 after a whsec_ prefix.
+\t\tif key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(secret), "whsec_")); err == nil {{
+\t\t\tsecret = key
+\t\t}}
+\t\tprefix := strings.TrimSuffix(webhookSignatureHeader, "Signature")
+\t\ttimestamp = header.Get(prefix + "Timestamp")
+\t\tsigned = header.Get(prefix+"Id") + "." + timestamp + "."
+\t\tfor _, candidate := range strings.Fields(value) {{
+\t\t\tif version, signature, ok := strings.Cut(candidate, ","); ok && version == "v1" {{
+\t\t\t\tcandidates = append(candidates, signature)
+\t\t\t}}
+\t\t}}
+\t\tencode = base64.StdEncoding.EncodeToString
+\tcase "stripe":
+\t\tfor _, part := range strings.Split(value, ",") {{
+\t\t\tkey, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+\t\t\tswitch key {{
+\t\t\tcase "t":
+\t\t\t\ttimestamp = v
+\t\t\tcase "v1":
+\t\t\t\tcandidates = append(candidates, v)
+\t\t\t}}
+\t\t}}
+\t\tsigned = timestamp + "."
+\tcase "hub":
+\t\tcandidates = []string{{strings.TrimPrefix(value, "sha256=")}}
+\tdefault:
+\t\tcandidates = []string{{strings.TrimPrefix(value, webhookSignaturePrefix)}}
+\t\tif webhookSignatureEncoding == "base64" {{
+\t\t\tencode = base64.StdEncoding.EncodeToString
+\t\t}}
+\t}}
+\tif webhookSignatureScheme == "standard" || webhookSignatureScheme == "stripe" {{
+\t\tseconds, err := strconv.ParseInt(timestamp, 10, 64)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("%w: bad timestamp %q", ErrWebhookSignature, timestamp)
+\t\t}}
+\t\tif age := now.Sub(time.Unix(seconds, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {{
+\t\t\treturn fmt.Errorf("%w: timestamp %s outside tolerance", ErrWebhookSignature, time.Unix(seconds, 0).UTC().Format(time.RFC3339))
+\t\t}}
+\t}}
+\tmac := hmac.New(sha256.New, secret)
+\tmac.Write([]byte(signed))
+\tmac.Write(body)
+\texpected := []byte(encode(mac.Sum(nil)))
+\tfor _, candidate := range candidates {{
+\t\tif hmac.Equal(expected, bytes.TrimSpace([]byte(candidate))) {{
+\t\t\treturn nil
+\t\t}}
+\t}}
+\treturn fmt.Errorf("%w: signature does not match", ErrWebhookSignature)
+}}
+"""
+    
+    CHANGE_FEED_PARAMS = ('updated_since', 'updatedSince', 'modified_since', 'modifiedSince', 'changed_since',
+                          'changedSince', 'since', 'since_id', 'sinceId', 'since_seq', 'after_seq', 'after_sequence',
+                          'from_seq', 'last_seq', 'seq', 'sequence')
+    CHANGE_SEQUENCE_FIELDS = ('sequence', 'seq', 'sequence_id', 'seq_id', 'sequenceNumber', 'version')
+    CHANGE_TIME_FIELDS = ('updated_at', 'updatedAt', 'modified_at', 'modifiedAt', 'changed_at', 'changedAt',
+                          'timestamp', 'ts', 'occurred_at', 'created_at', 'createdAt')
+    CHANGE_ID_FIELDS = ('id', 'event_id', 'eventId', 'change_id', 'changeId')
+    CHANGE_TYPE_FIELDS = ('type', 'event', 'event_type', 'eventType', 'kind', 'action', 'change_type', 'changeType')
+    CHANGE_DATA_FIELDS = ('data', 'object', 'payload', 'resource', 'record', 'entity')
+    CHANGE_NEXT_FIELDS = ('next_since', 'nextSince', 'next_sequence', 'next_seq', 'last_seq', 'last_sequence',
+                          'next_cursor', 'nextCursor', 'cursor', 'since')
+    CHANGE_MORE_FIELDS = ('has_more', 'hasMore', 'more', 'pending')
+    
+    SOCKET_PATH_NOISE = ('ws', 'wss', 'websocket', 'websockets', 'socket', 'sockets', 'api')
+    
+    def _go_socket_channels(self) -> List[Dict[str, Any]]:
+        """The captured WebSocket channels, each with the name of its socket
+        type (NotificationsSocket for /ws/notifications, leaving out words
+        such as ws and versions), the base URL of a channel on another host
+        than the API's, and its text and binary message types. Each message
+        has the Go type it decodes into: its struct for JSON objects, named
+        as the parser named the type unless another type has that name, or
+        json.RawMessage, string, or []byte. Received types get an On method
+        and sent ones a Send method."""
+        import json
+        import re
+        from urllib.parse import urlparse
+        taken = set(self.nested_structs) | set(self._go_struct_names().values()) | set(self.enum_values)
+        if self.webhook_parser and self.webhook_parser.events:
+            taken |= {e['struct'] for e in self._go_webhook_events()}
+        api = urlparse(self.base_url)
+        channels = []
+        for channel in sorted(self.websocket_parser.channels.values(), key=lambda channel: channel.path):
+            words = [w for w in re.split(r'[^0-9A-Za-z]+', channel.path) if w and not w.isdigit()]
+            meaningful = [w for w in words if w.lower() not in self.SOCKET_PATH_NOISE and not re.fullmatch(r'v\d+', w)]
+            base = ''.join(w[0].upper() + w[1:] for w in (meaningful or words)) or 'Default'
+            name, number = base + 'Socket', 1
+            while name in taken:
+                number += 1
+                name = f"{base}{number}Socket"
+            taken.add(name)
+            url = urlparse(channel.url)
+            base_url = ''
+            if url.netloc and url.netloc != api.netloc:
+                base_url = f"{'https' if url.scheme in ('wss', 'https') else 'http'}://{url.netloc}"
+            messages = []
+            for key, message_type in sorted(channel.message_types.items(), key=lambda item: item[1].name):
+                if message_type.opcode not in (1, 2):
+                    continue
+                schema = message_type.schema
+                struct = ''
+                if schema.get('type') == 'object' and schema.get('properties'):
+                    struct = message_type.name
+                    if struct in taken:
+                        struct = base + struct
+                    while struct in taken:
+                        struct += 'Message'
+                    taken.add(struct)
+                    go_type = struct
+                elif schema:
+                    go_type = 'json.RawMessage'
+                else:
+                    go_type = '[]byte' if message_type.opcode == 2 else 'string'
+                value = message_type.discriminator_value
+                label = str(value) if value is not None else key.split(':', 1)[1]
+                method = re.sub(r'Message$', '', message_type.name) or message_type.name
+                # The parser prefixes a type whose name the other direction
+                # took; On and Send already tell them apart.
+                unprefixed = re.sub(r'^(Inbound|Outbound)', '', method)
+                if unprefixed != method and unprefixed + 'Message' in {t.name for t in channel.message_types.values()}:
+                    method = unprefixed
+                if message_type.direction == 'receive':
+                    method = 'On' + method
+                    if method in ('OnConnect', 'OnUnhandled'):
+                        method += 'Message'
+                else:
+                    method = 'Send' + method
+                assign = ''
+                prop = (schema.get('properties') or {}).get(message_type.discriminator or '')
+                if struct and prop:
+                    go_name, prop_type, _ = self._go_field(message_type.discriminator, prop)
+                    if prop_type == 'string' and isinstance(value, str):
+                        assign = f"message.{go_name} = {json.dumps(value)}"
+                    elif prop_type in ('int', 'float64') and isinstance(value, int):
+                        assign = f"message.{go_name} = {value}"
+                messages.append({'type': message_type, 'struct': struct, 'go_type': go_type, 'label': label,
+                                 'method': method, 'assign': assign})
+            channels.append({'channel': channel, 'name': name, 'base_url': base_url, 'messages': messages})
+        return channels
+    
+    def _go_socket_example(self, channels: List[Dict[str, Any]]) -> Tuple[Dict[str, Any], Optional[Dict[str, Any]]]:
+        """The channel to show in examples, preferring one receiving a
+        struct, and the received message to handle there, if any."""
+        received = [(c, m) for c in channels for m in c['messages'] if m['type'].direction == 'receive']
+        for channel, message in sorted(received, key=lambda pair: not pair[1]['struct']):
+            return channel, message
+        return channels[0], None
+    
+    def _go_readme_websockets(self) -> str:
+        """The README section on the WebSocket channels' sockets."""
+        channels = self._go_socket_channels()
+        example, received = self._go_socket_example(channels)
+        sent = next((m for m in example['messages'] if m['type'].direction == 'send'), None)
+        handler = (f"{received['method']}(func(ctx context.Context, message {received['go_type']}) error {{"
+                   if received else "OnUnhandled(func(ctx context.Context, message SocketMessage) error {")
+        subscribe = ''
+        if sent:
+            value = f"{sent['go_type']}{{}}" if sent['struct'] else ('nil' if sent['go_type'] != 'string' else '""')
+            subscribe = f"""socket.OnConnect(func(ctx context.Context) error {{
+    return socket.{sent['method']}(ctx, {value})
+}})
+"""
+        listed = ', '.join(f"`{c['name']}` ({c['channel'].path})" for c in channels)
+        return f"""## WebSockets
+
+The capture included WebSocket traffic, so each channel has a socket type:
+{listed}. A socket decodes each message it receives into the type of its
+kind and calls the handler registered for it; `Run` keeps the connection
+open, reconnecting with backoff when it drops, until its context is done:
+
+```go
+socket := client.{example['name']}()
+socket.{handler}
+    return process(ctx, message)
+}})
+{subscribe}err := socket.Run(ctx)
+```
+
+The handshake carries the client's headers and credentials. `OnConnect` runs
+after every (re)connection, so subscriptions survive reconnects, and
+`Send` methods fill in each message's type. Handshakes the server rejects
+for good, such as `401`, and handler errors end `Run`.
+
+"""
+    
+    def _generate_go_websocket(self, package_name: str) -> str:
+        """websocket.go: a socket type per captured WebSocket channel over
+        Socket, which keeps a connection open with reconnection and backoff,
+        dispatches received messages to the handler of their type, and sends
+        messages through the client's Codec. The handshake is sent as a call,
+        so it carries the client's headers, credentials, and middleware."""
+        import json
+        import textwrap
+        channels = self._go_socket_channels()
+        
+        def doc(text):
+            return '\n'.join('// ' + line for line in textwrap.wrap(text, 74))
+        
+        types, sockets = [], []
+        for c in channels:
+            channel = c['channel']
+            path = json.dumps(channel.path)
+            methods = []
+            for m in c['messages']:
+                message_type = m['type']
+                kind = (f"{m['label']} message" if message_type.discriminator_value is not None
+                        else {'json': 'JSON message', 'text': 'text message', 'binary': 'binary message'}.get(m['label'], 'message'))
+                article = 'an' if kind[0].lower() in 'aeiou' else 'a'
+                if m['struct']:
+                    rows = []
+                    for prop_name, prop_schema in message_type.schema['properties'].items():
+                        go_name, prop_type, tag = self._go_field(prop_name, prop_schema)
+                        rows.append([go_name, prop_type, f'`json:"{tag}"`'])
+                    direction = 'received on' if message_type.direction == 'receive' else 'sent on'
+                    types.append(doc(f"{m['struct']} is {article} {kind} {direction} {channel.path}.") + "\n"
+                                 f"type {m['struct']} struct {{\n" + '\n'.join(self._align_go_columns(rows)) + "\n}")
+                if message_type.direction == 'receive':
+                    methods.append(doc(f"{m['method']} calls fn with each {kind} received.") + "\n"
+                                   f"func (s *{c['name']}) {m['method']}(fn func(context.Context, {m['go_type']}) error) {{\n"
+                                   f"\tonSocket(s.Socket, {json.dumps(m['label'])}, fn)\n}}")
+                else:
+                    assign = f"\t{m['assign']}\n" if m['assign'] else ''
+                    methods.append(doc(f"{m['method']} sends {article} {kind}.") + "\n"
+                                   f"func (s *{c['name']}) {m['method']}(ctx context.Context, message {m['go_type']}) error {{\n"
+                                   f"{assign}\treturn s.Send(ctx, message)\n}}")
+            sockets.append(f"""{doc(f"{c['name']} is a connection to the {channel.path} WebSocket channel, opened by Run.")}
+type {c['name']} struct {{
+\t*Socket
+}}
+
+{doc(f"{c['name']} returns a socket for the {channel.path} channel; opts apply to each handshake.")}
+func (c *{self.class_name}Client) {c['name']}(opts ...RequestOption) *{c['name']} {{
+\treturn &{c['name']}{{newSocket(c, {path}, {json.dumps(c['base_url'])}, {json.dumps(channel.discriminator or '')}, opts)}}
+}}""" + ''.join('\n\n' + method for method in methods))
+        example, received = self._go_socket_example(channels)
+        handler = (f"{received['method']}(func(ctx context.Context, message {received['go_type']}) error {{"
+                   if received else "OnUnhandled(func(ctx context.Context, message SocketMessage) error {")
+        example = (f"//\tsocket := client.{example['name']}()\n"
+                   f"//\tsocket.{handler}\n"
+                   "//\t\treturn process(ctx, message)\n"
+                   "//\t})\n"
+                   "//\terr := socket.Run(ctx)")
+        return f"""package {package_name}
+
+import (
+\t"bufio"
+\t"bytes"
+\t"context"
+\tcryptorand "crypto/rand"
+\t"crypto/sha1"
+\t"encoding/base64"
+\t"encoding/binary"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"io"
+\t"math/rand"
+\t"net/http"
+\t"sync"
+\t"time"
+)
+
+{''.join(type_ + chr(10) + chr(10) for type_ in types)}{(chr(10) + chr(10)).join(sockets)}
+
+// Default bounds of the backoff between a Socket's reconnection attempts.
+const (
+\tDefaultMinReconnectDelay = time.Second
+\tDefaultMaxReconnectDelay = 30 * time.Second
+)
+
+// maxSocketMessage bounds the messages a Socket reads when the client has
+// no response size limit.
+const maxSocketMessage = 32 << 20
+
+// socketGUID is appended to the handshake key the server answers with its
+// hash (RFC 6455, section 1.3).
+const socketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes and close codes of RFC 6455.
+const (
+\topContinuation = 0x0
+\topText         = 0x1
+\topBinary       = 0x2
+\topClose        = 0x8
+\topPing         = 0x9
+\topPong         = 0xA
+
+\tcloseNormal        = 1000
+\tcloseProtocolError = 1002
+\tcloseTooBig        = 1009
+)
+
+// ErrSocketNotConnected is returned by sends while a Socket is not
+// connected: before Run connects it, and while it reconnects.
+var ErrSocketNotConnected = errors.New("socket not connected")
+
+// ErrSocketHandshake is returned (wrapped) by Run when the server answers
+// the WebSocket handshake with something other than an upgrade.
+var ErrSocketHandshake = errors.New("websocket handshake failed")
+
+// SocketClosedError is a connection the server closed, with the code and
+// reason it gave. Run reconnects after it.
+type SocketClosedError struct {{
+\tCode   int
+\tReason string
+}}
+
+func (e *SocketClosedError) Error() string {{
+\treturn fmt.Sprintf("socket closed by server: %d %s", e.Code, e.Reason)
+}}
+
+// SocketMessage is a received message of a type no handler was registered
+// for.
+type SocketMessage struct {{
+\tType    string // the message's type, or "json", "text", or "binary"
+\tPayload []byte
+}}
+
+// Socket is a WebSocket connection to one of the API's channels, kept open
+// by Run. Each channel's socket type embeds it, adding a method registering
+// a handler per type of message received and one sending each type of
+// message sent:
+//
+{example}
+type Socket struct {{
+\t// MinReconnectDelay and MaxReconnectDelay bound the backoff between
+\t// reconnection attempts; zero means DefaultMinReconnectDelay and
+\t// DefaultMaxReconnectDelay.
+\tMinReconnectDelay time.Duration
+\tMaxReconnectDelay time.Duration
+
+\tclient *{self.class_name}Client
+\tpath   string
+\t// baseURL is set for channels on another host than the API's.
+\tbaseURL string
+\t// discriminator names the field holding a message's type, "[0]" for
+\t// the first element of an array, or is empty.
+\tdiscriminator string
+\topts          []RequestOption
+\thandlers      map[string]func(context.Context, []byte) error
+\tconnected     func(context.Context) error
+\tunhandled     func(context.Context, SocketMessage) error
+
+\tmu   sync.Mutex
+\tconn *socketConn
+}}
+
+func newSocket(c *{self.class_name}Client, path, baseURL, discriminator string, opts []RequestOption) *Socket {{
+\treturn &Socket{{client: c, path: path, baseURL: baseURL, discriminator: discriminator, opts: opts,
+\t\thandlers: make(map[string]func(context.Context, []byte) error)}}
+}}
+
+// OnConnect calls fn each time the socket connects, before any message is
+// dispatched, e.g. to subscribe again after a reconnection. An error ends
+// Run.
+func (s *Socket) OnConnect(fn func(ctx context.Context) error) {{
+\ts.connected = fn
+}}
+
+// OnUnhandled calls fn with messages of types without a handler of their
+// own, which are otherwise dropped.
+func (s *Socket) OnUnhandled(fn func(context.Context, SocketMessage) error) {{
+\ts.unhandled = fn
+}}
+
+func onSocket[T any](s *Socket, messageType string, fn func(context.Context, T) error) {{
+\ts.handlers[messageType] = func(ctx context.Context, payload []byte) error {{
+\t\tvar message T
+\t\tswitch target := interface{{}}(&message).(type) {{
+\t\tcase *[]byte:
+\t\t\t*target = payload
+\t\tcase *string:
+\t\t\t*target = string(payload)
+\t\tdefault:
+\t\t\tif err := s.client.jsonCodec().Unmarshal(payload, &message); err != nil {{
+\t\t\t\treturn fmt.Errorf("decoding %s message: %w", messageType, err)
+\t\t\t}}
+\t\t}}
+\t\treturn fn(ctx, message)
+\t}}
+}}
+
+// Run connects the socket and calls the handlers of the messages it
+// receives, one at a time, until ctx is done; it then closes the
+// connection and returns ctx.Err(). A dropped connection is reopened after
+// a backoff. Run returns early with the error of a handler or OnConnect
+// function, or of a handshake the server rejected for good, such as with
+// 401 Unauthorized. Register handlers before calling Run.
+func (s *Socket) Run(ctx context.Context) error {{
+\tminDelay, maxDelay := s.MinReconnectDelay, s.MaxReconnectDelay
+\tif minDelay <= 0 {{
+\t\tminDelay = DefaultMinReconnectDelay
+\t}}
+\tif maxDelay <= 0 {{
+\t\tmaxDelay = DefaultMaxReconnectDelay
+\t}}
+\tdelay := minDelay
+\tfor {{
+\t\tconn, err := s.dial(ctx)
+\t\tif err == nil {{
+\t\t\tdelay = minDelay
+\t\t\terr = s.serve(ctx, conn)
+\t\t\tvar handlerErr *socketHandlerError
+\t\t\tif errors.As(err, &handlerErr) {{
+\t\t\t\treturn handlerErr.err
+\t\t\t}}
+\t\t}}
+\t\tif ctx.Err() != nil {{
+\t\t\treturn ctx.Err()
+\t\t}}
+\t\tif !reconnectable(err) {{
+\t\t\treturn err
+\t\t}}
+\t\twait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\treturn ctx.Err()
+\t\tcase <-time.After(wait):
+\t\t}}
+\t\tif delay *= 2; delay > maxDelay {{
+\t\t\tdelay = maxDelay
+\t\t}}
+\t}}
+}}
+
+// Send sends v as a message: []byte as a binary frame, a string as text,
+// and anything else as JSON encoded with the client's Codec. It returns
+// ErrSocketNotConnected unless Run has the socket connected.
+func (s *Socket) Send(ctx context.Context, v interface{{}}) error {{
+\tif err := ctx.Err(); err != nil {{
+\t\treturn err
+\t}}
+\ts.mu.Lock()
+\tconn := s.conn
+\ts.mu.Unlock()
+\tif conn == nil {{
+\t\treturn ErrSocketNotConnected
+\t}}
+\tswitch v := v.(type) {{
+\tcase []byte:
+\t\treturn conn.write(opBinary, v)
+\tcase string:
+\t\treturn conn.write(opText, []byte(v))
+\t}}
+\tdata, err := s.client.jsonCodec().Marshal(v)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\treturn conn.write(opText, data)
+}}
+
+// socketHandlerError is the error of a handler, which ends Run.
+type socketHandlerError struct {{
+\terr error
+}}
+
+func (e *socketHandlerError) Error() string {{
+\treturn e.err.Error()
+}}
+
+// reconnectable reports whether a connection that failed with err may
+// succeed when reopened.
+func reconnectable(err error) bool {{
+\tvar closed *SocketClosedError
+\tvar apiErr *APIError
+\tswitch {{
+\tcase errors.As(err, &closed), errors.Is(err, ErrCircuitOpen):
+\t\treturn true
+\tcase errors.As(err, &apiErr):
+\t\treturn apiErr.Temporary()
+\t}}
+\treturn transientNetworkError(err)
+}}
+
+// dial opens a connection with the WebSocket handshake, sent as a call with
+// the client's headers, credentials, and middleware.
+func (s *Socket) dial(ctx context.Context) (*socketConn, error) {{
+\tnonce := make([]byte, 16)
+\tif _, err := cryptorand.Read(nonce); err != nil {{
+\t\treturn nil, err
+\t}}
+\tkey := base64.StdEncoding.EncodeToString(nonce)
+\topts := make([]RequestOption, 0, len(s.opts)+5)
+\tif s.baseURL != "" {{
+\t\topts = append(opts, WithBaseURL(s.baseURL))
+\t}}
+\topts = append(append(opts, s.opts...),
+\t\tWithHeader("Connection", "Upgrade"),
+\t\tWithHeader("Upgrade", "websocket"),
+\t\tWithHeader("Sec-WebSocket-Version", "13"),
+\t\tWithHeader("Sec-WebSocket-Key", key))
+\toperation := "GET " + s.path
+\tresp, release, err := s.client.openResponse(ctx, http.MethodGet, s.path, s.path, nil, nil, opts)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tfail := func(format string, args ...interface{{}}) (*socketConn, error) {{
+\t\tresp.Body.Close()
+\t\trelease()
+\t\treturn nil, fmt.Errorf("%s: %w: %s", operation, ErrSocketHandshake, fmt.Sprintf(format, args...))
+\t}}
+\tif resp.StatusCode != http.StatusSwitchingProtocols {{
+\t\treturn fail("got %s", resp.Status)
+\t}}
+\thash := sha1.Sum([]byte(key + socketGUID))
+\tif resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(hash[:]) {{
+\t\treturn fail("invalid Sec-WebSocket-Accept")
+\t}}
+\trw, ok := resp.Body.(io.ReadWriteCloser)
+\tif !ok {{
+\t\treturn fail("the HTTP transport does not support connection upgrades")
+\t}}
+\tlimit := s.client.limits.response
+\tif limit <= 0 {{
+\t\tlimit = maxSocketMessage
+\t}}
+\treturn &socketConn{{rw: rw, r: bufio.NewReader(rw), limit: limit, release: release}}, nil
+}}
+
+// serve reads conn's messages and dispatches them until the connection
+// drops, ctx is done, or a handler fails.
+func (s *Socket) serve(ctx context.Context, conn *socketConn) error {{
+\ts.mu.Lock()
+\ts.conn = conn
+\ts.mu.Unlock()
+\tdone := make(chan struct{{}})
+\tdefer func() {{
+\t\tclose(done)
+\t\ts.mu.Lock()
+\t\ts.conn = nil
+\t\ts.mu.Unlock()
+\t}}()
+\tgo func() {{
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\tconn.close(closeNormal)
+\t\tcase <-done:
+\t\t}}
+\t}}()
+
+\tif s.connected != nil {{
+\t\tif err := s.connected(ctx); err != nil {{
+\t\t\tconn.close(closeNormal)
+\t\t\treturn &socketHandlerError{{err}}
+\t\t}}
+\t}}
+\tfor {{
+\t\topcode, payload, err := conn.read()
+\t\tif err != nil {{
+\t\t\treturn err
+\t\t}}
+\t\tif err := s.dispatch(ctx, opcode, payload); err != nil {{
+\t\t\tconn.close(closeNormal)
+\t\t\treturn &socketHandlerError{{err}}
+\t\t}}
+\t}}
+}}
+
+// dispatch calls the handler of a received message's type.
+func (s *Socket) dispatch(ctx context.Context, opcode byte, payload []byte) error {{
+\tmessageType := socketMessageType(s.discriminator, opcode, payload)
+\tif handler, ok := s.handlers[messageType]; ok {{
+\t\treturn handler(ctx, payload)
+\t}}
+\tif s.unhandled != nil {{
+\t\treturn s.unhandled(ctx, SocketMessage{{Type: messageType, Payload: payload}})
+\t}}
+\treturn nil
+}}
+
+// socketMessageType returns the type of a message: the value of its
+// discriminator, or else "json" for JSON objects and arrays, "text" for
+// other text, and "binary".
+func socketMessageType(discriminator string, opcode byte, payload []byte) string {{
+\tif opcode == opBinary {{
+\t\treturn "binary"
+\t}}
+\ttrimmed := bytes.TrimSpace(payload)
+\tif len(trimmed) == 0 || (trimmed[0] != '{{' && trimmed[0] != '[') || !json.Valid(trimmed) {{
+\t\treturn "text"
+\t}}
+\tvar value json.RawMessage
+\tif discriminator == "[0]" {{
+\t\tvar items []json.RawMessage
+\t\tif json.Unmarshal(trimmed, &items) == nil && len(items) > 0 {{
+\t\t\tvalue = items[0]
+\t\t}}
+\t}} else if discriminator != "" {{
+\t\tvar object map[string]json.RawMessage
+\t\tif json.Unmarshal(trimmed, &object) == nil {{
+\t\t\tvalue = object[discriminator]
+\t\t}}
+\t}}
+\tvar name string
+\tvar number json.Number
+\tswitch {{
+\tcase json.Unmarshal(value, &name) == nil:
+\t\treturn name
+\tcase json.Unmarshal(value, &number) == nil:
+\t\treturn number.String()
+\t}}
+\treturn "json"
+}}
+
+// socketConn reads and writes the frames of an upgraded connection. Writes
+// may come from any goroutine, reads from one.
+type socketConn struct {{
+\trw      io.ReadWriteCloser
+\tr       *bufio.Reader
+\tlimit   int64
+\trelease func()
+
+\tmu     sync.Mutex
+\tclosed bool
+}}
+
+// write sends a frame.
+func (c *socketConn) write(opcode byte, payload []byte) error {{
+\tframe, err := encodeFrame(opcode, payload)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tc.mu.Lock()
+\tdefer c.mu.Unlock()
+\tif c.closed {{
+\t\treturn ErrSocketNotConnected
+\t}}
+\t_, err = c.rw.Write(frame)
+\treturn err
+}}
+
+// encodeFrame returns a final frame of payload, masked as a client's must
+// be.
+func encodeFrame(opcode byte, payload []byte) ([]byte, error) {{
+\tframe := []byte{{0x80 | opcode, 0x80}}
+\tswitch n := len(payload); {{
+\tcase n < 126:
+\t\tframe[1] |= byte(n)
+\tcase n <= 0xFFFF:
+\t\tframe[1] |= 126
+\t\tframe = binary.BigEndian.AppendUint16(frame, uint16(n))
+\tdefault:
+\t\tframe[1] |= 127
+\t\tframe = binary.BigEndian.AppendUint64(frame, uint64(n))
+\t}}
+\tvar mask [4]byte
+\tif _, err := cryptorand.Read(mask[:]); err != nil {{
+\t\treturn nil, err
+\t}}
+\tframe = append(frame, mask[:]...)
+\tstart := len(frame)
+\tframe = append(frame, payload...)
+\tfor i := range payload {{
+\t\tframe[start+i] ^= mask[i%4]
+\t}}
+\treturn frame, nil
+}}
+
+// read returns the next message, joining its fragments. It answers pings,
+// and closes the connection when the server does, returning a
+// *SocketClosedError, or when reading fails.
+func (c *socketConn) read() (byte, []byte, error) {{
+\tvar opcode byte
+\tvar message []byte
+\tfor {{
+\t\tfin, op, payload, err := c.readFrame()
+\t\tif err != nil {{
+\t\t\tcode := closeNormal
+\t\t\tif errors.Is(err, errSocketProtocol) {{
+\t\t\t\tcode = closeProtocolError
+\t\t\t}} else if errors.Is(err, errSocketTooBig) {{
+\t\t\t\tcode = closeTooBig
+\t\t\t}}
+\t\t\tc.close(code)
+\t\t\treturn 0, nil, err
+\t\t}}
+\t\tswitch {{
+\t\tcase op == opPing:
+\t\t\tif err := c.write(opPong, payload); err != nil {{
+\t\t\t\tc.close(closeNormal)
+\t\t\t\treturn 0, nil, err
+\t\t\t}}
+\t\t\tcontinue
+\t\tcase op == opPong:
+\t\t\tcontinue
+\t\tcase op == opClose:
+\t\t\tclosed := &SocketClosedError{{Code: 1005}}
+\t\t\tif len(payload) >= 2 {{
+\t\t\t\tclosed.Code, closed.Reason = int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+\t\t\t}}
+\t\t\tc.close(closeNormal)
+\t\t\treturn 0, nil, closed
+\t\tcase (op == opContinuation) == (opcode == 0):
+\t\t\tc.close(closeProtocolError)
+\t\t\treturn 0, nil, fmt.Errorf("%w: unexpected frame opcode %d", errSocketProtocol, op)
+\t\tcase op != opContinuation:
+\t\t\topcode = op
+\t\t}}
+\t\tif int64(len(message)+len(payload)) > c.limit {{
+\t\t\tc.close(closeTooBig)
+\t\t\treturn 0, nil, fmt.Errorf("%w: message exceeds %d bytes", errSocketTooBig, c.limit)
+\t\t}}
+\t\tmessage = append(message, payload...)
+\t\tif fin {{
+\t\t\treturn opcode, message, nil
+\t\t}}
+\t}}
+}}
+
+var (
+\terrSocketProtocol = errors.New("websocket protocol error")
+\terrSocketTooBig   = errors.New("websocket message too big")
+)
+
+// readFrame reads one frame, unmasking it if the server masked it.
+func (c *socketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {{
+\tvar head [2]byte
+\tif _, err = io.ReadFull(c.r, head[:]); err != nil {{
+\t\treturn
+\t}}
+\tfin, opcode = head[0]&0x80 != 0, head[0]&0x0F
+\tlength := uint64(head[1] & 0x7F)
+\tswitch length {{
+\tcase 126:
+\t\tvar extended [2]byte
+\t\tif _, err = io.ReadFull(c.r, extended[:]); err != nil {{
+\t\t\treturn
+\t\t}}
+\t\tlength = uint64(binary.BigEndian.Uint16(extended[:]))
+\tcase 127:
+\t\tvar extended [8]byte
+\t\tif _, err = io.ReadFull(c.r, extended[:]); err != nil {{
+\t\t\treturn
+\t\t}}
+\t\tlength = binary.BigEndian.Uint64(extended[:])
+\t}}
+\tif length > uint64(c.limit) {{
+\t\terr = fmt.Errorf("%w: frame of %d bytes exceeds %d", errSocketTooBig, length, c.limit)
+\t\treturn
+\t}}
+\tvar mask [4]byte
+\tif head[1]&0x80 != 0 {{
+\t\tif _, err = io.ReadFull(c.r, mask[:]); err != nil {{
+\t\t\treturn
+\t\t}}
+\t}}
+\tpayload = make([]byte, length)
+\tif _, err = io.ReadFull(c.r, payload); err != nil {{
+\t\treturn
+\t}}
+\tif head[1]&0x80 != 0 {{
+\t\tfor i := range payload {{
+\t\t\tpayload[i] ^= mask[i%4]
+\t\t}}
+\t}}
+\tif opcode >= opClose && (!fin || length > 125) {{
+\t\terr = fmt.Errorf("%w: invalid control frame", errSocketProtocol)
+\t}}
+\treturn
+}}
+
+// close sends a close frame with code, unless the connection is already
+// closed, and closes it.
+func (c *socketConn) close(code int) {{
+\tc.mu.Lock()
+\tdefer c.mu.Unlock()
+\tif c.closed {{
+\t\treturn
+\t}}
+\tc.closed = true
+\tif frame, err := encodeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(code))); err == nil {{
+\t\tc.rw.Write(frame)
+\t}}
+\tc.rw.Close()
+\tc.release()
+}}
+"""
+    
+    def _detect_change_feed(self) -> Optional[Dict[str, str]]:
+        """The first GET endpoint without path parameters taking a since or
+        sequence query parameter and answering with an array of events that
+        carry a sequence number or timestamp, with the field names its
+        Subscription reads, or None."""
+        for endpoint in self.endpoints.values():
+            if endpoint.method != 'GET' or endpoint.path_params:
+                continue
+            param = next((name for name in self.CHANGE_FEED_PARAMS if name in endpoint.query_params), '')
+            if not param:
+                continue
+            if 'seq' in param.lower():
+                cursors = self.CHANGE_SEQUENCE_FIELDS
+            elif any(word in param.lower() for word in ('updated', 'modified', 'changed')):
+                cursors = self.CHANGE_TIME_FIELDS
+            else:
+                cursors = self.CHANGE_SEQUENCE_FIELDS + self.CHANGE_TIME_FIELDS
+            for example in endpoint.examples:
+                data = self._example_json(example.get('response', {}).get('body'))
+                found = self._object_array(data)
+                if not found:
+                    continue
+                events_field, events = found
+                cursor = self._common_field(events, cursors)
+                if not cursor:
+                    continue
+                envelope = data if isinstance(data, dict) else {}
+                return {
+                    'endpoint': endpoint.path_pattern,
+                    'param': param,
+                    'events': events_field,
+                    'id': self._common_field(events, self.CHANGE_ID_FIELDS),
+                    'type': self._common_field(events, self.CHANGE_TYPE_FIELDS),
+                    'cursor': cursor,
+                    'data': self._common_field(events, self.CHANGE_DATA_FIELDS),
+                    'next': next((name for name in self.CHANGE_NEXT_FIELDS if name in envelope), ''),
+                    'more': next((name for name in self.CHANGE_MORE_FIELDS if isinstance(envelope.get(name), bool)), ''),
+                }
+        return None
+    
+    def _generate_go_changefeed(self, package_name: str, feed: Dict[str, str]) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"net/url"
+\t"sync"
+\t"time"
+)
+
+// The captured change feed and the JSON fields of its responses. An empty
+// events field means the response is a bare array of events; empty next and
+// more fields mean the feed reports neither, so the next cursor comes from
+// the last event's cursor field.
+const (
+\tchangeFeedEndpoint    = "{feed['endpoint']}"
+\tchangeFeedCursorParam = "{feed['param']}"
+\tchangeFeedEventsField = "{feed['events']}"
+\tchangeFeedIDField     = "{feed['id']}"
+\tchangeFeedTypeField   = "{feed['type']}"
+\tchangeFeedCursorField = "{feed['cursor']}"
+\tchangeFeedDataField   = "{feed['data']}"
+\tchangeFeedNextField   = "{feed['next']}"
+\tchangeFeedMoreField   = "{feed['more']}"
+)
+
+// changeFeedSeen is how many recent event keys a subscription remembers to
+// drop events a feed repeats, such as those at an inclusive since boundary.
+const changeFeedSeen = 1024
+
+// ChangeEvent is one entry of the change feed.
+type ChangeEvent struct {{
+\tID     string
+\tType   string
+\tCursor string // the feed position just after this event
+\tRaw    json.RawMessage
+}}
+
+// Decode unmarshals the event's payload, or the whole event when it has no
+// payload field, into v.
+func (e ChangeEvent) Decode(v interface{{}}) error {{
+\tvar fields map[string]json.RawMessage
+\tif changeFeedDataField != "" && json.Unmarshal(e.Raw, &fields) == nil {{
+\t\tif data, ok := fields[changeFeedDataField]; ok {{
+\t\t\treturn json.Unmarshal(data, v)
+\t\t}}
+\t}}
+\treturn json.Unmarshal(e.Raw, v)
+}}
+
+// key identifies the event for deduplication: an entity changing again is a
+// new event.
+func (e ChangeEvent) key() string {{
+\tif e.ID == "" && e.Cursor == "" {{
+\t\treturn string(e.Raw)
+\t}}
+\treturn e.ID + "@" + e.Cursor
+}}
+
+// ChangeHandler handles one change event. An error stops the subscription
+// before the event is checkpointed, so it is delivered again on restart.
+type ChangeHandler func(ctx context.Context, event ChangeEvent) error
+
+// Subscription polls the change feed and dispatches new events to the
+// handlers registered for their type, in feed order:
+//
+//\tsub := client.Subscribe("search-indexer").
+//\t\tWithCheckpoints(FileCheckpointStore{{Dir: "state"}})
+//\tHandleChange(sub, "user.updated", func(ctx context.Context, u User) error {{
+//\t\treturn index(u)
+//\t}})
+//\terr := sub.Run(ctx)
+//
+// Events are delivered at least once: the checkpoint advances only past
+// events whose handlers succeeded.
+type Subscription struct {{
+\t// Interval is the pause between polls that return no new events; zero
+\t// means 10 seconds.
+\tInterval time.Duration
+
+\tclient   *{self.class_name}Client
+\tname     string
+\tstore    CheckpointStore
+\thandlers map[string][]ChangeHandler
+\tfallback []ChangeHandler
+
+\tmu         sync.Mutex
+\tcheckpoint *Checkpoint
+\tseen       map[string]bool
+}}
+
+// Subscribe creates a subscription to the change feed. name identifies its
+// checkpoint; the feed is read from the start unless From or a saved
+// checkpoint says otherwise.
+func (c *{self.class_name}Client) Subscribe(name string) *Subscription {{
+\treturn &Subscription{{client: c, name: name, handlers: make(map[string][]ChangeHandler)}}
+}}
+
+// WithCheckpoints saves the subscription's position to store after every
+// page of events and resumes from it.
+func (s *Subscription) WithCheckpoints(store CheckpointStore) *Subscription {{
+\ts.store = store
+\treturn s
+}}
+
+// From starts the feed at cursor when there is no saved checkpoint.
+func (s *Subscription) From(cursor string) *Subscription {{
+\ts.checkpoint = &Checkpoint{{Name: s.name, Cursor: cursor}}
+\treturn s
+}}
+
+// On registers fn for events of eventType; an empty eventType matches
+// events no other handler is registered for.
+func (s *Subscription) On(eventType string, fn ChangeHandler) *Subscription {{
+\tif eventType == "" {{
+\t\ts.fallback = append(s.fallback, fn)
+\t}} else {{
+\t\ts.handlers[eventType] = append(s.handlers[eventType], fn)
+\t}}
+\treturn s
+}}
+
+// HandleChange registers fn for events of eventType, decoding each event's
+// payload into T first.
+func HandleChange[T any](s *Subscription, eventType string, fn func(ctx context.Context, v T) error) *Subscription {{
+\treturn s.On(eventType, func(ctx context.Context, event ChangeEvent) error {{
+\t\tvar v T
+\t\tif err := event.Decode(&v); err != nil {{
+\t\t\treturn fmt.Errorf("decoding %s event %s: %w", event.Type, event.ID, err)
+\t\t}}
+\t\treturn fn(ctx, v)
+\t}})
+}}
+
+// Checkpoint returns the subscription's current position.
+func (s *Subscription) Checkpoint() Checkpoint {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tif s.checkpoint == nil {{
+\t\treturn Checkpoint{{Name: s.name}}
+\t}}
+\tcheckpoint := *s.checkpoint
+\tcheckpoint.Seen = append([]string(nil), s.checkpoint.Seen...)
+\treturn checkpoint
+}}
+
+// Run polls the feed until ctx is done or a poll fails, for example because
+// a handler returned an error.
+func (s *Subscription) Run(ctx context.Context) error {{
+\tinterval := s.Interval
+\tif interval == 0 {{
+\t\tinterval = 10 * time.Second
+\t}}
+\tfor {{
+\t\tdispatched, more, err := s.poll(ctx)
+\t\tif err != nil {{
+\t\t\treturn err
+\t\t}}
+\t\tif dispatched > 0 && more {{
+\t\t\tcontinue
+\t\t}}
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\treturn ctx.Err()
+\t\tcase <-time.After(interval):
+\t\t}}
+\t}}
+}}
+
+// Poll fetches one page of the feed and dispatches its new events,
+// returning how many were dispatched.
+func (s *Subscription) Poll(ctx context.Context) (int, error) {{
+\tdispatched, _, err := s.poll(ctx)
+\treturn dispatched, err
+}}
+
+// poll reads one page, also reporting whether the feed may have more events
+// right away.
+func (s *Subscription) poll(ctx context.Context) (dispatched int, more bool, err error) {{
+\tif err := s.load(ctx); err != nil {{
+\t\treturn 0, false, err
+\t}}
+\tparams := url.Values{{}}
+\tif cursor := s.Checkpoint().Cursor; cursor != "" {{
+\t\tparams.Set(changeFeedCursorParam, cursor)
+\t}}
+\tbody, err := s.client.doRequest(ctx, "GET", changeFeedEndpoint, changeFeedEndpoint, params, nil)
+\tif err != nil {{
+\t\treturn 0, false, err
+\t}}
+\tevents, next, more, err := parseChangePage(body)
+\tif err != nil {{
+\t\treturn 0, false, err
+\t}}
+
+\tfor _, event := range events {{
+\t\tkey := event.key()
+\t\tif s.seen[key] {{
+\t\t\tcontinue
+\t\t}}
+\t\tif err := s.dispatch(ctx, event); err != nil {{
+\t\t\tif saveErr := s.save(ctx); saveErr != nil {{
+\t\t\t\treturn dispatched, false, errors.Join(err, saveErr)
+\t\t\t}}
+\t\t\treturn dispatched, false, err
+\t\t}}
+\t\tdispatched++
+\t\ts.advance(key, event.Cursor)
+\t}}
+\tif next != "" {{
+\t\ts.advance("", next)
+\t}}
+\treturn dispatched, more, s.save(ctx)
+}}
+
+func (s *Subscription) dispatch(ctx context.Context, event ChangeEvent) error {{
+\thandlers := s.handlers[event.Type]
+\tif len(handlers) == 0 {{
+\t\thandlers = s.fallback
+\t}}
+\tfor _, fn := range handlers {{
+\t\tif err := s.handle(ctx, fn, event); err != nil {{
+\t\t\treturn fmt.Errorf("subscription %s: %s event %s: %w", s.name, event.Type, event.ID, err)
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+func (s *Subscription) handle(ctx context.Context, fn ChangeHandler, event ChangeEvent) (err error) {{
+\tdefer recoverPanic("subscription "+s.name+": "+event.Type, 1, &err)
+\treturn fn(ctx, event)
+}}
+
+// advance moves the checkpoint to cursor, remembering the delivered event
+// key unless it is empty.
+func (s *Subscription) advance(key, cursor string) {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tif cursor != "" {{
+\t\ts.checkpoint.Cursor = cursor
+\t}}
+\tif key == "" {{
+\t\treturn
+\t}}
+\ts.seen[key] = true
+\ts.checkpoint.Seen = append(s.checkpoint.Seen, key)
+\tif len(s.checkpoint.Seen) > changeFeedSeen {{
+\t\tdelete(s.seen, s.checkpoint.Seen[0])
+\t\ts.checkpoint.Seen = s.checkpoint.Seen[1:]
+\t}}
+}}
+
+// load restores the saved checkpoint on the first poll.
+func (s *Subscription) load(ctx context.Context) error {{
+\tif s.seen != nil {{
+\t\treturn nil
+\t}}
+\tif s.store != nil {{
+\t\tsaved, err := s.store.Load(ctx, s.name)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("subscription %s: %w", s.name, err)
+\t\t}}
+\t\tif saved != nil {{
+\t\t\ts.checkpoint = saved
+\t\t}}
+\t}}
+\tif s.checkpoint == nil {{
+\t\ts.checkpoint = &Checkpoint{{Name: s.name}}
+\t}}
+\ts.seen = make(map[string]bool, len(s.checkpoint.Seen))
+\tfor _, key := range s.checkpoint.Seen {{
+\t\ts.seen[key] = true
+\t}}
+\treturn nil
+}}
+
+func (s *Subscription) save(ctx context.Context) error {{
+\tif s.store == nil {{
+\t\treturn nil
+\t}}
+\tcheckpoint := s.Checkpoint()
+\tif err := s.store.Save(ctx, &checkpoint); err != nil {{
+\t\treturn fmt.Errorf("subscription %s: %w", s.name, err)
+\t}}
+\treturn nil
+}}
+
+// parseChangePage splits a feed response into its events, the next cursor
+// it reports, and whether it says more events are waiting (true when it
+// does not say).
+func parseChangePage(body []byte) (events []ChangeEvent, next string, more bool, err error) {{
+\tvar items []json.RawMessage
+\tmore = true
+\tif changeFeedEventsField == "" {{
+\t\terr = json.Unmarshal(body, &items)
+\t}} else {{
+\t\tvar envelope map[string]json.RawMessage
+\t\tif err = json.Unmarshal(body, &envelope); err == nil {{
+\t\t\tif raw, ok := envelope[changeFeedEventsField]; ok && string(raw) != "null" {{
+\t\t\t\terr = json.Unmarshal(raw, &items)
+\t\t\t}}
+\t\t\tif raw, ok := envelope[changeFeedNextField]; ok && changeFeedNextField != "" {{
+\t\t\t\tnext = scalarString(raw)
+\t\t\t}}
+\t\t\tif raw, ok := envelope[changeFeedMoreField]; ok && changeFeedMoreField != "" {{
+\t\t\t\tmore = string(raw) == "true"
+\t\t\t}}
+\t\t}}
+\t}}
+\tif err != nil {{
+\t\treturn nil, "", false, fmt.Errorf("change feed: %w", err)
+\t}}
+\tfor _, item := range items {{
+\t\tvar fields map[string]json.RawMessage
+\t\tif err := json.Unmarshal(item, &fields); err != nil {{
+\t\t\treturn nil, "", false, fmt.Errorf("change feed: %w", err)
+\t\t}}
+\t\tevents = append(events, ChangeEvent{{
+\t\t\tID:     scalarString(fields[changeFeedIDField]),
+\t\t\tType:   scalarString(fields[changeFeedTypeField]),
+\t\t\tCursor: scalarString(fields[changeFeedCursorField]),
+\t\t\tRaw:    item,
+\t\t}})
+\t}}
+\treturn events, next, more, nil
+}}
+
+// scalarString returns a JSON string's value or a number's text; other
+// values are empty.
+func scalarString(raw json.RawMessage) string {{
+\tvar s string
+\tif json.Unmarshal(raw, &s) == nil {{
+\t\treturn s
+\t}}
+\tvar n json.Number
+\tif json.Unmarshal(raw, &n) == nil {{
+\t\treturn n.String()
+\t}}
+\treturn ""
+}}
+"""
+    
+    def _generate_go_checkpoint(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"os"
+\t"path/filepath"
+)
+
+// Checkpoint is the saved position of a change feed subscription or a
+// sync.
+type Checkpoint struct {{
+\tName   string   `json:"name"`
+\tCursor string   `json:"cursor"`
+\tSeen   []string `json:"seen,omitempty"`
+}}
+
+// CheckpointStore persists checkpoints so a restarted process resumes where
+// it stopped. Load returns nil for an unknown name.
+type CheckpointStore interface {{
+\tLoad(ctx context.Context, name string) (*Checkpoint, error)
+\tSave(ctx context.Context, checkpoint *Checkpoint) error
+}}
+
+// FileCheckpointStore keeps each checkpoint in Dir as <name>.json.
+type FileCheckpointStore struct {{
+\tDir string
+}}
+
+// Load implements CheckpointStore.
+func (f FileCheckpointStore) Load(_ context.Context, name string) (*Checkpoint, error) {{
+\tdata, err := os.ReadFile(f.path(name))
+\tif errors.Is(err, os.ErrNotExist) {{
+\t\treturn nil, nil
+\t}}
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar checkpoint Checkpoint
+\tif err := json.Unmarshal(data, &checkpoint); err != nil {{
+\t\treturn nil, fmt.Errorf("checkpoint %s: %w", name, err)
+\t}}
+\treturn &checkpoint, nil
+}}
+
+// Save implements CheckpointStore, replacing the file atomically.
+func (f FileCheckpointStore) Save(_ context.Context, checkpoint *Checkpoint) error {{
+\tdata, err := json.MarshalIndent(checkpoint, "", "  ")
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif err := os.MkdirAll(f.Dir, 0o700); err != nil {{
+\t\treturn err
+\t}}
+\ttmp := f.path(checkpoint.Name) + ".tmp"
+\tif err := os.WriteFile(tmp, data, 0o600); err != nil {{
+\t\treturn err
+\t}}
+\treturn os.Rename(tmp, f.path(checkpoint.Name))
+}}
+
+func (f FileCheckpointStore) path(name string) string {{
+\treturn filepath.Join(f.Dir, filepath.Base(name)+".json")
+}}
+"""
+    
+    def _generate_go_workflow(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"os"
+\t"path/filepath"
+\t"strings"
+)
+
+// Workflow runs dependent calls as named steps (create user, then profile,
+// then role). When a step fails, the steps already done are compensated in
+// reverse order, since the API offers no transactions. With a
+// WorkflowStore, progress is saved after every step, so a run interrupted
+// by a crash or a cancelled context resumes where it stopped:
+//
+//\twf := NewWorkflow("onboard").
+//\t\tStep("user", createUser, deleteUser).
+//\t\tStep("profile", createProfile, nil)
+//\tstate, err := wf.Run(ctx, "onboard-alice")
+type Workflow struct {{
+\tname  string
+\tsteps []workflowStep
+\tstore WorkflowStore
+}}
+
+// WorkflowFunc performs or compensates a step. Steps pass values, such as
+// the IDs of created resources, to later steps and to compensations through
+// state.
+type WorkflowFunc func(ctx context.Context, state *WorkflowState) error
+
+type workflowStep struct {{
+\tname       string
+\tdo         WorkflowFunc
+\tcompensate WorkflowFunc
+}}
+
+// WorkflowState is the progress of one run, JSON-encoded by stores.
+type WorkflowState struct {{
+\tID        string                     `json:"id"`
+\tCompleted []string                   `json:"completed"`
+\tValues    map[string]json.RawMessage `json:"values,omitempty"`
+\t// Compensating is set once a step has failed; resuming continues
+\t// undoing the completed steps rather than running new ones, and a fully
+\t// compensated run keeps returning its failure.
+\tCompensating bool   `json:"compensating,omitempty"`
+\tFailed       string `json:"failed,omitempty"`
+\tError        string `json:"error,omitempty"`
+\tDone         bool   `json:"done,omitempty"`
+}}
+
+// Set stores a JSON-encodable value for later steps.
+func (s *WorkflowState) Set(key string, value interface{{}}) error {{
+\tdata, err := json.Marshal(value)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif s.Values == nil {{
+\t\ts.Values = make(map[string]json.RawMessage)
+\t}}
+\ts.Values[key] = data
+\treturn nil
+}}
+
+// Get decodes the value stored under key into v.
+func (s *WorkflowState) Get(key string, v interface{{}}) error {{
+\tdata, ok := s.Values[key]
+\tif !ok {{
+\t\treturn fmt.Errorf("workflow %s: no value %q", s.ID, key)
+\t}}
+\treturn json.Unmarshal(data, v)
+}}
+
+// WorkflowStore persists workflow progress. Load returns a nil state for
+// an unknown ID.
+type WorkflowStore interface {{
+\tLoad(ctx context.Context, id string) (*WorkflowState, error)
+\tSave(ctx context.Context, state *WorkflowState) error
+}}
+
+// FileWorkflowStore keeps each run's state in Dir as <id>.json.
+type FileWorkflowStore struct {{
+\tDir string
+}}
+
+// Load implements WorkflowStore.
+func (f FileWorkflowStore) Load(_ context.Context, id string) (*WorkflowState, error) {{
+\tdata, err := os.ReadFile(f.path(id))
+\tif errors.Is(err, os.ErrNotExist) {{
+\t\treturn nil, nil
+\t}}
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar state WorkflowState
+\tif err := json.Unmarshal(data, &state); err != nil {{
+\t\treturn nil, fmt.Errorf("workflow %s: %w", id, err)
+\t}}
+\treturn &state, nil
+}}
+
+// Save implements WorkflowStore, replacing the file atomically.
+func (f FileWorkflowStore) Save(_ context.Context, state *WorkflowState) error {{
+\tdata, err := json.MarshalIndent(state, "", "  ")
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif err := os.MkdirAll(f.Dir, 0o700); err != nil {{
+\t\treturn err
+\t}}
+\ttmp := f.path(state.ID) + ".tmp"
+\tif err := os.WriteFile(tmp, data, 0o600); err != nil {{
+\t\treturn err
+\t}}
+\treturn os.Rename(tmp, f.path(state.ID))
+}}
+
+func (f FileWorkflowStore) path(id string) string {{
+\treturn filepath.Join(f.Dir, filepath.Base(id)+".json")
+}}
+
+// WorkflowError reports the step that failed a run. Compensations that
+// failed too are listed; their steps' effects were left in place.
+type WorkflowError struct {{
+\tWorkflow      string
+\tStep          string
+\tErr           error
+\tCompensations []error
+}}
+
+func (e *WorkflowError) Error() string {{
+\tmsg := fmt.Sprintf("workflow %s: step %s: %v", e.Workflow, e.Step, e.Err)
+\tif len(e.Compensations) > 0 {{
+\t\tfailed := make([]string, len(e.Compensations))
+\t\tfor i, err := range e.Compensations {{
+\t\t\tfailed[i] = err.Error()
+\t\t}}
+\t\tmsg += fmt.Sprintf(" (compensation failed: %s)", strings.Join(failed, "; "))
+\t}}
+\treturn msg
+}}
+
+func (e *WorkflowError) Unwrap() error {{
+\treturn e.Err
+}}
+
+// NewWorkflow starts an empty workflow.
+func NewWorkflow(name string) *Workflow {{
+\treturn &Workflow{{name: name}}
+}}
+
+// Step appends a step. compensate undoes do and may be nil for steps with
+// nothing to undo, such as reads.
+func (w *Workflow) Step(name string, do, compensate WorkflowFunc) *Workflow {{
+\tw.steps = append(w.steps, workflowStep{{name: name, do: do, compensate: compensate}})
+\treturn w
+}}
+
+// WithStore saves progress to store after every step and resumes runs
+// found there.
+func (w *Workflow) WithStore(store WorkflowStore) *Workflow {{
+\tw.store = store
+\treturn w
+}}
+
+// Run runs the workflow under id, resuming the stored state if there is
+// one; a finished run returns at once. A failing step triggers compensation
+// and a *WorkflowError, and retrying needs a new id. A cancelled context
+// stops the run without compensating, so it can be resumed.
+func (w *Workflow) Run(ctx context.Context, id string) (*WorkflowState, error) {{
+\tstate := &WorkflowState{{ID: id}}
+\tif w.store != nil {{
+\t\tstored, err := w.store.Load(ctx, id)
+\t\tif err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\tif stored != nil {{
+\t\t\tstate = stored
+\t\t}}
+\t}}
+\tif state.Done {{
+\t\treturn state, nil
+\t}}
+\tif state.Compensating {{
+\t\treturn state, w.compensate(ctx, state, errors.New(state.Error))
+\t}}
+
+\tcompleted := make(map[string]bool, len(state.Completed))
+\tfor _, name := range state.Completed {{
+\t\tcompleted[name] = true
+\t}}
+\tfor _, step := range w.steps {{
+\t\tif completed[step.name] {{
+\t\t\tcontinue
+\t\t}}
+\t\tif err := ctx.Err(); err != nil {{
+\t\t\treturn state, err
+\t\t}}
+\t\tif err := w.runStep(ctx, step.name, step.do, state); err != nil {{
+\t\t\tif ctx.Err() != nil {{
+\t\t\t\treturn state, err
+\t\t\t}}
+\t\t\tstate.Compensating, state.Failed, state.Error = true, step.name, err.Error()
+\t\t\treturn state, w.compensate(ctx, state, err)
+\t\t}}
+\t\tstate.Completed = append(state.Completed, step.name)
+\t\tif err := w.save(ctx, state); err != nil {{
+\t\t\treturn state, err
+\t\t}}
+\t}}
+\tstate.Done = true
+\treturn state, w.save(ctx, state)
+}}
+
+// compensate undoes the completed steps, newest first, saving progress so
+// an interrupted compensation resumes too.
+func (w *Workflow) compensate(ctx context.Context, state *WorkflowState, cause error) error {{
+\twerr := &WorkflowError{{Workflow: w.name, Step: state.Failed, Err: cause}}
+\tif err := w.save(ctx, state); err != nil {{
+\t\twerr.Compensations = append(werr.Compensations, err)
+\t}}
+\tfor len(state.Completed) > 0 {{
+\t\tname := state.Completed[len(state.Completed)-1]
+\t\tif step := w.step(name); step != nil && step.compensate != nil {{
+\t\t\tif err := w.runStep(ctx, "compensate "+name, step.compensate, state); err != nil {{
+\t\t\t\twerr.Compensations = append(werr.Compensations, fmt.Errorf("%s: %w", name, err))
+\t\t\t}}
+\t\t}}
+\t\tstate.Completed = state.Completed[:len(state.Completed)-1]
+\t\tif err := w.save(ctx, state); err != nil {{
+\t\t\twerr.Compensations = append(werr.Compensations, err)
+\t\t}}
+\t}}
+\treturn werr
+}}
+
+// runStep calls fn, recovering panics.
+func (w *Workflow) runStep(ctx context.Context, name string, fn WorkflowFunc, state *WorkflowState) (err error) {{
+\tdefer recoverPanic("workflow "+w.name+": "+name, 1, &err)
+\treturn fn(ctx, state)
+}}
+
+func (w *Workflow) step(name string) *workflowStep {{
+\tfor i := range w.steps {{
+\t\tif w.steps[i].name == name {{
+\t\t\treturn &w.steps[i]
+\t\t}}
+\t}}
+\treturn nil
+}}
+
+func (w *Workflow) save(ctx context.Context, state *WorkflowState) error {{
+\tif w.store == nil {{
+\t\treturn nil
+\t}}
+\treturn w.store.Save(ctx, state)
+}}
+"""
+    
+    def _detect_token_endpoint(self) -> Optional[str]:
+        """The path of a captured OAuth2 token endpoint: a POST answered with
+        an access_token, or None."""
+        for endpoint in self.endpoints.values():
+            properties = endpoint.response_schemas.get(200, {}).get('properties', {})
+            if endpoint.method == 'POST' and not endpoint.path_params and 'access_token' in properties:
+                return endpoint.path_pattern
+        return None
+    
+    def _generate_go_client_credentials(self, token_path: Optional[str]) -> str:
+        """WithClientCredentials, for an API whose token endpoint was captured."""
+        if not token_path:
+            return ''
+        return f"""
+// tokenPath is the token endpoint seen in the captured traffic.
+const tokenPath = "{token_path}"
+
+// WithClientCredentials authenticates with the client credentials grant
+// against the API's token endpoint, POST {token_path}, requested with the
+// client's base URL and HTTP client.
+func WithClientCredentials(clientID, clientSecret string, scopes ...string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tWithClientCredentialsConfig(&ClientCredentials{{
+\t\t\tTokenURL:     c.BaseURL + tokenPath,
+\t\t\tClientID:     clientID,
+\t\t\tClientSecret: clientSecret,
+\t\t\tScopes:       scopes,
+\t\t\tHTTPClient:   c.HTTPClient,
+\t\t}})(c)
+\t}}
+}}
+"""
+    
+    def _go_readme_client_credentials(self) -> str:
+        """The README note on WithClientCredentials, when it is generated."""
+        token_path = self._detect_token_endpoint()
+        if not token_path:
+            return ''
+        return f"""
+The captured traffic includes the API's token endpoint, `POST {token_path}`,
+so `WithClientCredentials` needs only the client's ID, secret, and scopes:
+
+```go
+client := New{self.class_name}Client("", WithClientCredentials(clientID, clientSecret, "read"))
+```
+"""
+    
+    def _go_readme_envelopes(self) -> str:
+        """The README section on response envelopes, when any were inferred."""
+        endpoint = next((endpoint for endpoint in self.endpoints.values()
+                         if self._go_envelope(endpoint) and self._go_envelope_meta(endpoint)['properties']), None)
+        if not endpoint:
+            return ''
+        name, field = self._go_name(endpoint), self._go_envelope(endpoint)
+        return f"""
+## Response Envelopes
+
+Operations whose captured responses wrap their payload in an envelope, next
+to metadata such as `{{"data": ..., "meta": ...}}`, return the payload itself,
+as `{name}` returns the `{field}` of `{endpoint.method} {endpoint.path_pattern}`. Each has a
+`WithMeta` variant returning the envelope's other fields too:
+
+```go
+result, meta, err := client.{name}WithMeta(ctx, ...)
+```
+
+`envelope.go` lists the enveloped operations, so batches and paginators
+unwrap them the same way.
+"""
+    
+    def _generate_go_oauth(self, package_name: str, token_path: Optional[str]) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"net/url"
+\t"strings"
+\t"sync"
+\t"time"
+)
+
+// Token is an OAuth2 access token. Its fields are those of
+// golang.org/x/oauth2's Token.
+type Token struct {{
+\tAccessToken  string
+\tTokenType    string // "Bearer" when empty
+\tRefreshToken string
+\tExpiry       time.Time // zero if the token does not expire
+}}
+
+// header is the Authorization header value for t.
+func (t *Token) header() string {{
+\ttokenType := t.TokenType
+\tif tokenType == "" || strings.EqualFold(tokenType, "bearer") {{
+\t\ttokenType = "Bearer"
+\t}}
+\treturn tokenType + " " + t.AccessToken
+}}
+
+// TokenSource supplies OAuth2 tokens. It has the shape of
+// golang.org/x/oauth2's TokenSource, which adapts with TokenSourceFunc:
+//
+//\tWithTokenSource(TokenSourceFunc(func() (*Token, error) {{
+//\t\tt, err := config.TokenSource(ctx).Token()
+//\t\tif err != nil {{
+//\t\t\treturn nil, err
+//\t\t}}
+//\t\treturn &Token{{AccessToken: t.AccessToken, TokenType: t.TokenType, Expiry: t.Expiry}}, nil
+//\t}}))
+type TokenSource interface {{
+\tToken() (*Token, error)
+}}
+
+// TokenSourceFunc adapts a function to TokenSource.
+type TokenSourceFunc func() (*Token, error)
+
+// Token implements TokenSource.
+func (f TokenSourceFunc) Token() (*Token, error) {{
+\treturn f()
+}}
+
+// WithTokenSource sends the tokens of src in the Authorization header. A
+// token is reused until it is within 30 seconds of expiring or the API
+// rejects it with a 401, and src is then asked for a new one.
+func WithTokenSource(src TokenSource) ClientOption {{
+\treturn withTokens(func(context.Context) (*Token, error) {{
+\t\treturn src.Token()
+\t}})
+}}
+
+// WithClientCredentialsConfig authenticates with tokens from config,
+// requested with the context of the call needing one.
+func WithClientCredentialsConfig(config *ClientCredentials) ClientOption {{
+\treturn withTokens(config.Token)
+}}
+
+func withTokens(fetch func(ctx context.Context) (*Token, error)) ClientOption {{
+\tcredential := &secretCredential{{secrets: &reuseTokenSource{{fetch: fetch}}, name: "token", header: "Authorization"}}
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.credentials = append(c.credentials, credential)
+\t}}
+}}
+
+// tokenLeeway is how long before it expires a token is replaced.
+const tokenLeeway = 30 * time.Second
+
+// reuseTokenSource caches the current token of a TokenSource.
+type reuseTokenSource struct {{
+\tfetch func(ctx context.Context) (*Token, error)
+
+\tmu    sync.Mutex
+\ttoken *Token
+}}
+
+// Secret returns the Authorization header value of the current token,
+// fetching a new one if needed.
+func (r *reuseTokenSource) Secret(ctx context.Context, _ string) (string, error) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tif r.token == nil || !r.token.Expiry.IsZero() && time.Until(r.token.Expiry) <= tokenLeeway {{
+\t\ttoken, err := r.fetch(ctx)
+\t\tif err != nil {{
+\t\t\treturn "", err
+\t\t}}
+\t\tif token == nil || token.AccessToken == "" {{
+\t\t\treturn "", fmt.Errorf("token source returned no access token")
+\t\t}}
+\t\tr.token = token
+\t}}
+\treturn r.token.header(), nil
+}}
+
+// expire drops the current token if it is the rejected one.
+func (r *reuseTokenSource) expire(_, rejected string) {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tif r.token != nil && r.token.header() == rejected {{
+\t\tr.token = nil
+\t}}
+}}
+
+// ClientCredentials fetches tokens with the OAuth2 client credentials grant
+// (RFC 6749, section 4.4), like golang.org/x/oauth2/clientcredentials.
+type ClientCredentials struct {{
+\tTokenURL     string
+\tClientID     string
+\tClientSecret string
+\tScopes       []string
+\t// EndpointParams are sent with every token request, e.g. an audience.
+\tEndpointParams url.Values
+\t// AuthInParams sends the client ID and secret in the request body
+\t// rather than with HTTP Basic authentication.
+\tAuthInParams bool
+\t// HTTPClient makes the token requests; nil means http.DefaultClient.
+\tHTTPClient *http.Client
+}}
+
+// TokenError is a token endpoint's refusal to issue a token.
+type TokenError struct {{
+\tStatusCode  int
+\tCode        string // the OAuth2 error code, e.g. "invalid_client"
+\tDescription string
+}}
+
+func (e *TokenError) Error() string {{
+\tif e.Description != "" {{
+\t\treturn fmt.Sprintf("token endpoint: status=%d, error=%s: %s", e.StatusCode, e.Code, e.Description)
+\t}}
+\treturn fmt.Sprintf("token endpoint: status=%d, error=%s", e.StatusCode, e.Code)
+}}
+
+// TokenSource returns a TokenSource requesting tokens with ctx.
+func (c *ClientCredentials) TokenSource(ctx context.Context) TokenSource {{
+\treturn TokenSourceFunc(func() (*Token, error) {{
+\t\treturn c.Token(ctx)
+\t}})
+}}
+
+// Token requests a new token.
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {{
+\tform := url.Values{{"grant_type": {{"client_credentials"}}}}
+\tif len(c.Scopes) > 0 {{
+\t\tform.Set("scope", strings.Join(c.Scopes, " "))
+\t}}
+\tfor key, values := range c.EndpointParams {{
+\t\tform[key] = values
+\t}}
+\tif c.AuthInParams {{
+\t\tform.Set("client_id", c.ClientID)
+\t\tform.Set("client_secret", c.ClientSecret)
+\t}}
+\treq, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(form.Encode()))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\treq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+\treq.Header.Set("Accept", "application/json")
+\tif !c.AuthInParams {{
+\t\treq.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+\t}}
+
+\tclient := c.HTTPClient
+\tif client == nil {{
+\t\tclient = http.DefaultClient
+\t}}
+\tresp, err := client.Do(req)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tdefer resp.Body.Close()
+\tbody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+
+\tvar payload struct {{
+\t\tAccessToken      string `json:"access_token"`
+\t\tTokenType        string `json:"token_type"`
+\t\tRefreshToken     string `json:"refresh_token"`
+\t\tExpiresIn        int64  `json:"expires_in"`
+\t\tError            string `json:"error"`
+\t\tErrorDescription string `json:"error_description"`
+\t}}
+\tdecodeErr := json.Unmarshal(body, &payload)
+\tif resp.StatusCode >= 400 || payload.Error != "" {{
+\t\tcode := payload.Error
+\t\tif code == "" {{
+\t\t\tcode = http.StatusText(resp.StatusCode)
+\t\t}}
+\t\treturn nil, &TokenError{{StatusCode: resp.StatusCode, Code: code, Description: payload.ErrorDescription}}
+\t}}
+\tif decodeErr != nil {{
+\t\treturn nil, fmt.Errorf("token endpoint: %w", decodeErr)
+\t}}
+\ttoken := &Token{{AccessToken: payload.AccessToken, TokenType: payload.TokenType, RefreshToken: payload.RefreshToken}}
+\tif payload.ExpiresIn > 0 {{
+\t\ttoken.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+\t}}
+\treturn token, nil
+}}
+{self._generate_go_client_credentials(token_path)}"""
+    
+    def _generate_go_tokencache(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"os"
+\t"path/filepath"
+\t"sync"
+\t"time"
+)
+
+// TokenRefresher obtains a fresh token, e.g. from an OAuth token endpoint,
+// with its expiry; a zero expiry keeps the token until the API rejects it.
+type TokenRefresher func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// SharedTokenCache holds one token per credential key for every client
+// using it. Concurrent refreshes of a key collapse into one, so an
+// application building many short-lived clients refreshes once rather than
+// once per client; with a Store, processes share tokens too. It is safe for
+// concurrent use.
+type SharedTokenCache struct {{
+\t// Store, if set, shares tokens with other processes.
+\tStore TokenStore
+\t// Leeway refreshes tokens this long before they expire; zero means 30
+\t// seconds.
+\tLeeway time.Duration
+
+\tmu      sync.Mutex
+\tentries map[string]*sharedToken
+}}
+
+// DefaultTokenCache is the process-wide cache WithSharedToken uses when
+// given a nil cache.
+var DefaultTokenCache = &SharedTokenCache{{}}
+
+type sharedToken struct {{
+\tmu      sync.Mutex // held while refreshing
+\trefresh TokenRefresher
+\ttoken   string
+\texpiry  time.Time
+}}
+
+// TokenStore shares tokens between processes. Lock serializes refreshes of
+// a key across processes.
+type TokenStore interface {{
+\tLoad(key string) (token string, expiry time.Time, err error)
+\tSave(key, token string, expiry time.Time) error
+\tLock(ctx context.Context, key string) (unlock func(), err error)
+}}
+
+// WithSharedToken sends the token cache holds for key as the bearer token,
+// refreshing it with refresh when it is missing, about to expire, or
+// rejected with a 401. Clients passing the same cache and key share one
+// token; a nil cache means DefaultTokenCache.
+func WithSharedToken(cache *SharedTokenCache, key string, refresh TokenRefresher) ClientOption {{
+\tif cache == nil {{
+\t\tcache = DefaultTokenCache
+\t}}
+\tcache.register(key, refresh)
+\tcredential := &secretCredential{{secrets: cache, name: key, header: "Authorization", prefix: "Bearer "}}
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.credentials = append(c.credentials, credential)
+\t}}
+}}
+
+// register records the refresher for key; the first one registered wins.
+func (c *SharedTokenCache) register(key string, refresh TokenRefresher) {{
+\tc.mu.Lock()
+\tdefer c.mu.Unlock()
+\tif c.entries == nil {{
+\t\tc.entries = make(map[string]*sharedToken)
+\t}}
+\tif _, ok := c.entries[key]; !ok {{
+\t\tc.entries[key] = &sharedToken{{refresh: refresh}}
+\t}}
+}}
+
+func (c *SharedTokenCache) entry(key string) (*sharedToken, error) {{
+\tc.mu.Lock()
+\tdefer c.mu.Unlock()
+\tentry, ok := c.entries[key]
+\tif !ok {{
+\t\treturn nil, fmt.Errorf("shared token %q: no refresher registered", key)
+\t}}
+\treturn entry, nil
+}}
+
+// valid reports whether a token is usable for at least the leeway.
+func (c *SharedTokenCache) valid(token string, expiry time.Time) bool {{
+\tleeway := c.Leeway
+\tif leeway == 0 {{
+\t\tleeway = 30 * time.Second
+\t}}
+\treturn token != "" && (expiry.IsZero() || time.Until(expiry) > leeway)
+}}
+
+// Secret returns the token for key, refreshing it if needed.
+func (c *SharedTokenCache) Secret(ctx context.Context, key string) (string, error) {{
+\tentry, err := c.entry(key)
+\tif err != nil {{
+\t\treturn "", err
+\t}}
+\tentry.mu.Lock()
+\tdefer entry.mu.Unlock()
+\tif c.valid(entry.token, entry.expiry) {{
+\t\treturn entry.token, nil
+\t}}
+\tif c.Store == nil {{
+\t\treturn c.refresh(ctx, key, entry)
+\t}}
+
+\t// Another process may have refreshed already; check before and after
+\t// taking the cross-process lock.
+\tif token, expiry, err := c.Store.Load(key); err == nil && c.valid(token, expiry) {{
+\t\tentry.token, entry.expiry = token, expiry
+\t\treturn token, nil
+\t}}
+\tunlock, err := c.Store.Lock(ctx, key)
+\tif err != nil {{
+\t\treturn "", fmt.Errorf("shared token %q: %w", key, err)
+\t}}
+\tdefer unlock()
+\tif token, expiry, err := c.Store.Load(key); err == nil && c.valid(token, expiry) {{
+\t\tentry.token, entry.expiry = token, expiry
+\t\treturn token, nil
+\t}}
+\ttoken, err := c.refresh(ctx, key, entry)
+\tif err != nil {{
+\t\treturn "", err
+\t}}
+\tif err := c.Store.Save(key, entry.token, entry.expiry); err != nil {{
+\t\treturn "", fmt.Errorf("shared token %q: %w", key, err)
+\t}}
+\treturn token, nil
+}}
+
+func (c *SharedTokenCache) refresh(ctx context.Context, key string, entry *sharedToken) (string, error) {{
+\ttoken, expiry, err := entry.refresh(ctx)
+\tif err != nil {{
+\t\treturn "", fmt.Errorf("shared token %q: refresh: %w", key, err)
+\t}}
+\tentry.token, entry.expiry = token, expiry
+\treturn token, nil
+}}
+
+// expire drops the token for key if it is still the rejected one, so the
+// 401s of calls that raced a refresh do not discard the new token.
+func (c *SharedTokenCache) expire(key, rejected string) {{
+\tentry, err := c.entry(key)
+\tif err != nil {{
+\t\treturn
+\t}}
+\tentry.mu.Lock()
+\tdefer entry.mu.Unlock()
+\tif entry.token == rejected {{
+\t\tentry.token, entry.expiry = "", time.Time{{}}
+\t}}
+\tif c.Store == nil {{
+\t\treturn
+\t}}
+\tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+\tdefer cancel()
+\tunlock, err := c.Store.Lock(ctx, key)
+\tif err != nil {{
+\t\treturn
+\t}}
+\tdefer unlock()
+\tif token, _, err := c.Store.Load(key); err == nil && token == rejected {{
+\t\tc.Store.Save(key, "", time.Time{{}})
+\t}}
+}}
+
+// FileTokenStore is a TokenStore keeping each key's token in Dir as
+// <key>.json, readable only by the current user, and locking with
+// <key>.lock files. Locks older than StaleLock (default 30 seconds) are
+// assumed abandoned by a crashed process.
+type FileTokenStore struct {{
+\tDir       string
+\tStaleLock time.Duration
+}}
+
+type storedToken struct {{
+\tToken  string    `json:"token"`
+\tExpiry time.Time `json:"expiry"`
+}}
+
+// Load implements TokenStore; a missing file is an empty token.
+func (f FileTokenStore) Load(key string) (string, time.Time, error) {{
+\tdata, err := os.ReadFile(f.path(key, ".json"))
+\tif errors.Is(err, os.ErrNotExist) {{
+\t\treturn "", time.Time{{}}, nil
+\t}}
+\tif err != nil {{
+\t\treturn "", time.Time{{}}, err
+\t}}
+\tvar stored storedToken
+\tif err := json.Unmarshal(data, &stored); err != nil {{
+\t\treturn "", time.Time{{}}, err
+\t}}
+\treturn stored.Token, stored.Expiry, nil
+}}
+
+// Save implements TokenStore, replacing the file atomically.
+func (f FileTokenStore) Save(key, token string, expiry time.Time) error {{
+\tdata, err := json.Marshal(storedToken{{Token: token, Expiry: expiry}})
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif err := os.MkdirAll(f.Dir, 0o700); err != nil {{
+\t\treturn err
+\t}}
+\ttmp, err := os.CreateTemp(f.Dir, filepath.Base(key)+".*.tmp")
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tdefer os.Remove(tmp.Name())
+\tif _, err := tmp.Write(data); err != nil {{
+\t\ttmp.Close()
+\t\treturn err
+\t}}
+\tif err := tmp.Close(); err != nil {{
+\t\treturn err
+\t}}
+\treturn os.Rename(tmp.Name(), f.path(key, ".json"))
+}}
+
+// Lock implements TokenStore, polling until the lock file can be created.
+func (f FileTokenStore) Lock(ctx context.Context, key string) (func(), error) {{
+\tif err := os.MkdirAll(f.Dir, 0o700); err != nil {{
+\t\treturn nil, err
+\t}}
+\tstale := f.StaleLock
+\tif stale == 0 {{
+\t\tstale = 30 * time.Second
+\t}}
+\tpath := f.path(key, ".lock")
+\tfor {{
+\t\tfile, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+\t\tif err == nil {{
+\t\t\tfile.Close()
+\t\t\treturn func() {{ os.Remove(path) }}, nil
+\t\t}}
+\t\tif !errors.Is(err, os.ErrExist) {{
+\t\t\treturn nil, err
+\t\t}}
+\t\tif info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > stale {{
+\t\t\tos.Remove(path)
+\t\t\tcontinue
+\t\t}}
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\treturn nil, ctx.Err()
+\t\tcase <-time.After(50 * time.Millisecond):
+\t\t}}
+\t}}
+}}
+
+func (f FileTokenStore) path(key, ext string) string {{
+\treturn filepath.Join(f.Dir, filepath.Base(key)+ext)
+}}
+"""
+    
+    def _generate_go_envelope(self, package_name: str) -> str:
+        import json
+        fields = {f"{endpoint.method} {endpoint.path_pattern}": self._go_envelope(endpoint)
+                  for endpoint in self.endpoints.values() if self._go_envelope(endpoint)}
+        width = max((len(json.dumps(operation)) + 1 for operation in fields), default=0)
+        entries = ''.join(f'\t{(json.dumps(operation) + ":").ljust(width)} {json.dumps(field)},\n'
+                          for operation, field in sorted(fields.items()))
+        entries = '\n' + entries if entries else ''
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"encoding/json"
+\t"errors"
+)
+
+// responseEnvelopes maps the operations whose responses wrap their payload
+// in an envelope, next to metadata such as {{"data": ..., "meta": ...}}, to
+// the field holding the payload. Their methods return the payload; the
+// WithMeta variants return the other fields too.
+var responseEnvelopes = map[string]string{{{entries}}}
+
+// envelopePayload returns the payload of an enveloped response body, or
+// body itself when the operation's responses are not enveloped or this one
+// is not a JSON object. A missing payload is empty.
+func envelopePayload(operation string, body []byte) []byte {{
+\tfield, ok := responseEnvelopes[operation]
+\tif !ok {{
+\t\treturn body
+\t}}
+\tvar envelope map[string]json.RawMessage
+\tif err := json.Unmarshal(body, &envelope); err != nil {{
+\t\treturn body
+\t}}
+\treturn envelope[field]
+}}
+
+// recordEnvelope stores the fields of the call's response envelope besides
+// its payload in meta, a pointer to the operation's Meta struct.
+func recordEnvelope(meta interface{{}}) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\to.envelope = meta
+\t}}
+}}
+
+// decodeEnvelopeMeta decodes the fields of an enveloped response body into
+// meta, which has no field for the payload. Like decode, it leaves fields
+// of an unexpected type at their zero value.
+func (c *{self.class_name}Client) decodeEnvelopeMeta(operation string, body []byte, meta interface{{}}) error {{
+\tif _, ok := responseEnvelopes[operation]; !ok || len(bytes.TrimSpace(body)) == 0 {{
+\t\treturn nil
+\t}}
+\terr := json.Unmarshal(body, meta)
+\tvar typeErr *json.UnmarshalTypeError
+\tif err != nil && !errors.As(err, &typeErr) {{
+\t\treturn err
+\t}}
+\tc.applyTimeLayouts(operation, meta)
+\treturn nil
+}}
+"""
+    
+    def _generate_go_codec(self, package_name: str) -> str:
+        """The Codec behind JSON bodies, replaceable with a faster
+        implementation, and the decode modes choosing between dropping,
+        keeping, or failing on response fields the generated types lack."""
+        return f"""package {package_name}
+
+import (
+\t"encoding/json"
+\t"fmt"
+\t"reflect"
+\t"sort"
+\t"strings"
+)
+
+// Codec encodes and decodes JSON bodies. The default uses encoding/json;
+// sonic.ConfigStd, jsoniter.ConfigCompatibleWithStandardLibrary, and other
+// drop-in replacements implement it as they are. A codec that does not
+// return *json.UnmarshalTypeError for values of the wrong type fails those
+// calls instead of leaving the field at its zero value.
+type Codec interface {{
+\tMarshal(v interface{{}}) ([]byte, error)
+\tUnmarshal(data []byte, v interface{{}}) error
+}}
+
+// WithCodec encodes JSON request bodies and decodes JSON responses with
+// codec, including those of +json media types without a Serializer of
+// their own.
+func WithCodec(codec Codec) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.codec = codec
+\t}}
+}}
+
+// jsonCodec returns the codec for JSON bodies.
+func (c *{self.class_name}Client) jsonCodec() Codec {{
+\tif c.codec != nil {{
+\t\treturn c.codec
+\t}}
+\treturn JSONSerializer{{}}
+}}
+
+// DecodeMode chooses what decoding does with response fields the generated
+// types have no field for. The schema was inferred from captured traffic,
+// so responses gain fields over time.
+type DecodeMode int
+
+const (
+\t// DecodeLenient drops unknown fields, reporting them only as schema
+\t// mismatches. It is the default.
+\tDecodeLenient DecodeMode = iota
+\t// DecodeTolerant keeps unknown fields, undecoded, in the Extra map of
+\t// the struct they appear in.
+\tDecodeTolerant
+\t// DecodeStrict fails calls whose response has unknown fields with an
+\t// *UnknownFieldsError. The result is still decoded.
+\tDecodeStrict
+)
+
+// WithDecodeMode sets what decoding does with unknown response fields.
+func WithDecodeMode(mode DecodeMode) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.decodeMode = mode
+\t}}
+}}
+
+// UnknownFieldsError reports the response fields the generated types have
+// no field for, returned in DecodeStrict mode.
+type UnknownFieldsError struct {{
+\tOperation string   // "GET /v1/users/{{id}}"
+\tFields    []string // paths such as "users[].nickname"
+}}
+
+func (e *UnknownFieldsError) Error() string {{
+\treturn fmt.Sprintf("%s: unknown response fields: %s", e.Operation, strings.Join(e.Fields, ", "))
+}}
+
+// checkUnknownFields keeps or rejects the fields of a JSON body v has no
+// field for, as the client's DecodeMode says.
+func (c *{self.class_name}Client) checkUnknownFields(operation string, body []byte, v interface{{}}) error {{
+\tif c.decodeMode == DecodeLenient {{
+\t\treturn nil
+\t}}
+\tunknown := make(map[string]bool)
+\tcollectUnknownFields("", body, reflect.ValueOf(v), c.decodeMode == DecodeTolerant, unknown)
+\tif c.decodeMode != DecodeStrict || len(unknown) == 0 {{
+\t\treturn nil
+\t}}
+\tfields := make([]string, 0, len(unknown))
+\tfor field := range unknown {{
+\t\tfields = append(fields, field)
+\t}}
+\tsort.Strings(fields)
+\treturn &UnknownFieldsError{{Operation: operation, Fields: fields}}
+}}
+
+var rawFieldsType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// collectUnknownFields adds the paths of the fields of raw that v has no
+// field for to unknown. With keep, it also stores them in the Extra map of
+// the struct they appear in. Values with custom decoding are not entered.
+func collectUnknownFields(path string, raw json.RawMessage, v reflect.Value, keep bool, unknown map[string]bool) {{
+\tfor v.Kind() == reflect.Ptr {{
+\t\tif v.IsNil() {{
+\t\t\treturn
+\t\t}}
+\t\tv = v.Elem()
+\t}}
+\tif v.Type().Implements(unmarshalerType) || (v.CanAddr() && v.Addr().Type().Implements(unmarshalerType)) {{
+\t\treturn
+\t}}
+\tswitch v.Kind() {{
+\tcase reflect.Struct:
+\t\tvar object map[string]json.RawMessage
+\t\tif json.Unmarshal(raw, &object) != nil {{
+\t\t\treturn
+\t\t}}
+\t\tknown := make(map[string]bool, len(object))
+\t\tvar extra reflect.Value
+\t\tfor i := 0; i < v.NumField(); i++ {{
+\t\t\tfield := v.Type().Field(i)
+\t\t\tif field.Type == rawFieldsType && field.Tag.Get("json") == "-" {{
+\t\t\t\textra = v.Field(i)
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tname := jsonName(field)
+\t\t\tif name == "" {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tif key, found := lookupKey(object, name); found {{
+\t\t\t\tknown[key] = true
+\t\t\t\tcollectUnknownFields(joinPath(path, name), object[key], v.Field(i), keep, unknown)
+\t\t\t}}
+\t\t}}
+\t\tfor key, value := range object {{
+\t\t\tif known[key] {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tunknown[joinPath(path, key)] = true
+\t\t\tif keep && extra.IsValid() && extra.CanSet() {{
+\t\t\t\tif extra.IsNil() {{
+\t\t\t\t\textra.Set(reflect.MakeMap(rawFieldsType))
+\t\t\t\t}}
+\t\t\t\textra.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+\t\t\t}}
+\t\t}}
+\tcase reflect.Slice, reflect.Array:
+\t\tvar items []json.RawMessage
+\t\tif json.Unmarshal(raw, &items) != nil {{
+\t\t\treturn
+\t\t}}
+\t\tfor i := 0; i < v.Len() && i < len(items); i++ {{
+\t\t\tcollectUnknownFields(path+"[]", items[i], v.Index(i), keep, unknown)
+\t\t}}
+\tcase reflect.Map:
+\t\tvar object map[string]json.RawMessage
+\t\tif v.Type().Key().Kind() != reflect.String || json.Unmarshal(raw, &object) != nil {{
+\t\t\treturn
+\t\t}}
+\t\tfor key, value := range object {{
+\t\t\tmapKey := reflect.ValueOf(key).Convert(v.Type().Key())
+\t\t\tcurrent := v.MapIndex(mapKey)
+\t\t\tif !current.IsValid() {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\telem := reflect.New(current.Type()).Elem()
+\t\t\telem.Set(current)
+\t\t\tcollectUnknownFields(joinPath(path, key), value, elem, keep, unknown)
+\t\t\tif keep {{
+\t\t\t\tv.SetMapIndex(mapKey, elem)
+\t\t\t}}
+\t\t}}
+\t}}
+}}
+"""
+    
+    def _generate_go_serializer(self, package_name: str, request_types: Dict[str, str],
+                                response_types: Dict[str, str]) -> str:
+        import json
+        
+        def go_map(types):
+            width = max((len(json.dumps(operation)) + 1 for operation in types), default=0)
+            entries = ''.join(f'\t{(json.dumps(operation) + ":").ljust(width)} {json.dumps(media_type)},\n'
+                              for operation, media_type in sorted(types.items()))
+            return '\n' + entries if entries else ''
+        
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"encoding/json"
+\t"encoding/xml"
+\t"fmt"
+\t"io"
+\t"net/url"
+\t"sort"
+\t"strings"
+)
+
+// Serializer encodes request bodies and decodes response bodies for one
+// media type. Register implementations for msgpack or vendor media types
+// with WithSerializer.
+type Serializer interface {{
+\tMarshal(v interface{{}}) ([]byte, error)
+\tUnmarshal(data []byte, v interface{{}}) error
+}}
+
+// requestContentTypes maps the operations captured with request bodies in
+// another media type than JSON to that media type.
+var requestContentTypes = map[string]string{{{go_map(request_types)}}}
+
+// responseContentTypes maps the operations captured with structured response
+// bodies in another media type than JSON to that media type.
+var responseContentTypes = map[string]string{{{go_map(response_types)}}}
+
+// defaultSerializers are the serializers every client has; media types with
+// a +json or +xml suffix use the JSON and XML ones.
+var defaultSerializers = map[string]Serializer{{
+\t"application/json":                  JSONSerializer{{}},
+\t"application/x-www-form-urlencoded": FormSerializer{{}},
+\t"application/xml":                   XMLSerializer{{}},
+\t"text/xml":                          XMLSerializer{{}},
+}}
+
+// WithSerializer encodes and decodes mediaType bodies with s, replacing the
+// default for that media type.
+func WithSerializer(mediaType string, s Serializer) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tif c.serializers == nil {{
+\t\t\tc.serializers = make(map[string]Serializer)
+\t\t}}
+\t\tc.serializers[mediaType] = s
+\t}}
+}}
+
+// serializer returns the serializer for mediaType, with the client's Codec
+// in place of JSONSerializer.
+func (c *{self.class_name}Client) serializer(mediaType string) (Serializer, error) {{
+\ts, err := c.mediaSerializer(mediaType)
+\tif _, isJSON := s.(JSONSerializer); isJSON {{
+\t\treturn c.jsonCodec(), nil
+\t}}
+\treturn s, err
+}}
+
+// mediaSerializer returns the serializer registered for mediaType.
+func (c *{self.class_name}Client) mediaSerializer(mediaType string) (Serializer, error) {{
+\tif s, ok := c.serializers[mediaType]; ok {{
+\t\treturn s, nil
+\t}}
+\tif s, ok := defaultSerializers[mediaType]; ok {{
+\t\treturn s, nil
+\t}}
+\tswitch {{
+\tcase strings.HasSuffix(mediaType, "+json"):
+\t\treturn JSONSerializer{{}}, nil
+\tcase strings.HasSuffix(mediaType, "+xml"):
+\t\treturn XMLSerializer{{}}, nil
+\t}}
+\treturn nil, fmt.Errorf("no serializer for %s; register one with WithSerializer", mediaType)
+}}
+
+// responseSerializer returns the serializer for operation's responses, or
+// nil for JSON.
+func (c *{self.class_name}Client) responseSerializer(operation string) (Serializer, error) {{
+\tmediaType, ok := responseContentTypes[operation]
+\tif !ok {{
+\t\treturn nil, nil
+\t}}
+\ts, err := c.mediaSerializer(mediaType)
+\tif _, isJSON := s.(JSONSerializer); isJSON {{
+\t\treturn nil, nil
+\t}}
+\treturn s, err
+}}
+
+// JSONSerializer is the default Serializer.
+type JSONSerializer struct{{}}
+
+// Marshal implements Serializer.
+func (JSONSerializer) Marshal(v interface{{}}) ([]byte, error) {{
+\treturn json.Marshal(v)
+}}
+
+// Unmarshal implements Serializer.
+func (JSONSerializer) Unmarshal(data []byte, v interface{{}}) error {{
+\treturn json.Unmarshal(data, v)
+}}
+
+// FormSerializer encodes bodies as application/x-www-form-urlencoded, using
+// the JSON field names. Arrays become repeated fields and nested objects
+// JSON text.
+type FormSerializer struct{{}}
+
+// Marshal implements Serializer.
+func (FormSerializer) Marshal(v interface{{}}) ([]byte, error) {{
+\tform, err := formValues(v)
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("form: %w", err)
+\t}}
+\treturn []byte(form.Encode()), nil
+}}
+
+// formValues returns the fields of v as form values, named as in JSON.
+// Arrays become repeated fields and nested objects JSON text.
+func formValues(v interface{{}}) (url.Values, error) {{
+\tfields, err := jsonObject(v)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tform := url.Values{{}}
+\tfor name, value := range fields {{
+\t\tvalues, ok := value.([]interface{{}})
+\t\tif !ok {{
+\t\t\tvalues = []interface{{}}{{value}}
+\t\t}}
+\t\tfor _, value := range values {{
+\t\t\tswitch value := value.(type) {{
+\t\t\tcase nil:
+\t\t\tcase string:
+\t\t\t\tform.Add(name, value)
+\t\t\tcase map[string]interface{{}}, []interface{{}}:
+\t\t\t\ttext, _ := json.Marshal(value)
+\t\t\t\tform.Add(name, string(text))
+\t\t\tdefault:
+\t\t\t\tform.Add(name, fmt.Sprint(value))
+\t\t\t}}
+\t\t}}
+\t}}
+\treturn form, nil
+}}
+
+// Unmarshal implements Serializer; repeated fields decode as arrays.
+func (FormSerializer) Unmarshal(data []byte, v interface{{}}) error {{
+\tform, err := url.ParseQuery(string(data))
+\tif err != nil {{
+\t\treturn fmt.Errorf("form: %w", err)
+\t}}
+\tfields := make(map[string]interface{{}}, len(form))
+\tfor name, values := range form {{
+\t\tif len(values) == 1 {{
+\t\t\tfields[name] = values[0]
+\t\t}} else {{
+\t\t\tfields[name] = values
+\t\t}}
+\t}}
+\treturn remarshal(fields, v)
+}}
+
+// XMLSerializer maps XML to and from the JSON shape of the generated types:
+// child elements become fields, repeated elements arrays, and attributes
+// fields too. Root names the document element of encoded bodies; it
+// defaults to "request".
+type XMLSerializer struct {{
+\tRoot string
+}}
+
+// Marshal implements Serializer.
+func (s XMLSerializer) Marshal(v interface{{}}) ([]byte, error) {{
+\tvar tree interface{{}}
+\tdata, err := json.Marshal(v)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tdecoder := json.NewDecoder(bytes.NewReader(data))
+\tdecoder.UseNumber()
+\tif err := decoder.Decode(&tree); err != nil {{
+\t\treturn nil, err
+\t}}
+\troot := s.Root
+\tif root == "" {{
+\t\troot = "request"
+\t}}
+\tvar buf bytes.Buffer
+\tbuf.WriteString(xml.Header)
+\twriteXML(&buf, root, tree)
+\treturn buf.Bytes(), nil
+}}
+
+// Unmarshal implements Serializer; the document element itself is dropped.
+func (XMLSerializer) Unmarshal(data []byte, v interface{{}}) error {{
+\tdecoder := xml.NewDecoder(bytes.NewReader(data))
+\tfor {{
+\t\ttoken, err := decoder.Token()
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("xml: %w", err)
+\t\t}}
+\t\tif start, ok := token.(xml.StartElement); ok {{
+\t\t\ttree, err := readXML(decoder, start)
+\t\t\tif err != nil {{
+\t\t\t\treturn fmt.Errorf("xml: %w", err)
+\t\t\t}}
+\t\t\treturn remarshal(tree, v)
+\t\t}}
+\t}}
+}}
+
+// readXML reads the element opened by start as a string (text only) or a
+// map of its attributes and children.
+func readXML(decoder *xml.Decoder, start xml.StartElement) (interface{{}}, error) {{
+\tfields := make(map[string]interface{{}})
+\tfor _, attr := range start.Attr {{
+\t\tfields[attr.Name.Local] = attr.Value
+\t}}
+\tvar text strings.Builder
+\tfor {{
+\t\ttoken, err := decoder.Token()
+\t\tif err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t\tswitch token := token.(type) {{
+\t\tcase xml.StartElement:
+\t\t\tchild, err := readXML(decoder, token)
+\t\t\tif err != nil {{
+\t\t\t\treturn nil, err
+\t\t\t}}
+\t\t\tname := token.Name.Local
+\t\t\tswitch existing := fields[name].(type) {{
+\t\t\tcase nil:
+\t\t\t\tfields[name] = child
+\t\t\tcase []interface{{}}:
+\t\t\t\tfields[name] = append(existing, child)
+\t\t\tdefault:
+\t\t\t\tfields[name] = []interface{{}}{{existing, child}}
+\t\t\t}}
+\t\tcase xml.CharData:
+\t\t\ttext.Write(token)
+\t\tcase xml.EndElement:
+\t\t\tif len(fields) == 0 {{
+\t\t\t\treturn strings.TrimSpace(text.String()), nil
+\t\t\t}}
+\t\t\treturn fields, nil
+\t\t}}
+\t}}
+}}
+
+// writeXML writes value as element name.
+func writeXML(w io.Writer, name string, value interface{{}}) {{
+\tswitch value := value.(type) {{
+\tcase nil:
+\tcase []interface{{}}:
+\t\tfor _, item := range value {{
+\t\t\twriteXML(w, name, item)
+\t\t}}
+\tcase map[string]interface{{}}:
+\t\tkeys := make([]string, 0, len(value))
+\t\tfor key := range value {{
+\t\t\tkeys = append(keys, key)
+\t\t}}
+\t\tsort.Strings(keys)
+\t\tfmt.Fprintf(w, "<%s>", name)
+\t\tfor _, key := range keys {{
+\t\t\twriteXML(w, key, value[key])
+\t\t}}
+\t\tfmt.Fprintf(w, "</%s>", name)
+\tdefault:
+\t\tfmt.Fprintf(w, "<%s>", name)
+\t\txml.EscapeText(w, []byte(fmt.Sprint(value)))
+\t\tfmt.Fprintf(w, "</%s>", name)
+\t}}
+}}
+
+// jsonObject returns v's JSON fields, keeping numbers exact.
+func jsonObject(v interface{{}}) (map[string]interface{{}}, error) {{
+\tdata, err := json.Marshal(v)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar fields map[string]interface{{}}
+\tdecoder := json.NewDecoder(bytes.NewReader(data))
+\tdecoder.UseNumber()
+\tif err := decoder.Decode(&fields); err != nil {{
+\t\treturn nil, err
+\t}}
+\treturn fields, nil
+}}
+
+// remarshal decodes a generic tree into v through JSON, so the generated
+// types' JSON field names apply.
+func remarshal(tree interface{{}}, v interface{{}}) error {{
+\tdata, err := json.Marshal(tree)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\treturn json.Unmarshal(data, v)
+}}
+"""
+    
+    def _generate_go_compression(self, package_name: str) -> str:
+        import json
+        encodings = self._request_encodings()
+        width = max((len(json.dumps(operation)) + 1 for operation in encodings), default=0)
+        entries = ''.join(f'\t{(json.dumps(operation) + ":").ljust(width)} {json.dumps(encoding)},\n'
+                          for operation, encoding in sorted(encodings.items()))
+        entries = '\n' + entries if entries else ''
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"compress/flate"
+\t"compress/gzip"
+\t"compress/zlib"
+\t"encoding/binary"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"strings"
+)
+
+// DefaultAcceptEncoding is the Accept-Encoding header clients send unless
+// WithAcceptEncoding says otherwise.
+const DefaultAcceptEncoding = "gzip, deflate"
+
+// DefaultCompressMinSize is the size from which request bodies are
+// compressed, for operations the server accepts compressed bodies for;
+// smaller bodies gain too little to be worth the server inflating them.
+const DefaultCompressMinSize = 1024
+
+// requestEncodings maps the operations the server was seen accepting
+// compressed request bodies for to the Content-Encoding it accepted.
+var requestEncodings = map[string]string{{{entries}}}
+
+// maxInflateRatio bounds how large a gzip trailer may claim the inflated
+// body to be, relative to the compressed one, before it is not trusted for
+// sizing the buffer.
+const maxInflateRatio = 1032
+
+// WithAcceptEncoding sets the Accept-Encoding header sent with every
+// request; gzip and deflate responses are inflated by the client. An empty
+// string leaves negotiation to the HTTP transport.
+//
+// The client inflates responses itself, into a buffer sized from the gzip
+// trailer, so a large response is held once compressed and once inflated
+// rather than regrown while reading. Checksum headers are verified against
+// the compressed bytes, as the server sent them.
+func WithAcceptEncoding(encodings string) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.acceptEncoding = encodings
+\t}}
+}}
+
+// WithRequestCompression compresses request bodies of minSize bytes or more,
+// instead of DefaultCompressMinSize, for the operations the server was seen
+// accepting compressed bodies for. A negative minSize sends every body
+// uncompressed. Request signatures and digests cover the compressed bytes,
+// as the server receives them.
+func WithRequestCompression(minSize int) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.compressMinSize = minSize
+\t}}
+}}
+
+// compressRequest encodes body as the server accepts for operation, when it
+// is large enough, returning the body to send and its Content-Encoding.
+func (c *{self.class_name}Client) compressRequest(operation string, body []byte) ([]byte, string, error) {{
+\tencoding, ok := requestEncodings[operation]
+\tif !ok || c.compressMinSize < 0 || len(body) < c.compressMinSize {{
+\t\treturn body, "", nil
+\t}}
+\tvar buf bytes.Buffer
+\tvar w io.WriteCloser = gzip.NewWriter(&buf)
+\tif encoding == "deflate" {{
+\t\tw = zlib.NewWriter(&buf)
+\t}}
+\tif _, err := w.Write(body); err != nil {{
+\t\treturn nil, "", fmt.Errorf("compressing request: %w", err)
+\t}}
+\tif err := w.Close(); err != nil {{
+\t\treturn nil, "", fmt.Errorf("compressing request: %w", err)
+\t}}
+\treturn buf.Bytes(), encoding, nil
+}}
+
+// inflate decodes the Content-Encoding of a response body read as wire,
+// stopping after limit inflated bytes when limit is positive. The wire bytes
+// are kept alongside, for the response cache and checksums.
+func inflate(resp *http.Response, wire []byte, limit int64) ([]byte, error) {{
+\tencodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+\tbody := wire
+\tfor i := len(encodings) - 1; i >= 0; i-- {{
+\t\tvar reader io.Reader
+\t\tvar err error
+\t\tswitch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {{
+\t\tcase "", "identity":
+\t\t\tcontinue
+\t\tcase "gzip", "x-gzip":
+\t\t\treader, err = gzip.NewReader(bytes.NewReader(body))
+\t\tcase "deflate":
+\t\t\t// Some servers send raw DEFLATE rather than the zlib format the
+\t\t\t// specification asks for.
+\t\t\tif reader, err = zlib.NewReader(bytes.NewReader(body)); err != nil {{
+\t\t\t\treader, err = flate.NewReader(bytes.NewReader(body)), nil
+\t\t\t}}
+\t\tdefault:
+\t\t\treturn nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+\t\t}}
+\t\tif err != nil {{
+\t\t\treturn nil, fmt.Errorf("inflating %s response: %w", encodings[i], err)
+\t\t}}
+\t\tif body, err = readInflated(reader, inflatedSize(body), limit); err != nil {{
+\t\t\treturn nil, err
+\t\t}}
+\t}}
+\treturn body, nil
+}}
+
+// inflatedSize estimates the inflated size of body: the size a gzip trailer
+// records, when plausible, or four times the compressed size. Either can be
+// far off, so readInflated caps it like any other presize.
+func inflatedSize(body []byte) int64 {{
+\tif len(body) >= 18 && body[0] == 0x1f && body[1] == 0x8b {{
+\t\tsize := int64(binary.LittleEndian.Uint32(body[len(body)-4:]))
+\t\tif size <= int64(len(body))*maxInflateRatio {{
+\t\t\treturn size
+\t\t}}
+\t}}
+\treturn int64(len(body)) * 4
+}}
+
+func readInflated(reader io.Reader, size, limit int64) ([]byte, error) {{
+\tif limit > 0 {{
+\t\tif size > limit {{
+\t\t\tsize = limit + 1
+\t\t}}
+\t\treader = io.LimitReader(reader, limit+1)
+\t}}
+\tbody, err := readAll(reader, size)
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("inflating response: %w", err)
+\t}}
+\tif limit > 0 && int64(len(body)) > limit {{
+\t\treturn nil, &SizeLimitError{{Direction: "response", Limit: limit, Size: int64(len(body))}}
+\t}}
+\treturn body, nil
+}}
+"""
+    
+    def _generate_go_compression_test(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"testing"
+)
+
+func TestReadInflatedPresize(t *testing.T) {{
+\tbody, err := readInflated(bytes.NewReader([]byte(`{{}}`)), 1<<40, 0)
+\tif err != nil {{
+\t\tt.Fatal(err)
+\t}}
+\tif cap(body) > maxPresize+bytes.MinRead {{
+\t\tt.Errorf("allocated %d bytes up front, want at most %d", cap(body), maxPresize+bytes.MinRead)
+\t}}
+}}
+"""
+    
+    def _generate_go_health(self, package_name: str, ping_endpoint: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"net/http"
+\t"sync"
+\t"time"
+)
+
+// pingEndpoint is the operation Ping calls: the API's health endpoint when
+// the capture has one, else the captured GET with the smallest response.
+const pingEndpoint = "{ping_endpoint}"
+
+// Ping calls the API's cheapest read-only endpoint and reports whether it
+// answered successfully, checking connectivity and credentials at once.
+func (c *{self.class_name}Client) Ping(ctx context.Context) error {{
+\t_, err := c.doRequest(ctx, "GET", pingEndpoint, pingEndpoint, nil, nil)
+\treturn err
+}}
+
+// Readiness reports whether the API is usable, for the readiness probes of
+// services that depend on it. Results are cached, so frequent probes from
+// many replicas do not load the API:
+//
+//\thttp.Handle("/readyz", NewReadiness(client))
+type Readiness struct {{
+\t// Timeout bounds each Ping; zero means 2 seconds.
+\tTimeout time.Duration
+\t// CacheFor reuses a result for this long; zero means 10 seconds.
+\tCacheFor time.Duration
+\t// FailureThreshold is how many consecutive failed pings make the API
+\t// unready; zero means 1.
+\tFailureThreshold int
+
+\tclient *{self.class_name}Client
+
+\tmu       sync.Mutex
+\tchecked  time.Time
+\tfailures int
+\terr      error
+}}
+
+// NewReadiness creates a Readiness pinging client.
+func NewReadiness(client *{self.class_name}Client) *Readiness {{
+\treturn &Readiness{{client: client}}
+}}
+
+// Check returns nil when the API is ready, or the error of the last ping.
+func (r *Readiness) Check(ctx context.Context) error {{
+\tr.mu.Lock()
+\tdefer r.mu.Unlock()
+\tcacheFor := r.CacheFor
+\tif cacheFor == 0 {{
+\t\tcacheFor = 10 * time.Second
+\t}}
+\tif !r.checked.IsZero() && time.Since(r.checked) < cacheFor {{
+\t\treturn r.status()
+\t}}
+
+\ttimeout := r.Timeout
+\tif timeout == 0 {{
+\t\ttimeout = 2 * time.Second
+\t}}
+\tctx, cancel := context.WithTimeout(ctx, timeout)
+\tdefer cancel()
+\tr.err = r.client.Ping(ctx)
+\tr.checked = time.Now()
+\tif r.err != nil {{
+\t\tr.failures++
+\t}} else {{
+\t\tr.failures = 0
+\t}}
+\treturn r.status()
+}}
+
+func (r *Readiness) status() error {{
+\tthreshold := r.FailureThreshold
+\tif threshold == 0 {{
+\t\tthreshold = 1
+\t}}
+\tif r.failures >= threshold {{
+\t\treturn r.err
+\t}}
+\treturn nil
+}}
+
+// ServeHTTP answers 200 when the API is ready and 503 with the error
+// otherwise.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {{
+\tw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+\tw.Header().Set("Cache-Control", "no-store")
+\tif err := r.Check(req.Context()); err != nil {{
+\t\tw.WriteHeader(http.StatusServiceUnavailable)
+\t\tw.Write([]byte(err.Error() + "\\n"))
+\t\treturn
+\t}}
+\tw.Write([]byte("ok\\n"))
+}}
+"""
+    
+    def _selftest_operations(self) -> Tuple[str, str]:
+        """The operations table of cmd/selftest: every GET without a request
+        body, called with its path parameters from -arg flags and no query
+        parameters, and the operation to call by default."""
+        import re
+        entries = []
+        for endpoint in sorted(self.endpoints.values(), key=lambda e: e.path_pattern):
+            if endpoint.method != 'GET' or endpoint.request_body_schema or not self._go_json_call(endpoint):
+                continue
+            name = self._go_name(endpoint)
+            signature = self._generate_go_method(name, endpoint)[1]
+            params = re.match(r'func \(c \*\w+\) \w+\((.*)\) \(', signature).group(1).split(', ')[1:-1]
+            path_params = self._go_path_params(endpoint)
+            names = 'nil' if not path_params else '[]string{' + ', '.join(f'"{p}"' for p, _ in path_params) + '}'
+            args, parse = [], ''
+            for param, param_type in path_params:
+                if param_type == 'int64':
+                    var = self._to_camel_case(param)
+                    parse += (f'\t\t{var}, err := strconv.ParseInt(args["{param}"], 10, 64)\n'
+                              f'\t\tif err != nil {{\n'
+                              f'\t\t\treturn fmt.Errorf("-arg {param}: %w", err)\n'
+                              f'\t\t}}\n')
+                    args.append(var)
+                else:
+                    args.append(f'args["{param}"]')
+            args += ['nil'] * (len(params) - len(args))
+            call = f'c.{name}({", ".join(["ctx"] + args)})'
+            call = f'return {call}.Err' if self.result_types else f'_, err {"=" if parse else ":="} {call}\n\t\treturn err'
+            entries.append(
+                f'\t"GET {endpoint.path_pattern}": {{{names}, func(ctx context.Context, c *api.{self.class_name}Client, args map[string]string) error {{\n'
+                f'{parse}'
+                f'\t\t{call}\n'
+                f'\t}}}},\n')
+        default = f"GET {self._ping_endpoint()}"
+        if not any(entry.startswith(f'\t"{default}"') for entry in entries):
+            default = entries[0].split('"')[1] if entries else ''
+        return ''.join(entries), default
+    
+    def _generate_go_selftest(self, sdk_import: str) -> str:
+        operations, default = self._selftest_operations()
+        strconv = '\t"strconv"\n' if 'strconv.' in operations else ''
+        credential_env = 'EnvToken' if self._captured_auth()[0] == 'bearer' else 'EnvAPIKey'
+        return f"""// Command selftest checks that this machine can use the {self.class_name} API:
+// DNS resolution, TCP and TLS reachability, credentials, and whether a
+// read-only call's response still matches the SDK's schema. It reads the
+// same environment variables and profiles as NewClientFromEnv and prints a
+// report to attach to support requests:
+//
+//\tgo run ./cmd/selftest -endpoint "GET /v1/users/{{id}}" -arg id=42
+//
+// It exits with status 1 if any check fails.
+package main
+
+import (
+\t"context"
+\t"crypto/tls"
+\t"errors"
+\t"flag"
+\t"fmt"
+\t"net"
+\t"net/http"
+\t"net/url"
+\t"os"
+\t"runtime"
+\t"sort"
+{strconv}\t"strings"
+\t"time"
+
+\tapi "{sdk_import}"
+)
+
+// operation is a read-only call the self-test can make.
+type operation struct {{
+\tparams []string
+\tcall   func(ctx context.Context, c *api.{self.class_name}Client, args map[string]string) error
+}}
+
+// operations are the captured GET operations; pingOperation is the default.
+var operations = map[string]operation{{
+{operations}}}
+
+const pingOperation = "{default}"
+
+type argFlags map[string]string
+
+func (a argFlags) String() string {{ return fmt.Sprint(map[string]string(a)) }}
+
+func (a argFlags) Set(value string) error {{
+\tname, arg, ok := strings.Cut(value, "=")
+\tif !ok {{
+\t\treturn fmt.Errorf("want name=value, got %q", value)
+\t}}
+\ta[name] = arg
+\treturn nil
+}}
+
+// report prints check results and remembers whether any failed.
+type report struct {{
+\tfailed bool
+}}
+
+func (r *report) check(name string, err error, detail string) {{
+\tswitch {{
+\tcase errors.Is(err, errSkipped):
+\t\tr.print("SKIP", name, detail)
+\tcase err != nil:
+\t\tr.failed = true
+\t\tr.print("FAIL", name, err.Error())
+\tdefault:
+\t\tr.print("PASS", name, detail)
+\t}}
+}}
+
+func (r *report) print(status, name, detail string) {{
+\tfmt.Printf("%-4s  %-12s %s\\n", status, name, strings.ReplaceAll(detail, "\\n", "\\n"+strings.Repeat(" ", 19)))
+}}
+
+// errSkipped marks a check that could not be run or decided.
+var errSkipped = errors.New("skipped")
+
+func main() {{
+\targs := argFlags{{}}
+\tendpoint := flag.String("endpoint", pingOperation, "read-only operation to call, such as \\"GET /v1/users\\"")
+\tbaseURL := flag.String("base-url", "", "API base URL, overriding the environment and profile")
+\ttimeout := flag.Duration("timeout", 10*time.Second, "timeout for each check")
+\tlist := flag.Bool("list", false, "list the operations -endpoint accepts and exit")
+\tflag.Var(args, "arg", "path parameter for -endpoint as name=value; repeatable")
+\tflag.Parse()
+
+\tif *list {{
+\t\tnames := make([]string, 0, len(operations))
+\t\tfor name := range operations {{
+\t\t\tnames = append(names, name)
+\t\t}}
+\t\tsort.Strings(names)
+\t\tfor _, name := range names {{
+\t\t\tfmt.Println(name)
+\t\t}}
+\t\treturn
+\t}}
+
+\tvar status int
+\tvar mismatches []api.SchemaMismatch
+\topts := []api.ClientOption{{
+\t\tapi.WithAuditSink(api.AuditSinkFunc(func(_ context.Context, record api.AuditRecord) {{
+\t\t\tstatus = record.StatusCode
+\t\t}})),
+\t\tapi.WithSchemaMismatchHandler(func(m api.SchemaMismatch) {{
+\t\t\tmismatches = append(mismatches, m)
+\t\t}}),
+\t}}
+\tif *baseURL != "" {{
+\t\topts = append(opts, func(c *api.{self.class_name}Client) {{ c.BaseURL = strings.TrimSuffix(*baseURL, "/") }})
+\t}}
+\tclient, err := api.NewClientFromEnv(opts...)
+\tr := &report{{}}
+\tfmt.Printf("{self.class_name} self-test, %s %s/%s, %s\\n\\n", runtime.Version(), runtime.GOOS, runtime.GOARCH, time.Now().Format(time.RFC3339))
+\tr.check("config", err, "base URL "+clientURL(client))
+\tif err != nil {{
+\t\tos.Exit(1)
+\t}}
+
+\ttarget, err := url.Parse(client.BaseURL)
+\tif err == nil && target.Host == "" {{
+\t\terr = fmt.Errorf("base URL %q has no host", client.BaseURL)
+\t}}
+\tif err != nil {{
+\t\tr.check("dns", err, "")
+\t\tos.Exit(1)
+\t}}
+\tctx, cancel := context.WithTimeout(context.Background(), *timeout)
+\taddrs, err := net.DefaultResolver.LookupHost(ctx, target.Hostname())
+\tcancel()
+\tr.check("dns", err, target.Hostname()+" -> "+strings.Join(addrs, ", "))
+
+\ttransport, _ := client.HTTPClient.Transport.(*http.Transport)
+\tdetail, err := checkConnect(target, transport, *timeout)
+\tr.check("connect", err, detail)
+
+\tctx, cancel = context.WithTimeout(context.Background(), *timeout)
+\terr = client.Ping(ctx)
+\tcancel()
+\tswitch {{
+\tcase status == 401 || status == 403:
+\t\tr.check("credentials", fmt.Errorf("rejected with status %d; check %s or the profile", status, api.{credential_env}), "")
+\tcase err == nil:
+\t\tr.check("credentials", nil, fmt.Sprintf("accepted by %s (status %d)", pingOperation, status))
+\tdefault:
+\t\tr.check("credentials", errSkipped, "not verified: "+err.Error())
+\t}}
+
+\top, ok := operations[*endpoint]
+\tif !ok {{
+\t\tr.check("call", fmt.Errorf("unknown operation %q; run with -list", *endpoint), "")
+\t\tos.Exit(1)
+\t}}
+\tfor _, name := range op.params {{
+\t\tif _, ok := args[name]; !ok {{
+\t\t\tr.check("call", fmt.Errorf("%s needs -arg %s=...", *endpoint, name), "")
+\t\t\tos.Exit(1)
+\t\t}}
+\t}}
+\tstatus, mismatches = 0, nil
+\tctx, cancel = context.WithTimeout(context.Background(), *timeout)
+\tstart := time.Now()
+\terr = op.call(ctx, client, args)
+\tcancel()
+\tr.check("call", err, fmt.Sprintf("%s -> %d in %s", *endpoint, status, time.Since(start).Round(time.Millisecond)))
+
+\tr.schema(err, mismatches)
+
+\tif r.failed {{
+\t\tos.Exit(1)
+\t}}
+}}
+
+// schema reports the mismatches between the call's response and the SDK
+// types. Values of the wrong type fail the check; fields added to or
+// missing from the response only warn, as the capture may not have seen
+// every optional field.
+func (r *report) schema(callErr error, mismatches []api.SchemaMismatch) {{
+\tif callErr != nil {{
+\t\tr.check("schema", errSkipped, "no response to validate")
+\t\treturn
+\t}}
+\tif len(mismatches) == 0 {{
+\t\tr.check("schema", nil, "response matches the SDK types")
+\t\treturn
+\t}}
+\tstatus := "WARN"
+\tlines := make([]string, len(mismatches))
+\tfor i, m := range mismatches {{
+\t\tlines[i] = fmt.Sprintf("%s %s", m.Kind, m.Field)
+\t\tif m.Kind == api.TypeFallback {{
+\t\t\tlines[i] += fmt.Sprintf(" (expected %s, got %s)", m.Expected, m.Observed)
+\t\t\tstatus, r.failed = "FAIL", true
+\t\t}}
+\t}}
+\tr.print(status, "schema", fmt.Sprintf("%d mismatches:\\n%s", len(mismatches), strings.Join(lines, "\\n")))
+}}
+
+// checkConnect dials the API, completing a TLS handshake for https with the
+// client's TLS settings, and describes the connection. Through a proxy, only
+// the proxy is dialed.
+func checkConnect(target *url.URL, transport *http.Transport, timeout time.Duration) (string, error) {{
+\tdialer := &net.Dialer{{Timeout: timeout}}
+\thost, port := target.Hostname(), target.Port()
+\tif port == "" {{
+\t\tport = map[bool]string{{true: "443", false: "80"}}[target.Scheme == "https"]
+\t}}
+\tif transport != nil && transport.Proxy != nil {{
+\t\tproxyURL, err := transport.Proxy(&http.Request{{URL: target}})
+\t\tif err != nil {{
+\t\t\treturn "", fmt.Errorf("proxy: %w", err)
+\t\t}}
+\t\tif proxyURL != nil {{
+\t\t\tconn, err := dialer.Dial("tcp", proxyURL.Host)
+\t\t\tif err != nil {{
+\t\t\t\treturn "", fmt.Errorf("proxy %s: %w", proxyURL.Redacted(), err)
+\t\t\t}}
+\t\t\tdefer conn.Close()
+\t\t\treturn "via proxy " + proxyURL.Redacted() + " (TLS not checked)", nil
+\t\t}}
+\t}}
+\taddress := net.JoinHostPort(host, port)
+\tif target.Scheme != "https" {{
+\t\tconn, err := dialer.Dial("tcp", address)
+\t\tif err != nil {{
+\t\t\treturn "", err
+\t\t}}
+\t\tdefer conn.Close()
+\t\treturn "tcp " + conn.RemoteAddr().String(), nil
+\t}}
+\tconfig := &tls.Config{{}}
+\tif transport != nil && transport.TLSClientConfig != nil {{
+\t\tconfig = transport.TLSClientConfig.Clone()
+\t}}
+\tconfig.ServerName = host
+\tconn, err := tls.DialWithDialer(dialer, "tcp", address, config)
+\tif err != nil {{
+\t\treturn "", err
+\t}}
+\tdefer conn.Close()
+\tstate := conn.ConnectionState()
+\tcert := state.PeerCertificates[0]
+\tdays := int(time.Until(cert.NotAfter).Hours() / 24)
+\tdetail := fmt.Sprintf("%s %s, certificate %q expires in %d days", conn.RemoteAddr(), tls.VersionName(state.Version), cert.Subject.CommonName, days)
+\tif days < 14 {{
+\t\tdetail += " (renew soon)"
+\t}}
+\treturn detail, nil
+}}
+
+func clientURL(client *api.{self.class_name}Client) string {{
+\tif client == nil {{
+\t\treturn ""
+\t}}
+\treturn client.BaseURL
+}}
+"""
+    
+    def _generate_go_errors(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"encoding/json"
+\t"errors"
+\t"fmt"
+\t"net/http"
+\t"os"
+\t"strconv"
+\t"strings"
+\t"sync"
+)
+
+// APIError is returned for responses with an error status. Its message is
+// the one registered with WithErrorMessages for the upstream error code or
+// status, in the request's locale, when there is one; the vendor's own
+// error is kept in Code, Message, Payload, and Body.
+//
+// Branch on the status with errors.Is and the sentinels below, or the Is
+// helpers, rather than on the message:
+//
+//\tuser, err := client.GetUser(ctx, id)
+//\tswitch {{
+//\tcase IsNotFound(err):
+//\t\treturn nil, nil
+//\tcase errors.Is(err, ErrConflict):
+//\t\treturn nil, retryLater(err)
+//\t}}
+//
+// Use errors.As to read the fields.
+type APIError struct {{
+\tOperation  string // "GET /v1/users/{{id}}"
+\tStatusCode int
+\tCode       string // the vendor's error code, when the body has one
+\tMessage    string // the vendor's error message, when the body has one
+\tRequestID  string // the server's request ID header, for support requests
+\tHeader     http.Header
+\tBody       []byte
+\t// Payload is the body decoded, when it is a JSON object.
+\tPayload map[string]interface{{}}
+
+\tuserMessage string
+}}
+
+// Sentinels an *APIError matches with errors.Is, by status code.
+var (
+\tErrBadRequest   = errors.New("bad request")       // 400
+\tErrUnauthorized = errors.New("unauthorized")      // 401
+\tErrForbidden    = errors.New("forbidden")         // 403
+\tErrNotFound     = errors.New("not found")         // 404
+\tErrConflict     = errors.New("conflict")          // 409
+\tErrValidation   = errors.New("validation failed") // 422
+\tErrRateLimited  = errors.New("rate limited")      // 429
+\tErrServer       = errors.New("server error")      // 5xx
+)
+
+// statusSentinels maps the status codes with a sentinel to it.
+var statusSentinels = map[int]error{{
+\thttp.StatusBadRequest:          ErrBadRequest,
+\thttp.StatusUnauthorized:        ErrUnauthorized,
+\thttp.StatusForbidden:           ErrForbidden,
+\thttp.StatusNotFound:            ErrNotFound,
+\thttp.StatusConflict:            ErrConflict,
+\thttp.StatusUnprocessableEntity: ErrValidation,
+\thttp.StatusTooManyRequests:     ErrRateLimited,
+}}
+
+// Is reports whether target is the sentinel for e's status code.
+func (e *APIError) Is(target error) bool {{
+\tif e.StatusCode >= 500 {{
+\t\treturn target == ErrServer
+\t}}
+\tsentinel, ok := statusSentinels[e.StatusCode]
+\treturn ok && target == sentinel
+}}
+
+// Temporary reports whether the call may succeed if made again: the API
+// was rate limited or failed on its side.
+func (e *APIError) Temporary() bool {{
+\treturn e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {{
+\treturn errors.Is(err, ErrNotFound)
+}}
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool {{
+\treturn errors.Is(err, ErrConflict)
+}}
+
+// IsRateLimited reports whether err is an API error with status 429.
+func IsRateLimited(err error) bool {{
+\treturn errors.Is(err, ErrRateLimited)
+}}
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool {{
+\treturn errors.Is(err, ErrUnauthorized)
+}}
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool {{
+\treturn errors.Is(err, ErrForbidden)
+}}
+
+// IsServerError reports whether err is an API error with a 5xx status.
+func IsServerError(err error) bool {{
+\treturn errors.Is(err, ErrServer)
+}}
+
+// StatusCode returns the status code of the API error in err's chain, or 0
+// if there is none.
+func StatusCode(err error) int {{
+\tvar apiErr *APIError
+\tif errors.As(err, &apiErr) {{
+\t\treturn apiErr.StatusCode
+\t}}
+\treturn 0
+}}
+
+func (e *APIError) Error() string {{
+\tif e.userMessage != "" {{
+\t\treturn e.userMessage
+\t}}
+\treturn e.Detail()
+}}
+
+// Detail describes the error as the API reported it, for logs.
+func (e *APIError) Detail() string {{
+\tif e.RequestID != "" {{
+\t\treturn fmt.Sprintf("API error: status=%d, request_id=%s, body=%s", e.StatusCode, e.RequestID, string(e.Body))
+\t}}
+\treturn fmt.Sprintf("API error: status=%d, body=%s", e.StatusCode, string(e.Body))
+}}
+
+// errorCodeFields and errorMessageFields are the body fields read as the
+// vendor's error code and message, at the top level or in an "error"
+// object or the first element of an "errors" array.
+var (
+\terrorCodeFields    = []string{{"code", "error_code", "errorCode", "type", "error"}}
+\terrorMessageFields = []string{{"message", "error_description", "errorMessage", "detail", "title", "error"}}
+)
+
+// newAPIError builds the error for a response, localizing it with
+// messages for locale, an Accept-Language value. header may be nil.
+func newAPIError(operation string, status int, header http.Header, body []byte, messages *ErrorMessages, locale string) *APIError {{
+\te := &APIError{{Operation: operation, StatusCode: status, Header: header, RequestID: requestID(header), Body: body}}
+\tvar payload map[string]interface{{}}
+\tif json.Unmarshal(body, &payload) == nil && payload != nil {{
+\t\te.Payload = payload
+\t\tobjects := []map[string]interface{{}}{{payload}}
+\t\tif nested, ok := payload["error"].(map[string]interface{{}}); ok {{
+\t\t\tobjects = append(objects, nested)
+\t\t}}
+\t\tif list, ok := payload["errors"].([]interface{{}}); ok && len(list) > 0 {{
+\t\t\tif first, ok := list[0].(map[string]interface{{}}); ok {{
+\t\t\t\tobjects = append(objects, first)
+\t\t\t}}
+\t\t}}
+\t\tfor _, object := range objects {{
+\t\t\tif e.Code == "" {{
+\t\t\t\te.Code = firstScalar(object, errorCodeFields)
+\t\t\t}}
+\t\t\tif e.Message == "" {{
+\t\t\t\te.Message = firstScalar(object, errorMessageFields)
+\t\t\t}}
+\t\t}}
+\t}}
+\tif message, ok := messages.lookup(e.Code, status, locale); ok {{
+\t\te.userMessage = message
+\t}}
+\treturn e
+}}
+
+func firstScalar(object map[string]interface{{}}, fields []string) string {{
+\tfor _, field := range fields {{
+\t\tswitch value := object[field].(type) {{
+\t\tcase string:
+\t\t\tif value != "" {{
+\t\t\t\treturn value
+\t\t\t}}
+\t\tcase float64:
+\t\t\treturn strconv.FormatFloat(value, 'f', -1, 64)
+\t\t}}
+\t}}
+\treturn ""
+}}
+
+// ErrorMessages maps upstream error codes to user-facing messages per
+// locale, so products embedding the SDK do not show vendor error strings to
+// end users. Keys are the vendor's error codes, HTTP status codes ("404"),
+// or "" for the message of errors nothing else matches. It is safe for
+// concurrent use.
+//
+//\tmessages := NewErrorMessages().
+//\t\tRegister("card_declined", "en", "Your card was declined.").
+//\t\tRegister("card_declined", "de", "Ihre Karte wurde abgelehnt.").
+//\t\tRegister("", "en", "Something went wrong. Please try again.")
+//\tclient := New{self.class_name}Client("", WithErrorMessages(messages))
+type ErrorMessages struct {{
+\tmu       sync.RWMutex
+\tmessages map[string]map[string]string // code, then lowercase locale
+}}
+
+// NewErrorMessages creates an empty table.
+func NewErrorMessages() *ErrorMessages {{
+\treturn &ErrorMessages{{messages: make(map[string]map[string]string)}}
+}}
+
+// LoadErrorMessages reads a table from a JSON file mapping codes to
+// messages by locale: {{"card_declined": {{"en": "...", "de": "..."}}}}.
+func LoadErrorMessages(path string) (*ErrorMessages, error) {{
+\tdata, err := os.ReadFile(path)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tvar table map[string]map[string]string
+\tif err := json.Unmarshal(data, &table); err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w", path, err)
+\t}}
+\tm := NewErrorMessages()
+\tfor code, messages := range table {{
+\t\tfor locale, message := range messages {{
+\t\t\tm.Register(code, locale, message)
+\t\t}}
+\t}}
+\treturn m, nil
+}}
+
+// Register sets the message for code in locale; an empty locale is the
+// fallback for locales without their own message.
+func (m *ErrorMessages) Register(code, locale, message string) *ErrorMessages {{
+\tm.mu.Lock()
+\tdefer m.mu.Unlock()
+\tif m.messages[code] == nil {{
+\t\tm.messages[code] = make(map[string]string)
+\t}}
+\tm.messages[code][strings.ToLower(locale)] = message
+\treturn m
+}}
+
+// Lookup returns the message for code in the first locale of
+// acceptLanguage (such as "fr-CA, fr;q=0.8") that has one, trying each tag,
+// then its language alone, then the fallback.
+func (m *ErrorMessages) Lookup(code, acceptLanguage string) (string, bool) {{
+\tif m == nil {{
+\t\treturn "", false
+\t}}
+\tm.mu.RLock()
+\tdefer m.mu.RUnlock()
+\tmessages, ok := m.messages[code]
+\tif !ok {{
+\t\treturn "", false
+\t}}
+\tfor _, tag := range strings.Split(acceptLanguage, ",") {{
+\t\ttag, _, _ = strings.Cut(tag, ";")
+\t\ttag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+\t\tif tag == "" {{
+\t\t\tcontinue
+\t\t}}
+\t\tif message, ok := messages[tag]; ok {{
+\t\t\treturn message, true
+\t\t}}
+\t\tlanguage, _, _ := strings.Cut(tag, "-")
+\t\tif message, ok := messages[language]; ok {{
+\t\t\treturn message, true
+\t\t}}
+\t}}
+\tmessage, ok := messages[""]
+\treturn message, ok
+}}
+
+// lookup finds the message for an error by its code, then its status, then
+// the catch-all entry.
+func (m *ErrorMessages) lookup(code string, status int, locale string) (string, bool) {{
+\tif code != "" {{
+\t\tif message, ok := m.Lookup(code, locale); ok {{
+\t\t\treturn message, true
+\t\t}}
+\t}}
+\tif message, ok := m.Lookup(strconv.Itoa(status), locale); ok {{
+\t\treturn message, true
+\t}}
+\treturn m.Lookup("", locale)
+}}
+
+// WithErrorMessages formats the client's API errors with messages.
+func WithErrorMessages(messages *ErrorMessages) ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.errorMessages = messages
+\t}}
+}}
+"""
+    
+    def _generate_go_result(self, package_name: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"errors"
+\t"fmt"
+)
+
+// Result is what each operation returns: the decoded response, the
+// response's metadata, and the error, if any. Meta is filled in whenever a
+// response arrived, including for error statuses:
+//
+//\tres := client.ListUsers(ctx)
+//\tif res.Err != nil {{
+//\t\tlog.Printf("%s failed (request %s): %v", res.Meta.Operation, res.Meta.RequestID, res.Err)
+//\t\treturn
+//\t}}
+//\tusers := res.Value
+type Result[T any] struct {{
+\tValue T
+\tMeta  ResponseMeta
+\tErr   error
+}}
+
+// Ok reports whether the call succeeded.
+func (r Result[T]) Ok() bool {{
+\treturn r.Err == nil
+}}
+
+// Get returns the value and error, as a method returning (T, error) would.
+func (r Result[T]) Get() (T, error) {{
+\treturn r.Value, r.Err
+}}
+
+// Must returns the value, panicking if the call failed. It is meant for
+// scripts and tests.
+func (r Result[T]) Must() T {{
+\tif r.Err != nil {{
+\t\tpanic(fmt.Sprintf("%s: %v", r.Meta.Operation, r.Err))
+\t}}
+\treturn r.Value
+}}
+
+// ValueOr returns the value, or fallback if the call failed.
+func (r Result[T]) ValueOr(fallback T) T {{
+\tif r.Err != nil {{
+\t\treturn fallback
+\t}}
+\treturn r.Value
+}}
+
+// APIError returns the call's error as an *APIError, or nil if it failed
+// before a response arrived or did not fail.
+func (r Result[T]) APIError() *APIError {{
+\tvar apiErr *APIError
+\tif errors.As(r.Err, &apiErr) {{
+\t\treturn apiErr
+\t}}
+\treturn nil
+}}
+
+// StatusCode returns the response status code, or 0 if no response arrived.
+func (r Result[T]) StatusCode() int {{
+\treturn r.Meta.StatusCode
+}}
+
+// MapResult converts a successful result's value with fn, keeping its
+// metadata and error.
+func MapResult[T, U any](r Result[T], fn func(T) U) Result[U] {{
+\tout := Result[U]{{Meta: r.Meta, Err: r.Err}}
+\tif r.Err == nil {{
+\t\tout.Value = fn(r.Value)
+\t}}
+\treturn out
+}}
+"""
+    
+    def _generate_go_tools(self, package_name: str, agenttool_import: str) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+
+\t"{agenttool_import}"
+)
+
+// Tools returns an agenttool.Tool for each of the client's operations, so
+// agents can discover and call them with JSON arguments. A tool's input is
+// an object of the operation's path and query parameters, plus "body" for
+// its request body; its output is the decoded response.
+func (c *{self.class_name}Client) Tools() []agenttool.Tool {{
+\treturn []agenttool.Tool{{
+{self._generate_go_tool_adapters()}
+\t}}
+}}
+
+// toolOutput encodes an operation's return values as a tool's output.
+func toolOutput[T any](value T, err error) (json.RawMessage, error) {{
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\treturn json.Marshal(value)
+}}
+"""
+    
+    JSON_SCHEMA_TYPES = ('string', 'integer', 'number', 'boolean', 'array', 'object', 'null')
+    
+    def _tool_schema(self, schema: Dict[str, Any]) -> Dict[str, Any]:
+        """schema without the inference statistics, as plain JSON Schema."""
+        out = {}
+        if schema.get('type') in self.JSON_SCHEMA_TYPES:
+            out['type'] = [schema['type'], 'null'] if schema.get('nullable') else schema['type']
+        for key in ('format', 'enum', 'required'):
+            if key in schema:
+                out[key] = schema[key]
+        if 'items' in schema:
+            out['items'] = self._tool_schema(schema['items'])
+        if 'properties' in schema:
+            out['properties'] = {name: self._tool_schema(field) for name, field in schema['properties'].items()}
+        return out
+    
+    def _generate_go_tool_adapters(self) -> str:
+        """An agenttool.Func literal per operation, decoding the tool input into
+        the client method's arguments."""
+        import json
+        import re
+        adapters = []
+        for endpoint in self.endpoints.values():
+            if not self._go_json_call(endpoint):
+                continue
+            name = self._go_name(endpoint)
+            signature = self._generate_go_method(name, endpoint)[1]
+            types = [param.split(' ', 1)[1] for param in
+                     re.match(r'func \(c \*\w+\) \w+\((.*)\) \(', signature).group(1).split(', ')[1:-1]]
+            query_params = self._go_query_params(endpoint)
+            path_params = [param for param, _ in self._go_path_params(endpoint)]
+            fields = [[self._to_class_name(key), param_type, f'`json:"{key}"`'] for key, param_type in self._go_path_params(endpoint)]
+            fields += [[self._go_param_field(key), self._schema_to_type_hint({'type': param_type}, 'go'), f'`json:"{key}"`']
+                       for key, param_type in query_params.items()]
+            properties = {param: {'type': 'integer' if param_type == 'int64' else 'string'}
+                          for param, param_type in self._go_path_params(endpoint)}
+            for param, param_type in query_params.items():
+                properties[param] = {'type': param_type} if param_type in self.JSON_SCHEMA_TYPES else {}
+            required = list(path_params)
+            args = ["ctx"] + [f"in.{field[0]}" for field in fields[:len(path_params)]]
+            if query_params:
+                values = ', '.join(f"{field[0]}: in.{field[0]}" for field in fields[len(path_params):])
+                args.append(f"&{name}Params{{{values}}}")
+            if len(types) > len(path_params) + bool(query_params):
+                fields.append(['Body', types[-1], '`json:"body"`'])
+                args.append("in.Body")
+                properties['body'] = self._tool_schema(endpoint.request_body_schema or {})
+                if self._go_partial_update(endpoint):
+                    properties['body'].pop('required', None)
+                if endpoint.request_body_schema:
+                    required.append('body')
+            schema = {'type': 'object', 'properties': properties, 'additionalProperties': False}
+            if required:
+                schema['required'] = required
+            schema_json = json.dumps(schema, separators=(',', ':'))
+            schema_literal = f"`{schema_json}`" if '`' not in schema_json else json.dumps(schema_json)
+            call = f"c.{name}({', '.join(args)})"
+            call = f"{call}.Get()" if self.result_types else call
+            words = self._to_snake_case(name).replace('_', ' ')
+            lines = [
+                "\t\tagenttool.Func{",
+                f"\t\t\tToolName:        \"{self._to_snake_case(name)}\",",
+                f"\t\t\tToolDescription: \"{words[0].upper() + words[1:]} ({endpoint.method} {endpoint.path_pattern}).\",",
+                f"\t\t\tSchema:          json.RawMessage({schema_literal}),",
+                "\t\t\tCall: func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {",
+            ]
+            if fields:
+                lines.append("\t\t\t\tvar in struct {")
+                lines.extend(self._align_go_columns(fields, indent='\t\t\t\t\t'))
+                lines.append("\t\t\t\t}")
+            else:
+                lines.append("\t\t\t\tvar in struct{}")
+            lines.extend([
+                "\t\t\t\tif err := agenttool.DecodeInput(input, &in); err != nil {",
+                "\t\t\t\t\treturn nil, err",
+                "\t\t\t\t}",
+                f"\t\t\t\treturn toolOutput({call})",
+                "\t\t\t},",
+                "\t\t},",
+            ])
+            adapters.append('\n'.join(lines))
+        return '\n'.join(adapters)
+    
+    def _generate_go_agenttool(self) -> str:
+        return """// Package agenttool is the interface through which agents discover and call
+// the capabilities of generated SDKs. Each SDK's Tools method returns one
+// Tool per captured operation, so an agent can list what an API offers and
+// invoke it with JSON arguments without knowing its Go types:
+//
+//\ttools := client.Tools()
+//\tcatalog, _ := agenttool.Catalog(tools) // for the model's tool list
+//\tout, err := agenttool.Find(tools, "list_users").Invoke(ctx, []byte(`{}`))
+package agenttool
+
+import (
+\t"bytes"
+\t"context"
+\t"encoding/json"
+\t"fmt"
+)
+
+// Tool is a callable capability with a JSON Schema for its input.
+type Tool interface {
+\t// Name identifies the tool, such as "get_user".
+\tName() string
+\t// Description tells an agent what the tool does.
+\tDescription() string
+\t// InputSchema is the JSON Schema of the object Invoke accepts.
+\tInputSchema() json.RawMessage
+\t// Invoke calls the tool with input matching InputSchema and returns
+\t// its JSON result.
+\tInvoke(ctx context.Context, input json.RawMessage) (json.RawMessage, error)
+}
+
+// Func is a Tool made of its parts.
+type Func struct {
+\tToolName        string
+\tToolDescription string
+\tSchema          json.RawMessage
+\tCall            func(ctx context.Context, input json.RawMessage) (json.RawMessage, error)
+}
+
+func (f Func) Name() string                 { return f.ToolName }
+func (f Func) Description() string          { return f.ToolDescription }
+func (f Func) InputSchema() json.RawMessage { return f.Schema }
+
+func (f Func) Invoke(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+\treturn f.Call(ctx, input)
+}
+
+// Find returns the tool named name, or nil.
+func Find(tools []Tool, name string) Tool {
+\tfor _, tool := range tools {
+\t\tif tool.Name() == name {
+\t\t\treturn tool
+\t\t}
+\t}
+\treturn nil
+}
+
+// Invoke calls the tool named name, for agents that pick tools by name.
+func Invoke(ctx context.Context, tools []Tool, name string, input json.RawMessage) (json.RawMessage, error) {
+\ttool := Find(tools, name)
+\tif tool == nil {
+\t\treturn nil, fmt.Errorf("agenttool: no tool %q", name)
+\t}
+\treturn tool.Invoke(ctx, input)
+}
+
+// Descriptor is a tool's name, description, and input schema, as listed
+// to models that choose tools.
+type Descriptor struct {
+\tName        string          `json:"name"`
+\tDescription string          `json:"description"`
+\tInputSchema json.RawMessage `json:"input_schema"`
+}
+
+// Catalog describes tools as a JSON array of Descriptors.
+func Catalog(tools []Tool) ([]byte, error) {
+\tdescriptors := make([]Descriptor, len(tools))
+\tfor i, tool := range tools {
+\t\tdescriptors[i] = Descriptor{tool.Name(), tool.Description(), tool.InputSchema()}
+\t}
+\treturn json.MarshalIndent(descriptors, "", "  ")
+}
+
+// DecodeInput unmarshals a tool's input into v, treating empty input as an
+// empty object and rejecting unknown fields so that misspelt arguments are
+// reported rather than ignored.
+func DecodeInput(input json.RawMessage, v interface{}) error {
+\tif len(input) == 0 {
+\t\treturn nil
+\t}
+\tdecoder := json.NewDecoder(bytes.NewReader(input))
+\tdecoder.DisallowUnknownFields()
+\tif err := decoder.Decode(v); err != nil {
+\t\treturn fmt.Errorf("agenttool: invalid input: %w", err)
+\t}
+\treturn nil
+}
+"""
+    
+    def _captured_latencies(self) -> Dict[str, float]:
+        """The median capture latency in milliseconds of each operation whose
+        examples recorded one."""
+        latencies = {}
+        for endpoint in self.endpoints.values():
+            durations = sorted(example['duration_ms'] for example in endpoint.examples if example.get('duration_ms'))
+            if durations:
+                latencies[f"{endpoint.method} {endpoint.path_pattern}"] = durations[len(durations) // 2]
+        return latencies
+    
+    def _generate_go_latency(self, package_name: str, captured: Dict[str, float]) -> str:
+        entries = [(f'"{operation}":', f"{round(ms * 1000)} * time.Microsecond") for operation, ms in sorted(captured.items())]
+        width = max((len(key) for key, _ in entries), default=0)
+        captured_map = '{\n' + ''.join(f"\t{key.ljust(width)} {value},\n" for key, value in entries) + '}' if entries else '{}'
+
+        return f"""package {package_name}
+
+import (
+\t"math"
+\t"sort"
+\t"time"
+)
+
+// capturedLatencies are the median latencies of the captured traffic, by
+// operation, for comparing the SDK's observed performance against the
+// original client's.
+var capturedLatencies = map[string]time.Duration{captured_map}
+
+// WithLatencyStats adds per-operation latency percentiles to
+// Stats().Endpoints. Each operation keeps a t-digest of at most about ten
+// kilobytes however many calls it sees.
+func WithLatencyStats() ClientOption {{
+\treturn func(c *{self.class_name}Client) {{
+\t\tc.stats.mu.Lock()
+\t\tdefer c.stats.mu.Unlock()
+\t\tc.stats.latency = true
+\t}}
+}}
+
+// tdigestCompression trades a digest's size for accuracy; at 100 it keeps
+// a few hundred centroids however many samples it sees.
+const tdigestCompression = 100
+
+// tdigest estimates quantiles of a stream in bounded memory by merging
+// nearby samples into weighted centroids, keeping them small near the
+// extremes so that high percentiles stay accurate.
+type tdigest struct {{
+\tcentroids []centroid // sorted by mean
+\tbuffer    []float64
+\tcount     float64
+\tmin, max  float64
+}}
+
+type centroid struct {{
+\tmean, weight float64
+}}
+
+func (d *tdigest) add(x float64) {{
+\tif d.count == 0 && len(d.buffer) == 0 {{
+\t\td.min, d.max = x, x
+\t}}
+\td.min, d.max = math.Min(d.min, x), math.Max(d.max, x)
+\td.buffer = append(d.buffer, x)
+\tif len(d.buffer) >= 5*tdigestCompression {{
+\t\td.compress()
+\t}}
+}}
+
+// compress merges the buffered samples into the centroids.
+func (d *tdigest) compress() {{
+\tif len(d.buffer) == 0 {{
+\t\treturn
+\t}}
+\tpoints := make([]centroid, 0, len(d.centroids)+len(d.buffer))
+\tpoints = append(points, d.centroids...)
+\tfor _, x := range d.buffer {{
+\t\tpoints = append(points, centroid{{x, 1}})
+\t}}
+\td.buffer = d.buffer[:0]
+\tsort.Slice(points, func(i, j int) bool {{ return points[i].mean < points[j].mean }})
+
+\ttotal := 0.0
+\tfor _, p := range points {{
+\t\ttotal += p.weight
+\t}}
+\tmerged := make([]centroid, 0, 2*tdigestCompression)
+\tcurrent, before := points[0], 0.0
+\tfor _, p := range points[1:] {{
+\t\tq := (before + current.weight + p.weight/2) / total
+\t\tif current.weight+p.weight <= 4*total*q*(1-q)/tdigestCompression {{
+\t\t\tcurrent.weight += p.weight
+\t\t\tcurrent.mean += (p.mean - current.mean) * p.weight / current.weight
+\t\t\tcontinue
+\t\t}}
+\t\tbefore += current.weight
+\t\tmerged = append(merged, current)
+\t\tcurrent = p
+\t}}
+\td.centroids = append(merged, current)
+\td.count = total
+}}
+
+// quantile estimates the q-th quantile, interpolating between centroid
+// centers and the observed extremes.
+func (d *tdigest) quantile(q float64) float64 {{
+\td.compress()
+\tif len(d.centroids) == 0 {{
+\t\treturn 0
+\t}}
+\ttarget := q * d.count
+\tprevCenter, prevMean := 0.0, d.min
+\tcumulative := 0.0
+\tfor _, c := range d.centroids {{
+\t\tcenter := cumulative + c.weight/2
+\t\tif target < center {{
+\t\t\tif center == prevCenter {{
+\t\t\t\treturn c.mean
+\t\t\t}}
+\t\t\treturn prevMean + (c.mean-prevMean)*(target-prevCenter)/(center-prevCenter)
+\t\t}}
+\t\tprevCenter, prevMean = center, c.mean
+\t\tcumulative += c.weight
+\t}}
+\tif d.count == prevCenter {{
+\t\treturn d.max
+\t}}
+\treturn prevMean + (d.max-prevMean)*(target-prevCenter)/(d.count-prevCenter)
+}}
+"""
+    
+    def _go_cli_name(self) -> str:
+        return f"{self.class_name.lower()}-cli"
+    
+    CLI_VERBS = {'POST': 'create', 'PUT': 'update', 'PATCH': 'patch', 'DELETE': 'delete'}
+    
+    def _go_cli_commands(self) -> Dict[str, str]:
+        """The CLI's subcommands, mapped to the tools they call: the resource
+        words of the path and a verb, as in "users list" and "users get", or
+        the operation's name where those collide."""
+        import re
+        commands = {}
+        for endpoint in self.endpoints.values():
+            if not self._go_json_call(endpoint):
+                continue
+            literals = [segment for segment in endpoint.path_pattern.split('/') if segment
+                        and not segment.startswith('{') and not re.fullmatch(self.VERSION_SEGMENT, segment)]
+            item = endpoint.path_pattern.rstrip('/').endswith('}')
+            verb = ('get' if item else 'list') if endpoint.method == 'GET' else self.CLI_VERBS.get(endpoint.method, endpoint.method.lower())
+            words = [self._to_snake_case(literal).replace('_', '-') for literal in literals] + [verb]
+            commands.setdefault(' '.join(words), []).append(self._to_snake_case(self._go_name(endpoint)))
+        resolved = {}
+        for command, tools in commands.items():
+            if len(tools) == 1:
+                resolved[command] = tools[0]
+            else:
+                resolved.update((tool.replace('_', '-'), tool) for tool in tools)
+        return resolved
+    
+    def _generate_go_cli(self, sdk_import: str, module_path: str) -> str:
+        cli = self._go_cli_name()
+        keys = [(f'"{command}":', tool) for command, tool in sorted(self._go_cli_commands().items())]
+        width = max((len(key) for key, _ in keys), default=0) + 1
+        commands = ''.join(f'\t{key:<{width}}"{tool}",\n' for key, tool in keys)
+        return f"""// Command {cli} calls the {self.class_name} API from the command line, with a
+// subcommand per operation and a flag per parameter and request body field:
+//
+//\t{cli} users list --limit 50 --json
+//\t{cli} users create --name Ada --email ada@example.com
+//\t{cli} users update --id 42 --body @user.json
+//
+// It reads the base URL and credentials from the same environment variables
+// and profiles as NewClientFromEnv. Run it without arguments to list the
+// commands, or with -h after a command to list its flags. Fields without a
+// flag of their own, such as nested objects, are passed in --body as JSON,
+// which the field flags then override.
+//
+// Results are printed as text, lists as tables, unless --json asks for the
+// JSON response. With --dry-run the request is printed as a curl command,
+// or as JSON with --json, instead of being sent. Failed calls exit with
+// status 1.
+package main
+
+import (
+\t"context"
+\t"encoding/json"
+\t"errors"
+\t"flag"
+\t"fmt"
+\t"io"
+\t"os"
+\t"os/signal"
+\t"sort"
+\t"strconv"
+\t"strings"
+\t"text/tabwriter"
+
+\tapi "{sdk_import}"
+\t"{module_path}/agenttool"
+)
+
+// commands maps each subcommand to the client tool it calls.
+var commands = map[string]string{{
+{commands}}}
+
+func main() {{
+\tctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+\terr := run(ctx, os.Args[1:], os.Stdout)
+\tstop()
+\tif errors.Is(err, flag.ErrHelp) {{
+\t\tos.Exit(2)
+\t}}
+\tif err != nil {{
+\t\tfmt.Fprintln(os.Stderr, "{cli}:", err)
+\t\tos.Exit(1)
+\t}}
+}}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {{
+\tclient, err := api.NewClientFromEnv()
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tname, args := splitCommand(args)
+\ttoolName, ok := commands[name]
+\tif !ok {{
+\t\tusage(os.Stderr)
+\t\tif name == "" {{
+\t\t\treturn flag.ErrHelp
+\t\t}}
+\t\treturn fmt.Errorf("unknown command %q", name)
+\t}}
+\ttool := agenttool.Find(client.Tools(), toolName)
+\tvar schema schema
+\tif err := json.Unmarshal(tool.InputSchema(), &schema); err != nil {{
+\t\treturn err
+\t}}
+
+\tfs := flag.NewFlagSet(name, flag.ContinueOnError)
+\tfs.Usage = func() {{
+\t\tfmt.Fprintf(fs.Output(), "%s\\n\\nusage: {cli} %s [flags]\\n\\n", tool.Description(), name)
+\t\tfs.PrintDefaults()
+\t}}
+\tasJSON := fs.Bool("json", false, "print the JSON response")
+\tdryRun := fs.Bool("dry-run", false, "print the request as a curl command instead of sending it")
+\tinput := map[string]interface{{}}{{}}
+\trequired := map[string]bool{{}}
+\tfor _, param := range schema.Required {{
+\t\trequired[param] = true
+\t}}
+\tfor param, property := range schema.Properties {{
+\t\tif param != "body" {{
+\t\t\tdefineFlag(fs, flagName(param), property, input, param, "parameter", required[param])
+\t\t}}
+\t}}
+\tbodySchema := schema.Properties["body"]
+\tvar bodyJSON *string
+\tbodyFields := map[string]interface{{}}{{}}
+\tif bodySchema != nil {{
+\t\tbodyJSON = fs.String("body", "", "the request body as JSON, @file to read it from a file, or @- from stdin")
+\t\tfor field, property := range bodySchema.Properties {{
+\t\t\tdefineFlag(fs, flagName(field), property, bodyFields, field, "request body field", false)
+\t\t}}
+\t}}
+\tif err := fs.Parse(args); err != nil {{
+\t\treturn err
+\t}}
+\tif fs.NArg() > 0 {{
+\t\treturn fmt.Errorf("unexpected argument %q", fs.Arg(0))
+\t}}
+\tfor param := range required {{
+\t\tif _, ok := input[param]; !ok && param != "body" {{
+\t\t\treturn fmt.Errorf("missing --%s", flagName(param))
+\t\t}}
+\t}}
+\tif bodySchema != nil {{
+\t\tbody := map[string]interface{{}}{{}}
+\t\tif *bodyJSON != "" {{
+\t\t\tif err := readBody(*bodyJSON, body); err != nil {{
+\t\t\t\treturn fmt.Errorf("--body: %w", err)
+\t\t\t}}
+\t\t}}
+\t\tfor field, value := range bodyFields {{
+\t\t\tbody[field] = value
+\t\t}}
+\t\tif len(body) > 0 || required["body"] {{
+\t\t\tinput["body"] = body
+\t\t}}
+\t}}
+
+\tencoded, err := json.Marshal(input)
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif *dryRun {{
+\t\ttool = agenttool.Find(client.DryRun().Tools(), toolName)
+\t}}
+\toutput, err := tool.Invoke(ctx, encoded)
+\tvar preview *api.DryRunError
+\tif errors.As(err, &preview) {{
+\t\tif *asJSON {{
+\t\t\treturn json.NewEncoder(stdout).Encode(preview.Preview)
+\t\t}}
+\t\t_, err := fmt.Fprintln(stdout, preview.Preview.Curl())
+\t\treturn err
+\t}}
+\tif err != nil {{
+\t\treturn err
+\t}}
+\tif *asJSON {{
+\t\t_, err := fmt.Fprintf(stdout, "%s\\n", output)
+\t\treturn err
+\t}}
+\tvar result interface{{}}
+\tif err := json.Unmarshal(output, &result); err != nil {{
+\t\treturn err
+\t}}
+\treturn printText(stdout, result)
+}}
+
+// splitCommand returns the command named by the leading words of args, and
+// the arguments after it.
+func splitCommand(args []string) (string, []string) {{
+\tfor i := len(args); i > 0; i-- {{
+\t\tif name := strings.Join(args[:i], " "); commands[name] != "" {{
+\t\t\treturn name, args[i:]
+\t\t}}
+\t}}
+\twords := 0
+\tfor words < len(args) && !strings.HasPrefix(args[words], "-") {{
+\t\twords++
+\t}}
+\treturn strings.Join(args[:words], " "), args[words:]
+}}
+
+func usage(w io.Writer) {{
+\tnames := make([]string, 0, len(commands))
+\tfor name := range commands {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\tfmt.Fprintln(w, "usage: {cli} <command> [flags]\\n\\ncommands:")
+\tfor _, name := range names {{
+\t\tfmt.Fprintln(w, "  "+name)
+\t}}
+}}
+
+// schema is the part of a tool's JSON Schema the flags are derived from.
+type schema struct {{
+\tType       string             `json:"type"`
+\tProperties map[string]*schema `json:"properties"`
+\tItems      *schema            `json:"items"`
+\tRequired   []string           `json:"required"`
+}}
+
+// flagName turns a parameter or field name into a flag name: per_page and
+// perPage become per-page.
+func flagName(name string) string {{
+\tvar b strings.Builder
+\tfor i, r := range name {{
+\t\tswitch {{
+\t\tcase r == '_' || r == '.':
+\t\t\tr = '-'
+\t\tcase r >= 'A' && r <= 'Z':
+\t\t\tif i > 0 {{
+\t\t\t\tb.WriteByte('-')
+\t\t\t}}
+\t\t\tr += 'a' - 'A'
+\t\t}}
+\t\tb.WriteRune(r)
+\t}}
+\treturn b.String()
+}}
+
+// defineFlag adds a flag setting target[key] for a property of a scalar
+// type or an array of them, which the flag may be repeated for. Other
+// properties, and those whose flag name is taken, have no flag.
+func defineFlag(fs *flag.FlagSet, name string, property *schema, target map[string]interface{{}}, key, role string, required bool) {{
+\tif fs.Lookup(name) != nil {{
+\t\treturn
+\t}}
+\ttyp, repeated := property.Type, false
+\tif typ == "array" && property.Items != nil {{
+\t\ttyp, repeated = property.Items.Type, true
+\t}}
+\tif typ != "string" && typ != "integer" && typ != "number" && typ != "boolean" {{
+\t\treturn
+\t}}
+\tdescription := "`" + typ + "` " + role
+\tif repeated {{
+\t\tdescription += ", repeatable"
+\t}}
+\tif required {{
+\t\tdescription += ", required"
+\t}}
+\tfs.Var(&fieldFlag{{typ: typ, repeated: repeated, target: target, key: key}}, name, description)
+}}
+
+// fieldFlag parses a flag of a JSON Schema type into a JSON value.
+type fieldFlag struct {{
+\ttyp      string
+\trepeated bool
+\ttarget   map[string]interface{{}}
+\tkey      string
+}}
+
+func (f *fieldFlag) String() string {{
+\tif f == nil || f.target[f.key] == nil {{
+\t\treturn ""
+\t}}
+\treturn fmt.Sprint(f.target[f.key])
+}}
+
+func (f *fieldFlag) IsBoolFlag() bool {{ return f.typ == "boolean" && !f.repeated }}
+
+func (f *fieldFlag) Set(value string) error {{
+\tvar parsed interface{{}}
+\tvar err error
+\tswitch f.typ {{
+\tcase "integer":
+\t\tparsed, err = strconv.ParseInt(value, 10, 64)
+\tcase "number":
+\t\tparsed, err = strconv.ParseFloat(value, 64)
+\tcase "boolean":
+\t\tparsed, err = strconv.ParseBool(value)
+\tdefault:
+\t\tparsed = value
+\t}}
+\tif err != nil {{
+\t\treturn fmt.Errorf("not a valid %s", f.typ)
+\t}}
+\tif f.repeated {{
+\t\tvalues, _ := f.target[f.key].([]interface{{}})
+\t\tparsed = append(values, parsed)
+\t}}
+\tf.target[f.key] = parsed
+\treturn nil
+}}
+
+// readBody decodes the value of the --body flag into body.
+func readBody(value string, body map[string]interface{{}}) error {{
+\tdata := []byte(value)
+\tif path, ok := strings.CutPrefix(value, "@"); ok {{
+\t\tvar err error
+\t\tif path == "-" {{
+\t\t\tdata, err = io.ReadAll(os.Stdin)
+\t\t}} else {{
+\t\t\tdata, err = os.ReadFile(path)
+\t\t}}
+\t\tif err != nil {{
+\t\t\treturn err
+\t\t}}
+\t}}
+\treturn json.Unmarshal(data, &body)
+}}
+
+// printText prints a result for reading: a list of objects, or an object
+// holding one, as a table, and other objects as one field per line.
+func printText(w io.Writer, result interface{{}}) error {{
+\tif object, ok := result.(map[string]interface{{}}); ok {{
+\t\tif items := listField(object); items != nil {{
+\t\t\tresult = items
+\t\t}}
+\t}}
+\ttw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+\tswitch result := result.(type) {{
+\tcase []interface{{}}:
+\t\tcolumns := tableColumns(result)
+\t\tif len(columns) == 0 {{
+\t\t\tfor _, item := range result {{
+\t\t\t\tfmt.Fprintln(tw, text(item))
+\t\t\t}}
+\t\t\tbreak
+\t\t}}
+\t\tfmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\\t")))
+\t\tfor _, item := range result {{
+\t\t\tobject, _ := item.(map[string]interface{{}})
+\t\t\tcells := make([]string, len(columns))
+\t\t\tfor i, column := range columns {{
+\t\t\t\tcells[i] = text(object[column])
+\t\t\t}}
+\t\t\tfmt.Fprintln(tw, strings.Join(cells, "\\t"))
+\t\t}}
+\tcase map[string]interface{{}}:
+\t\tkeys := make([]string, 0, len(result))
+\t\tfor key := range result {{
+\t\t\tkeys = append(keys, key)
+\t\t}}
+\t\tsort.Strings(keys)
+\t\tfor _, key := range keys {{
+\t\t\tfmt.Fprintf(tw, "%s:\\t%s\\n", key, text(result[key]))
+\t\t}}
+\tcase nil:
+\tdefault:
+\t\tfmt.Fprintln(tw, text(result))
+\t}}
+\treturn tw.Flush()
+}}
+
+// listField returns the only array of objects in an envelope such as
+// {{"users": [...], "total": 2}}, or nil.
+func listField(object map[string]interface{{}}) []interface{{}} {{
+\tvar list []interface{{}}
+\tfor _, value := range object {{
+\t\tif items, ok := value.([]interface{{}}); ok && len(tableColumns(items)) > 0 {{
+\t\t\tif list != nil {{
+\t\t\t\treturn nil
+\t\t\t}}
+\t\t\tlist = items
+\t\t}}
+\t}}
+\treturn list
+}}
+
+// tableColumns returns the scalar fields of a list of objects, in sorted
+// order with id first, or nil when the list does not hold objects.
+func tableColumns(items []interface{{}}) []string {{
+\tseen := map[string]bool{{}}
+\tvar columns []string
+\tfor _, item := range items {{
+\t\tobject, ok := item.(map[string]interface{{}})
+\t\tif !ok {{
+\t\t\treturn nil
+\t\t}}
+\t\tfor key, value := range object {{
+\t\t\tswitch value.(type) {{
+\t\t\tcase map[string]interface{{}}, []interface{{}}:
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\tif !seen[key] {{
+\t\t\t\tseen[key] = true
+\t\t\t\tcolumns = append(columns, key)
+\t\t\t}}
+\t\t}}
+\t}}
+\tsort.Slice(columns, func(i, j int) bool {{
+\t\tif (columns[i] == "id") != (columns[j] == "id") {{
+\t\t\treturn columns[i] == "id"
+\t\t}}
+\t\treturn columns[i] < columns[j]
+\t}})
+\treturn columns
+}}
+
+// text formats a value for a table cell or field line: scalars as they
+// are, anything else as JSON.
+func text(value interface{{}}) string {{
+\tswitch value := value.(type) {{
+\tcase nil:
+\t\treturn ""
+\tcase string:
+\t\treturn value
+\tcase float64:
+\t\treturn strconv.FormatFloat(value, 'f', -1, 64)
+\tcase bool:
+\t\treturn strconv.FormatBool(value)
+\t}}
+\tencoded, _ := json.Marshal(value)
+\treturn string(encoded)
+}}
+"""
+    
+    def _generate_go_compare(self, sdk_import: str, module_path: str) -> str:
+        operations, _ = self._selftest_operations()
+        strconv = '\t"strconv"\n' if 'strconv.' in operations else ''
+        return f"""// Command compare makes the same read-only calls against two deployments of
+// the {self.class_name} API, such as production and staging or the original and a
+// reimplementation, and reports where their responses differ. Each side is a
+// base URL, using the credentials from the environment, or the name of a
+// profile:
+//
+//\tgo run ./cmd/compare -a prod -b https://staging.example.com -arg id=42
+//
+// Identifiers and timestamps differ between deployments by design, so
+// their values are masked before comparing unless -strict is given; their
+// presence and type still count. It exits with status 1 if any response
+// differs.
+package main
+
+import (
+\t"bytes"
+\t"context"
+\t"flag"
+\t"fmt"
+\t"io"
+\t"net/http"
+\t"os"
+\t"sort"
+{strconv}\t"strings"
+\t"time"
+
+\tapi "{sdk_import}"
+\t"{module_path}/jsondiff"
+)
+
+// operation is a read-only call compare can make.
+type operation struct {{
+\tparams []string
+\tcall   func(ctx context.Context, c *api.{self.class_name}Client, args map[string]string) error
+}}
+
+// operations are the captured GET operations.
+var operations = map[string]operation{{
+{operations}}}
+
+type listFlags []string
+
+func (l *listFlags) String() string {{ return strings.Join(*l, ", ") }}
+
+func (l *listFlags) Set(value string) error {{
+\t*l = append(*l, value)
+\treturn nil
+}}
+
+type argFlags map[string]string
+
+func (a argFlags) String() string {{ return fmt.Sprint(map[string]string(a)) }}
+
+func (a argFlags) Set(value string) error {{
+\tname, arg, ok := strings.Cut(value, "=")
+\tif !ok {{
+\t\treturn fmt.Errorf("want name=value, got %q", value)
+\t}}
+\ta[name] = arg
+\treturn nil
+}}
+
+func main() {{
+\targs := argFlags{{}}
+\tvar endpoints, ignore, unordered listFlags
+\ta := flag.String("a", "", "first deployment: a base URL, or a profile name")
+\tb := flag.String("b", "", "second deployment: a base URL, or a profile name")
+\ttimeout := flag.Duration("timeout", 30*time.Second, "timeout for each call")
+\tstrict := flag.Bool("strict", false, "compare identifier and timestamp values too")
+\tlist := flag.Bool("list", false, "list the operations -endpoint accepts and exit")
+\tflag.Var(&endpoints, "endpoint", "operation to compare, such as \\"GET /v1/users\\"; repeatable (default: every operation whose path parameters are given)")
+\tflag.Var(args, "arg", "path parameter as name=value; repeatable")
+\tflag.Var(&ignore, "ignore", "field name or path (such as $.meta.total) to leave out; repeatable")
+\tflag.Var(&unordered, "unordered", "path of an array (such as $.users[*].roles) to compare regardless of order, or * for all; repeatable")
+\tflag.Parse()
+
+\tnames := make([]string, 0, len(operations))
+\tfor name := range operations {{
+\t\tnames = append(names, name)
+\t}}
+\tsort.Strings(names)
+\tif *list {{
+\t\tfor _, name := range names {{
+\t\t\tfmt.Println(name)
+\t\t}}
+\t\treturn
+\t}}
+\tif *a == "" || *b == "" {{
+\t\tfmt.Fprintln(os.Stderr, "compare: -a and -b are required")
+\t\tflag.Usage()
+\t\tos.Exit(2)
+\t}}
+\tif len(endpoints) == 0 {{
+\t\tfor _, name := range names {{
+\t\t\tif hasArgs(operations[name].params, args) {{
+\t\t\t\tendpoints = append(endpoints, name)
+\t\t\t}}
+\t\t}}
+\t}}
+
+\tleft, err := newSide(*a)
+\tif err != nil {{
+\t\tfatal(err)
+\t}}
+\tright, err := newSide(*b)
+\tif err != nil {{
+\t\tfatal(err)
+\t}}
+\topts := jsondiff.Options{{
+\t\tIgnore:           ignore,
+\t\tIgnoreTimestamps: !*strict,
+\t\tIgnoreIDs:        !*strict,
+\t\tUnordered:        unordered,
+\t}}
+
+\tfmt.Printf("comparing %s (a) with %s (b)\\n", left.client.BaseURL, right.client.BaseURL)
+\tdiffering := 0
+\tfor _, name := range endpoints {{
+\t\top, ok := operations[name]
+\t\tif !ok {{
+\t\t\tfatal(fmt.Errorf("unknown operation %q; run with -list", name))
+\t\t}}
+\t\tif !hasArgs(op.params, args) {{
+\t\t\tfatal(fmt.Errorf("%s needs -arg for %s", name, strings.Join(op.params, ", ")))
+\t\t}}
+\t\tresultA := left.call(op, args, *timeout)
+\t\tresultB := right.call(op, args, *timeout)
+\t\tdiffs := compare(resultA, resultB, opts)
+\t\tif len(diffs) == 0 {{
+\t\t\tfmt.Printf("\\nsame  %s\\n", name)
+\t\t\tcontinue
+\t\t}}
+\t\tdiffering++
+\t\tfmt.Printf("\\ndiff  %s\\n", name)
+\t\tfor _, diff := range diffs {{
+\t\t\tfmt.Printf("      %s\\n", diff)
+\t\t}}
+\t}}
+\tfmt.Printf("\\n%d of %d operations differ\\n", differing, len(endpoints))
+\tif differing > 0 {{
+\t\tos.Exit(1)
+\t}}
+}}
+
+func hasArgs(params []string, args map[string]string) bool {{
+\tfor _, name := range params {{
+\t\tif _, ok := args[name]; !ok {{
+\t\t\treturn false
+\t\t}}
+\t}}
+\treturn true
+}}
+
+func fatal(err error) {{
+\tfmt.Fprintln(os.Stderr, "compare:", err)
+\tos.Exit(2)
+}}
+
+// side is one deployment and the raw response of its last call.
+type side struct {{
+\tclient   *api.{self.class_name}Client
+\trecorder *recorder
+}}
+
+// newSide creates a client for target, a base URL or a profile name. Its
+// transport records raw response bodies, so fields the SDK types do not
+// know are compared too.
+func newSide(target string) (*side, error) {{
+\t// Leave decompression to the transport, so the recorder sees plain bodies.
+\topts := []api.ClientOption{{api.WithAcceptEncoding("")}}
+\tvar client *api.{self.class_name}Client
+\tvar err error
+\tif strings.Contains(target, "://") {{
+\t\tbaseURL := strings.TrimSuffix(target, "/")
+\t\tclient, err = api.NewClientFromEnv(append(opts, func(c *api.{self.class_name}Client) {{ c.BaseURL = baseURL }})...)
+\t}} else {{
+\t\tclient, err = api.NewClientFromProfile(target, opts...)
+\t}}
+\tif err != nil {{
+\t\treturn nil, fmt.Errorf("%s: %w", target, err)
+\t}}
+\thttpClient := *client.HTTPClient
+\trec := &recorder{{next: httpClient.Transport}}
+\tif rec.next == nil {{
+\t\trec.next = http.DefaultTransport
+\t}}
+\thttpClient.Transport = rec
+\tclient.HTTPClient = &httpClient
+\treturn &side{{client: client, recorder: rec}}, nil
+}}
+
+// result is what one call returned.
+type result struct {{
+\tstatus int
+\tbody   []byte
+\terr    error
+}}
+
+func (s *side) call(op operation, args map[string]string, timeout time.Duration) result {{
+\ts.recorder.status, s.recorder.body = 0, nil
+\tctx, cancel := context.WithTimeout(context.Background(), timeout)
+\tdefer cancel()
+\terr := op.call(ctx, s.client, args)
+\tif s.recorder.status != 0 {{
+\t\t// An error status is a response to compare, not a failure to call.
+\t\terr = nil
+\t}}
+\treturn result{{s.recorder.status, s.recorder.body, err}}
+}}
+
+// recorder is an http.RoundTripper keeping the last response's status and
+// body.
+type recorder struct {{
+\tnext   http.RoundTripper
+\tstatus int
+\tbody   []byte
+}}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {{
+\tresp, err := r.next.RoundTrip(req)
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tbody, err := io.ReadAll(resp.Body)
+\tresp.Body.Close()
+\tif err != nil {{
+\t\treturn nil, err
+\t}}
+\tr.status, r.body = resp.StatusCode, body
+\tresp.Body = io.NopCloser(bytes.NewReader(body))
+\treturn resp, nil
+}}
+
+// compare describes the differences between two results.
+func compare(a, b result, opts jsondiff.Options) []string {{
+\tswitch {{
+\tcase a.err != nil || b.err != nil:
+\t\treturn []string{{fmt.Sprintf("! call failed: a: %v, b: %v", a.err, b.err)}}
+\tcase a.status != b.status:
+\t\treturn []string{{fmt.Sprintf("! status %d vs %d", a.status, b.status)}}
+\t}}
+\tdiffs, err := jsondiff.Compare(a.body, b.body, opts)
+\tif err != nil {{
+\t\tif !bytes.Equal(a.body, b.body) {{
+\t\t\treturn []string{{fmt.Sprintf("~ body (not JSON): %d bytes vs %d bytes", len(a.body), len(b.body))}}
+\t\t}}
+\t\treturn nil
+\t}}
+\tlines := make([]string, len(diffs))
+\tfor i, diff := range diffs {{
+\t\tlines[i] = markers[diff.Kind] + " " + diff.String()
+\t}}
+\treturn lines
+}}
+
+var markers = map[jsondiff.Kind]string{{
+\tjsondiff.Changed:     "~",
+\tjsondiff.TypeChanged: "!",
+\tjsondiff.Removed:     "-",
+\tjsondiff.Added:       "+",
+}}
+"""
+    
+    def _generate_go_jsondiff(self) -> str:
+        return """// Package jsondiff compares JSON documents, or values that encode to JSON
+// such as SDK responses, and reports each difference by path
+// ("$.users[0].email") rather than a bare "not equal". Options skip what is
+// expected to differ: named fields, timestamps, identifiers, and the order
+// of arrays.
+//
+//\tdiffs, err := jsondiff.Compare(want, got, jsondiff.Options{
+//\t\tIgnore:           []string{"etag", "$.meta.request_time"},
+//\t\tIgnoreTimestamps: true,
+//\t\tUnordered:        []string{"$.users[*].roles"},
+//\t})
+package jsondiff
+
+import (
+\t"encoding/json"
+\t"fmt"
+\t"regexp"
+\t"sort"
+\t"strings"
+)
+
+// Kind classifies a difference.
+type Kind string
+
+const (
+\tChanged     Kind = "changed"      // scalar values differ
+\tTypeChanged Kind = "type changed" // the values have different JSON types
+\tRemoved     Kind = "removed"      // present in A only
+\tAdded       Kind = "added"        // present in B only
+)
+
+// Difference is one place where two documents differ.
+type Difference struct {
+\tPath string // "$.users[0].email"
+\tKind Kind
+\tA, B interface{} // the decoded values at Path; nil on the side lacking it
+}
+
+func (d Difference) String() string {
+\tswitch d.Kind {
+\tcase Removed:
+\t\treturn fmt.Sprintf("%s: only in a: %s", d.Path, Describe(d.A))
+\tcase Added:
+\t\treturn fmt.Sprintf("%s: only in b: %s", d.Path, Describe(d.B))
+\tcase TypeChanged:
+\t\treturn fmt.Sprintf("%s: %s vs %s", d.Path, TypeOf(d.A), TypeOf(d.B))
+\t}
+\treturn fmt.Sprintf("%s: %s vs %s", d.Path, Describe(d.A), Describe(d.B))
+}
+
+// Options adjusts what Compare reports.
+type Options struct {
+\t// Ignore lists fields to skip, by name ("etag") or by path
+\t// ("$.meta.total"), where "[*]" matches any array index.
+\tIgnore []string
+\t// IgnoreTimestamps skips the values of timestamp fields (named like
+\t// created_at or updatedAt, or holding an ISO 8601 date) as long as both
+\t// sides have one.
+\tIgnoreTimestamps bool
+\t// IgnoreIDs skips the values of identifier fields (id, uuid, user_id,
+\t// userId, etag, or holding a UUID) as long as both sides have one.
+\tIgnoreIDs bool
+\t// Unordered lists the paths of arrays compared regardless of order,
+\t// with "[*]" matching any index; "*" makes every array unordered.
+\tUnordered []string
+\t// Subset reports only what A has that B lacks or holds differently,
+\t// for checking that a response contains an expected fragment.
+\tSubset bool
+}
+
+var (
+\ttimestampFields = regexp.MustCompile(`(?i)^(.+_at|.+At|created|updated|modified|deleted|timestamp|ts|date|time|expires)$`)
+\ttimestampValues = regexp.MustCompile(`^\\d{4}-\\d{2}-\\d{2}([T ]\\d{2}:\\d{2}(:\\d{2}(\\.\\d+)?)?(Z|[+-]\\d{2}:?\\d{2})?)?$`)
+\tidFields        = regexp.MustCompile(`(?i)^(id|uuid|guid|etag|version|request_?id|trace_?id|.+_id|.+Id)$`)
+\tidValues        = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+\tarrayIndex      = regexp.MustCompile(`\\[\\d+\\]`)
+)
+
+// Compare lists the differences between a and b, object fields in sorted
+// order and array items in index order. Each may be raw JSON ([]byte,
+// string, json.RawMessage) or any value that encodes to JSON, so SDK
+// structs, maps, and recorded bodies compare with each other.
+func Compare(a, b interface{}, opts Options) ([]Difference, error) {
+\tleft, err := Normalize(a)
+\tif err != nil {
+\t\treturn nil, fmt.Errorf("jsondiff: a: %w", err)
+\t}
+\tright, err := Normalize(b)
+\tif err != nil {
+\t\treturn nil, fmt.Errorf("jsondiff: b: %w", err)
+\t}
+\tc := &comparer{opts: opts, ignore: set(opts.Ignore), unordered: set(opts.Unordered)}
+\tvar diffs []Difference
+\tc.compare("$", "", left, right, &diffs)
+\treturn diffs, nil
+}
+
+// Equal reports whether a and b have no differences under opts; values that
+// cannot be compared are not equal.
+func Equal(a, b interface{}, opts Options) bool {
+\tdiffs, err := Compare(a, b, opts)
+\treturn err == nil && len(diffs) == 0
+}
+
+// Normalize decodes v into the generic form Compare works on: maps,
+// slices, float64, string, bool, and nil.
+func Normalize(v interface{}) (interface{}, error) {
+\tvar data []byte
+\tswitch v := v.(type) {
+\tcase json.RawMessage:
+\t\tdata = v
+\tcase []byte:
+\t\tdata = v
+\tcase string:
+\t\tdata = []byte(v)
+\tdefault:
+\t\tvar err error
+\t\tif data, err = json.Marshal(v); err != nil {
+\t\t\treturn nil, err
+\t\t}
+\t}
+\tvar out interface{}
+\tif err := json.Unmarshal(data, &out); err != nil {
+\t\treturn nil, err
+\t}
+\treturn out, nil
+}
+
+// TypeOf names the JSON type of a normalized value.
+func TypeOf(v interface{}) string {
+\tswitch v.(type) {
+\tcase nil:
+\t\treturn "null"
+\tcase bool:
+\t\treturn "boolean"
+\tcase float64:
+\t\treturn "number"
+\tcase string:
+\t\treturn "string"
+\tcase []interface{}:
+\t\treturn "array"
+\tdefault:
+\t\treturn "object"
+\t}
+}
+
+// Describe renders a normalized value for messages: scalars as JSON,
+// objects and arrays by their type.
+func Describe(v interface{}) string {
+\tswitch v.(type) {
+\tcase map[string]interface{}:
+\t\treturn "object"
+\tcase []interface{}:
+\t\treturn "array"
+\t}
+\tdata, _ := json.Marshal(v)
+\treturn string(data)
+}
+
+type comparer struct {
+\topts      Options
+\tignore    map[string]bool
+\tunordered map[string]bool
+}
+
+func set(patterns []string) map[string]bool {
+\ts := make(map[string]bool, len(patterns))
+\tfor _, pattern := range patterns {
+\t\ts[pattern] = true
+\t}
+\treturn s
+}
+
+// matches reports whether path is listed in patterns, exactly or with its
+// array indexes replaced by "[*]".
+func matches(patterns map[string]bool, path string) bool {
+\treturn patterns[path] || patterns[arrayIndex.ReplaceAllString(path, "[*]")]
+}
+
+func (c *comparer) ignored(path, name string) bool {
+\treturn (name != "" && c.ignore[name]) || matches(c.ignore, path)
+}
+
+func (c *comparer) isUnordered(path string) bool {
+\treturn c.unordered["*"] || matches(c.unordered, path)
+}
+
+// volatile reports whether a scalar is a timestamp or identifier whose
+// value the options skip.
+func (c *comparer) volatile(name string, v interface{}) bool {
+\ts, _ := v.(string)
+\tif c.opts.IgnoreTimestamps && (timestampFields.MatchString(name) || timestampValues.MatchString(s)) {
+\t\treturn true
+\t}
+\treturn c.opts.IgnoreIDs && (idFields.MatchString(name) || idValues.MatchString(s))
+}
+
+// compare appends the differences between a and b, found at path under the
+// field name, to diffs.
+func (c *comparer) compare(path, name string, a, b interface{}, diffs *[]Difference) {
+\tif TypeOf(a) != TypeOf(b) {
+\t\t*diffs = append(*diffs, Difference{path, TypeChanged, a, b})
+\t\treturn
+\t}
+\tswitch a := a.(type) {
+\tcase map[string]interface{}:
+\t\tb := b.(map[string]interface{})
+\t\tkeys := make([]string, 0, len(a)+len(b))
+\t\tfor key := range a {
+\t\t\tkeys = append(keys, key)
+\t\t}
+\t\tfor key := range b {
+\t\t\tif _, ok := a[key]; !ok && !c.opts.Subset {
+\t\t\t\tkeys = append(keys, key)
+\t\t\t}
+\t\t}
+\t\tsort.Strings(keys)
+\t\tfor _, key := range keys {
+\t\t\tchild := path + "." + key
+\t\t\tif c.ignored(child, key) {
+\t\t\t\tcontinue
+\t\t\t}
+\t\t\tvalueA, inA := a[key]
+\t\t\tvalueB, inB := b[key]
+\t\t\tswitch {
+\t\t\tcase !inB:
+\t\t\t\t*diffs = append(*diffs, Difference{child, Removed, valueA, nil})
+\t\t\tcase !inA:
+\t\t\t\t*diffs = append(*diffs, Difference{child, Added, nil, valueB})
+\t\t\tdefault:
+\t\t\t\tc.compare(child, key, valueA, valueB, diffs)
+\t\t\t}
+\t\t}
+\tcase []interface{}:
+\t\tb := b.([]interface{})
+\t\tif c.isUnordered(path) {
+\t\t\tc.compareUnordered(path, name, a, b, diffs)
+\t\t\treturn
+\t\t}
+\t\tfor i := 0; i < len(a) || i < len(b); i++ {
+\t\t\tchild := fmt.Sprintf("%s[%d]", path, i)
+\t\t\tswitch {
+\t\t\tcase i >= len(b):
+\t\t\t\t*diffs = append(*diffs, Difference{child, Removed, a[i], nil})
+\t\t\tcase i >= len(a):
+\t\t\t\tif !c.opts.Subset {
+\t\t\t\t\t*diffs = append(*diffs, Difference{child, Added, nil, b[i]})
+\t\t\t\t}
+\t\t\tdefault:
+\t\t\t\tc.compare(child, name, a[i], b[i], diffs)
+\t\t\t}
+\t\t}
+\tdefault:
+\t\tif a != b && !(c.volatile(name, a) && c.volatile(name, b)) {
+\t\t\t*diffs = append(*diffs, Difference{path, Changed, a, b})
+\t\t}
+\t}
+}
+
+// compareUnordered pairs each item of a with an equal, unpaired item of b
+// and reports the items left over on either side.
+func (c *comparer) compareUnordered(path, name string, a, b []interface{}, diffs *[]Difference) {
+\tpaired := make([]bool, len(b))
+\tfor i, item := range a {
+\t\tfound := false
+\t\tfor j := range b {
+\t\t\tif paired[j] {
+\t\t\t\tcontinue
+\t\t\t}
+\t\t\tvar itemDiffs []Difference
+\t\t\tc.compare(fmt.Sprintf("%s[%d]", path, i), name, item, b[j], &itemDiffs)
+\t\t\tif len(itemDiffs) == 0 {
+\t\t\t\tpaired[j], found = true, true
+\t\t\t\tbreak
+\t\t\t}
+\t\t}
+\t\tif !found {
+\t\t\t*diffs = append(*diffs, Difference{fmt.Sprintf("%s[%d]", path, i), Removed, item, nil})
+\t\t}
+\t}
+\tif c.opts.Subset {
+\t\treturn
+\t}
+\tfor j, item := range b {
+\t\tif !paired[j] {
+\t\t\t*diffs = append(*diffs, Difference{fmt.Sprintf("%s[%d]", path, j), Added, nil, item})
+\t\t}
+\t}
+}
+
+// Format lists diffs one per line, for test failures and reports.
+func Format(diffs []Difference) string {
+\tlines := make([]string, len(diffs))
+\tfor i, diff := range diffs {
+\t\tlines[i] = diff.String()
+\t}
+\treturn strings.Join(lines, "\\n")
+}
+"""
+    
+    def _generate_go_export(self, package_name: str, export_import: str) -> str:
+        specs, methods = self._generate_go_export_specs()
+        return f"""package {package_name}
+
+import (
+\t"bytes"
+\t"context"
+\t"encoding/json"
+\t"fmt"
+\t"net/http"
+\t"net/url"
+\t"regexp"
+\t"strconv"
+\t"strings"
+
+\t"{export_import}"
+)
+
+// ExportOptions adjusts an export.
+type ExportOptions struct {{
+\t// Columns selects and orders columns by name, or by dotted prefix for
+\t// nested objects; empty means every column.
+\tColumns []string
+\t// PageSize is the number of items to request per page; zero means the
+\t// page size seen in the captured traffic, or the API's default.
+\tPageSize int
+\t// MaxRows stops the export after this many rows; zero means all.
+\tMaxRows int
+\t// RequestOptions apply to every page request.
+\tRequestOptions []RequestOption
+}}
+
+// exportSpec describes how a list endpoint pages and what its items hold.
+// An endpoint with no page or offset parameter and no cursor to follow is
+// read in one request.
+type exportSpec struct {{
+\tendpoint    string
+\titems       string // the field holding the items; empty for a bare array
+\tpageParam   string // 1-based page number
+\toffsetParam string
+\tlimitParam  string
+\tpageSize    int
+\tmoreField   string // a boolean saying whether more pages follow
+\ttotalField  string // the total number of items
+\tcursorParam string // takes the cursor of the next page
+\tnextField   string // dotted path of the next page's cursor, or URL
+\tnextLink    bool   // the Link header gives the next page's URL
+\tafterField  string // the field of the last item that is the next cursor
+\tcolumns     []export.Column
+}}
+
+// exportSpecs are the captured list endpoints, with columns derived from
+// the fields of their items.
+var exportSpecs = map[string]exportSpec{{{specs}}}
+
+// ExportColumns returns the columns an export of operation, such as
+// "GET /v1/users", writes, or nil if the operation has no export.
+func ExportColumns(operation string) []export.Column {{
+\treturn append([]export.Column(nil), exportSpecs[operation].columns...)
+}}
+
+{methods}// export writes every item of spec's endpoint to w, then closes w. It
+// returns the number of rows written.
+func (c *{self.class_name}Client) export(ctx context.Context, spec exportSpec, w export.Writer, opts ExportOptions) (int, error) {{
+\tcolumns := spec.columns
+\tif len(opts.Columns) > 0 {{
+\t\tvar err error
+\t\tif columns, err = export.Select(columns, opts.Columns...); err != nil {{
+\t\t\treturn 0, err
+\t\t}}
+\t}}
+\tif err := w.WriteHeader(columns); err != nil {{
+\t\treturn 0, err
+\t}}
+\trows := 0
+\terr := c.listPages(ctx, spec, opts.PageSize, nil, opts.RequestOptions, func(items []map[string]interface{{}}) (bool, error) {{
+\t\tfor _, item := range items {{
+\t\t\tif opts.MaxRows > 0 && rows >= opts.MaxRows {{
+\t\t\t\treturn false, nil
+\t\t\t}}
+\t\t\tif err := w.WriteRow(export.Row(columns, item)); err != nil {{
+\t\t\t\treturn false, err
+\t\t\t}}
+\t\t\trows++
+\t\t}}
+\t\treturn opts.MaxRows <= 0 || rows < opts.MaxRows, nil
+\t}})
+\tif err != nil {{
+\t\treturn rows, fmt.Errorf("export: %w", err)
+\t}}
+\treturn rows, w.Close()
+}}
+
+// listPages requests spec's endpoint with params page after page, passing
+// each page's items to fn, until the API reports no more or fn returns
+// false. A zero pageSize means the captured page size.
+func (c *{self.class_name}Client) listPages(ctx context.Context, spec exportSpec, pageSize int, params url.Values, opts []RequestOption, fn func(items []map[string]interface{{}}) (bool, error)) error {{
+\tif pageSize <= 0 {{
+\t\tpageSize = spec.pageSize
+\t}}
+\tseen := 0
+\tvar previous, cursor string
+\tfor page := 1; ; page++ {{
+\t\tquery := url.Values{{}}
+\t\tfor name, values := range params {{
+\t\t\tquery[name] = values
+\t\t}}
+\t\tif spec.limitParam != "" && pageSize > 0 {{
+\t\t\tquery.Set(spec.limitParam, strconv.Itoa(pageSize))
+\t\t}}
+\t\tif spec.pageParam != "" {{
+\t\t\tquery.Set(spec.pageParam, strconv.Itoa(page))
+\t\t}} else if spec.offsetParam != "" {{
+\t\t\tquery.Set(spec.offsetParam, strconv.Itoa(seen))
+\t\t}}
+\t\tif cursor != "" {{
+\t\t\tsetCursor(query, spec.cursorParam, cursor)
+\t\t}}
+\t\tvar meta ResponseMeta
+\t\tbody, err := c.doRequest(ctx, "GET", spec.endpoint, spec.endpoint, query, nil, append(opts[:len(opts):len(opts)], recordMeta(&meta))...)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("%s page %d: %w", spec.endpoint, page, err)
+\t\t}}
+\t\titems, envelope, err := parseExportPage(body, spec.items)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("%s page %d: %w", spec.endpoint, page, err)
+\t\t}}
+\t\tif len(items) > 0 {{
+\t\t\t// An API ignoring the paging parameters would repeat its first
+\t\t\t// page forever.
+\t\t\tfirst, _ := json.Marshal(items[0])
+\t\t\tif page > 1 && string(first) == previous {{
+\t\t\t\treturn fmt.Errorf("%s: page %d repeats page %d", spec.endpoint, page, page-1)
+\t\t\t}}
+\t\t\tprevious = string(first)
+\t\t}}
+\t\tnext, err := fn(items)
+\t\tif err != nil || !next {{
+\t\t\treturn err
+\t\t}}
+\t\tseen += len(items)
+\t\tcursor = spec.nextCursor(meta.Header, envelope, items)
+\t\tif !spec.more(envelope, len(items), seen, pageSize, cursor) {{
+\t\t\treturn nil
+\t\t}}
+\t}}
+}}
+
+// more reports whether another page follows one of got items, seen in
+// total, given the next page's cursor: as the response says, or else while
+// pages come back full.
+func (s exportSpec) more(envelope map[string]interface{{}}, got, seen, pageSize int, cursor string) bool {{
+\tcursored := s.nextField != "" || s.nextLink || s.afterField != ""
+\tif (s.pageParam == "" && s.offsetParam == "" && !cursored) || got == 0 || (cursored && cursor == "") {{
+\t\treturn false
+\t}}
+\tif more, ok := envelope[s.moreField].(bool); ok {{
+\t\treturn more
+\t}}
+\tif total, ok := envelope[s.totalField].(json.Number); ok {{
+\t\tif n, err := total.Int64(); err == nil {{
+\t\t\treturn int64(seen) < n
+\t\t}}
+\t}}
+\tif s.nextField != "" || s.nextLink {{
+\t\treturn true
+\t}}
+\treturn pageSize <= 0 || got >= pageSize
+}}
+
+// nextCursor returns the cursor of the page after one answered with header,
+// envelope, and items, or "" if there is none.
+func (s exportSpec) nextCursor(header http.Header, envelope map[string]interface{{}}, items []map[string]interface{{}}) string {{
+\tswitch {{
+\tcase s.nextField != "":
+\t\tvar value interface{{}} = envelope
+\t\tfor _, name := range strings.Split(s.nextField, ".") {{
+\t\t\tobject, _ := value.(map[string]interface{{}})
+\t\t\tvalue = object[name]
+\t\t}}
+\t\tcursor, _ := value.(string)
+\t\treturn cursor
+\tcase s.nextLink:
+\t\treturn nextLink(header)
+\tcase s.afterField != "" && len(items) > 0:
+\t\tif value := items[len(items)-1][s.afterField]; value != nil {{
+\t\t\treturn fmt.Sprint(value)
+\t\t}}
+\t}}
+\treturn ""
+}}
+
+// linkNextPattern matches the URL of the next page in a Link header.
+var linkNextPattern = regexp.MustCompile(`<([^>]*)>\\s*;[^,]*\\brel="?next"?`)
+
+// nextLink returns the URL of the next page in header's Link, or "".
+func nextLink(header http.Header) string {{
+\tfor _, value := range header.Values("Link") {{
+\t\tif match := linkNextPattern.FindStringSubmatch(value); match != nil {{
+\t\t\treturn match[1]
+\t\t}}
+\t}}
+\treturn ""
+}}
+
+// setCursor sends the cursor of the next page with query: the query
+// parameters of a next-page URL, or else cursor as param.
+func setCursor(query url.Values, param, cursor string) {{
+\tif link, err := url.Parse(cursor); err == nil && (link.IsAbs() || strings.HasPrefix(cursor, "/")) {{
+\t\tfor name, values := range link.Query() {{
+\t\t\tquery[name] = values
+\t\t}}
+\t\treturn
+\t}}
+\tquery.Set(param, cursor)
+}}
+
+// parseExportPage decodes a page's items and, for an enveloped list, the
+// envelope's other fields. Numbers stay json.Number so that large ids keep
+// their precision.
+func parseExportPage(body []byte, field string) ([]map[string]interface{{}}, map[string]interface{{}}, error) {{
+\tdecoder := json.NewDecoder(bytes.NewReader(body))
+\tdecoder.UseNumber()
+\tvar page interface{{}}
+\tif err := decoder.Decode(&page); err != nil {{
+\t\treturn nil, nil, err
+\t}}
+\tvar envelope map[string]interface{{}}
+\tlist := page
+\tif field != "" {{
+\t\tenvelope, _ = page.(map[string]interface{{}})
+\t\tlist = envelope[field]
+\t}}
+\tvalues, ok := list.([]interface{{}})
+\tif !ok && list != nil {{
+\t\treturn nil, nil, fmt.Errorf("response has no %q array", field)
+\t}}
+\titems := make([]map[string]interface{{}}, 0, len(values))
+\tfor _, value := range values {{
+\t\tif item, ok := value.(map[string]interface{{}}); ok {{
+\t\t\titems = append(items, item)
+\t\t}}
+\t}}
+\treturn items, envelope, nil
+}}
+"""
+    
+    EXPORT_LIMIT_PARAMS = ('limit', 'per_page', 'page_size')
+    EXPORT_TOTAL_FIELDS = ('total', 'total_count', 'count')
+    
+    def _export_specs(self) -> List[Dict[str, Any]]:
+        """The captured GET endpoints without path parameters answering with
+        a list of objects, with how they page and the columns of their items."""
+        specs = []
+        names = set()
+        for endpoint in sorted(self.endpoints.values(), key=lambda e: e.path_pattern):
+            if endpoint.method != 'GET' or endpoint.path_params:
+                continue
+            schema = next((schema for status, schema in sorted(endpoint.response_schemas.items())
+                           if 200 <= status < 300), {})
+            field, items, meta = '', None, {}
+            if schema.get('type') == 'object':
+                for key, prop in schema.get('properties', {}).items():
+                    if prop.get('type') == 'array' and prop.get('items', {}).get('type') == 'object' and items is None:
+                        field, items = key, prop['items']
+                    elif key in self.LIST_META_FIELDS:
+                        meta[key] = prop
+            elif schema.get('type') == 'array' and schema.get('items', {}).get('type') == 'object':
+                items = schema['items']
+            if not items or not items.get('properties'):
+                continue
+            params = set(endpoint.query_params) | set(meta)
+            cursor = {} if 'page' in params or 'offset' in params else self._list_cursor(endpoint, schema, items)
+            limit = next((name for name in self.EXPORT_LIMIT_PARAMS if name in params), '')
+            page_size = meta.get(limit, {}).get('example')
+            name = self._go_name(endpoint)
+            name = 'Export' + (name[len('List'):] if name.startswith('List') and len(name) > len('List') else name)
+            while name in names:
+                name += 'Items'
+            names.add(name)
+            specs.append({
+                'name': name,
+                'endpoint': endpoint.path_pattern,
+                'items': field,
+                'page': 'page' if 'page' in params else '',
+                'offset': 'offset' if 'offset' in params else '',
+                'limit': limit,
+                'page_size': page_size if isinstance(page_size, int) and not isinstance(page_size, bool) else 0,
+                'more': 'has_more' if meta.get('has_more', {}).get('type') == 'boolean' else '',
+                'total': next((name for name in self.EXPORT_TOTAL_FIELDS if name in meta), ''),
+                'cursor': cursor.get('cursor', ''),
+                'next': cursor.get('next', ''),
+                'link': cursor.get('link', False),
+                'after': cursor.get('after', ''),
+                'columns': self._export_columns(items),
+                'query': dict(endpoint.query_params),
+            })
+        return specs
+    
+    CURSOR_PARAMS = ('cursor', 'page_token', 'pageToken', 'next_token', 'nextToken', 'continuation_token',
+                     'continuationToken')
+    CURSOR_NEXT_FIELDS = ('next_cursor', 'nextCursor', 'next_page_token', 'nextPageToken', 'next_token', 'nextToken',
+                          'continuation_token', 'continuationToken', 'end_cursor', 'endCursor', 'next_page', 'next')
+    CURSOR_CONTAINERS = ('meta', 'metadata', 'pagination', 'paging', 'page_info', 'pageInfo', 'response_metadata',
+                         'links', 'cursor')
+    CURSOR_AFTER_PARAMS = ('starting_after', 'startingAfter', 'after', 'after_id', 'afterId')
+    
+    def _list_cursor(self, endpoint: APIEndpoint, schema: Dict[str, Any], items: Dict[str, Any]) -> Dict[str, Any]:
+        """How a list endpoint without page or offset parameters reaches its
+        next page, if by a cursor: 'next', the dotted path of the response
+        field holding it, as "meta.next_cursor"; or 'link', for a Link
+        header with rel="next"; or 'after', the field of the last item to
+        send, as Stripe's starting_after takes the last id. 'cursor' is the
+        query parameter taking it, which a next field naming a URL replaces."""
+        import re
+        properties = schema.get('properties', {}) if schema.get('type') == 'object' else {}
+        fields = {(name,): prop for name, prop in properties.items()}
+        for container in self.CURSOR_CONTAINERS:
+            for name, prop in (properties.get(container, {}).get('properties') or {}).items():
+                fields[(container, name)] = prop
+        param = next((name for name in self.CURSOR_PARAMS if name in endpoint.query_params), '')
+        for name in self.CURSOR_NEXT_FIELDS:
+            path = next((path for path, prop in fields.items() if path[-1] == name and prop.get('type') == 'string'), None)
+            if path:
+                derived = 'after' if name in ('end_cursor', 'endCursor') else re.sub(r'^next_?', '', name)
+                return {'cursor': param or (derived[:1].lower() + derived[1:]) or 'cursor', 'next': '.'.join(path)}
+        for example in endpoint.examples:
+            headers = example.get('response', {}).get('headers', {})
+            if any(key.lower() == 'link' and re.search(r'rel="?next"?', str(value)) for key, value in headers.items()):
+                return {'cursor': param, 'link': True}
+        after = next((name for name in self.CURSOR_AFTER_PARAMS if name in endpoint.query_params), '')
+        if after and 'id' in items.get('properties', {}):
+            return {'cursor': after, 'after': 'id'}
+        return {}
+    
+    def _export_columns(self, schema: Dict[str, Any], path: Tuple[str, ...] = ()) -> List[Tuple[Tuple[str, ...], str]]:
+        """(path, export type) per column of items matching schema, id first;
+        fields of nested objects become columns of their own, two levels deep."""
+        columns = []
+        properties = schema.get('properties', {})
+        for key in sorted(properties, key=lambda key: (key != 'id', key)):
+            prop = properties[key]
+            if prop.get('type') == 'object' and prop.get('properties') and len(path) < 2:
+                columns.extend(self._export_columns(prop, path + (key,)))
+                continue
+            kind = {'integer': 'Integer', 'number': 'Number', 'boolean': 'Boolean', 'string': 'String'}.get(prop.get('type'), 'JSON')
+            if kind == 'String' and prop.get('format') == 'date-time':
+                kind = 'Timestamp'
+            columns.append((path + (key,), kind))
+        return columns
+    
+    def _generate_go_export_specs(self) -> Tuple[str, str]:
+        """The exportSpecs entries and the Export method of each list endpoint."""
+        import textwrap
+        entries, methods = [], []
+        for spec in self._export_specs():
+            operation = f"GET {spec['endpoint']}"
+            lines = [f'\t"{operation}": {{']
+            fields = [[f'{field}:', f'"{spec[key]}",'] for field, key in (
+                ('endpoint', 'endpoint'), ('items', 'items'), ('pageParam', 'page'), ('offsetParam', 'offset'),
+                ('limitParam', 'limit'), ('moreField', 'more'), ('totalField', 'total'), ('cursorParam', 'cursor'),
+                ('nextField', 'next'), ('afterField', 'after')) if spec[key]]
+            if spec['link']:
+                fields.append(['nextLink:', 'true,'])
+            if spec['page_size']:
+                fields.append(['pageSize:', f'{spec["page_size"]},'])
+            lines.extend(self._align_go_columns(fields, indent='\t\t'))
+            lines.append('\t\tcolumns: []export.Column{')
+            for path, kind in spec['columns']:
+                quoted = ', '.join(f'"{name}"' for name in path)
+                lines.append(f'\t\t\t{{Name: "{".".join(path)}", Path: []string{{{quoted}}}, Type: export.{kind}}},')
+            lines.extend(['\t\t},', '\t},'])
+            entries.append('\n'.join(lines))
+            paging = 'page by page' if spec['page'] or spec['offset'] else 'in one request'
+            if spec['next'] or spec['link'] or spec['after']:
+                paging = 'following the cursor of each page'
+            doc = textwrap.fill(f"{spec['name']} writes every item of {operation} to w, {paging}, then closes w. "
+                                "It returns the number of rows written.", width=74)
+            methods.append(
+                textwrap.indent(doc, '// ') + "\n"
+                f"func (c *{self.class_name}Client) {spec['name']}(ctx context.Context, w export.Writer, opts ExportOptions) (int, error) {{\n"
+                f"\treturn c.export(ctx, exportSpecs[\"{operation}\"], w, opts)\n"
+                f"}}\n"
+            )
+        specs = ''.join('\n' + entry for entry in entries) + ('\n' if entries else '')
+        return specs, ''.join(method + '\n' for method in methods)
+    
+    def _pager_specs(self) -> List[Dict[str, Any]]:
+        """The list endpoints paging by a page or offset query parameter, or
+        by a cursor, with the Go expressions a Pager reads their pages with."""
+        by_path = {e.path_pattern: e for e in self.endpoints.values() if e.method == 'GET'}
+        field = lambda name: ''.join(word.capitalize() for word in name.split('_'))
+        specs = []
+        for spec in self._export_specs():
+            endpoint = by_path[spec['endpoint']]
+            query_params = self._go_query_params(endpoint)
+            param = spec['page'] or spec['offset']
+            cursored = spec['next'] or spec['link'] or spec['after']
+            if not cursored and (not param or param not in query_params):
+                continue
+            schema = endpoint.response_schemas.get(200, {})
+            properties = schema.get('properties', {})
+            items_schema = properties[spec['items']] if spec['items'] else schema
+            if items_schema.get('type') != 'array':
+                continue
+            total = spec['total'] if properties.get(spec['total'], {}).get('type') == 'integer' else ''
+            # An enveloped list is the method's result, its paging fields the meta's.
+            unwrapped = spec['items'] and spec['items'] == self._go_envelope(endpoint)
+            source = 'meta' if unwrapped else 'result'
+            next_field = spec['next'].split('.') if spec['next'] else []
+            if len(next_field) > 1 and id(properties.get(next_field[0])) not in self.struct_types:
+                continue  # a map, without a field to read
+            leaf = properties[next_field[0]]['properties'][next_field[1]] if len(next_field) > 1 else properties.get(spec['next'], {})
+            if spec['link'] and unwrapped and self.result_types:
+                continue  # WithMeta hides the response's headers
+            specs.append({
+                'name': self._go_name(endpoint),
+                'operation': f"GET {spec['endpoint']}",
+                'item_type': self._schema_to_type_hint(items_schema, 'go')[len('[]'):],
+                'items': f"result.{field(spec['items'])}" if spec['items'] and not unwrapped else 'result',
+                'page_field': self._go_param_field(spec['page']) if spec['page'] else '',
+                'offset_field': self._go_param_field(spec['offset']) if spec['offset'] else '',
+                'limit_field': self._go_param_field(spec['limit']) if spec['limit'] in query_params else '',
+                'page_size': spec['page_size'],
+                'more': f"&{source}.{field(spec['more'])}" if spec['more'] else '',
+                'total': f"{source}.{field(total)}" if total else '-1',
+                'with_meta': bool(unwrapped and (spec['more'] or total or next_field)),
+                'params': bool(query_params),
+                'cursor_param': spec['cursor'],
+                'next': f"{source}." + '.'.join(field(name) for name in next_field) if next_field else '',
+                'next_pointer': self._schema_to_type_hint(leaf, 'go').startswith('*'),
+                'link': spec['link'],
+                'after': field(spec['after']) if spec['after'] else '',
+            })
+        return specs
+    
+    def _generate_go_pager_methods(self, specs: List[Dict[str, Any]]) -> str:
+        """The Pager and All methods of each paged list endpoint."""
+        methods = []
+        for spec in specs:
+            name, item = spec['name'], spec['item_type']
+            if spec['next'] or spec['link'] or spec['after']:
+                methods.append(self._generate_go_cursor_pager_methods(spec))
+                continue
+            if spec['page_field']:
+                start = f"query.{spec['page_field']}, 0"
+                advance = f"\t\tquery.{spec['page_field']} = page"
+                paging = f"starting from params' {spec['page_field']}"
+            else:
+                start = f"1, query.{spec['offset_field']}"
+                advance = f"\t\tquery.{spec['offset_field']} = offset"
+                paging = f"starting from params' {spec['offset_field']}"
+            info = [f"total: {spec['total']}"]
+            if spec['more']:
+                info.insert(0, f"hasMore: {spec['more']}")
+            if spec['limit_field']:
+                info.append(f"pageSize: query.{spec['limit_field']}")
+            elif spec['page_size']:
+                info.append(f"pageSize: {spec['page_size']}")
+            call = f"result, err := c.{name}(ctx, &query, opts...)" + (".Get()" if self.result_types else "")
+            if spec['with_meta']:
+                call = f"result, meta, err := c.{name}WithMeta(ctx, &query, opts...)"
+            lines = [
+                f"// {name}Pager iterates the items of {spec['operation']} page by page,",
+                f"// {paging}.",
+                f"func (c *{self.class_name}Client) {name}Pager(ctx context.Context, params *{name}Params, opts ...RequestOption) *Pager[{item}] {{",
+                f"\tvar query {name}Params",
+                "\tif params != nil {",
+                "\t\tquery = *params",
+                "\t}",
+                f"\treturn newPager(ctx, {start}, func(ctx context.Context, page, offset int) ([]{item}, pageInfo, error) {{",
+                advance,
+                f"\t\t{call}",
+                "\t\tif err != nil {",
+                "\t\t\treturn nil, pageInfo{}, err",
+                "\t\t}",
+                f"\t\tinfo := pageInfo{{{', '.join(info)}}}",
+            ]
+            if spec['limit_field'] and spec['page_size']:
+                lines.extend([
+                    "\t\tif info.pageSize == 0 {",
+                    f"\t\t\tinfo.pageSize = {spec['page_size']} // the captured page size",
+                    "\t\t}",
+                ])
+            lines.extend([
+                f"\t\treturn {spec['items']}, info, nil",
+                "\t})",
+                "}",
+                "",
+                f"// {name}All returns every item of {spec['operation']}, requesting the",
+                "// pages in turn.",
+                f"func (c *{self.class_name}Client) {name}All(ctx context.Context, params *{name}Params, opts ...RequestOption) ([]{item}, error) {{",
+                f"\treturn c.{name}Pager(ctx, params, opts...).All()",
+                "}",
+            ])
+            methods.append('\n'.join(lines))
+        return ''.join('\n' + method + '\n' for method in methods)
+    
+    def _generate_go_cursor_pager_methods(self, spec: Dict[str, Any]) -> str:
+        """The Pager and All methods of a list endpoint paging by a cursor."""
+        name, item = spec['name'], spec['item_type']
+        params = f"params *{name}Params, " if spec['params'] else ''
+        args = '&query, ' if spec['params'] else ''
+        lines = [
+            f"// {name}Pager iterates the items of {spec['operation']} page by page,",
+            "// following the cursor of each page.",
+            f"func (c *{self.class_name}Client) {name}Pager(ctx context.Context, {params}opts ...RequestOption) *Pager[{item}] {{",
+        ]
+        if spec['params']:
+            lines.extend([
+                f"\tvar query {name}Params",
+                "\tif params != nil {",
+                "\t\tquery = *params",
+                "\t}",
+            ])
+        lines.extend([
+            f"\treturn newCursorPager(ctx, func(ctx context.Context, cursor string) ([]{item}, pageInfo, string, error) {{",
+            "\t\tpageOpts := opts",
+            "\t\tif cursor != \"\" {",
+            f"\t\t\tpageOpts = append(opts[:len(opts):len(opts)], withCursor(\"{spec['cursor_param']}\", cursor))",
+            "\t\t}",
+        ])
+        if spec['with_meta']:
+            lines.append(f"\t\tresult, meta, err := c.{name}WithMeta(ctx, {args}pageOpts...)")
+        elif spec['link'] and self.result_types:
+            lines.extend([
+                f"\t\tresponse := c.{name}(ctx, {args}pageOpts...)",
+                "\t\tresult, err := response.Get()",
+            ])
+        else:
+            call = f"c.{name}(ctx, {args}pageOpts...)" + (".Get()" if self.result_types else "")
+            lines.append(f"\t\tresult, err := {call}")
+        if spec['link'] and not self.result_types:
+            lines.insert(-1, "\t\tvar response ResponseMeta")
+            lines.insert(-1, "\t\tpageOpts = append(pageOpts[:len(pageOpts):len(pageOpts)], recordMeta(&response))")
+        lines.extend([
+            "\t\tif err != nil {",
+            "\t\t\treturn nil, pageInfo{}, \"\", err",
+            "\t\t}",
+            f"\t\titems := {spec['items']}",
+        ])
+        if spec['next_pointer']:
+            lines.extend([
+                "\t\tvar next string",
+                f"\t\tif {spec['next']} != nil {{",
+                f"\t\t\tnext = *{spec['next']}",
+                "\t\t}",
+            ])
+        elif spec['next']:
+            lines.append(f"\t\tnext := {spec['next']}")
+        elif spec['link']:
+            lines.append(f"\t\tnext := nextLink(response.{'Meta.' if self.result_types else ''}Header)")
+        else:
+            lines.extend([
+                "\t\tvar next string",
+                "\t\tif n := len(items); n > 0 {",
+                f"\t\t\tnext = fmt.Sprint(items[n-1].{spec['after']})",
+                "\t\t}",
+            ])
+        info = [f"total: {spec['total']}"]
+        if spec['more']:
+            info.insert(0, f"hasMore: {spec['more']}")
+        # A cursor says whether more pages follow; a last item does not.
+        if spec['after'] and spec['limit_field'] and spec['params']:
+            info.append(f"pageSize: query.{spec['limit_field']}")
+        elif spec['after'] and spec['page_size']:
+            info.append(f"pageSize: {spec['page_size']}")
+        lines.extend([
+            f"\t\treturn items, pageInfo{{{', '.join(info)}}}, next, nil",
+            "\t})",
+            "}",
+            "",
+            f"// {name}All returns every item of {spec['operation']}, following the",
+            "// cursor of each page.",
+            f"func (c *{self.class_name}Client) {name}All(ctx context.Context, {params}opts ...RequestOption) ([]{item}, error) {{",
+            f"\treturn c.{name}Pager(ctx, {'params, ' if spec['params'] else ''}opts...).All()",
+            "}",
+        ])
+        return '\n'.join(lines)
+    
+    def _generate_go_pager(self, package_name: str, specs: List[Dict[str, Any]]) -> str:
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"encoding/json"
+\t"fmt"
+\t"net/url"
+)
+
+// Pager iterates the items of a paged list endpoint, requesting the next
+// page when the current one runs out:
+//
+//\tit := client.{specs[0]['name']}Pager(ctx{', nil' if specs[0]['params'] else ''})
+//\tfor it.Next() {{
+//\t\titem := it.Item()
+//\t}}
+//\tif err := it.Err(); err != nil {{
+//\t\treturn err
+//\t}}
+//
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {{
+\tctx   context.Context
+\tfetch func(ctx context.Context, page, offset int) ([]T, pageInfo, error)
+
+\tpage, offset int // of the next request
+\titems        []T
+\tindex        int
+\tpages        int
+\tprevious     string // the first item of the last page, as JSON
+\tdone         bool
+\terr          error
+}}
+
+// pageInfo is what a page says about the pages after it.
+type pageInfo struct {{
+\thasMore  *bool // from a boolean field, when the envelope has one
+\ttotal    int   // from a total field; -1 when the envelope has none
+\tpageSize int   // the page size requested, or captured; 0 if unknown
+}}
+
+// newPager starts at page (1-based) and offset, fetching lazily.
+func newPager[T any](ctx context.Context, page, offset int, fetch func(ctx context.Context, page, offset int) ([]T, pageInfo, error)) *Pager[T] {{
+\tif page < 1 {{
+\t\tpage = 1
+\t}}
+\treturn &Pager[T]{{ctx: ctx, fetch: fetch, page: page, offset: offset, index: -1}}
+}}
+
+// newCursorPager starts at the first page, fetching each later one with the
+// cursor fetch returned with the page before; an empty cursor ends the
+// iteration.
+func newCursorPager[T any](ctx context.Context, fetch func(ctx context.Context, cursor string) ([]T, pageInfo, string, error)) *Pager[T] {{
+\tcursor := ""
+\treturn newPager(ctx, 1, 0, func(ctx context.Context, _, _ int) ([]T, pageInfo, error) {{
+\t\titems, info, next, err := fetch(ctx, cursor)
+\t\tif next == "" {{
+\t\t\tinfo.hasMore = new(bool)
+\t\t}}
+\t\tcursor = next
+\t\treturn items, info, err
+\t}})
+}}
+
+// withCursor sends the cursor of the next page with the call, as the query
+// parameters of a next-page URL, or else as param.
+func withCursor(param, cursor string) RequestOption {{
+\treturn func(o *requestOptions) {{
+\t\tif o.query == nil {{
+\t\t\to.query = make(url.Values)
+\t\t}}
+\t\tsetCursor(o.query, param, cursor)
+\t}}
+}}
+
+// Next advances to the next item, requesting the next page if needed. It
+// returns false when the items are exhausted or a request fails; check Err
+// to tell which.
+func (p *Pager[T]) Next() bool {{
+\tfor p.index+1 >= len(p.items) {{
+\t\tif p.done || p.err != nil {{
+\t\t\treturn false
+\t\t}}
+\t\tp.nextPage()
+\t}}
+\tp.index++
+\treturn true
+}}
+
+// Item returns the current item.
+func (p *Pager[T]) Item() T {{
+\treturn p.items[p.index]
+}}
+
+// NextPage requests the next page, reporting whether it has items; Page
+// then returns them, for handling items a page at a time:
+//
+//\tfor it.NextPage() {{
+//\t\tupsert(it.Page())
+//\t}}
+func (p *Pager[T]) NextPage() bool {{
+\tif p.done || p.err != nil {{
+\t\treturn false
+\t}}
+\tp.nextPage()
+\treturn len(p.items) > 0
+}}
+
+// Page returns the items of the current page.
+func (p *Pager[T]) Page() []T {{
+\treturn p.items
+}}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Pager[T]) Err() error {{
+\treturn p.err
+}}
+
+func (p *Pager[T]) nextPage() {{
+\titems, info, err := p.fetch(p.ctx, p.page, p.offset)
+\tif err != nil {{
+\t\tp.items, p.index, p.err = nil, -1, err
+\t\treturn
+\t}}
+\tif len(items) > 0 {{
+\t\t// An API ignoring the paging parameters would repeat its first
+\t\t// page forever.
+\t\tfirst, _ := json.Marshal(items[0])
+\t\tif p.pages > 0 && string(first) == p.previous {{
+\t\t\tp.items, p.index = nil, -1
+\t\t\tp.err = fmt.Errorf("page %d repeats page %d", p.page, p.page-1)
+\t\t\treturn
+\t\t}}
+\t\tp.previous = string(first)
+\t}}
+\tp.pages++
+\tp.page++
+\tp.offset += len(items)
+\tp.items, p.index = items, -1
+\tp.done = !info.more(len(items), p.offset)
+}}
+
+// more reports whether another page follows one of got items, seen in
+// total: as the page says, or else while pages come back full.
+func (i pageInfo) more(got, seen int) bool {{
+\tswitch {{
+\tcase got == 0:
+\t\treturn false
+\tcase i.hasMore != nil:
+\t\treturn *i.hasMore
+\tcase i.total >= 0:
+\t\treturn seen < i.total
+\t}}
+\treturn i.pageSize <= 0 || got >= i.pageSize
+}}
+
+// All requests every remaining page and returns their items.
+func (p *Pager[T]) All() ([]T, error) {{
+\tvar all []T
+\tfor p.Next() {{
+\t\tall = append(all, p.Item())
+\t}}
+\treturn all, p.Err()
+}}
+{self._generate_go_pager_methods(specs)}"""
+    
+    POLL_STATE_FIELDS = ('status', 'state', 'phase')
+    POLL_SUCCEEDED_STATES = ('completed', 'complete', 'succeeded', 'success', 'successful', 'done', 'finished')
+    POLL_FAILED_STATES = ('failed', 'failure', 'error', 'errored', 'cancelled', 'canceled', 'expired', 'rejected',
+                          'aborted', 'timed_out')
+    
+    def _poll_specs(self) -> List[Dict[str, Any]]:
+        """The GET endpoints of single resources, such as jobs, that the capture
+        shows callers polling until the work they track finishes: fetched with
+        an ID a POST returned, or fetched repeatedly, while a status, state,
+        or phase field reaches a terminal value such as "completed" or
+        "failed". Each with the Go names its WaitFor method needs."""
+        import re
+        from urllib.parse import urlparse
+        terminal = set(self.POLL_SUCCEEDED_STATES + self.POLL_FAILED_STATES)
+        
+        def payload(endpoint, example):
+            body = self._example_json(example.get('response', {}).get('body'))
+            envelope = self._go_envelope(endpoint)
+            return body.get(envelope) if envelope and isinstance(body, dict) else body
+        
+        created = set()
+        for endpoint in self.endpoints.values():
+            if endpoint.method != 'POST':
+                continue
+            for example in endpoint.examples:
+                body = payload(endpoint, example)
+                if isinstance(body, dict):
+                    created.update(str(value) for name, value in body.items()
+                                   if re.fullmatch(r'(\w+_)?id|\w+Id', name)
+                                   and isinstance(value, (str, int)) and not isinstance(value, bool))
+        
+        signatures = {name: (params, results) for _, name, params, results in self._go_client_signatures()}
+        specs = []
+        for endpoint in self.endpoints.values():
+            if (endpoint.method != 'GET' or not endpoint.path_pattern.endswith('}')
+                    or self._go_query_params(endpoint) or not self._go_json_call(endpoint)):
+                continue
+            status = self._go_success_status(endpoint)
+            schema = endpoint.response_schemas.get(status) or {}
+            if self._go_envelope(endpoint):
+                schema = schema['properties'][self._go_envelope(endpoint)]
+            properties = schema.get('properties') or {}
+            field = next((name for name in self.POLL_STATE_FIELDS if properties.get(name, {}).get('type') == 'string'), '')
+            if not field:
+                continue
+            states, paths = set(), []
+            for example in endpoint.examples:
+                body = payload(endpoint, example)
+                if isinstance(body, dict) and isinstance(body.get(field), str):
+                    states.add(body[field].lower())
+                paths.append(urlparse(example.get('request', {}).get('url', '')).path.rstrip('/'))
+            polled = len(paths) > len(set(paths)) or any(path.rsplit('/', 1)[-1] in created for path in paths)
+            name = self._go_name(endpoint)
+            wait = 'WaitFor' + self._go_resource(endpoint)
+            if wait in signatures or any(spec['wait'] == wait for spec in specs):
+                wait = 'WaitFor' + name
+            params, results = signatures[name]
+            resource = re.fullmatch(r'Result\[(\w+)\]' if self.result_types else r'\(\*(\w+), error\)', results)
+            go_field, go_type, _ = self._go_field(field, properties[field])
+            if not (states & terminal and polled and resource) or wait in signatures or go_type.startswith('*'):
+                continue
+            specs.append({
+                'name': name,
+                'wait': wait,
+                'operation': f"GET {endpoint.path_pattern}",
+                'type': resource.group(1),
+                'params': params[1:-1],
+                'field': go_field,
+                'states': sorted(states & terminal),
+            })
+        return specs
+    
+    def _generate_go_poll_methods(self, specs: List[Dict[str, Any]]) -> str:
+        """The WaitFor method of each polled resource."""
+        import textwrap
+        methods = []
+        for spec in specs:
+            args = ', '.join(param.split(' ')[0] for param in spec['params'])
+            call = f"c.{spec['name']}(ctx, {args}, opts...)"
+            states = ', '.join(f'"{state}"' for state in spec['states'][:2])
+            if self.result_types:
+                fetch = [
+                    f"\t\tvalue, err := {call}.Get()",
+                    "\t\tif err != nil {",
+                    "\t\t\treturn false, err",
+                    "\t\t}",
+                    "\t\tresult = &value",
+                ]
+            else:
+                fetch = [
+                    "\t\tvar err error",
+                    f"\t\tresult, err = {call}",
+                    "\t\tif err != nil {",
+                    "\t\t\treturn false, err",
+                    "\t\t}",
+                ]
+            doc = textwrap.fill(f"{spec['wait']} polls {spec['name']} until the {spec['field']} it returns is terminal "
+                                f"({states}), and returns the last result. Polls are interval apart at first, "
+                                "backing off from there; timeout, if positive, bounds the wait. A failure state "
+                                "returns a *WaitError too.", width=74)
+            methods.append('\n'.join([
+                textwrap.indent(doc, '// '),
+                f"func (c *{self.class_name}Client) {spec['wait']}(ctx context.Context, {', '.join(spec['params'])}, interval, timeout time.Duration, opts ...RequestOption) (*{spec['type']}, error) {{",
+                f"\tvar result *{spec['type']}",
+                "\terr := pollUntil(ctx, interval, timeout, func(ctx context.Context) (bool, error) {",
+                *fetch,
+                f"\t\treturn terminalState(\"{spec['operation']}\", string(result.{spec['field']}))",
+                "\t})",
+                "\treturn result, err",
+                "}",
+            ]))
+        return ''.join('\n' + method + '\n' for method in methods)
+    
+    def _generate_go_poll(self, package_name: str, specs: List[Dict[str, Any]]) -> str:
+        import json
+        succeeded = ', '.join(json.dumps(state) for state in self.POLL_SUCCEEDED_STATES)
+        failed = ', '.join(json.dumps(state) for state in self.POLL_FAILED_STATES)
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"fmt"
+\t"strings"
+\t"time"
+)
+
+// Polling defaults for the WaitFor methods of async operations, such as
+// jobs a POST starts and a GET reports on.
+const (
+\tdefaultPollInterval = time.Second
+\tmaxPollInterval     = time.Minute
+)
+
+// succeededStates and failedStates are the states, in lower case, that end
+// an async operation.
+var (
+\tsucceededStates = stateSet({succeeded})
+\tfailedStates    = stateSet({failed})
+)
+
+func stateSet(states ...string) map[string]bool {{
+\tset := make(map[string]bool, len(states))
+\tfor _, state := range states {{
+\t\tset[state] = true
+\t}}
+\treturn set
+}}
+
+// WaitError reports an async operation that ended in a failure state.
+type WaitError struct {{
+\tOperation string // "GET /v1/jobs/{{id}}"
+\tState     string // "failed", "cancelled", ...
+}}
+
+func (e *WaitError) Error() string {{
+\treturn fmt.Sprintf("%s: operation ended in state %q", e.Operation, e.State)
+}}
+
+// terminalState reports whether state ends an async operation, and whether
+// it ended in failure.
+func terminalState(operation, state string) (bool, error) {{
+\tlower := strings.ToLower(state)
+\tif failedStates[lower] {{
+\t\treturn true, &WaitError{{Operation: operation, State: state}}
+\t}}
+\treturn succeededStates[lower], nil
+}}
+
+// pollUntil calls poll until it reports done or fails, waiting interval
+// (defaultPollInterval if zero) after the first call and half as long again
+// after each later one, up to maxPollInterval. It gives up with the
+// context's error when ctx is done or timeout, if positive, runs out.
+func pollUntil(ctx context.Context, interval, timeout time.Duration, poll func(ctx context.Context) (bool, error)) error {{
+\tif timeout > 0 {{
+\t\tvar cancel context.CancelFunc
+\t\tctx, cancel = context.WithTimeout(ctx, timeout)
+\t\tdefer cancel()
+\t}}
+\tif interval <= 0 {{
+\t\tinterval = defaultPollInterval
+\t}}
+\tfor {{
+\t\tdone, err := poll(ctx)
+\t\tif done || err != nil {{
+\t\t\treturn err
+\t\t}}
+\t\ttimer := time.NewTimer(interval)
+\t\tselect {{
+\t\tcase <-ctx.Done():
+\t\t\ttimer.Stop()
+\t\t\treturn ctx.Err()
+\t\tcase <-timer.C:
+\t\t}}
+\t\tif interval += interval / 2; interval > maxPollInterval {{
+\t\t\tinterval = maxPollInterval
+\t\t}}
+\t}}
+}}
+{self._generate_go_poll_methods(specs)}"""
+    
+    def _generate_go_export_package(self) -> str:
+        return """// Package export writes API list items as rows of a table, for loading into
+// spreadsheets, dataframes, and warehouses. Generated SDKs derive each list
+// endpoint's columns from the fields of its items and stream every page into
+// a Writer:
+//
+//\tf, _ := os.Create("users.parquet")
+//\tdefer f.Close()
+//\tn, err := client.ExportUsers(ctx, export.NewParquet(f), ExportOptions{})
+//
+// Nested objects become dotted columns ("profile.bio"); arrays and objects
+// without known fields are written as JSON text.
+package export
+
+import (
+\t"encoding/csv"
+\t"encoding/json"
+\t"fmt"
+\t"io"
+\t"strconv"
+\t"strings"
+\t"time"
+)
+
+// Type is the type of a column's values.
+type Type int
+
+const (
+\tString    Type = iota // string
+\tInteger               // int64
+\tNumber                // float64
+\tBoolean               // bool
+\tTimestamp             // time.Time, from an RFC 3339 string
+\tJSON                  // string holding the value's JSON encoding
+)
+
+func (t Type) String() string {
+\tswitch t {
+\tcase Integer:
+\t\treturn "integer"
+\tcase Number:
+\t\treturn "number"
+\tcase Boolean:
+\t\treturn "boolean"
+\tcase Timestamp:
+\t\treturn "timestamp"
+\tcase JSON:
+\t\treturn "json"
+\t}
+\treturn "string"
+}
+
+// Column is one column of an export: the field at Path in each item.
+type Column struct {
+\tName string   // header, such as "profile.bio"
+\tPath []string // JSON field names from the item down to the value
+\tType Type
+}
+
+// Writer receives an export: the columns, then one row per item whose
+// values are nil or of the Go type listed for the column's Type, then Close.
+// Close finishes the output but does not close the underlying io.Writer.
+type Writer interface {
+\tWriteHeader(columns []Column) error
+\tWriteRow(row []interface{}) error
+\tClose() error
+}
+
+// Select returns the columns named by names, in that order. A name may also
+// be a dotted prefix, selecting every column below it ("profile" selects
+// "profile.bio" and "profile.avatar").
+func Select(columns []Column, names ...string) ([]Column, error) {
+\tvar selected []Column
+\tfor _, name := range names {
+\t\tfound := false
+\t\tfor _, column := range columns {
+\t\t\tif column.Name == name || strings.HasPrefix(column.Name, name+".") {
+\t\t\t\tselected = append(selected, column)
+\t\t\t\tfound = true
+\t\t\t}
+\t\t}
+\t\tif !found {
+\t\t\treturn nil, fmt.Errorf("export: no column %q", name)
+\t\t}
+\t}
+\treturn selected, nil
+}
+
+// Row extracts the columns' values from item, a JSON object decoded with
+// json.Decoder.UseNumber. Missing fields, nulls, and values that do not
+// convert to a column's type are nil.
+func Row(columns []Column, item map[string]interface{}) []interface{} {
+\trow := make([]interface{}, len(columns))
+\tfor i, column := range columns {
+\t\trow[i] = convert(lookup(item, column.Path), column.Type)
+\t}
+\treturn row
+}
+
+func lookup(item map[string]interface{}, path []string) interface{} {
+\tvar value interface{} = item
+\tfor _, name := range path {
+\t\tobject, ok := value.(map[string]interface{})
+\t\tif !ok {
+\t\t\treturn nil
+\t\t}
+\t\tvalue = object[name]
+\t}
+\treturn value
+}
+
+func convert(value interface{}, typ Type) interface{} {
+\tif value == nil {
+\t\treturn nil
+\t}
+\tswitch typ {
+\tcase Integer:
+\t\tswitch v := value.(type) {
+\t\tcase json.Number:
+\t\t\tif n, err := v.Int64(); err == nil {
+\t\t\t\treturn n
+\t\t\t}
+\t\t\tif f, err := v.Float64(); err == nil && f == float64(int64(f)) {
+\t\t\t\treturn int64(f)
+\t\t\t}
+\t\tcase string:
+\t\t\tif n, err := strconv.ParseInt(v, 10, 64); err == nil {
+\t\t\t\treturn n
+\t\t\t}
+\t\t}
+\t\treturn nil
+\tcase Number:
+\t\tswitch v := value.(type) {
+\t\tcase json.Number:
+\t\t\tif f, err := v.Float64(); err == nil {
+\t\t\t\treturn f
+\t\t\t}
+\t\tcase string:
+\t\t\tif f, err := strconv.ParseFloat(v, 64); err == nil {
+\t\t\t\treturn f
+\t\t\t}
+\t\t}
+\t\treturn nil
+\tcase Boolean:
+\t\tswitch v := value.(type) {
+\t\tcase bool:
+\t\t\treturn v
+\t\tcase string:
+\t\t\tif b, err := strconv.ParseBool(v); err == nil {
+\t\t\t\treturn b
+\t\t\t}
+\t\t}
+\t\treturn nil
+\tcase Timestamp:
+\t\tif s, ok := value.(string); ok {
+\t\t\tif t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+\t\t\t\treturn t
+\t\t\t}
+\t\t}
+\t\treturn nil
+\tcase String:
+\t\tswitch v := value.(type) {
+\t\tcase string:
+\t\t\treturn v
+\t\tcase json.Number:
+\t\t\treturn v.String()
+\t\tcase bool:
+\t\t\treturn strconv.FormatBool(v)
+\t\t}
+\t}
+\tdata, err := json.Marshal(value)
+\tif err != nil {
+\t\treturn nil
+\t}
+\treturn string(data)
+}
+
+// CSVWriter writes an export as CSV with a header line. Nulls are empty
+// cells and timestamps are RFC 3339.
+type CSVWriter struct {
+\tw *csv.Writer
+}
+
+// NewCSV returns a Writer producing CSV on w.
+func NewCSV(w io.Writer) *CSVWriter {
+\treturn &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Comma sets the field delimiter, such as '\\t' for TSV.
+func (c *CSVWriter) Comma(r rune) *CSVWriter {
+\tc.w.Comma = r
+\treturn c
+}
+
+// WriteHeader implements Writer.
+func (c *CSVWriter) WriteHeader(columns []Column) error {
+\theader := make([]string, len(columns))
+\tfor i, column := range columns {
+\t\theader[i] = column.Name
+\t}
+\treturn c.w.Write(header)
+}
+
+// WriteRow implements Writer.
+func (c *CSVWriter) WriteRow(row []interface{}) error {
+\trecord := make([]string, len(row))
+\tfor i, value := range row {
+\t\tswitch v := value.(type) {
+\t\tcase nil:
+\t\tcase string:
+\t\t\trecord[i] = v
+\t\tcase int64:
+\t\t\trecord[i] = strconv.FormatInt(v, 10)
+\t\tcase float64:
+\t\t\trecord[i] = strconv.FormatFloat(v, 'f', -1, 64)
+\t\tcase bool:
+\t\t\trecord[i] = strconv.FormatBool(v)
+\t\tcase time.Time:
+\t\t\trecord[i] = v.Format(time.RFC3339Nano)
+\t\tdefault:
+\t\t\trecord[i] = fmt.Sprint(v)
+\t\t}
+\t}
+\treturn c.w.Write(record)
+}
+
+// Close implements Writer, flushing buffered rows.
+func (c *CSVWriter) Close() error {
+\tc.w.Flush()
+\treturn c.w.Error()
+}
+"""
+    
+    def _generate_go_export_parquet(self) -> str:
+        return """package export
+
+import (
+\t"bytes"
+\t"encoding/binary"
+\t"errors"
+\t"io"
+\t"math"
+\t"time"
+)
+
+// DefaultRowGroupRows is the number of rows a ParquetWriter buffers before
+// writing them out as a row group.
+const DefaultRowGroupRows = 50000
+
+// ParquetWriter writes an export as a Parquet file of optional columns,
+// PLAIN-encoded and uncompressed so that it needs no dependencies; any
+// Parquet reader loads it. Integers are INT64, numbers DOUBLE, strings and
+// JSON UTF-8 BYTE_ARRAY, and timestamps INT64 microseconds since the epoch
+// in UTC.
+type ParquetWriter struct {
+\t// RowGroupRows overrides DefaultRowGroupRows; larger groups compress
+\t// better in later processing but hold more rows in memory.
+\tRowGroupRows int
+
+\tw         *countingWriter
+\tcolumns   []Column
+\tbuffered  [][]interface{}
+\trowGroups []parquetRowGroup
+\trows      int64
+}
+
+// NewParquet returns a Writer producing a Parquet file on w.
+func NewParquet(w io.Writer) *ParquetWriter {
+\treturn &ParquetWriter{w: &countingWriter{w: w}}
+}
+
+// WriteHeader implements Writer, writing the file's magic number.
+func (p *ParquetWriter) WriteHeader(columns []Column) error {
+\tif p.columns != nil {
+\t\treturn errors.New("export: header already written")
+\t}
+\tp.columns = columns
+\tp.buffered = make([][]interface{}, len(columns))
+\t_, err := p.w.Write([]byte("PAR1"))
+\treturn err
+}
+
+// WriteRow implements Writer.
+func (p *ParquetWriter) WriteRow(row []interface{}) error {
+\tif p.columns == nil {
+\t\treturn errors.New("export: row written before header")
+\t}
+\tfor i := range p.columns {
+\t\tvar value interface{}
+\t\tif i < len(row) {
+\t\t\tvalue = row[i]
+\t\t}
+\t\tp.buffered[i] = append(p.buffered[i], value)
+\t}
+\tlimit := p.RowGroupRows
+\tif limit <= 0 {
+\t\tlimit = DefaultRowGroupRows
+\t}
+\tif len(p.buffered[0]) >= limit {
+\t\treturn p.flush()
+\t}
+\treturn nil
+}
+
+// Close implements Writer, writing the last row group and the footer.
+func (p *ParquetWriter) Close() error {
+\tif p.columns == nil {
+\t\tif err := p.WriteHeader(nil); err != nil {
+\t\t\treturn err
+\t\t}
+\t}
+\tif err := p.flush(); err != nil {
+\t\treturn err
+\t}
+\tfooter := p.footer()
+\tvar length [4]byte
+\tbinary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+\tfor _, data := range [][]byte{footer, length[:], []byte("PAR1")} {
+\t\tif _, err := p.w.Write(data); err != nil {
+\t\t\treturn err
+\t\t}
+\t}
+\treturn nil
+}
+
+type parquetRowGroup struct {
+\trows    int64
+\tsize    int64
+\tcolumns []parquetChunk
+}
+
+type parquetChunk struct {
+\toffset int64 // of the data page header
+\tvalues int64
+\tsize   int64
+}
+
+// flush writes the buffered rows as a row group of one data page per
+// column.
+func (p *ParquetWriter) flush() error {
+\tif len(p.columns) == 0 || len(p.buffered[0]) == 0 {
+\t\treturn nil
+\t}
+\tgroup := parquetRowGroup{rows: int64(len(p.buffered[0]))}
+\tfor i, column := range p.columns {
+\t\tpage := encodePage(column.Type, p.buffered[i])
+\t\tvar header thriftWriter
+\t\theader.i32(1, 0) // type: DATA_PAGE
+\t\theader.i32(2, int32(len(page)))
+\t\theader.i32(3, int32(len(page)))
+\t\theader.beginStruct(5) // data_page_header
+\t\theader.i32(1, int32(len(p.buffered[i])))
+\t\theader.i32(2, 0) // encoding: PLAIN
+\t\theader.i32(3, 3) // definition_level_encoding: RLE
+\t\theader.i32(4, 3) // repetition_level_encoding: RLE
+\t\theader.endStruct()
+\t\theader.endStruct()
+
+\t\tchunk := parquetChunk{offset: p.w.n, values: int64(len(p.buffered[i]))}
+\t\tfor _, data := range [][]byte{header.buf.Bytes(), page} {
+\t\t\tif _, err := p.w.Write(data); err != nil {
+\t\t\t\treturn err
+\t\t\t}
+\t\t}
+\t\tchunk.size = p.w.n - chunk.offset
+\t\tgroup.size += chunk.size
+\t\tgroup.columns = append(group.columns, chunk)
+\t\tp.buffered[i] = p.buffered[i][:0]
+\t}
+\tp.rowGroups = append(p.rowGroups, group)
+\tp.rows += group.rows
+\treturn nil
+}
+
+// encodePage encodes values as a version 1 data page body: the definition
+// levels (1 for a value, 0 for null) in the RLE hybrid encoding, then the
+// non-null values PLAIN-encoded.
+func encodePage(typ Type, values []interface{}) []byte {
+\tvar levels bytes.Buffer
+\tfor i := 0; i < len(values); {
+\t\tdefined := values[i] != nil
+\t\trun := 1
+\t\tfor i+run < len(values) && (values[i+run] != nil) == defined {
+\t\t\trun++
+\t\t}
+\t\twriteUvarint(&levels, uint64(run)<<1)
+\t\tif defined {
+\t\t\tlevels.WriteByte(1)
+\t\t} else {
+\t\t\tlevels.WriteByte(0)
+\t\t}
+\t\ti += run
+\t}
+
+\tvar page bytes.Buffer
+\tvar length [4]byte
+\tbinary.LittleEndian.PutUint32(length[:], uint32(levels.Len()))
+\tpage.Write(length[:])
+\tpage.Write(levels.Bytes())
+
+\tvar bits, nbits byte
+\tvar word [8]byte
+\tfor _, value := range values {
+\t\tswitch v := value.(type) {
+\t\tcase nil:
+\t\t\tcontinue
+\t\tcase bool:
+\t\t\tif v {
+\t\t\t\tbits |= 1 << nbits
+\t\t\t}
+\t\t\tif nbits++; nbits == 8 {
+\t\t\t\tpage.WriteByte(bits)
+\t\t\t\tbits, nbits = 0, 0
+\t\t\t}
+\t\tcase int64:
+\t\t\tbinary.LittleEndian.PutUint64(word[:], uint64(v))
+\t\t\tpage.Write(word[:])
+\t\tcase float64:
+\t\t\tbinary.LittleEndian.PutUint64(word[:], math.Float64bits(v))
+\t\t\tpage.Write(word[:])
+\t\tcase time.Time:
+\t\t\tbinary.LittleEndian.PutUint64(word[:], uint64(v.UnixMicro()))
+\t\t\tpage.Write(word[:])
+\t\tcase string:
+\t\t\tbinary.LittleEndian.PutUint32(length[:], uint32(len(v)))
+\t\t\tpage.Write(length[:])
+\t\t\tpage.WriteString(v)
+\t\t}
+\t}
+\tif typ == Boolean && nbits > 0 {
+\t\tpage.WriteByte(bits)
+\t}
+\treturn page.Bytes()
+}
+
+// Parquet physical and converted types.
+const (
+\tparquetBoolean   = 0
+\tparquetInt64     = 2
+\tparquetDouble    = 5
+\tparquetByteArray = 6
+
+\tparquetUTF8            = 0
+\tparquetTimestampMicros = 10
+\tparquetJSON            = 19
+)
+
+func physicalType(typ Type) int32 {
+\tswitch typ {
+\tcase Integer, Timestamp:
+\t\treturn parquetInt64
+\tcase Number:
+\t\treturn parquetDouble
+\tcase Boolean:
+\t\treturn parquetBoolean
+\t}
+\treturn parquetByteArray
+}
+
+// footer encodes the FileMetaData.
+func (p *ParquetWriter) footer() []byte {
+\tvar t thriftWriter
+\tt.i32(1, 1) // version
+\tt.beginList(2, thriftStruct, len(p.columns)+1)
+\tt.beginElement() // the root schema element groups the columns
+\tt.str(4, "schema")
+\tt.i32(5, int32(len(p.columns)))
+\tt.endStruct()
+\tfor _, column := range p.columns {
+\t\tt.beginElement()
+\t\tt.i32(1, physicalType(column.Type))
+\t\tt.i32(3, 1) // repetition_type: OPTIONAL
+\t\tt.str(4, column.Name)
+\t\tswitch column.Type {
+\t\tcase String:
+\t\t\tt.i32(6, parquetUTF8)
+\t\tcase JSON:
+\t\t\tt.i32(6, parquetJSON)
+\t\tcase Timestamp:
+\t\t\tt.i32(6, parquetTimestampMicros)
+\t\t}
+\t\tt.endStruct()
+\t}
+\tt.i64(3, p.rows)
+\tt.beginList(4, thriftStruct, len(p.rowGroups))
+\tfor _, group := range p.rowGroups {
+\t\tt.beginElement()
+\t\tt.beginList(1, thriftStruct, len(group.columns))
+\t\tfor i, chunk := range group.columns {
+\t\t\tcolumn := p.columns[i]
+\t\t\tt.beginElement()
+\t\t\tt.i64(2, chunk.offset)
+\t\t\tt.beginStruct(3) // meta_data
+\t\t\tt.i32(1, physicalType(column.Type))
+\t\t\tt.beginList(2, thriftI32, 2)
+\t\t\tt.varint(0) // PLAIN
+\t\t\tt.varint(3) // RLE
+\t\t\tt.beginList(3, thriftBinary, 1)
+\t\t\tt.bytes(column.Name)
+\t\t\tt.i32(4, 0) // codec: UNCOMPRESSED
+\t\t\tt.i64(5, chunk.values)
+\t\t\tt.i64(6, chunk.size)
+\t\t\tt.i64(7, chunk.size)
+\t\t\tt.i64(9, chunk.offset)
+\t\t\tt.endStruct()
+\t\t\tt.endStruct()
+\t\t}
+\t\tt.i64(2, group.size)
+\t\tt.i64(3, group.rows)
+\t\tt.endStruct()
+\t}
+\tt.str(6, "export")
+\tt.endStruct()
+\treturn t.buf.Bytes()
+}
+
+// thriftWriter encodes structs in the Thrift compact protocol, which
+// Parquet uses for its page headers and footer. The top-level struct is
+// implicit; end it with endStruct.
+type thriftWriter struct {
+\tbuf  bytes.Buffer
+\tlast []int16 // the previous field id of each enclosing struct
+\tprev int16
+}
+
+const (
+\tthriftI32    = 5
+\tthriftI64    = 6
+\tthriftBinary = 8
+\tthriftList   = 9
+\tthriftStruct = 12
+)
+
+func (t *thriftWriter) field(id int16, typ byte) {
+\tif delta := id - t.prev; delta > 0 && delta <= 15 {
+\t\tt.buf.WriteByte(byte(delta)<<4 | typ)
+\t} else {
+\t\tt.buf.WriteByte(typ)
+\t\tt.varint(int64(id))
+\t}
+\tt.prev = id
+}
+
+func (t *thriftWriter) varint(v int64) {
+\twriteUvarint(&t.buf, uint64(v<<1)^uint64(v>>63))
+}
+
+func (t *thriftWriter) bytes(s string) {
+\twriteUvarint(&t.buf, uint64(len(s)))
+\tt.buf.WriteString(s)
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+\tt.field(id, thriftI32)
+\tt.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+\tt.field(id, thriftI64)
+\tt.varint(v)
+}
+
+func (t *thriftWriter) str(id int16, s string) {
+\tt.field(id, thriftBinary)
+\tt.bytes(s)
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+\tt.field(id, thriftStruct)
+\tt.beginElement()
+}
+
+// beginElement opens a struct without a field header, as the elements of
+// a list are.
+func (t *thriftWriter) beginElement() {
+\tt.last = append(t.last, t.prev)
+\tt.prev = 0
+}
+
+func (t *thriftWriter) endStruct() {
+\tt.buf.WriteByte(0)
+\tif n := len(t.last); n > 0 {
+\t\tt.prev = t.last[n-1]
+\t\tt.last = t.last[:n-1]
+\t}
+}
+
+// beginList starts a list field of size elements of type elem, written
+// next: structs with beginElement, integers with varint, strings with bytes.
+func (t *thriftWriter) beginList(id int16, elem byte, size int) {
+\tt.field(id, thriftList)
+\tif size < 15 {
+\t\tt.buf.WriteByte(byte(size)<<4 | elem)
+\t} else {
+\t\tt.buf.WriteByte(0xf0 | elem)
+\t\twriteUvarint(&t.buf, uint64(size))
+\t}
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+\tvar scratch [binary.MaxVarintLen64]byte
+\tbuf.Write(scratch[:binary.PutUvarint(scratch[:], v)])
+}
+
+// countingWriter tracks the offset that Parquet metadata refers to.
+type countingWriter struct {
+\tw io.Writer
+\tn int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+\tn, err := c.w.Write(p)
+\tc.n += int64(n)
+\treturn n, err
+}
+"""
+    
+    def _generate_go_sync(self, package_name: str, specs: List[Dict[str, Any]]) -> str:
+        entries, methods = [], []
+        for spec in specs:
+            operation = f"GET {spec['endpoint']}"
+            fields = [['list:', f'exportSpecs["{operation}"],'], ['cursorField:', f'"{spec["cursor"]}",']]
+            if spec['id']:
+                fields.append(['idField:', f'"{spec["id"]}",'])
+            if spec['since']:
+                fields.append(['sinceParam:', f'"{spec["since"]}",'])
+            if spec['since_unix']:
+                fields.append(['sinceUnix:', 'true,'])
+            entries.append('\n'.join([f'\t"{operation}": {{'] + self._align_go_columns(fields, indent='\t\t') + ['\t},']))
+            methods.append(
+                f"// {spec['name']} creates a sync of the items of {operation}, following their\n"
+                f"// {spec['cursor']} timestamps. name identifies its checkpoint.\n"
+                f"func (c *{self.class_name}Client) {spec['name']}(name string) *Sync {{\n"
+                f"\treturn c.newSync(name, syncSpecs[\"{operation}\"])\n"
+                f"}}\n"
+            )
+        specs = ''.join('\n' + entry for entry in entries) + '\n'
+        methods = ''.join(method + '\n' for method in methods)
+        return f"""package {package_name}
+
+import (
+\t"context"
+\t"crypto/sha256"
+\t"encoding/hex"
+\t"encoding/json"
+\t"fmt"
+\t"net/url"
+\t"strconv"
+\t"strings"
+\t"sync"
+\t"time"
+)
+
+// DefaultSyncOverlap is how far before its checkpoint a sync reads again,
+// catching changes that became visible after later ones, such as writes
+// whose transactions committed out of timestamp order.
+const DefaultSyncOverlap = time.Minute
+
+// syncSpec is what an incremental sync of a list endpoint needs beyond
+// paging through it.
+type syncSpec struct {{
+\tlist        exportSpec
+\tcursorField string // the item timestamp the checkpoint follows
+\tidField     string
+\t// sinceParam filters the list to items changed since a time. Without
+\t// one, every run reads all pages and drops the unchanged items itself.
+\tsinceParam string
+\tsinceUnix  bool // sinceParam takes Unix seconds rather than a timestamp
+}}
+
+// syncSpecs are the captured list endpoints whose items carry a change or
+// creation timestamp.
+var syncSpecs = map[string]syncSpec{{{specs}}}
+
+// SyncRecord is an item that is new or changed since the last run.
+type SyncRecord struct {{
+\tID        string
+\tChangedAt time.Time // the item's timestamp the sync follows
+\tRaw       json.RawMessage
+}}
+
+// Decode unmarshals the item into v.
+func (r SyncRecord) Decode(v interface{{}}) error {{
+\treturn json.Unmarshal(r.Raw, v)
+}}
+
+// key identifies the record's version for deduplication: an item changing
+// again is a new record.
+func (r SyncRecord) key() string {{
+\tid := r.ID
+\tif id == "" {{
+\t\tsum := sha256.Sum256(r.Raw)
+\t\tid = hex.EncodeToString(sum[:16])
+\t}}
+\treturn id + "@" + r.ChangedAt.UTC().Format(time.RFC3339Nano)
+}}
+
+// SyncHandler handles one record. An error stops the run before the
+// checkpoint advances, so records from the failed run that were not
+// handled are delivered again by the next one.
+type SyncHandler func(ctx context.Context, record SyncRecord) error
+
+// Sync pulls the items of a list endpoint that changed since its
+// checkpoint, turning the API into an incremental data source:
+//
+//\tusers := client.SyncUsers("warehouse").
+//\t\tWithCheckpoints(FileCheckpointStore{{Dir: "state"}})
+//\tn, err := users.Run(ctx, func(ctx context.Context, r SyncRecord) error {{
+//\t\treturn upsert(ctx, r.ID, r.Raw)
+//\t}})
+//
+// Records are delivered at least once. Each run reads again from Overlap
+// before the checkpoint and drops the records earlier runs delivered.
+type Sync struct {{
+\t// Overlap overrides DefaultSyncOverlap.
+\tOverlap time.Duration
+\t// PageSize is the number of items to request per page; zero means the
+\t// captured page size.
+\tPageSize int
+\t// RequestOptions apply to every page request.
+\tRequestOptions []RequestOption
+
+\tclient *{self.class_name}Client
+\tspec   syncSpec
+\tname   string
+\tstore  CheckpointStore
+
+\trunning    sync.Mutex // held for a whole run
+\tmu         sync.Mutex
+\tloaded     bool
+\tcheckpoint *Checkpoint
+}}
+
+func (c *{self.class_name}Client) newSync(name string, spec syncSpec) *Sync {{
+\treturn &Sync{{client: c, spec: spec, name: name}}
+}}
+
+{methods}// WithCheckpoints saves the sync's checkpoint to store after every run and
+// resumes from it.
+func (s *Sync) WithCheckpoints(store CheckpointStore) *Sync {{
+\ts.store = store
+\treturn s
+}}
+
+// From starts the sync at since when there is no saved checkpoint, rather
+// than with every item.
+func (s *Sync) From(since time.Time) *Sync {{
+\ts.checkpoint = &Checkpoint{{Name: s.name, Cursor: since.UTC().Format(time.RFC3339Nano)}}
+\treturn s
+}}
+
+// Checkpoint returns the sync's current position: the latest timestamp
+// delivered, and the records delivered within Overlap of it.
+func (s *Sync) Checkpoint() Checkpoint {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tif s.checkpoint == nil {{
+\t\treturn Checkpoint{{Name: s.name}}
+\t}}
+\tcheckpoint := *s.checkpoint
+\tcheckpoint.Seen = append([]string(nil), s.checkpoint.Seen...)
+\treturn checkpoint
+}}
+
+// Run delivers the records changed since the checkpoint to fn, in the
+// order the API lists them, then advances and saves the checkpoint. It
+// returns the number of records delivered. Items whose timestamp cannot be
+// read are delivered by the first run only.
+func (s *Sync) Run(ctx context.Context, fn SyncHandler) (int, error) {{
+\ts.running.Lock()
+\tdefer s.running.Unlock()
+\tif err := s.load(ctx); err != nil {{
+\t\treturn 0, err
+\t}}
+\tvar since time.Time
+\tif s.checkpoint.Cursor != "" {{
+\t\tvar err error
+\t\tif since, err = time.Parse(time.RFC3339Nano, s.checkpoint.Cursor); err != nil {{
+\t\t\treturn 0, fmt.Errorf("sync %s: checkpoint cursor: %w", s.name, err)
+\t\t}}
+\t}}
+\toverlap := s.Overlap
+\tif overlap <= 0 {{
+\t\toverlap = DefaultSyncOverlap
+\t}}
+\tfrom := since.Add(-overlap)
+
+\tlayouts := s.client.timeLayouts
+\tif layouts == nil {{
+\t\tlayouts = TimeLayouts
+\t}}
+\tparams := url.Values{{}}
+\tswitch {{
+\tcase s.spec.sinceParam == "" || since.IsZero():
+\tcase s.spec.sinceUnix:
+\t\tparams.Set(s.spec.sinceParam, strconv.FormatInt(from.Unix(), 10))
+\tdefault:
+\t\tparams.Set(s.spec.sinceParam, strings.Trim(string(layouts.Format(from)), `"`))
+\t}}
+\tseen := make(map[string]bool, len(s.checkpoint.Seen))
+\tfor _, key := range s.checkpoint.Seen {{
+\t\tseen[key] = true
+\t}}
+
+\tlatest, delivered := since, 0
+\tvar keys []string
+\terr := s.client.listPages(ctx, s.spec.list, s.PageSize, params, s.RequestOptions, func(items []map[string]interface{{}}) (bool, error) {{
+\t\tfor _, item := range items {{
+\t\t\tchanged, err := syncTime(layouts, item[s.spec.cursorField])
+\t\t\tif !since.IsZero() && (err != nil || changed.Before(from)) {{
+\t\t\t\tcontinue
+\t\t\t}}
+\t\t\traw, err := json.Marshal(item)
+\t\t\tif err != nil {{
+\t\t\t\treturn false, err
+\t\t\t}}
+\t\t\trecord := SyncRecord{{ID: syncID(item[s.spec.idField]), ChangedAt: changed, Raw: raw}}
+\t\t\tkey := record.key()
+\t\t\tif !seen[key] {{
+\t\t\t\tif err := s.handle(ctx, fn, record); err != nil {{
+\t\t\t\t\treturn false, err
+\t\t\t\t}}
+\t\t\t\tseen[key] = true
+\t\t\t\tkeys = append(keys, key)
+\t\t\t\tdelivered++
+\t\t\t}}
+\t\t\tif changed.After(latest) {{
+\t\t\t\tlatest = changed
+\t\t\t}}
+\t\t}}
+\t\treturn true, nil
+\t}})
+\tif err != nil {{
+\t\t// Keep the cursor, but remember what was delivered so the next run
+\t\t// does not repeat it.
+\t\ts.remember(since, overlap, keys)
+\t\tif saveErr := s.save(ctx); saveErr != nil {{
+\t\t\treturn delivered, fmt.Errorf("sync %s: %w (saving checkpoint: %v)", s.name, err, saveErr)
+\t\t}}
+\t\treturn delivered, fmt.Errorf("sync %s: %w", s.name, err)
+\t}}
+\ts.remember(latest, overlap, keys)
+\treturn delivered, s.save(ctx)
+}}
+
+func (s *Sync) handle(ctx context.Context, fn SyncHandler, record SyncRecord) (err error) {{
+\tdefer recoverPanic("sync "+s.name, 1, &err)
+\tif err := fn(ctx, record); err != nil {{
+\t\treturn fmt.Errorf("record %s: %w", record.key(), err)
+\t}}
+\treturn nil
+}}
+
+// remember moves the checkpoint to latest and adds keys to the delivered
+// records, forgetting those the next run's overlap no longer reaches.
+func (s *Sync) remember(latest time.Time, overlap time.Duration, keys []string) {{
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tif !latest.IsZero() {{
+\t\ts.checkpoint.Cursor = latest.UTC().Format(time.RFC3339Nano)
+\t}}
+\thorizon := latest.Add(-overlap)
+\tvar kept []string
+\tfor _, key := range append(s.checkpoint.Seen, keys...) {{
+\t\tat, err := time.Parse(time.RFC3339Nano, key[strings.LastIndex(key, "@")+1:])
+\t\tif err == nil && !at.Before(horizon) {{
+\t\t\tkept = append(kept, key)
+\t\t}}
+\t}}
+\ts.checkpoint.Seen = kept
+}}
+
+// load restores the saved checkpoint on the first run.
+func (s *Sync) load(ctx context.Context) error {{
+\tif s.loaded {{
+\t\treturn nil
+\t}}
+\ts.mu.Lock()
+\tdefer s.mu.Unlock()
+\tif s.store != nil {{
+\t\tsaved, err := s.store.Load(ctx, s.name)
+\t\tif err != nil {{
+\t\t\treturn fmt.Errorf("sync %s: %w", s.name, err)
+\t\t}}
+\t\tif saved != nil {{
+\t\t\ts.checkpoint = saved
+\t\t}}
+\t}}
+\tif s.checkpoint == nil {{
+\t\ts.checkpoint = &Checkpoint{{Name: s.name}}
+\t}}
+\ts.loaded = true
+\treturn nil
+}}
+
+func (s *Sync) save(ctx context.Context) error {{
+\tif s.store == nil {{
+\t\treturn nil
+\t}}
+\tcheckpoint := s.Checkpoint()
+\tif err := s.store.Save(ctx, &checkpoint); err != nil {{
+\t\treturn fmt.Errorf("sync %s: %w", s.name, err)
+\t}}
+\treturn nil
+}}
+
+// syncTime reads an item's timestamp, a string in one of the accepted
+// layouts or a number of Unix seconds or, if too large for those,
+// milliseconds.
+func syncTime(layouts *TimeLayoutRegistry, v interface{{}}) (time.Time, error) {{
+\tif n, ok := v.(json.Number); ok {{
+\t\tf, err := n.Float64()
+\t\tif err != nil {{
+\t\t\treturn time.Time{{}}, err
+\t\t}}
+\t\tif f >= 1e11 {{
+\t\t\treturn time.UnixMilli(int64(f)).UTC(), nil
+\t\t}}
+\t\treturn time.Unix(0, int64(f*1e9)).UTC(), nil
+\t}}
+\tstamp, _ := json.Marshal(v)
+\treturn layouts.Parse(stamp)
+}}
+
+// syncID renders an item's id field, a string or a number.
+func syncID(v interface{{}}) string {{
+\tswitch v := v.(type) {{
+\tcase string:
+\t\treturn v
+\tcase json.Number:
+\t\treturn v.String()
+\t}}
+\treturn ""
+}}
+"""
+    
+    SYNC_SINCE_PARAMS = ('updated_since', 'updatedSince', 'updated_after', 'updatedAfter', 'modified_since',
+                         'modifiedSince', 'changed_since', 'changedSince', 'since')
+    SYNC_CREATED_PARAMS = ('created_since', 'createdSince', 'created_after', 'createdAfter', 'since')
+    
+    def _sync_specs(self) -> List[Dict[str, Any]]:
+        """The list endpoints with an export whose items carry an update or,
+        failing that, a creation timestamp, with the query parameter that
+        filters them by it, if one was captured."""
+        specs = []
+        for spec in self._export_specs():
+            fields = [path[0] for path, kind in spec['columns'] if len(path) == 1]
+            cursor = next((name for name in self.UPDATED_FIELDS if name in fields), '')
+            params = self.SYNC_SINCE_PARAMS
+            if not cursor:
+                cursor = next((name for name in self.CREATED_FIELDS if name in fields), '')
+                params = self.SYNC_CREATED_PARAMS
+            if not cursor:
+                continue
+            since = next((name for name in params if name in spec['query']), '')
+            specs.append({
+                'name': 'Sync' + spec['name'][len('Export'):],
+                'endpoint': spec['endpoint'],
+                'cursor': cursor,
+                'id': 'id' if 'id' in fields else next((name for name in fields if name.endswith(('_id', 'Id'))), ''),
+                'since': since,
+                'since_unix': spec['query'].get(since) in ('integer', 'number'),
+            })
+        return specs
+    
+    def _generate_go_mod(self, output_dir: str, package_name: str):
+        go_mod = f"""module github.com/example/{package_name}
+
+go 1.20
+"""
+        
+        with open(f"{output_dir}/go.mod", 'w') as f:
+            f.write(go_mod)
+    
+    def _generate_readme(self, output_dir: str):
+        package_name = self._to_snake_case(self.api_name).replace('-', '_')
+        cli, cli_commands = self._go_cli_name(), sorted(self._go_cli_commands())
+        cli_example = next((command for command in cli_commands if command.endswith(' list')), cli_commands[0] if cli_commands else 'help')
+        
+        readme = f"""# {self.class_name} Go SDK
+
+Auto-generated Go SDK for {self.api_name} API.
+
+## Installation
+
+```bash
+go get github.com/example/{package_name}
+```
+
+## Usage
+
+```go
+package main
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "{package_name}"
+)
+
+func main() {{
+    // Initialize the client
+    client := {package_name}.New{self.class_name}Client("{self.base_url}")
+    
+    // Set authentication if needed
+    client.SetAuthToken("your-token-here")
+    
+    // Make API calls; every method takes a context.Context first
+    ctx := context.Background()
+"""
+        
+        example_method = None
+        for endpoint_key, endpoint in self.endpoints.items():
+            if endpoint.method == 'GET' and not endpoint.path_params and self._go_json_call(endpoint):
+                readme += f"    response, err := client.{self._go_name(endpoint)}({', '.join(['ctx'] + ['nil'] * bool(self._go_query_params(endpoint)))})\n"
+                readme += f"    if err != nil {{\n"
+                readme += f"        log.Fatal(err)\n"
+                readme += f"    }}\n"
+                readme += f"    fmt.Printf(\"%+v\\n\", response)\n"
+                break
+        
+        readme += """}}
+```
+
+## Available Methods
+
+"""
+        
+        for endpoint_key, endpoint in self.endpoints.items():
+            readme += f"- `{self._go_name(endpoint)}()` - {endpoint.method} {endpoint.path_pattern}\n"
+        for versions in self._go_facade_versions():
+            newest = versions[0][1]
+            name = self._go_operation_names()[self._go_operation_key(newest)]
+            readme += f"- `{name}()` - {newest.method} {newest.path_pattern}, falling back to {', '.join(e.path_pattern for _, e in versions[1:])}\n"
+        
+        readme += """
+
+## Concurrent Use
+
+A client is safe for concurrent use. `SetAuthToken` and `SetHeader` may be
+called while other goroutines make calls, which send either the old value or
+the new one. To give part of a program different headers without touching
+the shared client, derive one; it shares the connection pool, stats, and
+configuration with the original:
+
+```go
+billing := client.WithHeader("X-Team", "billing")
+asUser := client.WithAuthToken(userToken)
+```
+
+Configure the exported fields, middleware, and options before sharing the
+client, and do not write to `Headers` directly once it is in use.
+
+## Capture Store
+
+The `capturestore` package reads stores written with `cli.py --capture-store`,
+so tests and tools can query recorded samples per endpoint:
+
+```go
+store, err := capturestore.Open("captures")
+for _, record := range store.Samples("GET /v1/users") {
+    body, err := store.Body(record.Response.Body)
+    // ...
+}
+```
+
+Bodies compressed with zstd need a decoder registered via
+`capturestore.RegisterDecoder("zstd", ...)`; gzip works out of the box.
+
+## Test Fixtures
+
+The `fixtures` package generates realistic but synthetic bodies learned from
+the anonymized captures (formats, enum frequencies, ranges, null rates), so
+tests never depend on real customer data. Generators are seedable:
+
+```go
+var users ListUsersResponse // any SDK response type
+err := fixtures.New(42).Fill("GET /v1/users", &users)
+
+body, err := fixtures.New(42).Request("POST /v1/users")
+```
+
+"""
+        lookup = next((e for e in self.endpoints.values() if e.method == 'GET' and e.path_params),
+                      next(iter(self.endpoints.values())))
+        lookup_args = ['ctx'] + ['id'] * len(lookup.path_params) + ['nil'] * (bool(self._go_query_params(lookup)) + bool(lookup.request_body_schema))
+        readme += f"""## Debugging
+
+`WithDebug` prints every request as a copy-pasteable curl command followed by
+the raw response, which makes it easy to compare the SDK with the browser
+traffic it was generated from. Credentials become shell variables:
+
+```go
+client := New{self.class_name}Client("", WithDebug(os.Stderr))
+// curl \\
+//   '{self.base_url}/v1/users' \\
+//   -H "Authorization: $AUTHORIZATION"
+// < HTTP/1.1 200 OK
+// ...
+```
+
+For wire-level detail (exact headers, encodings), `DumpTransport` writes
+`httputil` dumps with credentials redacted and bodies capped. It starts
+disabled and can be toggled at runtime, e.g. from an admin endpoint:
+
+```go
+dump := NewDumpTransport(nil, os.Stderr)
+client := New{self.class_name}Client("", WithDumpTransport(dump))
+dump.SetEnabled(true)
+```
+
+## Dry Runs
+
+To see what a call would send without sending it, make it on the client
+`DryRun` returns, or pass `WithDryRun()` to it. The call prepares its request
+as usual, with headers, credentials, idempotency key, and signature, and
+returns a `*DryRunError` holding the `*http.Request` and a `RequestPreview`:
+its method, URL, headers, and body with credentials redacted, which
+marshals as JSON and prints as a curl command:
+
+```go
+_, err := client.DryRun().{self._go_name(lookup)}({', '.join(lookup_args)})
+var dryRun *DryRunError
+if errors.As(err, &dryRun) {{
+    fmt.Println(dryRun.Preview.Curl())
+}}
+```
+
+Middleware and transports only see requests that are sent, so their changes
+are not in the preview. Rate limits, quotas, and the circuit breaker are
+not consulted, and dry runs are not retried.
+
+## Logging
+
+On Go 1.21 and later, `WithLogger` logs every HTTP attempt to a `log/slog`
+logger, with its operation, method, URL, status, duration, and attempt
+number, and every retry with the wait before it. Authorization, cookies,
+API keys, the client's credential headers, and credential query parameters
+are redacted. `WithLogLevels` sets the levels, including the one at which
+headers are logged:
+
+```go
+logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+client := New{self.class_name}Client("", WithLogger(logger), WithLogLevels(LogLevels{{
+    Success: slog.LevelDebug,
+    Failure: slog.LevelWarn,
+    Retry:   slog.LevelInfo,
+    Headers: slog.LevelDebug,
+}}))
+```
+
+## OpenTelemetry
+
+The `otel` directory is a separate module that instruments the client with
+OpenTelemetry, so the SDK itself does not depend on it. Every call gets a
+client span named after its method (`{self.class_name}.CreateUser`) with the
+`http.request.method`, `url.full`, `server.address`, `server.port`, and
+`http.response.status_code` attributes; the trace context is injected into
+the request headers; and the `{self.class_name.lower()}.client.calls` counter and
+`{self.class_name.lower()}.client.call.duration` histogram record every call. Run
+`go mod tidy` in `otel` once to resolve its dependencies, then:
+
+```go
+import (
+    api "github.com/example/{package_name}"
+    apiotel "github.com/example/{package_name}/otel"
+)
+
+client := api.New{self.class_name}Client("", apiotel.WithTelemetry(
+    apiotel.WithTracerProvider(tracerProvider),
+    apiotel.WithMeterProvider(meterProvider),
+))
+```
+
+Without options the global providers and propagator are used. Other
+tracing libraries can implement `Instrumentation` and pass it to
+`WithInstrumentation`.
+
+## Schema Mismatch Telemetry
+
+Every decoded response is compared with the generated types. Unknown fields,
+missing fields, and values of an unexpected type (which fall back to the zero
+value instead of failing the call) are counted per operation and field:
+
+```go
+client := New{self.class_name}Client("", WithSchemaMismatchHandler(func(m SchemaMismatch) {{
+    log.Printf("schema drift: %s %s %s", m.Operation, m.Kind, m.Field)
+}}))
+
+for _, m := range client.SchemaMismatches() {{
+    fmt.Println(m.Count, m.Operation, m.Kind, m.Field)
+}}
+```
+
+## Nested Types
+
+Objects nested in request and response bodies get structs of their own,
+named after the resource and the field, such as `UserProfile`; the items of
+a list take the singular, or `Item` when that is the resource (`UserItem`).
+Nested objects with the same name but a different shape are numbered. A
+field whose shape changed between captured responses, such as an object in
+one and an array in another, is a `json.RawMessage` to decode as needed.
+Methods return the struct of their 2xx response, so a create answered with
+`201 Created` returns its resource too; operations answered without a body
+return a nil map.
+{self._go_readme_envelopes()}
+## Optional Fields
+
+Request fields the capture shows missing or null, and every field of a
+partial update (`PATCH`, or `PUT` to a single resource), are pointers tagged
+`omitempty`. A nil field is left out of the request, so the server keeps its
+value, while `Ptr` sets one, even to its zero value. Slices and maps are left
+out when empty:
+
+```go
+req := UpdateUserRequest{{Name: Ptr("")}} // sends {{"name": ""}} and nothing else
+```
+
+{self._go_readme_enums()}## Request Validation
+
+Every request struct has a `Validate` method checking what the capture
+showed the server expects: fields never left out or empty in at least
+{self.REQUIRED_MIN_SAMPLES} captured bodies, email and URL formats, enum values, and string
+lengths that never varied. Methods call it before sending, so a bad request
+fails with a `*ValidationError` listing every problem instead of a round
+trip and a `400`:
+
+```go
+_, err := client.CreateUser(ctx, &CreateUserRequest{{Email: "not-an-email"}})
+var invalid *ValidationError
+if errors.As(err, &invalid) {{
+    for _, problem := range invalid.Fields {{
+        log.Printf("%s: %s", problem.Field, problem.Problem)
+    }}
+}}
+```
+
+The checks are only as strict as the capture. If the API accepts something
+they reject, `WithoutRequestValidation()` sends requests unchecked.
+
+## Timestamps
+
+Date and date-time fields use the `Time` type, which embeds `time.Time` and
+parses with the layouts registered in `TimeLayouts`: RFC 3339, SQL-style
+`2006-01-02 15:04:05`, plain dates, Unix seconds and milliseconds, and any
+other formats seen in the captured traffic. Values are emitted as RFC 3339. A
+timestamp matching no layout decodes to the zero time (its text is kept in
+`Raw()`) and is counted as a `type_fallback` mismatch. `null` decodes to the
+zero time, and the zero time encodes as `null`.
+
+Fields the API sends as Unix epoch numbers use `UnixTime` (seconds) or
+`UnixMilliTime` (milliseconds) instead, which decode like `Time` but always
+encode as a number in the same unit:
+
+```go
+req := CreateEventRequest{{StartsAt: NewUnixTime(time.Now())}} // "starts_at": 1700000000
+```
+
+Register extra layouts globally, or give a client its own registry when one
+API disagrees with the rest:
+
+```go
+TimeLayouts.Accept("02/01/2006 15:04")
+
+layouts := NewTimeLayoutRegistry(LayoutUnixMilli, time.RFC3339)
+client := New{self.class_name}Client("", WithTimeLayouts(layouts))
+```
+
+## Decimals and Money
+
+Prices, totals, and other fractional money fields use `Decimal`, an exact
+decimal type, instead of `float64`; `{{"amount", "currency"}}` objects use
+`Money`. Arithmetic is exact, and values encode exactly as they were decoded
+(numeric strings stay strings, `10.50` keeps its scale):
+
+```go
+total := order.Total.Add(order.Tax)                 // exact
+share := total.Div(MustParseDecimal("3"), 2)        // rounded half away from zero
+sum, err := order.Price.Add(NewMoney(total, "USD")) // ErrCurrencyMismatch across currencies
+req.Amount = MustParseDecimal("19.99").AsString()   // send as "19.99"
+```
+
+## Localization
+
+`WithLocale` sets `Accept-Language`, and any vendor locale headers seen in the
+captured traffic, on every call, controlling the language of localized field
+values and error messages. Every method also takes trailing `RequestOption`s
+that apply to that call only, such as a different locale:
+
+```go
+client := New{self.class_name}Client("", WithLocale("fr-CA"))
+posts, err := client.ListPosts(ctx, WithRequestLocale("de-DE"))
+```
+
+## Request Options
+
+Options for one call change that call without touching the client, which
+other goroutines may be using at the same time:
+
+```go
+posts, err := client.ListPosts(ctx,
+\tWithHeader("X-Debug", "1"),
+\tWithQueryParam("include", "author"),
+\tWithTimeout(5*time.Second),
+\tWithRetryPolicy(RetryPolicy{{}}),
+)
+```
+
+`WithHeader` replaces the client's value of the header, and `WithQueryParam`
+any value the method's parameters give the parameter. `WithTimeout` covers
+the call's retries, and reading the body of streams and downloads.
+
+## Other Hosts
+
+Endpoints captured on another host than the base URL (uploads, exports,
+auth) are sent to that host while the client uses its default base URL;
+setting `BaseURL` to a staging or test server redirects everything. A single
+call can go elsewhere with `WithBaseURL`:
+
+```go
+posts, err := client.ListPosts(ctx, WithBaseURL("https://eu.example.com"))
+```
+
+## Environments
+
+`Environments` lists the deployments the captured traffic reached, told apart
+by host (`api.example.com`, `api.staging.example.com`, `sandbox-api.example.com`).
+Clients created without a base URL use `DefaultEnvironment`; the others are a
+name away, with credentials read from a variable per environment
+(`{self._env_prefix()}_STAGING_TOKEN` or `_API_KEY`):
+
+```go
+client, err := New{self.class_name}ClientForEnv("staging")
+```
+
+`{self._env_prefix()}_ENV` picks the environment for `NewClientFromEnv`.
+
+## Environment Profiles
+
+Named environments (sandbox, staging, production) can live in a profiles file
+instead of code: `{self._env_prefix()}_PROFILES`, else
+`./{self._env_prefix().lower().replace("_", "-")}-profiles.json`, else
+`~/.config/{self._env_prefix().lower().replace("_", "-")}/profiles.json`. Each profile sets the base URL, a token
+(preferably via `token_env`), headers, a timeout, a rate limit, and TLS
+settings; relative file paths resolve against the profiles file:
+
+```json
+{{
+  "profiles": {{
+    "sandbox": {{
+      "base_url": "https://sandbox.example.com",
+      "token_env": "SANDBOX_TOKEN",
+      "timeout": "10s",
+      "rate_limit": {{"requests": 5, "per": "1s"}},
+      "tls": {{"ca_file": "sandbox-ca.pem"}}
+    }}
+  }}
+}}
+```
+
+```go
+client, err := NewClientFromProfile("sandbox")
+```
+
+Options passed after the profile name override the profile's settings.
+`WithRateLimit` and `WithTLSConfig` are also available as plain client options.
+
+## Configuration from the Environment
+
+`NewClientFromEnv` configures the client from environment variables, for
+twelve-factor deployments:
+
+| Variable | Setting |
+|----------|---------|
+| `{self._env_prefix()}_ENV` | entry of `Environments` to use |
+| `{self._env_prefix()}_PROFILE` | profile to load from the profiles file |
+| `{self._env_prefix()}_BASE_URL` | base URL |
+| `{self._env_prefix()}_TOKEN` | bearer token |
+| `{self._env_prefix()}_API_KEY` | API key, sent as the captured requests sent theirs |
+| `{self._env_prefix()}_TIMEOUT` | request timeout, as a Go duration or seconds |
+| `{self._env_prefix()}_PROXY` | proxy URL (else `HTTPS_PROXY`/`HTTP_PROXY`/`NO_PROXY`) |
+
+Later sources win: built-in defaults, then the environment, then the profile, then the other
+variables, then options passed to `NewClientFromEnv`:
+
+```go
+client, err := NewClientFromEnv(WithDebug(os.Stderr))
+```
+
+## API Keys
+
+Besides bearer tokens, clients authenticate the other ways APIs expect
+credentials. `SetAPIKey` sends a key the way the captured requests did, in a
+header such as `X-Api-Key`, a query parameter such as `?api_key=`, a session
+cookie, or basic auth, and the options below choose explicitly:
+
+```go
+client := New{self.class_name}Client("",
+    WithAPIKeyHeader("X-Api-Key", key),  // or WithAPIKeyQuery("api_key", key)
+    WithBasicAuth(username, password),
+    WithSessionCookie("sessionid", session),
+)
+```
+
+Keys sent in custom headers and query parameters are redacted from debug
+output and logs like the usual credentials. A query parameter captured as an
+API key is not generated as a method parameter.
+
+## Secret Providers
+
+Instead of passing a token at construction, point the client at a
+`SecretProvider` (Vault, AWS Secrets Manager, the OS keychain, or the bundled
+`EnvSecrets` and `FileSecrets`). The secret is fetched on first use and cached
+for the given TTL; a 401 response drops it, so the next call picks up a
+rotated credential:
+
+```go
+client := New{self.class_name}Client("",
+    WithSecretToken(FileSecrets{{Dir: "/run/secrets"}}, "api_token", 10*time.Minute),
+    WithSecretHeader(EnvSecrets{{}}, "API_KEY", "X-Api-Key", 0),
+)
+```
+
+## Shared Tokens
+
+Applications that build many short-lived clients should not refresh a token
+per client. `WithSharedToken` keeps one token per key in a `SharedTokenCache`
+(`DefaultTokenCache` when nil): concurrent refreshes collapse into one, the
+token is renewed shortly before it expires, and a 401 drops it only if no
+other call has refreshed it meanwhile. Give the cache a `FileTokenStore` to
+share tokens between processes, with a lock file serializing refreshes:
+
+```go
+cache := &SharedTokenCache{{Store: FileTokenStore{{Dir: "/var/cache/myapp/tokens"}}}}
+client := New{self.class_name}Client("", WithSharedToken(cache, "service-account", func(ctx context.Context) (string, time.Time, error) {{
+    tok, err := oauthConfig.Token(ctx)
+    if err != nil {{
+        return "", time.Time{{}}, err
+    }}
+    return tok.AccessToken, tok.Expiry, nil
+}}))
+```
+
+## OAuth2 Tokens
+
+`WithTokenSource` takes tokens from a `TokenSource`, which has the shape of
+golang.org/x/oauth2's, reusing each until it is about to expire or the API
+rejects it with a 401. `ClientCredentials` implements the client credentials
+grant:
+
+```go
+client := New{self.class_name}Client("", WithClientCredentialsConfig(&ClientCredentials{{
+    TokenURL:     "https://auth.example.com/oauth/token",
+    ClientID:     os.Getenv("CLIENT_ID"),
+    ClientSecret: os.Getenv("CLIENT_SECRET"),
+    Scopes:       []string{{"read"}},
+}}))
+```
+{self._go_readme_client_credentials()}
+## Timeouts
+
+Clients have no overall deadline by default. Instead, each phase of a request
+is bounded on the client's transport: dialing (10s), the TLS handshake (10s),
+and waiting for response headers (30s). A slow endpoint that streams its body
+is therefore not cut off, while an unreachable host still fails fast. Tune
+each phase, or add an end-to-end limit:
+
+```go
+client := New{self.class_name}Client("",
+    WithDialTimeout(2*time.Second),
+    WithResponseHeaderTimeout(5*time.Second),
+    WithTotalTimeout(time.Minute),
+)
+```
+
+Every method takes a `context.Context` as its first argument, and the request
+is made with it, so a per-call deadline or the cancellation of an incoming
+request stops the call in flight:
+
+```go
+ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+defer cancel()
+posts, err := client.ListPosts(ctx)
+```
+
+## Query Parameters
+
+Methods of endpoints with query parameters take a `Params` struct after the
+path parameters, holding those seen in the captured traffic and, for list
+envelopes, the paging parameters their `page`, `offset`, or `limit` fields
+echo. Zero values are left out; `Extra` sends anything else as given. Pass
+nil for none:
+
+```go
+users, err := client.ListUsers(ctx, &ListUsersParams{{Page: 2, Limit: 50}})
+users, err = client.ListUsers(ctx, &ListUsersParams{{Extra: url.Values{{"status": {{"active"}}}}}})
+```
+
+## Retries
+
+Clients do not retry by default. `WithRetry` retries calls answered with 429
+or a 5xx (other than 501), and transient network errors such as reset
+connections, waiting with exponential backoff and jitter, or as long as a
+`Retry-After` header asks (up to `MaxRetryAfter`). Waits never outlast the
+call's context. POST and PATCH calls are retried only when the request cannot
+have been processed, after a 429 or a refused connection, unless
+`RetryNonIdempotent` is set. `WithRetryPolicy` overrides the policy per call:
+
+```go
+client := New{self.class_name}Client("", WithRetry(DefaultRetryPolicy))
+posts, err := client.ListPosts(ctx, WithRetryPolicy(RetryPolicy{{}})) // no retries
+```
+
+## Idempotency Keys
+
+`WithIdempotencyKeys` sends a random key with every POST, PUT, PATCH, and
+DELETE call, the same key with each of its retries, so a server deduplicating
+by key never acts on a call twice. Calls carrying a key are retried like
+idempotent ones. Clients send keys by default when the captured requests
+carried them. `WithIdempotencyKey` supplies the key for a call, for instance
+one saved before a crash:
+
+```go
+client := New{self.class_name}Client("", WithRetry(DefaultRetryPolicy), WithIdempotencyKeys("Idempotency-Key"))
+post, err := client.CreatePost(ctx, req, WithIdempotencyKey(savedKey))
+```
+
+## Circuit Breaker
+
+`WithCircuitBreaker` fails calls fast with `ErrCircuitOpen` once a host has
+failed several requests in a row, instead of letting batch jobs hammer an API
+that is down. A failure is a network error or a 5xx. After the cooldown, a
+probe request is let through, and its success closes the circuit again:
+
+```go
+client := New{self.class_name}Client("", WithCircuitBreaker(BreakerPolicy{{
+    FailureThreshold: 5,
+    Cooldown:         30 * time.Second,
+    OnStateChange: func(circuit string, from, to CircuitState) {{
+        log.Printf("circuit %s: %s -> %s", circuit, from, to)
+    }},
+}}))
+```
+
+Set `PerOperation` to keep one circuit per operation rather than per host.
+`CircuitStates` reports the state of each circuit for health checks.
+
+## Response Caching
+
+`WithResponseCache` remembers GET responses that carry an `ETag` or
+`Last-Modified` header and sends them back as `If-None-Match` and
+`If-Modified-Since`; a `304 Not Modified` is answered with the stored body.
+Calls still reach the server, so results are never stale, but unchanged
+resources transfer no body. Entries are keyed by URL and credentials, so
+clients with different tokens can share one cache:
+
+```go
+cache := NewMemoryCache(1000)
+client := New{self.class_name}Client("", WithResponseCache(cache))
+```
+
+Implement `ResponseCache` to keep responses elsewhere, such as in Redis.
+
+## Middleware
+
+`Use` (or `WithMiddleware`) wraps the client's HTTP calls in middleware, a
+`func(next Doer) Doer`, for logging, metrics, token refresh, or request
+rewriting without editing generated code. Every method goes through it, once
+per attempt, with the client's headers and credentials already set; the first
+middleware added is the outermost:
+
+```go
+client.Use(func(next Doer) Doer {{
+    return DoerFunc(func(req *http.Request) (*http.Response, error) {{
+        start := time.Now()
+        resp, err := next.Do(req)
+        log.Printf("%s %s took %s", req.Method, req.URL.Path, time.Since(start))
+        return resp, err
+    }})
+}})
+```
+
+## Connection Pooling
+
+The client's transport keeps up to 32 idle connections per host for 90
+seconds, rather than Go's default of 2, so concurrent callers reuse
+connections instead of re-dialing. Adjust the pool for your workload:
+
+```go
+client := New{self.class_name}Client("",
+    WithMaxIdleConnsPerHost(64),
+    WithMaxConnsPerHost(100),
+    WithIdleConnTimeout(30*time.Second),
+)
+```
+
+`WithoutKeepAlives` opens a fresh connection for every request, and
+`WithHTTP2(false)` keeps to HTTP/1.1 for servers or proxies that mishandle
+HTTP/2. Dial and TLS handshake timeouts are covered under Timeouts, and
+`WithProxy` overrides the proxy environment variables.
+
+APIs served with self-signed certificates are reached by pinning the server's
+key rather than turning verification off. A pinned CA key only admits
+certificates it signed for the host, so a pinned certificate sent along with
+another one is rejected. `WithTLSConfig` covers private CAs and client
+certificates:
+
+```go
+client := New{self.class_name}Client("https://10.0.0.5:8443",
+    WithPinnedCertificates("sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
+)
+```
+
+## Graceful Shutdown
+
+`Close` fits into a service's shutdown sequence: new calls fail with
+`ErrClientClosed`, calls in flight are waited for until the context ends,
+functions registered with `WithCloseFunc` run (to flush exporters or
+monitors), and idle connections are closed:
+
+```go
+client := New{self.class_name}Client("", WithCloseFunc(func(ctx context.Context) error {{
+    monitor.Flush()
+    return nil
+}}))
+
+ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+defer cancel()
+if err := client.Close(ctx); err != nil {{
+    log.Printf("shutdown: %v", err)
+}}
+```
+
+## Panic Recovery
+
+A panic in code running inside a call (a custom `UnmarshalJSON`, a schema
+mismatch handler, a custom `RoundTripper`, a `SecretProvider`) or in a
+`WithCloseFunc` function is returned as a `*PanicError` carrying the
+operation, attempt number, and stack, rather than crashing the program:
+
+```go
+var p *PanicError
+if errors.As(err, &p) {{
+    log.Printf("%v\\n%s", p, p.Stack)
+}}
+```
+
+## Redirects
+
+Clients follow up to 10 redirects, but drop `Authorization`, cookies, and API
+key headers when a redirect leaves the original scheme and host, so a 302 to
+a signed storage URL does not leak or conflict with your credentials. Change
+this with `WithRedirectPolicy`; redirects not followed come back as a
+`*RedirectError` holding the `Location`:
+
+```go
+client := New{self.class_name}Client("", WithRedirectPolicy(RedirectPolicy{{NoFollow: true}}))
+_, err := client.ListPosts(ctx)
+var redirect *RedirectError
+if errors.As(err, &redirect) {{
+    log.Printf("download from %s", redirect.Location)
+}}
+```
+
+## Checksums
+
+When a response carries `Content-MD5`, `x-amz-checksum-*`, `Digest`, or
+`Content-Digest` headers, the body is verified against them and a mismatch
+fails the call with a `*ChecksumError`. Downloads and streams are hashed as
+they are read, and the `*ChecksumError` is returned by the `Read` that reaches
+the end of the body, so their content is not to be trusted until then. Turn
+verification off for operations whose checksums are known to be unreliable,
+or entirely:
+
+```go
+client := New{self.class_name}Client("", WithoutChecksumValidation("GET /v1/users/{{id}}"))
+```
+
+## Serializers
+
+Bodies are JSON unless the capture showed another media type for an
+endpoint: form-encoded requests and XML responses are handled out of the box,
+with `+json` and `+xml` vendor types mapped to JSON and XML. Plug in msgpack
+or any other format by registering a `Serializer` for its media type:
+
+```go
+client := New{self.class_name}Client("", WithSerializer("application/msgpack", msgpackSerializer{{}}))
+```
+
+## JSON Codec and Unknown Fields
+
+JSON goes through `encoding/json` unless `WithCodec` plugs in a faster
+drop-in such as sonic or jsoniter. Response fields the generated types lack
+are dropped by default (and counted as schema mismatches). `DecodeTolerant`
+keeps them, undecoded, in the `Extra` map of the struct they appeared in,
+and `DecodeStrict` fails the call with an `*UnknownFieldsError` naming them:
+
+```go
+client := New{self.class_name}Client("", WithCodec(sonic.ConfigStd), WithDecodeMode(DecodeTolerant))
+users, err := client.ListUsers(ctx, nil)
+nickname := users.Users[0].Extra["nickname"] // json.RawMessage, nil if absent
+```
+
+Only types the client decodes have `Extra`, and it is not sent back when
+one of them is reused in a request body.
+
+## Encrypted and Signed Payloads
+
+Endpoints captured with `application/jose` or `application/jwt` bodies get a
+`JOSECodec` from `WithJOSE`: request bodies the capture showed encrypted are
+sent as compact JWE (`RSA-OAEP-256` or `dir`, with AES-GCM), and signed
+responses are verified as compact JWS (RS, PS, ES, HS, EdDSA) before their
+payload is decoded. Keys come from a `JOSEKeyResolver`, such as
+`StaticJOSEKeys` or your own JWKS lookup; verification failures wrap
+`ErrJWSInvalid`:
+
+```go
+client := New{self.class_name}Client("", WithJOSE(StaticJOSEKeys{{
+    Encryption:   serverPublicKey,
+    Verification: map[string]interface{{}}{{"": signingPublicKey}},
+}}))
+```
+
+Other wire formats can plug in through `WithPayloadCodec`.
+
+## Response Signatures
+
+For APIs that sign their responses, `WithResponseSignatures` checks the
+signature header (by default the one seen in the capture) over the timestamp
+and raw body before anything is decoded. Tampered, unsigned, or stale
+responses fail with a `*SignatureError`:
+
+```go
+client := New{self.class_name}Client("", WithResponseSignatures(SignatureVerifier{{
+    Keys:      StaticSignatureKey([]byte(os.Getenv("SIGNING_SECRET"))),
+    Algorithm: SignatureHS256,
+}}))
+```
+
+Hex and base64 signatures, `sha256=` prefixes, and `t=...,v1=...` headers are
+understood; `Payload` adapts to other signing schemes. Downloads and streams
+are verified too: their body is held until it has been read to the end, and
+the `*SignatureError` is returned by the `Read` that reaches it.
+
+## Request Signing
+
+`WithSigner` signs every request, after its headers and credentials are set
+and again on each retry. `HMACSigner` covers the common private-API scheme,
+an HMAC of timestamp, method, path, and body sent with the timestamp, using
+the header names, encoding, and timestamp format seen in the capture.
+`SigV4Signer` implements AWS Signature Version 4:
+
+```go
+client := New{self.class_name}Client("", WithSigner(HMACSigner{{Secret: []byte(os.Getenv("SIGNING_SECRET"))}}))
+aws := New{self.class_name}Client("", WithSigner(SigV4Signer{{
+    AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+    SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+    Region:          "us-east-1",
+    Service:         "execute-api",
+}}))
+```
+
+Set `HMACSigner.Payload` for other canonical forms, or implement `Signer` (or
+use `SignerFunc`) for schemes of your own.
+
+## Audit Logging
+
+`WithAuditSink` receives an `AuditRecord` for every call: the caller identity
+carried by the call's context, the operation, the resource IDs from the path,
+the status code, the outcome, and the duration. Regulated users can turn it
+into an access log of the data pulled through the SDK:
+
+```go
+client := New{self.class_name}Client("", WithAuditSink(AuditSinkFunc(func(ctx context.Context, r AuditRecord) {{
+    log.Printf("audit caller=%s op=%q ids=%v status=%d outcome=%s took=%s",
+        r.Caller, r.Operation, r.ResourceIDs, r.StatusCode, r.Outcome(), r.Duration)
+}})))
+
+ctx := ContextWithCaller(r.Context(), user.Email)
+posts, err := client.ListPosts(ctx)
+```
+
+## Quota
+
+Every response's `X-RateLimit-Limit`, `-Remaining`, and `-Reset` headers (or
+their `RateLimit-*` forms, or `Retry-After` on a 429) update a quota shared by
+the client and its derived clients. `Quota()` returns the latest figures, and
+`WaitForQuota` blocks until the quota allows `n` more requests and reserves
+them, so batch jobs pace themselves instead of running into 429s:
+
+```go
+for _, id := range ids {{
+    if err := client.WaitForQuota(ctx, 1); err != nil {{
+        return err
+    }}
+    client.DeleteUser(ctx, id)
+}}
+```
+
+`WithQuotaWait` does the same before every call. Headers named like these in
+the captured traffic are read first. `WithRateLimit` adds a limit of the
+client's own, a token bucket, and `RateLimitState()` reports both:
+
+```go
+client := New{self.class_name}Client("", WithRateLimit(10, time.Second), WithQuotaWait())
+state := client.RateLimitState() // state.Remaining, state.Reset, state.Tokens
+```
+
+## Endpoint Toggles
+
+Deployments of the same API do not always serve every captured endpoint.
+The client's `EndpointRegistry` switches operations off at runtime, from a
+profile's `disabled_endpoints` or from discovery, and calls to a disabled
+operation fail with `ErrEndpointDisabled` without touching the network:
+
+```go
+client := New{self.class_name}Client("", WithDisabledEndpoints("DELETE /v1/users/{{id}}"))
+client.Endpoints().EnableOnly(advertised...) // e.g. from the deployment's capability list
+
+if _, err := client.ListPosts(ctx); errors.Is(err, ErrEndpointDisabled) {{
+    // fall back or skip
+}}
+```
+
+## API Versions
+
+An operation captured under several versions (`/v1/orders/{{id}}` and
+`/v2/orders/{{id}}`) gets a method per version, suffixed `V1`, `V2`, and so
+on. When every version takes and returns the same types, it also gets an
+unsuffixed facade that calls the newest version and falls back to older
+ones when it answers 404 or 410 or is disabled. `WithVersionHook` reports
+which version served each facade call, to follow a gradual upstream
+migration:
+
+```go
+client := New{self.class_name}Client("", WithVersionHook(func(ctx context.Context, r VersionReport) {{
+    if len(r.FellBack) > 0 {{
+        log.Printf("%s served by %s after %v", r.Operation, r.Version, r.FellBack)
+    }}
+}}))
+```
+{self._go_readme_version_services()}
+## Workflows
+
+Reverse-engineered APIs rarely offer transactions. `Workflow` chains
+dependent calls as named steps, each with an optional compensation; when a
+step fails, the completed steps are compensated newest first and `Run`
+returns a `*WorkflowError`. With a `WorkflowStore` such as
+`FileWorkflowStore`, progress and the values steps share are saved after
+every step, so a run stopped by a crash or a cancelled context resumes under
+the same ID:
+
+```go
+wf := NewWorkflow("onboard").WithStore(FileWorkflowStore{{Dir: "workflows"}}).
+    Step("user", func(ctx context.Context, s *WorkflowState) error {{
+        user, err := client.CreateUser(ctx, &CreateUserRequest{{Name: "Alice"}})
+        if err != nil {{
+            return err
+        }}
+        return s.Set("user", user.Id)
+    }}, func(ctx context.Context, s *WorkflowState) error {{
+        var id int64
+        s.Get("user", &id)
+        _, err := client.DeleteUser(ctx, id)
+        return err
+    }})
+state, err := wf.Run(ctx, "onboard-alice")
+```
+
+## Size Limits
+
+`WithMaxRequestBytes` rejects oversized request bodies before sending, and
+`WithMaxResponseBytes` stops reading a response once it passes the cap, so a
+misbehaving API cannot exhaust memory. Both fail with a `*SizeLimitError`:
+
+```go
+client := New{self.class_name}Client("", WithMaxResponseBytes(10<<20))
+_, err := client.ListPosts(ctx)
+var tooBig *SizeLimitError
+if errors.As(err, &tooBig) {{
+    log.Printf("%s over %d bytes", tooBig.Direction, tooBig.Limit)
+}}
+```
+
+## Compression
+
+Requests ask for `gzip, deflate` responses, and the client inflates them into
+a buffer sized from the gzip trailer, so a large list export is held once
+compressed and once inflated instead of being regrown as it is read. The
+response size limit applies to the inflated body, and checksum headers are
+checked against the compressed bytes. `WithAcceptEncoding` changes the
+encodings asked for; an empty string leaves compression to `net/http`.
+
+Request bodies of 1 KiB or more are gzipped for the operations the server was
+seen accepting compressed bodies for, in a captured request or its
+`Accept-Encoding` response header. `WithRequestCompression(minSize)` changes
+the threshold, and a negative size turns request compression off.
+
+## Health Checks
+
+`client.Ping(ctx)` calls `GET {self._ping_endpoint()}`, the cheapest read-only call
+in the capture, and fails on network, TLS, credential, or server errors. For
+services that depend on the API, `Readiness` caches ping results and serves
+them as a Kubernetes readiness probe:
+
+```go
+readiness := NewReadiness(client)
+readiness.FailureThreshold = 3 // tolerate brief blips
+http.Handle("/readyz", readiness)
+```
+
+## Command-Line Interface
+
+`cmd/{cli}` wraps every operation as a subcommand named after its resource
+and verb, with a flag for each path and query parameter and each scalar
+request body field; the rest of a body goes in `--body` as JSON, or `@file`.
+It is configured like `NewClientFromEnv`, and prints lists as tables and
+other results one field per line, or the JSON response with `--json`:
+
+```bash
+go install ./cmd/{cli}
+export {self._env_prefix()}_TOKEN=...
+{cli} {cli_example} --json
+{cli} {cli_example} -h   # the command's flags
+```
+
+`--dry-run` prints the request a command would send as a curl command, or
+as JSON with `--json`, without sending it. Run it without arguments to list
+the commands. Streaming, upload, and download operations are left to the
+library.
+
+## Self-Test
+
+`cmd/selftest` checks everything support asks about first: DNS, TCP and TLS
+reachability (with the certificate's expiry), whether the credentials are
+accepted, and whether a read-only call still matches the SDK's types. It is
+configured like `NewClientFromEnv`:
+
+```bash
+{self._env_prefix()}_TOKEN=... go run ./cmd/selftest
+```
+
+`-endpoint` picks the call to validate and `-arg` fills its path parameters
+(`-endpoint "GET /orders/{{id}}" -arg id=42`); `-list` prints the operations
+it accepts. The exit status is 1 if any check fails, so it also works as a
+deployment smoke test.
+
+## Comparing Deployments
+
+`cmd/compare` makes the same read-only calls against two deployments, such
+as production and staging, or the original API and a reimplementation, and
+prints the fields that differ by JSON path. Each side is a base URL, with
+credentials from the environment, or a profile name:
+
+```bash
+go run ./cmd/compare -a prod -b https://staging.example.com -arg id=42
+```
+
+Identifier and timestamp values are expected to differ and are skipped
+unless `-strict` is given, though missing fields and type changes still
+show; `-ignore` leaves out more fields by name or path (`$.meta.total`).
+
+## Errors
+
+Error responses come back as `*APIError`, holding the status, the request ID,
+the response headers, the raw body, and the body decoded as `Payload`. Branch
+on the status with `errors.Is` and the sentinels, or the helpers wrapping them,
+and read the fields with `errors.As`:
+
+```go
+result, err := client.{self._go_name(lookup)}({', '.join(lookup_args)})
+switch {{
+case IsNotFound(err): // errors.Is(err, ErrNotFound)
+    // treat as absent
+case errors.Is(err, ErrConflict), IsRateLimited(err):
+    // try again later
+case err != nil:
+    var apiErr *APIError
+    if errors.As(err, &apiErr) {{
+        log.Printf("status %d, request %s: %s", apiErr.StatusCode, apiErr.RequestID, apiErr.Detail())
+    }}
+}}
+```
+
+`ErrBadRequest`, `ErrUnauthorized`, `ErrForbidden`, `ErrValidation` (422), and
+`ErrServer` (any 5xx) complete the sentinels; `Temporary()` reports whether a
+retry may succeed, and `StatusCode(err)` returns the status or 0.
+
+## Error Messages
+
+The vendor's error code and message are parsed into `Code` and `Message`. To keep vendor strings away from
+end users, register user-facing messages by error code, HTTP status, or ""
+for everything else; `Error()` then returns the message for the request's
+locale, while `Detail()` keeps the raw error for logs:
+
+```go
+messages := NewErrorMessages().
+    Register("card_declined", "en", "Your card was declined.").
+    Register("card_declined", "de", "Ihre Karte wurde abgelehnt.").
+    Register("", "", "Something went wrong. Please try again.")
+client := New{self.class_name}Client("", WithErrorMessages(messages), WithLocale("de-DE"))
+```
+
+`LoadErrorMessages` reads the same table from a JSON file.
+
+## Agent Tools
+
+`client.Tools()` adapts every operation to the `agenttool.Tool` interface
+shared by the SDKs generated into this module: a name, a description, a
+JSON Schema for the input (path and query parameters, plus `body`), and
+`Invoke(ctx, input)` returning the response as JSON. Agents can list an
+API's capabilities and call them without its Go types:
+
+```go
+tools := client.Tools()
+catalog, err := agenttool.Catalog(tools) // name, description, input_schema
+out, err := agenttool.Invoke(ctx, tools, "{self._to_snake_case(self._go_name(next((e for e in self.endpoints.values() if self._go_json_call(e)), next(iter(self.endpoints.values())))))}", json.RawMessage(`{{}}`))
+```
+
+"""
+        pager_specs = self._pager_specs()
+        if pager_specs:
+            spec = pager_specs[0]
+            args = 'ctx, nil' if spec['params'] else 'ctx'
+            readme += f"""## Pagination
+
+List endpoints paged by a `page` or `offset` parameter have a `Pager`
+iterator, which requests the next page when the current one runs out, until
+the response's `has_more` or `total` says there are no more, or a page comes
+back short. Endpoints paged by a cursor, from a field such as `next_cursor`,
+the `Link` header, or the last item's `id` for an `after` parameter, have
+one following the cursors until none is left. `All` collects every item;
+`NextPage` and `Page` hand them over a page at a time:
+
+```go
+it := client.{spec['name']}Pager({args})
+for it.Next() {{
+    item := it.Item()
+}}
+if err := it.Err(); err != nil {{
+    return err
+}}
+
+items, err := client.{spec['name']}All({args})
+```
+
+"""
+        poll_specs = self._poll_specs()
+        if poll_specs:
+            spec = poll_specs[0]
+            args = ', '.join('0' if param.endswith(' int64') else '""' for param in spec['params'])
+            readme += f"""## Async Operations
+
+Resources the capture shows being polled until their work finishes, such as
+a job a `POST` starts, have a `WaitFor` method. It polls until the
+resource's `{spec['field']}` reaches a terminal state (`completed`, `failed`,
+`cancelled`, ...), waiting `interval` between polls at first and backing
+off from there, within `timeout` if positive:
+
+```go
+result, err := client.{spec['wait']}(ctx, {args}, time.Second, 5*time.Minute)
+var failed *WaitError
+if errors.As(err, &failed) {{
+    log.Printf("ended in state %s", failed.State)
+}}
+```
+
+"""
+        streams = [endpoint for endpoint in self.endpoints.values() if endpoint.stream]
+        if streams:
+            call = f"client.{self._go_name(streams[0])}({', '.join(['ctx'] + self._go_placeholder_args(streams[0]))})"
+            readme += f"""## Streaming
+
+Endpoints captured answering with server-sent events (`text/event-stream`)
+or JSON lines return an `EventStream` instead of buffering the body: each
+event is decoded as it arrives, into a type inferred from the captured
+events. The stream ends when the server closes it or sends `[DONE]`:
+
+```go
+stream, err := {call}
+if err != nil {{
+    return err
+}}
+defer stream.Close()
+for stream.Next() {{
+    event := stream.Event() // Type, ID, Data, Raw
+}}
+if err := stream.Err(); err != nil {{
+    return err
+}}
+```
+
+`stream.Events(ctx)` delivers the events on a channel instead. To resume a
+server-sent event stream that broke off, wait `stream.Retry()` if the server
+set it, then pass `WithLastEventID(stream.LastEventID())` to the next call.
+
+"""
+        uploads = [endpoint for endpoint in self.endpoints.values() if endpoint.upload]
+        if uploads:
+            example = next((e for e in uploads if e.upload == 'multipart'), uploads[0])
+            call = f"client.{self._go_name(example)}({', '.join(['ctx'] + self._go_placeholder_args(example))})"
+            readme += f"""## File Uploads
+
+Endpoints captured receiving `multipart/form-data` take an `io.Reader` and a
+filename for each file field, after the form's text fields; endpoints
+captured receiving raw bytes (images, PDFs, `application/octet-stream`) take
+an `io.Reader` sent as the body, labeled with the captured media type unless
+`WithContentType` says otherwise. Bodies are streamed rather than buffered:
+
+```go
+f, err := os.Open("upload.bin")
+if err != nil {{
+    return err
+}}
+defer f.Close()
+result, err := {call}
+```
+
+Content-Length is sent when the readers' lengths are known (files, byte and
+string readers). Retries resend an upload only when its readers can seek
+back, as files can.
+
+"""
+        downloads = [endpoint for endpoint in self.endpoints.values() if endpoint.download]
+        if downloads:
+            name = self._go_name(downloads[0])
+            args = ', '.join(['ctx'] + self._go_placeholder_args(downloads[0]))
+            readme += f"""## Downloads
+
+Endpoints captured returning files (CSV exports, PDFs, images, attachments)
+hand over the response body unread instead of decoding it. The method
+returns a `Download`, an `io.ReadCloser` with the response's content type,
+length, and filename; its `To` variant copies the body to an `io.Writer`:
+
+```go
+download, err := client.{name}({args})
+if err != nil {{
+    return err
+}}
+defer download.Close()
+fmt.Println(download.ContentType, download.ContentLength, download.Filename)
+
+f, err := os.Create("download.bin")
+n, err := client.{name}To({args}, f)
+```
+
+Compressed bodies are inflated as they are read.
+
+"""
+        export_specs = self._export_specs()
+        if export_specs:
+            spec = next((spec for spec in export_specs if spec['page'] or spec['offset']), export_specs[0])
+            columns = ', '.join(f'"{".".join(path)}"' for path, _ in spec['columns'][:3])
+            readme += f"""## Exporting Data
+
+Each captured list endpoint has an `Export` method writing all of its items
+to an `export.Writer`, as CSV or as a Parquet file (written without
+dependencies). Columns come from the fields of the items, with nested objects
+flattened to dotted names; pages are requested until the response's
+`has_more` or `total` says there are no more, or a page comes back short:
+
+```go
+f, err := os.Create("{spec['endpoint'].strip('/').split('/')[-1]}.parquet")
+defer f.Close()
+n, err := client.{spec['name']}(ctx, export.NewParquet(f), ExportOptions{{}})
+
+n, err = client.{spec['name']}(ctx, export.NewCSV(os.Stdout), ExportOptions{{
+    Columns: []string{{{columns}}},
+    MaxRows: 1000,
+}})
+```
+
+`ExportColumns("GET {spec['endpoint']}")` lists the columns and their types.
+
+"""
+        sync_specs = self._sync_specs()
+        if sync_specs:
+            spec = sync_specs[0]
+            filtering = (f"The `{spec['since']}` parameter limits each run to recent changes."
+                         if spec['since'] else
+                         "The API has no captured filter by time, so each run reads every page and\nskips the unchanged items.")
+            readme += f"""## Incremental Sync
+
+List endpoints whose items carry an `updated_at` (or, failing that,
+`created_at`) timestamp have a `Sync` helper that pulls only what changed
+since its checkpoint and hands each record to a callback. Every run re-reads
+an overlap window before the checkpoint (`DefaultSyncOverlap`, one minute) to
+catch late writes, and drops records it already delivered:
+
+```go
+s := client.{spec['name']}("warehouse").WithCheckpoints(FileCheckpointStore{{Dir: "state"}})
+n, err := s.Run(ctx, func(ctx context.Context, r SyncRecord) error {{
+    return upsert(ctx, r.ID, r.Raw)
+}})
+```
+
+{filtering}
+A callback error stops the run without advancing the checkpoint, so the next
+run delivers the remaining records again.
+
+"""
+        tenant_scope = self._detect_tenant_scope()
+        if tenant_scope:
+            tenant_header, tenant_collection = tenant_scope
+            carriers = [f"the `{tenant_header}` header"] if tenant_header else []
+            if tenant_collection:
+                carriers.append(f"the `/{tenant_collection}/{{id}}` path segment")
+            example = next((e for e in self.endpoints.values() if e.method == 'GET'), next(iter(self.endpoints.values())))
+            call = self._go_name(example)
+            args = self._go_placeholder_args(example)
+            readme += f"""## Tenant Scoping
+
+Calls are scoped to a tenant through {' and '.join(carriers)}.
+`WithTenant` returns a client that fills it in on every call (tenant path
+parameters can be left empty), sharing the original's connections and stats;
+`WithRequestTenant` overrides it for a single call:
+
+```go
+acme := client.WithTenant("acme")
+acme.{call}({', '.join(['ctx'] + args)})
+acme.{call}({', '.join(['ctx'] + args + ['WithRequestTenant("globex")'])})
+```
+
+"""
+        batch = self._detect_batch_endpoint()
+        if batch:
+            calls = [e for e in self.endpoints.values() if self._go_json_call(e) and not (e.method == 'POST' and e.path_pattern == batch['endpoint'])][:2]
+            adds = []
+            for e in calls:
+                name = self._go_name(e)
+                args = self._go_placeholder_args(e)
+                adds.append(f"{name[0].lower() + name[1:]} := batch.{name}({', '.join(args)})")
+            readme += f"""## Batching
+
+The API accepts several calls in one `POST {batch['endpoint']}`. `NewBatch`
+collects calls through methods mirroring the client's, `Send` makes a single
+HTTP request, and each call's `Result` returns its own decoded response or
+error:
+
+```go
+batch := client.NewBatch()
+{chr(10).join(adds)}
+if err := batch.Send(ctx); err != nil {{
+    log.Fatal(err) // the batch request itself failed
+}}
+result, err := {adds[0].split(' ')[0]}.Result()
+```
+
+"""
+        bulk = self._go_bulk_operations()
+        if bulk:
+            example = next((op for op in bulk if op['items'] == 'requests'), bulk[0])
+            readme += f"""## Bulk Calls
+
+Methods taking a single request body or ID have a bulk helper, such as
+`{example['bulk']}`, that calls them for a whole slice from a pool of
+workers. Results come back in the order of the slice, each with its own value
+or error; if any call failed, the error is a `*BulkError` mapping the index
+of every failed item to its error:
+
+```go
+results, err := client.{example['bulk']}(ctx, {example['items']}, Concurrency(5))
+for _, result := range results {{
+    if result.Err != nil {{
+        log.Printf("item %d: %v", result.Index, result.Err)
+    }}
+}}
+```
+
+Every call still waits for the rate limiter and circuit breaker, so
+`Concurrency` bounds parallelism without bypassing either. `StopOnError()`
+stops starting calls after the first failure, and `WithBulkRequestOptions`
+applies request options to every call.
+
+"""
+        if self.webhook_parser and self.webhook_parser.events:
+            import textwrap
+            example = self._go_webhook_events()[0]
+            signature = self.webhook_parser.signature
+            verified = (f"checks each delivery's `{'-'.join(w.capitalize() for w in signature.header.split('-'))}` signature, "
+                        if signature else "")
+            intro = textwrap.fill(
+                "The capture included the API's webhook deliveries, so the package has a type per event and a "
+                f"`WebhookHandler` to receive them. The handler {verified}decodes the payload into its event's "
+                "type, and calls the function registered for that type:", 76)
+            readme += f"""## Webhooks
+
+{intro}
+
+```go
+webhooks := NewWebhookHandler(os.Getenv("WEBHOOK_SECRET"))
+webhooks.{example['handler']}(func(ctx context.Context, event {example['struct']}) error {{
+    return process(ctx, event)
+}})
+http.Handle("/webhooks", webhooks)
+```
+
+Deliveries that fail verification are answered `401`, ones whose function
+returns an error `500`, so the sender retries them. `OnUnhandled` receives
+event types without a function of their own, and `VerifyWebhook` checks a
+delivery received by another router.
+
+"""
+        if self.websocket_parser and self.websocket_parser.channels:
+            readme += self._go_readme_websockets()
+        feed = self._detect_change_feed()
+        if feed:
+            event_type = ''
+            if feed['type']:
+                endpoint = next(e for e in self.endpoints.values() if e.method == 'GET' and e.path_pattern == feed['endpoint'])
+                for example in endpoint.examples:
+                    found = self._object_array(self._example_json(example.get('response', {}).get('body')))
+                    if found and found[0] == feed['events']:
+                        event_type = str(found[1][0].get(feed['type'], ''))
+                        break
+            handle = (f'HandleChange(sub, "{event_type}", func(ctx context.Context, event Event) error {{'
+                      if event_type else 'sub.On("", func(ctx context.Context, event ChangeEvent) error {')
+            readme += f"""## Change Feed
+
+`GET {feed['endpoint']}?{feed['param']}=...` returns what changed since a position.
+`Subscribe` polls it, drops events already delivered, and dispatches each new
+one to the handlers registered for its type, decoded into the type you name. With a `CheckpointStore` the position is saved after
+every page, so a restarted process picks up where it stopped; a handler error
+stops `Run` before that event is checkpointed:
+
+```go
+sub := client.Subscribe("indexer").WithCheckpoints(FileCheckpointStore{{Dir: "state"}})
+{handle}
+    return index(event)
+}})
+err := sub.Run(ctx)
+```
+
+"""
+        if self.result_types:
+            example = next((e for e in self.endpoints.values() if e.method == 'GET' and self._go_json_call(e)), next(iter(self.endpoints.values())))
+            args = self._go_placeholder_args(example)
+            readme += f"""## Results
+
+This SDK was generated with `--go-results`: methods return `Result[T]`
+rather than `(T, error)`, bundling the decoded value, the response's
+`ResponseMeta` (status, headers, request ID, duration), and the error, so
+call sites can log the request ID of a failure without extra plumbing.
+Where the examples above take `result, err` from a call, use `res.Get()`:
+
+```go
+res := client.{self._go_name(example)}({', '.join(['ctx'] + args)})
+if apiErr := res.APIError(); apiErr != nil {{
+    log.Printf("%s: status %d, request %s", apiErr.Code, res.StatusCode(), res.Meta.RequestID)
+}}
+value, err := res.Get()
+```
+
+`Must`, `ValueOr`, and `MapResult` cover scripts, defaults, and converting
+the value while keeping the metadata.
+
+"""
+        readme += """## Runtime Stats
+
+`client.Stats()` snapshots request counts, transport errors, in-flight
+requests, responses by status class, retries, body bytes sent and received,
+and schema mismatches. `Stats().Endpoints` has the same counters for each
+operation, with its error rate and total latency. For environments that
+scrape `/debug/vars`, publish them with expvar under a name of your choice:
+
+```go
+if err := client.PublishExpvar("exampleapi"); err != nil {
+    log.Fatal(err)
+}
+```
+
+`client.Metrics(namespace)` serves them in the Prometheus text format, as
+counters labelled by operation (`exampleapi_requests_total`,
+`exampleapi_responses_total{class="5xx"}`, `exampleapi_retries_total`, ...)
+and a latency summary, with no Prometheus dependency:
+
+```go
+http.Handle("/metrics/exampleapi", client.Metrics("exampleapi"))
+```
+
+With `WithLatencyStats()`, each operation also gets its p50, p95, and p99
+latency (from a t-digest, so memory stays bounded), next to the median
+latency recorded in the capture where it had timings, to compare the SDK's
+performance against the original client's; the Prometheus summary then
+includes them as quantiles.
+
+Every call also runs under pprof labels `endpoint` (the path pattern) and
+`method`, so CPU and goroutine profiles attribute time to API operations:
+`go tool pprof -tagfocus=endpoint=/v1/users cpu.pprof`.
+
+## Test Data Factories
+
+Every request and response struct has a factory populated from the same
+learned distributions, with optional overrides:
+
+```go
+req := NewCreateUserRequestFixture(func(r *CreateUserRequest) {
+    r.Email = "ada@example.com"
+})
+
+factory := NewFixtureFactory(42) // deterministic per seed
+users := factory.ListUsersResponse()
+```
+
+## Fixture Server
+
+`sdktest.NewFixtureServer(t)` starts an `httptest.Server` answering every
+captured endpoint with its captured responses. Path parameters are honoured:
+`GET /v1/users/42` returns the captured user with its id rewritten to 42.
+
+```go
+srv := sdktest.NewFixtureServer(t)
+client.BaseURL = srv.URL
+
+srv.Handle("GET /v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+    w.WriteHeader(http.StatusServiceUnavailable)
+})
+```
+
+## Test Assertions
+
+`sdktest` also has dependency-free assertions tuned to the SDK's types and
+errors. Failures name the differing fields:
+
+```go
+users, err := client.ListUsers(ctx, nil)
+sdktest.NoError(t, err)
+sdktest.JSONSubset(t, `{"users": [{"name": "John Doe"}]}`, users)
+sdktest.Equal(t, want, users) // users[1].email: want "...", got "..."
+
+_, err = client.UpdateUser(ctx, missingID, req)
+sdktest.ErrorStatus(t, err, http.StatusNotFound)
+```
+
+Comparisons go through the `jsondiff` package, which reports each difference
+by path and can skip what is expected to vary. Use it directly, or pass its
+options to `sdktest.EqualWith` or `vcr.MatchBodyIgnoring`:
+
+```go
+opts := jsondiff.Options{{
+    Ignore:           []string{{"$.meta.request_time"}},
+    IgnoreTimestamps: true,
+    Unordered:        []string{{"$.users[*].roles"}},
+}}
+sdktest.EqualWith(t, want, users, opts)
+
+diffs, err := jsondiff.Compare(golden, body, opts)
+fmt.Println(jsondiff.Format(diffs)) // $.users[1].email: "a@x.com" vs "b@x.com"
+```
+
+""" + self._go_readme_mocks() + """## In-Memory Fake
+
+The `fake` package implements the captured resources over in-memory maps with
+real CRUD semantics: creates assign ids and timestamps, lists paginate
+(`page`/`limit` or `offset`), and updates and deletes are visible to later
+calls. It plugs in underneath any client, so business logic runs unchanged:
+
+```go
+api := fake.New()
+api.Seed("users", fake.Object{"name": "Ada"})
+client.HTTPClient.Transport = api.Transport()
+```
+
+Integration tests that need a real endpoint, for the client's own transport
+or for another process such as the CLI, serve the same fake over HTTP on a
+local `httptest.Server` that stops when the test ends:
+
+```go
+api := fake.New()
+client := New""" + self.class_name + """Client(api.Listen(t))
+```
+
+## Recording Integration Tests
+
+The `vcr` package records real interactions to JSON cassettes and replays
+them deterministically, so integration tests run without the network.
+Credentials in headers and query strings are redacted before saving:
+
+```go
+rec, err := vcr.New("testdata/cassettes/users.json") // records if missing
+defer rec.Stop()
+client.HTTPClient.Transport = rec
+```
+
+Run with `VCR_MODE=record` to re-record (`vcr.WithMode(vcr.ModeFromEnv())`).
+In tests, `vcr.ForTest(t)` opens `testdata/cassettes/<test name>.json` in the
+mode `VCR_MODE` selects and saves it when the test ends, so a test records
+once against the real API and replays offline from then on:
+
+```go
+func TestGetUser(t *testing.T) {
+    client := New""" + self.class_name + """Client("")
+    client.HTTPClient.Transport = vcr.ForTest(t)
+    // ...
+}
+```
+
+Requests match on method, URL and body by default; combine `vcr.MatchMethod`,
+`vcr.MatchPath`, `vcr.MatchQuery("ts")`, `vcr.MatchBody` or
+`vcr.MatchHeaders(...)` with `vcr.WithMatcher` to change that.
+
+## Drift Monitoring
+
+The `drift` package validates live responses against a schema published with
+`cli.py --registry`, reporting new fields, type changes, missing fields, and
+endpoints that start answering 404/410:
+
+```go
+snapshot, err := drift.LoadLatest("schemas", "ExampleAPI")
+monitor := drift.NewMonitor(snapshot, drift.WithSampleRate(0.05))
+client.HTTPClient.Transport = monitor.Transport(client.HTTPClient.Transport)
+
+// later, e.g. on a ticker
+monitor.Report(os.Stderr)
+```
+
+## Alerting
+
+Findings can be pushed to `alert` sinks with the endpoint, field, expected and
+observed types, and a response sample:
+
+```go
+metrics := alert.NewPrometheus("exampleapi")
+http.Handle("/metrics", metrics)
+
+monitor := drift.NewMonitor(snapshot,
+    drift.WithSink(alert.Multi(
+        metrics,
+        &alert.Slack{WebhookURL: os.Getenv("SLACK_WEBHOOK_URL")},
+        &alert.Webhook{URL: "https://alerts.internal/schema"},
+    )),
+)
+```
+
+## Regenerating
+
+Regenerating from a new capture keeps the code where it was: operations
+are ordered by path and method, and struct fields in the order first
+captured. `names.lock.json` records the name given to every operation and
+struct and is read back on the next run, so a newly captured operation
+cannot take an existing method's name or renumber its types. Commit it with
+the SDK; delete an entry to let that name be chosen afresh. In CI, fail
+when the committed SDK is stale:
+
+```bash
+python cli.py --har traffic.har --name "MyAPI" --output generated_sdks --check
+```
+
+## OpenAPI Spec
+
+`openapi.yaml` describes the captured API as OpenAPI 3.1: operations named
+as in this SDK, inferred schemas, a captured example for each request and
+response status, and the servers of each environment. Load it into other
+tooling, or correct it by hand and regenerate the SDK from the edited copy:
+
+```bash
+cp openapi.yaml ../api.yaml
+python cli.py --openapi ../api.yaml --name "MyAPI" --languages go
+```
 
 ## Generated from Network Traffic
 
@@ -0,0 +1,165 @@
+import json
+import re
+import hashlib
+from typing import Dict, List, Any
+from dataclasses import dataclass, field
+
+
+@dataclass
+class GraphQLOperation:
+    name: str
+    operation_type: str
+    query: str
+    selection_hash: str
+    path: str
+    root_fields: List[str] = field(default_factory=list)
+    variables_schema: Dict[str, Any] = field(default_factory=dict)
+    response_schema: Dict[str, Any] = field(default_factory=dict)
+    error_count: int = 0
+    examples: List[Dict[str, Any]] = field(default_factory=list)
+
+
+class GraphQLParser:
+    """Reconstructs GraphQL operations from captured HTTP traffic.
+
+    Operations are deduplicated by the hash of their normalized selection
+    set, so the same query sent with different variables or formatting is
+    only recorded once. Schema helpers are borrowed from the owning
+    TrafficParser to keep inference consistent with REST endpoints.
+    """
+
+    OPERATION_PATTERN = re.compile(r'^\s*(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?')
+
+    def __init__(self, schema_builder):
+        self.schema_builder = schema_builder
+        self.operations: Dict[str, GraphQLOperation] = {}
+
+    def process(self, method: str, path: str, query_params: Dict[str, List[str]],
+                body: Any, status: int, response_body: Any) -> bool:
+        payloads = self._extract_payloads(method, query_params, body)
+        if not payloads:
+            return False
+
+        responses = response_body if isinstance(response_body, list) else [response_body] * len(payloads)
+        for payload, response in zip(payloads, responses):
+            self._record(path, payload, status, response)
+        return True
+
+    def _extract_payloads(self, method: str, query_params: Dict[str, List[str]], body: Any) -> List[Dict[str, Any]]:
+        if method == 'GET' and 'query' in query_params:
+            payload = {'query': query_params['query'][0]}
+            if 'operationName' in query_params:
+                payload['operationName'] = query_params['operationName'][0]
+            if 'variables' in query_params:
+                try:
+                    payload['variables'] = json.loads(query_params['variables'][0])
+                except json.JSONDecodeError:
+                    pass
+            return [payload]
+
+        candidates = body if isinstance(body, list) else [body]
+        payloads = [c for c in candidates if self._is_graphql_payload(c)]
+        if payloads and len(payloads) == len(candidates):
+            return payloads
+        return []
+
+    def _is_graphql_payload(self, data: Any) -> bool:
+        return isinstance(data, dict) and isinstance(data.get('query'), str) and \
+            bool(self.OPERATION_PATTERN.match(data['query']) or data['query'].lstrip().startswith('{'))
+
+    def _record(self, path: str, payload: Dict[str, Any], status: int, response: Any):
+        query = payload['query']
+        normalized = self.normalize_query(query)
+        selection_hash = hashlib.sha256(normalized.encode('utf-8')).hexdigest()[:16]
+
+        operation = self.operations.get(selection_hash)
+        if operation is None:
+            operation_type, parsed_name = self._parse_operation_header(query)
+            root_fields = self._root_fields(normalized)
+            name = payload.get('operationName') or parsed_name or self._fallback_name(root_fields, selection_hash)
+            operation = GraphQLOperation(
+                name=self._unique_name(name, selection_hash),
+                operation_type=operation_type,
+                query=query,
+                selection_hash=selection_hash,
+                path=path,
+                root_fields=root_fields
+            )
+            self.operations[selection_hash] = operation
+
+        variables = payload.get('variables')
+        if isinstance(variables, dict) and variables:
+            self.schema_builder._merge_schema(operation.variables_schema, self.schema_builder._extract_schema(variables))
+
+        if isinstance(response, dict):
+            if response.get('errors'):
+                operation.error_count += 1
+            if isinstance(response.get('data'), dict):
+                self.schema_builder._merge_schema(operation.response_schema, self.schema_builder._extract_schema(response['data']))
+
+        operation.examples.append({
+            'variables': variables,
+            'status': status,
+            'response': response
+        })
+
+    @staticmethod
+    def normalize_query(query: str) -> str:
+        without_comments = re.sub(r'#[^\n]*', '', query)
+        collapsed = re.sub(r'\s+', ' ', without_comments).strip()
+        return re.sub(r'\s*([{}():,!=\[\]])\s*', r'\1', collapsed)
+
+    def _parse_operation_header(self, query: str):
+        match = self.OPERATION_PATTERN.match(query)
+        if not match:
+            return 'query', None
+        return match.group(1), match.group(2)
+
+    def _root_fields(self, normalized: str) -> List[str]:
+        fields = []
+        depth = 0
+        paren_depth = 0
+        token = ''
+        for char in normalized:
+            if char == '(':
+                paren_depth += 1
+            elif char == ')':
+                paren_depth -= 1
+            elif paren_depth:
+                continue
+            elif char == '{':
+                depth += 1
+                if depth == 2 and token:
+                    fields.append(token)
+                token = ''
+            elif char == '}':
+                if depth == 1 and token:
+                    fields.append(token)
+                depth -= 1
+                token = ''
+                if depth == 0:
+                    break
+            elif depth == 0:
+                continue
+            elif depth == 1:
+                if char in ' ,':
+                    if token:
+                        fields.append(token)
+                    token = ''
+                elif char == ':':
+                    token = ''
+                else:
+                    token += char
+
+        return [f for f in dict.fromkeys(fields) if not f.startswith('...')]
+
+    def _fallback_name(self, root_fields: List[str], selection_hash: str) -> str:
+        if root_fields:
+            return root_fields[0][0].upper() + root_fields[0][1:]
+        return f"Anonymous{selection_hash[:8]}"
+
+    def _unique_name(self, name: str, selection_hash: str) -> str:
+        taken = {op.name for op in self.operations.values()}
+        if name not in taken:
+            return name
+        return f"{name}_{selection_hash[:8]}"
@@ -0,0 +1,290 @@
+import base64
+import gzip
+import struct
+from typing import Dict, List, Any, Optional, Tuple
+from dataclasses import dataclass, field
+from collections import Counter
+
+
+@dataclass
+class InferredField:
+    number: int
+    kinds: Counter = field(default_factory=Counter)
+    observations: int = 0
+    repeated: bool = False
+    message: Optional['InferredMessage'] = None
+
+    @property
+    def kind(self) -> str:
+        if not self.kinds:
+            return 'bytes'
+        return self.kinds.most_common(1)[0][0]
+
+    @property
+    def confidence(self) -> float:
+        base = {
+            'string': 0.9, 'message': 0.8, 'int64': 0.7, 'double': 0.6,
+            'bytes': 0.6, 'bool': 0.5, 'float': 0.5, 'fixed64': 0.5, 'fixed32': 0.5
+        }.get(self.kind, 0.4)
+        consistency = self.kinds[self.kind] / sum(self.kinds.values())
+        coverage = min(1.0, self.observations / 3)
+        return round(base * consistency * (0.5 + 0.5 * coverage), 2)
+
+
+@dataclass
+class InferredMessage:
+    name: str
+    samples: int = 0
+    fields: Dict[int, InferredField] = field(default_factory=dict)
+
+
+@dataclass
+class GrpcMethod:
+    path: str
+    service: str
+    method: str
+    package: str = ''
+    request: Optional[InferredMessage] = None
+    response: Optional[InferredMessage] = None
+    calls: int = 0
+
+
+class ProtobufWireDecoder:
+    """Best-effort protobuf decoding without a descriptor.
+
+    Field numbers and wire types are exact; the scalar type behind a wire
+    type is a heuristic (a varint could be an int, bool, or enum; a
+    length-delimited field could be a string, bytes, or nested message).
+    """
+
+    MAX_FIELD_NUMBER = 536870911
+    MAX_DEPTH = 8
+
+    def decode(self, data: bytes, depth: int = 0) -> Optional[List[Tuple[int, str, Any]]]:
+        fields = []
+        offset = 0
+        while offset < len(data):
+            key, offset = self._read_varint(data, offset)
+            if key is None:
+                return None
+            number, wire_type = key >> 3, key & 0x7
+            if number < 1 or number > self.MAX_FIELD_NUMBER:
+                return None
+
+            if wire_type == 0:
+                value, offset = self._read_varint(data, offset)
+                if value is None:
+                    return None
+                fields.append((number, self._classify_varint(value), value))
+            elif wire_type == 1:
+                if offset + 8 > len(data):
+                    return None
+                raw = data[offset:offset + 8]
+                offset += 8
+                fields.append((number, *self._classify_fixed(raw, '<d', '<Q', 'double', 'fixed64')))
+            elif wire_type == 5:
+                if offset + 4 > len(data):
+                    return None
+                raw = data[offset:offset + 4]
+                offset += 4
+                fields.append((number, *self._classify_fixed(raw, '<f', '<I', 'float', 'fixed32')))
+            elif wire_type == 2:
+                length, offset = self._read_varint(data, offset)
+                if length is None or offset + length > len(data):
+                    return None
+                chunk = data[offset:offset + length]
+                offset += length
+                fields.append((number, *self._classify_bytes(chunk, depth)))
+            else:
+                return None
+        return fields
+
+    def _read_varint(self, data: bytes, offset: int) -> Tuple[Optional[int], int]:
+        result = 0
+        shift = 0
+        while offset < len(data) and shift < 64:
+            byte = data[offset]
+            offset += 1
+            result |= (byte & 0x7F) << shift
+            if not byte & 0x80:
+                return result, offset
+            shift += 7
+        return None, offset
+
+    def _classify_varint(self, value: int) -> str:
+        return 'bool' if value in (0, 1) else 'int64'
+
+    def _classify_fixed(self, raw: bytes, float_fmt: str, int_fmt: str, float_kind: str, int_kind: str):
+        as_float = struct.unpack(float_fmt, raw)[0]
+        if as_float == 0 or (as_float == as_float and 1e-6 <= abs(as_float) <= 1e15):
+            return float_kind, as_float
+        return int_kind, struct.unpack(int_fmt, raw)[0]
+
+    def _classify_bytes(self, chunk: bytes, depth: int):
+        try:
+            text = chunk.decode('utf-8')
+            if all(ch.isprintable() or ch in '\r\n\t' for ch in text):
+                return 'string', text
+        except UnicodeDecodeError:
+            pass
+        if chunk and depth < self.MAX_DEPTH:
+            nested = self.decode(chunk, depth + 1)
+            if nested:
+                return 'message', nested
+        return 'bytes', chunk
+
+
+class GrpcParser:
+    """Infers gRPC services and message layouts from captured traffic.
+
+    Used when the target does not expose server reflection: message bodies
+    are decoded with ProtobufWireDecoder and merged across calls so each
+    field carries a confidence score reflecting how consistent the observed
+    wire data was.
+    """
+
+    CONTENT_TYPES = ('application/grpc', 'application/grpc-web')
+
+    def __init__(self):
+        self.decoder = ProtobufWireDecoder()
+        self.methods: Dict[str, GrpcMethod] = {}
+
+    def is_grpc(self, headers: Dict[str, Any]) -> bool:
+        content_type = self._content_type(headers)
+        return any(content_type.startswith(ct) for ct in self.CONTENT_TYPES)
+
+    def process(self, path: str, headers: Dict[str, Any], request_body: Any, response_body: Any,
+                request_encoding: Optional[str] = None, response_encoding: Optional[str] = None) -> bool:
+        if not self.is_grpc(headers):
+            return False
+
+        grpc_method = self.methods.get(path)
+        if grpc_method is None:
+            grpc_method = self._new_method(path)
+            self.methods[path] = grpc_method
+        grpc_method.calls += 1
+
+        content_type = self._content_type(headers)
+        text_mode = content_type.startswith('application/grpc-web-text')
+        for payload in self._frames(self._to_bytes(request_body, 'base64' if text_mode else request_encoding)):
+            self._observe(grpc_method.request, payload)
+        for payload in self._frames(self._to_bytes(response_body, 'base64' if text_mode else response_encoding)):
+            self._observe(grpc_method.response, payload)
+        return True
+
+    def _content_type(self, headers: Dict[str, Any]) -> str:
+        for name, value in headers.items():
+            if name.lower() == 'content-type':
+                return str(value).lower()
+        return ''
+
+    def _new_method(self, path: str) -> GrpcMethod:
+        parts = [p for p in path.split('/') if p]
+        qualified_service = parts[0] if parts else 'Service'
+        method_name = parts[1] if len(parts) > 1 else 'Call'
+        package, _, service = qualified_service.rpartition('.')
+        return GrpcMethod(
+            path=path,
+            service=service,
+            method=method_name,
+            package=package,
+            request=InferredMessage(name=f"{method_name}Request"),
+            response=InferredMessage(name=f"{method_name}Response")
+        )
+
+    def _to_bytes(self, body: Any, encoding: Optional[str]) -> bytes:
+        if body is None:
+            return b''
+        if isinstance(body, bytes):
+            return body
+        if encoding == 'base64':
+            try:
+                return base64.b64decode(body)
+            except (ValueError, TypeError):
+                return b''
+        return str(body).encode('latin-1', errors='ignore')
+
+    def _frames(self, data: bytes) -> List[bytes]:
+        frames = []
+        offset = 0
+        while offset + 5 <= len(data):
+            flags = data[offset]
+            length = struct.unpack('>I', data[offset + 1:offset + 5])[0]
+            payload = data[offset + 5:offset + 5 + length]
+            offset += 5 + length
+            if flags & 0x80:
+                continue
+            if flags & 0x01:
+                try:
+                    payload = gzip.decompress(payload)
+                except OSError:
+                    continue
+            frames.append(payload)
+        return frames
+
+    def _observe(self, message: InferredMessage, payload: bytes):
+        decoded = self.decoder.decode(payload)
+        if decoded is None:
+            return
+        self._merge(message, decoded)
+
+    def _merge(self, message: InferredMessage, decoded: List[Tuple[int, str, Any]]):
+        message.samples += 1
+        occurrences = Counter(number for number, _, _ in decoded)
+        for number, kind, value in decoded:
+            inferred = message.fields.get(number)
+            if inferred is None:
+                inferred = InferredField(number=number)
+                message.fields[number] = inferred
+            inferred.kinds[kind] += 1
+            inferred.observations += 1
+            if occurrences[number] > 1:
+                inferred.repeated = True
+            if kind == 'message':
+                if inferred.message is None:
+                    inferred.message = InferredMessage(name=f"{message.name}Field{number}")
+                self._merge(inferred.message, value)
+
+    def render_proto(self, package: str = '') -> str:
+        package = package or next((m.package for m in self.methods.values() if m.package), 'inferred')
+        lines = [
+            'syntax = "proto3";',
+            '',
+            f'package {package};',
+            '',
+            '// Inferred from captured traffic without server reflection.',
+            '// Field names are placeholders; types carry a confidence score.',
+            ''
+        ]
+
+        services: Dict[str, List[GrpcMethod]] = {}
+        for grpc_method in self.methods.values():
+            services.setdefault(grpc_method.service, []).append(grpc_method)
+
+        for service, methods in services.items():
+            lines.append(f'service {service} {{')
+            for grpc_method in methods:
+                lines.append(f'  rpc {grpc_method.method}({grpc_method.request.name}) returns ({grpc_method.response.name});')
+            lines.append('}')
+            lines.append('')
+
+        for grpc_method in self.methods.values():
+            for message in (grpc_method.request, grpc_method.response):
+                lines.extend(self._render_message(message))
+        return '\n'.join(lines)
+
+    def _render_message(self, message: InferredMessage) -> List[str]:
+        lines = [f'message {message.name} {{']
+        nested = []
+        for number in sorted(message.fields):
+            inferred = message.fields[number]
+            proto_type = inferred.message.name if inferred.kind == 'message' and inferred.message else inferred.kind
+            label = 'repeated ' if inferred.repeated else ''
+            lines.append(f'  {label}{proto_type} field_{number} = {number}; // confidence: {inferred.confidence:.2f}')
+            if inferred.kind == 'message' and inferred.message:
+                nested.append(inferred.message)
+        lines.append('}')
+        lines.append('')
+        for nested_message in nested:
+            lines.extend(self._render_message(nested_message))
+        return lines
@@ -0,0 +1,84 @@
+import json
+from typing import Dict, List, Any
+from dataclasses import dataclass, field, fields
+
+
+@dataclass
+class InferenceConfig:
+    """Knobs for correcting systematic mis-inferences.
+
+    Loaded from the `inference` section of a JSON config file passed with
+    `cli.py --config`, for example:
+
+        {
+          "inference": {
+            "path_params": "conservative",
+            "static_segments": ["v2024", "oauth2"],
+            "enum_max_values": 5,
+            "date_formats": ["iso8601", "%d/%m/%Y"],
+            "detect_envelopes": false
+          }
+        }
+    """
+
+    # How eagerly path segments become parameters: "conservative" (numbers,
+    # UUIDs, object ids), "normal" (also long segments containing digits),
+    # or "aggressive" (also any segment with digits and opaque tokens).
+    path_params: str = 'normal'
+    # Extra regexes whose matching segments are always parameters.
+    path_param_patterns: List[str] = field(default_factory=list)
+    # Segments that are never parameters, whatever they look like.
+    static_segments: List[str] = field(default_factory=list)
+
+    # String fields with at most this many distinct values, seen at least
+    # enum_min_samples times, are annotated as enums.
+    enum_max_values: int = 10
+    enum_min_samples: int = 3
+
+    # String fields whose values, seen at least this many times, all had the
+    # same length are constrained to it (minLength and maxLength).
+    fixed_length_min_samples: int = 5
+
+    # Formats recognized as dates: "iso8601" or strptime patterns.
+    date_formats: List[str] = field(default_factory=lambda: ['iso8601'])
+
+    # Fractional number fields whose name contains one of these words (or
+    # that sit next to a currency field) are annotated as decimals, as are
+    # numeric strings such as "10.50".
+    money_fields: List[str] = field(default_factory=lambda: [
+        'amount', 'price', 'cost', 'total', 'subtotal', 'balance', 'fee', 'tax', 'discount'])
+
+    # Integer and number fields whose name contains one of these words, with
+    # a value in this century as Unix seconds or milliseconds, are annotated
+    # as epoch timestamps.
+    timestamp_fields: List[str] = field(default_factory=lambda: [
+        'at', 'time', 'timestamp', 'ts', 'date', 'expires', 'expiry'])
+
+    # Detect responses wrapping their payload in an envelope key.
+    detect_envelopes: bool = True
+    envelope_keys: List[str] = field(default_factory=lambda: ['data', 'result', 'payload', 'response'])
+
+    # Samples needed before a field gets a concrete type, and below which
+    # it is reported as low-confidence.
+    min_samples: int = 1
+    low_confidence_samples: int = 3
+
+    PATH_PARAM_LEVELS = ('conservative', 'normal', 'aggressive')
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> 'InferenceConfig':
+        known = {f.name for f in fields(cls)}
+        unknown = sorted(set(data) - known)
+        if unknown:
+            raise ValueError(f"unknown inference settings: {', '.join(unknown)} "
+                             f"(valid: {', '.join(sorted(known))})")
+        config = cls(**data)
+        if config.path_params not in cls.PATH_PARAM_LEVELS:
+            raise ValueError(f"path_params must be one of {', '.join(cls.PATH_PARAM_LEVELS)}")
+        return config
+
+    @classmethod
+    def load(cls, path: str) -> 'InferenceConfig':
+        with open(path, 'r') as f:
+            data = json.load(f)
+        return cls.from_dict(data.get('inference', {}))
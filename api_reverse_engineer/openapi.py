@@ -0,0 +1,513 @@
+import json
+import re
+from typing import Dict, List, Any, Optional, Tuple
+from urllib.parse import urlparse, parse_qsl, urlencode
+import yaml
+from traffic_parser import APIEndpoint
+
+
+HTTP_METHODS = ['get', 'put', 'post', 'delete', 'options', 'head', 'patch', 'trace']
+STREAM_MEDIA_TYPES = {'sse': 'text/event-stream', 'ndjson': 'application/x-ndjson'}
+# Request headers the spec states otherwise (media types, security) or that
+# describe the connection rather than the API.
+SKIPPED_REQUEST_HEADERS = {'host', 'content-type', 'content-length', 'content-encoding', 'accept', 'accept-encoding',
+                           'authorization', 'cookie', 'connection', 'user-agent', 'x-request-id'}
+SKIPPED_RESPONSE_HEADERS = {'content-type', 'content-length', 'content-encoding', 'transfer-encoding', 'connection',
+                            'keep-alive', 'date', 'server', 'set-cookie'}
+SECURITY_SCHEME = 'credentials'
+
+
+class OpenAPIExporter:
+    """Describes inferred REST endpoints as an OpenAPI 3.1 document.
+
+    Each operation keeps what the generators rely on: its operationId (the
+    Go method name), parameters with a captured example each, request and
+    response bodies per media type with the first captured example, the
+    response headers seen, and streams, uploads, and downloads as their media
+    types. The captured environments become servers and the credentials a
+    security scheme, so OpenAPIImporter can regenerate the same SDK from the
+    document after it was edited.
+    """
+
+    def __init__(self, api_name: str, base_url: str, endpoints: Dict[str, APIEndpoint],
+                 environments: Optional[Dict[str, str]] = None, operation_ids: Optional[Dict[str, str]] = None,
+                 auth: Optional[Tuple[str, str]] = None):
+        self.api_name = api_name
+        self.base_url = base_url
+        self.endpoints = endpoints
+        self.environments = environments or {}
+        self.operation_ids = operation_ids or {}
+        self.auth = auth
+
+    def render(self) -> str:
+        """The document as YAML."""
+        return yaml.safe_dump(self.document(), sort_keys=False, allow_unicode=True, width=100)
+
+    def document(self) -> Dict[str, Any]:
+        servers = [{'url': self.base_url, 'description': name} for name, url in self.environments.items()
+                   if url == self.base_url] or [{'url': self.base_url}]
+        servers += [{'url': url, 'description': name} for name, url in self.environments.items() if url != self.base_url]
+        document = {
+            'openapi': '3.1.0',
+            'info': {
+                'title': self.api_name,
+                'version': '1.0.0',
+                'description': 'Inferred from captured traffic. Edit operation IDs, parameters, and schemas, '
+                               'then regenerate the SDKs from this file with --openapi.',
+            },
+            'servers': servers,
+            'paths': {},
+        }
+        if self.auth:
+            document['components'] = {'securitySchemes': {SECURITY_SCHEME: self._security_scheme(*self.auth)}}
+            document['security'] = [{SECURITY_SCHEME: []}]
+        for key, endpoint in sorted(self.endpoints.items(), key=lambda item: (
+                item[1].origin if item[1].origin != self.base_url else '', item[1].path_pattern,
+                HTTP_METHODS.index(item[1].method.lower()) if item[1].method.lower() in HTTP_METHODS else len(HTTP_METHODS))):
+            if endpoint.method.lower() not in HTTP_METHODS:
+                continue
+            path_item = document['paths'].setdefault(endpoint.path_pattern, {})
+            path_item[endpoint.method.lower()] = self._operation(key, endpoint)
+        return document
+
+    def _security_scheme(self, mode: str, name: str) -> Dict[str, Any]:
+        if mode in ('bearer', 'basic'):
+            return {'type': 'http', 'scheme': mode}
+        return {'type': 'apiKey', 'in': mode, 'name': name}
+
+    def _operation(self, key: str, endpoint: APIEndpoint) -> Dict[str, Any]:
+        operation = {}
+        if key in self.operation_ids:
+            operation['operationId'] = self.operation_ids[key]
+        if endpoint.origin and endpoint.origin != self.base_url:
+            operation['servers'] = [{'url': endpoint.origin}]
+        parameters = self._parameters(endpoint)
+        if parameters:
+            operation['parameters'] = parameters
+        request_body = self._request_body(endpoint)
+        if request_body:
+            operation['requestBody'] = request_body
+        operation['responses'] = self._responses(endpoint)
+        return operation
+
+    def _parameters(self, endpoint: APIEndpoint) -> List[Dict[str, Any]]:
+        request = endpoint.examples[0].get('request', {}) if endpoint.examples else {}
+        url = urlparse(request.get('url') or '')
+        segments = dict(zip(endpoint.path_pattern.split('/'), url.path.split('/')))
+        query = dict(parse_qsl(url.query, keep_blank_values=True))
+        headers = {name.lower(): value for name, value in (request.get('headers') or {}).items()}
+        parameters = []
+        for name in sorted(endpoint.path_params, key=endpoint.path_pattern.find):
+            value = segments.get(f'{{{name}}}')
+            parameter = {'name': name, 'in': 'path', 'required': True,
+                         'schema': {'type': 'integer' if value is not None and value.isdigit() else 'string'}}
+            if value is not None:
+                parameter['example'] = int(value) if value.isdigit() else value
+            parameters.append(parameter)
+        for name, value_type in endpoint.query_params.items():
+            if self.auth and self.auth == ('query', name):
+                continue
+            parameter = {'name': name, 'in': 'query', 'schema': _scalar_schema(value_type)}
+            if name in query:
+                parameter['example'] = query[name]
+            parameters.append(parameter)
+        for name, value_type in endpoint.headers.items():
+            lowered = name.lower()
+            if lowered in SKIPPED_REQUEST_HEADERS or (self.auth and self.auth[0] == 'header' and lowered == self.auth[1].lower()):
+                continue
+            parameter = {'name': name, 'in': 'header', 'schema': _scalar_schema(value_type)}
+            if lowered in headers:
+                parameter['example'] = headers[lowered]
+            parameters.append(parameter)
+        return parameters
+
+    def _request_body(self, endpoint: APIEndpoint) -> Optional[Dict[str, Any]]:
+        example = next((e for e in endpoint.examples if e.get('request', {}).get('body')), None)
+        request = example['request'] if example else {}
+        media_type = _content_type(request) or 'application/json'
+        if endpoint.upload == 'binary':
+            return {'content': {media_type: {'schema': {'type': 'string', 'format': 'binary'}}}}
+        if endpoint.upload == 'multipart':
+            schema = _json_schema(endpoint.request_body_schema) if endpoint.request_body_schema else {'type': 'object'}
+            schema.setdefault('properties', {})
+            for name in endpoint.upload_files:
+                schema['properties'][name] = {'type': 'string', 'format': 'binary'}
+            return {'content': {'multipart/form-data': {'schema': schema}}}
+        if not endpoint.request_body_schema and not request.get('body'):
+            return None
+        content = {'schema': _json_schema(endpoint.request_body_schema)}
+        if request.get('body'):
+            content['example'] = _load_body(request['body'], media_type)
+        body = {'content': {media_type: content}}
+        encoding = _header(request, 'content-encoding')
+        if encoding:
+            body['x-content-encoding'] = encoding
+        return body
+
+    def _responses(self, endpoint: APIEndpoint) -> Dict[str, Any]:
+        examples = {}
+        for example in endpoint.examples:
+            response = example.get('response', {})
+            examples.setdefault(response.get('status', 200), response)
+        statuses = sorted(set(endpoint.response_schemas) | set(examples))
+        responses = {}
+        for status in statuses:
+            captured = examples.get(status, {})
+            media_type = _content_type(captured) or 'application/json'
+            response = {'description': f"Captured {status} response"}
+            headers = {name: {'schema': {'type': 'string'}, 'example': str(value)}
+                       for name, value in (captured.get('headers') or {}).items()
+                       if name.lower() not in SKIPPED_RESPONSE_HEADERS}
+            if headers:
+                response['headers'] = headers
+            success = 200 <= status < 300
+            if success and endpoint.stream:
+                schema = _json_schema(endpoint.event_schema) if endpoint.event_schema else {}
+                response['content'] = {STREAM_MEDIA_TYPES.get(endpoint.stream, media_type): {'schema': schema}}
+            elif success and endpoint.download:
+                response['content'] = {media_type: {'schema': {'type': 'string', 'format': 'binary'}}}
+            if response.get('content') and isinstance(captured.get('body'), str):
+                next(iter(response['content'].values()))['example'] = captured['body']
+            elif status in endpoint.response_schemas:
+                content = {'schema': _json_schema(endpoint.response_schemas[status])}
+                if captured.get('body'):
+                    content['example'] = _load_body(captured['body'], media_type)
+                response['content'] = {media_type: content}
+            responses[str(status)] = response
+        return responses or {'200': {'description': 'Captured response'}}
+
+
+class OpenAPIImporter:
+    """Reads an OpenAPI 3.x document back into endpoints for the generators.
+
+    Operations keep their operationId as the Go method name, and schemas are
+    taken as they stand, so renamed operations and corrected types carry
+    into regenerated SDKs. Each operation gets examples rebuilt from the
+    examples in the document, one per response, standing in for the
+    captured exchanges the generators look at for credentials, headers, and
+    media types.
+    """
+
+    def __init__(self, document: Dict[str, Any]):
+        self.document = document
+        servers = [server for server in document.get('servers') or [] if server.get('url')]
+        self.base_url = servers[0]['url'].rstrip('/') if servers else ''
+        self.environments = {server['description']: server['url'].rstrip('/') for server in servers
+                             if server.get('description')}
+        self.endpoints: Dict[str, APIEndpoint] = {}
+        self.auth = self._auth()
+        for path, path_item in (document.get('paths') or {}).items():
+            for method, operation in (path_item or {}).items():
+                if method in HTTP_METHODS and isinstance(operation, dict):
+                    self._add_operation(path, method.upper(), operation, path_item.get('parameters') or [])
+
+    @classmethod
+    def load(cls, path: str) -> 'OpenAPIImporter':
+        with open(path, 'r') as f:
+            text = f.read()
+        # JSON is YAML too.
+        return cls(yaml.safe_load(text))
+
+    def _auth(self) -> Optional[Tuple[str, str]]:
+        schemes = (self.document.get('components') or {}).get('securitySchemes') or {}
+        names = [name for requirement in self.document.get('security') or [] for name in requirement] or list(schemes)
+        scheme = next((self._resolve(schemes[name]) for name in names if name in schemes), None)
+        if not scheme:
+            return None
+        if scheme.get('type') == 'http':
+            return (str(scheme.get('scheme', 'bearer')).lower(), 'Authorization')
+        if scheme.get('type') == 'apiKey' and scheme.get('in') in ('header', 'query', 'cookie'):
+            return (scheme['in'], scheme.get('name', ''))
+        if scheme.get('type') in ('oauth2', 'openIdConnect'):
+            return ('bearer', 'Authorization')
+        return None
+
+    def _resolve(self, node: Any, depth: int = 0) -> Any:
+        """node with a local $ref replaced by what it points to."""
+        while isinstance(node, dict) and isinstance(node.get('$ref'), str) and depth < 32:
+            target = self.document
+            for part in node['$ref'].lstrip('#/').split('/'):
+                target = (target or {}).get(part.replace('~1', '/').replace('~0', '~'))
+            node, depth = target, depth + 1
+        return node
+
+    def _add_operation(self, path: str, method: str, operation: Dict[str, Any], shared: List[Any]):
+        servers = operation.get('servers') or []
+        origin = servers[0]['url'].rstrip('/') if servers and servers[0].get('url') else self.base_url
+        key = f"{method}:{path}" if origin == self.base_url else f"{method}:{origin}{path}"
+        endpoint = APIEndpoint(method=method, path_pattern=path, path_params=set(re.findall(r'\{([^}]+)\}', path)),
+                               origin=origin, operation_id=str(operation.get('operationId') or ''))
+        url_path, query, headers = path, [], {}
+        parameters = {}
+        for parameter in [*shared, *(operation.get('parameters') or [])]:
+            parameter = self._resolve(parameter) or {}
+            parameters[(parameter.get('in'), parameter.get('name'))] = parameter
+        for (location, name), parameter in parameters.items():
+            schema = _internal_schema(self._resolve(parameter.get('schema')) or {}, self._resolve)
+            example = _parameter_example(parameter, self._resolve)
+            if location == 'path':
+                value = example if example is not None else (1 if schema.get('type') == 'integer' else name)
+                url_path = url_path.replace(f'{{{name}}}', str(value))
+            elif location == 'query':
+                endpoint.query_params[name] = schema.get('type', 'string')
+                if example is not None:
+                    query.append((name, _scalar(example)))
+            elif location == 'header':
+                endpoint.headers[name] = schema.get('type', 'string')
+                if example is not None:
+                    headers[name] = _scalar(example)
+        if self.auth:
+            self._add_credentials(headers, query)
+
+        request = {'url': origin + url_path + ('?' + urlencode(query) if query else ''), 'method': method, 'headers': headers}
+        request_body = self._resolve(operation.get('requestBody')) or {}
+        for media_type, content in (request_body.get('content') or {}).items():
+            schema = self._resolve(content.get('schema')) or {}
+            if media_type == 'multipart/form-data':
+                endpoint.upload = 'multipart'
+                properties = dict(schema.get('properties') or {})
+                endpoint.upload_files = [name for name, prop in properties.items()
+                                         if (self._resolve(prop) or {}).get('format') == 'binary']
+                fields = dict(schema, properties={name: prop for name, prop in properties.items()
+                                                  if name not in endpoint.upload_files})
+                if fields['properties']:
+                    endpoint.request_body_schema = _internal_schema(fields, self._resolve)
+            elif schema.get('type') == 'string' and schema.get('format') == 'binary':
+                endpoint.upload = 'binary'
+            else:
+                if schema:
+                    endpoint.request_body_schema = _internal_schema(schema, self._resolve)
+                example = _media_example(content, self._resolve)
+                if example is not None:
+                    request['body'] = _dump_body(example, media_type)
+                    if endpoint.request_body_schema:
+                        _attach_examples(endpoint.request_body_schema, example)
+            headers['Content-Type'] = media_type
+            if request_body.get('x-content-encoding'):
+                headers['Content-Encoding'] = request_body['x-content-encoding']
+            break
+
+        for status, response in (operation.get('responses') or {}).items():
+            if not str(status).isdigit():
+                continue
+            status, response = int(status), self._resolve(response) or {}
+            captured = {'status': status, 'headers': {}}
+            for name, header in (response.get('headers') or {}).items():
+                example = _parameter_example(self._resolve(header) or {}, self._resolve)
+                if example is not None:
+                    captured['headers'][name] = _scalar(example)
+            for media_type, content in (response.get('content') or {}).items():
+                schema = self._resolve(content.get('schema')) or {}
+                captured['headers']['Content-Type'] = media_type
+                stream = next((name for name, stream_type in STREAM_MEDIA_TYPES.items() if stream_type == media_type), '')
+                example = _media_example(content, self._resolve)
+                if stream or media_type in ('application/ndjson', 'application/jsonl', 'application/x-jsonlines'):
+                    endpoint.stream = stream or 'ndjson'
+                    if schema:
+                        endpoint.event_schema = _internal_schema(schema, self._resolve)
+                    if isinstance(example, str):
+                        captured['body'] = example
+                elif schema.get('type') == 'string' and schema.get('format') == 'binary' and 200 <= status < 300:
+                    endpoint.download = True
+                    if isinstance(example, str):
+                        captured['body'] = example
+                else:
+                    endpoint.response_schemas[status] = _internal_schema(schema, self._resolve)
+                    if example is not None:
+                        captured['body'] = _dump_body(example, media_type)
+                        _attach_examples(endpoint.response_schemas[status], example)
+                break
+            endpoint.examples.append({'request': request, 'response': captured})
+        self.endpoints[key] = endpoint
+
+    def _add_credentials(self, headers: Dict[str, str], query: List[Tuple[str, str]]):
+        """Stands a secret placeholder in for the credentials the security
+        scheme asks for, where the capture had them."""
+        mode, name = self.auth
+        placeholder = '{{secret:api_key}}'
+        if mode in ('bearer', 'basic'):
+            headers['Authorization'] = f"{mode.capitalize()} {placeholder}"
+        elif mode == 'header':
+            headers[name] = placeholder
+        elif mode == 'query':
+            query.append((name, placeholder))
+        elif mode == 'cookie':
+            headers['Cookie'] = f"{name}={placeholder}"
+
+
+def _header(message: Dict[str, Any], name: str) -> str:
+    return next((str(v) for k, v in (message.get('headers') or {}).items() if k.lower() == name), '')
+
+
+def _content_type(message: Dict[str, Any]) -> str:
+    return _header(message, 'content-type').split(';')[0].strip().lower()
+
+
+def _is_json(media_type: str) -> bool:
+    return media_type == 'application/json' or media_type.endswith('+json')
+
+
+def _load_body(body: Any, media_type: str) -> Any:
+    if isinstance(body, str) and _is_json(media_type):
+        try:
+            return json.loads(body)
+        except json.JSONDecodeError:
+            pass
+    return body
+
+
+def _dump_body(example: Any, media_type: str) -> str:
+    if isinstance(example, str) and not _is_json(media_type):
+        return example
+    return json.dumps(example, separators=(',', ':'))
+
+
+def _scalar(value: Any) -> str:
+    if isinstance(value, bool):
+        return 'true' if value else 'false'
+    return str(value)
+
+
+def _scalar_schema(value_type: str) -> Dict[str, Any]:
+    return {'type': value_type} if value_type in ('integer', 'number', 'boolean', 'string', 'array', 'object') else {}
+
+
+def _parameter_example(parameter: Dict[str, Any], resolve) -> Any:
+    if 'example' in parameter:
+        return parameter['example']
+    for example in (parameter.get('examples') or {}).values():
+        example = resolve(example) or {}
+        if 'value' in example:
+            return example['value']
+    schema = resolve(parameter.get('schema')) or {}
+    if schema.get('examples'):
+        return schema['examples'][0]
+    return schema.get('example')
+
+
+def _media_example(content: Dict[str, Any], resolve) -> Any:
+    if 'example' in content:
+        return content['example']
+    for example in (content.get('examples') or {}).values():
+        example = resolve(example) or {}
+        if 'value' in example:
+            return example['value']
+    return None
+
+
+def _json_schema(schema: Dict[str, Any]) -> Dict[str, Any]:
+    """The JSON Schema (as OpenAPI 3.1 uses it) for an inferred schema.
+    Sample statistics and field examples are left out, the latter being in
+    the body's example; nullable fields take a "null" type."""
+    if not schema:
+        return {}
+    observed = schema.get('type', 'any')
+    if observed == 'any':
+        types = list(schema.get('conflicting_types') or [])
+    else:
+        types = [observed]
+    if schema.get('nullable') and types and 'null' not in types:
+        types.append('null')
+    result: Dict[str, Any] = {}
+    if len(types) == 1:
+        result['type'] = types[0]
+    elif types:
+        result['type'] = types
+    if schema.get('format'):
+        result['format'] = schema['format']
+    if schema.get('date_format'):
+        result['x-date-format'] = schema['date_format']
+    if schema.get('enum'):
+        result['enum'] = list(schema['enum']) + ([None] if 'null' in types else [])
+    for key in ('minLength', 'maxLength'):
+        if key in schema:
+            result[key] = schema[key]
+    if observed == 'object':
+        result['properties'] = {name: _json_schema(prop) for name, prop in (schema.get('properties') or {}).items()}
+        required = [name for name in schema.get('required') or [] if name in result['properties']]
+        if required:
+            result['required'] = required
+    if observed == 'array' and isinstance(schema.get('items'), dict):
+        result['items'] = _json_schema(schema['items'])
+    if schema.get('envelope'):
+        result['x-envelope'] = schema['envelope']
+    return result
+
+
+def _attach_examples(schema: Dict[str, Any], example: Any):
+    """Gives the fields of schema their values in a body example, as
+    captured schemas have them, where the spec gave none."""
+    if isinstance(example, dict):
+        for name, prop in (schema.get('properties') or {}).items():
+            if name in example:
+                _attach_examples(prop, example[name])
+    elif isinstance(example, list):
+        if example and isinstance(schema.get('items'), dict):
+            _attach_examples(schema['items'], example[0])
+    elif example is not None:
+        schema.setdefault('example', example)
+
+
+def _internal_schema(schema: Dict[str, Any], resolve, depth: int = 0) -> Dict[str, Any]:
+    """The inferred-schema form of a JSON Schema: the inverse of
+    _json_schema, also taking OpenAPI 3.0's nullable and anyOf/oneOf
+    alternatives with null."""
+    schema = resolve(schema) or {}
+    if depth > 32:
+        return {'type': 'any'}
+    alternatives = [resolve(s) or {} for s in schema.get('anyOf') or schema.get('oneOf') or []]
+    if alternatives:
+        non_null = [s for s in alternatives if s.get('type') != 'null']
+        merged = dict(non_null[0]) if len(non_null) == 1 else {}
+        merged.update({k: v for k, v in schema.items() if k not in ('anyOf', 'oneOf')})
+        if len(non_null) < len(alternatives):
+            merged['nullable'] = True
+        if len(non_null) > 1:
+            merged['type'] = sorted({str(s.get('type')) for s in non_null if isinstance(s.get('type'), str)})
+        schema = merged
+    for part in schema.get('allOf') or []:
+        part = resolve(part) or {}
+        schema = dict(schema, **{k: v for k, v in part.items() if k not in ('properties', 'required')},
+                      properties={**(schema.get('properties') or {}), **(part.get('properties') or {})},
+                      required=[*(schema.get('required') or []), *(part.get('required') or [])])
+    types = schema.get('type')
+    types = list(types) if isinstance(types, list) else ([types] if types else [])
+    nullable = 'null' in types or bool(schema.get('nullable'))
+    types = [t for t in types if t != 'null']
+    if not types:
+        types = ['object'] if 'properties' in schema else ['array'] if 'items' in schema else []
+    result: Dict[str, Any] = {}
+    if len(types) == 1:
+        result['type'] = types[0]
+    elif types:
+        result['type'] = 'any'
+        result['conflicting_types'] = sorted(types)
+    elif nullable and schema.get('type') is not None:
+        result['type'] = 'null'
+    else:
+        result['type'] = 'any'
+    if nullable:
+        result['nullable'] = True
+    if schema.get('format'):
+        result['format'] = schema['format']
+    if schema.get('x-date-format'):
+        result['date_format'] = schema['x-date-format']
+    enum = [value for value in schema.get('enum') or [] if value is not None]
+    if enum and all(isinstance(value, str) for value in enum):
+        result['enum'] = enum
+    for key in ('minLength', 'maxLength'):
+        if key in schema:
+            result[key] = schema[key]
+    if result['type'] == 'object':
+        result['properties'] = {name: _internal_schema(prop, resolve, depth + 1)
+                                for name, prop in (schema.get('properties') or {}).items()}
+        result['required'] = [name for name in schema.get('required') or [] if name in result['properties']]
+    if result['type'] == 'array':
+        result['items'] = _internal_schema(schema.get('items') or {}, resolve, depth + 1)
+    if schema.get('examples'):
+        result['example'] = schema['examples'][0]
+    elif 'example' in schema:
+        result['example'] = schema['example']
+    if schema.get('x-envelope'):
+        result['envelope'] = schema['x-envelope']
+    return result
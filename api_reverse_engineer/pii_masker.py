@@ -0,0 +1,128 @@
+import re
+import json
+import hashlib
+from typing import Dict, Any, Optional
+from secret_redactor import SecretRedactor
+
+
+class PIIMasker:
+    """Masks personal data in captured traffic while preserving its shape.
+
+    Masked values keep their character classes and separators (an email is
+    still an email, a phone number keeps its punctuation) so type and format
+    inference behave exactly as they would on the raw capture. Masking is
+    deterministic per value, so repeated values still deduplicate.
+    """
+
+    EMAIL_PATTERN = re.compile(r'[\w.+-]+@[\w-]+(?:\.[\w-]+)+')
+    CARD_PATTERN = re.compile(r'(?<![\w-])\d(?:[ -]?\d){12,18}(?![\w-])')
+    PHONE_PATTERN = re.compile(r'(?<![\w-])\+?\(?\d[\d\s().-]{7,}\d(?![\w-])')
+    TOKEN_PATTERN = re.compile(
+        r'eyJ[\w-]+\.[\w-]+\.[\w-]+'
+        r'|\b(?:sk|pk|rk)_(?:live|test)_\w+'
+        r'|\bgh[pousr]_\w{20,}'
+        r'|\bxox[abpr]-[\w-]+'
+        r'|\b[A-Fa-f0-9]{32,}\b'
+        r'|\b[A-Za-z0-9_\-]{40,}\b'
+    )
+    SENSITIVE_KEYS = {
+        'password', 'passwd', 'pass', 'secret', 'ssn', 'social_security_number',
+        'phone', 'phone_number', 'mobile', 'card_number', 'cc_number', 'cvv', 'cvc', 'iban'
+    }
+
+    def __init__(self, enabled: bool = True, salt: str = ''):
+        self.enabled = enabled
+        self.salt = salt
+
+    def mask(self, data: Any, key: Optional[str] = None) -> Any:
+        if not self.enabled:
+            return data
+        if isinstance(data, dict):
+            return {k: self.mask(v, k) for k, v in data.items()}
+        if isinstance(data, list):
+            return [self.mask(item, key) for item in data]
+        if isinstance(data, str):
+            # Credentials the redactor already replaced stay resolvable.
+            if SecretRedactor.PLACEHOLDER_PATTERN.fullmatch(data):
+                return data
+            if key and key.lower() in self.SENSITIVE_KEYS:
+                return self._scramble(data)
+            return self.mask_text(data)
+        return data
+
+    def mask_text(self, text: str) -> str:
+        if not self.enabled or not text:
+            return text
+        text = self.TOKEN_PATTERN.sub(lambda m: self._scramble(m.group(0)), text)
+        text = self.EMAIL_PATTERN.sub(lambda m: self._mask_email(m.group(0)), text)
+        text = self.CARD_PATTERN.sub(self._mask_card, text)
+        text = self.PHONE_PATTERN.sub(self._mask_phone, text)
+        return text
+
+    def mask_body(self, body: Any) -> Any:
+        if not self.enabled or body is None:
+            return body
+        if isinstance(body, (dict, list)):
+            return self.mask(body)
+        if isinstance(body, str):
+            try:
+                return json.dumps(self.mask(json.loads(body)), separators=(',', ':'))
+            except json.JSONDecodeError:
+                return self.mask_text(body)
+        return body
+
+    def mask_headers(self, headers: Dict[str, Any]) -> Dict[str, Any]:
+        if not self.enabled:
+            return headers
+        return {name: self.mask_text(value) if isinstance(value, str) else value
+                for name, value in headers.items()}
+
+    def _mask_email(self, email: str) -> str:
+        local, _, domain = email.partition('@')
+        labels = domain.split('.')
+        masked_domain = '.'.join([self._scramble(label) for label in labels[:-1]] + labels[-1:])
+        return f"{self._scramble(local)}@{masked_domain}"
+
+    def _mask_card(self, match) -> str:
+        value = match.group(0)
+        digits = [c for c in value if c.isdigit()]
+        if not self._luhn_valid(digits):
+            return value
+        head, tail = value[:-4], value[-4:]
+        return self._scramble(head) + tail
+
+    def _mask_phone(self, match) -> str:
+        value = match.group(0)
+        digit_count = sum(c.isdigit() for c in value)
+        if digit_count < 10 or digit_count > 15:
+            return value
+        return self._scramble(value)
+
+    def _luhn_valid(self, digits) -> bool:
+        total = 0
+        for i, digit in enumerate(reversed(digits)):
+            n = int(digit)
+            if i % 2 == 1:
+                n *= 2
+                if n > 9:
+                    n -= 9
+            total += n
+        return total % 10 == 0
+
+    def _scramble(self, value: str) -> str:
+        stream = hashlib.sha256(f"{self.salt}:{value}".encode('utf-8')).digest()
+        while len(stream) < len(value):
+            stream += hashlib.sha256(stream).digest()
+
+        masked = []
+        for i, char in enumerate(value):
+            b = stream[i]
+            if char.isdigit():
+                masked.append(str(b % 10))
+            elif char.islower():
+                masked.append(chr(ord('a') + b % 26))
+            elif char.isupper():
+                masked.append(chr(ord('A') + b % 26))
+            else:
+                masked.append(char)
+        return ''.join(masked)
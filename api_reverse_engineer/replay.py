@@ -0,0 +1,356 @@
+#!/usr/bin/env python3
+"""
+Capture Replay
+Re-sends captured request sequences against another deployment (typically
+staging) and reports where its responses diverge from the originals
+"""
+
+import argparse
+import base64
+import json
+import sys
+import time
+import urllib.error
+import urllib.request
+from datetime import datetime
+from typing import Dict, List, Any, Optional, Tuple
+from dataclasses import dataclass, field
+from urllib.parse import urlparse, parse_qsl, urlencode
+from traffic_parser import TrafficParser
+from capture_store import CaptureStore
+from secret_redactor import SecretRedactor, SecretProvider, EnvSecretProvider, FileSecretProvider, MissingSecretError
+
+
+@dataclass
+class Divergence:
+    kind: str
+    path: str = ''
+    original: Any = None
+    replayed: Any = None
+
+    def __str__(self) -> str:
+        location = f" {self.path}" if self.path else ''
+        if self.kind in ('status', 'type_changed', 'value_changed'):
+            return f"{self.kind}{location}: {self.original!r} -> {self.replayed!r}"
+        return f"{self.kind}{location}"
+
+
+@dataclass
+class ReplayResult:
+    index: int
+    method: str
+    url: str
+    original_status: int
+    status: Optional[int] = None
+    elapsed: float = 0.0
+    error: str = ''
+    divergences: List[Divergence] = field(default_factory=list)
+
+    @property
+    def ok(self) -> bool:
+        return not self.error and not self.divergences
+
+
+class ReplayEngine:
+    """Replays captures in order against a target deployment.
+
+    Requests are rewritten before sending: hosts are mapped to the target,
+    secret placeholders are resolved through a SecretProvider, the
+    Authorization header can be replaced, and ids are rewritten. Id
+    rewrites are seeded from `id_map` and learned as the replay runs: when
+    a replayed response carries a different value than the original in an
+    `id`/`*_id` field, later requests referencing the original id use the
+    new one, so create-then-read sequences keep working on a fresh target.
+    """
+
+    HOP_HEADERS = {'host', 'content-length', 'connection', 'accept-encoding', 'transfer-encoding', 'keep-alive'}
+
+    def __init__(self, target: Optional[str] = None, host_map: Optional[Dict[str, str]] = None,
+                 auth: Optional[str] = None, headers: Optional[Dict[str, str]] = None,
+                 id_map: Optional[Dict[str, str]] = None, secret_provider: Optional[SecretProvider] = None,
+                 preserve_timing: bool = True, speed: float = 1.0, compare_values: bool = False,
+                 timeout: float = 30):
+        self.target = target.rstrip('/') if target else None
+        self.host_map = {host: url.rstrip('/') for host, url in (host_map or {}).items()}
+        self.auth = auth
+        self.headers = headers or {}
+        self.id_map: Dict[str, str] = dict(id_map or {})
+        self.secret_provider = secret_provider or EnvSecretProvider()
+        self.preserve_timing = preserve_timing
+        self.speed = speed
+        self.compare_values = compare_values
+        self.timeout = timeout
+        self._secrets = SecretRedactor(enabled=False)
+
+    def replay(self, captures: List[Dict[str, Any]]) -> List[ReplayResult]:
+        results = []
+        previous_start = None
+        for index, capture in enumerate(self._in_order(captures)):
+            started_at = self._timestamp(capture.get('started_at'))
+            if self.preserve_timing and previous_start and started_at and started_at > previous_start:
+                time.sleep((started_at - previous_start).total_seconds() / self.speed)
+            previous_start = started_at or previous_start
+            results.append(self.replay_one(index, capture))
+        return results
+
+    def replay_one(self, index: int, capture: Dict[str, Any]) -> ReplayResult:
+        request = capture.get('request', {})
+        response = capture.get('response', {})
+        method, url, headers, body = self._rewrite_request(request)
+        result = ReplayResult(index=index, method=method, url=url, original_status=response.get('status', 200))
+
+        started = time.monotonic()
+        try:
+            status, replayed_body = self._send(method, url, headers, body)
+        except (urllib.error.URLError, OSError) as e:
+            result.error = str(getattr(e, 'reason', e))
+            return result
+        result.elapsed = time.monotonic() - started
+        result.status = status
+
+        if status != result.original_status:
+            result.divergences.append(Divergence('status', original=result.original_status, replayed=status))
+
+        original_json = self._load_json(response.get('body'))
+        replayed_json = self._load_json(replayed_body)
+        if original_json is not None and replayed_json is not None:
+            self._learn_ids(original_json, replayed_json)
+            self._compare(original_json, replayed_json, '$', result.divergences)
+        elif self.compare_values and (response.get('body') or '') != replayed_body:
+            result.divergences.append(Divergence('body_changed'))
+        return result
+
+    def _in_order(self, captures: List[Dict[str, Any]]) -> List[Dict[str, Any]]:
+        if all(capture.get('started_at') for capture in captures):
+            return sorted(captures, key=lambda capture: self._timestamp(capture['started_at']))
+        return list(captures)
+
+    def _timestamp(self, value: Optional[str]) -> Optional[datetime]:
+        if not value:
+            return None
+        try:
+            return datetime.fromisoformat(value.replace('Z', '+00:00'))
+        except ValueError:
+            return None
+
+    def _rewrite_request(self, request: Dict[str, Any]) -> Tuple[str, str, Dict[str, str], Optional[bytes]]:
+        method = request.get('method', 'GET')
+        parsed_url = urlparse(self._resolve(request.get('url', '')))
+
+        origin = f"{parsed_url.scheme}://{parsed_url.netloc}"
+        origin = self.host_map.get(parsed_url.netloc) or self.target or origin
+        path = '/'.join(self.id_map.get(segment, segment) for segment in parsed_url.path.split('/'))
+        query = urlencode([(key, self.id_map.get(value, value))
+                           for key, value in parse_qsl(parsed_url.query, keep_blank_values=True)])
+        url = f"{origin}{path}" + (f"?{query}" if query else '')
+
+        headers = {name: self._resolve(value) for name, value in (request.get('headers') or {}).items()
+                   if name.lower() not in self.HOP_HEADERS and not name.startswith(':')}
+        if self.auth:
+            headers = {name: value for name, value in headers.items() if name.lower() != 'authorization'}
+            headers['Authorization'] = self.auth
+        headers.update(self.headers)
+
+        body = request.get('body')
+        if body is not None and request.get('encoding') != 'base64':
+            if isinstance(body, (dict, list)):
+                body = json.dumps(body)
+            body = self._resolve(body)
+            parsed_body = self._load_json(body)
+            if parsed_body is not None and self.id_map:
+                body = json.dumps(self._rewrite_ids(parsed_body))
+        elif body is not None:
+            body = base64.b64decode(body)
+
+        return method, url, headers, body.encode('utf-8') if isinstance(body, str) else body
+
+    def _resolve(self, text: str) -> str:
+        try:
+            return self._secrets.resolve(text, self.secret_provider)
+        except MissingSecretError as e:
+            raise MissingSecretError(
+                f"capture references secret '{e.args[0]}' but no value was provided "
+                f"(set it in the secrets file or the environment)") from None
+
+    def _rewrite_ids(self, data: Any) -> Any:
+        if isinstance(data, dict):
+            return {key: self._rewrite_ids(value) for key, value in data.items()}
+        if isinstance(data, list):
+            return [self._rewrite_ids(item) for item in data]
+        if isinstance(data, (str, int)) and not isinstance(data, bool) and str(data) in self.id_map:
+            replacement = self.id_map[str(data)]
+            return int(replacement) if isinstance(data, int) and replacement.isdigit() else replacement
+        return data
+
+    def _learn_ids(self, original: Any, replayed: Any):
+        if isinstance(original, dict) and isinstance(replayed, dict):
+            for key, value in original.items():
+                if key not in replayed:
+                    continue
+                is_id = key == 'id' or key.endswith('_id')
+                if is_id and isinstance(value, (str, int)) and not isinstance(value, bool) and value != replayed[key]:
+                    self.id_map[str(value)] = str(replayed[key])
+                else:
+                    self._learn_ids(value, replayed[key])
+        elif isinstance(original, list) and isinstance(replayed, list):
+            for original_item, replayed_item in zip(original, replayed):
+                self._learn_ids(original_item, replayed_item)
+
+    def _compare(self, original: Any, replayed: Any, path: str, divergences: List[Divergence]):
+        original_type, replayed_type = self._json_type(original), self._json_type(replayed)
+        if original_type != replayed_type and 'null' not in (original_type, replayed_type):
+            divergences.append(Divergence('type_changed', path, original_type, replayed_type))
+            return
+
+        if isinstance(original, dict) and isinstance(replayed, dict):
+            for key in sorted(original.keys() - replayed.keys()):
+                divergences.append(Divergence('field_removed', f"{path}.{key}"))
+            for key in sorted(replayed.keys() - original.keys()):
+                divergences.append(Divergence('field_added', f"{path}.{key}"))
+            for key in sorted(original.keys() & replayed.keys()):
+                self._compare(original[key], replayed[key], f"{path}.{key}", divergences)
+        elif isinstance(original, list) and isinstance(replayed, list):
+            if original and replayed:
+                self._compare(original[0], replayed[0], f"{path}[]", divergences)
+            if self.compare_values and len(original) != len(replayed):
+                divergences.append(Divergence('value_changed', f"{path}.length", len(original), len(replayed)))
+        elif self.compare_values and original != replayed and self.id_map.get(str(original)) != str(replayed):
+            divergences.append(Divergence('value_changed', path, original, replayed))
+
+    def _json_type(self, value: Any) -> str:
+        if value is None:
+            return 'null'
+        if isinstance(value, bool):
+            return 'boolean'
+        if isinstance(value, (int, float)):
+            return 'number'
+        if isinstance(value, str):
+            return 'string'
+        return 'array' if isinstance(value, list) else 'object'
+
+    def _send(self, method: str, url: str, headers: Dict[str, str], body: Optional[bytes]) -> Tuple[int, str]:
+        request = urllib.request.Request(url, data=body, method=method, headers=headers)
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                return response.status, response.read().decode('utf-8', errors='replace')
+        except urllib.error.HTTPError as e:
+            return e.code, e.read().decode('utf-8', errors='replace')
+
+    def _load_json(self, body: Any) -> Any:
+        if isinstance(body, (dict, list)):
+            return body
+        if not body:
+            return None
+        try:
+            return json.loads(body)
+        except (json.JSONDecodeError, TypeError):
+            return None
+
+
+def format_report(results: List[ReplayResult], verbose: bool = False) -> str:
+    lines = []
+    for result in results:
+        if result.ok and not verbose:
+            continue
+        marker = '✅' if result.ok else '❌'
+        status = result.status if result.status is not None else 'error'
+        lines.append(f"{marker} #{result.index} {result.method} {result.url} "
+                     f"[{result.original_status} -> {status}, {result.elapsed * 1000:.0f}ms]")
+        if result.error:
+            lines.append(f"     {result.error}")
+        for divergence in result.divergences:
+            lines.append(f"     {divergence}")
+    diverged = sum(1 for result in results if not result.ok)
+    lines.append(f"\n{len(results)} requests replayed, {diverged} diverged")
+    return '\n'.join(lines)
+
+
+def load_captures(har: Optional[str] = None, json_file: Optional[str] = None,
+                  store: Optional[str] = None) -> List[Dict[str, Any]]:
+    if store:
+        return list(CaptureStore(store).samples())
+    parser = TrafficParser(mask_pii=False, redact_secrets=False)
+    if har:
+        parser.parse_har_file(har)
+    elif json_file:
+        with open(json_file, 'r') as f:
+            parser.parse_raw_traffic(json.load(f))
+    return parser.captures
+
+
+def parse_pairs(values: Optional[List[str]], separator: str = '=') -> Dict[str, str]:
+    pairs = {}
+    for value in values or []:
+        key, sep, rest = value.partition(separator)
+        if not sep:
+            raise ValueError(f"expected KEY{separator}VALUE, got '{value}'")
+        pairs[key.strip()] = rest.strip()
+    return pairs
+
+
+def main():
+    parser = argparse.ArgumentParser(
+        description='Replay captured API traffic against a staging deployment and report divergences',
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog="""
+Examples:
+  # Replay a HAR capture against staging with original timing
+  python replay.py --har traffic.har --target https://staging.example.com
+
+  # Replay a capture store as fast as possible with a staging token
+  python replay.py --from-store captures --target https://staging.example.com \\
+      --auth "Bearer $STAGING_TOKEN" --no-timing
+        """
+    )
+
+    parser.add_argument('--har', type=str, help='HAR file to replay')
+    parser.add_argument('--json', type=str, help='JSON traffic file (or --save-capture output) to replay')
+    parser.add_argument('--from-store', type=str, help='Capture store directory to replay')
+    parser.add_argument('--target', type=str, help='Base URL to send every request to (e.g. https://staging.example.com)')
+    parser.add_argument('--host-map', action='append', metavar='HOST=URL',
+                        help='Send requests for HOST to URL instead (repeatable, for multi-host captures)')
+    parser.add_argument('--auth', type=str, help='Authorization header value to use instead of the captured one')
+    parser.add_argument('--header', action='append', metavar='NAME:VALUE', help='Extra header to set (repeatable)')
+    parser.add_argument('--rewrite-id', action='append', metavar='OLD=NEW',
+                        help='Replace an id in paths, query strings, and bodies (repeatable)')
+    parser.add_argument('--secrets-file', type=str,
+                        help='Secrets file written by cli.py --secrets-file (default: CAPTURE_SECRET_* environment variables)')
+    parser.add_argument('--no-timing', action='store_true', help='Send requests back to back instead of with the original gaps')
+    parser.add_argument('--speed', type=float, default=1.0, help='Replay speed multiplier for the original timing (default: 1.0)')
+    parser.add_argument('--compare-values', action='store_true', help='Report changed values, not only status and shape changes')
+    parser.add_argument('--timeout', type=float, default=30, help='Per-request timeout in seconds (default: 30)')
+    parser.add_argument('--verbose', action='store_true', help='List every replayed request, not only divergent ones')
+
+    args = parser.parse_args()
+
+    if not args.har and not args.json and not args.from_store:
+        parser.error('Please provide --har, --json, or --from-store')
+    if not args.target and not args.host_map:
+        parser.error('Please provide --target or --host-map')
+
+    try:
+        engine = ReplayEngine(
+            target=args.target,
+            host_map=parse_pairs(args.host_map),
+            auth=args.auth,
+            headers=parse_pairs(args.header, ':'),
+            id_map=parse_pairs(args.rewrite_id),
+            secret_provider=FileSecretProvider(args.secrets_file) if args.secrets_file else None,
+            preserve_timing=not args.no_timing,
+            speed=args.speed,
+            compare_values=args.compare_values,
+            timeout=args.timeout
+        )
+        captures = load_captures(args.har, args.json, args.from_store)
+        print(f"🔁 Replaying {len(captures)} requests against {args.target or ', '.join(engine.host_map.values())}")
+        results = engine.replay(captures)
+        print(format_report(results, args.verbose))
+        sys.exit(0 if all(result.ok for result in results) else 1)
+
+    except (ValueError, MissingSecretError) as e:
+        print(f"❌ Error: {e.args[0] if e.args else e}")
+        sys.exit(2)
+
+
+if __name__ == '__main__':
+    main()
@@ -0,0 +1,234 @@
+import os
+import re
+import json
+import hashlib
+import urllib.error
+import urllib.request
+from datetime import datetime, timezone
+from typing import Dict, List, Any, Optional
+from dataclasses import dataclass, field, asdict
+from traffic_parser import APIEndpoint
+
+
+@dataclass
+class SchemaSnapshot:
+    api_name: str
+    base_url: str
+    endpoints: Dict[str, Dict[str, Any]]
+    source_captures: List[str] = field(default_factory=list)
+    version: int = 0
+    created_at: str = ''
+    fingerprint: str = ''
+
+    @classmethod
+    def from_endpoints(cls, api_name: str, base_url: str, endpoints: Dict[str, APIEndpoint],
+                       source_captures: Optional[List[str]] = None) -> 'SchemaSnapshot':
+        schema = {}
+        for endpoint in endpoints.values():
+            path = endpoint.path_pattern
+            if endpoint.origin and endpoint.origin != base_url:
+                path = endpoint.origin + path
+            label = f"{endpoint.method} {path}"
+            schema[label] = {
+                'method': endpoint.method,
+                'path': endpoint.path_pattern,
+                'path_params': sorted(endpoint.path_params),
+                'query_params': dict(sorted(endpoint.query_params.items())),
+                'request_body_schema': _strip_examples(endpoint.request_body_schema),
+                'response_schemas': {str(status): _strip_examples(s) for status, s in sorted(endpoint.response_schemas.items())}
+            }
+        snapshot = cls(api_name=api_name, base_url=base_url, endpoints=dict(sorted(schema.items())),
+                       source_captures=list(source_captures or []))
+        snapshot.fingerprint = hashlib.sha256(
+            json.dumps(snapshot.endpoints, sort_keys=True).encode('utf-8')).hexdigest()
+        return snapshot
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> 'SchemaSnapshot':
+        return cls(**{k: data[k] for k in cls.__dataclass_fields__ if k in data})
+
+    def to_dict(self) -> Dict[str, Any]:
+        return asdict(self)
+
+
+@dataclass
+class SchemaChange:
+    kind: str
+    endpoint: str
+    path: str = ''
+    before: Any = None
+    after: Any = None
+
+    def __str__(self) -> str:
+        location = f"{self.endpoint} {self.path}".strip()
+        if self.kind == 'type_changed':
+            return f"~ {location}: {self.before} -> {self.after}"
+        marker = {'endpoint_added': '+', 'field_added': '+', 'endpoint_removed': '-', 'field_removed': '-'}.get(self.kind, '~')
+        return f"{marker} {location} ({self.kind.replace('_', ' ')})"
+
+
+SAMPLE_KEYS = {'example', 'samples', 'null_count', 'null_rate', 'observed_values', 'min_length', 'max_length'}
+
+
+def _strip_examples(schema: Any) -> Any:
+    # Sample statistics grow with every capture; only the shape is versioned.
+    if isinstance(schema, dict):
+        return {k: _strip_examples(v) for k, v in sorted(schema.items()) if k not in SAMPLE_KEYS}
+    if isinstance(schema, list):
+        return sorted(schema) if all(isinstance(i, str) for i in schema) else [_strip_examples(i) for i in schema]
+    return schema
+
+
+def compare_snapshots(old: SchemaSnapshot, new: SchemaSnapshot) -> List[SchemaChange]:
+    changes = []
+    for label in sorted(set(old.endpoints) | set(new.endpoints)):
+        if label not in new.endpoints:
+            changes.append(SchemaChange('endpoint_removed', label))
+        elif label not in old.endpoints:
+            changes.append(SchemaChange('endpoint_added', label))
+        else:
+            before, after = old.endpoints[label], new.endpoints[label]
+            _compare_schema(label, 'request', before.get('request_body_schema', {}), after.get('request_body_schema', {}), changes)
+            for status in sorted(set(before.get('response_schemas', {})) | set(after.get('response_schemas', {}))):
+                _compare_schema(label, f"response[{status}]",
+                                before.get('response_schemas', {}).get(status, {}),
+                                after.get('response_schemas', {}).get(status, {}), changes)
+    return changes
+
+
+def _compare_schema(endpoint: str, path: str, before: Dict[str, Any], after: Dict[str, Any], changes: List[SchemaChange]):
+    if not before and not after:
+        return
+    if not before:
+        changes.append(SchemaChange('field_added', endpoint, path))
+        return
+    if not after:
+        changes.append(SchemaChange('field_removed', endpoint, path))
+        return
+    if before.get('type') != after.get('type'):
+        changes.append(SchemaChange('type_changed', endpoint, path, before.get('type'), after.get('type')))
+        return
+
+    if before.get('type') == 'object':
+        before_props, after_props = before.get('properties', {}), after.get('properties', {})
+        for prop in sorted(set(before_props) | set(after_props)):
+            _compare_schema(endpoint, f"{path}.{prop}", before_props.get(prop, {}), after_props.get(prop, {}), changes)
+    elif before.get('type') == 'array':
+        _compare_schema(endpoint, f"{path}[]", before.get('items', {}), after.get('items', {}), changes)
+
+
+class SchemaRegistry:
+    """Stores one versioned schema snapshot per inference run.
+
+    Publishing a snapshot whose fingerprint matches the latest version
+    returns that version instead of creating a new one, so regenerating
+    from unchanged captures is reproducible.
+    """
+
+    def publish(self, snapshot: SchemaSnapshot) -> SchemaSnapshot:
+        latest = self.get(snapshot.api_name)
+        if latest and latest.fingerprint == snapshot.fingerprint:
+            return latest
+        snapshot.version = (latest.version if latest else 0) + 1
+        snapshot.created_at = datetime.now(timezone.utc).strftime('%Y-%m-%dT%H:%M:%SZ')
+        self._store(snapshot)
+        return snapshot
+
+    def compare(self, api_name: str, old_version: int, new_version: Optional[int] = None) -> List[SchemaChange]:
+        old = self.get(api_name, old_version)
+        new = self.get(api_name, new_version)
+        if old is None or new is None:
+            raise KeyError(f"unknown schema version for {api_name}")
+        return compare_snapshots(old, new)
+
+    def versions(self, api_name: str) -> List[int]:
+        raise NotImplementedError
+
+    def get(self, api_name: str, version: Optional[int] = None) -> Optional[SchemaSnapshot]:
+        raise NotImplementedError
+
+    def _store(self, snapshot: SchemaSnapshot):
+        raise NotImplementedError
+
+
+class FileSchemaRegistry(SchemaRegistry):
+    def __init__(self, root: str):
+        self.root = root
+
+    def versions(self, api_name: str) -> List[int]:
+        api_dir = self._api_dir(api_name)
+        if not os.path.isdir(api_dir):
+            return []
+        return sorted(int(m.group(1)) for m in (re.match(r'^v(\d+)\.json$', f) for f in os.listdir(api_dir)) if m)
+
+    def get(self, api_name: str, version: Optional[int] = None) -> Optional[SchemaSnapshot]:
+        versions = self.versions(api_name)
+        if not versions:
+            return None
+        version = version or versions[-1]
+        path = os.path.join(self._api_dir(api_name), f"v{version:04d}.json")
+        if not os.path.exists(path):
+            return None
+        with open(path, 'r') as f:
+            return SchemaSnapshot.from_dict(json.load(f))
+
+    def _store(self, snapshot: SchemaSnapshot):
+        api_dir = self._api_dir(snapshot.api_name)
+        os.makedirs(api_dir, exist_ok=True)
+        with open(os.path.join(api_dir, f"v{snapshot.version:04d}.json"), 'w') as f:
+            json.dump(snapshot.to_dict(), f, indent=2, sort_keys=True)
+
+    def _api_dir(self, api_name: str) -> str:
+        return os.path.join(self.root, re.sub(r'[^0-9A-Za-z_.-]+', '_', api_name))
+
+
+class HTTPSchemaRegistry(SchemaRegistry):
+    """Registry backed by an HTTP service exposing:
+
+        GET  {base}/apis/{api}/versions            -> [1, 2, ...]
+        GET  {base}/apis/{api}/versions/{version}  -> snapshot
+        PUT  {base}/apis/{api}/versions/{version}  <- snapshot
+    """
+
+    def __init__(self, base_url: str, token: Optional[str] = None, timeout: float = 30):
+        self.base_url = base_url.rstrip('/')
+        self.token = token
+        self.timeout = timeout
+
+    def versions(self, api_name: str) -> List[int]:
+        return sorted(self._request('GET', f"/apis/{api_name}/versions") or [])
+
+    def get(self, api_name: str, version: Optional[int] = None) -> Optional[SchemaSnapshot]:
+        if version is None:
+            versions = self.versions(api_name)
+            if not versions:
+                return None
+            version = versions[-1]
+        data = self._request('GET', f"/apis/{api_name}/versions/{version}")
+        return SchemaSnapshot.from_dict(data) if data else None
+
+    def _store(self, snapshot: SchemaSnapshot):
+        self._request('PUT', f"/apis/{snapshot.api_name}/versions/{snapshot.version}", snapshot.to_dict())
+
+    def _request(self, method: str, path: str, body: Any = None) -> Any:
+        data = json.dumps(body).encode('utf-8') if body is not None else None
+        request = urllib.request.Request(self.base_url + path, data=data, method=method)
+        request.add_header('Accept', 'application/json')
+        if data is not None:
+            request.add_header('Content-Type', 'application/json')
+        if self.token:
+            request.add_header('Authorization', f"Bearer {self.token}")
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                payload = response.read()
+        except urllib.error.HTTPError as e:
+            if e.code == 404:
+                return None
+            raise
+        return json.loads(payload) if payload else None
+
+
+def open_registry(location: str) -> SchemaRegistry:
+    if location.startswith(('http://', 'https://')):
+        return HTTPSchemaRegistry(location, token=os.environ.get('SCHEMA_REGISTRY_TOKEN'))
+    return FileSchemaRegistry(location)
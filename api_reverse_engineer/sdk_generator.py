@@ -6,12 +6,21 @@ from traffic_parser import APIEndpoint
 
 
 class SDKGenerator:
+    # Operations are generated by path, then in this method order, whatever
+    # order the capture reached them in, so a new capture moves no code.
+    METHOD_ORDER = ['GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'OPTIONS']
+    
     def __init__(self, api_name: str, base_url: str, endpoints: Dict[str, APIEndpoint]):
         self.api_name = api_name
         self.base_url = base_url
-        self.endpoints = endpoints
+        self.endpoints = dict(sorted(endpoints.items(), key=lambda item: self._endpoint_order(item[1])))
         self.class_name = self._to_class_name(api_name)
     
+    def _endpoint_order(self, endpoint: APIEndpoint):
+        method = endpoint.method.upper()
+        rank = self.METHOD_ORDER.index(method) if method in self.METHOD_ORDER else len(self.METHOD_ORDER)
+        return (endpoint.origin, endpoint.path_pattern, rank, method)
+    
     def _to_class_name(self, name: str) -> str:
         return ''.join(word.capitalize() for word in re.split(r'[_\-\s]+', name))
     
@@ -108,6 +117,13 @@ class SDKGenerator:
             }
             base_type = type_map.get(schema_type, 'interface{}')
             
+            if schema_type == 'string' and schema.get('format') in ('date-time', 'date'):
+                base_type = 'Time'
+            elif schema_type in ('string', 'number') and schema.get('format') == 'decimal':
+                base_type = 'Decimal'
+            elif schema_type == 'object' and schema.get('format') == 'money' and len(schema.get('properties', {})) == 2:
+                base_type = 'Money'
+            
             if schema_type == 'array' and 'items' in schema:
                 item_type = self._schema_to_type_hint(schema['items'], lang)
                 base_type = f"[]{item_type}"
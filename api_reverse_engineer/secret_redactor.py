@@ -0,0 +1,190 @@
+import os
+import re
+import json
+import hashlib
+from typing import Dict, Any, Optional
+from urllib.parse import urlparse, parse_qsl, urlencode
+
+
+class MissingSecretError(KeyError):
+    pass
+
+
+class SecretProvider:
+    """Resolves secret placeholders left in redacted captures."""
+
+    def get(self, name: str) -> Optional[str]:
+        raise NotImplementedError
+
+
+class EnvSecretProvider(SecretProvider):
+    def __init__(self, prefix: str = 'CAPTURE_SECRET_'):
+        self.prefix = prefix
+
+    def get(self, name: str) -> Optional[str]:
+        return os.environ.get(self.prefix + re.sub(r'[^0-9A-Za-z]+', '_', name).upper())
+
+
+class FileSecretProvider(SecretProvider):
+    def __init__(self, path: str):
+        self.path = path
+        self._secrets: Optional[Dict[str, str]] = None
+
+    def get(self, name: str) -> Optional[str]:
+        if self._secrets is None:
+            with open(self.path, 'r') as f:
+                self._secrets = json.load(f)
+        return self._secrets.get(name)
+
+
+class SecretRedactor:
+    """Replaces credentials in captures with named placeholders.
+
+    Authorization headers, cookies, API keys in headers or query strings,
+    and token, secret, and password fields in JSON and form-encoded bodies
+    become `{{secret:<name>}}` so captures and fixtures can be shared.
+    Original values are kept only in memory and can be written to a separate
+    owner-only file for local replay.
+    """
+
+    PLACEHOLDER_PATTERN = re.compile(r'\{\{secret:([\w.\-]+)\}\}')
+    SENSITIVE_HEADERS = {
+        'authorization', 'proxy-authorization', 'cookie', 'set-cookie', 'x-api-key', 'api-key',
+        'apikey', 'x-auth-token', 'x-access-token', 'x-csrf-token', 'x-xsrf-token', 'x-amz-security-token'
+    }
+    SENSITIVE_PARAMS = {
+        'api_key', 'apikey', 'api-key', 'key', 'access_token', 'token', 'auth', 'signature', 'sig',
+        'client_secret', 'refresh_token', 'id_token', 'session', 'sessionid'
+    }
+    SENSITIVE_FIELDS = {
+        'access_token', 'refresh_token', 'id_token', 'client_secret', 'api_key', 'apikey', 'secret_key',
+        'password', 'passwd', 'secret', 'app_secret', 'consumer_secret', 'client_assertion'
+    }
+    FORM_CONTENT_TYPE = 'application/x-www-form-urlencoded'
+    AUTH_SCHEMES = ('bearer', 'basic', 'token', 'digest', 'apikey')
+
+    def __init__(self, enabled: bool = True):
+        self.enabled = enabled
+        self.secrets: Dict[str, str] = {}
+        self._names_by_value: Dict[str, str] = {}
+
+    def redact(self, data: Dict[str, Any]) -> Dict[str, Any]:
+        if not self.enabled:
+            return data
+
+        redacted = dict(data)
+        request = dict(data.get('request', {}))
+        response = dict(data.get('response', {}))
+
+        if request.get('url'):
+            request['url'] = self._redact_url(request['url'])
+        for message in (request, response):
+            if message.get('headers'):
+                message['headers'] = {name: self._redact_header(name, value)
+                                      for name, value in message['headers'].items()}
+            if 'body' in message and message.get('encoding') != 'base64':
+                message['body'] = self._redact_body(message['body'], self._content_type(message))
+
+        redacted['request'] = request
+        redacted['response'] = response
+        return redacted
+
+    def resolve(self, text: str, provider: Optional[SecretProvider] = None) -> str:
+        def substitute(match):
+            value = self.secrets.get(match.group(1)) if provider is None else provider.get(match.group(1))
+            if value is None:
+                raise MissingSecretError(match.group(1))
+            return value
+        return self.PLACEHOLDER_PATTERN.sub(substitute, text)
+
+    def write_secrets(self, path: str):
+        fd = os.open(path, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0o600)
+        with os.fdopen(fd, 'w') as f:
+            json.dump(self.secrets, f, indent=2)
+
+    def _placeholder(self, base_name: str, value: str) -> str:
+        if self.PLACEHOLDER_PATTERN.fullmatch(value):
+            return value
+        fingerprint = hashlib.sha256(value.encode('utf-8')).hexdigest()
+        name = self._names_by_value.get(f"{base_name}:{fingerprint}")
+        if name is None:
+            name = base_name
+            suffix = 2
+            while name in self.secrets:
+                name = f"{base_name}_{suffix}"
+                suffix += 1
+            self.secrets[name] = value
+            self._names_by_value[f"{base_name}:{fingerprint}"] = name
+        return f"{{{{secret:{name}}}}}"
+
+    def _secret_name(self, name: str) -> str:
+        return re.sub(r'[^0-9a-z]+', '_', name.lower()).strip('_')
+
+    def _redact_header(self, name: str, value: Any) -> Any:
+        lowered = name.lower()
+        if lowered not in self.SENSITIVE_HEADERS or not isinstance(value, str) or not value:
+            return value
+
+        if lowered in ('cookie', 'set-cookie'):
+            return self._redact_cookie(value)
+
+        scheme, _, credential = value.partition(' ')
+        if credential and scheme.lower() in self.AUTH_SCHEMES:
+            return f"{scheme} {self._placeholder(f'{self._secret_name(scheme)}_token', credential)}"
+        return self._placeholder(self._secret_name(name), value)
+
+    def _redact_cookie(self, value: str) -> str:
+        parts = []
+        for index, part in enumerate(value.split(';')):
+            cookie_name, sep, cookie_value = part.strip().partition('=')
+            # Attributes of a Set-Cookie header (Path, Expires, ...) are not secret.
+            if not sep or (index > 0 and cookie_name.lower() in ('path', 'domain', 'expires', 'max-age', 'samesite')):
+                parts.append(part.strip())
+                continue
+            parts.append(f"{cookie_name}={self._placeholder(f'cookie.{cookie_name}', cookie_value)}")
+        return '; '.join(parts)
+
+    def _redact_url(self, url: str) -> str:
+        parsed_url = urlparse(url)
+        if not parsed_url.query:
+            return url
+        params = [(key, self._placeholder(f'query.{key}', value) if key.lower() in self.SENSITIVE_PARAMS else value)
+                  for key, value in parse_qsl(parsed_url.query, keep_blank_values=True)]
+        return parsed_url._replace(query=urlencode(params, safe='{}:')).geturl()
+
+    def _content_type(self, message: Dict[str, Any]) -> str:
+        for name, value in (message.get('headers') or {}).items():
+            if name.lower() == 'content-type' and isinstance(value, str):
+                return value.split(';')[0].strip().lower()
+        return ''
+
+    def _redact_body(self, body: Any, content_type: str = '') -> Any:
+        if isinstance(body, str) and content_type == self.FORM_CONTENT_TYPE:
+            return self._redact_form(body)
+        if isinstance(body, str):
+            try:
+                parsed = json.loads(body)
+            except json.JSONDecodeError:
+                return body
+            if not isinstance(parsed, (dict, list)):
+                return body
+            return json.dumps(self._redact_json(parsed), separators=(',', ':'))
+        if isinstance(body, (dict, list)):
+            return self._redact_json(body)
+        return body
+
+    def _redact_form(self, body: str) -> str:
+        params = [(key, self._placeholder(f'body.{key}', value)
+                   if key.lower() in self.SENSITIVE_FIELDS and value else value)
+                  for key, value in parse_qsl(body, keep_blank_values=True)]
+        return urlencode(params, safe='{}:')
+
+    def _redact_json(self, data: Any) -> Any:
+        if isinstance(data, dict):
+            return {key: self._placeholder(f'body.{key}', value)
+                    if key.lower() in self.SENSITIVE_FIELDS and isinstance(value, str) and value
+                    else self._redact_json(value)
+                    for key, value in data.items()}
+        if isinstance(data, list):
+            return [self._redact_json(item) for item in data]
+        return data
@@ -0,0 +1,140 @@
+"""
+Tests for the Go SDK generator.
+"""
+
+import os
+import re
+import shutil
+import subprocess
+import tempfile
+import unittest
+
+from traffic_parser import TrafficParser
+from go_generator import GoSDKGenerator
+
+
+def exchange(method, url, response_body, request_body=None, content_type='application/json', headers=None):
+    """A captured request and response in the raw traffic format."""
+    entry = {
+        'request': {'url': url, 'method': method, 'headers': {'Content-Type': 'application/json'}},
+        'response': {'status': 200, 'headers': dict({'Content-Type': content_type}, **(headers or {})),
+                     'body': response_body},
+    }
+    if request_body:
+        entry['request']['body'] = request_body
+    return entry
+
+
+SHOP = 'https://api.shop.example.com'
+
+SHOP_TRAFFIC = [
+    exchange('GET', SHOP + '/v1/orders/12', '{"id":12,"total":5,"state":"paid"}'),
+    exchange('GET', SHOP + '/v1/orders/13', '{"id":13,"total":6,"state":"new"}'),
+    exchange('POST', SHOP + '/v1/carts', '{"id":1}', '{"sku":"a","qty":2}'),
+    exchange('GET', SHOP + '/v1/reports/1', 'a,b\n1,2\n', content_type='text/csv',
+             headers={'Content-Disposition': 'attachment; filename=r.csv'}),
+]
+
+
+@unittest.skipUnless(shutil.which('go'), "go toolchain not installed")
+class TestGoGeneratorBuilds(unittest.TestCase):
+    """Generated SDKs compile and vet cleanly."""
+
+    def setUp(self):
+        """Set up test fixtures."""
+        self.temp_dir = tempfile.mkdtemp()
+        self.parser = TrafficParser()
+        self.parser.parse_raw_traffic(SHOP_TRAFFIC)
+
+    def tearDown(self):
+        """Clean up test fixtures."""
+        shutil.rmtree(self.temp_dir, ignore_errors=True)
+
+    def assertBuilds(self, generator):
+        generator.generate(self.temp_dir)
+        for command in (['go', 'build', './...'], ['go', 'vet', './...']):
+            result = subprocess.run(command, cwd=self.temp_dir, capture_output=True, text=True)
+            self.assertEqual(result.returncode, 0, f"{' '.join(command)} failed:\n{result.stderr}")
+
+    def test_default_mode(self):
+        """Test an SDK returning (T, error)."""
+        self.assertBuilds(GoSDKGenerator('Shop', self.parser.base_url, self.parser.endpoints))
+
+    def test_results_mode_with_download(self):
+        """Test an SDK returning Result[T] with a download endpoint."""
+        generator = GoSDKGenerator('Shop', self.parser.base_url, self.parser.endpoints, result_types=True)
+        self.assertBuilds(generator)
+        with open(f"{self.temp_dir}/client.go") as f:
+            client = f.read()
+        self.assertIn("(*Download, error) {", client)
+        self.assertIn("w io.Writer, opts ...RequestOption) (int64, error) {", client)
+
+    def test_readme_workflow_example(self):
+        """Test that the README's Workflows example compiles against the SDK."""
+        parser = TrafficParser()
+        parser.parse_har_file(os.path.join(os.path.dirname(__file__), '..', 'example_traffic.har'))
+        generator = GoSDKGenerator('ExampleAPI', parser.base_url, parser.endpoints)
+        generator.generate(self.temp_dir)
+        with open(f"{self.temp_dir}/README.md") as f:
+            snippet = re.search(r'## Workflows\n.*?```go\n(.*?)```', f.read(), re.S).group(1)
+        with open(f"{self.temp_dir}/readme_example.go", 'w') as f:
+            f.write(f"package {generator._to_snake_case(generator.api_name)}\n\n"
+                    f"import \"context\"\n\n"
+                    f"func readmeWorkflow(ctx context.Context, client *{generator.class_name}Client) error {{\n"
+                    f"{snippet}_ = state\nreturn err\n}}\n")
+        result = subprocess.run(['go', 'vet', '.'], cwd=self.temp_dir, capture_output=True, text=True)
+        self.assertEqual(result.returncode, 0, result.stderr)
+
+
+class TestGoVersion(unittest.TestCase):
+    """The SDK builds with the Go version its go.mod declares."""
+
+    # Standard library packages newer than the go.mod version, by the build
+    # constraint files importing them need. go vet's stdversion check covers
+    # newer symbols in older packages.
+    NEWER_PACKAGES = {'"log/slog"': 'go1.21', '"slices"': 'go1.21', '"maps"': 'go1.21', '"cmp"': 'go1.21'}
+
+    def test_newer_packages_are_gated(self):
+        """Test that files importing post-1.20 packages carry a build constraint."""
+        temp_dir = tempfile.mkdtemp()
+        self.addCleanup(shutil.rmtree, temp_dir, ignore_errors=True)
+        parser = TrafficParser()
+        parser.parse_raw_traffic(SHOP_TRAFFIC)
+        GoSDKGenerator('Shop', parser.base_url, parser.endpoints).generate(temp_dir)
+        with open(f"{temp_dir}/go.mod") as f:
+            self.assertIn("\ngo 1.20\n", f.read())
+        for name in sorted(os.listdir(temp_dir)):
+            if not name.endswith('.go'):
+                continue
+            with open(f"{temp_dir}/{name}") as f:
+                source = f.read()
+            for package, version in self.NEWER_PACKAGES.items():
+                if package in source:
+                    self.assertTrue(source.startswith(f"//go:build {version}\n"),
+                                    f"{name} imports {package} without //go:build {version}")
+
+
+class TestGoVersionFacades(unittest.TestCase):
+    """Operations captured under several versions."""
+
+    def facades(self, v2_body):
+        parser = TrafficParser()
+        parser.parse_raw_traffic([
+            exchange('GET', SHOP + '/v1/orders/12', '{"id":12,"total":5}'),
+            exchange('GET', SHOP + '/v2/orders/12', v2_body),
+        ])
+        return GoSDKGenerator('Shop', parser.base_url, parser.endpoints)._generate_go_version_facades()
+
+    def test_facade_for_matching_types(self):
+        """Test versions with the same types share a facade."""
+        facades = self.facades('{"id":12,"total":5}')
+        self.assertEqual(len(facades), 1)
+        self.assertIn("falling back to\n// /v1/orders/{id}", facades[0])
+
+    def test_no_facade_for_differing_types(self):
+        """Test versions with different response types get none."""
+        self.assertEqual(self.facades('{"id":12,"total":5,"currency":"USD"}'), [])
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,79 @@
+"""
+Tests for credential redaction in captures.
+"""
+
+import json
+import unittest
+from urllib.parse import parse_qs
+
+from pii_masker import PIIMasker
+from secret_redactor import SecretRedactor
+
+
+class TestSecretRedactor(unittest.TestCase):
+    """Test SecretRedactor functionality."""
+
+    def setUp(self):
+        """Set up test fixtures."""
+        self.redactor = SecretRedactor()
+
+    def test_json_body(self):
+        """Test token and password fields in a JSON body."""
+        redacted = self.redactor.redact({
+            'request': {'method': 'POST', 'url': 'https://api.example.com/login',
+                        'headers': {'Content-Type': 'application/json'},
+                        'body': '{"email":"a@example.com","password":"hunter2"}'},
+            'response': {'status': 200, 'headers': {'Content-Type': 'application/json'},
+                         'body': '{"access_token":"at-123"}'},
+        })
+        self.assertEqual(json.loads(redacted['request']['body']),
+                         {'email': 'a@example.com', 'password': '{{secret:body.password}}'})
+        self.assertEqual(json.loads(redacted['response']['body']),
+                         {'access_token': '{{secret:body.access_token}}'})
+        self.assertEqual(self.redactor.secrets['body.password'], 'hunter2')
+
+    def test_form_encoded_token_request(self):
+        """Test an OAuth token request with a form-encoded body."""
+        body = ('grant_type=password&client_id=app&client_secret=s3cr3t'
+                '&username=alice&password=hunter2&refresh_token=rt-1')
+        redacted = self.redactor.redact({
+            'request': {'method': 'POST', 'url': 'https://auth.example.com/oauth/token',
+                        'headers': {'Content-Type': 'application/x-www-form-urlencoded; charset=utf-8'},
+                        'body': body},
+            'response': {'status': 200, 'headers': {'Content-Type': 'application/json'},
+                         'body': '{"access_token":"at-123","token_type":"bearer"}'},
+        })
+        form = parse_qs(redacted['request']['body'])
+        self.assertEqual(form['grant_type'], ['password'])
+        self.assertEqual(form['client_id'], ['app'])
+        self.assertEqual(form['username'], ['alice'])
+        self.assertEqual(form['client_secret'], ['{{secret:body.client_secret}}'])
+        self.assertEqual(form['password'], ['{{secret:body.password}}'])
+        self.assertEqual(form['refresh_token'], ['{{secret:body.refresh_token}}'])
+        self.assertNotIn('hunter2', redacted['request']['body'])
+        self.assertNotIn('s3cr3t', redacted['request']['body'])
+        self.assertEqual(self.redactor.resolve(redacted['request']['body']), body)
+
+    def test_form_body_without_form_content_type(self):
+        """Test that other bodies are not parsed as forms."""
+        redacted = self.redactor.redact({
+            'request': {'method': 'POST', 'url': 'https://api.example.com/notes',
+                        'headers': {'Content-Type': 'text/plain'}, 'body': 'password=hunter2'},
+            'response': {'status': 204, 'headers': {}},
+        })
+        self.assertEqual(redacted['request']['body'], 'password=hunter2')
+
+    def test_placeholders_survive_pii_masking(self):
+        """Test that PII masking leaves redacted placeholders resolvable."""
+        redacted = self.redactor.redact({
+            'request': {'method': 'POST', 'url': 'https://api.example.com/users',
+                        'headers': {'Content-Type': 'application/json'},
+                        'body': '{"name":"Alice","password":"hunter2"}'},
+            'response': {'status': 201, 'headers': {}},
+        })
+        masked = PIIMasker().mask_body(redacted['request']['body'])
+        self.assertEqual(json.loads(masked)['password'], '{{secret:body.password}}')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -5,6 +5,16 @@ from dataclasses import dataclass, field
 from urllib.parse import urlparse, parse_qs
 from collections import defaultdict
 import hashlib
+from graphql_parser import GraphQLParser, GraphQLOperation
+from websocket_parser import WebSocketParser, WebSocketChannel
+from grpc_parser import GrpcParser, GrpcMethod
+from webhook_parser import WebhookParser, WebhookEvent
+from pii_masker import PIIMasker
+from secret_redactor import SecretRedactor
+from capture_store import CaptureStore
+from fixture_synthesizer import FixtureSynthesizer
+from inference_config import InferenceConfig
+from datetime import datetime
 
 
 @dataclass
@@ -17,12 +27,78 @@ class APIEndpoint:
     request_body_schema: Dict[str, Any] = field(default_factory=dict)
     response_schemas: Dict[int, Dict[str, Any]] = field(default_factory=dict)
     examples: List[Dict[str, Any]] = field(default_factory=list)
+    origin: str = ''
+    stream: str = ''  # 'sse' or 'ndjson' for streamed responses
+    event_schema: Dict[str, Any] = field(default_factory=dict)
+    upload: str = ''  # 'multipart' or 'binary' for file uploads
+    upload_files: List[str] = field(default_factory=list)  # multipart file fields
+    download: bool = False  # file responses, handed over unread
+    operation_id: str = ''  # method name given by an OpenAPI spec
+
+
+@dataclass
+class APIPartition:
+    key: str
+    name: str
+    base_url: str
+    base_path: str = ''
+    endpoints: Dict[str, APIEndpoint] = field(default_factory=dict)
     
 
 class TrafficParser:
-    def __init__(self):
+    WIDENING_ORDER = ['integer', 'number', 'string']
+    DECIMAL_PATTERN = re.compile(r'^-?\d+\.\d+$')
+    MONEY_AMOUNT_KEYS = ('amount', 'value')
+    MONEY_CURRENCY_KEYS = ('currency', 'currency_code')
+    # Epoch seconds from 2000 up to 2100, when numbers are taken as timestamps.
+    EPOCH_RANGE = (946684800, 4102444800)
+    # Host labels (or hyphenated parts of one) naming a deployment of an API,
+    # as in staging-api.example.com or api.sandbox.example.com.
+    ENVIRONMENT_LABELS = {
+        'prod': 'production', 'production': 'production', 'live': 'production', 'staging': 'staging',
+        'stage': 'staging', 'stg': 'staging', 'sandbox': 'sandbox', 'sbx': 'sandbox', 'dev': 'development',
+        'development': 'development', 'test': 'test', 'testing': 'test', 'qa': 'qa', 'uat': 'uat',
+        'preprod': 'preprod', 'demo': 'demo'
+    }
+    ENVELOPE_META_KEYS = {
+        'meta', 'metadata', 'links', 'pagination', 'paging', 'status', 'success', 'ok', 'errors', 'error',
+        'message', 'code', 'page', 'total', 'count', 'next', 'cursor', 'request_id', 'version',
+        'limit', 'offset', 'per_page', 'page_size', 'total_count', 'has_more', 'next_cursor', 'nextcursor',
+        'next_page_token', 'nextpagetoken'
+    }
+    
+    def __init__(self, mask_pii: bool = True, redact_secrets: bool = True,
+                 config: Optional[InferenceConfig] = None):
         self.endpoints: Dict[str, APIEndpoint] = {}
+        self.config = config or InferenceConfig()
         self.base_url = None
+        # The deployments of each API, by name, keyed by the origin its
+        # endpoints are filed under.
+        self.environments: Dict[str, Dict[str, str]] = {}
+        self.captures: List[Dict[str, Any]] = []
+        self.pii_masker = PIIMasker(enabled=mask_pii)
+        self.secret_redactor = SecretRedactor(enabled=redact_secrets)
+        self.graphql_parser = GraphQLParser(self)
+        self.websocket_parser = WebSocketParser(self)
+        self.grpc_parser = GrpcParser()
+        self.webhook_parser = WebhookParser(self)
+        self.fixture_synthesizer = FixtureSynthesizer(max_enum_values=self.config.enum_max_values)
+    
+    @property
+    def graphql_operations(self) -> Dict[str, GraphQLOperation]:
+        return self.graphql_parser.operations
+    
+    @property
+    def websocket_channels(self) -> Dict[str, WebSocketChannel]:
+        return self.websocket_parser.channels
+    
+    @property
+    def grpc_methods(self) -> Dict[str, GrpcMethod]:
+        return self.grpc_parser.methods
+    
+    @property
+    def webhook_events(self) -> Dict[str, WebhookEvent]:
+        return self.webhook_parser.events
         
     def parse_har_file(self, har_file_path: str) -> Dict[str, APIEndpoint]:
         with open(har_file_path, 'r') as f:
@@ -35,78 +111,283 @@ class TrafficParser:
     
     def parse_raw_traffic(self, traffic_data: List[Dict[str, Any]]) -> Dict[str, APIEndpoint]:
         for request_response in traffic_data:
-            self._process_raw_request_response(request_response)
+            self._process_capture(request_response)
         
         return self.endpoints
     
     def _process_entry(self, entry: Dict[str, Any]):
+        self._process_capture(self._har_entry_to_capture(entry))
+    
+    def _har_entry_to_capture(self, entry: Dict[str, Any]) -> Dict[str, Any]:
         request = entry['request']
         response = entry['response']
         
-        url = request['url']
-        method = request['method']
-        
-        parsed_url = urlparse(url)
-        if not self.base_url:
-            self.base_url = f"{parsed_url.scheme}://{parsed_url.netloc}"
-        
-        path = parsed_url.path
-        query_params = parse_qs(parsed_url.query)
-        
-        path_pattern, path_params = self._extract_path_pattern(path)
-        
-        endpoint_key = f"{method}:{path_pattern}"
-        
-        if endpoint_key not in self.endpoints:
-            self.endpoints[endpoint_key] = APIEndpoint(
-                method=method,
-                path_pattern=path_pattern,
-                path_params=path_params
-            )
-        
-        endpoint = self.endpoints[endpoint_key]
-        
-        for param, values in query_params.items():
-            if param not in endpoint.query_params:
-                endpoint.query_params[param] = self._infer_type(values[0])
-        
-        headers = {h['name']: h['value'] for h in request.get('headers', [])}
-        for header, value in headers.items():
-            if header.lower() not in ['cookie', 'authorization', 'x-request-id']:
-                if header not in endpoint.headers:
-                    endpoint.headers[header] = self._infer_type(value)
-        
-        if request.get('postData'):
-            body_text = request['postData'].get('text', '')
-            if body_text:
-                try:
-                    body_data = json.loads(body_text)
-                    self._merge_schema(endpoint.request_body_schema, self._extract_schema(body_data))
-                except json.JSONDecodeError:
-                    pass
-        
-        status = response['status']
-        if response.get('content', {}).get('text'):
-            try:
-                response_data = json.loads(response['content']['text'])
-                if status not in endpoint.response_schemas:
-                    endpoint.response_schemas[status] = {}
-                self._merge_schema(endpoint.response_schemas[status], self._extract_schema(response_data))
-            except json.JSONDecodeError:
-                pass
-        
-        endpoint.examples.append({
+        capture = {
             'request': {
-                'url': url,
-                'method': method,
-                'headers': headers,
-                'body': request.get('postData', {}).get('text')
+                'url': request['url'],
+                'method': request['method'],
+                'headers': {h['name']: h['value'] for h in request.get('headers', [])}
             },
             'response': {
-                'status': status,
-                'body': response.get('content', {}).get('text')
+                'status': response['status'],
+                'headers': {h['name']: h['value'] for h in response.get('headers', [])}
             }
-        })
+        }
+        
+        post_data = request.get('postData') or {}
+        if post_data.get('text'):
+            capture['request']['body'] = post_data['text']
+        elif post_data.get('params'):
+            capture['request']['parts'] = post_data['params']
+        if post_data.get('encoding'):
+            capture['request']['encoding'] = post_data['encoding']
+        
+        content = response.get('content') or {}
+        if content.get('text'):
+            capture['response']['body'] = content['text']
+        if content.get('encoding'):
+            capture['response']['encoding'] = content['encoding']
+        
+        if entry.get('_webSocketMessages'):
+            capture['websocket_messages'] = entry['_webSocketMessages']
+        if entry.get('startedDateTime'):
+            capture['started_at'] = entry['startedDateTime']
+        duration = entry.get('time')
+        if not duration:
+            duration = sum(t for t in (entry.get('timings') or {}).values() if isinstance(t, (int, float)) and t > 0)
+        if duration:
+            capture['duration_ms'] = duration
+        
+        return capture
+    
+    def parse_capture_store(self, store: CaptureStore) -> Dict[str, APIEndpoint]:
+        for capture in store.samples():
+            self._process_capture(capture, sanitize=False)
+        
+        return self.endpoints
+    
+    def parse_openapi(self, spec_path: str) -> Dict[str, APIEndpoint]:
+        """Takes the endpoints of an OpenAPI document, such as the
+        openapi.yaml written beside a Go SDK and edited since, in place of
+        captured traffic."""
+        from openapi import OpenAPIImporter
+        spec = OpenAPIImporter.load(spec_path)
+        if not self.base_url:
+            self.base_url = spec.base_url
+        if spec.environments:
+            self.environments[spec.base_url] = spec.environments
+        self.endpoints.update(spec.endpoints)
+        # The spec's examples stand in for captures when synthesizing fixtures.
+        for endpoint in spec.endpoints.values():
+            for example in endpoint.examples:
+                self.fixture_synthesizer.observe(f"{endpoint.method} {endpoint.path_pattern}", example)
+        return self.endpoints
+    
+    def write_capture_store(self, store: CaptureStore):
+        for capture in self.captures:
+            store.add(capture, self.endpoint_label(capture))
+        store.flush()
+    
+    def endpoint_label(self, capture: Dict[str, Any]) -> str:
+        request = capture.get('request', {})
+        path_pattern, _ = self._extract_path_pattern(urlparse(request.get('url', '')).path)
+        return f"{request.get('method', 'GET')} {path_pattern}"
+    
+    def _process_capture(self, data: Dict[str, Any], sanitize: bool = True):
+        sanitized = self._sanitize_capture(data) if sanitize else data
+        self.captures.append(sanitized)
+        # Webhook deliveries are calls the API makes, not ones the client does.
+        if self.webhook_parser.process(sanitized):
+            return
+        self.fixture_synthesizer.observe(self.endpoint_label(sanitized), sanitized)
+        self._process_raw_request_response(sanitized)
+    
+    def _sanitize_capture(self, data: Dict[str, Any]) -> Dict[str, Any]:
+        data = self.secret_redactor.redact(data)
+        if not self.pii_masker.enabled:
+            return data
+        
+        request = dict(data.get('request', {}))
+        response = dict(data.get('response', {}))
+        binary = self.grpc_parser.is_grpc(request.get('headers', {}))
+        
+        if request.get('url'):
+            parsed_url = urlparse(request['url'])
+            request['url'] = parsed_url._replace(
+                path=self.pii_masker.mask_text(parsed_url.path),
+                query=self.pii_masker.mask_text(parsed_url.query)
+            ).geturl()
+        
+        for message in (request, response):
+            if message.get('headers'):
+                message['headers'] = self.pii_masker.mask_headers(message['headers'])
+            if 'body' in message and not binary and message.get('encoding') != 'base64':
+                message['body'] = self.pii_masker.mask_body(message['body'])
+        
+        sanitized = dict(data)
+        sanitized['request'] = request
+        sanitized['response'] = response
+        if data.get('websocket_messages'):
+            sanitized['websocket_messages'] = [
+                dict(frame, data=self.pii_masker.mask_body(frame.get('data')))
+                if frame.get('opcode', 1) == 1 else frame
+                for frame in data['websocket_messages']
+            ]
+        return sanitized
+    
+    def save_capture(self, capture_path: str):
+        with open(capture_path, 'w') as f:
+            json.dump(self.captures, f, indent=2)
+    
+    def capture_digest(self) -> str:
+        digest = hashlib.sha256()
+        for capture in self.captures:
+            digest.update(json.dumps(capture, sort_keys=True).encode('utf-8'))
+        return f"sha256:{digest.hexdigest()}"
+    
+    def partition_by_api(self) -> Dict[str, APIPartition]:
+        partitions: Dict[str, APIPartition] = {}
+        for endpoint_key, endpoint in self.endpoints.items():
+            origin = endpoint.origin or self.base_url
+            base_path = self._api_base_path(endpoint.path_pattern)
+            key = f"{origin}{base_path}"
+            if key not in partitions:
+                partitions[key] = APIPartition(
+                    key=key,
+                    name=self._partition_name(origin, base_path, partitions),
+                    base_url=origin,
+                    base_path=base_path
+                )
+            partitions[key].endpoints[endpoint_key] = endpoint
+        return partitions
+    
+    def _environment_host(self, origin: str) -> Tuple[str, str]:
+        """An origin without its environment label, and the environment it
+        names: ("https://api.example.com", "staging") for
+        https://staging-api.example.com, or "production" when unlabeled."""
+        parsed = urlparse(origin)
+        environment, labels = 'production', []
+        for label in (parsed.hostname or '').split('.'):
+            parts = label.split('-')
+            kept = [part for part in parts if part not in self.ENVIRONMENT_LABELS]
+            if len(kept) < len(parts):
+                environment = self.ENVIRONMENT_LABELS[next(part for part in parts if part in self.ENVIRONMENT_LABELS)]
+            if kept:
+                labels.append('-'.join(kept))
+        port = f":{parsed.port}" if parsed.port else ''
+        return f"{parsed.scheme}://{'.'.join(labels)}{port}", environment
+    
+    def _environment_origin(self, origin: str) -> str:
+        """Records origin as a deployment of an API and returns the origin
+        the API's endpoints are filed under: the first seen of the origins
+        that differ from it only by an environment label, so a capture
+        spanning staging and production yields one client."""
+        family, environment = self._environment_host(origin)
+        for canonical, environments in self.environments.items():
+            if self._environment_host(canonical)[0] == family:
+                environments.setdefault(environment, origin)
+                return canonical
+        self.environments[origin] = {environment: origin}
+        return origin
+    
+    def _api_base_path(self, path: str) -> str:
+        segments = [s for s in path.split('/') if s]
+        if segments and segments[0].lower() in ('api', 'rest'):
+            return f"/{segments[0]}"
+        return ''
+    
+    def _partition_name(self, origin: str, base_path: str, existing: Dict[str, APIPartition]) -> str:
+        labels = [l for l in urlparse(origin).hostname.split('.') if l != 'www']
+        name = labels[0] if len(labels) > 2 or len(labels) == 1 else labels[-2]
+        name = re.sub(r'[^0-9a-z]+', '_', name.lower()).strip('_') or 'api'
+        if base_path:
+            name = f"{name}_{base_path.strip('/')}"
+        
+        taken = {p.name for p in existing.values()}
+        candidate = name
+        suffix = 2
+        while candidate in taken:
+            candidate = f"{name}{suffix}"
+            suffix += 1
+        return candidate
+    
+    def _load_json(self, body: Any) -> Any:
+        if isinstance(body, (dict, list)):
+            return body
+        if not isinstance(body, str) or not body:
+            return None
+        try:
+            return json.loads(body)
+        except json.JSONDecodeError:
+            return None
+    
+    def _load_form(self, request: Dict[str, Any]) -> Optional[Dict[str, Any]]:
+        """A form-encoded request body as a dict, repeated fields as lists."""
+        content_type = next((str(v) for k, v in (request.get('headers') or {}).items()
+                             if k.lower() == 'content-type'), '')
+        if not content_type.lower().startswith('application/x-www-form-urlencoded') or not isinstance(request['body'], str):
+            return None
+        fields = parse_qs(request['body'], keep_blank_values=True)
+        return {name: values[0] if len(values) == 1 else values for name, values in fields.items()} or None
+    
+    BINARY_CONTENT_TYPES = ('application/octet-stream', 'application/pdf', 'application/zip',
+                            'application/gzip', 'image/', 'audio/', 'video/', 'font/')
+    
+    def _load_multipart(self, request: Dict[str, Any]) -> Optional[Tuple[Dict[str, Any], List[str]]]:
+        """A multipart/form-data request body as its text fields and the names
+        of its file fields, read from the body or from HAR postData params."""
+        content_type = next((str(v) for k, v in (request.get('headers') or {}).items()
+                             if k.lower() == 'content-type'), '')
+        if not content_type.lower().startswith('multipart/form-data'):
+            return None
+        parts = request.get('parts')
+        if parts is None:
+            parts = []
+            boundary = re.search(r'boundary="?([^";]+)"?', content_type)
+            body = request.get('body')
+            if boundary and isinstance(body, str):
+                for chunk in body.split('--' + boundary.group(1))[1:]:
+                    if chunk.startswith('--'):
+                        break
+                    match = re.match(r'(.*?)\r?\n\r?\n(.*)', chunk.lstrip('\r\n'), re.S)
+                    if not match:
+                        continue
+                    head, value = match.groups()
+                    name = re.search(r'\bname="([^"]*)"', head)
+                    filename = re.search(r'\bfilename="([^"]*)"', head)
+                    if name:
+                        parts.append({'name': name.group(1), 'value': value.rstrip('\r\n'),
+                                      'fileName': filename.group(1) if filename else None})
+        fields, files = {}, []
+        for part in parts:
+            name = part.get('name', '')
+            if part.get('fileName') is not None:
+                if name not in files:
+                    files.append(name)
+            elif name:
+                fields[name] = part.get('value', '')
+        return fields, files
+    
+    def _is_binary_upload(self, request: Dict[str, Any]) -> bool:
+        content_type = next((str(v) for k, v in (request.get('headers') or {}).items()
+                             if k.lower() == 'content-type'), '')
+        return content_type.split(';')[0].strip().lower().startswith(self.BINARY_CONTENT_TYPES)
+    
+    DOWNLOAD_CONTENT_TYPES = BINARY_CONTENT_TYPES + ('text/csv', 'text/tab-separated-values',
+                                                     'application/vnd.ms-excel',
+                                                     'application/vnd.openxmlformats')
+    
+    def _is_download(self, response: Dict[str, Any]) -> bool:
+        """Whether a successful response is a file rather than data: sent as
+        an attachment, or in a binary or tabular media type."""
+        if not 200 <= response.get('status', 200) < 300:
+            return False
+        headers = {name.lower(): str(value).lower() for name, value in (response.get('headers') or {}).items()}
+        content_type = headers.get('content-type', '').split(';')[0].strip()
+        if content_type == 'application/json' or content_type.endswith('+json'):
+            return False
+        return (headers.get('content-disposition', '').startswith('attachment')
+                or content_type.startswith(self.DOWNLOAD_CONTENT_TYPES))
     
     def _extract_path_pattern(self, path: str) -> Tuple[str, Set[str]]:
         segments = path.split('/')
@@ -115,12 +396,17 @@ class TrafficParser:
         
         uuid_pattern = r'^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
         
+        aggressiveness = self.config.path_params
         for segment in segments:
-            if not segment:
+            if not segment or segment in self.config.static_segments:
                 pattern_segments.append(segment)
                 continue
             
-            if segment.isdigit():
+            if any(re.match(pattern, segment) for pattern in self.config.path_param_patterns):
+                param_name = self._generate_param_name(segments, segments.index(segment))
+                pattern_segments.append(f'{{{param_name}}}')
+                path_params.add(param_name)
+            elif segment.isdigit():
                 param_name = 'id'
                 pattern_segments.append(f'{{{param_name}}}')
                 path_params.add(param_name)
@@ -132,7 +418,13 @@ class TrafficParser:
                 param_name = 'objectId'
                 pattern_segments.append(f'{{{param_name}}}')
                 path_params.add(param_name)
-            elif any(char.isdigit() for char in segment) and len(segment) > 5:
+            elif aggressiveness != 'conservative' and any(char.isdigit() for char in segment) and (
+                    len(segment) > 5 or (aggressiveness == 'aggressive' and not re.match(r'^v\d+$', segment))):
+                param_name = self._generate_param_name(segments, segments.index(segment))
+                pattern_segments.append(f'{{{param_name}}}')
+                path_params.add(param_name)
+            elif aggressiveness == 'aggressive' and re.match(r'^[A-Za-z0-9_-]{20,}$', segment) and \
+                    not segment.islower() and not segment.isupper():
                 param_name = self._generate_param_name(segments, segments.index(segment))
                 pattern_segments.append(f'{{{param_name}}}')
                 path_params.add(param_name)
@@ -182,20 +474,27 @@ class TrafficParser:
     
     def _extract_schema(self, data: Any, depth: int = 0) -> Dict[str, Any]:
         if depth > 10:
-            return {'type': 'any'}
+            return {'type': 'any', 'samples': 1}
         
         if data is None:
-            return {'type': 'null', 'nullable': True}
+            return {'type': 'null', 'nullable': True, 'samples': 1, 'null_count': 1}
         elif isinstance(data, bool):
-            return {'type': 'boolean', 'example': data}
+            return {'type': 'boolean', 'example': data, 'samples': 1}
         elif isinstance(data, int):
-            return {'type': 'integer', 'example': data}
+            return {'type': 'integer', 'example': data, 'samples': 1}
         elif isinstance(data, float):
-            return {'type': 'number', 'example': data}
+            return {'type': 'number', 'example': data, 'samples': 1}
         elif isinstance(data, str):
-            schema = {'type': 'string', 'example': data[:100] if len(data) > 100 else data}
-            if re.match(r'^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}', data):
-                schema['format'] = 'date-time'
+            schema = {'type': 'string', 'example': data[:100] if len(data) > 100 else data, 'samples': 1,
+                      'observed_values': [data] if len(data) <= 100 else None,
+                      'min_length': len(data), 'max_length': len(data)}
+            date_format = self._match_date_format(data)
+            if date_format:
+                schema['format'] = 'date' if date_format != 'iso8601' and not re.search(r'%[HIMSTcXp]', date_format) else 'date-time'
+                if date_format != 'iso8601':
+                    schema['date_format'] = date_format
+            elif self.DECIMAL_PATTERN.match(data):
+                schema['format'] = 'decimal'
             elif re.match(r'^[\w\.-]+@[\w\.-]+\.\w+$', data):
                 schema['format'] = 'email'
             elif re.match(r'^https?://', data):
@@ -203,14 +502,14 @@ class TrafficParser:
             return schema
         elif isinstance(data, list):
             if not data:
-                return {'type': 'array', 'items': {'type': 'any'}}
+                return {'type': 'array', 'items': {'type': 'any', 'samples': 0}, 'samples': 1}
             
             item_schemas = [self._extract_schema(item, depth + 1) for item in data[:10]]
             merged_schema = item_schemas[0] if item_schemas else {'type': 'any'}
             for schema in item_schemas[1:]:
                 merged_schema = self._merge_schemas(merged_schema, schema)
             
-            return {'type': 'array', 'items': merged_schema}
+            return {'type': 'array', 'items': merged_schema, 'samples': 1}
         elif isinstance(data, dict):
             properties = {}
             required = []
@@ -220,23 +519,115 @@ class TrafficParser:
                 if value is not None:
                     required.append(key)
             
-            return {
+            schema = {
                 'type': 'object',
                 'properties': properties,
-                'required': required
+                'required': required,
+                'samples': 1
             }
+            self._annotate_money(schema)
+            self._annotate_epoch_times(schema)
+            return schema
         else:
-            return {'type': 'any'}
+            return {'type': 'any', 'samples': 1}
+    
+    def _annotate_money(self, schema: Dict[str, Any]):
+        """Mark fractional money-like fields as decimals, and {amount, currency}
+        objects as money."""
+        properties = schema['properties']
+        has_currency = any(key in properties and properties[key].get('type') == 'string'
+                           for key in self.MONEY_CURRENCY_KEYS)
+        for key, prop in properties.items():
+            words = key.lower().split('_')
+            if prop.get('type') == 'number' and (has_currency or any(w in words for w in self.config.money_fields)):
+                prop['format'] = 'decimal'
+        amount_keys = [key for key in self.MONEY_AMOUNT_KEYS if key in properties]
+        if has_currency and len(properties) == 2 and amount_keys:
+            amount = properties[amount_keys[0]]
+            if amount.get('type') in ('integer', 'number') or amount.get('format') == 'decimal':
+                schema['format'] = 'money'
+    
+    def _annotate_epoch_times(self, schema: Dict[str, Any]):
+        """Mark numbers named like timestamps (created_at, expiry_time) and
+        falling within this century as Unix seconds or milliseconds."""
+        for key, prop in schema['properties'].items():
+            value = prop.get('example')
+            if prop.get('type') not in ('integer', 'number') or not any(
+                    word in self.config.timestamp_fields for word in key.lower().split('_')):
+                continue
+            for date_format, scale in (('unix', 1), ('unixmilli', 1000)):
+                if self.EPOCH_RANGE[0] * scale <= value < self.EPOCH_RANGE[1] * scale:
+                    prop['format'] = 'date-time'
+                    prop['date_format'] = date_format
+    
+    def _match_date_format(self, value: str) -> Optional[str]:
+        for date_format in self.config.date_formats:
+            if date_format == 'iso8601':
+                if re.match(r'^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}', value):
+                    return date_format
+                continue
+            try:
+                datetime.strptime(value, date_format)
+                return date_format
+            except ValueError:
+                continue
+        return None
     
     def _merge_schemas(self, schema1: Dict[str, Any], schema2: Dict[str, Any]) -> Dict[str, Any]:
-        if schema1.get('type') != schema2.get('type'):
-            return {'type': 'any'}
-        
-        merged = {'type': schema1['type']}
-        
-        if schema1['type'] == 'object':
+        type1, type2 = self._observed_type(schema1), self._observed_type(schema2)
+        samples = schema1.get('samples', 1) + schema2.get('samples', 1)
+        null_count = schema1.get('null_count', 0) + schema2.get('null_count', 0)
+        
+        if type1 == 'null' or type2 == 'null':
+            merged = dict(schema2 if type1 == 'null' else schema1)
+            merged.update({'samples': samples, 'null_count': null_count, 'nullable': True})
+            return merged
+        
+        if type1 != type2:
+            if type1 in self.WIDENING_ORDER and type2 in self.WIDENING_ORDER:
+                widened = max(type1, type2, key=self.WIDENING_ORDER.index)
+                merged = {'type': widened, 'widened_from': sorted(
+                    set(schema1.get('widened_from', [type1])) | set(schema2.get('widened_from', [type2])))}
+                if self._decimal_compatible(schema1) and self._decimal_compatible(schema2) and 'decimal' in (
+                        schema1.get('format'), schema2.get('format')):
+                    merged['format'] = 'decimal'
+            else:
+                merged = {'type': 'any', 'conflicting_types': sorted(
+                    set(schema1.get('conflicting_types', [type1])) | set(schema2.get('conflicting_types', [type2])))}
+            merged.update({'samples': samples, 'null_count': null_count})
+            if schema1.get('nullable') or schema2.get('nullable'):
+                merged['nullable'] = True
+            return merged
+        
+        merged = {'type': type1, 'samples': samples, 'null_count': null_count}
+        if schema1.get('nullable') or schema2.get('nullable'):
+            merged['nullable'] = True
+        if 'example' in schema1:
+            merged['example'] = schema1['example']
+        if schema1.get('format') and schema1.get('format') == schema2.get('format'):
+            merged['format'] = schema1['format']
+            if schema1.get('date_format') and schema1.get('date_format') == schema2.get('date_format'):
+                merged['date_format'] = schema1['date_format']
+        if type1 == 'string':
+            values = schema1.get('observed_values')
+            other = schema2.get('observed_values')
+            if values is not None and other is not None:
+                values = sorted(set(values) | set(other))
+                merged['observed_values'] = values if len(values) <= self.config.enum_max_values else None
+            else:
+                merged['observed_values'] = None
+            if 'min_length' in schema1 and 'min_length' in schema2:
+                merged['min_length'] = min(schema1['min_length'], schema2['min_length'])
+                merged['max_length'] = max(schema1['max_length'], schema2['max_length'])
+        for key in ('widened_from', 'conflicting_types'):
+            if key in schema1 or key in schema2:
+                merged[key] = sorted(set(schema1.get(key, [])) | set(schema2.get(key, [])))
+        
+        if type1 == 'object':
             merged['properties'] = {}
-            all_props = set(schema1.get('properties', {}).keys()) | set(schema2.get('properties', {}).keys())
+            # First-seen order, so regenerated structs keep their field order.
+            all_props = list(schema1.get('properties', {}))
+            all_props += [prop for prop in schema2.get('properties', {}) if prop not in all_props]
             
             for prop in all_props:
                 if prop in schema1.get('properties', {}) and prop in schema2.get('properties', {}):
@@ -251,16 +642,17 @@ class TrafficParser:
                     merged['properties'][prop] = schema2['properties'][prop]
                     merged['properties'][prop]['nullable'] = True
             
-            req1 = set(schema1.get('required', []))
             req2 = set(schema2.get('required', []))
-            merged['required'] = list(req1 & req2)
+            merged['required'] = [prop for prop in schema1.get('required', []) if prop in req2]
         
-        elif schema1['type'] == 'array':
+        elif type1 == 'array':
             if 'items' in schema1 and 'items' in schema2:
                 merged['items'] = self._merge_schemas(schema1['items'], schema2['items'])
             else:
                 merged['items'] = schema1.get('items', {'type': 'any'})
         
+        if not merged['null_count']:
+            del merged['null_count']
         return merged
     
     def _merge_schema(self, target: Dict[str, Any], source: Dict[str, Any]):
@@ -270,6 +662,154 @@ class TrafficParser:
             merged = self._merge_schemas(target, source)
             target.clear()
             target.update(merged)
+        self._apply_sample_threshold(target)
+    
+    def _decimal_compatible(self, schema: Dict[str, Any]) -> bool:
+        return schema.get('format') == 'decimal' or self._observed_type(schema) in ('integer', 'number')
+    
+    def _observed_type(self, schema: Dict[str, Any]) -> str:
+        return schema.get('inferred_type', schema.get('type', 'any'))
+    
+    def _apply_sample_threshold(self, schema: Dict[str, Any]):
+        """Keeps leaf types uncommitted (`any`) until min_samples values were seen."""
+        observed = self._observed_type(schema)
+        if observed in ('object', 'array', 'null', 'any'):
+            schema['type'] = observed
+            schema.pop('inferred_type', None)
+        elif schema.get('samples', 1) - schema.get('null_count', 0) < self.config.min_samples:
+            schema['type'] = 'any'
+            schema['inferred_type'] = observed
+        else:
+            schema['type'] = observed
+            schema.pop('inferred_type', None)
+        
+        if schema.get('null_count'):
+            schema['null_rate'] = round(schema['null_count'] / schema.get('samples', 1), 3)
+        self._apply_enum_threshold(schema)
+        self._apply_length_threshold(schema)
+        for child in schema.get('properties', {}).values():
+            self._apply_sample_threshold(child)
+        if isinstance(schema.get('items'), dict):
+            self._apply_sample_threshold(schema['items'])
+    
+    def _apply_enum_threshold(self, schema: Dict[str, Any]):
+        values = schema.get('observed_values')
+        non_null = schema.get('samples', 1) - schema.get('null_count', 0)
+        if (self._observed_type(schema) == 'string' and values and not schema.get('format')
+                and non_null >= self.config.enum_min_samples and len(values) < non_null
+                and len(values) <= self.config.enum_max_values):
+            schema['enum'] = list(values)
+        else:
+            schema.pop('enum', None)
+    
+    def _apply_length_threshold(self, schema: Dict[str, Any]):
+        """Constrains strings to their length when at least
+        fixed_length_min_samples values all had the same one, as codes such
+        as "USD" or "DE" do."""
+        length = schema.get('min_length')
+        non_null = schema.get('samples', 1) - schema.get('null_count', 0)
+        if (self._observed_type(schema) == 'string' and length and length == schema.get('max_length')
+                and not schema.get('format') and not schema.get('enum')
+                and non_null >= self.config.fixed_length_min_samples):
+            schema['minLength'] = schema['maxLength'] = length
+        else:
+            schema.pop('minLength', None)
+            schema.pop('maxLength', None)
+    
+    def _detect_envelope(self, schema: Dict[str, Any]):
+        schema.pop('envelope', None)
+        if not self.config.detect_envelopes or schema.get('type') != 'object':
+            return
+        properties = schema.get('properties', {})
+        candidates = [key for key in self.config.envelope_keys
+                      if properties.get(key, {}).get('type') in ('object', 'array')]
+        if len(candidates) == 1 and all(key == candidates[0] or key.lower() in self.ENVELOPE_META_KEYS
+                                        for key in properties):
+            schema['envelope'] = candidates[0]
+    
+    def low_confidence_fields(self, threshold: Optional[int] = None) -> List[Tuple[str, str, str]]:
+        """Lists (endpoint, field path, reason) for fields whose type rests on
+        fewer than `threshold` samples, was widened, or conflicted."""
+        threshold = threshold or max(self.config.min_samples, self.config.low_confidence_samples)
+        fields = []
+        for endpoint in self.endpoints.values():
+            label = f"{endpoint.method} {endpoint.path_pattern}"
+            if endpoint.request_body_schema:
+                self._collect_low_confidence(label, 'request', endpoint.request_body_schema, threshold, fields)
+            for status, schema in sorted(endpoint.response_schemas.items()):
+                self._collect_low_confidence(label, f"response[{status}]", schema, threshold, fields)
+        return fields
+    
+    def _collect_low_confidence(self, label: str, path: str, schema: Dict[str, Any], threshold: int,
+                                fields: List[Tuple[str, str, str]]):
+        observed = self._observed_type(schema)
+        if observed not in ('object', 'array'):
+            samples = schema.get('samples', 1) - schema.get('null_count', 0)
+            reason = None
+            if schema.get('conflicting_types'):
+                reason = f"conflicting types {'/'.join(schema['conflicting_types'])}"
+            elif schema.get('widened_from'):
+                narrower = [t for t in schema['widened_from'] if t != observed]
+                reason = f"widened {'/'.join(narrower)} to {observed}"
+            elif 'inferred_type' in schema:
+                reason = f"{samples} sample(s), left untyped (looks like {observed})"
+            elif observed == 'null':
+                reason = 'only null values seen'
+            elif samples < threshold:
+                reason = f"{observed} from {samples} sample(s)"
+            if reason:
+                if schema.get('null_rate') and observed != 'null':
+                    reason += f", null in {schema['null_rate']:.0%}"
+                fields.append((label, path, reason))
+        for name, child in sorted(schema.get('properties', {}).items()):
+            self._collect_low_confidence(label, f"{path}.{name}", child, threshold, fields)
+        if isinstance(schema.get('items'), dict) and schema['items'].get('samples', 1):
+            self._collect_low_confidence(label, f"{path}[]", schema['items'], threshold, fields)
+    
+    NDJSON_CONTENT_TYPES = ('application/x-ndjson', 'application/ndjson', 'application/jsonl',
+                            'application/x-jsonlines', 'application/stream+json')
+    
+    def _stream_format(self, response: Dict[str, Any]) -> str:
+        """'sse' for a server-sent event stream, 'ndjson' for JSON lines
+        (declared, or several JSON values in a chunked body), else ''."""
+        if not isinstance(response.get('body'), str):
+            return ''
+        headers = {name.lower(): str(value).lower() for name, value in (response.get('headers') or {}).items()}
+        content_type = headers.get('content-type', '').split(';')[0].strip()
+        if content_type == 'text/event-stream':
+            return 'sse'
+        if content_type in self.NDJSON_CONTENT_TYPES:
+            return 'ndjson'
+        lines = [line for line in response['body'].splitlines() if line.strip()]
+        if 'chunked' in headers.get('transfer-encoding', '') and len(lines) > 1:
+            try:
+                for line in lines:
+                    json.loads(line)
+                return 'ndjson'
+            except json.JSONDecodeError:
+                pass
+        return ''
+    
+    def _stream_events(self, stream: str, body: str) -> List[Any]:
+        """The JSON payloads of a captured stream: the data of each server-sent
+        event, or each line of JSON lines."""
+        if stream == 'sse':
+            payloads, data = [], []
+            for line in body.splitlines() + ['']:
+                if line.startswith('data:'):
+                    data.append(line[len('data:'):].removeprefix(' '))
+                elif not line and data:
+                    payloads.append('\n'.join(data))
+                    data = []
+        else:
+            payloads = [line for line in body.splitlines() if line.strip()]
+        events = []
+        for payload in payloads:
+            try:
+                events.append(json.loads(payload))
+            except json.JSONDecodeError:
+                continue
+        return events
     
     def _process_raw_request_response(self, data: Dict[str, Any]):
         request = data.get('request', {})
@@ -285,15 +825,32 @@ class TrafficParser:
         path = parsed_url.path
         query_params = parse_qs(parsed_url.query)
         
+        if self.grpc_parser.process(path, request.get('headers', {}), request.get('body'),
+                                    response.get('body'), request.get('encoding'), response.get('encoding')):
+            return
+        
+        if self.websocket_parser.process(url, path, data.get('websocket_messages', [])):
+            return
+        
+        if self.graphql_parser.process(method, path, query_params,
+                                       self._load_json(request.get('body')),
+                                       response.get('status', 200),
+                                       self._load_json(response.get('body'))):
+            return
+        
         path_pattern, path_params = self._extract_path_pattern(path)
+        origin = self._environment_origin(f"{parsed_url.scheme}://{parsed_url.netloc}")
         
         endpoint_key = f"{method}:{path_pattern}"
+        if origin != self.base_url:
+            endpoint_key = f"{method}:{origin}{path_pattern}"
         
         if endpoint_key not in self.endpoints:
             self.endpoints[endpoint_key] = APIEndpoint(
                 method=method,
                 path_pattern=path_pattern,
-                path_params=path_params
+                path_params=path_params,
+                origin=origin
             )
         
         endpoint = self.endpoints[endpoint_key]
@@ -308,24 +865,46 @@ class TrafficParser:
                 if header not in endpoint.headers:
                     endpoint.headers[header] = self._infer_type(value)
         
-        if request.get('body'):
+        multipart = self._load_multipart(request)
+        if multipart:
+            endpoint.upload = 'multipart'
+            fields, files = multipart
+            endpoint.upload_files.extend(name for name in files if name not in endpoint.upload_files)
+            if fields:
+                self._merge_schema(endpoint.request_body_schema, self._extract_schema(fields))
+        elif self._is_binary_upload(request):
+            endpoint.upload = 'binary'
+        elif request.get('body'):
             try:
                 body_data = json.loads(request['body']) if isinstance(request['body'], str) else request['body']
                 self._merge_schema(endpoint.request_body_schema, self._extract_schema(body_data))
             except (json.JSONDecodeError, TypeError):
-                pass
+                form = self._load_form(request)
+                if form:
+                    self._merge_schema(endpoint.request_body_schema, self._extract_schema(form))
         
         status = response.get('status', 200)
-        if response.get('body'):
+        stream = self._stream_format(response)
+        if stream:
+            endpoint.stream = stream
+            for event in self._stream_events(stream, response['body']):
+                self._merge_schema(endpoint.event_schema, self._extract_schema(event))
+        elif self._is_download(response):
+            endpoint.download = True
+        elif response.get('body'):
             try:
                 response_data = json.loads(response['body']) if isinstance(response['body'], str) else response['body']
                 if status not in endpoint.response_schemas:
                     endpoint.response_schemas[status] = {}
                 self._merge_schema(endpoint.response_schemas[status], self._extract_schema(response_data))
+                self._detect_envelope(endpoint.response_schemas[status])
             except (json.JSONDecodeError, TypeError):
                 pass
         
-        endpoint.examples.append({
+        example = {
             'request': request,
             'response': response
-        })
\ No newline at end of file
+        }
+        if data.get('duration_ms'):
+            example['duration_ms'] = data['duration_ms']
+        endpoint.examples.append(example)
\ No newline at end of file
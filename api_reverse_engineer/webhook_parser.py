@@ -0,0 +1,150 @@
+import json
+import re
+from typing import Dict, List, Any, Optional
+from dataclasses import dataclass, field
+from collections import Counter
+
+
+@dataclass
+class WebhookEvent:
+    name: str
+    event_type: Optional[str] = None
+    schema: Dict[str, Any] = field(default_factory=dict)
+    count: int = 0
+    examples: List[Any] = field(default_factory=list)
+
+
+@dataclass
+class WebhookSignature:
+    header: str
+    scheme: str  # 'standard', 'stripe', 'hub', or 'hmac'
+    encoding: str = 'hex'
+    prefix: str = ''
+
+
+class WebhookParser:
+    """Infers typed event payloads from captured webhook deliveries.
+
+    A delivery is a POST the API makes to a subscriber, recognized by the
+    capture's `webhook` flag or by the signature and event headers webhook
+    senders use. Deliveries are classified by an event header when one is
+    sent, otherwise by the discriminator field most payloads share (type,
+    event, ...), and the signature header's format is recorded so receivers
+    can verify deliveries.
+    """
+
+    # Signature headers in order of preference, with the scheme each implies.
+    SIGNATURE_HEADERS = [
+        ('webhook-signature', 'standard'), ('svix-signature', 'standard'), ('stripe-signature', 'stripe'),
+        ('x-hub-signature-256', 'hub'), ('x-webhook-signature', 'hmac'), ('x-webhook-signature-256', 'hmac'),
+    ]
+    EVENT_HEADERS = ['x-webhook-event', 'x-github-event', 'x-event-type', 'webhook-event']
+    DISCRIMINATOR_CANDIDATES = ['type', 'event', 'event_type', 'eventType', 'topic', 'kind', 'action']
+    MAX_EXAMPLES = 5
+
+    def __init__(self, schema_builder):
+        self.schema_builder = schema_builder
+        self.events: Dict[str, WebhookEvent] = {}
+        self.signature: Optional[WebhookSignature] = None
+        self.event_header: Optional[str] = None
+        self.discriminator: Optional[str] = None
+        self._payloads: List[Any] = []
+
+    def is_delivery(self, capture: Dict[str, Any]) -> bool:
+        request = capture.get('request', {})
+        if capture.get('webhook') or str(capture.get('direction', '')).lower() == 'inbound':
+            return True
+        if request.get('method', 'GET') != 'POST':
+            return False
+        names = {name.lower() for name in request.get('headers', {})}
+        return any(header in names for header, _ in self.SIGNATURE_HEADERS) or \
+            any(header in names for header in self.EVENT_HEADERS[:2])
+
+    def process(self, capture: Dict[str, Any]) -> bool:
+        if not self.is_delivery(capture):
+            return False
+        request = capture.get('request', {})
+        headers = {name.lower(): value for name, value in request.get('headers', {}).items()}
+        try:
+            payload = json.loads(request['body']) if isinstance(request.get('body'), str) else request.get('body')
+        except json.JSONDecodeError:
+            return True
+        if self.signature is None:
+            self.signature = self._detect_signature(headers)
+        if self.event_header is None:
+            self.event_header = next((header for header in self.EVENT_HEADERS if headers.get(header)), None)
+
+        self._payloads.append(payload)
+        if self.event_header:
+            self._classify(str(headers.get(self.event_header) or ''), payload)
+        else:
+            # Payloads seen before the discriminator settled are classified
+            # again as it changes.
+            discriminator = self._pick_discriminator(self._payloads)
+            if discriminator != self.discriminator:
+                self.discriminator = discriminator
+                self.events = {}
+                for seen in self._payloads:
+                    self._classify(self._discriminator_value(seen), seen)
+            else:
+                self._classify(self._discriminator_value(payload), payload)
+        return True
+
+    def _detect_signature(self, headers: Dict[str, str]) -> Optional[WebhookSignature]:
+        for header, scheme in self.SIGNATURE_HEADERS:
+            value = str(headers.get(header) or '')
+            if not value:
+                continue
+            if scheme != 'hmac':
+                return WebhookSignature(header=header, scheme=scheme)
+            prefix = ''
+            match = re.match(r'(sha256=|v1=|v1,)', value)
+            if match:
+                prefix, value = match.group(1), value[len(match.group(1)):]
+            # PII masking keeps a value's length and character classes but not
+            # its hex digits, so a SHA-256 is told by its 64 characters.
+            encoding = 'hex' if re.fullmatch(r'[0-9A-Za-z]{64}', value) else 'base64'
+            return WebhookSignature(header=header, scheme=scheme, encoding=encoding, prefix=prefix)
+        return None
+
+    def _pick_discriminator(self, payloads: List[Any]) -> Optional[str]:
+        counts = Counter()
+        objects = [payload for payload in payloads if isinstance(payload, dict)]
+        for payload in objects:
+            for candidate in self.DISCRIMINATOR_CANDIDATES:
+                if isinstance(payload.get(candidate), str) and payload[candidate]:
+                    counts[candidate] += 1
+        if not counts:
+            return None
+        candidate, hits = max(counts.items(), key=lambda item: (item[1], -self.DISCRIMINATOR_CANDIDATES.index(item[0])))
+        return candidate if hits * 2 >= len(objects) else None
+
+    def _discriminator_value(self, payload: Any) -> str:
+        if self.discriminator and isinstance(payload, dict) and isinstance(payload.get(self.discriminator), str):
+            return payload[self.discriminator]
+        return ''
+
+    def _classify(self, event_type: str, payload: Any):
+        event = self.events.get(event_type)
+        if event is None:
+            event = WebhookEvent(name=self._unique_name(self._to_type_name(event_type)), event_type=event_type or None)
+            self.events[event_type] = event
+        event.count += 1
+        if isinstance(payload, (dict, list)):
+            self.schema_builder._merge_schema(event.schema, self.schema_builder._extract_schema(payload))
+        if len(event.examples) < self.MAX_EXAMPLES:
+            event.examples.append(payload)
+
+    def _to_type_name(self, value: str) -> str:
+        words = [w for w in re.split(r'[^0-9A-Za-z]+', value) if w]
+        name = ''.join(w[0].upper() + w[1:] for w in words) or 'Webhook'
+        if name[0].isdigit():
+            name = 'Event' + name
+        return name
+
+    def _unique_name(self, name: str) -> str:
+        taken = {event.name for event in self.events.values()}
+        unique, n = name, 2
+        while unique in taken:
+            unique, n = f"{name}{n}", n + 1
+        return unique
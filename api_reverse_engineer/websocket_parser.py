@@ -0,0 +1,146 @@
+import json
+import re
+from typing import Dict, List, Any, Optional
+from dataclasses import dataclass, field
+from collections import Counter
+
+
+@dataclass
+class WebSocketMessageType:
+    name: str
+    direction: str
+    opcode: int
+    discriminator: Optional[str] = None
+    discriminator_value: Any = None
+    schema: Dict[str, Any] = field(default_factory=dict)
+    count: int = 0
+    examples: List[Any] = field(default_factory=list)
+
+
+@dataclass
+class WebSocketChannel:
+    url: str
+    path: str
+    discriminator: Optional[str] = None
+    frame_count: int = 0
+    message_types: Dict[str, WebSocketMessageType] = field(default_factory=dict)
+
+
+class WebSocketParser:
+    """Infers typed message schemas from captured WebSocket frames.
+
+    Frames are grouped per channel (connection path). JSON frames are
+    classified by the discriminator field most frames on the channel share
+    (type, event, op, ...); anything else is classified by opcode.
+    """
+
+    DISCRIMINATOR_CANDIDATES = ['type', 'event', 'op', 'action', 'kind', 'method', 'channel', 'topic', 'e']
+    OPCODE_NAMES = {1: 'text', 2: 'binary', 8: 'close', 9: 'ping', 10: 'pong'}
+    MAX_EXAMPLES = 5
+
+    def __init__(self, schema_builder):
+        self.schema_builder = schema_builder
+        self.channels: Dict[str, WebSocketChannel] = {}
+
+    def process(self, url: str, path: str, frames: List[Dict[str, Any]]) -> bool:
+        if not frames:
+            return False
+
+        channel = self.channels.get(path)
+        if channel is None:
+            channel = WebSocketChannel(url=url, path=path)
+            self.channels[path] = channel
+
+        decoded = [(self._direction(frame), frame.get('opcode', 1), self._decode(frame)) for frame in frames]
+        if channel.discriminator is None:
+            channel.discriminator = self._pick_discriminator([payload for _, _, payload in decoded])
+
+        for direction, opcode, payload in decoded:
+            channel.frame_count += 1
+            self._classify(channel, direction, opcode, payload)
+        return True
+
+    def _direction(self, frame: Dict[str, Any]) -> str:
+        kind = str(frame.get('type', frame.get('direction', 'receive'))).lower()
+        return 'send' if kind in ('send', 'sent', 'outgoing', 'client') else 'receive'
+
+    def _decode(self, frame: Dict[str, Any]) -> Any:
+        data = frame.get('data')
+        if frame.get('opcode', 1) != 1 or not isinstance(data, str):
+            return data
+        try:
+            return json.loads(data)
+        except json.JSONDecodeError:
+            return data
+
+    def _pick_discriminator(self, payloads: List[Any]) -> Optional[str]:
+        counts = Counter()
+        objects = 0
+        for payload in payloads:
+            if isinstance(payload, dict):
+                objects += 1
+                for candidate in self.DISCRIMINATOR_CANDIDATES:
+                    if isinstance(payload.get(candidate), (str, int)) and not isinstance(payload.get(candidate), bool):
+                        counts[candidate] += 1
+            elif isinstance(payload, list) and payload and isinstance(payload[0], str):
+                objects += 1
+                counts['[0]'] += 1
+
+        if not counts:
+            return None
+        candidate, hits = max(counts.items(), key=lambda item: (item[1], -self._candidate_rank(item[0])))
+        return candidate if hits * 2 >= objects else None
+
+    def _candidate_rank(self, candidate: str) -> int:
+        if candidate in self.DISCRIMINATOR_CANDIDATES:
+            return self.DISCRIMINATOR_CANDIDATES.index(candidate)
+        return len(self.DISCRIMINATOR_CANDIDATES)
+
+    def _discriminator_value(self, discriminator: Optional[str], payload: Any) -> Any:
+        if discriminator == '[0]' and isinstance(payload, list) and payload:
+            return payload[0]
+        if discriminator and isinstance(payload, dict):
+            return payload.get(discriminator)
+        return None
+
+    def _classify(self, channel: WebSocketChannel, direction: str, opcode: int, payload: Any):
+        value = self._discriminator_value(channel.discriminator, payload)
+        if value is not None:
+            key = f"{direction}:{channel.discriminator}={value}"
+            base_name = self._to_type_name(str(value))
+        else:
+            label = self.OPCODE_NAMES.get(opcode, f"opcode{opcode}")
+            if isinstance(payload, (dict, list)):
+                label = 'json'
+            key = f"{direction}:{label}"
+            base_name = self._to_type_name(label)
+
+        message_type = channel.message_types.get(key)
+        if message_type is None:
+            message_type = WebSocketMessageType(
+                name=self._unique_name(channel, base_name, direction),
+                direction=direction,
+                opcode=opcode,
+                discriminator=channel.discriminator if value is not None else None,
+                discriminator_value=value
+            )
+            channel.message_types[key] = message_type
+
+        message_type.count += 1
+        if isinstance(payload, (dict, list)):
+            self.schema_builder._merge_schema(message_type.schema, self.schema_builder._extract_schema(payload))
+        if len(message_type.examples) < self.MAX_EXAMPLES:
+            message_type.examples.append(payload)
+
+    def _to_type_name(self, value: str) -> str:
+        words = [w for w in re.split(r'[^0-9A-Za-z]+', value) if w]
+        name = ''.join(w[0].upper() + w[1:] for w in words) or 'Unknown'
+        if name[0].isdigit():
+            name = 'Msg' + name
+        return name + 'Message'
+
+    def _unique_name(self, channel: WebSocketChannel, name: str, direction: str) -> str:
+        taken = {mt.name for mt in channel.message_types.values()}
+        if name not in taken:
+            return name
+        return ('Outbound' if direction == 'send' else 'Inbound') + name